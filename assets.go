@@ -25,7 +25,8 @@ type AssetLoader interface {
 	// Returns ErrStyleNotFound if the style doesn't exist.
 	LoadStyle(name string) (string, error)
 
-	// LoadTemplateSet loads cover and signature templates by name.
+	// LoadTemplateSet loads cover and signature templates by name, along with
+	// optional footer and header templates.
 	// Returns ErrTemplateSetNotFound if the template set doesn't exist.
 	// Returns ErrIncompleteTemplateSet if required templates are missing.
 	LoadTemplateSet(name string) (*TemplateSet, error)
@@ -33,10 +34,19 @@ type AssetLoader interface {
 
 // TemplateSet holds HTML templates for document generation.
 // A template set contains cover and signature templates that work together.
+//
+// Footer and Header optionally replace the generated page footer and running
+// header. They are html/template sources rendered against the cover and
+// document metadata ({{.Title}}, {{.Version}}, {{.DocumentID}}, {{.Date}},
+// {{.Status}}, {{.Text}}, ...) plus Chrome's {{.PageNumber}}, {{.TotalPages}}
+// and {{.PageTitle}} placeholders. Chrome prints them in an isolated context:
+// styles must be inline and images must be data: URIs.
 type TemplateSet struct {
 	Name      string // Identifier (name or path)
 	Cover     string // Cover page template HTML
 	Signature string // Signature block template HTML
+	Footer    string // Page footer template HTML (optional)
+	Header    string // Page header template HTML (optional)
 }
 
 // NewTemplateSet creates a TemplateSet from cover and signature HTML content.
@@ -55,7 +65,8 @@ func NewTemplateSet(name, cover, signature string) *TemplateSet {
 //
 // The basePath directory should contain:
 //   - styles/{name}.css for CSS styles
-//   - templates/{name}/cover.html and signature.html for template sets
+//   - templates/{name}/cover.html and signature.html for template sets,
+//     with optional footer.html and header.html
 //
 // Returns ErrInvalidAssetPath if basePath is set but not a valid, readable directory.
 func NewAssetLoader(basePath string) (AssetLoader, error) {
@@ -88,6 +99,8 @@ func (a *assetLoaderAdapter) LoadTemplateSet(name string) (*TemplateSet, error)
 		Name:      ts.Name,
 		Cover:     ts.Cover,
 		Signature: ts.Signature,
+		Footer:    ts.Footer,
+		Header:    ts.Header,
 	}, nil
 }
 
@@ -65,9 +65,10 @@ type completionMeta struct {
 // flagCompletionMeta maps flag names to their completion metadata.
 var flagCompletionMeta = map[string]completionMeta{
 	// Enum flags
-	"page-size":       {Values: []string{"letter", "a4", "legal"}},
+	"page-size":       {Values: []string{"letter", "a4", "legal", "a3", "a5", "b5", "executive", "tabloid"}},
 	"orientation":     {Values: []string{"portrait", "landscape"}},
 	"footer-position": {Values: []string{"left", "center", "right"}},
+	"diagram-theme":   {Values: []string{"default", "neutral", "dark", "forest", "base"}},
 
 	// File flags with glob patterns
 	"config":     {FileGlob: "*.yaml,*.yml"},
@@ -90,6 +91,8 @@ func buildConvertFlagSet() *flag.FlagSet {
 	// I/O flags
 	fs.StringVarP(&f.output, "output", "o", "", "output file or directory")
 	fs.IntVarP(&f.workers, "workers", "w", 0, "parallel workers (0 = auto)")
+	fs.BoolVar(&f.merge, "merge", false, "combine all inputs into one PDF")
+	fs.BoolVar(&f.pdfa, "pdfa", false, "produce PDF/A-2b for archiving")
 
 	// Flag groups - same as parseConvertFlags
 	addCommonFlags(fs, &f.common)
@@ -97,11 +100,25 @@ func buildConvertFlagSet() *flag.FlagSet {
 	addDocumentFlags(fs, &f.document)
 	addPageFlags(fs, &f.page)
 	addFooterFlags(fs, &f.footer)
+	addHeaderFlags(fs, &f.header)
+	addPageNumberingFlags(fs, &f.numbering)
+	addDiagramFlags(fs, &f.diagrams)
+	addMathFlags(fs, &f.math)
+	addRawHTMLFlags(fs, &f.rawHTML)
+	addInterpolationFlags(fs, &f.interp)
+	addCitationFlags(fs, &f.citations)
+	addFootnoteFlags(fs, &f.footnotes)
 	addCoverFlags(fs, &f.cover)
 	addSignatureFlags(fs, &f.signature)
 	addTOCFlags(fs, &f.toc)
+	addIndexFlags(fs, &f.index)
+	addListFlags(fs, &f.figures, "lof", "figures")
+	addListFlags(fs, &f.tables, "lot", "tables")
+	addOutlineFlags(fs, &f.outline)
 	addWatermarkFlags(fs, &f.watermark)
 	addPageBreakFlags(fs, &f.pageBreaks)
+	addSecurityFlags(fs, &f.security)
+	addSigningFlags(fs, &f.signing)
 	addAssetFlags(fs, &f.assets)
 	addOutputFlags(fs, &f.outputMode)
 
@@ -175,6 +192,13 @@ func getCommands() []commandDef {
 			Desc:  "Check system configuration",
 			Flags: nil,
 		},
+		{
+			Name:        "verify-signature",
+			Desc:        "Check the digital signatures of a PDF",
+			Flags:       extractFlagsFromFlagSet(buildVerifySignatureFlagSet(&verifySignatureFlags{})),
+			TakesFiles:  true,
+			FilePattern: "*.pdf",
+		},
 		{
 			Name:  "version",
 			Desc:  "Show version information",
@@ -252,7 +252,7 @@ func TestGetCommands(t *testing.T) {
 
 	commands := getCommands()
 
-	expectedCommands := []string{"convert", "config", "doctor", "version", "help", "completion"}
+	expectedCommands := []string{"convert", "config", "doctor", "verify-signature", "version", "help", "completion"}
 	if len(commands) != len(expectedCommands) {
 		t.Fatalf("getCommands() = %d commands, want %d", len(commands), len(expectedCommands))
 	}
@@ -358,7 +358,7 @@ func TestGetCommands_EnumFlagsHaveValues(t *testing.T) {
 	}
 
 	enumFlags := map[string][]string{
-		"page-size":       {"letter", "a4", "legal"},
+		"page-size":       {"letter", "a4", "legal", "a3", "a5", "b5", "executive", "tabloid"},
 		"orientation":     {"portrait", "landscape"},
 		"footer-position": {"left", "center", "right"},
 	}
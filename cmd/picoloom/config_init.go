@@ -318,7 +318,7 @@ func promptConfigInitBaseAnswers(reader *bufio.Reader, output io.Writer, answers
 	}
 	pageSize, err := promptString(reader, output, wizardPrompt{
 		title:        "Page size",
-		options:      "letter, a4, legal",
+		options:      "letter, a4, legal, a3, a5, b5, executive, tabloid",
 		example:      "a4",
 		defaultValue: answers.pageSize,
 		helpYAML:     "page:\n  size: letter",
@@ -696,10 +696,11 @@ func parseYesNo(value string) (bool, error) {
 // validatePageSize constrains output layout to supported rendering sizes.
 func validatePageSize(value string) error {
 	switch strings.ToLower(strings.TrimSpace(value)) {
-	case "letter", "a4", "legal":
+	case picoloom.PageSizeLetter, picoloom.PageSizeA4, picoloom.PageSizeLegal, picoloom.PageSizeA3,
+		picoloom.PageSizeA5, picoloom.PageSizeB5, picoloom.PageSizeExecutive, picoloom.PageSizeTabloid:
 		return nil
 	default:
-		return fmt.Errorf("must be one of: letter, a4, legal")
+		return fmt.Errorf("must be one of: letter, a4, legal, a3, a5, b5, executive, tabloid")
 	}
 }
 
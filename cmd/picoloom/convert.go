@@ -39,8 +39,17 @@ func runConvert(ctx context.Context, positionalArgs []string, flags *convertFlag
 	// Resolve output directory
 	outputDir := resolveOutputDir(flags.output, cfgForRun)
 
-	// Discover files to convert
-	files, err := discoverFiles(inputPath, outputDir)
+	// Discover files to convert (all positional inputs are chapters with --merge)
+	var files []FileToConvert
+	inputPaths := []string{inputPath}
+	if flags.merge {
+		if len(positionalArgs) > 0 {
+			inputPaths = positionalArgs
+		}
+		files, err = discoverChapters(inputPaths, outputDir)
+	} else {
+		files, err = discoverFiles(inputPath, outputDir)
+	}
 	if err != nil {
 		return fmt.Errorf("discovering files: %w", err)
 	}
@@ -61,6 +70,21 @@ func runConvert(ctx context.Context, positionalArgs []string, flags *convertFlag
 	// Build footer data (uses cfg.Document.Date, cfg.Document.Version)
 	footerData := buildFooterData(cfgForRun, flags.footer.disabled)
 
+	// Build header data (uses cfg.Document.* for placeholders)
+	headerData := buildHeaderData(cfgForRun, flags.header.disabled)
+
+	// Build page numbering data
+	numberingData := buildPageNumberingData(cfgForRun)
+
+	// Build diagram data
+	diagramsData := buildDiagramsData(cfgForRun)
+
+	// Build math data
+	mathData := buildMathData(cfgForRun)
+
+	// Build raw HTML data
+	rawHTMLData := buildRawHTMLData(cfgForRun)
+
 	// Build page settings
 	pageData := buildPageSettings(cfgForRun)
 
@@ -70,25 +94,64 @@ func runConvert(ctx context.Context, positionalArgs []string, flags *convertFlag
 	// Build TOC data
 	tocData := buildTOCData(cfgForRun, flags.toc)
 
+	// Build index data
+	indexData := buildIndexData(cfgForRun)
+
+	// Build lists of figures and tables data
+	figuresData := buildListOfData(cfgForRun.ListOfFigures)
+	tablesData := buildListOfData(cfgForRun.ListOfTables)
+
+	// Build footnote placement data
+	footnotesData := buildFootnotesData(cfgForRun)
+
+	// Build outline data
+	outlineData := buildOutlineData(cfgForRun)
+
 	// Build page breaks data
 	pageBreaksData := buildPageBreaksData(cfgForRun)
 
+	// Build encryption data
+	encryptionData := buildEncryptionData(cfgForRun)
+
+	// Build digital signature data
+	signingData := buildSigningData(cfgForRun)
+
 	// Bundle conversion parameters
 	params := &conversionParams{
 		css:        cssContent,
 		footer:     footerData,
+		header:     headerData,
+		numbering:  numberingData,
+		diagrams:   diagramsData,
+		math:       mathData,
+		rawHTML:    rawHTMLData,
 		signature:  sigData,
 		page:       pageData,
 		watermark:  watermarkData,
 		toc:        tocData,
+		index:      indexData,
+		figures:    figuresData,
+		tables:     tablesData,
+		footnotes:  footnotesData,
+		outline:    outlineData,
 		pageBreaks: pageBreaksData,
+		encryption: encryptionData,
+		signing:    signingData,
 		cfg:        cfgForRun,
 		htmlOnly:   flags.outputMode.htmlOnly,
 		htmlOutput: flags.outputMode.html,
+		flags:      flags,
+		loader:     env.AssetLoader,
+		now:        env.Now,
 	}
 
 	// Convert files
-	results := convertBatch(ctx, pool, files, params)
+	var results []ConversionResult
+	if flags.merge {
+		results = []ConversionResult{convertMerged(ctx, pool, strings.Join(inputPaths, ", "), files, params)}
+	} else {
+		results = convertBatch(ctx, pool, files, params)
+	}
 
 	// Print results
 	failedCount := printResultsWithWriter(results, flags.common.quiet, flags.common.verbose, env)
@@ -134,12 +197,26 @@ func mergeFlags(flags *convertFlags, cfg *config.Config) {
 	mergeAuthorFlags(flags, cfg)
 	mergeDocumentFlags(flags, cfg)
 	mergeFooterFlags(flags, cfg)
+	mergeHeaderFlags(flags, cfg)
+	mergePageNumberingFlags(flags, cfg)
+	mergeDiagramFlags(flags, cfg)
+	mergeMathFlags(flags, cfg)
+	mergeRawHTMLFlags(flags, cfg)
+	mergeInterpolationFlags(flags, cfg)
+	mergeCitationFlags(flags, cfg)
+	mergeFootnoteFlags(flags, cfg)
 	mergeCoverFlags(flags, cfg)
 	mergeSignatureFlags(flags, cfg)
 	mergeTOCFlags(flags, cfg)
+	mergeIndexFlags(flags, cfg)
+	mergeListFlags(&flags.figures, &cfg.ListOfFigures)
+	mergeListFlags(&flags.tables, &cfg.ListOfTables)
+	mergeOutlineFlags(flags, cfg)
 	mergeWatermarkFlags(flags, cfg)
 	mergePageFlags(flags, cfg)
 	mergePageBreakFlags(flags, cfg)
+	mergeSecurityFlags(flags, cfg)
+	mergeSigningFlags(flags, cfg)
 	mergeDisableFlags(flags, cfg)
 }
 
@@ -195,6 +272,9 @@ func mergeDocumentFlags(flags *convertFlags, cfg *config.Config) {
 	if flags.document.description != "" {
 		cfg.Document.Description = flags.document.description
 	}
+	if len(flags.document.keywords) > 0 {
+		cfg.Document.Keywords = flags.document.keywords
+	}
 }
 
 func mergeFooterFlags(flags *convertFlags, cfg *config.Config) {
@@ -216,6 +296,21 @@ func mergeFooterFlags(flags *convertFlags, cfg *config.Config) {
 	}
 }
 
+func mergeHeaderFlags(flags *convertFlags, cfg *config.Config) {
+	if flags.header.left != "" {
+		cfg.Header.Left = flags.header.left
+		cfg.Header.Enabled = true
+	}
+	if flags.header.center != "" {
+		cfg.Header.Center = flags.header.center
+		cfg.Header.Enabled = true
+	}
+	if flags.header.right != "" {
+		cfg.Header.Right = flags.header.right
+		cfg.Header.Enabled = true
+	}
+}
+
 func mergeCoverFlags(flags *convertFlags, cfg *config.Config) {
 	if flags.cover.logo != "" {
 		cfg.Cover.Logo = flags.cover.logo
@@ -247,6 +342,115 @@ func mergeTOCFlags(flags *convertFlags, cfg *config.Config) {
 		cfg.TOC.MaxDepth = flags.toc.maxDepth
 		cfg.TOC.Enabled = true
 	}
+	if flags.toc.pageNumbers {
+		cfg.TOC.PageNumbers = true
+		cfg.TOC.Enabled = true
+	}
+}
+
+func mergeIndexFlags(flags *convertFlags, cfg *config.Config) {
+	if flags.index.enabled {
+		cfg.Index.Enabled = true
+	}
+	if flags.index.title != "" {
+		cfg.Index.Title = flags.index.title
+		cfg.Index.Enabled = true
+	}
+}
+
+func mergeListFlags(flags *listFlags, cfg *config.ListOfConfig) {
+	if flags.enabled {
+		cfg.Enabled = true
+	}
+	if flags.title != "" {
+		cfg.Title = flags.title
+		cfg.Enabled = true
+	}
+	if flags.pageNumbers {
+		cfg.PageNumbers = true
+		cfg.Enabled = true
+	}
+}
+
+func mergePageNumberingFlags(flags *convertFlags, cfg *config.Config) {
+	if flags.numbering.format != "" {
+		cfg.PageNumbering.Format = flags.numbering.format
+		cfg.PageNumbering.Enabled = true
+	}
+	if flags.numbering.startAt > 0 {
+		cfg.PageNumbering.StartAt = flags.numbering.startAt
+		cfg.PageNumbering.Enabled = true
+	}
+	if flags.numbering.frontMatter != "" {
+		cfg.PageNumbering.FrontMatter = flags.numbering.frontMatter
+		cfg.PageNumbering.Enabled = true
+	}
+}
+
+func mergeDiagramFlags(flags *convertFlags, cfg *config.Config) {
+	if flags.diagrams.theme != "" {
+		cfg.Diagrams.Theme = flags.diagrams.theme
+		cfg.Diagrams.Enabled = true
+	}
+}
+
+func mergeMathFlags(flags *convertFlags, cfg *config.Config) {
+	if flags.math.enabled {
+		cfg.Math.Enabled = true
+	}
+	if flags.math.numbering {
+		cfg.Math.Numbering = true
+		cfg.Math.Enabled = true
+	}
+}
+
+func mergeRawHTMLFlags(flags *convertFlags, cfg *config.Config) {
+	if flags.rawHTML.enabled {
+		cfg.HTML.AllowRaw = true
+	}
+}
+
+func mergeInterpolationFlags(flags *convertFlags, cfg *config.Config) {
+	if flags.interp.enabled {
+		cfg.Interpolation.Enabled = true
+	}
+}
+
+// mergeCitationFlags resolves --bibliography against the working directory,
+// as typed, while config and frontmatter paths stay relative to the document.
+func mergeCitationFlags(flags *convertFlags, cfg *config.Config) {
+	if path := flags.citations.bibliography; path != "" {
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		cfg.Bibliography = path
+	}
+	if flags.citations.style != "" {
+		cfg.Citations.Style = flags.citations.style
+	}
+}
+
+func mergeFootnoteFlags(flags *convertFlags, cfg *config.Config) {
+	if flags.footnotes.placement != "" {
+		cfg.Footnotes.Placement = flags.footnotes.placement
+	}
+	if flags.footnotes.numbering != "" {
+		cfg.Footnotes.Numbering = flags.footnotes.numbering
+	}
+}
+
+func mergeOutlineFlags(flags *convertFlags, cfg *config.Config) {
+	if flags.outline.enabled {
+		cfg.Outline.Enabled = true
+	}
+	if flags.outline.minDepth > 0 {
+		cfg.Outline.MinDepth = flags.outline.minDepth
+		cfg.Outline.Enabled = true
+	}
+	if flags.outline.maxDepth > 0 {
+		cfg.Outline.MaxDepth = flags.outline.maxDepth
+		cfg.Outline.Enabled = true
+	}
 }
 
 func mergeWatermarkFlags(flags *convertFlags, cfg *config.Config) {
@@ -281,6 +485,28 @@ func mergePageFlags(flags *convertFlags, cfg *config.Config) {
 	if flags.page.margin > 0 {
 		cfg.Page.Margin = flags.page.margin
 	}
+	if flags.page.width != "" {
+		cfg.Page.Width = flags.page.width
+	}
+	if flags.page.height != "" {
+		cfg.Page.Height = flags.page.height
+	}
+	margins := &cfg.Page.Margins
+	for _, side := range []struct {
+		flag string
+		dst  *string
+	}{
+		{flags.page.marginTop, &margins.Top},
+		{flags.page.marginRight, &margins.Right},
+		{flags.page.marginBottom, &margins.Bottom},
+		{flags.page.marginLeft, &margins.Left},
+		{flags.page.marginInner, &margins.Inner},
+		{flags.page.marginOuter, &margins.Outer},
+	} {
+		if side.flag != "" {
+			*side.dst = side.flag
+		}
+	}
 }
 
 func mergePageBreakFlags(flags *convertFlags, cfg *config.Config) {
@@ -299,10 +525,68 @@ func mergePageBreakFlags(flags *convertFlags, cfg *config.Config) {
 	}
 }
 
+func mergeSecurityFlags(flags *convertFlags, cfg *config.Config) {
+	if flags.security.enabled {
+		cfg.Security.Enabled = true
+	}
+	if flags.security.allowPrint {
+		cfg.Security.AllowPrint = true
+		cfg.Security.Enabled = true
+	}
+	if flags.security.allowCopy {
+		cfg.Security.AllowCopy = true
+		cfg.Security.Enabled = true
+	}
+	if flags.security.allowModify {
+		cfg.Security.AllowModify = true
+		cfg.Security.Enabled = true
+	}
+}
+
+func mergeSigningFlags(flags *convertFlags, cfg *config.Config) {
+	if flags.signing.certFile != "" {
+		cfg.Signing.CertFile = flags.signing.certFile
+		cfg.Signing.Enabled = true
+	}
+	if flags.signing.keyFile != "" {
+		cfg.Signing.KeyFile = flags.signing.keyFile
+	}
+	if flags.signing.reason != "" {
+		cfg.Signing.Reason = flags.signing.reason
+	}
+	if flags.signing.visible {
+		cfg.Signing.Visible = true
+	}
+	if flags.signing.tsaURL != "" {
+		cfg.Signing.TimestampURL = flags.signing.tsaURL
+	}
+}
+
 func mergeDisableFlags(flags *convertFlags, cfg *config.Config) {
 	if flags.footer.disabled {
 		cfg.Footer.Enabled = false
 	}
+	if flags.header.disabled {
+		cfg.Header.Enabled = false
+	}
+	if flags.numbering.disabled {
+		cfg.PageNumbering.Enabled = false
+	}
+	if flags.diagrams.disabled {
+		cfg.Diagrams.Enabled = false
+	}
+	if flags.math.disabled {
+		cfg.Math.Enabled = false
+	}
+	if flags.rawHTML.disabled {
+		cfg.HTML.AllowRaw = false
+	}
+	if flags.interp.disabled {
+		cfg.Interpolation.Enabled = false
+	}
+	if flags.citations.disabled {
+		cfg.Bibliography = ""
+	}
 	if flags.cover.disabled {
 		cfg.Cover.Enabled = false
 	}
@@ -312,12 +596,30 @@ func mergeDisableFlags(flags *convertFlags, cfg *config.Config) {
 	if flags.toc.disabled {
 		cfg.TOC.Enabled = false
 	}
+	if flags.index.disabled {
+		cfg.Index.Enabled = false
+	}
+	if flags.figures.disabled {
+		cfg.ListOfFigures.Enabled = false
+	}
+	if flags.tables.disabled {
+		cfg.ListOfTables.Enabled = false
+	}
+	if flags.outline.disabled {
+		cfg.Outline.Enabled = false
+	}
 	if flags.watermark.disabled {
 		cfg.Watermark.Enabled = false
 	}
 	if flags.pageBreaks.disabled {
 		cfg.PageBreaks.Enabled = false
 	}
+	if flags.security.disabled {
+		cfg.Security.Enabled = false
+	}
+	if flags.signing.disabled {
+		cfg.Signing.Enabled = false
+	}
 }
 
 // resolveDateWithTime resolves "auto" and "auto:FORMAT" to formatted date.
@@ -363,7 +665,8 @@ func resolveTemplateSet(templateFlag string, loader picoloom.AssetLoader) (*pico
 	return loader.LoadTemplateSet(templateFlag)
 }
 
-// loadTemplateSetFromDir loads cover.html and signature.html from a directory.
+// loadTemplateSetFromDir loads cover.html and signature.html from a directory,
+// plus footer.html and header.html when present.
 func loadTemplateSetFromDir(dirPath string) (*picoloom.TemplateSet, error) {
 	coverPath := filepath.Join(dirPath, "cover.html")
 	sigPath := filepath.Join(dirPath, "signature.html")
@@ -392,7 +695,28 @@ func loadTemplateSetFromDir(dirPath string) (*picoloom.TemplateSet, error) {
 		return nil, fmt.Errorf("%w: %q missing signature.html", picoloom.ErrIncompleteTemplateSet, dirPath)
 	}
 
-	return picoloom.NewTemplateSet(dirPath, string(cover), string(signature)), nil
+	ts := picoloom.NewTemplateSet(dirPath, string(cover), string(signature))
+	var err error
+	if ts.Footer, err = readOptionalTemplate(filepath.Join(dirPath, "footer.html")); err != nil {
+		return nil, err
+	}
+	if ts.Header, err = readOptionalTemplate(filepath.Join(dirPath, "header.html")); err != nil {
+		return nil, err
+	}
+	return ts, nil
+}
+
+// readOptionalTemplate reads a template file that a set may omit.
+// Returns an empty string if the file does not exist.
+func readOptionalTemplate(path string) (string, error) {
+	content, err := os.ReadFile(path) // #nosec G304 -- user-provided path
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading %s: %w", filepath.Base(path), err)
+	}
+	return string(content), nil
 }
 
 // resolveCSSContent resolves CSS content from CLI flag, config, or asset loader.
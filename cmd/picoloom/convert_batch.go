@@ -31,6 +31,7 @@ var (
 // CLIConverter is the interface for the conversion service.
 type CLIConverter interface {
 	Convert(ctx context.Context, input picoloom.Input) (*picoloom.ConvertResult, error)
+	ConvertMany(ctx context.Context, input picoloom.Input, chapters []picoloom.Chapter) (*picoloom.ConvertResult, error)
 }
 
 // Compile-time interface implementation check.
@@ -121,6 +122,17 @@ func convertFile(ctx context.Context, service CLIConverter, f FileToConvert, par
 		return result
 	}
 
+	// Merge per-document frontmatter over the shared config
+	fm, _, err := picoloom.ParseFrontmatter(string(content))
+	if err == nil {
+		params, err = documentParams(params, fm)
+	}
+	if err != nil {
+		result.Err = fmt.Errorf("%s: %w", f.InputPath, err)
+		result.Duration = time.Since(start)
+		return result
+	}
+
 	// Build cover data (depends on markdown content for H1 extraction)
 	coverData := buildCoverData(params.cfg, string(content), f.InputPath)
 
@@ -131,52 +143,143 @@ func convertFile(ctx context.Context, service CLIConverter, f FileToConvert, par
 		return result
 	}
 
-	convResult, err := service.Convert(ctx, picoloom.Input{
-		Markdown:   string(content),
-		SourceDir:  filepath.Dir(f.InputPath), // Auto-set for relative image resolution
-		CSS:        params.css,
-		Footer:     params.footer,
-		Signature:  params.signature,
-		Page:       params.page,
-		Watermark:  params.watermark,
-		Cover:      coverData,
-		TOC:        params.toc,
-		PageBreaks: params.pageBreaks,
-		HTMLOnly:   params.htmlOnly,
-	})
+	input := buildInput(params, coverData)
+	input.Metadata = buildMetadata(params.cfg, string(content), f.InputPath)
+	input.Variables = buildVariablesData(params.cfg, string(content), f.InputPath)
+	input.Bibliography = buildBibliographyData(params.cfg)
+	input.Markdown = string(content)
+	input.SourceDir = filepath.Dir(f.InputPath) // Auto-set for relative image resolution
+	convResult, err := service.Convert(ctx, input)
+	if err != nil {
+		result.Err = err
+		result.Duration = time.Since(start)
+		return result
+	}
+
+	result.OutputPath, result.Err = writeOutput(f.OutputPath, convResult, params)
+	result.Duration = time.Since(start)
+	return result
+}
+
+// convertMerged combines chapters into the single PDF named by their shared
+// OutputPath. The first chapter's frontmatter and H1 apply to the whole document.
+func convertMerged(ctx context.Context, pool Pool, inputPath string, chapters []FileToConvert, params *conversionParams) ConversionResult {
+	start := time.Now()
+	outputPath := chapters[0].OutputPath
+	result := ConversionResult{
+		InputPath:  inputPath,
+		OutputPath: outputPath,
+	}
+
+	service := pool.Acquire()
+	if service == nil {
+		result.Err = ErrServiceInit
+		return result
+	}
+	defer pool.Release(service)
+
+	contents := make([]picoloom.Chapter, len(chapters))
+	for i, f := range chapters {
+		content, err := os.ReadFile(f.InputPath) // #nosec G304 -- discovered path
+		if err != nil {
+			result.Err = fmt.Errorf("%w %s: %w", ErrReadMarkdown, f.InputPath, err)
+			result.Duration = time.Since(start)
+			return result
+		}
+		contents[i] = picoloom.Chapter{Markdown: string(content), Path: f.InputPath}
+	}
+
+	fm, _, err := picoloom.ParseFrontmatter(contents[0].Markdown)
+	if err == nil {
+		params, err = documentParams(params, fm)
+	}
+	if err != nil {
+		result.Err = fmt.Errorf("%s: %w", chapters[0].InputPath, err)
+		result.Duration = time.Since(start)
+		return result
+	}
+
+	// Cover title falls back to the first chapter's H1, then the output name
+	coverData := buildCoverData(params.cfg, contents[0].Markdown, outputPath)
+
+	outDir := filepath.Dir(outputPath)
+	if err := os.MkdirAll(outDir, dirPermissions); err != nil {
+		result.Err = fmt.Errorf("cannot create output directory %s: %w%s", outDir, err, hints.ForOutputDirectory())
+		result.Duration = time.Since(start)
+		return result
+	}
+
+	input := buildInput(params, coverData)
+	input.Metadata = buildMetadata(params.cfg, contents[0].Markdown, outputPath)
+	input.Variables = buildVariablesData(params.cfg, contents[0].Markdown, outputPath)
+	input.Bibliography = buildBibliographyData(params.cfg)
+	if b := input.Bibliography; b != nil && !filepath.IsAbs(b.Path) {
+		// No SourceDir here: resolve like the first chapter's frontmatter
+		b.Path = filepath.Join(filepath.Dir(chapters[0].InputPath), b.Path)
+	}
+	convResult, err := service.ConvertMany(ctx, input, contents)
 	if err != nil {
 		result.Err = err
 		result.Duration = time.Since(start)
 		return result
 	}
 
+	result.OutputPath, result.Err = writeOutput(outputPath, convResult, params)
+	result.Duration = time.Since(start)
+	return result
+}
+
+// buildInput creates the document-wide library input from conversion params.
+// Callers set Markdown, SourceDir, and Metadata.
+func buildInput(params *conversionParams, cover *picoloom.Cover) picoloom.Input {
+	return picoloom.Input{
+		CSS:           params.css,
+		Footer:        params.footer,
+		Header:        params.header,
+		Signature:     params.signature,
+		Page:          params.page,
+		Watermark:     params.watermark,
+		Cover:         cover,
+		TOC:           params.toc,
+		Index:         params.index,
+		ListOfFigures: params.figures,
+		ListOfTables:  params.tables,
+		Footnotes:     params.footnotes,
+		Outline:       params.outline,
+		Diagrams:      params.diagrams,
+		Math:          params.math,
+		RawHTML:       params.rawHTML,
+		PageBreaks:    params.pageBreaks,
+		Encryption:    params.encryption,
+		HTMLOnly:      params.htmlOnly,
+
+		PageNumbering:    params.numbering,
+		DigitalSignature: params.signing,
+	}
+}
+
+// writeOutput writes the PDF and, if requested, HTML for one conversion.
+// Returns the path of the primary output (the HTML file for --html-only).
+func writeOutput(pdfPath string, convResult *picoloom.ConvertResult, params *conversionParams) (string, error) {
 	// Write HTML output if requested (--html or --html-only)
 	if params.htmlOnly || params.htmlOutput {
-		htmlPath := htmlOutputPath(f.OutputPath)
+		htmlPath := htmlOutputPath(pdfPath)
 		// #nosec G306 -- HTML files are meant to be readable
 		if err := os.WriteFile(htmlPath, convResult.HTML, filePermissions); err != nil {
-			result.Err = fmt.Errorf("failed to write HTML file: %w", err)
-			result.Duration = time.Since(start)
-			return result
+			return pdfPath, fmt.Errorf("failed to write HTML file: %w", err)
 		}
 		// For --html-only, update output path to HTML file
 		if params.htmlOnly {
-			result.OutputPath = htmlPath
-			result.Duration = time.Since(start)
-			return result
+			return htmlPath, nil
 		}
 	}
 
 	// Write PDF (unless --html-only)
 	// #nosec G306 -- PDFs are meant to be readable
-	if err := os.WriteFile(f.OutputPath, convResult.PDF, filePermissions); err != nil {
-		result.Err = fmt.Errorf("%w: %w", ErrWritePDF, err)
-		result.Duration = time.Since(start)
-		return result
+	if err := os.WriteFile(pdfPath, convResult.PDF, filePermissions); err != nil {
+		return pdfPath, fmt.Errorf("%w: %w", ErrWritePDF, err)
 	}
-
-	result.Duration = time.Since(start)
-	return result
+	return pdfPath, nil
 }
 
 // ResultSummary holds the count of succeeded and failed conversions.
@@ -58,6 +58,43 @@ func discoverFiles(inputPath, outputDir string) ([]FileToConvert, error) {
 	return files, err
 }
 
+// discoverChapters finds the markdown files to merge into one PDF, in order.
+// Inputs keep their given order; files inside a directory are in name order.
+// Every chapter shares the merged output path.
+func discoverChapters(inputPaths []string, outputDir string) ([]FileToConvert, error) {
+	if len(inputPaths) == 0 {
+		return nil, ErrNoInput
+	}
+
+	outPath := resolveMergedOutputPath(inputPaths[0], outputDir)
+	var chapters []FileToConvert
+	for _, inputPath := range inputPaths {
+		files, err := discoverFiles(inputPath, "")
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			chapters = append(chapters, FileToConvert{InputPath: f.InputPath, OutputPath: outPath})
+		}
+	}
+	return chapters, nil
+}
+
+// resolveMergedOutputPath determines the PDF output path for merged inputs.
+// The PDF is named after the first input: "docs/handbook/" gives "docs/handbook.pdf".
+func resolveMergedOutputPath(firstInput, outputDir string) string {
+	if strings.HasSuffix(outputDir, ".pdf") {
+		return outputDir
+	}
+
+	cleaned := filepath.Clean(firstInput)
+	base := strings.TrimSuffix(filepath.Base(cleaned), filepath.Ext(cleaned))
+	if outputDir == "" {
+		return filepath.Join(filepath.Dir(cleaned), base+".pdf")
+	}
+	return filepath.Join(outputDir, base+".pdf")
+}
+
 // resolveOutputPath determines the PDF output path for a markdown file.
 func resolveOutputPath(inputPath, outputDir, baseInputDir string) string {
 	ext := filepath.Ext(inputPath)
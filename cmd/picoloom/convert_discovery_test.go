@@ -9,6 +9,7 @@ package main
 // These are acceptable gaps: we test observable behavior, not implementation details.
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -349,3 +350,98 @@ func TestDiscoverFiles(t *testing.T) {
 		}
 	})
 }
+
+// ---------------------------------------------------------------------------
+// TestResolveMergedOutputPath - Output path for --merge
+// ---------------------------------------------------------------------------
+
+func TestResolveMergedOutputPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		firstInput string
+		outputDir  string
+		want       string
+	}{
+		{"directory beside input", "docs/handbook/", "", filepath.Join("docs", "handbook.pdf")},
+		{"file beside input", filepath.Join("docs", "intro.md"), "", filepath.Join("docs", "intro.pdf")},
+		{"output directory", "docs/handbook", "out", filepath.Join("out", "handbook.pdf")},
+		{"explicit pdf path", "docs/handbook", "book.pdf", "book.pdf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := resolveMergedOutputPath(tt.firstInput, tt.outputDir)
+			if got != tt.want {
+				t.Errorf("resolveMergedOutputPath(%q, %q) = %q, want %q", tt.firstInput, tt.outputDir, got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestDiscoverChapters - Chapter discovery for --merge
+// ---------------------------------------------------------------------------
+
+func TestDiscoverChapters(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	for _, name := range []string{"book/02-usage.md", "book/01-intro.md", "book/10-appendix/a.md", "extra.md"} {
+		fullPath := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0750); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(fullPath, []byte("# x"), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+
+	t.Run("directory in name order then explicit inputs", func(t *testing.T) {
+		t.Parallel()
+
+		bookDir := filepath.Join(tempDir, "book")
+		got, err := discoverChapters([]string{bookDir, filepath.Join(tempDir, "extra.md")}, "")
+		if err != nil {
+			t.Fatalf("discoverChapters() unexpected error: %v", err)
+		}
+
+		want := []string{"01-intro.md", "02-usage.md", "a.md", "extra.md"}
+		if len(got) != len(want) {
+			t.Fatalf("discoverChapters() returned %d chapters, want %d", len(got), len(want))
+		}
+		for i, f := range got {
+			if filepath.Base(f.InputPath) != want[i] {
+				t.Errorf("chapter %d = %q, want %q", i, filepath.Base(f.InputPath), want[i])
+			}
+			if f.OutputPath != filepath.Join(tempDir, "book.pdf") {
+				t.Errorf("chapter %d OutputPath = %q, want %q", i, f.OutputPath, filepath.Join(tempDir, "book.pdf"))
+			}
+		}
+	})
+
+	t.Run("error case: no inputs", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := discoverChapters(nil, "")
+		if !errors.Is(err, ErrNoInput) {
+			t.Errorf("discoverChapters(nil) error = %v, want ErrNoInput", err)
+		}
+	})
+
+	t.Run("error case: non-markdown file", func(t *testing.T) {
+		t.Parallel()
+
+		txt := filepath.Join(tempDir, "notes.txt")
+		if err := os.WriteFile(txt, []byte("x"), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+		_, err := discoverChapters([]string{txt}, "")
+		if !errors.Is(err, ErrInvalidExtension) {
+			t.Errorf("discoverChapters() error = %v, want ErrInvalidExtension", err)
+		}
+	})
+}
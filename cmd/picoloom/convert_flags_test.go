@@ -545,6 +545,145 @@ func TestParseConvertFlags_NewFlags(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "toc-page-numbers flag",
+			args: []string{"--toc-page-numbers"},
+			check: func(t *testing.T, f *convertFlags) {
+				if !f.toc.pageNumbers {
+					t.Error("parseConvertFlags() toc.pageNumbers = false, want true")
+				}
+			},
+		},
+		{
+			name: "page numbering flags",
+			args: []string{"--page-number-format", "page-of", "--page-number-start", "5", "--front-matter", "roman", "--no-page-numbering"},
+			check: func(t *testing.T, f *convertFlags) {
+				want := pageNumberingFlags{format: "page-of", startAt: 5, frontMatter: "roman", disabled: true}
+				if f.numbering != want {
+					t.Errorf("parseConvertFlags() numbering = %+v, want %+v", f.numbering, want)
+				}
+			},
+		},
+		{
+			name: "diagram flags",
+			args: []string{"--diagram-theme", "dark", "--no-diagrams"},
+			check: func(t *testing.T, f *convertFlags) {
+				want := diagramFlags{theme: "dark", disabled: true}
+				if f.diagrams != want {
+					t.Errorf("parseConvertFlags() diagrams = %+v, want %+v", f.diagrams, want)
+				}
+			},
+		},
+		{
+			name: "math flags",
+			args: []string{"--math", "--math-numbering", "--no-math"},
+			check: func(t *testing.T, f *convertFlags) {
+				want := mathFlags{enabled: true, numbering: true, disabled: true}
+				if f.math != want {
+					t.Errorf("parseConvertFlags() math = %+v, want %+v", f.math, want)
+				}
+			},
+		},
+		{
+			name: "raw HTML flags",
+			args: []string{"--raw-html", "--no-raw-html"},
+			check: func(t *testing.T, f *convertFlags) {
+				want := rawHTMLFlags{enabled: true, disabled: true}
+				if f.rawHTML != want {
+					t.Errorf("parseConvertFlags() rawHTML = %+v, want %+v", f.rawHTML, want)
+				}
+			},
+		},
+		{
+			name: "interpolation flags",
+			args: []string{"--interpolate", "--no-interpolate"},
+			check: func(t *testing.T, f *convertFlags) {
+				want := interpolationFlags{enabled: true, disabled: true}
+				if f.interp != want {
+					t.Errorf("parseConvertFlags() interp = %+v, want %+v", f.interp, want)
+				}
+			},
+		},
+		{
+			name: "citation flags",
+			args: []string{"--bibliography", "refs.bib", "--citation-style", "numeric", "--no-bibliography"},
+			check: func(t *testing.T, f *convertFlags) {
+				want := citationFlags{bibliography: "refs.bib", style: "numeric", disabled: true}
+				if f.citations != want {
+					t.Errorf("parseConvertFlags() citations = %+v, want %+v", f.citations, want)
+				}
+			},
+		},
+		{
+			name: "footnote flags",
+			args: []string{"--footnotes", "page", "--footnote-numbering", "page"},
+			check: func(t *testing.T, f *convertFlags) {
+				want := footnoteFlags{placement: "page", numbering: "page"}
+				if f.footnotes != want {
+					t.Errorf("parseConvertFlags() footnotes = %+v, want %+v", f.footnotes, want)
+				}
+			},
+		},
+		{
+			name: "index flags",
+			args: []string{"--index", "--index-title", "Terms", "--no-index"},
+			check: func(t *testing.T, f *convertFlags) {
+				want := indexFlags{enabled: true, title: "Terms", disabled: true}
+				if f.index != want {
+					t.Errorf("parseConvertFlags() index = %+v, want %+v", f.index, want)
+				}
+			},
+		},
+		{
+			name: "list flags",
+			args: []string{"--lof", "--lof-title", "Figures", "--lof-page-numbers", "--no-lot"},
+			check: func(t *testing.T, f *convertFlags) {
+				if want := (listFlags{enabled: true, title: "Figures", pageNumbers: true}); f.figures != want {
+					t.Errorf("parseConvertFlags() figures = %+v, want %+v", f.figures, want)
+				}
+				if want := (listFlags{disabled: true}); f.tables != want {
+					t.Errorf("parseConvertFlags() tables = %+v, want %+v", f.tables, want)
+				}
+			},
+		},
+		{
+			name: "security flags",
+			args: []string{"--encrypt", "--allow-print", "--allow-copy", "--allow-modify"},
+			check: func(t *testing.T, f *convertFlags) {
+				want := securityFlags{enabled: true, allowPrint: true, allowCopy: true, allowModify: true}
+				if f.security != want {
+					t.Errorf("parseConvertFlags() security = %+v, want %+v", f.security, want)
+				}
+			},
+		},
+		{
+			name: "signing flags",
+			args: []string{"--sign-cert", "signer.p12", "--sign-key", "signer.key", "--sign-reason", "Approval", "--sign-visible", "--tsa-url", "http://localhost:3180"},
+			check: func(t *testing.T, f *convertFlags) {
+				want := signingFlags{certFile: "signer.p12", keyFile: "signer.key", reason: "Approval", visible: true, tsaURL: "http://localhost:3180"}
+				if f.signing != want {
+					t.Errorf("parseConvertFlags() signing = %+v, want %+v", f.signing, want)
+				}
+			},
+		},
+		{
+			name: "no-sign flag",
+			args: []string{"--no-sign"},
+			check: func(t *testing.T, f *convertFlags) {
+				if !f.signing.disabled {
+					t.Error("parseConvertFlags() signing.disabled = false, want true")
+				}
+			},
+		},
+		{
+			name: "no-encrypt flag",
+			args: []string{"--no-encrypt"},
+			check: func(t *testing.T, f *convertFlags) {
+				if !f.security.disabled {
+					t.Error("parseConvertFlags() security.disabled = false, want true")
+				}
+			},
+		},
 		{
 			name: "wm-text flag",
 			args: []string{"--wm-text", "DRAFT"},
@@ -636,6 +775,15 @@ func TestParseConvertFlags_NewFlags(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "pdfa flag",
+			args: []string{"--pdfa"},
+			check: func(t *testing.T, f *convertFlags) {
+				if !f.pdfa {
+					t.Error("parseConvertFlags() pdfa = false, want true")
+				}
+			},
+		},
 		{
 			name: "timeout flag long form",
 			args: []string{"--timeout", "2m"},
@@ -9,6 +9,8 @@ package main
 // These are acceptable gaps: we test observable behavior, not implementation details.
 
 import (
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -158,6 +160,226 @@ func TestMergeFlags(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:  "auto-enables header when header.right flag set",
+			flags: &convertFlags{header: headerFlags{right: "{pageNumber}"}},
+			cfg:   &Config{Header: HeaderConfig{Enabled: false, Right: "{date}"}},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Header.Right != "{pageNumber}" {
+					t.Errorf("mergeFlags() Header.Right = %q, want %q", cfg.Header.Right, "{pageNumber}")
+				}
+				if !cfg.Header.Enabled {
+					t.Error("mergeFlags() Header.Enabled = false, want true")
+				}
+			},
+		},
+		{
+			name:  "disables header when header.disabled flag set",
+			flags: &convertFlags{header: headerFlags{left: "{title}", disabled: true}},
+			cfg:   &Config{Header: HeaderConfig{Enabled: true}},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Header.Enabled {
+					t.Error("mergeFlags() Header.Enabled = true, want false")
+				}
+			},
+		},
+		{
+			name:  "auto-enables page numbering when numbering flags set",
+			flags: &convertFlags{numbering: pageNumberingFlags{format: "page-of", startAt: 3, frontMatter: "roman"}},
+			cfg:   &Config{PageNumbering: NumberingConfig{Format: "plain"}},
+			check: func(t *testing.T, cfg *Config) {
+				want := NumberingConfig{Enabled: true, Format: "page-of", StartAt: 3, FrontMatter: "roman"}
+				if cfg.PageNumbering != want {
+					t.Errorf("mergeFlags() PageNumbering = %+v, want %+v", cfg.PageNumbering, want)
+				}
+			},
+		},
+		{
+			name:  "disables page numbering when numbering.disabled flag set",
+			flags: &convertFlags{numbering: pageNumberingFlags{format: "plain", disabled: true}},
+			cfg:   &Config{PageNumbering: NumberingConfig{Enabled: true}},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.PageNumbering.Enabled {
+					t.Error("mergeFlags() PageNumbering.Enabled = true, want false")
+				}
+			},
+		},
+		{
+			name:  "auto-enables diagrams when diagram theme flag set",
+			flags: &convertFlags{diagrams: diagramFlags{theme: "forest"}},
+			cfg:   &Config{Diagrams: DiagramsConfig{Theme: "dark"}},
+			check: func(t *testing.T, cfg *Config) {
+				want := DiagramsConfig{Enabled: true, Theme: "forest"}
+				if cfg.Diagrams != want {
+					t.Errorf("mergeFlags() Diagrams = %+v, want %+v", cfg.Diagrams, want)
+				}
+			},
+		},
+		{
+			name:  "disables diagrams when diagrams.disabled flag set",
+			flags: &convertFlags{diagrams: diagramFlags{theme: "dark", disabled: true}},
+			cfg:   &Config{Diagrams: DiagramsConfig{Enabled: true}},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Diagrams.Enabled {
+					t.Error("mergeFlags() Diagrams.Enabled = true, want false")
+				}
+			},
+		},
+		{
+			name:  "auto-enables math when math numbering flag set",
+			flags: &convertFlags{math: mathFlags{numbering: true}},
+			cfg:   &Config{},
+			check: func(t *testing.T, cfg *Config) {
+				want := MathConfig{Enabled: true, Numbering: true}
+				if cfg.Math != want {
+					t.Errorf("mergeFlags() Math = %+v, want %+v", cfg.Math, want)
+				}
+			},
+		},
+		{
+			name:  "disables math when math.disabled flag set",
+			flags: &convertFlags{math: mathFlags{enabled: true, disabled: true}},
+			cfg:   &Config{Math: MathConfig{Enabled: true}},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Math.Enabled {
+					t.Error("mergeFlags() Math.Enabled = true, want false")
+				}
+			},
+		},
+		{
+			name:  "enables raw HTML when raw-html flag set",
+			flags: &convertFlags{rawHTML: rawHTMLFlags{enabled: true}},
+			cfg:   &Config{HTML: HTMLConfig{AllowedTags: []string{"meter"}}},
+			check: func(t *testing.T, cfg *Config) {
+				if !cfg.HTML.AllowRaw || len(cfg.HTML.AllowedTags) != 1 {
+					t.Errorf("mergeFlags() HTML = %+v, want raw HTML with the config allowlist", cfg.HTML)
+				}
+			},
+		},
+		{
+			name:  "disables raw HTML when no-raw-html flag set",
+			flags: &convertFlags{rawHTML: rawHTMLFlags{enabled: true, disabled: true}},
+			cfg:   &Config{HTML: HTMLConfig{AllowRaw: true}},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.HTML.AllowRaw {
+					t.Error("mergeFlags() HTML.AllowRaw = true, want false")
+				}
+			},
+		},
+		{
+			name:  "enables interpolation when interpolate flag set",
+			flags: &convertFlags{interp: interpolationFlags{enabled: true}},
+			cfg:   &Config{},
+			check: func(t *testing.T, cfg *Config) {
+				if !cfg.Interpolation.Enabled {
+					t.Error("mergeFlags() Interpolation.Enabled = false, want true")
+				}
+			},
+		},
+		{
+			name:  "disables interpolation when interp.disabled flag set",
+			flags: &convertFlags{interp: interpolationFlags{enabled: true, disabled: true}},
+			cfg:   &Config{Interpolation: InterpConfig{Enabled: true}},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Interpolation.Enabled {
+					t.Error("mergeFlags() Interpolation.Enabled = true, want false")
+				}
+			},
+		},
+		{
+			name:  "resolves bibliography flag against the working directory",
+			flags: &convertFlags{citations: citationFlags{bibliography: "refs.bib", style: "numeric"}},
+			cfg:   &Config{Bibliography: "config.bib"},
+			check: func(t *testing.T, cfg *Config) {
+				if !filepath.IsAbs(cfg.Bibliography) || filepath.Base(cfg.Bibliography) != "refs.bib" {
+					t.Errorf("mergeFlags() Bibliography = %q, want absolute refs.bib", cfg.Bibliography)
+				}
+				if cfg.Citations.Style != "numeric" {
+					t.Errorf("mergeFlags() Citations.Style = %q, want numeric", cfg.Citations.Style)
+				}
+			},
+		},
+		{
+			name:  "clears bibliography when no-bibliography flag set",
+			flags: &convertFlags{citations: citationFlags{bibliography: "refs.bib", disabled: true}},
+			cfg:   &Config{Bibliography: "config.bib"},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Bibliography != "" {
+					t.Errorf("mergeFlags() Bibliography = %q, want empty", cfg.Bibliography)
+				}
+			},
+		},
+		{
+			name:  "footnote flags override config",
+			flags: &convertFlags{footnotes: footnoteFlags{numbering: "page"}},
+			cfg:   &Config{Footnotes: FootnotesConfig{Placement: "page", Numbering: "document"}},
+			check: func(t *testing.T, cfg *Config) {
+				want := FootnotesConfig{Placement: "page", Numbering: "page"}
+				if cfg.Footnotes != want {
+					t.Errorf("mergeFlags() Footnotes = %+v, want %+v", cfg.Footnotes, want)
+				}
+			},
+		},
+		{
+			name:  "auto-enables index when index title flag set",
+			flags: &convertFlags{index: indexFlags{title: "Terms"}},
+			cfg:   &Config{},
+			check: func(t *testing.T, cfg *Config) {
+				want := IndexConfig{Enabled: true, Title: "Terms"}
+				if cfg.Index != want {
+					t.Errorf("mergeFlags() Index = %+v, want %+v", cfg.Index, want)
+				}
+			},
+		},
+		{
+			name:  "disables index when index.disabled flag set",
+			flags: &convertFlags{index: indexFlags{enabled: true, disabled: true}},
+			cfg:   &Config{Index: IndexConfig{Enabled: true}},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Index.Enabled {
+					t.Error("mergeFlags() Index.Enabled = true, want false")
+				}
+			},
+		},
+		{
+			name:  "auto-enables lists when list flags set",
+			flags: &convertFlags{figures: listFlags{title: "Figures"}, tables: listFlags{pageNumbers: true}},
+			cfg:   &Config{},
+			check: func(t *testing.T, cfg *Config) {
+				if want := (ListOfConfig{Enabled: true, Title: "Figures"}); cfg.ListOfFigures != want {
+					t.Errorf("mergeFlags() ListOfFigures = %+v, want %+v", cfg.ListOfFigures, want)
+				}
+				if want := (ListOfConfig{Enabled: true, PageNumbers: true}); cfg.ListOfTables != want {
+					t.Errorf("mergeFlags() ListOfTables = %+v, want %+v", cfg.ListOfTables, want)
+				}
+			},
+		},
+		{
+			name:  "disables lists when disabled flags set",
+			flags: &convertFlags{figures: listFlags{disabled: true}, tables: listFlags{enabled: true, disabled: true}},
+			cfg:   &Config{ListOfFigures: ListOfConfig{Enabled: true}},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.ListOfFigures.Enabled || cfg.ListOfTables.Enabled {
+					t.Errorf("mergeFlags() lists = %+v, %+v, want disabled", cfg.ListOfFigures, cfg.ListOfTables)
+				}
+			},
+		},
+		{
+			name: "overrides page dimensions and margin sides with CLI flags",
+			flags: &convertFlags{page: pageFlags{
+				width: "170mm", height: "240mm", marginTop: "2cm", marginInner: "25mm", marginOuter: "15mm",
+			}},
+			cfg: &Config{Page: PageConfig{Width: "6in", Height: "9in", Margins: MarginsConfig{Top: "1in", Bottom: "1in"}}},
+			check: func(t *testing.T, cfg *Config) {
+				want := PageConfig{
+					Width: "170mm", Height: "240mm",
+					Margins: MarginsConfig{Top: "2cm", Bottom: "1in", Inner: "25mm", Outer: "15mm"},
+				}
+				if cfg.Page != want {
+					t.Errorf("mergeFlags() Page = %+v, want %+v", cfg.Page, want)
+				}
+			},
+		},
 		{
 			name:  "overrides cover.logo with CLI flag",
 			flags: &convertFlags{cover: coverFlags{logo: "/cli/logo.png"}},
@@ -228,6 +450,85 @@ func TestMergeFlags(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:  "enables outline with CLI flag",
+			flags: &convertFlags{outline: outlineFlags{enabled: true}},
+			cfg:   &Config{},
+			check: func(t *testing.T, cfg *Config) {
+				if !cfg.Outline.Enabled {
+					t.Error("mergeFlags() Outline.Enabled = false, want true")
+				}
+			},
+		},
+		{
+			name:  "overrides outline.maxDepth with CLI flag and enables outline",
+			flags: &convertFlags{outline: outlineFlags{maxDepth: 5}},
+			cfg:   &Config{Outline: OutlineConfig{MaxDepth: 2}},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Outline.MaxDepth != 5 || !cfg.Outline.Enabled {
+					t.Errorf("mergeFlags() Outline = %+v, want enabled with MaxDepth 5", cfg.Outline)
+				}
+			},
+		},
+		{
+			name:  "disables outline when outline.disabled flag set",
+			flags: &convertFlags{outline: outlineFlags{enabled: true, disabled: true}},
+			cfg:   &Config{Outline: OutlineConfig{Enabled: true}},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Outline.Enabled {
+					t.Error("mergeFlags() Outline.Enabled = true, want false")
+				}
+			},
+		},
+		{
+			name:  "allow flags enable security and keep config passwords",
+			flags: &convertFlags{security: securityFlags{allowPrint: true, allowCopy: true}},
+			cfg:   &Config{Security: SecurityConfig{UserPassword: "secret"}},
+			check: func(t *testing.T, cfg *Config) {
+				want := SecurityConfig{Enabled: true, UserPassword: "secret", AllowPrint: true, AllowCopy: true}
+				if cfg.Security != want {
+					t.Errorf("mergeFlags() Security = %+v, want %+v", cfg.Security, want)
+				}
+			},
+		},
+		{
+			name:  "sign-cert enables signing and keeps config settings",
+			flags: &convertFlags{signing: signingFlags{certFile: "signer.p12", visible: true, tsaURL: "http://localhost:3180"}},
+			cfg:   &Config{Signing: SigningConfig{Password: "secret", Reason: "Approval"}},
+			check: func(t *testing.T, cfg *Config) {
+				want := SigningConfig{
+					Enabled:      true,
+					CertFile:     "signer.p12",
+					Password:     "secret",
+					Reason:       "Approval",
+					Visible:      true,
+					TimestampURL: "http://localhost:3180",
+				}
+				if cfg.Signing != want {
+					t.Errorf("mergeFlags() Signing = %+v, want %+v", cfg.Signing, want)
+				}
+			},
+		},
+		{
+			name:  "disables signing when signing.disabled flag set",
+			flags: &convertFlags{signing: signingFlags{disabled: true}},
+			cfg:   &Config{Signing: SigningConfig{Enabled: true, CertFile: "signer.p12"}},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Signing.Enabled {
+					t.Error("mergeFlags() Signing.Enabled = true, want false")
+				}
+			},
+		},
+		{
+			name:  "disables security when security.disabled flag set",
+			flags: &convertFlags{security: securityFlags{disabled: true}},
+			cfg:   &Config{Security: SecurityConfig{Enabled: true, UserPassword: "secret"}},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Security.Enabled {
+					t.Error("mergeFlags() Security.Enabled = true, want false")
+				}
+			},
+		},
 		{
 			name:  "disables toc when toc.disabled flag set",
 			flags: &convertFlags{toc: tocFlags{disabled: true}},
@@ -372,6 +673,16 @@ func TestMergeFlags(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:  "overrides document.keywords with CLI flag",
+			flags: &convertFlags{document: documentFlags{keywords: []string{"cli", "flag"}}},
+			cfg:   &Config{Document: DocumentConfig{Keywords: []string{"config"}}},
+			check: func(t *testing.T, cfg *Config) {
+				if strings.Join(cfg.Document.Keywords, ",") != "cli,flag" {
+					t.Errorf("mergeFlags() Document.Keywords = %v, want [cli flag]", cfg.Document.Keywords)
+				}
+			},
+		},
 		{
 			name:  "enables footer when footer.showDocumentID flag set",
 			flags: &convertFlags{footer: footerFlags{showDocumentID: true}},
@@ -544,6 +855,16 @@ func TestMergeFlagsAutoEnable(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:  "auto-enables TOC when toc-page-numbers flag set",
+			flags: &convertFlags{toc: tocFlags{pageNumbers: true}},
+			cfg:   &Config{TOC: TOCConfig{Enabled: false}},
+			check: func(t *testing.T, cfg *Config) {
+				if !cfg.TOC.Enabled || !cfg.TOC.PageNumbers {
+					t.Errorf("mergeFlags() TOC = %+v, want enabled with page numbers", cfg.TOC)
+				}
+			},
+		},
 		{
 			name:  "disabled flag takes precedence over auto-enable",
 			flags: &convertFlags{footer: footerFlags{text: "Footer", disabled: true}},
@@ -11,9 +11,11 @@
 package main
 
 import (
+	"fmt"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	picoloom "github.com/alnah/picoloom/v2"
 	"github.com/alnah/picoloom/v2/internal/config"
@@ -23,14 +25,84 @@ import (
 type conversionParams struct {
 	css        string
 	footer     *picoloom.Footer
+	header     *picoloom.Header
+	numbering  *picoloom.PageNumbering
+	diagrams   *picoloom.Diagrams
+	math       *picoloom.Math
+	rawHTML    *picoloom.RawHTML
 	signature  *picoloom.Signature
 	page       *picoloom.PageSettings
 	watermark  *picoloom.Watermark
 	toc        *picoloom.TOC
+	index      *picoloom.Index
+	figures    *picoloom.ListOf
+	tables     *picoloom.ListOf
+	footnotes  *picoloom.Footnotes
+	outline    *picoloom.Outline
 	pageBreaks *picoloom.PageBreaks
+	encryption *picoloom.Encryption
+	signing    *picoloom.DigitalSignature
 	cfg        *config.Config
 	htmlOnly   bool // Output HTML only, skip PDF
 	htmlOutput bool // Output HTML alongside PDF
+
+	// Inputs needed to rebuild the fields above from per-document frontmatter.
+	flags  *convertFlags        // CLI flags, re-applied so they win over frontmatter
+	loader picoloom.AssetLoader // Resolves a frontmatter style
+	now    func() time.Time     // Resolves a frontmatter "auto" date
+}
+
+// documentParams returns the conversion params for one document by merging
+// its frontmatter over the shared config.
+// Priority: CLI flags > frontmatter > config file > env vars > defaults.
+// Returns params unchanged if fm is nil.
+func documentParams(params *conversionParams, fm *picoloom.Frontmatter) (*conversionParams, error) {
+	if fm == nil {
+		return params, nil
+	}
+
+	cfg, err := params.cfg.WithFrontmatter(fm)
+	if err != nil {
+		return nil, err
+	}
+
+	var footerDisabled, headerDisabled bool
+	var tocOpts tocFlags
+	var assetOpts assetFlags
+	if flags := params.flags; flags != nil {
+		mergeFlags(flags, cfg)
+		footerDisabled = flags.footer.disabled
+		headerDisabled = flags.header.disabled
+		tocOpts = flags.toc
+		assetOpts = flags.assets
+	}
+
+	now := params.now
+	if now == nil {
+		now = time.Now
+	}
+	cfg.Document.Date, err = resolveDateWithTime(cfg.Document.Date, now)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date format: %w", err)
+	}
+
+	docParams := *params
+	docParams.cfg = cfg
+	docParams.footer = buildFooterData(cfg, footerDisabled)
+	docParams.header = buildHeaderData(cfg, headerDisabled)
+	docParams.watermark = buildWatermarkData(cfg)
+	docParams.toc = buildTOCData(cfg, tocOpts)
+	docParams.index = buildIndexData(cfg)
+	docParams.figures = buildListOfData(cfg.ListOfFigures)
+	docParams.tables = buildListOfData(cfg.ListOfTables)
+	docParams.footnotes = buildFootnotesData(cfg)
+	if fm.Style != "" && params.loader != nil {
+		docParams.css, err = resolveCSSContent(assetOpts.style, cfg, assetOpts.noStyle, params.loader)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &docParams, nil
 }
 
 // buildSignatureData creates picoloom.Signature from config.
@@ -86,6 +158,25 @@ func buildFooterData(cfg *config.Config, noFooter bool) *picoloom.Footer {
 	}
 }
 
+// buildHeaderData creates picoloom.Header from config.
+// Uses cfg.Document.* to fill the {title}, {version}, {documentID} and {date}
+// placeholders; empty values fall back to the cover at render time.
+func buildHeaderData(cfg *config.Config, noHeader bool) *picoloom.Header {
+	if noHeader || !cfg.Header.Enabled {
+		return nil
+	}
+
+	return &picoloom.Header{
+		Left:       cfg.Header.Left,
+		Center:     cfg.Header.Center,
+		Right:      cfg.Header.Right,
+		Title:      cfg.Document.Title,
+		Version:    cfg.Document.Version,
+		DocumentID: cfg.Document.DocumentID,
+		Date:       cfg.Document.Date,
+	}
+}
+
 // buildWatermarkData creates picoloom.Watermark from config.
 // Flags are merged into config by mergeFlags before this is called.
 func buildWatermarkData(cfg *config.Config) *picoloom.Watermark {
@@ -115,7 +206,8 @@ func buildWatermarkData(cfg *config.Config) *picoloom.Watermark {
 // buildPageSettings creates picoloom.PageSettings from config.
 // Flags are merged into config by mergeFlags before this is called.
 func buildPageSettings(cfg *config.Config) *picoloom.PageSettings {
-	hasConfig := cfg.Page.Size != "" || cfg.Page.Orientation != "" || cfg.Page.Margin > 0
+	hasConfig := cfg.Page.Size != "" || cfg.Page.Orientation != "" || cfg.Page.Margin > 0 ||
+		cfg.Page.Width != "" || cfg.Page.Height != "" || cfg.Page.Margins.IsSet()
 
 	if !hasConfig {
 		return nil
@@ -125,6 +217,9 @@ func buildPageSettings(cfg *config.Config) *picoloom.PageSettings {
 		Size:        cfg.Page.Size,
 		Orientation: cfg.Page.Orientation,
 		Margin:      cfg.Page.Margin,
+		Width:       cfg.Page.Width,
+		Height:      cfg.Page.Height,
+		Margins:     cfg.Page.Margins.ToMargins(),
 	}
 
 	// Apply defaults
@@ -153,6 +248,74 @@ func extractFirstHeading(markdown string) string {
 	return ""
 }
 
+// resolveDocumentTitle returns the document title: config -> H1 -> filename.
+func resolveDocumentTitle(cfg *config.Config, markdownContent, filename string) string {
+	if cfg.Document.Title != "" {
+		return cfg.Document.Title
+	}
+	if title := extractFirstHeading(markdownContent); title != "" {
+		return title
+	}
+	return strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+}
+
+// buildMetadata creates picoloom.Metadata for the PDF document properties.
+// Unlike the cover, metadata is always written, so the PDF title never
+// stays at Chrome's "Document" placeholder.
+func buildMetadata(cfg *config.Config, markdownContent, filename string) *picoloom.Metadata {
+	return &picoloom.Metadata{
+		Title:    resolveDocumentTitle(cfg, markdownContent, filename),
+		Author:   cfg.Author.Name,
+		Subject:  cfg.Document.Description,
+		Keywords: cfg.Document.Keywords,
+	}
+}
+
+// buildVariablesData creates picoloom.Variables from config and markdown
+// content. The title resolves like the cover's.
+func buildVariablesData(cfg *config.Config, markdownContent, filename string) *picoloom.Variables {
+	if !cfg.Interpolation.Enabled {
+		return nil
+	}
+
+	return &picoloom.Variables{
+		Document: picoloom.DocumentVariables{
+			Title:        resolveDocumentTitle(cfg, markdownContent, filename),
+			Subtitle:     cfg.Document.Subtitle,
+			Version:      cfg.Document.Version,
+			Date:         cfg.Document.Date, // Already resolved
+			ClientName:   cfg.Document.ClientName,
+			ProjectName:  cfg.Document.ProjectName,
+			DocumentType: cfg.Document.DocumentType,
+			DocumentID:   cfg.Document.DocumentID,
+			Description:  cfg.Document.Description,
+		},
+		Author: picoloom.AuthorVariables{
+			Name:         cfg.Author.Name,
+			Title:        cfg.Author.Title,
+			Email:        cfg.Author.Email,
+			Organization: cfg.Author.Organization,
+			Phone:        cfg.Author.Phone,
+			Address:      cfg.Author.Address,
+			Department:   cfg.Author.Department,
+		},
+		Vars: cfg.Vars,
+	}
+}
+
+// buildBibliographyData creates picoloom.Bibliography from config. Nil
+// without a bibliography file, so citations are left as written.
+func buildBibliographyData(cfg *config.Config) *picoloom.Bibliography {
+	if cfg.Bibliography == "" {
+		return nil
+	}
+	return &picoloom.Bibliography{
+		Path:  cfg.Bibliography,
+		Style: cfg.Citations.Style,
+		Title: cfg.Citations.Title,
+	}
+}
+
 // buildCoverData creates picoloom.Cover from config and markdown content.
 // Uses cfg.Author.* and cfg.Document.* for metadata.
 // Department is only shown if cfg.Cover.ShowDepartment is true.
@@ -162,17 +325,8 @@ func buildCoverData(cfg *config.Config, markdownContent, filename string) *picol
 	}
 
 	c := &picoloom.Cover{
-		Logo: cfg.Cover.Logo,
-	}
-
-	// Title: config -> H1 -> filename
-	if cfg.Document.Title != "" {
-		c.Title = cfg.Document.Title
-	} else {
-		c.Title = extractFirstHeading(markdownContent)
-		if c.Title == "" {
-			c.Title = strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
-		}
+		Logo:  cfg.Cover.Logo,
+		Title: resolveDocumentTitle(cfg, markdownContent, filename),
 	}
 
 	c.Subtitle = cfg.Document.Subtitle
@@ -209,14 +363,131 @@ func buildTOCData(cfg *config.Config, tocFlags tocFlags) *picoloom.TOC {
 	}
 
 	toc := &picoloom.TOC{
-		Title:    cfg.TOC.Title,
-		MinDepth: cfg.TOC.MinDepth, // 0 = library defaults to 2
-		MaxDepth: maxDepth,
+		Title:       cfg.TOC.Title,
+		MinDepth:    cfg.TOC.MinDepth, // 0 = library defaults to 2
+		MaxDepth:    maxDepth,
+		PageNumbers: cfg.TOC.PageNumbers,
 	}
 
 	return toc
 }
 
+// buildPageNumberingData creates picoloom.PageNumbering from config.
+// Flags are merged into config by mergeFlags before this is called.
+func buildPageNumberingData(cfg *config.Config) *picoloom.PageNumbering {
+	if !cfg.PageNumbering.Enabled {
+		return nil
+	}
+	return &picoloom.PageNumbering{
+		Format:      cfg.PageNumbering.Format,      // "" = library defaults to slash
+		StartAt:     cfg.PageNumbering.StartAt,     // 0 = library defaults to 1
+		FrontMatter: cfg.PageNumbering.FrontMatter, // "" = library defaults to continue
+	}
+}
+
+// buildDiagramsData creates picoloom.Diagrams from config.
+// Flags are merged into config by mergeFlags before this is called.
+func buildDiagramsData(cfg *config.Config) *picoloom.Diagrams {
+	if !cfg.Diagrams.Enabled {
+		return nil
+	}
+	return &picoloom.Diagrams{Theme: cfg.Diagrams.Theme} // "" = library defaults to default
+}
+
+// buildMathData creates picoloom.Math from config.
+// Flags are merged into config by mergeFlags before this is called.
+func buildMathData(cfg *config.Config) *picoloom.Math {
+	if !cfg.Math.Enabled {
+		return nil
+	}
+	return &picoloom.Math{Numbering: cfg.Math.Numbering}
+}
+
+// buildRawHTMLData creates picoloom.RawHTML from config.
+// Flags are merged into config by mergeFlags before this is called.
+func buildRawHTMLData(cfg *config.Config) *picoloom.RawHTML {
+	if !cfg.HTML.AllowRaw {
+		return nil
+	}
+	return &picoloom.RawHTML{AllowedTags: cfg.HTML.AllowedTags, AllowedAttributes: cfg.HTML.AllowedAttributes}
+}
+
+// buildIndexData creates picoloom.Index from config.
+// Flags are merged into config by mergeFlags before this is called.
+func buildIndexData(cfg *config.Config) *picoloom.Index {
+	if !cfg.Index.Enabled {
+		return nil
+	}
+	return &picoloom.Index{Title: cfg.Index.Title} // "" = library defaults to "Index"
+}
+
+// buildListOfData creates picoloom.ListOf from a list of figures or tables
+// config section.
+// Flags are merged into config by mergeFlags before this is called.
+func buildListOfData(cfg config.ListOfConfig) *picoloom.ListOf {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &picoloom.ListOf{Title: cfg.Title, PageNumbers: cfg.PageNumbers} // "" = library default title
+}
+
+// buildFootnotesData creates picoloom.Footnotes from config.
+// Flags are merged into config by mergeFlags before this is called.
+func buildFootnotesData(cfg *config.Config) *picoloom.Footnotes {
+	if cfg.Footnotes.Placement == "" && cfg.Footnotes.Numbering == "" {
+		return nil
+	}
+	return &picoloom.Footnotes{Placement: cfg.Footnotes.Placement, Numbering: cfg.Footnotes.Numbering}
+}
+
+// buildOutlineData creates picoloom.Outline from config.
+// Flags are merged into config by mergeFlags before this is called.
+func buildOutlineData(cfg *config.Config) *picoloom.Outline {
+	if !cfg.Outline.Enabled {
+		return nil
+	}
+	return &picoloom.Outline{
+		MinDepth: cfg.Outline.MinDepth, // 0 = library defaults to 1
+		MaxDepth: cfg.Outline.MaxDepth, // 0 = library defaults to 3
+	}
+}
+
+// buildEncryptionData creates picoloom.Encryption from config.
+// Flags are merged into config by mergeFlags before this is called.
+// Missing passwords are reported by the library (Encryption.Validate).
+func buildEncryptionData(cfg *config.Config) *picoloom.Encryption {
+	if !cfg.Security.Enabled {
+		return nil
+	}
+	return &picoloom.Encryption{
+		UserPassword:  cfg.Security.UserPassword,
+		OwnerPassword: cfg.Security.OwnerPassword,
+		AllowPrint:    cfg.Security.AllowPrint,
+		AllowCopy:     cfg.Security.AllowCopy,
+		AllowModify:   cfg.Security.AllowModify,
+	}
+}
+
+// buildSigningData creates picoloom.DigitalSignature from config.
+// Flags are merged into config by mergeFlags before this is called.
+// A missing certificate is reported by the library (DigitalSignature.Validate).
+func buildSigningData(cfg *config.Config) *picoloom.DigitalSignature {
+	if !cfg.Signing.Enabled {
+		return nil
+	}
+	return &picoloom.DigitalSignature{
+		CertFile:     cfg.Signing.CertFile,
+		KeyFile:      cfg.Signing.KeyFile,
+		Password:     cfg.Signing.Password,
+		Name:         cfg.Signing.Name,
+		Reason:       cfg.Signing.Reason,
+		Location:     cfg.Signing.Location,
+		ContactInfo:  cfg.Signing.ContactInfo,
+		Visible:      cfg.Signing.Visible,
+		TimestampURL: cfg.Signing.TimestampURL,
+	}
+}
+
 // buildPageBreaksData creates picoloom.PageBreaks from config.
 // Flags are merged into config by mergeFlags before this is called.
 func buildPageBreaksData(cfg *config.Config) *picoloom.PageBreaks {
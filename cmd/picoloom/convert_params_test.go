@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -359,6 +360,57 @@ func TestBuildFooterData(t *testing.T) {
 	})
 }
 
+// ---------------------------------------------------------------------------
+// TestBuildHeaderData - Running header data construction
+// ---------------------------------------------------------------------------
+
+func TestBuildHeaderData(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{
+		Document: DocumentConfig{
+			Title:      "Spec",
+			Version:    "v2",
+			DocumentID: "DOC-1",
+			Date:       "2025-01-15",
+		},
+		Header: HeaderConfig{Enabled: true, Left: "{title}", Right: "{pageNumber}"},
+	}
+
+	t.Run("header enabled maps slots and document metadata", func(t *testing.T) {
+		t.Parallel()
+
+		got := buildHeaderData(cfg, false)
+		want := &picoloom.Header{
+			Left:       "{title}",
+			Right:      "{pageNumber}",
+			Title:      "Spec",
+			Version:    "v2",
+			DocumentID: "DOC-1",
+			Date:       "2025-01-15",
+		}
+		if got == nil || *got != *want {
+			t.Errorf("buildHeaderData() = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("noHeader flag returns nil", func(t *testing.T) {
+		t.Parallel()
+
+		if got := buildHeaderData(cfg, true); got != nil {
+			t.Errorf("buildHeaderData() = %+v, want nil", got)
+		}
+	})
+
+	t.Run("header disabled returns nil", func(t *testing.T) {
+		t.Parallel()
+
+		if got := buildHeaderData(&Config{Header: HeaderConfig{Left: "x"}}, false); got != nil {
+			t.Errorf("buildHeaderData() = %+v, want nil", got)
+		}
+	})
+}
+
 // ---------------------------------------------------------------------------
 // TestBuildPageSettings - Page size, orientation, and margin settings
 // ---------------------------------------------------------------------------
@@ -496,6 +548,26 @@ func TestBuildPageSettings(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("custom size and margin sides", func(t *testing.T) {
+		t.Parallel()
+
+		flags := &cliFlags{page: pageFlags{width: "170mm", height: "240mm", marginOuter: "15mm"}}
+		cfg := &Config{Page: PageConfig{Margins: MarginsConfig{Inner: "25mm"}}}
+		mergeFlags(flags, cfg)
+		got := buildPageSettings(cfg)
+
+		if got == nil {
+			t.Fatalf("buildPageSettings() = nil, want PageSettings")
+		}
+		if got.Width != "170mm" || got.Height != "240mm" {
+			t.Errorf("Width, Height = %q, %q, want %q, %q", got.Width, got.Height, "170mm", "240mm")
+		}
+		want := picoloom.Margins{Inner: "25mm", Outer: "15mm"}
+		if got.Margins == nil || *got.Margins != want {
+			t.Errorf("Margins = %+v, want %+v", got.Margins, want)
+		}
+	})
 }
 
 // ---------------------------------------------------------------------------
@@ -937,6 +1009,54 @@ func TestResolveDateWithTime(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// TestBuildMetadata - PDF document properties construction
+// ---------------------------------------------------------------------------
+
+func TestBuildMetadata(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		cfg      *Config
+		markdown string
+		want     picoloom.Metadata
+	}{
+		{
+			name: "maps author and document config",
+			cfg: &Config{
+				Author:   AuthorConfig{Name: "Ada"},
+				Document: DocumentConfig{Title: "Spec", Description: "Summary", Keywords: []string{"go", "pdf"}},
+			},
+			markdown: "# Heading",
+			want:     picoloom.Metadata{Title: "Spec", Author: "Ada", Subject: "Summary", Keywords: []string{"go", "pdf"}},
+		},
+		{
+			name:     "title from H1 with cover disabled",
+			cfg:      &Config{},
+			markdown: "# Release Notes",
+			want:     picoloom.Metadata{Title: "Release Notes"},
+		},
+		{
+			name:     "title falls back to filename",
+			cfg:      &Config{},
+			markdown: "No heading",
+			want:     picoloom.Metadata{Title: "notes"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := buildMetadata(tt.cfg, tt.markdown, "docs/notes.md")
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("buildMetadata() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
 // ---------------------------------------------------------------------------
 // TestBuildCoverData - Cover page data construction
 // ---------------------------------------------------------------------------
@@ -1291,6 +1411,7 @@ func TestBuildTOCData(t *testing.T) {
 		wantTitle    string
 		wantMinDepth int
 		wantMaxDepth int
+		wantPages    bool
 	}{
 		{
 			name:    "noTOC flag returns nil",
@@ -1379,6 +1500,13 @@ func TestBuildTOCData(t *testing.T) {
 			wantMinDepth: 6,
 			wantMaxDepth: 6,
 		},
+		{
+			name:         "page numbers from config",
+			cfg:          &Config{TOC: TOCConfig{Enabled: true, MaxDepth: 3, PageNumbers: true}},
+			flags:        tocFlags{},
+			wantMaxDepth: 3,
+			wantPages:    true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -1406,6 +1534,9 @@ func TestBuildTOCData(t *testing.T) {
 			if got.MaxDepth != tt.wantMaxDepth {
 				t.Errorf("MaxDepth = %d, want %d", got.MaxDepth, tt.wantMaxDepth)
 			}
+			if got.PageNumbers != tt.wantPages {
+				t.Errorf("PageNumbers = %v, want %v", got.PageNumbers, tt.wantPages)
+			}
 		})
 	}
 }
@@ -1529,6 +1660,394 @@ func TestParseBreakBefore(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// TestBuildPageNumberingData - Page numbering data construction
+// ---------------------------------------------------------------------------
+
+func TestBuildPageNumberingData(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cfg  *Config
+		want *picoloom.PageNumbering
+	}{
+		{"disabled returns nil", &Config{PageNumbering: NumberingConfig{Format: "plain"}}, nil},
+		{"enabled keeps zero values for library defaults", &Config{PageNumbering: NumberingConfig{Enabled: true}}, &picoloom.PageNumbering{}},
+		{
+			"enabled with values",
+			&Config{PageNumbering: NumberingConfig{Enabled: true, Format: "page-of", StartAt: 2, FrontMatter: "skip"}},
+			&picoloom.PageNumbering{Format: "page-of", StartAt: 2, FrontMatter: "skip"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := buildPageNumberingData(tt.cfg)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildPageNumberingData() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestBuildDiagramsData - Diagram data construction
+// ---------------------------------------------------------------------------
+
+func TestBuildDiagramsData(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cfg  *Config
+		want *picoloom.Diagrams
+	}{
+		{"disabled returns nil", &Config{Diagrams: DiagramsConfig{Theme: "dark"}}, nil},
+		{"enabled keeps zero theme for library default", &Config{Diagrams: DiagramsConfig{Enabled: true}}, &picoloom.Diagrams{}},
+		{"enabled with theme", &Config{Diagrams: DiagramsConfig{Enabled: true, Theme: "neutral"}}, &picoloom.Diagrams{Theme: "neutral"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := buildDiagramsData(tt.cfg)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildDiagramsData() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestBuildMathData - Math data construction
+// ---------------------------------------------------------------------------
+
+func TestBuildMathData(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cfg  *Config
+		want *picoloom.Math
+	}{
+		{"disabled returns nil", &Config{Math: MathConfig{Numbering: true}}, nil},
+		{"enabled", &Config{Math: MathConfig{Enabled: true}}, &picoloom.Math{}},
+		{"enabled with numbering", &Config{Math: MathConfig{Enabled: true, Numbering: true}}, &picoloom.Math{Numbering: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := buildMathData(tt.cfg)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildMathData() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestBuildRawHTMLData - Raw HTML data construction
+// ---------------------------------------------------------------------------
+
+func TestBuildRawHTMLData(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cfg  *Config
+		want *picoloom.RawHTML
+	}{
+		{"disabled returns nil", &Config{HTML: HTMLConfig{AllowedTags: []string{"meter"}}}, nil},
+		{"enabled", &Config{HTML: HTMLConfig{AllowRaw: true}}, &picoloom.RawHTML{}},
+		{
+			"enabled with allowlist",
+			&Config{HTML: HTMLConfig{AllowRaw: true, AllowedTags: []string{"meter"}, AllowedAttributes: []string{"style"}}},
+			&picoloom.RawHTML{AllowedTags: []string{"meter"}, AllowedAttributes: []string{"style"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := buildRawHTMLData(tt.cfg)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildRawHTMLData() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestBuildIndexData - Index data construction
+// ---------------------------------------------------------------------------
+
+func TestBuildIndexData(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cfg  *Config
+		want *picoloom.Index
+	}{
+		{"disabled returns nil", &Config{Index: IndexConfig{Title: "Terms"}}, nil},
+		{"enabled", &Config{Index: IndexConfig{Enabled: true}}, &picoloom.Index{}},
+		{"enabled with title", &Config{Index: IndexConfig{Enabled: true, Title: "Terms"}}, &picoloom.Index{Title: "Terms"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := buildIndexData(tt.cfg)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildIndexData() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestBuildListOfData - List of figures and tables data construction
+// ---------------------------------------------------------------------------
+
+func TestBuildListOfData(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cfg  ListOfConfig
+		want *picoloom.ListOf
+	}{
+		{"disabled returns nil", ListOfConfig{Title: "Figures", PageNumbers: true}, nil},
+		{"enabled", ListOfConfig{Enabled: true}, &picoloom.ListOf{}},
+		{"enabled with settings", ListOfConfig{Enabled: true, Title: "Figures", PageNumbers: true}, &picoloom.ListOf{Title: "Figures", PageNumbers: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := buildListOfData(tt.cfg)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildListOfData() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestBuildFootnotesData - Footnote placement data construction
+// ---------------------------------------------------------------------------
+
+func TestBuildFootnotesData(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cfg  FootnotesConfig
+		want *picoloom.Footnotes
+	}{
+		{"unset returns nil", FootnotesConfig{}, nil},
+		{"placement only", FootnotesConfig{Placement: "page"}, &picoloom.Footnotes{Placement: "page"}},
+		{"placement and numbering", FootnotesConfig{Placement: "page", Numbering: "page"}, &picoloom.Footnotes{Placement: "page", Numbering: "page"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := buildFootnotesData(&Config{Footnotes: tt.cfg})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildFootnotesData() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestBuildVariablesData - Variable data construction
+// ---------------------------------------------------------------------------
+
+func TestBuildVariablesData(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{
+		Interpolation: InterpConfig{Enabled: true},
+		Author:        AuthorConfig{Name: "Alice", Department: "R&D"},
+		Document:      DocumentConfig{Version: "v2", DocumentID: "DOC-7"},
+		Vars:          map[string]string{"client": "Acme"},
+	}
+
+	tests := []struct {
+		name string
+		cfg  *Config
+		want *picoloom.Variables
+	}{
+		{"disabled returns nil", &Config{Vars: map[string]string{"client": "Acme"}}, nil},
+		{
+			"enabled",
+			cfg,
+			&picoloom.Variables{
+				Document: picoloom.DocumentVariables{Title: "Guide", Version: "v2", DocumentID: "DOC-7"},
+				Author:   picoloom.AuthorVariables{Name: "Alice", Department: "R&D"},
+				Vars:     map[string]string{"client": "Acme"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := buildVariablesData(tt.cfg, "# Guide\n\nBody", "docs/guide.md")
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildVariablesData() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestBuildBibliographyData - Citation settings construction
+// ---------------------------------------------------------------------------
+
+func TestBuildBibliographyData(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cfg  *Config
+		want *picoloom.Bibliography
+	}{
+		{"no file returns nil", &Config{Citations: CitationsConfig{Style: "numeric"}}, nil},
+		{
+			"file with settings",
+			&Config{Bibliography: "refs.bib", Citations: CitationsConfig{Style: "numeric", Title: "Sources"}},
+			&picoloom.Bibliography{Path: "refs.bib", Style: "numeric", Title: "Sources"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := buildBibliographyData(tt.cfg); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildBibliographyData() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestBuildOutlineData - PDF outline data construction
+// ---------------------------------------------------------------------------
+
+func TestBuildOutlineData(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cfg  *Config
+		want *picoloom.Outline
+	}{
+		{"disabled returns nil", &Config{Outline: OutlineConfig{MaxDepth: 4}}, nil},
+		{"enabled keeps zero depths for library defaults", &Config{Outline: OutlineConfig{Enabled: true}}, &picoloom.Outline{}},
+		{"enabled with depths", &Config{Outline: OutlineConfig{Enabled: true, MinDepth: 2, MaxDepth: 4}}, &picoloom.Outline{MinDepth: 2, MaxDepth: 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := buildOutlineData(tt.cfg)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildOutlineData() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestBuildEncryptionData - Password protection data construction
+// ---------------------------------------------------------------------------
+
+func TestBuildEncryptionData(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cfg  *Config
+		want *picoloom.Encryption
+	}{
+		{"disabled returns nil", &Config{Security: SecurityConfig{UserPassword: "secret"}}, nil},
+		{
+			"enabled maps passwords and permissions",
+			&Config{Security: SecurityConfig{Enabled: true, UserPassword: "u", OwnerPassword: "o", AllowPrint: true, AllowModify: true}},
+			&picoloom.Encryption{UserPassword: "u", OwnerPassword: "o", AllowPrint: true, AllowModify: true},
+		},
+		{"enabled without passwords is left to library validation", &Config{Security: SecurityConfig{Enabled: true}}, &picoloom.Encryption{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := buildEncryptionData(tt.cfg)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildEncryptionData() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestBuildSigningData - Digital signature data construction
+// ---------------------------------------------------------------------------
+
+func TestBuildSigningData(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cfg  *Config
+		want *picoloom.DigitalSignature
+	}{
+		{"disabled returns nil", &Config{Signing: SigningConfig{CertFile: "signer.p12"}}, nil},
+		{
+			"enabled maps every setting",
+			&Config{Signing: SigningConfig{
+				Enabled: true, CertFile: "signer.pem", KeyFile: "signer.key", Password: "pw",
+				Name: "Jane", Reason: "Approval", Location: "Paris", ContactInfo: "jane@example.com",
+				Visible: true, TimestampURL: "http://localhost:3180",
+			}},
+			&picoloom.DigitalSignature{
+				CertFile: "signer.pem", KeyFile: "signer.key", Password: "pw",
+				Name: "Jane", Reason: "Approval", Location: "Paris", ContactInfo: "jane@example.com",
+				Visible: true, TimestampURL: "http://localhost:3180",
+			},
+		},
+		{"enabled without certificate is left to library validation", &Config{Signing: SigningConfig{Enabled: true}}, &picoloom.DigitalSignature{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := buildSigningData(tt.cfg)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildSigningData() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
 // ---------------------------------------------------------------------------
 // TestBuildPageBreaksData - Page breaks data construction
 // ---------------------------------------------------------------------------
@@ -4,7 +4,8 @@ package main
 // - resolveCSSContent: we test CSS loading from flag, config style name, and default.
 // - printResultsOutput: we test success/failure counting (actual output formatting
 //   is an implementation detail).
-// - convertFile: we test error paths (read failure, write failure, mkdir failure).
+// - convertFile: we test error paths (read failure, write failure, mkdir failure)
+//   and per-document frontmatter merging.
 //   Success paths are covered by integration tests.
 // - loadTemplateSetFromDir: we test directory loading with complete/incomplete templates.
 // - resolveTemplateSet: we test name vs path resolution.
@@ -13,6 +14,7 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -452,6 +454,168 @@ func TestConvertFile_SourceDir(t *testing.T) {
 	})
 }
 
+// ---------------------------------------------------------------------------
+// TestConvertFile_Frontmatter - Per-document frontmatter metadata
+// ---------------------------------------------------------------------------
+
+func TestConvertFile_Frontmatter(t *testing.T) {
+	t.Parallel()
+
+	writeInput := func(t *testing.T, content string) FileToConvert {
+		t.Helper()
+		tempDir := t.TempDir()
+		inputPath := filepath.Join(tempDir, "doc.md")
+		if err := os.WriteFile(inputPath, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create input: %v", err)
+		}
+		return FileToConvert{InputPath: inputPath, OutputPath: filepath.Join(tempDir, "doc.pdf")}
+	}
+
+	t.Run("overrides shared config for this file only", func(t *testing.T) {
+		t.Parallel()
+
+		f := writeInput(t, "---\ntitle: From Frontmatter\nversion: v9\nwatermark:\n  text: DRAFT\n---\n# Heading\n")
+
+		cfg := config.DefaultConfig()
+		cfg.Cover.Enabled = true
+		cfg.Document.Title = "From Config"
+		params := &conversionParams{cfg: cfg}
+		mockConv := &capturingMockConverter{result: []byte("%PDF-1.4 mock")}
+
+		result := convertFile(context.Background(), mockConv, f, params)
+		if result.Err != nil {
+			t.Fatalf("convertFile() error = %v", result.Err)
+		}
+
+		in := mockConv.capturedIn
+		if in.Cover == nil || in.Cover.Title != "From Frontmatter" || in.Cover.Version != "v9" {
+			t.Errorf("Cover = %+v, want frontmatter title and version", in.Cover)
+		}
+		if in.Watermark == nil || in.Watermark.Text != "DRAFT" {
+			t.Errorf("Watermark = %+v, want DRAFT", in.Watermark)
+		}
+		if cfg.Document.Title != "From Config" || params.watermark != nil {
+			t.Error("convertFile() mutated shared params")
+		}
+	})
+
+	t.Run("CLI flags win over frontmatter", func(t *testing.T) {
+		t.Parallel()
+
+		f := writeInput(t, "---\ntitle: From Frontmatter\n---\nBody\n")
+
+		cfg := config.DefaultConfig()
+		cfg.Cover.Enabled = true
+		flags := &convertFlags{}
+		flags.document.title = "From Flag"
+		flags.watermark.angle = watermarkAngleSentinel
+		mockConv := &capturingMockConverter{result: []byte("%PDF-1.4 mock")}
+
+		result := convertFile(context.Background(), mockConv, f, &conversionParams{cfg: cfg, flags: flags})
+		if result.Err != nil {
+			t.Fatalf("convertFile() error = %v", result.Err)
+		}
+		if got := mockConv.capturedIn.Cover.Title; got != "From Flag" {
+			t.Errorf("Cover.Title = %q, want %q", got, "From Flag")
+		}
+	})
+
+	t.Run("error case: unknown key names the file", func(t *testing.T) {
+		t.Parallel()
+
+		f := writeInput(t, "---\ntitel: Typo\n---\nBody\n")
+		mockConv := &staticMockConverter{result: []byte("%PDF-1.4 mock")}
+
+		result := convertFile(context.Background(), mockConv, f, &conversionParams{cfg: config.DefaultConfig()})
+		if !errors.Is(result.Err, picoloom.ErrInvalidFrontmatter) {
+			t.Errorf("convertFile() error = %v, want ErrInvalidFrontmatter", result.Err)
+		}
+		if result.Err != nil && !strings.Contains(result.Err.Error(), f.InputPath) {
+			t.Errorf("convertFile() error = %q, want it to contain %q", result.Err, f.InputPath)
+		}
+	})
+}
+
+// ---------------------------------------------------------------------------
+// TestConvertMerged - Book mode (--merge)
+// ---------------------------------------------------------------------------
+
+func TestConvertMerged(t *testing.T) {
+	t.Parallel()
+
+	writeChapters := func(t *testing.T, contents ...string) []FileToConvert {
+		t.Helper()
+		tempDir := t.TempDir()
+		out := filepath.Join(tempDir, "out", "book.pdf")
+		files := make([]FileToConvert, len(contents))
+		for i, content := range contents {
+			path := filepath.Join(tempDir, fmt.Sprintf("%02d.md", i+1))
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatalf("failed to create input: %v", err)
+			}
+			files[i] = FileToConvert{InputPath: path, OutputPath: out}
+		}
+		return files
+	}
+
+	t.Run("writes one PDF from all chapters", func(t *testing.T) {
+		t.Parallel()
+
+		files := writeChapters(t, "---\ntitle: Handbook\n---\n# Intro\n", "# Usage\n")
+		cfg := config.DefaultConfig()
+		cfg.Cover.Enabled = true
+		mockConv := &capturingMockConverter{result: []byte("%PDF-1.4 book")}
+
+		result := convertMerged(context.Background(), &mockPool{conv: mockConv}, "docs", files, &conversionParams{cfg: cfg})
+		if result.Err != nil {
+			t.Fatalf("convertMerged() error = %v", result.Err)
+		}
+
+		if len(mockConv.capturedChapters) != 2 {
+			t.Fatalf("ConvertMany() got %d chapters, want 2", len(mockConv.capturedChapters))
+		}
+		if mockConv.capturedChapters[1].Path != files[1].InputPath {
+			t.Errorf("chapter 2 Path = %q, want %q", mockConv.capturedChapters[1].Path, files[1].InputPath)
+		}
+		if mockConv.capturedIn.Markdown != "" {
+			t.Errorf("Input.Markdown = %q, want empty", mockConv.capturedIn.Markdown)
+		}
+		if mockConv.capturedIn.Cover == nil || mockConv.capturedIn.Cover.Title != "Handbook" {
+			t.Errorf("Cover = %+v, want title from first chapter frontmatter", mockConv.capturedIn.Cover)
+		}
+		got, err := os.ReadFile(files[0].OutputPath)
+		if err != nil {
+			t.Fatalf("reading merged PDF: %v", err)
+		}
+		if string(got) != "%PDF-1.4 book" {
+			t.Errorf("merged PDF = %q, want %q", got, "%PDF-1.4 book")
+		}
+	})
+
+	t.Run("error case: service init failure", func(t *testing.T) {
+		t.Parallel()
+
+		files := writeChapters(t, "# Intro\n")
+		result := convertMerged(context.Background(), &mockPool{}, "docs", files, &conversionParams{cfg: config.DefaultConfig()})
+		if !errors.Is(result.Err, ErrServiceInit) {
+			t.Errorf("convertMerged() error = %v, want ErrServiceInit", result.Err)
+		}
+	})
+
+	t.Run("error case: unreadable chapter", func(t *testing.T) {
+		t.Parallel()
+
+		files := writeChapters(t, "# Intro\n")
+		files = append(files, FileToConvert{InputPath: filepath.Join(t.TempDir(), "missing.md"), OutputPath: files[0].OutputPath})
+		mockConv := &capturingMockConverter{result: []byte("%PDF-1.4 book")}
+
+		result := convertMerged(context.Background(), &mockPool{conv: mockConv}, "docs", files, &conversionParams{cfg: config.DefaultConfig()})
+		if !errors.Is(result.Err, ErrReadMarkdown) {
+			t.Errorf("convertMerged() error = %v, want ErrReadMarkdown", result.Err)
+		}
+	})
+}
+
 // ---------------------------------------------------------------------------
 // TestHtmlOutputPath - HTML output path generation
 // ---------------------------------------------------------------------------
@@ -545,6 +709,33 @@ func TestLoadTemplateSetFromDir(t *testing.T) {
 		}
 	})
 
+	t.Run("happy path: loads optional footer and header", func(t *testing.T) {
+		t.Parallel()
+
+		tmpDir := t.TempDir()
+		files := map[string]string{
+			"cover.html":     "<cover/>",
+			"signature.html": "<sig/>",
+			"footer.html":    "<div>{{.PageNumber}}</div>",
+		}
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+				t.Fatalf("failed to write %s: %v", name, err)
+			}
+		}
+
+		ts, err := loadTemplateSetFromDir(tmpDir)
+		if err != nil {
+			t.Fatalf("loadTemplateSetFromDir(%q) unexpected error: %v", tmpDir, err)
+		}
+		if ts.Footer != files["footer.html"] {
+			t.Errorf("loadTemplateSetFromDir(%q) Footer = %q, want %q", tmpDir, ts.Footer, files["footer.html"])
+		}
+		if ts.Header != "" {
+			t.Errorf("loadTemplateSetFromDir(%q) Header = %q, want empty", tmpDir, ts.Header)
+		}
+	})
+
 	t.Run("error case: missing cover.html", func(t *testing.T) {
 		t.Parallel()
 
@@ -24,13 +24,27 @@ type (
 	OutputConfig     = config.OutputConfig
 	SignatureConfig  = config.SignatureConfig
 	FooterConfig     = config.FooterConfig
+	HeaderConfig     = config.HeaderConfig
+	NumberingConfig  = config.PageNumberingConfig
+	DiagramsConfig   = config.DiagramsConfig
+	MathConfig       = config.MathConfig
+	HTMLConfig       = config.HTMLConfig
+	InterpConfig     = config.InterpolationConfig
+	CitationsConfig  = config.CitationsConfig
+	IndexConfig      = config.IndexConfig
+	ListOfConfig     = config.ListOfConfig
+	FootnotesConfig  = config.FootnotesConfig
 	AuthorConfig     = config.AuthorConfig
 	DocumentConfig   = config.DocumentConfig
 	PageConfig       = config.PageConfig
+	MarginsConfig    = config.MarginsConfig
 	WatermarkConfig  = config.WatermarkConfig
 	CoverConfig      = config.CoverConfig
 	TOCConfig        = config.TOCConfig
+	OutlineConfig    = config.OutlineConfig
 	PageBreaksConfig = config.PageBreaksConfig
+	SecurityConfig   = config.SecurityConfig
+	SigningConfig    = config.SigningConfig
 	Link             = config.Link
 )
 
@@ -77,12 +91,17 @@ func (m *staticMockConverter) Convert(_ context.Context, _ picoloom.Input) (*pic
 	return &picoloom.ConvertResult{PDF: m.result}, nil
 }
 
+func (m *staticMockConverter) ConvertMany(ctx context.Context, input picoloom.Input, _ []picoloom.Chapter) (*picoloom.ConvertResult, error) {
+	return m.Convert(ctx, input)
+}
+
 // capturingMockConverter captures the Input for inspection in tests.
 type capturingMockConverter struct {
-	result      []byte
-	err         error
-	capturedIn  picoloom.Input
-	convertFunc func(picoloom.Input) (*picoloom.ConvertResult, error)
+	result           []byte
+	err              error
+	capturedIn       picoloom.Input
+	capturedChapters []picoloom.Chapter
+	convertFunc      func(picoloom.Input) (*picoloom.ConvertResult, error)
 }
 
 func (m *capturingMockConverter) Convert(_ context.Context, input picoloom.Input) (*picoloom.ConvertResult, error) {
@@ -96,6 +115,20 @@ func (m *capturingMockConverter) Convert(_ context.Context, input picoloom.Input
 	return &picoloom.ConvertResult{PDF: m.result}, nil
 }
 
+func (m *capturingMockConverter) ConvertMany(ctx context.Context, input picoloom.Input, chapters []picoloom.Chapter) (*picoloom.ConvertResult, error) {
+	m.capturedChapters = chapters
+	return m.Convert(ctx, input)
+}
+
+// mockPool hands out a single converter; a nil converter simulates init failure.
+type mockPool struct {
+	conv CLIConverter
+}
+
+func (p *mockPool) Acquire() CLIConverter { return p.conv }
+func (p *mockPool) Release(CLIConverter)  {}
+func (p *mockPool) Size() int             { return 1 }
+
 // mockTemplateLoader implements picoloom.AssetLoader for testing resolveTemplateSet.
 type mockTemplateLoader struct {
 	templateSets map[string]*picoloom.TemplateSet
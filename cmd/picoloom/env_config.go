@@ -34,6 +34,11 @@ type envConfig struct {
 	DocDate       string // PICOLOOM_DOC_DATE / MD2PDF_DOC_DATE: document date
 	DocID         string // PICOLOOM_DOC_ID / MD2PDF_DOC_ID: document ID
 	Workers       int    // PICOLOOM_WORKERS / MD2PDF_WORKERS: parallel workers
+
+	// Tier 3 - Secrets, kept off the command line
+	UserPassword  string // PICOLOOM_USER_PASSWORD / MD2PDF_USER_PASSWORD: PDF open password
+	OwnerPassword string // PICOLOOM_OWNER_PASSWORD / MD2PDF_OWNER_PASSWORD: PDF permissions password
+	SignPassword  string // PICOLOOM_SIGN_PASSWORD / MD2PDF_SIGN_PASSWORD: signing key password
 }
 
 const (
@@ -57,6 +62,9 @@ var envVarSuffixes = []string{
 	"DOC_DATE",
 	"DOC_ID",
 	"WORKERS",
+	"USER_PASSWORD",
+	"OWNER_PASSWORD",
+	"SIGN_PASSWORD",
 	"CONTAINER",
 }
 
@@ -100,6 +108,9 @@ func loadEnvConfig() *envConfig {
 		DocVersion:    lookupEnv("DOC_VERSION"),
 		DocDate:       lookupEnv("DOC_DATE"),
 		DocID:         lookupEnv("DOC_ID"),
+		UserPassword:  lookupEnv("USER_PASSWORD"),
+		OwnerPassword: lookupEnv("OWNER_PASSWORD"),
+		SignPassword:  lookupEnv("SIGN_PASSWORD"),
 	}
 
 	// Parse duration for timeout
@@ -192,4 +203,18 @@ func applyEnvConfig(env *envConfig, cfg *config.Config) {
 	if env.DocID != "" && cfg.Document.DocumentID == "" {
 		cfg.Document.DocumentID = env.DocID
 	}
+
+	// Tier 3 - Passwords (auto-enable)
+	if env.UserPassword != "" && cfg.Security.UserPassword == "" {
+		cfg.Security.UserPassword = env.UserPassword
+		cfg.Security.Enabled = true
+	}
+	if env.OwnerPassword != "" && cfg.Security.OwnerPassword == "" {
+		cfg.Security.OwnerPassword = env.OwnerPassword
+		cfg.Security.Enabled = true
+	}
+	// The signing password alone does not enable signing: it needs a certificate.
+	if env.SignPassword != "" && cfg.Signing.Password == "" {
+		cfg.Signing.Password = env.SignPassword
+	}
 }
@@ -67,6 +67,24 @@ func TestLoadEnvConfig(t *testing.T) {
 		}
 	})
 
+	t.Run("password variables", func(t *testing.T) {
+		t.Setenv("PICOLOOM_USER_PASSWORD", "open-sesame")
+		t.Setenv("MD2PDF_OWNER_PASSWORD", "legacy-admin")
+		t.Setenv("PICOLOOM_SIGN_PASSWORD", "key-secret")
+
+		cfg := loadEnvConfig()
+
+		if cfg.UserPassword != "open-sesame" {
+			t.Errorf("loadEnvConfig() UserPassword = %q, want open-sesame", cfg.UserPassword)
+		}
+		if cfg.OwnerPassword != "legacy-admin" {
+			t.Errorf("loadEnvConfig() OwnerPassword = %q, want legacy-admin", cfg.OwnerPassword)
+		}
+		if cfg.SignPassword != "key-secret" {
+			t.Errorf("loadEnvConfig() SignPassword = %q, want key-secret", cfg.SignPassword)
+		}
+	})
+
 	t.Run("tier 3 extended variables", func(t *testing.T) {
 		t.Setenv("PICOLOOM_PAGE_SIZE", "a4")
 		t.Setenv("PICOLOOM_WATERMARK_TEXT", "DRAFT")
@@ -315,6 +333,38 @@ func TestApplyEnvConfig(t *testing.T) {
 		}
 	})
 
+	t.Run("passwords auto-enable security without overriding config", func(t *testing.T) {
+		env := &envConfig{UserPassword: "env-user", OwnerPassword: "env-owner"}
+		cfg := config.DefaultConfig()
+		cfg.Security.OwnerPassword = "config-owner"
+
+		applyEnvConfig(env, cfg)
+
+		if !cfg.Security.Enabled {
+			t.Error("applyEnvConfig() Security.Enabled = false, want true (auto-enabled)")
+		}
+		if cfg.Security.UserPassword != "env-user" {
+			t.Errorf("applyEnvConfig() Security.UserPassword = %q, want env-user", cfg.Security.UserPassword)
+		}
+		if cfg.Security.OwnerPassword != "config-owner" {
+			t.Errorf("applyEnvConfig() Security.OwnerPassword = %q, want config-owner", cfg.Security.OwnerPassword)
+		}
+	})
+
+	t.Run("sign password does not enable signing", func(t *testing.T) {
+		env := &envConfig{SignPassword: "key-secret"}
+		cfg := config.DefaultConfig()
+
+		applyEnvConfig(env, cfg)
+
+		if cfg.Signing.Enabled {
+			t.Error("applyEnvConfig() Signing.Enabled = true, want false (needs a certificate)")
+		}
+		if cfg.Signing.Password != "key-secret" {
+			t.Errorf("applyEnvConfig() Signing.Password = %q, want key-secret", cfg.Signing.Password)
+		}
+	})
+
 	t.Run("priority: does not override existing config values", func(t *testing.T) {
 		env := &envConfig{
 			Style:      "env-style",
@@ -458,6 +508,9 @@ func TestKnownEnvVars(t *testing.T) {
 		"PICOLOOM_DOC_DATE",
 		"PICOLOOM_DOC_ID",
 		"PICOLOOM_WORKERS",
+		"PICOLOOM_USER_PASSWORD",
+		"PICOLOOM_OWNER_PASSWORD",
+		"PICOLOOM_SIGN_PASSWORD",
 		"PICOLOOM_CONTAINER",
 		"MD2PDF_CONFIG",
 		"MD2PDF_STYLE",
@@ -474,6 +527,9 @@ func TestKnownEnvVars(t *testing.T) {
 		"MD2PDF_DOC_DATE",
 		"MD2PDF_DOC_ID",
 		"MD2PDF_WORKERS",
+		"MD2PDF_USER_PASSWORD",
+		"MD2PDF_OWNER_PASSWORD",
+		"MD2PDF_SIGN_PASSWORD",
 		"MD2PDF_CONTAINER",
 	}
 
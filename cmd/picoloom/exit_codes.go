@@ -30,6 +30,7 @@ var (
 		config.ErrConfigParse,
 		config.ErrFieldTooLong,
 		ErrConfigCommandUsage,
+		ErrVerifyCommandUsage,
 		ErrConfigInitNeedsTTY,
 		ErrConfigInitExists,
 		ErrConfigInitBusy,
@@ -38,13 +39,33 @@ var (
 		picoloom.ErrInvalidOrientation,
 		picoloom.ErrInvalidMargin,
 		picoloom.ErrInvalidFooterPosition,
+		picoloom.ErrInvalidHeaderPlaceholder,
+		picoloom.ErrInvalidPageNumbering,
+		picoloom.ErrInvalidDiagramTheme,
+		picoloom.ErrDiagramRender,
+		picoloom.ErrMathRender,
+		picoloom.ErrInclude,
+		picoloom.ErrInvalidVariable,
+		picoloom.ErrInterpolation,
+		picoloom.ErrCrossReference,
+		picoloom.ErrInvalidBibliography,
+		picoloom.ErrCitation,
+		picoloom.ErrInvalidRawHTML,
+		picoloom.ErrInvalidFootnotes,
+		picoloom.ErrFootnoteLayout,
 		picoloom.ErrInvalidWatermarkColor,
 		picoloom.ErrInvalidTOCDepth,
+		picoloom.ErrInvalidOutlineDepth,
 		picoloom.ErrInvalidOrphans,
 		picoloom.ErrInvalidWidows,
+		picoloom.ErrInvalidEncryption,
+		picoloom.ErrInvalidDigitalSignature,
+		picoloom.ErrSigningCredentials,
 		picoloom.ErrStyleNotFound,
 		picoloom.ErrTemplateSetNotFound,
 		picoloom.ErrIncompleteTemplateSet,
+		picoloom.ErrPageTemplateRender,
+		picoloom.ErrInvalidFrontmatter,
 		picoloom.ErrInvalidAssetPath,
 		ErrUnsupportedShell,
 	}
@@ -58,11 +58,30 @@ func TestExitCodeFor(t *testing.T) {
 		{"returns usage exit code for invalid footer position error", picoloom.ErrInvalidFooterPosition, ExitUsage},
 		{"returns usage exit code for invalid watermark color error", picoloom.ErrInvalidWatermarkColor, ExitUsage},
 		{"returns usage exit code for invalid toc depth error", picoloom.ErrInvalidTOCDepth, ExitUsage},
+		{"returns usage exit code for invalid outline depth error", picoloom.ErrInvalidOutlineDepth, ExitUsage},
 		{"returns usage exit code for invalid orphans error", picoloom.ErrInvalidOrphans, ExitUsage},
 		{"returns usage exit code for invalid widows error", picoloom.ErrInvalidWidows, ExitUsage},
 		{"returns usage exit code for style not found error", picoloom.ErrStyleNotFound, ExitUsage},
 		{"returns usage exit code for template set not found error", picoloom.ErrTemplateSetNotFound, ExitUsage},
 		{"returns usage exit code for incomplete template set error", picoloom.ErrIncompleteTemplateSet, ExitUsage},
+		{"returns usage exit code for page template render error", picoloom.ErrPageTemplateRender, ExitUsage},
+		{"returns usage exit code for invalid header placeholder error", picoloom.ErrInvalidHeaderPlaceholder, ExitUsage},
+		{"returns usage exit code for invalid page numbering error", picoloom.ErrInvalidPageNumbering, ExitUsage},
+		{"returns usage exit code for invalid diagram theme error", picoloom.ErrInvalidDiagramTheme, ExitUsage},
+		{"returns usage exit code for diagram render error", picoloom.ErrDiagramRender, ExitUsage},
+		{"returns usage exit code for math render error", picoloom.ErrMathRender, ExitUsage},
+		{"returns usage exit code for include error", picoloom.ErrInclude, ExitUsage},
+		{"returns usage exit code for invalid variable error", picoloom.ErrInvalidVariable, ExitUsage},
+		{"returns usage exit code for interpolation error", picoloom.ErrInterpolation, ExitUsage},
+		{"returns usage exit code for cross-reference error", picoloom.ErrCrossReference, ExitUsage},
+		{"returns usage exit code for invalid bibliography error", picoloom.ErrInvalidBibliography, ExitUsage},
+		{"returns usage exit code for citation error", picoloom.ErrCitation, ExitUsage},
+		{"returns usage exit code for invalid raw HTML error", picoloom.ErrInvalidRawHTML, ExitUsage},
+		{"returns usage exit code for invalid footnotes error", picoloom.ErrInvalidFootnotes, ExitUsage},
+		{"returns usage exit code for footnote layout error", picoloom.ErrFootnoteLayout, ExitUsage},
+		{"returns io exit code for missing bibliography file", fmt.Errorf("%w: %w", picoloom.ErrCitation, os.ErrNotExist), ExitIO},
+		{"returns io exit code for missing included file", fmt.Errorf("%w: reading x.md: %w", picoloom.ErrInclude, os.ErrNotExist), ExitIO},
+		{"returns usage exit code for invalid frontmatter error", picoloom.ErrInvalidFrontmatter, ExitUsage},
 		{"returns usage exit code for invalid asset path error", picoloom.ErrInvalidAssetPath, ExitUsage},
 		{"returns usage exit code for unsupported shell error", ErrUnsupportedShell, ExitUsage},
 		{"returns usage exit code for config init busy error", ErrConfigInitBusy, ExitUsage},
@@ -70,6 +89,14 @@ func TestExitCodeFor(t *testing.T) {
 
 		// General errors (exit 1)
 		{"returns general exit code for unknown error", errors.New("something unexpected"), ExitGeneral},
+		{"returns general exit code for pdf post-processing error", picoloom.ErrPDFPostProcess, ExitGeneral},
+		{"returns general exit code for PDF/A conformance error", picoloom.ErrPDFAConformance, ExitGeneral},
+		{"returns usage exit code for invalid encryption error", picoloom.ErrInvalidEncryption, ExitUsage},
+		{"returns general exit code for PDF encryption error", picoloom.ErrPDFEncryption, ExitGeneral},
+		{"returns usage exit code for invalid digital signature error", picoloom.ErrInvalidDigitalSignature, ExitUsage},
+		{"returns usage exit code for signing credentials error", picoloom.ErrSigningCredentials, ExitUsage},
+		{"returns IO exit code for missing signing certificate", fmt.Errorf("%w: %w", picoloom.ErrSigningCredentials, os.ErrNotExist), ExitIO},
+		{"returns general exit code for PDF signing error", picoloom.ErrPDFSigning, ExitGeneral},
 		{"returns general exit code for wrapped unknown error", fmt.Errorf("context: %w", errors.New("unknown")), ExitGeneral},
 	}
 
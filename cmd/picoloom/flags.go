@@ -40,13 +40,22 @@ type documentFlags struct {
 	documentType string
 	documentID   string
 	description  string
+	keywords     []string
 }
 
 // pageFlags holds page layout flags.
 type pageFlags struct {
-	size        string
-	orientation string
-	margin      float64
+	size         string
+	orientation  string
+	margin       float64
+	width        string
+	height       string
+	marginTop    string
+	marginRight  string
+	marginBottom string
+	marginLeft   string
+	marginInner  string
+	marginOuter  string
 }
 
 // footerFlags holds footer-related flags.
@@ -58,6 +67,60 @@ type footerFlags struct {
 	disabled       bool
 }
 
+// headerFlags holds running page header flags.
+type headerFlags struct {
+	left     string
+	center   string
+	right    string
+	disabled bool
+}
+
+// pageNumberingFlags holds footer/header page numbering flags.
+type pageNumberingFlags struct {
+	format      string
+	startAt     int
+	frontMatter string
+	disabled    bool
+}
+
+// diagramFlags holds Mermaid diagram flags.
+type diagramFlags struct {
+	theme    string
+	disabled bool
+}
+
+// mathFlags holds math rendering flags.
+type mathFlags struct {
+	enabled   bool
+	numbering bool
+	disabled  bool
+}
+
+// rawHTMLFlags holds raw HTML flags.
+type rawHTMLFlags struct {
+	enabled  bool
+	disabled bool
+}
+
+// interpolationFlags holds variable interpolation flags.
+type interpolationFlags struct {
+	enabled  bool
+	disabled bool
+}
+
+// citationFlags holds citation and bibliography flags.
+type citationFlags struct {
+	bibliography string
+	style        string
+	disabled     bool
+}
+
+// footnoteFlags holds footnote placement flags.
+type footnoteFlags struct {
+	placement string
+	numbering string
+}
+
 // coverFlags holds cover page flags.
 type coverFlags struct {
 	logo           string
@@ -73,12 +136,57 @@ type signatureFlags struct {
 
 // tocFlags holds table of contents flags.
 type tocFlags struct {
+	title       string
+	minDepth    int
+	maxDepth    int
+	pageNumbers bool
+	disabled    bool
+}
+
+// indexFlags holds back-of-book index flags.
+type indexFlags struct {
+	enabled  bool
 	title    string
+	disabled bool
+}
+
+// listFlags holds list of figures or list of tables flags.
+type listFlags struct {
+	enabled     bool
+	title       string
+	pageNumbers bool
+	disabled    bool
+}
+
+// outlineFlags holds PDF outline (bookmarks) flags.
+type outlineFlags struct {
+	enabled  bool
 	minDepth int
 	maxDepth int
 	disabled bool
 }
 
+// securityFlags holds PDF password protection flags. Passwords are read from
+// config or environment only, so they never appear in shell history.
+type securityFlags struct {
+	enabled     bool
+	allowPrint  bool
+	allowCopy   bool
+	allowModify bool
+	disabled    bool
+}
+
+// signingFlags holds digital signature flags. The key password is read from
+// config or environment only, so it never appears in shell history.
+type signingFlags struct {
+	certFile string
+	keyFile  string
+	reason   string
+	visible  bool
+	tsaURL   string
+	disabled bool
+}
+
 // watermarkFlags holds watermark-related flags.
 type watermarkFlags struct {
 	text     string
@@ -116,15 +224,31 @@ type convertFlags struct {
 	output     string
 	workers    int
 	timeout    string
+	merge      bool // Combine all inputs into one PDF
+	pdfa       bool // Produce PDF/A-2b
 	author     authorFlags
 	document   documentFlags
 	page       pageFlags
 	footer     footerFlags
+	header     headerFlags
+	numbering  pageNumberingFlags
+	diagrams   diagramFlags
+	math       mathFlags
+	rawHTML    rawHTMLFlags
+	interp     interpolationFlags
+	citations  citationFlags
+	footnotes  footnoteFlags
 	cover      coverFlags
 	signature  signatureFlags
 	toc        tocFlags
+	index      indexFlags
+	figures    listFlags
+	tables     listFlags
+	outline    outlineFlags
 	watermark  watermarkFlags
 	pageBreaks pageBreakFlags
+	security   securityFlags
+	signing    signingFlags
 	assets     assetFlags
 	outputMode outputFlags
 }
@@ -158,13 +282,22 @@ func addDocumentFlags(fs *flag.FlagSet, f *documentFlags) {
 	fs.StringVar(&f.documentType, "doc-type", "", "document type")
 	fs.StringVar(&f.documentID, "doc-id", "", "document ID/reference")
 	fs.StringVar(&f.description, "doc-desc", "", "document description")
+	fs.StringSliceVar(&f.keywords, "doc-keywords", nil, "PDF keywords (comma-separated)")
 }
 
 // addPageFlags adds page layout flags to a FlagSet.
 func addPageFlags(fs *flag.FlagSet, f *pageFlags) {
-	fs.StringVarP(&f.size, "page-size", "p", "", "page size: letter, a4, legal")
+	fs.StringVarP(&f.size, "page-size", "p", "", "page size: letter, a4, legal, a3, a5, b5, executive, tabloid")
 	fs.StringVar(&f.orientation, "orientation", "", "page orientation: portrait, landscape")
 	fs.Float64Var(&f.margin, "margin", 0, "page margin in inches (0.25-3.0)")
+	fs.StringVar(&f.width, "page-width", "", "custom page width, e.g. 210mm (with --page-height)")
+	fs.StringVar(&f.height, "page-height", "", "custom page height, e.g. 297mm (with --page-width)")
+	fs.StringVar(&f.marginTop, "margin-top", "", "top margin, e.g. 20mm (overrides --margin)")
+	fs.StringVar(&f.marginRight, "margin-right", "", "right margin, e.g. 20mm (overrides --margin)")
+	fs.StringVar(&f.marginBottom, "margin-bottom", "", "bottom margin, e.g. 20mm (overrides --margin)")
+	fs.StringVar(&f.marginLeft, "margin-left", "", "left margin, e.g. 20mm (overrides --margin)")
+	fs.StringVar(&f.marginInner, "margin-inner", "", "binding-side margin, mirrored on left-hand pages")
+	fs.StringVar(&f.marginOuter, "margin-outer", "", "outer margin, mirrored on left-hand pages")
 }
 
 // addFooterFlags adds footer flags to a FlagSet.
@@ -176,6 +309,60 @@ func addFooterFlags(fs *flag.FlagSet, f *footerFlags) {
 	fs.BoolVar(&f.disabled, "no-footer", false, "disable footer")
 }
 
+// addHeaderFlags adds running header flags to a FlagSet.
+func addHeaderFlags(fs *flag.FlagSet, f *headerFlags) {
+	fs.StringVar(&f.left, "header-left", "", "left header text (placeholders: {title}, {pageNumber}, ...)")
+	fs.StringVar(&f.center, "header-center", "", "center header text")
+	fs.StringVar(&f.right, "header-right", "", "right header text")
+	fs.BoolVar(&f.disabled, "no-header", false, "disable running header")
+}
+
+// addPageNumberingFlags adds page numbering flags to a FlagSet.
+func addPageNumberingFlags(fs *flag.FlagSet, f *pageNumberingFlags) {
+	fs.StringVar(&f.format, "page-number-format", "", "page number format: slash, page-of, plain")
+	fs.IntVar(&f.startAt, "page-number-start", 0, "number of the first numbered page (default: 1)")
+	fs.StringVar(&f.frontMatter, "front-matter", "", "cover/TOC numbering: continue, skip, roman")
+	fs.BoolVar(&f.disabled, "no-page-numbering", false, "use Chrome's page numbers")
+}
+
+// addDiagramFlags adds Mermaid diagram flags to a FlagSet.
+func addDiagramFlags(fs *flag.FlagSet, f *diagramFlags) {
+	fs.StringVar(&f.theme, "diagram-theme", "", "mermaid theme: default, neutral, dark, forest, base")
+	fs.BoolVar(&f.disabled, "no-diagrams", false, "leave mermaid blocks as code")
+}
+
+// addMathFlags adds math rendering flags to a FlagSet.
+func addMathFlags(fs *flag.FlagSet, f *mathFlags) {
+	fs.BoolVar(&f.enabled, "math", false, "render $...$ and $$...$$ TeX with KaTeX")
+	fs.BoolVar(&f.numbering, "math-numbering", false, "number display equations")
+	fs.BoolVar(&f.disabled, "no-math", false, "leave TeX as written")
+}
+
+// addRawHTMLFlags adds raw HTML flags to a FlagSet.
+func addRawHTMLFlags(fs *flag.FlagSet, f *rawHTMLFlags) {
+	fs.BoolVar(&f.enabled, "raw-html", false, "keep HTML written in the Markdown, sanitized")
+	fs.BoolVar(&f.disabled, "no-raw-html", false, "omit HTML written in the Markdown")
+}
+
+// addInterpolationFlags adds variable interpolation flags to a FlagSet.
+func addInterpolationFlags(fs *flag.FlagSet, f *interpolationFlags) {
+	fs.BoolVar(&f.enabled, "interpolate", false, "substitute {{ .Document.Version }} and other variables in the body")
+	fs.BoolVar(&f.disabled, "no-interpolate", false, "leave {{ ... }} as written")
+}
+
+// addCitationFlags adds citation flags to a FlagSet.
+func addCitationFlags(fs *flag.FlagSet, f *citationFlags) {
+	fs.StringVar(&f.bibliography, "bibliography", "", "BibTeX (.bib) or CSL-JSON (.json) file for [@key] citations")
+	fs.StringVar(&f.style, "citation-style", "", "citation style: author-date, numeric")
+	fs.BoolVar(&f.disabled, "no-bibliography", false, "leave citations as written")
+}
+
+// addFootnoteFlags adds footnote placement flags to a FlagSet.
+func addFootnoteFlags(fs *flag.FlagSet, f *footnoteFlags) {
+	fs.StringVar(&f.placement, "footnotes", "", "footnote placement: end, page")
+	fs.StringVar(&f.numbering, "footnote-numbering", "", "footnote numbering: document, page")
+}
+
 // addCoverFlags adds cover page flags to a FlagSet.
 func addCoverFlags(fs *flag.FlagSet, f *coverFlags) {
 	fs.StringVar(&f.logo, "cover-logo", "", "cover page logo path or URL")
@@ -194,9 +381,34 @@ func addTOCFlags(fs *flag.FlagSet, f *tocFlags) {
 	fs.StringVar(&f.title, "toc-title", "", "table of contents heading")
 	fs.IntVar(&f.minDepth, "toc-min-depth", 0, "min heading depth for TOC (1-6, default: 2)")
 	fs.IntVar(&f.maxDepth, "toc-max-depth", 0, "max heading depth for TOC (1-6, default: 3)")
+	fs.BoolVar(&f.pageNumbers, "toc-page-numbers", false, "show page numbers in TOC")
 	fs.BoolVar(&f.disabled, "no-toc", false, "disable table of contents")
 }
 
+// addIndexFlags adds index flags to a FlagSet.
+func addIndexFlags(fs *flag.FlagSet, f *indexFlags) {
+	fs.BoolVar(&f.enabled, "index", false, "list [[idx:term]] terms in an index with page numbers")
+	fs.StringVar(&f.title, "index-title", "", "index heading (implies --index)")
+	fs.BoolVar(&f.disabled, "no-index", false, "disable the index")
+}
+
+// addListFlags adds list of figures or tables flags to a FlagSet, named
+// after prefix ("lof" or "lot"); what names the listed elements.
+func addListFlags(fs *flag.FlagSet, f *listFlags, prefix, what string) {
+	fs.BoolVar(&f.enabled, prefix, false, "list captioned "+what+" after the table of contents")
+	fs.StringVar(&f.title, prefix+"-title", "", "list of "+what+" heading (implies --"+prefix+")")
+	fs.BoolVar(&f.pageNumbers, prefix+"-page-numbers", false, "show page numbers in the list of "+what+" (implies --"+prefix+")")
+	fs.BoolVar(&f.disabled, "no-"+prefix, false, "disable the list of "+what)
+}
+
+// addOutlineFlags adds PDF outline flags to a FlagSet.
+func addOutlineFlags(fs *flag.FlagSet, f *outlineFlags) {
+	fs.BoolVar(&f.enabled, "outline", false, "generate PDF bookmarks from headings")
+	fs.IntVar(&f.minDepth, "outline-min-depth", 0, "min heading depth for bookmarks (1-6, default: 1)")
+	fs.IntVar(&f.maxDepth, "outline-max-depth", 0, "max heading depth for bookmarks (1-6, default: 3)")
+	fs.BoolVar(&f.disabled, "no-outline", false, "disable PDF bookmarks")
+}
+
 // addWatermarkFlags adds watermark flags to a FlagSet.
 func addWatermarkFlags(fs *flag.FlagSet, f *watermarkFlags) {
 	fs.StringVar(&f.text, "wm-text", "", "watermark text")
@@ -214,6 +426,25 @@ func addPageBreakFlags(fs *flag.FlagSet, f *pageBreakFlags) {
 	fs.BoolVar(&f.disabled, "no-page-breaks", false, "disable page break features")
 }
 
+// addSecurityFlags adds PDF password protection flags to a FlagSet.
+func addSecurityFlags(fs *flag.FlagSet, f *securityFlags) {
+	fs.BoolVar(&f.enabled, "encrypt", false, "password-protect the PDF (passwords from config or env)")
+	fs.BoolVar(&f.allowPrint, "allow-print", false, "allow printing an encrypted PDF")
+	fs.BoolVar(&f.allowCopy, "allow-copy", false, "allow copying from an encrypted PDF")
+	fs.BoolVar(&f.allowModify, "allow-modify", false, "allow editing an encrypted PDF")
+	fs.BoolVar(&f.disabled, "no-encrypt", false, "disable password protection")
+}
+
+// addSigningFlags adds digital signature flags to a FlagSet.
+func addSigningFlags(fs *flag.FlagSet, f *signingFlags) {
+	fs.StringVar(&f.certFile, "sign-cert", "", "sign the PDF with this PEM or PKCS#12 certificate")
+	fs.StringVar(&f.keyFile, "sign-key", "", "PEM private key (default: from --sign-cert)")
+	fs.StringVar(&f.reason, "sign-reason", "", "reason for signing")
+	fs.BoolVar(&f.visible, "sign-visible", false, "show the signature field over the signature block")
+	fs.StringVar(&f.tsaURL, "tsa-url", "", "RFC 3161 timestamp server URL")
+	fs.BoolVar(&f.disabled, "no-sign", false, "disable digital signature")
+}
+
 // addAssetFlags adds asset-related flags to a FlagSet.
 func addAssetFlags(fs *flag.FlagSet, f *assetFlags) {
 	fs.StringVar(&f.style, "style", "", "CSS style name or file path")
@@ -237,6 +468,8 @@ func parseConvertFlags(args []string) (*convertFlags, []string, error) {
 	fs.StringVarP(&f.output, "output", "o", "", "output file or directory")
 	fs.IntVarP(&f.workers, "workers", "w", 0, "parallel workers (0 = auto)")
 	fs.StringVarP(&f.timeout, "timeout", "t", "", "PDF generation timeout (e.g., 30s, 2m)")
+	fs.BoolVar(&f.merge, "merge", false, "combine all inputs into one PDF")
+	fs.BoolVar(&f.pdfa, "pdfa", false, "produce PDF/A-2b for archiving")
 
 	// Flag groups
 	addCommonFlags(fs, &f.common)
@@ -244,11 +477,25 @@ func parseConvertFlags(args []string) (*convertFlags, []string, error) {
 	addDocumentFlags(fs, &f.document)
 	addPageFlags(fs, &f.page)
 	addFooterFlags(fs, &f.footer)
+	addHeaderFlags(fs, &f.header)
+	addPageNumberingFlags(fs, &f.numbering)
+	addDiagramFlags(fs, &f.diagrams)
+	addMathFlags(fs, &f.math)
+	addRawHTMLFlags(fs, &f.rawHTML)
+	addInterpolationFlags(fs, &f.interp)
+	addCitationFlags(fs, &f.citations)
+	addFootnoteFlags(fs, &f.footnotes)
 	addCoverFlags(fs, &f.cover)
 	addSignatureFlags(fs, &f.signature)
 	addTOCFlags(fs, &f.toc)
+	addIndexFlags(fs, &f.index)
+	addListFlags(fs, &f.figures, "lof", "figures")
+	addListFlags(fs, &f.tables, "lot", "tables")
+	addOutlineFlags(fs, &f.outline)
 	addWatermarkFlags(fs, &f.watermark)
 	addPageBreakFlags(fs, &f.pageBreaks)
+	addSecurityFlags(fs, &f.security)
+	addSigningFlags(fs, &f.signing)
 	addAssetFlags(fs, &f.assets)
 	addOutputFlags(fs, &f.outputMode)
 
@@ -18,6 +18,8 @@ func printUsageFor(w io.Writer, cliName string) {
 	fmt.Fprintln(w, "  convert      Convert markdown files to PDF")
 	fmt.Fprintln(w, "  config       Manage configuration files")
 	fmt.Fprintln(w, "  doctor       Check system configuration")
+	fmt.Fprintln(w, "  verify-signature")
+	fmt.Fprintln(w, "               Check the digital signatures of a PDF")
 	fmt.Fprintln(w, "  completion   Generate shell completion script")
 	fmt.Fprintln(w, "  version      Show version information")
 	fmt.Fprintln(w, "  help         Show help for a command")
@@ -62,13 +64,20 @@ var convertUsageLines = []string{
 	"    # A4 landscape with watermark",
 	"    md2pdf convert -p a4 --orientation landscape --wm-text DRAFT doc.md",
 	"",
+	"    # Merge chapters into one book (files in name order)",
+	"    md2pdf convert --merge ./handbook/ -o handbook.pdf",
+	"",
 	"Arguments:",
 	"  input    Markdown file or directory (optional if config has input.defaultDir)",
+	"           With --merge, several inputs may be given, in chapter order",
 	"",
 	"Input/Output:",
 	"  -o, --output <path>       Output file or directory",
 	"  -c, --config <name>       Config file name or path",
 	"  -w, --workers <n>         Parallel workers (0 = auto)",
+	"      --merge               Combine all inputs into one PDF",
+	"                            (one cover and TOC, chapter links become anchors)",
+	"      --pdfa                Produce PDF/A-2b for archiving",
 	"  -t, --timeout <duration>  PDF generation timeout (default: 30s)",
 	"                            Examples: 30s, 2m, 1m30s",
 	"",
@@ -94,11 +103,19 @@ var convertUsageLines = []string{
 	"      --doc-type <s>        Document type",
 	"      --doc-id <s>          Document ID/reference",
 	"      --doc-desc <s>        Document description",
+	"      --doc-keywords <s>    PDF keywords (comma-separated)",
 	"",
 	"Page:",
-	"  -p, --page-size <s>       letter, a4, legal (default: letter)",
+	"  -p, --page-size <s>       letter, a4, legal, a3, a5, b5, executive, tabloid",
+	"                            (default: letter)",
+	"      --page-width <len>    Custom width, e.g. 210mm (with --page-height)",
+	"      --page-height <len>   Custom height, e.g. 297mm (with --page-width)",
 	"      --orientation <s>     portrait, landscape (default: portrait)",
 	"      --margin <f>          Margin in inches (default: 0.5)",
+	"      --margin-top <len>    Top margin; also --margin-right, --margin-bottom,",
+	"                            --margin-left (lengths in mm, cm, in, or pt)",
+	"      --margin-inner <len>  Binding-side margin, mirrored on left-hand pages",
+	"      --margin-outer <len>  Outer margin, mirrored on left-hand pages",
 	"",
 	"Footer:",
 	"      --footer-position <s> left, center, right (default: right)",
@@ -107,6 +124,51 @@ var convertUsageLines = []string{
 	"      --footer-doc-id       Show document ID in footer",
 	"      --no-footer           Disable footer",
 	"",
+	"Header:",
+	"      --header-left <s>     Left header text",
+	"      --header-center <s>   Center header text",
+	"      --header-right <s>    Right header text",
+	"                            Placeholders: {title}, {version}, {documentID},",
+	"                            {date}, {pageNumber}, {totalPages}",
+	"      --no-header           Disable running header",
+	"",
+	"Page Numbering:",
+	"      --page-number-format <s> slash (3/12), page-of (Page 3 of 12), plain",
+	"      --page-number-start <n> Number of the first numbered page (default: 1)",
+	"      --front-matter <s>    Cover/TOC pages: continue, skip, roman",
+	"                            (default: continue)",
+	"      --no-page-numbering   Use Chrome's page numbers",
+	"",
+	"Diagrams:",
+	"      --diagram-theme <s>   Render ```mermaid blocks: default, neutral, dark,",
+	"                            forest, base",
+	"      --no-diagrams         Leave mermaid blocks as code",
+	"",
+	"Math:",
+	"      --math                Render $...$ and $$...$$ TeX with KaTeX",
+	"      --math-numbering      Number display equations (implies --math)",
+	"      --no-math             Leave TeX as written",
+	"",
+	"Raw HTML:",
+	"      --raw-html            Keep HTML written in the Markdown, sanitized",
+	"      --no-raw-html         Omit HTML written in the Markdown",
+	"",
+	"Variables:",
+	"      --interpolate         Substitute {{ .Document.Version }}, {{ .Author.Name }}",
+	"                            and {{ .Vars.name }} in the body",
+	"      --no-interpolate      Leave {{ ... }} as written",
+	"",
+	"Citations:",
+	"      --bibliography <path> Format [@key] citations from a .bib or CSL-JSON file",
+	"      --citation-style <s>  author-date (default) or numeric",
+	"      --no-bibliography     Leave citations as written",
+	"",
+	"Footnotes:",
+	"      --footnotes <s>       end (default) or page: at the foot of the page",
+	"                            (renders the PDF two or three times)",
+	"      --footnote-numbering <s>",
+	"                            document (default) or page: restart on every page",
+	"",
 	"Cover:",
 	"      --cover-logo <path>   Logo path or URL",
 	"      --cover-dept          Show author department on cover",
@@ -121,8 +183,31 @@ var convertUsageLines = []string{
 	"      --toc-min-depth <n>   Min heading depth (1-6, default: 2)",
 	"                            1=H1, 2=H2, etc. Use 2 to skip title",
 	"      --toc-max-depth <n>   Max heading depth (1-6, default: 3)",
+	"      --toc-page-numbers    Show page numbers (renders the PDF twice)",
 	"      --no-toc              Disable table of contents",
 	"",
+	"Lists of Figures and Tables:",
+	"      --lof                 List captioned figures after the TOC",
+	"      --lof-title <s>       Heading (default: List of Figures)",
+	"      --lof-page-numbers    Show page numbers (renders the PDF twice)",
+	"      --no-lof              Disable the list of figures",
+	"      --lot                 List captioned tables after the TOC",
+	"      --lot-title <s>       Heading (default: List of Tables)",
+	"      --lot-page-numbers    Show page numbers (renders the PDF twice)",
+	"      --no-lot              Disable the list of tables",
+	"",
+	"Index:",
+	"      --index               List [[idx:term]] terms with page numbers",
+	"                            (renders the PDF twice)",
+	"      --index-title <s>     Index heading (default: Index)",
+	"      --no-index            Disable the index",
+	"",
+	"Outline:",
+	"      --outline             Generate PDF bookmarks from headings",
+	"      --outline-min-depth <n> Min heading depth (1-6, default: 1)",
+	"      --outline-max-depth <n> Max heading depth (1-6, default: 3)",
+	"      --no-outline          Disable PDF bookmarks",
+	"",
 	"Watermark:",
 	"      --wm-text <s>         Watermark text",
 	"      --wm-color <s>        Color hex (default: #888888)",
@@ -136,6 +221,24 @@ var convertUsageLines = []string{
 	"      --widows <n>          Min lines at page top (default: 2)",
 	"      --no-page-breaks      Disable page break features",
 	"",
+	"Security:",
+	"      --encrypt             Password-protect the PDF (AES-256)",
+	"                            Passwords: security config or",
+	"                            PICOLOOM_USER_PASSWORD / PICOLOOM_OWNER_PASSWORD",
+	"      --allow-print         Allow printing",
+	"      --allow-copy          Allow copying text and images",
+	"      --allow-modify        Allow editing and annotating",
+	"      --no-encrypt          Disable password protection",
+	"",
+	"Digital Signature:",
+	"      --sign-cert <path>    Sign with a PEM or PKCS#12 (.p12, .pfx) certificate",
+	"                            Password: signing config or PICOLOOM_SIGN_PASSWORD",
+	"      --sign-key <path>     PEM private key (default: from --sign-cert)",
+	"      --sign-reason <s>     Reason for signing",
+	"      --sign-visible        Show the signature field over the signature block",
+	"      --tsa-url <url>       RFC 3161 timestamp server URL",
+	"      --no-sign             Disable digital signature",
+	"",
 	"Assets & Styling:",
 	"      --style <name|path>   CSS style name or file path (default: default)",
 	"                            Name: uses embedded or custom asset",
@@ -208,6 +311,8 @@ func runHelp(args []string, env *Environment) {
 		printConfigUsageFor(env.Stdout, cliName)
 	case "doctor":
 		printDoctorUsageFor(env.Stdout, cliName)
+	case "verify-signature":
+		printVerifySignatureUsageFor(env.Stdout, cliName)
 	case "completion":
 		printCompletionUsageFor(env.Stdout, cliName)
 	case "version":
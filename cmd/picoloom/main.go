@@ -54,6 +54,11 @@ func runMain(args []string, env *Environment) int {
 		}
 	case "doctor":
 		return runDoctorCmd(cmdArgs, env)
+	case "verify-signature":
+		if err := runVerifySignatureCmd(cmdArgs, env); err != nil {
+			fmt.Fprintln(env.Stderr, err)
+			return exitCodeFor(err)
+		}
 	case "version":
 		fmt.Fprintf(env.Stdout, "%s %s\n", envCLIName(env), Version)
 	case "help":
@@ -75,7 +80,7 @@ func runMain(args []string, env *Environment) int {
 // isCommand checks if a string is a known command.
 func isCommand(s string) bool {
 	switch s {
-	case "convert", "config", "doctor", "version", "help", "completion":
+	case "convert", "config", "doctor", "verify-signature", "version", "help", "completion":
 		return true
 	}
 	return false
@@ -118,7 +123,9 @@ func runConvertCmd(args []string, env *Environment) error {
 		return err
 	}
 
-	converterPool := createConverterPool(flags, env, templateSet, timeout)
+	// PDF/A is a converter option, so it is resolved before the pool exists.
+	pdfa := flags.pdfa || env.Config.Output.PDFA
+	converterPool := createConverterPool(flags, env, templateSet, timeout, pdfa)
 	defer func() { _ = converterPool.Close() }()
 
 	pool := &poolAdapter{pool: converterPool}
@@ -232,20 +239,23 @@ func resolveTemplateSetForRun(flags *convertFlags, env *Environment) (*picoloom.
 
 // createConverterPool keeps pool construction together so sizing/options/logging
 // evolve in one place without widening runConvertCmd.
-func createConverterPool(flags *convertFlags, env *Environment, templateSet *picoloom.TemplateSet, timeout time.Duration) *picoloom.ConverterPool {
+func createConverterPool(flags *convertFlags, env *Environment, templateSet *picoloom.TemplateSet, timeout time.Duration, pdfa bool) *picoloom.ConverterPool {
 	poolSize := picoloom.ResolvePoolSize(flags.workers)
 	if flags.common.verbose {
 		fmt.Fprintf(env.Stderr, "Pool size: %d\n", poolSize)
 		if timeout > 0 {
 			fmt.Fprintf(env.Stderr, "Timeout: %v\n", timeout)
 		}
+		if pdfa {
+			fmt.Fprintln(env.Stderr, "Output: PDF/A-2b")
+		}
 	}
-	return picoloom.NewConverterPool(poolSize, buildPoolOptions(env.AssetLoader, templateSet, timeout)...)
+	return picoloom.NewConverterPool(poolSize, buildPoolOptions(env.AssetLoader, templateSet, timeout, pdfa)...)
 }
 
 // buildPoolOptions prevents option assembly duplication and preserves option
 // ordering assumptions in a single helper.
-func buildPoolOptions(loader picoloom.AssetLoader, templateSet *picoloom.TemplateSet, timeout time.Duration) []picoloom.Option {
+func buildPoolOptions(loader picoloom.AssetLoader, templateSet *picoloom.TemplateSet, timeout time.Duration, pdfa bool) []picoloom.Option {
 	opts := []picoloom.Option{
 		picoloom.WithAssetLoader(loader),
 		picoloom.WithTemplateSet(templateSet),
@@ -253,6 +263,9 @@ func buildPoolOptions(loader picoloom.AssetLoader, templateSet *picoloom.Templat
 	if timeout > 0 {
 		opts = append(opts, picoloom.WithTimeout(timeout))
 	}
+	if pdfa {
+		opts = append(opts, picoloom.WithPDFA())
+	}
 	return opts
 }
 
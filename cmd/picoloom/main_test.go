@@ -32,6 +32,10 @@ func (w *wrongTypeConverter) Convert(_ context.Context, _ picoloom.Input) (*pico
 	return &picoloom.ConvertResult{PDF: []byte("%PDF-1.4 mock")}, nil
 }
 
+func (w *wrongTypeConverter) ConvertMany(ctx context.Context, input picoloom.Input, _ []picoloom.Chapter) (*picoloom.ConvertResult, error) {
+	return w.Convert(ctx, input)
+}
+
 // ---------------------------------------------------------------------------
 // TestPoolAdapter_Release_WrongType - Pool adapter defensive release
 // ---------------------------------------------------------------------------
@@ -121,6 +125,7 @@ func TestIsCommand(t *testing.T) {
 		{"convert", true},
 		{"config", true},
 		{"doctor", true},
+		{"verify-signature", true},
 		{"completion", true},
 		{"version", true},
 		{"help", true},
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	picoloom "github.com/alnah/picoloom/v2"
+	flag "github.com/spf13/pflag"
+)
+
+var (
+	// ErrVerifyCommandUsage groups verify-signature command-shape errors.
+	ErrVerifyCommandUsage = errors.New("invalid verify-signature usage")
+	// ErrNoSignature reports a PDF without digital signatures.
+	ErrNoSignature = errors.New("PDF has no digital signature")
+	// ErrSignatureNotValid reports a signature that is altered, untrusted or
+	// does not cover the whole document.
+	ErrSignatureNotValid = errors.New("digital signature not valid")
+)
+
+// verifySignatureFlags holds verify-signature command flags.
+type verifySignatureFlags struct {
+	caFiles []string // PEM files of trusted roots; system roots if empty
+}
+
+// buildVerifySignatureFlagSet defines the verify-signature flags, shared
+// with shell completion.
+func buildVerifySignatureFlagSet(f *verifySignatureFlags) *flag.FlagSet {
+	fs := flag.NewFlagSet("verify-signature", flag.ContinueOnError)
+	fs.StringArrayVar(&f.caFiles, "ca", nil, "trusted root certificates (PEM), instead of the system roots")
+	return fs
+}
+
+// parseVerifySignatureFlags returns the flags and the PDF path.
+func parseVerifySignatureFlags(args []string, stderr io.Writer) (verifySignatureFlags, string, error) {
+	var f verifySignatureFlags
+
+	fs := buildVerifySignatureFlagSet(&f)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		printVerifySignatureUsage(stderr)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return verifySignatureFlags{}, "", err
+	}
+	if fs.NArg() != 1 {
+		return verifySignatureFlags{}, "", fmt.Errorf("%w: expected one PDF file, got %d arguments", ErrVerifyCommandUsage, fs.NArg())
+	}
+	return f, fs.Arg(0), nil
+}
+
+// runVerifySignatureCmd prints a report for each signature of a PDF.
+// Succeeds only when every signature is intact, trusted and covers the
+// whole document.
+func runVerifySignatureCmd(args []string, env *Environment) error {
+	flags, pdfPath, err := parseVerifySignatureFlags(args, env.Stderr)
+	if err != nil {
+		return err
+	}
+
+	roots, err := loadRootCertificates(flags.caFiles)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(pdfPath) // #nosec G304 -- user-provided path
+	if err != nil {
+		return err
+	}
+	reports, err := picoloom.VerifyPDFSignatures(data, roots)
+	if err != nil {
+		return err
+	}
+	if len(reports) == 0 {
+		return fmt.Errorf("%w: %s", ErrNoSignature, pdfPath)
+	}
+
+	printSignatureReports(env.Stdout, pdfPath, reports)
+
+	invalid := 0
+	for i := range reports {
+		if !reports[i].Valid() {
+			invalid++
+		}
+	}
+	if invalid > 0 {
+		return fmt.Errorf("%w: %d of %d signatures in %s", ErrSignatureNotValid, invalid, len(reports), pdfPath)
+	}
+	return nil
+}
+
+// loadRootCertificates reads PEM certificates into a pool.
+// Returns nil, meaning the system roots, when files is empty.
+func loadRootCertificates(files []string) (*x509.CertPool, error) {
+	if len(files) == 0 {
+		return nil, nil
+	}
+	pool := x509.NewCertPool()
+	for _, path := range files {
+		data, err := os.ReadFile(path) // #nosec G304 -- user-provided path
+		if err != nil {
+			return nil, err
+		}
+		found := false
+		for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+			if block.Type != "CERTIFICATE" {
+				continue
+			}
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s: %w", ErrVerifyCommandUsage, path, err)
+			}
+			pool.AddCert(cert)
+			found = true
+		}
+		if !found {
+			return nil, fmt.Errorf("%w: %s: no PEM certificate", ErrVerifyCommandUsage, path)
+		}
+	}
+	return pool, nil
+}
+
+// printSignatureReports prints one section per signature, in the
+// [OK]/[ERROR] style of the doctor command.
+func printSignatureReports(w io.Writer, pdfPath string, reports []picoloom.SignatureReport) {
+	fmt.Fprintf(w, "%s: %d signature(s)\n", pdfPath, len(reports))
+	for _, r := range reports {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, r.Field)
+
+		if r.Err != nil {
+			fmt.Fprintf(w, "  [ERROR] Integrity: %v\n", r.Err)
+		} else {
+			fmt.Fprintln(w, "  [OK] Integrity: signed content unchanged")
+		}
+		if r.Signer != nil {
+			fmt.Fprintf(w, "  [OK] Signer: %s\n", r.Signer.Subject)
+		}
+		if r.Err == nil {
+			if r.TrustErr != nil {
+				fmt.Fprintf(w, "  [ERROR] Trust: %v\n", r.TrustErr)
+			} else {
+				fmt.Fprintln(w, "  [OK] Trust: certificate chains to a trusted root")
+			}
+		}
+		if r.CoversDocument {
+			fmt.Fprintln(w, "  [OK] Coverage: whole document")
+		} else {
+			fmt.Fprintln(w, "  [ERROR] Coverage: document modified after signing")
+		}
+		if !r.Timestamp.IsZero() {
+			fmt.Fprintf(w, "  [OK] Timestamp: %s\n", r.Timestamp.UTC().Format(time.RFC3339))
+		}
+
+		details := []struct{ label, value string }{
+			{"Name", r.SignerName},
+			{"Reason", r.Reason},
+			{"Location", r.Location},
+		}
+		if !r.SigningTime.IsZero() {
+			details = append(details, struct{ label, value string }{"Signed", r.SigningTime.Format(time.RFC3339)})
+		}
+		for _, d := range details {
+			if strings.TrimSpace(d.value) != "" {
+				fmt.Fprintf(w, "  %s: %s\n", d.label, d.value)
+			}
+		}
+	}
+}
+
+// printVerifySignatureUsage prints usage for the verify-signature command.
+func printVerifySignatureUsage(w io.Writer) {
+	printVerifySignatureUsageFor(w, canonicalCLIName)
+}
+
+func printVerifySignatureUsageFor(w io.Writer, cliName string) {
+	fmt.Fprintf(w, "Usage: %s verify-signature <file.pdf> [flags]\n", cliName)
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Check the digital signatures of a PDF.")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Flags:")
+	fmt.Fprintln(w, "      --ca <file>   Trusted root certificates (PEM), instead of the system roots")
+	fmt.Fprintln(w, "                    Repeat for several files")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Examples:")
+	fmt.Fprintf(w, "  %s verify-signature contract.pdf\n", cliName)
+	fmt.Fprintf(w, "  %s verify-signature --ca company-root.pem contract.pdf\n", cliName)
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Exit codes:")
+	fmt.Fprintln(w, "  0  Every signature is intact, trusted and covers the whole document")
+	fmt.Fprintln(w, "  1  No signature, or a signature is not valid")
+	fmt.Fprintln(w, "  2  Invalid flags or certificate file")
+	fmt.Fprintln(w, "  3  File not found, permission denied")
+}
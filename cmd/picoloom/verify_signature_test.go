@@ -0,0 +1,146 @@
+package main
+
+// Notes:
+// - Tests go through runMain to cover exit codes and output
+// - testdata/signed.pdf is a one-page PDF signed by the self-signed
+//   certificate testdata/signer.crt (see internal/pdfsign/testdata)
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// unsignedPDF returns a minimal valid PDF with a catalog and no pages.
+func unsignedPDF() []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offset := buf.Len()
+	buf.WriteString("1 0 obj\n<</Type /Catalog>>\nendobj\n")
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 2\n0000000000 65535 f \n%010d 00000 n \n", offset)
+	fmt.Fprintf(&buf, "trailer\n<</Size 2 /Root 1 0 R>>\nstartxref\n%d\n%%%%EOF\n", xref)
+	return buf.Bytes()
+}
+
+// ---------------------------------------------------------------------------
+// TestRunVerifySignatureCmd - Exit codes and report
+// ---------------------------------------------------------------------------
+
+func TestRunVerifySignatureCmd(t *testing.T) {
+	t.Parallel()
+
+	signed := filepath.Join("testdata", "signed.pdf")
+	ca := filepath.Join("testdata", "signer.crt")
+	dir := t.TempDir()
+
+	data, err := os.ReadFile(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	appended := filepath.Join(dir, "appended.pdf")
+	if err := os.WriteFile(appended, append(data, "% edit\n"...), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tampered := filepath.Join(dir, "tampered.pdf")
+	altered := bytes.Replace(data, []byte("/Reason (Approval)"), []byte("/Reason (Rejected)"), 1)
+	if bytes.Equal(altered, data) {
+		t.Fatal("testdata/signed.pdf has no /Reason (Approval) to tamper with")
+	}
+	if err := os.WriteFile(tampered, altered, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	unsigned := filepath.Join(dir, "unsigned.pdf")
+	if err := os.WriteFile(unsigned, unsignedPDF(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	notCert := filepath.Join(dir, "empty.pem")
+	if err := os.WriteFile(notCert, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantStdout []string
+		wantStderr string
+	}{
+		{
+			name:       "trusted signature",
+			args:       []string{"--ca", ca, signed},
+			wantCode:   ExitSuccess,
+			wantStdout: []string{"[OK] Integrity", "[OK] Trust", "[OK] Coverage", "Reason: Approval"},
+		},
+		{
+			name:       "untrusted signer",
+			args:       []string{signed},
+			wantCode:   ExitGeneral,
+			wantStdout: []string{"[OK] Integrity", "[ERROR] Trust"},
+			wantStderr: "digital signature not valid",
+		},
+		{
+			name:       "content appended after signing",
+			args:       []string{"--ca", ca, appended},
+			wantCode:   ExitGeneral,
+			wantStdout: []string{"[ERROR] Coverage"},
+			wantStderr: "digital signature not valid",
+		},
+		{
+			name:       "signed content modified",
+			args:       []string{"--ca", ca, tampered},
+			wantCode:   ExitGeneral,
+			wantStdout: []string{"[ERROR] Integrity"},
+			wantStderr: "digital signature not valid",
+		},
+		{
+			name:       "unsigned PDF",
+			args:       []string{unsigned},
+			wantCode:   ExitGeneral,
+			wantStderr: "no digital signature",
+		},
+		{
+			name:       "missing file",
+			args:       []string{filepath.Join(dir, "missing.pdf")},
+			wantCode:   ExitIO,
+			wantStderr: "no such file",
+		},
+		{
+			name:       "no argument",
+			args:       nil,
+			wantCode:   ExitUsage,
+			wantStderr: "expected one PDF file",
+		},
+		{
+			name:       "CA file without certificate",
+			args:       []string{"--ca", notCert, signed},
+			wantCode:   ExitUsage,
+			wantStderr: "no PEM certificate",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var stdout, stderr bytes.Buffer
+			env := &Environment{Stdout: &stdout, Stderr: &stderr}
+
+			code := runMain(append([]string{"picoloom", "verify-signature"}, tt.args...), env)
+			if code != tt.wantCode {
+				t.Errorf("runMain(verify-signature %v) = %d, want %d (stderr: %s)", tt.args, code, tt.wantCode, stderr.String())
+			}
+			for _, want := range tt.wantStdout {
+				if !strings.Contains(stdout.String(), want) {
+					t.Errorf("stdout = %q, want substring %q", stdout.String(), want)
+				}
+			}
+			if !strings.Contains(stderr.String(), tt.wantStderr) {
+				t.Errorf("stderr = %q, want substring %q", stderr.String(), tt.wantStderr)
+			}
+		})
+	}
+}
@@ -4,8 +4,13 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/alnah/picoloom/v2/internal/assets"
+	"github.com/alnah/picoloom/v2/internal/bibliography"
+	"github.com/alnah/picoloom/v2/internal/pdfedit"
 	"github.com/alnah/picoloom/v2/internal/pipeline"
 	"github.com/alnah/picoloom/v2/internal/styleinput"
 )
@@ -15,6 +20,7 @@ import (
 // catching signature mismatches before runtime.
 var (
 	_ pipeline.MarkdownPreprocessor = (*pipeline.CommonMarkPreprocessor)(nil)
+	_ pipeline.VariableInterpolator = (*pipeline.CommonMarkPreprocessor)(nil)
 	_ pipeline.HTMLConverter        = (*pipeline.GoldmarkConverter)(nil)
 	_ pipeline.CSSInjector          = (*pipeline.CSSInjection)(nil)
 	_ pipeline.CoverInjector        = (*pipeline.CoverInjection)(nil)
@@ -22,6 +28,8 @@ var (
 	_ pipeline.SignatureInjector    = (*pipeline.SignatureInjection)(nil)
 	_ pdfConverter                  = (*rodConverter)(nil)
 	_ pdfRenderer                   = (*rodRenderer)(nil)
+	_ pipeline.MermaidRenderer      = (*rodConverter)(nil)
+	_ pipeline.MathRenderer         = (*rodConverter)(nil)
 )
 
 // Converter orchestrates the markdown-to-PDF conversion pipeline.
@@ -32,11 +40,20 @@ type Converter struct {
 	publicAssetLoader AssetLoader        // public loader (from WithAssetLoader)
 	preprocessor      pipeline.MarkdownPreprocessor
 	htmlConverter     pipeline.HTMLConverter
+	rawHTMLConverter  pipeline.HTMLConverter // Used when Input.RawHTML is set
 	cssInjector       pipeline.CSSInjector
 	coverInjector     pipeline.CoverInjector
 	tocInjector       pipeline.TOCInjector
 	signatureInjector pipeline.SignatureInjector
+	footerTemplate    *pipeline.PageTemplate // nil = generated footer
+	headerTemplate    *pipeline.PageTemplate // nil = generated header
 	pdfConverter      pdfConverter
+
+	// Hooks added with WithMarkdownTransform, WithHTMLTransform and
+	// WithPDFPostProcessor, in order.
+	markdownTransforms []MarkdownTransformer
+	htmlTransforms     []HTMLTransformer
+	pdfPostProcessors  []PDFPostProcessor
 }
 
 // Service is an alias for Converter for backward compatibility.
@@ -62,6 +79,8 @@ func (a *publicToInternalAdapter) LoadTemplateSet(name string) (*assets.Template
 		Name:      ts.Name,
 		Cover:     ts.Cover,
 		Signature: ts.Signature,
+		Footer:    ts.Footer,
+		Header:    ts.Header,
 	}, nil
 }
 
@@ -79,18 +98,25 @@ func New(opts ...Option) (*Converter, error) {
 // Returns error if asset loading or template parsing fails.
 func NewConverter(opts ...Option) (*Converter, error) {
 	c := &Converter{
-		cfg:           converterConfig{timeout: defaultTimeout},
-		assetLoader:   assets.NewEmbeddedLoader(),
-		preprocessor:  &pipeline.CommonMarkPreprocessor{},
-		htmlConverter: pipeline.NewGoldmarkConverter(),
-		cssInjector:   &pipeline.CSSInjection{},
-		tocInjector:   pipeline.NewTOCInjection(),
+		cfg:              converterConfig{timeout: defaultTimeout},
+		assetLoader:      assets.NewEmbeddedLoader(),
+		preprocessor:     &pipeline.CommonMarkPreprocessor{},
+		htmlConverter:    pipeline.NewGoldmarkConverter(),
+		rawHTMLConverter: pipeline.NewRawHTMLGoldmarkConverter(),
+		cssInjector:      &pipeline.CSSInjection{},
+		tocInjector:      pipeline.NewTOCInjection(),
 	}
 
 	for _, opt := range opts {
 		opt(c)
 	}
 
+	// Handle WithGoldmarkExtensions: rebuild the converters with them
+	if exts := c.cfg.goldmarkExtensions; len(exts) > 0 {
+		c.htmlConverter = pipeline.NewGoldmarkConverter(exts...)
+		c.rawHTMLConverter = pipeline.NewRawHTMLGoldmarkConverter(exts...)
+	}
+
 	// Handle WithAssetPath: resolve to internal loader
 	if c.cfg.assetPath != "" {
 		resolver, err := assets.NewAssetResolver(c.cfg.assetPath)
@@ -139,6 +165,21 @@ func NewConverter(opts ...Option) (*Converter, error) {
 		}
 	}
 
+	// Footer and header templates are optional; without them the renderer
+	// generates its own from Footer and Header settings.
+	if templateSet.Footer != "" {
+		c.footerTemplate, err = pipeline.NewPageTemplate("footer", templateSet.Footer)
+		if err != nil {
+			return nil, fmt.Errorf("initializing footer template: %w", err)
+		}
+	}
+	if templateSet.Header != "" {
+		c.headerTemplate, err = pipeline.NewPageTemplate("header", templateSet.Header)
+		if err != nil {
+			return nil, fmt.Errorf("initializing header template: %w", err)
+		}
+	}
+
 	// Create PDF converter if not injected (e.g., by tests)
 	if c.pdfConverter == nil {
 		c.pdfConverter = newRodConverter(c.cfg.timeout)
@@ -158,6 +199,7 @@ func (c *Converter) Convert(ctx context.Context, input Input) (result *ConvertRe
 		}
 	}()
 
+	input = applyFrontmatter(input)
 	if err := c.validateInput(input); err != nil {
 		return nil, err
 	}
@@ -166,7 +208,63 @@ func (c *Converter) Convert(ctx context.Context, input Input) (result *ConvertRe
 	if err != nil {
 		return nil, err
 	}
+	return c.renderResult(ctx, htmlContent, input)
+}
+
+// ConvertMany merges chapters, in order, into a single document and returns
+// its HTML and PDF.
+//
+// The input supplies the document-wide settings: one cover, one TOC spanning
+// every chapter, footer, page, watermark, signature and styling. If
+// input.Markdown is set, it is rendered before the first chapter.
+// Heading IDs are made unique across chapters, and relative links between
+// chapter files are rewritten into in-document anchors.
+func (c *Converter) ConvertMany(ctx context.Context, input Input, chapters []Chapter) (result *ConvertResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("internal error: %v", r)
+		}
+	}()
 
+	if len(chapters) == 0 {
+		return nil, ErrNoChapters
+	}
+	for i, ch := range chapters {
+		if ch.Markdown == "" {
+			return nil, fmt.Errorf("chapter %d: %w", i+1, ErrEmptyMarkdown)
+		}
+	}
+	input = applyFrontmatter(input)
+	if err := c.validateSettings(input); err != nil {
+		return nil, err
+	}
+
+	htmlContent, err := c.renderMergedHTML(ctx, input, chapters)
+	if err != nil {
+		return nil, err
+	}
+	return c.renderResult(ctx, htmlContent, input)
+}
+
+// renderResult shares the decoration and PDF stages between single and merged
+// conversions. bodyHTML is the converted document before decorations.
+//
+// A TOC or list with page numbers, or an index with terms, needs two
+// renders: the first locates each heading's, figure's and term's page from
+// the PDF, the second prints the TOC, lists and index with those pages. The
+// first pass reserves the page column so the TOC does not change length
+// between passes, and is left unencrypted so its destinations can be read.
+//
+// A digital signature is added to the final PDF, after every other edit.
+func (c *Converter) renderResult(ctx context.Context, bodyHTML string, input Input) (*ConvertResult, error) {
+	bodyHTML, err := c.transformHTML(ctx, bodyHTML)
+	if err != nil {
+		return nil, err
+	}
+	htmlContent, err := c.injectHTMLDecorations(ctx, bodyHTML, input, nil)
+	if err != nil {
+		return nil, err
+	}
 	res := &ConvertResult{
 		HTML: []byte(htmlContent),
 	}
@@ -175,26 +273,84 @@ func (c *Converter) Convert(ctx context.Context, input Input) (result *ConvertRe
 		return res, nil
 	}
 
-	pdfBytes, err := c.pdfConverter.ToPDF(ctx, htmlContent, buildPDFOptions(input))
+	twoPass := (input.TOC != nil && input.TOC.PageNumbers) || hasPagedList(input) ||
+		(input.Index != nil && pipeline.HasIndexTerms(bodyHTML))
+	firstPass := input
+	if twoPass {
+		firstPass.Encryption = nil
+	}
+	pdfBytes, err := c.renderPDF(ctx, htmlContent, firstPass, !twoPass)
 	if err != nil {
-		return nil, fmt.Errorf("converting to PDF: %w", err)
+		return nil, err
+	}
+
+	if twoPass {
+		pages, err := destinationPages(pdfBytes, input.PageNumbering)
+		if err != nil {
+			return nil, fmt.Errorf("%w: locating pages: %w", ErrPDFPostProcess, err)
+		}
+		if htmlContent, err = c.injectHTMLDecorations(ctx, bodyHTML, input, pages); err != nil {
+			return nil, err
+		}
+		if pdfBytes, err = c.renderPDF(ctx, htmlContent, input, true); err != nil {
+			return nil, err
+		}
+		res.HTML = []byte(htmlContent)
 	}
 
+	if input.DigitalSignature != nil {
+		if pdfBytes, err = signPDF(ctx, pdfBytes, input.DigitalSignature); err != nil {
+			return nil, err
+		}
+	}
 	res.PDF = pdfBytes
 	return res, nil
 }
 
+// renderPDF prints the decorated HTML, with footnotes at the foot of the
+// page if input asks for it. Only the final PDF goes through the PDF
+// post-processors; drafts printed to locate pages do not.
+func (c *Converter) renderPDF(ctx context.Context, htmlContent string, input Input, final bool) ([]byte, error) {
+	if input.Footnotes.atPageFoot() {
+		return c.renderFootnotedPDF(ctx, htmlContent, input, final)
+	}
+	return c.printPDF(ctx, htmlContent, input, nil, final)
+}
+
+// printPDF prints htmlContent once, stamping footnotes if set.
+func (c *Converter) printPDF(ctx context.Context, htmlContent string, input Input, footnotes *footnoteLayout, final bool) ([]byte, error) {
+	pdfOpts, err := c.buildPDFOptions(input, htmlContent)
+	if err != nil {
+		return nil, err
+	}
+	pdfOpts.Footnotes = footnotes
+	if final {
+		pdfOpts.PostProcessors = c.pdfPostProcessors
+	}
+	pdfBytes, err := c.pdfConverter.ToPDF(ctx, htmlContent, pdfOpts)
+	if err != nil {
+		return nil, fmt.Errorf("converting to PDF: %w", err)
+	}
+	return pdfBytes, nil
+}
+
 // renderHTML isolates markdown-to-HTML stages so PDF concerns remain outside
 // this path and HTML-only mode can reuse the same transformation pipeline.
 func (c *Converter) renderHTML(ctx context.Context, input Input) (string, error) {
-	mdContent := c.preprocessor.PreprocessMarkdown(ctx, input.Markdown)
-	if ctx.Err() != nil {
-		return "", ctx.Err()
+	markdown, err := c.expandMarkdown(ctx, input.Markdown, input.SourceDir, input)
+	if err != nil {
+		return "", err
 	}
-
-	htmlContent, err := c.htmlConverter.ToHTML(ctx, mdContent)
+	sources, err := citeSources(input, []string{markdown}, []string{""})
 	if err != nil {
-		return "", fmt.Errorf("converting to HTML: %w", err)
+		return "", err
+	}
+	htmlContent, blocks, err := c.markdownToHTML(ctx, sources[0], input, 0)
+	if err != nil {
+		return "", err
+	}
+	if htmlContent, err = c.renderDiagrams(ctx, htmlContent, blocks, input.Diagrams); err != nil {
+		return "", err
 	}
 	if input.SourceDir != "" {
 		htmlContent, err = pipeline.RewriteRelativePaths(htmlContent, input.SourceDir)
@@ -203,28 +359,310 @@ func (c *Converter) renderHTML(ctx context.Context, input Input) (string, error)
 		}
 	}
 
-	// Complete ==highlight== rendering after markdown conversion.
-	htmlContent = pipeline.ConvertMarkPlaceholders(htmlContent)
-	return c.injectHTMLDecorations(ctx, htmlContent, input)
+	// Complete ==highlight== and :::landscape rendering after markdown conversion.
+	htmlContent = pipeline.ConvertLandscapePlaceholders(pipeline.ConvertMarkPlaceholders(htmlContent))
+	if htmlContent, err = numberCrossRefs(htmlContent); err != nil {
+		return "", err
+	}
+	return c.renderMath(ctx, htmlContent, input.Math)
+}
+
+// renderMergedHTML converts each chapter separately so relative paths resolve
+// per chapter, then merges them. Citations are processed across all sources
+// first, so chapters share one numbering and one reference list. Diagrams
+// are rendered once on the merged document, so their ids are unique.
+// Document-wide decorations come later, in renderResult.
+func (c *Converter) renderMergedHTML(ctx context.Context, input Input, chapters []Chapter) (string, error) {
+	// Sources are input.Markdown, if any, then the chapters. labels name the
+	// chapters in errors; input.Markdown has none.
+	var sources, labels []string
+	var parts []pipeline.ChapterHTML
+	if input.Markdown != "" {
+		markdown, err := c.expandMarkdown(ctx, input.Markdown, input.SourceDir, input)
+		if err != nil {
+			return "", err
+		}
+		sources, labels = append(sources, markdown), append(labels, "")
+		parts = append(parts, pipeline.ChapterHTML{SourceDir: input.SourceDir})
+	}
+	for i, ch := range chapters {
+		label := fmt.Sprintf("chapter %d", i+1)
+		markdown, err := c.expandMarkdown(ctx, ch.Markdown, chapterSourceDir(ch, input.SourceDir), input)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", label, err)
+		}
+		sources, labels = append(sources, markdown), append(labels, label)
+		parts = append(parts, pipeline.ChapterHTML{Path: ch.Path})
+	}
+
+	sources, err := citeSources(input, sources, labels)
+	if err != nil {
+		return "", err
+	}
+	var blocks []pipeline.DiagramBlock
+	for i, markdown := range sources {
+		var partBlocks []pipeline.DiagramBlock
+		if parts[i].HTML, partBlocks, err = c.markdownToHTML(ctx, markdown, input, len(blocks)); err != nil {
+			return "", withLabel(labels[i], err)
+		}
+		for _, b := range partBlocks {
+			b.Label = labels[i]
+			blocks = append(blocks, b)
+		}
+	}
+
+	htmlContent, err := pipeline.MergeChapters(parts)
+	if err != nil {
+		return "", fmt.Errorf("merging chapters: %w", err)
+	}
+	if htmlContent, err = c.renderDiagrams(ctx, htmlContent, blocks, input.Diagrams); err != nil {
+		return "", err
+	}
+
+	htmlContent = pipeline.ConvertLandscapePlaceholders(pipeline.ConvertMarkPlaceholders(htmlContent))
+	if htmlContent, err = numberCrossRefs(htmlContent); err != nil {
+		return "", err
+	}
+	return c.renderMath(ctx, htmlContent, input.Math)
+}
+
+// numberCrossRefs numbers figures and tables and resolves references. Like
+// math, it runs on the assembled document, so numbers continue across
+// chapters and references may point into another chapter.
+func numberCrossRefs(htmlContent string) (string, error) {
+	numbered, err := pipeline.NumberCrossRefs(htmlContent)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrCrossReference, err)
+	}
+	return numbered, nil
+}
+
+// chapterSourceDir returns the directory a chapter's includes resolve
+// against: its own file's directory, or sourceDir when it has no path.
+func chapterSourceDir(ch Chapter, sourceDir string) string {
+	if ch.Path == "" {
+		return sourceDir
+	}
+	return filepath.Dir(ch.Path)
+}
+
+// expandMarkdown runs the include and variable stages for one source.
+// Includes resolve against sourceDir.
+func (c *Converter) expandMarkdown(ctx context.Context, markdown, sourceDir string, input Input) (string, error) {
+	markdown, err := pipeline.ExpandIncludes(markdown, sourceDir)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInclude, err)
+	}
+
+	if input.Variables != nil {
+		interpolator, ok := c.preprocessor.(pipeline.VariableInterpolator)
+		if !ok {
+			return "", fmt.Errorf("%w: preprocessor cannot interpolate variables", ErrInterpolation)
+		}
+		markdown, err = interpolator.InterpolateVariables(markdown, input.Variables)
+		if err != nil {
+			return "", fmt.Errorf("%w: %w", ErrInterpolation, err)
+		}
+	}
+	return c.transformMarkdown(ctx, markdown)
+}
+
+// citeSources formats the citations in the expanded sources with one
+// processor, so numbering runs across them, and inserts the reference list
+// at the first [bibliography] line or at the end of the last source. labels
+// name the sources in errors ("" for none). Sources are returned unchanged
+// without a bibliography.
+func citeSources(input Input, sources, labels []string) ([]string, error) {
+	b := input.Bibliography
+	if b == nil {
+		return sources, nil
+	}
+	path := b.Path
+	if !filepath.IsAbs(path) && input.SourceDir != "" {
+		path = filepath.Join(input.SourceDir, path)
+	}
+	entries, err := bibliography.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCitation, err)
+	}
+
+	style := bibliography.AuthorDate
+	if strings.EqualFold(b.Style, CitationStyleNumeric) {
+		style = bibliography.Numeric
+	}
+	processor := pipeline.NewCitationProcessor(entries, style)
+	cited := make([]string, len(sources))
+	for i, source := range sources {
+		if cited[i], err = processor.Cite(source); err != nil {
+			return nil, withLabel(labels[i], fmt.Errorf("%w: %w", ErrCitation, err))
+		}
+	}
+
+	title := b.Title
+	if title == "" {
+		title = DefaultBibliographyTitle
+	}
+	section := processor.References(title, pipeline.SectionLevel(cited...))
+	for i := range cited {
+		var found bool
+		if cited[i], found = pipeline.InsertBibliography(cited[i], section); found {
+			return cited, nil
+		}
+	}
+	if section != "" {
+		last := len(cited) - 1
+		cited[last] = strings.TrimRight(cited[last], "\n") + "\n\n" + section
+	}
+	return cited, nil
+}
+
+// withLabel prefixes err with the source label, if any.
+func withLabel(label string, err error) error {
+	if label == "" {
+		return err
+	}
+	return fmt.Errorf("%s: %w", label, err)
+}
+
+// markdownToHTML runs the preprocessing and Markdown conversion stages for
+// one expanded source; input supplies the document-wide settings. With
+// diagrams, mermaid fences are taken out before preprocessing, so their line
+// numbers match the source (once includes are expanded), and returned with
+// their placeholders numbered from first, for renderDiagrams.
+func (c *Converter) markdownToHTML(ctx context.Context, markdown string, input Input, first int) (string, []pipeline.DiagramBlock, error) {
+	var blocks []pipeline.DiagramBlock
+	if input.Diagrams != nil {
+		markdown, blocks = pipeline.ExtractMermaidBlocks(markdown, first)
+	}
+
+	mdContent := c.preprocessor.PreprocessMarkdown(ctx, markdown)
+	if ctx.Err() != nil {
+		return "", nil, ctx.Err()
+	}
+
+	htmlContent, err := c.toHTML(ctx, mdContent, input.RawHTML)
+	if err != nil {
+		return "", nil, err
+	}
+	return htmlContent, blocks, nil
+}
+
+// renderDiagrams renders the mermaid blocks of the document in one batch and
+// puts them in place of their placeholders. Like math, it runs once per
+// document, so the ids Mermaid gives each diagram are unique across chapters.
+func (c *Converter) renderDiagrams(ctx context.Context, htmlContent string, blocks []pipeline.DiagramBlock, d *Diagrams) (string, error) {
+	if len(blocks) == 0 {
+		return htmlContent, nil
+	}
+	renderer, ok := c.pdfConverter.(pipeline.MermaidRenderer)
+	if !ok {
+		return "", fmt.Errorf("%w: PDF converter cannot render diagrams", ErrDiagramRender)
+	}
+	figures, err := pipeline.RenderDiagrams(ctx, renderer, blocks, strings.ToLower(d.Theme))
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return "", ctxErr
+		}
+		return "", fmt.Errorf("%w: %w", ErrDiagramRender, err)
+	}
+	return pipeline.InsertDiagrams(htmlContent, figures), nil
+}
+
+// toHTML converts preprocessed Markdown to HTML. With raw HTML, the HTML
+// written in the Markdown is kept and the result sanitized before the
+// pipeline adds its own markup.
+func (c *Converter) toHTML(ctx context.Context, mdContent string, rawHTML *RawHTML) (string, error) {
+	if rawHTML == nil {
+		htmlContent, err := c.htmlConverter.ToHTML(ctx, mdContent)
+		if err != nil {
+			return "", fmt.Errorf("converting to HTML: %w", err)
+		}
+		return htmlContent, nil
+	}
+
+	htmlContent, err := c.rawHTMLConverter.ToHTML(ctx, mdContent)
+	if err != nil {
+		return "", fmt.Errorf("converting to HTML: %w", err)
+	}
+	htmlContent, err = pipeline.SanitizeHTML(htmlContent, pipeline.HTMLAllowlist{
+		Tags:       rawHTML.AllowedTags,
+		Attributes: rawHTML.AllowedAttributes,
+	})
+	if err != nil {
+		return "", fmt.Errorf("sanitizing raw HTML: %w", err)
+	}
+	return htmlContent, nil
+}
+
+// renderMath renders the TeX left by the goldmark math extension. It runs on
+// the assembled document, so equation numbers continue across chapters.
+func (c *Converter) renderMath(ctx context.Context, htmlContent string, m *Math) (string, error) {
+	if m == nil || !pipeline.HasMath(htmlContent) {
+		return htmlContent, nil
+	}
+	renderer, ok := c.pdfConverter.(pipeline.MathRenderer)
+	if !ok {
+		return "", fmt.Errorf("%w: PDF converter cannot render math", ErrMathRender)
+	}
+	rendered, err := pipeline.RenderMath(ctx, renderer, htmlContent, m.Numbering)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return "", ctxErr
+		}
+		return "", fmt.Errorf("%w: %w", ErrMathRender, err)
+	}
+	return rendered, nil
 }
 
 // injectHTMLDecorations keeps injection ordering explicit because cover/TOC/
-// signature placement depends on deterministic sequencing.
-func (c *Converter) injectHTMLDecorations(ctx context.Context, htmlContent string, input Input) (string, error) {
-	htmlContent = c.cssInjector.InjectCSS(ctx, htmlContent, buildCombinedCSS(c.cfg.resolvedStyle, input))
+// signature placement depends on deterministic sequencing. pages holds the
+// heading, figure, table and index term pages found by a previous render, if
+// any. The index goes in before the TOC, so the TOC lists it; the lists of
+// figures and tables follow the TOC.
+func (c *Converter) injectHTMLDecorations(ctx context.Context, htmlContent string, input Input, pages map[string]int) (string, error) {
+	htmlContent = pipeline.SetTitle(htmlContent, documentTitle(input, htmlContent))
+
+	baseCSS, err := c.documentStyle(input)
+	if err != nil {
+		return "", err
+	}
+	cssContent := buildCombinedCSS(baseCSS, input)
+	if pipeline.HasLandscapeSections(htmlContent) {
+		cssContent = buildLandscapeCSS(input.Page) + cssContent
+	}
+	if pipeline.HasRenderedMath(htmlContent) {
+		katexCSS, err := assets.KatexCSS()
+		if err != nil {
+			return "", fmt.Errorf("%w: %w", ErrMathRender, err)
+		}
+		cssContent = katexCSS + buildMathCSS() + cssContent
+	}
+	htmlContent = c.cssInjector.InjectCSS(ctx, htmlContent, cssContent)
 	if ctx.Err() != nil {
 		return "", ctx.Err()
 	}
 
+	htmlContent = pipeline.InjectIndex(htmlContent, toIndexData(input.Index, pages))
+
 	htmlWithCover, err := c.coverInjector.InjectCover(ctx, htmlContent, toCoverData(input.Cover))
 	if err != nil {
 		return "", fmt.Errorf("injecting cover: %w", err)
 	}
-	htmlWithTOC, err := c.tocInjector.InjectTOC(ctx, htmlWithCover, toTOCData(input.TOC))
+	htmlWithTOC, err := c.tocInjector.InjectTOC(ctx, htmlWithCover, toTOCData(input.TOC, pages))
 	if err != nil {
 		return "", fmt.Errorf("injecting TOC: %w", err)
 	}
-	htmlWithSignature, err := c.signatureInjector.InjectSignature(ctx, htmlWithTOC, toSignatureData(input.Signature))
+	htmlWithTOC = pipeline.InjectLists(htmlWithTOC,
+		toListData(input.ListOfFigures, DefaultListOfFiguresTitle, pages),
+		toListData(input.ListOfTables, DefaultListOfTablesTitle, pages))
+	sigData := toSignatureData(input.Signature)
+	if sigData != nil && input.DigitalSignature != nil {
+		sigData.FieldAnchors = input.DigitalSignature.Visible
+	}
+	if input.PageNumbering != nil {
+		htmlWithTOC = pipeline.MarkContentStart(htmlWithTOC)
+	}
+	htmlWithSignature, err := c.signatureInjector.InjectSignature(ctx, htmlWithTOC, sigData)
 	if err != nil {
 		return "", fmt.Errorf("injecting signature: %w", err)
 	}
@@ -242,16 +680,70 @@ func buildCombinedCSS(baseCSS string, input Input) string {
 	if input.Watermark != nil {
 		cssContent = buildWatermarkCSS(input.Watermark) + cssContent
 	}
+	if (input.TOC != nil && input.TOC.PageNumbers) || hasPagedList(input) {
+		cssContent = buildTOCPageNumbersCSS() + cssContent
+	}
+	if input.Diagrams != nil {
+		cssContent = buildDiagramsCSS() + cssContent
+	}
+	if input.Footnotes.atPageFoot() {
+		cssContent = buildPageFootnotesCSS() + cssContent
+	}
+	cssContent = buildMirroredMarginsCSS(input.Page) + cssContent
 	return buildPageBreaksCSS(input.PageBreaks) + cssContent
 }
 
 // buildPDFOptions isolates input-to-renderer option mapping to avoid repeating
-// footer/page conversion logic at call sites.
-func buildPDFOptions(input Input) *pdfOptions {
-	return &pdfOptions{
-		Footer: toFooterData(input.Footer),
-		Page:   input.Page,
+// footer/page conversion logic at call sites. htmlContent is the final
+// document, read for the PDF title and outline headings.
+// Custom footer and header templates render only when the matching Footer or
+// Header setting is present, so they follow the same enable/disable switches.
+func (c *Converter) buildPDFOptions(input Input, htmlContent string) (*pdfOptions, error) {
+	opts := &pdfOptions{
+		Footer:   toFooterData(input.Footer),
+		Header:   toHeaderData(input.Header, input.Cover),
+		Page:     input.Page,
+		Metadata: toPDFMetadata(input, pipeline.DocumentTitle(htmlContent)),
+		Outline:  toOutlineHeadings(input.Outline, htmlContent),
+		PDFA:     c.cfg.pdfa,
+
+		Numbering: input.PageNumbering,
+	}
+	if e := input.Encryption; e != nil {
+		opts.Encryption = toPDFEncryption(e)
+	}
+
+	// Landscape sections print at their CSS page size. Chrome lays out the
+	// footer and header at the paper size it is given, so they are stamped
+	// per page instead, with Chrome's default numbering.
+	if pipeline.HasLandscapeSections(htmlContent) {
+		opts.CSSPageSize = true
+		if opts.Numbering == nil && (input.Footer != nil || input.Header != nil) {
+			opts.Numbering = &PageNumbering{}
+		}
+	}
+	// Page footnotes raise the bottom margin, where Chrome would move the
+	// footer; it is stamped at its usual place instead.
+	if input.Footnotes.atPageFoot() && opts.Numbering == nil && (input.Footer != nil || input.Header != nil) {
+		opts.Numbering = &PageNumbering{}
+	}
+
+	if c.footerTemplate == nil && c.headerTemplate == nil {
+		return opts, nil
+	}
+	data := toPageTemplateData(input)
+	var err error
+	if c.footerTemplate != nil && input.Footer != nil {
+		if opts.FooterTemplate, err = c.footerTemplate.Render(data); err != nil {
+			return nil, fmt.Errorf("%w: footer: %w", ErrPageTemplateRender, err)
+		}
+	}
+	if c.headerTemplate != nil && input.Header != nil {
+		if opts.HeaderTemplate, err = c.headerTemplate.Render(data); err != nil {
+			return nil, fmt.Errorf("%w: header: %w", ErrPageTemplateRender, err)
+		}
 	}
+	return opts, nil
 }
 
 // Close releases resources (headless Chrome browser).
@@ -265,34 +757,51 @@ func (c *Converter) Close() error {
 // resolveStyle resolves the style input (name, path, or CSS content) to CSS content.
 // Called during New() after options are applied and asset loader is configured.
 func (c *Converter) resolveStyle() error {
-	source, value := styleinput.Classify(c.cfg.styleInput, "", true)
+	css, err := c.loadStyle(c.cfg.styleInput)
+	if err != nil {
+		return err
+	}
+	c.cfg.resolvedStyle = css
+	return nil
+}
+
+// documentStyle returns the base CSS for one conversion.
+// A frontmatter style replaces the converter-wide style for that document only.
+func (c *Converter) documentStyle(input Input) (string, error) {
+	if input.Frontmatter == nil || input.Frontmatter.Style == "" {
+		return c.cfg.resolvedStyle, nil
+	}
+	return c.loadStyle(input.Frontmatter.Style)
+}
+
+// loadStyle resolves a style input (name, path, or CSS content) to CSS content.
+// Returns empty CSS when no style is specified.
+func (c *Converter) loadStyle(styleInput string) (string, error) {
+	source, value := styleinput.Classify(styleInput, "", true)
 	if source == styleinput.SourceNone {
-		return nil // no style specified, use default from loader if needed
+		return "", nil // no style specified, use default from loader if needed
 	}
 
 	// File path? (contains / or \)
 	if source == styleinput.SourceFile {
 		content, err := os.ReadFile(value) // #nosec G304 -- user-provided path
 		if err != nil {
-			return fmt.Errorf("loading style file %q: %w", value, err)
+			return "", fmt.Errorf("loading style file %q: %w", value, err)
 		}
-		c.cfg.resolvedStyle = string(content)
-		return nil
+		return string(content), nil
 	}
 
 	// CSS content? (contains {)
 	if source == styleinput.SourceRawCSS {
-		c.cfg.resolvedStyle = value
-		return nil
+		return value, nil
 	}
 
 	// Style name -> use asset loader
 	css, err := c.assetLoader.LoadStyle(value)
 	if err != nil {
-		return fmt.Errorf("loading style %q: %w", value, err)
+		return "", fmt.Errorf("loading style %q: %w", value, err)
 	}
-	c.cfg.resolvedStyle = css
-	return nil
+	return css, nil
 }
 
 // validateInput checks that required fields are present and valid.
@@ -304,12 +813,39 @@ func (c *Converter) validateInput(input Input) error {
 	if input.Markdown == "" {
 		return ErrEmptyMarkdown
 	}
+	return c.validateSettings(input)
+}
+
+// validateSettings checks the optional decoration and layout fields of input.
+// ConvertMany uses it directly because its Markdown comes from chapters.
+func (c *Converter) validateSettings(input Input) error {
 	if err := input.Page.Validate(); err != nil {
 		return err
 	}
 	if err := input.Footer.Validate(); err != nil {
 		return err
 	}
+	if err := input.Header.Validate(); err != nil {
+		return err
+	}
+	if err := input.PageNumbering.Validate(); err != nil {
+		return err
+	}
+	if err := input.Diagrams.Validate(); err != nil {
+		return err
+	}
+	if err := input.RawHTML.Validate(); err != nil {
+		return err
+	}
+	if err := input.Variables.Validate(); err != nil {
+		return err
+	}
+	if err := input.Bibliography.Validate(); err != nil {
+		return err
+	}
+	if err := input.Footnotes.Validate(); err != nil {
+		return err
+	}
 	if err := input.Watermark.Validate(); err != nil {
 		return err
 	}
@@ -319,12 +855,32 @@ func (c *Converter) validateInput(input Input) error {
 	if err := input.TOC.Validate(); err != nil {
 		return err
 	}
+	if err := input.Outline.Validate(); err != nil {
+		return err
+	}
 	if err := input.PageBreaks.Validate(); err != nil {
 		return err
 	}
 	if err := input.Signature.Validate(); err != nil {
 		return err
 	}
+	if err := input.Encryption.Validate(); err != nil {
+		return err
+	}
+	if c.cfg.pdfa && input.Encryption != nil {
+		return fmt.Errorf("%w: PDF/A forbids encryption", ErrInvalidEncryption)
+	}
+	if err := input.DigitalSignature.Validate(); err != nil {
+		return err
+	}
+	if ds := input.DigitalSignature; ds != nil {
+		if input.Encryption != nil {
+			return fmt.Errorf("%w: an encrypted PDF cannot be signed", ErrInvalidDigitalSignature)
+		}
+		if ds.Visible && input.Signature == nil {
+			return fmt.Errorf("%w: a visible signature needs a signature block", ErrInvalidDigitalSignature)
+		}
+	}
 	return nil
 }
 
@@ -365,6 +921,116 @@ func toFooterData(f *Footer) *pipeline.FooterData {
 	}
 }
 
+// toHeaderData converts the public Header type to internal pipeline.HeaderData.
+// Empty placeholder values fall back to the cover so one Cover feeds both.
+// Returns nil when every slot is empty (no header to render).
+func toHeaderData(h *Header, cover *Cover) *pipeline.HeaderData {
+	if h == nil || (h.Left == "" && h.Center == "" && h.Right == "") {
+		return nil
+	}
+	data := &pipeline.HeaderData{
+		Left:       h.Left,
+		Center:     h.Center,
+		Right:      h.Right,
+		Title:      h.Title,
+		Version:    h.Version,
+		DocumentID: h.DocumentID,
+		Date:       h.Date,
+	}
+	if cover != nil {
+		if data.Title == "" {
+			data.Title = cover.Title
+		}
+		if data.Version == "" {
+			data.Version = cover.Version
+		}
+		if data.DocumentID == "" {
+			data.DocumentID = cover.DocumentID
+		}
+		if data.Date == "" {
+			data.Date = cover.Date
+		}
+	}
+	return data
+}
+
+// toPageTemplateData builds the data for custom footer and header templates.
+// Cover metadata is the base; Header values override it, and Footer values
+// fill fields that are still empty.
+func toPageTemplateData(input Input) *pipeline.PageTemplateData {
+	data := pipeline.NewPageTemplateData(toCoverData(input.Cover))
+	if h := input.Header; h != nil {
+		overrideString(&data.Title, h.Title)
+		overrideString(&data.Version, h.Version)
+		overrideString(&data.DocumentID, h.DocumentID)
+		overrideString(&data.Date, h.Date)
+	}
+	if f := input.Footer; f != nil {
+		data.Status = f.Status
+		data.Text = f.Text
+		if data.Date == "" {
+			data.Date = f.Date
+		}
+		if data.DocumentID == "" {
+			data.DocumentID = f.DocumentID
+		}
+	}
+	return data
+}
+
+// documentTitle returns the first title set in Metadata, Cover, Header or the first H1.
+func documentTitle(input Input, htmlContent string) string {
+	if input.Metadata != nil && input.Metadata.Title != "" {
+		return input.Metadata.Title
+	}
+	if input.Cover != nil && input.Cover.Title != "" {
+		return input.Cover.Title
+	}
+	if input.Header != nil && input.Header.Title != "" {
+		return input.Header.Title
+	}
+	return pipeline.FirstHeading(htmlContent)
+}
+
+// toPDFMetadata maps document properties to the PDF post-processing stage.
+// Empty Metadata fields fall back to the Cover, as documented on Metadata.
+func toPDFMetadata(input Input, title string) *pdfedit.Metadata {
+	m := &pdfedit.Metadata{
+		Title:    title,
+		Creator:  producerName,
+		Producer: producerName,
+		ModDate:  time.Now(),
+	}
+	if input.Cover != nil {
+		m.Author = input.Cover.Author
+		m.Subject = input.Cover.Description
+	}
+	if md := input.Metadata; md != nil {
+		overrideString(&m.Author, md.Author)
+		overrideString(&m.Subject, md.Subject)
+		m.Keywords = md.Keywords
+	}
+	return m
+}
+
+// toPDFEncryption maps the public permission switches to PDF permission bits.
+func toPDFEncryption(e *Encryption) *pdfedit.Encryption {
+	enc := &pdfedit.Encryption{
+		UserPassword:  e.UserPassword,
+		OwnerPassword: e.OwnerPassword,
+	}
+	if e.AllowPrint {
+		enc.Permissions |= pdfedit.PermPrint | pdfedit.PermPrintHigh
+	}
+	if e.AllowCopy {
+		enc.Permissions |= pdfedit.PermCopy
+	}
+	if e.AllowModify {
+		enc.Permissions |= pdfedit.PermModify | pdfedit.PermAnnotate | pdfedit.PermFillForms | pdfedit.PermAssemble
+	}
+	return enc
+}
+
 // toCoverData converts the public Cover type to internal pipeline.CoverData.
 func toCoverData(c *Cover) *pipeline.CoverData {
 	if c == nil {
@@ -388,8 +1054,64 @@ func toCoverData(c *Cover) *pipeline.CoverData {
 	}
 }
 
+// toOutlineHeadings selects the headings bookmarked in the PDF outline.
+func toOutlineHeadings(o *Outline, htmlContent string) []pipeline.Heading {
+	if o == nil {
+		return nil
+	}
+	minDepth := o.MinDepth
+	if minDepth == 0 {
+		minDepth = DefaultOutlineMinDepth
+	}
+	maxDepth := o.MaxDepth
+	if maxDepth == 0 {
+		maxDepth = DefaultOutlineMaxDepth
+	}
+	return pipeline.ExtractHeadings(htmlContent, minDepth, maxDepth)
+}
+
+// toIndexData converts the public Index type to internal pipeline.IndexData.
+// pages holds term pages from a previous render, if any.
+func toIndexData(idx *Index, pages map[string]int) *pipeline.IndexData {
+	if idx == nil {
+		return nil
+	}
+	title := idx.Title
+	if title == "" {
+		title = DefaultIndexTitle
+	}
+	return &pipeline.IndexData{Title: title, Pages: pages}
+}
+
+// toListData converts a public ListOf type to internal pipeline.ListData,
+// with pages as in toTOCData.
+func toListData(l *ListOf, defaultTitle string, pages map[string]int) *pipeline.ListData {
+	if l == nil {
+		return nil
+	}
+	data := &pipeline.ListData{Title: l.Title}
+	if data.Title == "" {
+		data.Title = defaultTitle
+	}
+	if l.PageNumbers {
+		data.PageNumbers = pages
+		if data.PageNumbers == nil {
+			data.PageNumbers = map[string]int{}
+		}
+	}
+	return data
+}
+
+// hasPagedList reports whether a list of figures or tables prints pages.
+func hasPagedList(input Input) bool {
+	return (input.ListOfFigures != nil && input.ListOfFigures.PageNumbers) ||
+		(input.ListOfTables != nil && input.ListOfTables.PageNumbers)
+}
+
 // toTOCData converts the public TOC type to internal pipeline.TOCData.
-func toTOCData(t *TOC) *pipeline.TOCData {
+// pages holds heading pages from a previous render; with PageNumbers set and
+// no pages yet, the TOC is laid out with empty page columns.
+func toTOCData(t *TOC, pages map[string]int) *pipeline.TOCData {
 	if t == nil {
 		return nil
 	}
@@ -401,9 +1123,16 @@ func toTOCData(t *TOC) *pipeline.TOCData {
 	if maxDepth == 0 {
 		maxDepth = DefaultTOCMaxDepth
 	}
-	return &pipeline.TOCData{
+	data := &pipeline.TOCData{
 		Title:    t.Title,
 		MinDepth: minDepth,
 		MaxDepth: maxDepth,
 	}
+	if t.PageNumbers {
+		data.PageNumbers = pages
+		if data.PageNumbers == nil {
+			data.PageNumbers = map[string]int{}
+		}
+	}
+	return data
 }
@@ -464,7 +464,7 @@ func BenchmarkToTOCData(b *testing.B) {
 		b.ResetTimer()
 
 		for i := 0; i < b.N; i++ {
-			result := toTOCData(nil)
+			result := toTOCData(nil, nil)
 			_ = result
 		}
 	})
@@ -474,7 +474,7 @@ func BenchmarkToTOCData(b *testing.B) {
 		b.ResetTimer()
 
 		for i := 0; i < b.N; i++ {
-			result := toTOCData(toc)
+			result := toTOCData(toc, nil)
 			_ = result
 		}
 	})
@@ -371,3 +371,36 @@ func TestConverter_Convert_PageBreaksWithOtherFeatures(t *testing.T) {
 		t.Errorf("Convert() PDF size = %d bytes, want >= 100", len(data.PDF))
 	}
 }
+
+// ---------------------------------------------------------------------------
+// TestConverter_Convert_DigitalSignature - Visible Signature Field
+// ---------------------------------------------------------------------------
+
+func TestConverter_Convert_DigitalSignature(t *testing.T) {
+	t.Parallel()
+
+	service := acquireService(t)
+
+	input := Input{
+		Markdown:         "# Agreement\n\nTerms.",
+		Signature:        &Signature{Name: "Jane Doe", Title: "Director"},
+		DigitalSignature: &DigitalSignature{CertFile: testSigningCert, Password: "test", Visible: true},
+	}
+	result, err := service.Convert(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Convert() unexpected error: %v", err)
+	}
+
+	// The signature is added to Chrome's output, so the markers around the
+	// signature block must have become named destinations.
+	if _, _, ok, err := signatureFieldRect(result.PDF); err != nil || !ok {
+		t.Errorf("signatureFieldRect() = %v, %v, want the signature block located", ok, err)
+	}
+	reports, err := VerifyPDFSignatures(result.PDF, testSigningRoots(t))
+	if err != nil {
+		t.Fatalf("VerifyPDFSignatures() unexpected error: %v", err)
+	}
+	if len(reports) != 1 || !reports[0].Valid() {
+		t.Errorf("VerifyPDFSignatures() = %+v, want one valid signature", reports)
+	}
+}
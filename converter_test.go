@@ -10,11 +10,14 @@ package picoloom
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
+	"github.com/alnah/picoloom/v2/internal/pdfedit"
 	"github.com/alnah/picoloom/v2/internal/pipeline"
 )
 
@@ -75,16 +78,20 @@ func (m *mockCSSInjector) InjectCSS(_ context.Context, htmlContent, cssContent s
 
 type mockPDFConverter struct {
 	called    bool
+	calls     int
 	inputHTML string
 	inputOpts *pdfOptions
+	allOpts   []*pdfOptions
 	output    []byte
 	err       error
 }
 
 func (m *mockPDFConverter) ToPDF(_ context.Context, htmlContent string, opts *pdfOptions) ([]byte, error) {
 	m.called = true
+	m.calls++
 	m.inputHTML = htmlContent
 	m.inputOpts = opts
+	m.allOpts = append(m.allOpts, opts)
 	if m.err != nil {
 		return nil, m.err
 	}
@@ -98,6 +105,47 @@ func (m *mockPDFConverter) Close() error {
 	return nil
 }
 
+// mockDiagramConverter is a mockPDFConverter that also renders diagrams.
+type mockDiagramConverter struct {
+	mockPDFConverter
+	sources []string
+	theme   string
+	calls   int
+	err     error
+}
+
+func (m *mockDiagramConverter) RenderMermaid(_ context.Context, sources []string, theme string) ([]string, error) {
+	m.sources, m.theme = sources, theme
+	m.calls++
+	if m.err != nil {
+		return nil, m.err
+	}
+	svgs := make([]string, len(sources))
+	for i := range sources {
+		svgs[i] = fmt.Sprintf("<svg id=\"d%d\"></svg>", i)
+	}
+	return svgs, nil
+}
+
+// mockMathConverter is a mockPDFConverter that also renders math.
+type mockMathConverter struct {
+	mockPDFConverter
+	sources []string
+	err     error
+}
+
+func (m *mockMathConverter) RenderMath(_ context.Context, sources []string, _ []bool) ([]string, error) {
+	m.sources = sources
+	if m.err != nil {
+		return nil, m.err
+	}
+	out := make([]string, len(sources))
+	for i := range sources {
+		out[i] = fmt.Sprintf("<span id=\"m%d\"></span>", i)
+	}
+	return out, nil
+}
+
 type mockSignatureInjector struct {
 	called    bool
 	inputHTML string
@@ -583,573 +631,798 @@ func TestWithTimeout(t *testing.T) {
 }
 
 // ---------------------------------------------------------------------------
-// TestWithAssetLoader - Asset Loader Option
+// TestWithPDFA - PDF/A Option
 // ---------------------------------------------------------------------------
 
-func TestWithAssetLoader(t *testing.T) {
+func TestWithPDFA(t *testing.T) {
 	t.Parallel()
 
-	customLoader := &mockAssetLoader{
-		styleContent: "/* custom */",
-		templateSet: &TemplateSet{
-			Name:      "custom",
-			Cover:     "<div>custom cover</div>",
-			Signature: "<div>custom signature</div>",
-		},
-	}
-
-	service, err := New(WithAssetLoader(customLoader))
+	pdfConv := &mockPDFConverter{}
+	service, err := NewConverter(WithPDFA(), withPDFConverter(pdfConv))
 	if err != nil {
-		t.Fatalf("New() unexpected error: %v", err)
+		t.Fatalf("NewConverter(WithPDFA()) unexpected error: %v", err)
 	}
 	defer service.Close()
 
-	if service.publicAssetLoader != customLoader {
-		t.Error("publicAssetLoader should be the custom loader")
+	if _, err := service.Convert(context.Background(), Input{Markdown: "# Archive"}); err != nil {
+		t.Fatalf("Convert() unexpected error: %v", err)
+	}
+	if !pdfConv.inputOpts.PDFA {
+		t.Error("pdfOptions.PDFA = false, want true")
 	}
 }
 
 // ---------------------------------------------------------------------------
-// TestWithAssetLoader_UsedByInjectors - Asset Loader Injector Integration
+// TestService_Convert_encryption - Password protection options
 // ---------------------------------------------------------------------------
 
-func TestWithAssetLoader_UsedByInjectors(t *testing.T) {
+func TestService_Convert_encryption(t *testing.T) {
 	t.Parallel()
 
-	// Test that the asset loader is used when creating cover and signature injectors.
-	// We use a mock loader that returns valid templates.
-	loader := &mockAssetLoader{
-		templateSet: NewTemplateSet("test", "<div>cover</div>", "<div>sig</div>"),
-	}
+	t.Run("maps permissions to PDF options", func(t *testing.T) {
+		t.Parallel()
 
-	service, err := New(WithAssetLoader(loader))
-	if err != nil {
-		t.Fatalf("New() unexpected error: %v", err)
-	}
-	defer service.Close()
+		pdfConv := &mockPDFConverter{}
+		service, err := NewConverter(withPDFConverter(pdfConv))
+		if err != nil {
+			t.Fatalf("NewConverter() unexpected error: %v", err)
+		}
+		t.Cleanup(func() { _ = service.Close() })
 
-	// Service should have initialized its injectors using the loader
-	if service.coverInjector == nil {
-		t.Error("coverInjector should not be nil")
-	}
-	if service.signatureInjector == nil {
-		t.Error("signatureInjector should not be nil")
-	}
-}
+		enc := &Encryption{UserPassword: "u", OwnerPassword: "o", AllowPrint: true, AllowCopy: true}
+		if _, err := service.Convert(context.Background(), Input{Markdown: "# Secret", Encryption: enc}); err != nil {
+			t.Fatalf("Convert() unexpected error: %v", err)
+		}
+		got := pdfConv.inputOpts.Encryption
+		want := &pdfedit.Encryption{
+			UserPassword:  "u",
+			OwnerPassword: "o",
+			Permissions:   pdfedit.PermPrint | pdfedit.PermPrintHigh | pdfedit.PermCopy,
+		}
+		if got == nil || *got != *want {
+			t.Errorf("pdfOptions.Encryption = %+v, want %+v", got, want)
+		}
+	})
 
-// ---------------------------------------------------------------------------
-// TestService_Close - Service Cleanup
-// ---------------------------------------------------------------------------
+	t.Run("TOC page numbers encrypt only the final render", func(t *testing.T) {
+		t.Parallel()
 
-func TestService_Close(t *testing.T) {
-	t.Parallel()
+		pdfConv := &mockPDFConverter{output: chromeLikePDF()}
+		service, err := NewConverter(withPDFConverter(pdfConv))
+		if err != nil {
+			t.Fatalf("NewConverter() unexpected error: %v", err)
+		}
+		t.Cleanup(func() { _ = service.Close() })
 
-	service, err := New()
-	if err != nil {
-		t.Fatalf("New() unexpected error: %v", err)
-	}
+		input := Input{Markdown: "# Secret", TOC: &TOC{PageNumbers: true}, Encryption: &Encryption{UserPassword: "u"}}
+		if _, err := service.Convert(context.Background(), input); err != nil {
+			t.Fatalf("Convert() unexpected error: %v", err)
+		}
+		if len(pdfConv.allOpts) != 2 || pdfConv.allOpts[0].Encryption != nil || pdfConv.allOpts[1].Encryption == nil {
+			t.Error("want an unencrypted first render and an encrypted second render")
+		}
+	})
 
-	// Close should not error
-	if err := service.Close(); err != nil {
-		t.Errorf("Close() error = %v", err)
+	tests := []struct {
+		name    string
+		opts    []Option
+		enc     *Encryption
+		wantErr error
+	}{
+		{"missing password", nil, &Encryption{AllowPrint: true}, ErrInvalidEncryption},
+		{"PDF/A forbids encryption", []Option{WithPDFA()}, &Encryption{UserPassword: "u"}, ErrInvalidEncryption},
 	}
 
-	// Double close should also not error
-	if err := service.Close(); err != nil {
-		t.Errorf("Close() second call error = %v", err)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service, err := NewConverter(append(tt.opts, withPDFConverter(&mockPDFConverter{}))...)
+			if err != nil {
+				t.Fatalf("NewConverter() unexpected error: %v", err)
+			}
+			t.Cleanup(func() { _ = service.Close() })
+
+			_, err = service.Convert(context.Background(), Input{Markdown: "# Secret", Encryption: tt.enc})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Convert() error = %v, want %v", err, tt.wantErr)
+			}
+		})
 	}
 }
 
 // ---------------------------------------------------------------------------
-// TestToSignatureData - Signature Data Conversion
+// TestService_Convert_pageNumbering - Front Matter Marker and Options
 // ---------------------------------------------------------------------------
 
-func TestToSignatureData(t *testing.T) {
+func TestService_Convert_pageNumbering(t *testing.T) {
 	t.Parallel()
 
-	t.Run("edge case: nil returns nil", func(t *testing.T) {
+	t.Run("marks content start and passes numbering", func(t *testing.T) {
 		t.Parallel()
-		result := toSignatureData(nil)
-		if result != nil {
-			t.Errorf("toSignatureData(nil) = %v, want nil", result)
+
+		pdfConv := &mockPDFConverter{}
+		service, err := NewConverter(withPDFConverter(pdfConv))
+		if err != nil {
+			t.Fatalf("NewConverter() unexpected error: %v", err)
+		}
+		t.Cleanup(func() { _ = service.Close() })
+
+		numbering := &PageNumbering{FrontMatter: FrontMatterRoman}
+		_, err = service.Convert(context.Background(), Input{
+			Markdown:      "# Guide\n\n## Setup",
+			TOC:           &TOC{MinDepth: 2},
+			PageNumbering: numbering,
+		})
+		if err != nil {
+			t.Fatalf("Convert() unexpected error: %v", err)
+		}
+		marker := `<div id="` + pipeline.ContentStartID + `">`
+		if !strings.Contains(pdfConv.inputHTML, "</nav>"+marker) {
+			t.Errorf("HTML = %s, want content start marker after the TOC", pdfConv.inputHTML)
+		}
+		if pdfConv.inputOpts.Numbering != numbering {
+			t.Errorf("pdfOptions.Numbering = %v, want %v", pdfConv.inputOpts.Numbering, numbering)
 		}
 	})
 
-	t.Run("happy path: converts all fields", func(t *testing.T) {
+	t.Run("invalid numbering", func(t *testing.T) {
 		t.Parallel()
 
-		sig := &Signature{
-			Name:      "John Doe",
-			Title:     "Developer",
-			Email:     "john@example.com",
-			ImagePath: "/path/to/image.png",
-			Links: []Link{
-				{Label: "GitHub", URL: "https://github.com/john"},
-			},
+		service, err := NewConverter(withPDFConverter(&mockPDFConverter{}))
+		if err != nil {
+			t.Fatalf("NewConverter() unexpected error: %v", err)
 		}
+		t.Cleanup(func() { _ = service.Close() })
 
-		result := toSignatureData(sig)
-
-		if result.Name != sig.Name {
-			t.Errorf("Name = %q, want %q", result.Name, sig.Name)
+		_, err = service.Convert(context.Background(), Input{Markdown: "# A", PageNumbering: &PageNumbering{Format: "x"}})
+		if !errors.Is(err, ErrInvalidPageNumbering) {
+			t.Errorf("Convert() error = %v, want %v", err, ErrInvalidPageNumbering)
 		}
-		if result.Title != sig.Title {
-			t.Errorf("Title = %q, want %q", result.Title, sig.Title)
+	})
+}
+
+// ---------------------------------------------------------------------------
+// TestService_Convert_diagrams - Mermaid Rendering Stage
+// ---------------------------------------------------------------------------
+
+func TestService_Convert_diagrams(t *testing.T) {
+	t.Parallel()
+
+	markdown := "---\ntitle: Flow\n---\n# Flow\n\n```mermaid\ngraph TD\n  A --> B\n```\n\n```go\nx := 1\n```\n\n~~~mermaid\nsequenceDiagram\n~~~\n"
+
+	t.Run("renders fences as figures", func(t *testing.T) {
+		t.Parallel()
+
+		conv := &mockDiagramConverter{}
+		service, err := NewConverter(withPDFConverter(conv))
+		if err != nil {
+			t.Fatalf("NewConverter() unexpected error: %v", err)
 		}
-		if result.Email != sig.Email {
-			t.Errorf("Email = %q, want %q", result.Email, sig.Email)
+		t.Cleanup(func() { _ = service.Close() })
+
+		result, err := service.Convert(context.Background(), Input{
+			Markdown: markdown,
+			Diagrams: &Diagrams{Theme: "Forest"},
+			HTMLOnly: true,
+		})
+		if err != nil {
+			t.Fatalf("Convert() unexpected error: %v", err)
 		}
-		if result.ImagePath != sig.ImagePath {
-			t.Errorf("ImagePath = %q, want %q", result.ImagePath, sig.ImagePath)
+		html := string(result.HTML)
+		for _, want := range []string{
+			`<figure class="diagram diagram-mermaid"><svg id="d0"></svg></figure>`,
+			`<figure class="diagram diagram-mermaid"><svg id="d1"></svg></figure>`,
+			`class="chroma"`,
+			"figure.diagram {",
+		} {
+			if !strings.Contains(html, want) {
+				t.Errorf("HTML missing %q:\n%s", want, html)
+			}
 		}
-		if len(result.Links) != 1 {
-			t.Fatalf("Links count = %d, want 1", len(result.Links))
+		if want := []string{"graph TD\n  A --> B", "sequenceDiagram"}; fmt.Sprint(conv.sources) != fmt.Sprint(want) {
+			t.Errorf("RenderMermaid() sources = %q, want %q", conv.sources, want)
 		}
-		if result.Links[0].Label != "GitHub" || result.Links[0].URL != "https://github.com/john" {
-			t.Errorf("Links[0] = %+v, want {GitHub, https://github.com/john}", result.Links[0])
+		if conv.theme != DiagramThemeForest {
+			t.Errorf("RenderMermaid() theme = %q, want %q", conv.theme, DiagramThemeForest)
 		}
 	})
 
-	t.Run("happy path: converts extended metadata fields", func(t *testing.T) {
+	t.Run("nil diagrams leaves code blocks", func(t *testing.T) {
 		t.Parallel()
 
-		sig := &Signature{
-			Name:       "Jane Smith",
-			Phone:      "+1-555-123-4567",
-			Address:    "123 Main St\nCity, State 12345",
-			Department: "Engineering",
+		conv := &mockDiagramConverter{}
+		service, err := NewConverter(withPDFConverter(conv))
+		if err != nil {
+			t.Fatalf("NewConverter() unexpected error: %v", err)
 		}
+		t.Cleanup(func() { _ = service.Close() })
 
-		result := toSignatureData(sig)
-
-		if result.Phone != sig.Phone {
-			t.Errorf("Phone = %q, want %q", result.Phone, sig.Phone)
-		}
-		if result.Address != sig.Address {
-			t.Errorf("Address = %q, want %q", result.Address, sig.Address)
+		result, err := service.Convert(context.Background(), Input{Markdown: markdown, HTMLOnly: true})
+		if err != nil {
+			t.Fatalf("Convert() unexpected error: %v", err)
 		}
-		if result.Department != sig.Department {
-			t.Errorf("Department = %q, want %q", result.Department, sig.Department)
+		html := string(result.HTML)
+		if conv.sources != nil || strings.Contains(html, "<figure") || !strings.Contains(html, "sequenceDiagram") {
+			t.Errorf("HTML = %s, want mermaid code block and no rendering", result.HTML)
 		}
 	})
-}
 
-// ---------------------------------------------------------------------------
-// TestToFooterData - Footer Data Conversion
-// ---------------------------------------------------------------------------
+	t.Run("error names the block line", func(t *testing.T) {
+		t.Parallel()
 
-func TestToFooterData(t *testing.T) {
-	t.Parallel()
+		conv := &mockDiagramConverter{err: &pipeline.MermaidError{Index: 1, Message: "Parse error on line 1"}}
+		service, err := NewConverter(withPDFConverter(conv))
+		if err != nil {
+			t.Fatalf("NewConverter() unexpected error: %v", err)
+		}
+		t.Cleanup(func() { _ = service.Close() })
 
-	t.Run("edge case: nil returns nil", func(t *testing.T) {
-		t.Parallel()
-		result := toFooterData(nil)
-		if result != nil {
-			t.Errorf("toFooterData(nil) = %v, want nil", result)
+		_, err = service.Convert(context.Background(), Input{Markdown: markdown, Diagrams: &Diagrams{}})
+		if !errors.Is(err, ErrDiagramRender) {
+			t.Fatalf("Convert() error = %v, want %v", err, ErrDiagramRender)
+		}
+		if !strings.Contains(err.Error(), "mermaid block at line 15: Parse error on line 1") {
+			t.Errorf("Convert() error = %q, want the failing block's line", err)
 		}
 	})
 
-	t.Run("happy path: converts all fields", func(t *testing.T) {
+	t.Run("chapters render in one batch", func(t *testing.T) {
 		t.Parallel()
 
-		footer := &Footer{
-			Position:       "center",
-			ShowPageNumber: true,
-			Date:           "2025-01-15",
-			Status:         "DRAFT",
-			Text:           "Footer",
+		conv := &mockDiagramConverter{}
+		service, err := NewConverter(withPDFConverter(conv))
+		if err != nil {
+			t.Fatalf("NewConverter() unexpected error: %v", err)
 		}
+		t.Cleanup(func() { _ = service.Close() })
 
-		result := toFooterData(footer)
-
-		if result.Position != footer.Position {
-			t.Errorf("Position = %q, want %q", result.Position, footer.Position)
+		result, err := service.ConvertMany(context.Background(), Input{Diagrams: &Diagrams{}, HTMLOnly: true}, []Chapter{
+			{Markdown: "# One\n\n```mermaid\ngraph TD\n```\n"},
+			{Markdown: "# Two\n\n```mermaid\npie\n```\n"},
+		})
+		if err != nil {
+			t.Fatalf("ConvertMany() unexpected error: %v", err)
 		}
-		if result.ShowPageNumber != footer.ShowPageNumber {
-			t.Errorf("ShowPageNumber = %v, want %v", result.ShowPageNumber, footer.ShowPageNumber)
+		if want := []string{"graph TD", "pie"}; conv.calls != 1 || fmt.Sprint(conv.sources) != fmt.Sprint(want) {
+			t.Errorf("RenderMermaid() called %d times with %q, want once with %q", conv.calls, conv.sources, want)
 		}
-		if result.Date != footer.Date {
-			t.Errorf("Date = %q, want %q", result.Date, footer.Date)
+		html := string(result.HTML)
+		for _, want := range []string{`<svg id="d0">`, `<svg id="d1">`} {
+			if strings.Count(html, want) != 1 {
+				t.Errorf("HTML has %d of %q, want 1:\n%s", strings.Count(html, want), want, html)
+			}
 		}
-		if result.Status != footer.Status {
-			t.Errorf("Status = %q, want %q", result.Status, footer.Status)
+		if strings.Index(html, `<svg id="d0">`) > strings.Index(html, "Two") {
+			t.Errorf("HTML = %s, want the first diagram in chapter 1", html)
 		}
-		if result.Text != footer.Text {
-			t.Errorf("Text = %q, want %q", result.Text, footer.Text)
+	})
+
+	t.Run("chapter error names the chapter", func(t *testing.T) {
+		t.Parallel()
+
+		conv := &mockDiagramConverter{err: &pipeline.MermaidError{Index: 1, Message: "Parse error on line 1"}}
+		service, err := NewConverter(withPDFConverter(conv))
+		if err != nil {
+			t.Fatalf("NewConverter() unexpected error: %v", err)
+		}
+		t.Cleanup(func() { _ = service.Close() })
+
+		_, err = service.ConvertMany(context.Background(), Input{Diagrams: &Diagrams{}}, []Chapter{
+			{Markdown: "```mermaid\ngraph TD\n```\n"},
+			{Markdown: "# Two\n\n```mermaid\npie\n```\n"},
+		})
+		if !errors.Is(err, ErrDiagramRender) || !strings.Contains(err.Error(), "chapter 2: mermaid block at line 3") {
+			t.Errorf("ConvertMany() error = %v, want %v naming chapter 2, line 3", err, ErrDiagramRender)
 		}
 	})
 
-	t.Run("converts DocumentID field", func(t *testing.T) {
+	t.Run("converter without diagram support", func(t *testing.T) {
 		t.Parallel()
 
-		footer := &Footer{
-			Position:   "right",
-			DocumentID: "DOC-2024-001",
+		service, err := NewConverter(withPDFConverter(&mockPDFConverter{}))
+		if err != nil {
+			t.Fatalf("NewConverter() unexpected error: %v", err)
 		}
+		t.Cleanup(func() { _ = service.Close() })
 
-		result := toFooterData(footer)
+		_, err = service.Convert(context.Background(), Input{Markdown: markdown, Diagrams: &Diagrams{}})
+		if !errors.Is(err, ErrDiagramRender) {
+			t.Errorf("Convert() error = %v, want %v", err, ErrDiagramRender)
+		}
+	})
 
-		if result.DocumentID != footer.DocumentID {
-			t.Errorf("DocumentID = %q, want %q", result.DocumentID, footer.DocumentID)
+	t.Run("invalid theme", func(t *testing.T) {
+		t.Parallel()
+
+		service, err := NewConverter(withPDFConverter(&mockDiagramConverter{}))
+		if err != nil {
+			t.Fatalf("NewConverter() unexpected error: %v", err)
+		}
+		t.Cleanup(func() { _ = service.Close() })
+
+		_, err = service.Convert(context.Background(), Input{Markdown: markdown, Diagrams: &Diagrams{Theme: "neon"}})
+		if !errors.Is(err, ErrInvalidDiagramTheme) {
+			t.Errorf("Convert() error = %v, want %v", err, ErrInvalidDiagramTheme)
 		}
 	})
 }
 
 // ---------------------------------------------------------------------------
-// TestToCoverData - Cover Data Conversion
+// TestService_Convert_math - KaTeX Rendering
 // ---------------------------------------------------------------------------
 
-func TestToCoverData(t *testing.T) {
+func TestService_Convert_math(t *testing.T) {
 	t.Parallel()
 
-	t.Run("edge case: nil returns nil", func(t *testing.T) {
+	chapter1 := "# One\n\nInline $x$ and\n\n$$\na^2\n$$\n"
+	chapter2 := "# Two\n\n$$b^2$$\n\nCosts $5, not `$y$`.\n"
+
+	t.Run("numbers equations across chapters", func(t *testing.T) {
 		t.Parallel()
-		result := toCoverData(nil)
-		if result != nil {
-			t.Errorf("toCoverData(nil) = %v, want nil", result)
+
+		conv := &mockMathConverter{}
+		service, err := NewConverter(withPDFConverter(conv))
+		if err != nil {
+			t.Fatalf("NewConverter() unexpected error: %v", err)
+		}
+		t.Cleanup(func() { _ = service.Close() })
+
+		result, err := service.ConvertMany(context.Background(), Input{Math: &Math{Numbering: true}, HTMLOnly: true}, []Chapter{
+			{Markdown: chapter1, Path: "one.md"},
+			{Markdown: chapter2, Path: "two.md"},
+		})
+		if err != nil {
+			t.Fatalf("ConvertMany() unexpected error: %v", err)
+		}
+		want := []string{"x", `a^2\tag{1}`, `b^2\tag{2}`}
+		if fmt.Sprint(conv.sources) != fmt.Sprint(want) {
+			t.Errorf("RenderMath() sources = %q, want %q", conv.sources, want)
+		}
+		html := string(result.HTML)
+		for _, want := range []string{
+			`<span class="math math-inline"><span id="m0"></span></span>`,
+			`<div class="math math-display"><span id="m2"></span></div>`,
+			"Costs $5",
+		} {
+			if !strings.Contains(html, want) {
+				t.Errorf("HTML missing %q:\n%s", want, html)
+			}
 		}
 	})
 
-	t.Run("happy path: converts all fields", func(t *testing.T) {
+	t.Run("nil math leaves TeX as written", func(t *testing.T) {
 		t.Parallel()
 
-		cover := &Cover{
-			Title:        "My Document",
-			Subtitle:     "A Comprehensive Guide",
-			Logo:         "/path/to/logo.png",
-			Author:       "John Doe",
-			AuthorTitle:  "Senior Developer",
-			Organization: "Acme Corp",
-			Date:         "2025-01-15",
-			Version:      "v1.0.0",
+		conv := &mockMathConverter{}
+		service, err := NewConverter(withPDFConverter(conv))
+		if err != nil {
+			t.Fatalf("NewConverter() unexpected error: %v", err)
 		}
+		t.Cleanup(func() { _ = service.Close() })
 
-		result := toCoverData(cover)
-
-		if result.Title != cover.Title {
-			t.Errorf("Title = %q, want %q", result.Title, cover.Title)
-		}
-		if result.Subtitle != cover.Subtitle {
-			t.Errorf("Subtitle = %q, want %q", result.Subtitle, cover.Subtitle)
-		}
-		if result.Logo != cover.Logo {
-			t.Errorf("Logo = %q, want %q", result.Logo, cover.Logo)
-		}
-		if result.Author != cover.Author {
-			t.Errorf("Author = %q, want %q", result.Author, cover.Author)
-		}
-		if result.AuthorTitle != cover.AuthorTitle {
-			t.Errorf("AuthorTitle = %q, want %q", result.AuthorTitle, cover.AuthorTitle)
-		}
-		if result.Organization != cover.Organization {
-			t.Errorf("Organization = %q, want %q", result.Organization, cover.Organization)
-		}
-		if result.Date != cover.Date {
-			t.Errorf("Date = %q, want %q", result.Date, cover.Date)
+		result, err := service.Convert(context.Background(), Input{Markdown: chapter1, HTMLOnly: true})
+		if err != nil {
+			t.Fatalf("Convert() unexpected error: %v", err)
 		}
-		if result.Version != cover.Version {
-			t.Errorf("Version = %q, want %q", result.Version, cover.Version)
+		if conv.sources != nil || !strings.Contains(string(result.HTML), `<span class="math math-inline">$x$</span>`) {
+			t.Errorf("HTML = %s, want unrendered TeX", result.HTML)
 		}
 	})
 
-	t.Run("edge case: empty fields preserved", func(t *testing.T) {
+	t.Run("error names the expression", func(t *testing.T) {
 		t.Parallel()
 
-		cover := &Cover{
-			Title: "Only Title",
-			// All other fields empty
+		conv := &mockMathConverter{err: &pipeline.MathError{Index: 1, Message: "KaTeX parse error"}}
+		service, err := NewConverter(withPDFConverter(conv))
+		if err != nil {
+			t.Fatalf("NewConverter() unexpected error: %v", err)
 		}
+		t.Cleanup(func() { _ = service.Close() })
 
-		result := toCoverData(cover)
-
-		if result.Title != "Only Title" {
-			t.Errorf("Title = %q, want %q", result.Title, "Only Title")
-		}
-		if result.Subtitle != "" {
-			t.Errorf("Subtitle = %q, want empty", result.Subtitle)
-		}
-		if result.Logo != "" {
-			t.Errorf("Logo = %q, want empty", result.Logo)
+		_, err = service.Convert(context.Background(), Input{Markdown: chapter1, Math: &Math{}})
+		if !errors.Is(err, ErrMathRender) {
+			t.Fatalf("Convert() error = %v, want %v", err, ErrMathRender)
 		}
-		if result.Author != "" {
-			t.Errorf("Author = %q, want empty", result.Author)
+		if !strings.Contains(err.Error(), `math "$$a^2$$": KaTeX parse error`) {
+			t.Errorf("Convert() error = %q, want the failing expression", err)
 		}
 	})
 
-	t.Run("happy path: converts extended metadata fields", func(t *testing.T) {
+	t.Run("converter without math support", func(t *testing.T) {
 		t.Parallel()
 
-		cover := &Cover{
-			Title:        "Project Spec",
-			ClientName:   "Acme Corporation",
-			ProjectName:  "Project Phoenix",
-			DocumentType: "Technical Specification",
-			DocumentID:   "DOC-2024-001",
-			Description:  "System design document",
-			Department:   "Engineering",
+		service, err := NewConverter(withPDFConverter(&mockPDFConverter{}))
+		if err != nil {
+			t.Fatalf("NewConverter() unexpected error: %v", err)
 		}
+		t.Cleanup(func() { _ = service.Close() })
 
-		result := toCoverData(cover)
-
-		if result.ClientName != cover.ClientName {
-			t.Errorf("ClientName = %q, want %q", result.ClientName, cover.ClientName)
-		}
-		if result.ProjectName != cover.ProjectName {
-			t.Errorf("ProjectName = %q, want %q", result.ProjectName, cover.ProjectName)
-		}
-		if result.DocumentType != cover.DocumentType {
-			t.Errorf("DocumentType = %q, want %q", result.DocumentType, cover.DocumentType)
-		}
-		if result.DocumentID != cover.DocumentID {
-			t.Errorf("DocumentID = %q, want %q", result.DocumentID, cover.DocumentID)
-		}
-		if result.Description != cover.Description {
-			t.Errorf("Description = %q, want %q", result.Description, cover.Description)
-		}
-		if result.Department != cover.Department {
-			t.Errorf("Department = %q, want %q", result.Department, cover.Department)
+		_, err = service.Convert(context.Background(), Input{Markdown: chapter1, Math: &Math{}})
+		if !errors.Is(err, ErrMathRender) {
+			t.Errorf("Convert() error = %v, want %v", err, ErrMathRender)
 		}
 	})
 }
 
 // ---------------------------------------------------------------------------
-// TestToTOCData - TOC Data Conversion
+// TestService_Convert_rawHTML - Sanitized Raw HTML
 // ---------------------------------------------------------------------------
 
-func TestToTOCData(t *testing.T) {
+func TestService_Convert_rawHTML(t *testing.T) {
 	t.Parallel()
 
-	t.Run("edge case: nil returns nil", func(t *testing.T) {
-		t.Parallel()
-		result := toTOCData(nil)
-		if result != nil {
-			t.Errorf("toTOCData(nil) = %v, want nil", result)
-		}
-	})
-
-	t.Run("happy path: converts all fields", func(t *testing.T) {
-		t.Parallel()
-
-		toc := &TOC{
-			Title:    "Table of Contents",
-			MinDepth: 2,
-			MaxDepth: 4,
-		}
-
-		result := toTOCData(toc)
-
-		if result.Title != toc.Title {
-			t.Errorf("Title = %q, want %q", result.Title, toc.Title)
-		}
-		if result.MinDepth != toc.MinDepth {
-			t.Errorf("MinDepth = %d, want %d", result.MinDepth, toc.MinDepth)
-		}
-		if result.MaxDepth != toc.MaxDepth {
-			t.Errorf("MaxDepth = %d, want %d", result.MaxDepth, toc.MaxDepth)
-		}
-	})
-
-	t.Run("edge case: zero MinDepth gets default", func(t *testing.T) {
-		t.Parallel()
-
-		toc := &TOC{
-			Title:    "Contents",
-			MinDepth: 0,
-			MaxDepth: 3,
-		}
-
-		result := toTOCData(toc)
-
-		if result.MinDepth != DefaultTOCMinDepth {
-			t.Errorf("MinDepth = %d, want %d (default)", result.MinDepth, DefaultTOCMinDepth)
-		}
-	})
+	markdown := "# Keys\n\nPress <kbd>Ctrl</kbd>+<kbd>C</kbd>.<br>H<sub>2</sub>O<script>alert(1)</script>\n\n" +
+		"<details>\n<summary>More</summary>\n\nHidden *text*.\n\n</details>\n\n" +
+		"<a href=\"javascript:alert(1)\" onclick=\"x\">link</a> <span style=\"color: red\">red</span>\n"
 
-	t.Run("edge case: zero MaxDepth gets default", func(t *testing.T) {
-		t.Parallel()
+	tests := []struct {
+		name    string
+		rawHTML *RawHTML
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "nil omits raw HTML",
+			want:    []string{"<!-- raw HTML omitted -->Ctrl"},
+			notWant: []string{"<kbd>", "<details>"},
+		},
+		{
+			name:    "keeps allowed elements and strips scripts",
+			rawHTML: &RawHTML{},
+			want: []string{
+				"<kbd>Ctrl</kbd>", "<br/>", "<sub>2</sub>", "<details>", "<summary>More</summary>",
+				"<p>Hidden <em>text</em>.</p>", "<a>link</a>", "<span>red</span>",
+			},
+			notWant: []string{"script", "alert(1)", "onclick", "javascript:"},
+		},
+		{
+			name:    "allowlist adds attributes",
+			rawHTML: &RawHTML{AllowedAttributes: []string{"style"}},
+			want:    []string{`<span style="color: red">red</span>`},
+			notWant: []string{"alert(1)"},
+		},
+	}
 
-		toc := &TOC{
-			Title:    "Contents",
-			MaxDepth: 0,
-		}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
 
-		result := toTOCData(toc)
+			service, err := NewConverter(withPDFConverter(&mockPDFConverter{}))
+			if err != nil {
+				t.Fatalf("NewConverter() unexpected error: %v", err)
+			}
+			t.Cleanup(func() { _ = service.Close() })
 
-		if result.MaxDepth != DefaultTOCMaxDepth {
-			t.Errorf("MaxDepth = %d, want %d (default)", result.MaxDepth, DefaultTOCMaxDepth)
-		}
-	})
+			result, err := service.Convert(context.Background(), Input{Markdown: markdown, RawHTML: tt.rawHTML, HTMLOnly: true})
+			if err != nil {
+				t.Fatalf("Convert() unexpected error: %v", err)
+			}
+			html := string(result.HTML)
+			for _, want := range tt.want {
+				if !strings.Contains(html, want) {
+					t.Errorf("HTML missing %q:\n%s", want, html)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(html, notWant) {
+					t.Errorf("HTML contains %q:\n%s", notWant, html)
+				}
+			}
+		})
+	}
 
-	t.Run("edge case: empty title preserved", func(t *testing.T) {
+	t.Run("invalid allowlist", func(t *testing.T) {
 		t.Parallel()
 
-		toc := &TOC{
-			Title:    "",
-			MaxDepth: 3,
+		service, err := NewConverter(withPDFConverter(&mockPDFConverter{}))
+		if err != nil {
+			t.Fatalf("NewConverter() unexpected error: %v", err)
 		}
+		t.Cleanup(func() { _ = service.Close() })
 
-		result := toTOCData(toc)
-
-		if result.Title != "" {
-			t.Errorf("Title = %q, want empty", result.Title)
+		_, err = service.Convert(context.Background(), Input{Markdown: markdown, RawHTML: &RawHTML{AllowedTags: []string{"script"}}})
+		if !errors.Is(err, ErrInvalidRawHTML) {
+			t.Errorf("Convert() error = %v, want %v", err, ErrInvalidRawHTML)
 		}
 	})
 }
 
 // ---------------------------------------------------------------------------
-// TestService_validateInput_TOC - TOC Validation
+// TestService_Convert_includes - Fragments Resolved Against SourceDir
 // ---------------------------------------------------------------------------
 
-func TestService_validateInput_TOC(t *testing.T) {
+func TestService_Convert_includes(t *testing.T) {
 	t.Parallel()
 
-	service, err := New()
+	dir := t.TempDir()
+	chapterDir := filepath.Join(dir, "chapters")
+	if err := os.MkdirAll(chapterDir, 0o755); err != nil {
+		t.Fatalf("MkdirAll() unexpected error: %v", err)
+	}
+	for path, content := range map[string]string{
+		filepath.Join(dir, "legal.md"):        "# Legal\n\nProvided as is.\n",
+		filepath.Join(chapterDir, "notes.md"): "Chapter notes.\n",
+	} {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("WriteFile() unexpected error: %v", err)
+		}
+	}
+
+	service, err := NewConverter(withPDFConverter(&mockPDFConverter{}))
 	if err != nil {
-		t.Fatalf("New() unexpected error: %v", err)
+		t.Fatalf("NewConverter() unexpected error: %v", err)
 	}
-	defer service.Close()
+	t.Cleanup(func() { _ = service.Close() })
 
-	t.Run("happy path: nil TOC", func(t *testing.T) {
+	t.Run("document includes from SourceDir", func(t *testing.T) {
 		t.Parallel()
-		input := Input{Markdown: "# Hello", TOC: nil}
-		err := service.validateInput(input)
+
+		result, err := service.Convert(context.Background(), Input{
+			Markdown:  "# Report\n\n!include(legal.md, shift=1)\n",
+			SourceDir: dir,
+			HTMLOnly:  true,
+		})
 		if err != nil {
-			t.Errorf("validateInput(%v) unexpected error: %v", input, err)
+			t.Fatalf("Convert() unexpected error: %v", err)
+		}
+		if !strings.Contains(string(result.HTML), `<h2 id="legal">Legal</h2>`) {
+			t.Errorf("HTML missing shifted included heading:\n%s", result.HTML)
 		}
 	})
 
-	t.Run("happy path: valid TOC", func(t *testing.T) {
+	t.Run("chapters include from their own directory", func(t *testing.T) {
 		t.Parallel()
 
-		input := Input{
-			Markdown: "# Hello",
-			TOC:      &TOC{Title: "Contents", MaxDepth: 3},
-		}
-		err := service.validateInput(input)
+		result, err := service.ConvertMany(context.Background(), Input{HTMLOnly: true}, []Chapter{
+			{Markdown: "# One\n\n!include(notes.md)\n", Path: filepath.Join(chapterDir, "one.md")},
+		})
 		if err != nil {
-			t.Errorf("validateInput(%v) unexpected error: %v", input, err)
+			t.Fatalf("ConvertMany() unexpected error: %v", err)
+		}
+		if !strings.Contains(string(result.HTML), "Chapter notes.") {
+			t.Errorf("HTML missing included chapter notes:\n%s", result.HTML)
 		}
 	})
 
-	t.Run("error case: invalid TOC depth", func(t *testing.T) {
+	t.Run("error wraps ErrInclude", func(t *testing.T) {
 		t.Parallel()
 
-		input := Input{
-			Markdown: "# Hello",
-			TOC:      &TOC{MaxDepth: 7},
-		}
-		err := service.validateInput(input)
-		if err == nil {
-			t.Fatalf("validateInput(%v) error = nil, want error", input)
-		}
-		if !errors.Is(err, ErrInvalidTOCDepth) {
-			t.Errorf("validateInput(%v) error = %v, want ErrInvalidTOCDepth", input, err)
+		_, err := service.Convert(context.Background(), Input{
+			Markdown:  "!include(../outside.md)",
+			SourceDir: dir,
+		})
+		if !errors.Is(err, ErrInclude) || !errors.Is(err, pipeline.ErrIncludeOutsideRoot) {
+			t.Errorf("Convert() error = %v, want %v and %v", err, ErrInclude, pipeline.ErrIncludeOutsideRoot)
 		}
 	})
 }
 
 // ---------------------------------------------------------------------------
-// TestService_Convert_recoversPanic - Panic Recovery
+// TestService_Convert_variables - Template Variable Interpolation
 // ---------------------------------------------------------------------------
 
-func TestService_Convert_recoversPanic(t *testing.T) {
+func TestService_Convert_variables(t *testing.T) {
 	t.Parallel()
 
-	service, err := New(
-		withPreprocessor(&panicPreprocessor{}),
-		withHTMLConverter(&mockHTMLConverter{}),
-		withCSSInjector(&mockCSSInjector{}),
-		withSignatureInjector(&mockSignatureInjector{}),
-		withPDFConverter(&mockPDFConverter{}),
-	)
+	service, err := NewConverter(withPDFConverter(&mockPDFConverter{}))
 	if err != nil {
-		t.Fatalf("New() unexpected error: %v", err)
+		t.Fatalf("NewConverter() unexpected error: %v", err)
 	}
-	defer service.Close()
+	t.Cleanup(func() { _ = service.Close() })
 
-	ctx := context.Background()
-	input := Input{Markdown: "# Test"}
-	_, err = service.Convert(ctx, input)
+	vars := &Variables{
+		Document: DocumentVariables{Version: "v2.1"},
+		Author:   AuthorVariables{Name: "Jane Doe"},
+		Vars:     map[string]string{"sla": "99.9%"},
+	}
 
-	if err == nil {
-		t.Fatalf("Convert(%v, %v) error = nil, want error", ctx, input)
+	tests := []struct {
+		name      string
+		input     Input
+		wantHTML  []string
+		wantError error
+	}{
+		{
+			name: "substitutes variables outside code",
+			input: Input{
+				Markdown:  "# Spec {{ .Document.Version }}\n\nBy {{ .Author.Name }}, SLA {{ .Vars.sla }}, `{{ .Vars.sla }}`\n",
+				Variables: vars,
+				HTMLOnly:  true,
+			},
+			wantHTML: []string{"Spec v2.1", "By Jane Doe, SLA 99.9%", "<code>{{ .Vars.sla }}</code>"},
+		},
+		{
+			name: "disabled leaves actions as written",
+			input: Input{
+				Markdown: "Version {{ .Document.Version }}\n",
+				HTMLOnly: true,
+			},
+			wantHTML: []string{"Version {{ .Document.Version }}"},
+		},
+		{
+			name: "unknown variable wraps ErrInterpolation",
+			input: Input{
+				Markdown:  "Contact {{ .Vars.support }}\n",
+				Variables: vars,
+			},
+			wantError: ErrInterpolation,
+		},
+		{
+			name: "invalid variable name",
+			input: Input{
+				Markdown:  "text",
+				Variables: &Variables{Vars: map[string]string{"not-valid": "x"}},
+			},
+			wantError: ErrInvalidVariable,
+		},
 	}
-	if !strings.Contains(err.Error(), "internal error") {
-		t.Errorf("Convert(%v, %v) error should contain 'internal error', got %q", ctx, input, err.Error())
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := service.Convert(context.Background(), tt.input)
+			if tt.wantError != nil {
+				if !errors.Is(err, tt.wantError) {
+					t.Fatalf("Convert() error = %v, want %v", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Convert() unexpected error: %v", err)
+			}
+			for _, want := range tt.wantHTML {
+				if !strings.Contains(string(result.HTML), want) {
+					t.Errorf("HTML missing %q:\n%s", want, result.HTML)
+				}
+			}
+		})
 	}
 }
 
 // ---------------------------------------------------------------------------
-// TestService_Convert_contextCancellation - Context Cancellation Handling
+// TestService_Convert_crossRefs - Figure and Table Numbering
 // ---------------------------------------------------------------------------
 
-func TestService_Convert_contextCancellation(t *testing.T) {
+func TestService_Convert_crossRefs(t *testing.T) {
 	t.Parallel()
 
-	service, err := New(
-		withPreprocessor(&mockPreprocessor{}),
-		withHTMLConverter(&mockHTMLConverter{output: "<html></html>"}),
-		withCSSInjector(&mockCSSInjector{}),
-		withSignatureInjector(&mockSignatureInjector{}),
-		withPDFConverter(&mockPDFConverter{}),
-	)
+	service, err := NewConverter(withPDFConverter(&mockPDFConverter{}))
 	if err != nil {
-		t.Fatalf("New() unexpected error: %v", err)
+		t.Fatalf("NewConverter() unexpected error: %v", err)
 	}
-	defer service.Close()
+	t.Cleanup(func() { _ = service.Close() })
 
-	// Cancel context before calling Convert
-	ctx, cancel := context.WithCancel(context.Background())
-	cancel()
+	t.Run("numbers across chapters", func(t *testing.T) {
+		t.Parallel()
 
-	input := Input{Markdown: "# Test"}
-	_, err = service.Convert(ctx, input)
+		result, err := service.ConvertMany(context.Background(), Input{HTMLOnly: true}, []Chapter{
+			{Markdown: "# One\n\n![A](a.png \"Layout\"){#fig:layout}\n"},
+			{Markdown: "# Two\n\nAs @fig:layout shows, see @tbl:cost.\n\n| a |\n|---|\n| 1 |\n\nTable: Cost {#tbl:cost}\n"},
+		})
+		if err != nil {
+			t.Fatalf("ConvertMany() unexpected error: %v", err)
+		}
+		for _, want := range []string{
+			`<a class="crossref" href="#fig:layout">Figure 1</a>`,
+			`<a class="crossref" href="#tbl:cost">Table 1</a>`,
+		} {
+			if !strings.Contains(string(result.HTML), want) {
+				t.Errorf("HTML missing %q:\n%s", want, result.HTML)
+			}
+		}
+	})
 
-	if err == nil {
-		t.Fatalf("Convert(%v, %v) error = nil, want error", ctx, input)
-	}
-	if !errors.Is(err, context.Canceled) {
-		t.Errorf("Convert(%v, %v) error = %v, want context.Canceled", ctx, input, err)
-	}
+	t.Run("dangling reference wraps ErrCrossReference", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := service.Convert(context.Background(), Input{Markdown: "See @fig:missing."})
+		if !errors.Is(err, ErrCrossReference) || !errors.Is(err, pipeline.ErrUnresolvedReference) {
+			t.Errorf("Convert() error = %v, want %v and %v", err, ErrCrossReference, pipeline.ErrUnresolvedReference)
+		}
+	})
 }
 
 // ---------------------------------------------------------------------------
-// TestService_validateInput_invalidWatermark - Watermark Validation
+// TestService_Convert_citations - Citations and Reference List
 // ---------------------------------------------------------------------------
 
-func TestService_validateInput_invalidWatermark(t *testing.T) {
+func TestService_Convert_citations(t *testing.T) {
 	t.Parallel()
 
-	service, err := New()
+	service, err := NewConverter(withPDFConverter(&mockPDFConverter{}))
 	if err != nil {
-		t.Fatalf("New() unexpected error: %v", err)
+		t.Fatalf("NewConverter() unexpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = service.Close() })
+
+	dir := t.TempDir()
+	bib := "@book{knuth1984, author = {Donald E. Knuth}, title = {The {\\TeX}book}, publisher = {Addison-Wesley}, year = 1984}\n" +
+		"@article{lamport1986, author = {Leslie Lamport}, title = {Document Preparation}, journal = {TUGboat}, year = 1986}\n"
+	if err := os.WriteFile(filepath.Join(dir, "refs.bib"), []byte(bib), 0o600); err != nil {
+		t.Fatalf("WriteFile() unexpected error: %v", err)
 	}
-	defer service.Close()
 
 	tests := []struct {
 		name      string
-		watermark *Watermark
-		wantErr   bool
+		input     Input
+		chapters  []Chapter
+		wantHTML  []string
+		wantError error
 	}{
 		{
-			name:      "error case: opacity too high",
-			watermark: &Watermark{Text: "DRAFT", Opacity: 1.5},
-			wantErr:   true,
+			name: "author-date at end of document",
+			input: Input{
+				Markdown:     "# Notes\n\nAs shown [@lamport1986; see @knuth1984, p. 3].\n",
+				SourceDir:    dir,
+				Bibliography: &Bibliography{Path: "refs.bib"},
+			},
+			wantHTML: []string{
+				`(<a href="#ref-lamport1986">Lamport 1986</a>; see <a href="#ref-knuth1984">Knuth 1984</a>, p. 3)`,
+				`<h2 id="references">References</h2>`,
+				`<div class="fenced-div csl-entry" id="ref-knuth1984">`,
+				"Knuth, Donald E. 1984. <em>The TeXbook</em>. Addison-Wesley.",
+			},
 		},
 		{
-			name:      "error case: opacity negative",
-			watermark: &Watermark{Text: "DRAFT", Opacity: -0.1},
-			wantErr:   true,
+			name: "numeric at marker with custom title",
+			input: Input{
+				Markdown:     "# Notes\n\n@knuth1984 and [@lamport1986].\n\n[bibliography]\n\n## Appendix\n",
+				SourceDir:    dir,
+				Bibliography: &Bibliography{Path: "refs.bib", Style: CitationStyleNumeric, Title: "Sources"},
+			},
+			wantHTML: []string{
+				`Knuth [<a href="#ref-knuth1984">1</a>] and [<a href="#ref-lamport1986">2</a>]`,
+				"<h2 id=\"sources\">Sources</h2>\n<div class=\"fenced-div references\">",
+				"[2] L. Lamport, “Document Preparation,” <em>TUGboat</em>, 1986.",
+				"</div>\n<h2 id=\"appendix\">Appendix</h2>",
+			},
 		},
 		{
-			name:      "error case: angle too high",
-			watermark: &Watermark{Text: "DRAFT", Opacity: 0.5, Angle: 100},
-			wantErr:   true,
+			name:  "chapters share numbering",
+			input: Input{SourceDir: dir, Bibliography: &Bibliography{Path: "refs.bib", Style: CitationStyleNumeric}},
+			chapters: []Chapter{
+				{Markdown: "# One\n\n[@lamport1986]\n"},
+				{Markdown: "# Two\n\n[@knuth1984; @lamport1986]\n"},
+			},
+			wantHTML: []string{
+				`[<a href="#ref-knuth1984">2</a>, <a href="#ref-lamport1986">1</a>]`,
+				`<h1 id="references">References</h1>`,
+			},
 		},
 		{
-			name:      "error case: angle too low",
-			watermark: &Watermark{Text: "DRAFT", Opacity: 0.5, Angle: -100},
-			wantErr:   true,
+			name: "disabled leaves citations as written",
+			input: Input{
+				Markdown: "See [@knuth1984].\n",
+			},
+			wantHTML: []string{"See [@knuth1984]."},
+		},
+		{
+			name: "unknown key wraps ErrCitation",
+			input: Input{
+				Markdown:     "See [@nobody].\n",
+				SourceDir:    dir,
+				Bibliography: &Bibliography{Path: "refs.bib"},
+			},
+			wantError: pipeline.ErrUnknownCitation,
+		},
+		{
+			name: "missing file wraps ErrCitation",
+			input: Input{
+				Markdown:     "See [@knuth1984].\n",
+				SourceDir:    dir,
+				Bibliography: &Bibliography{Path: "missing.bib"},
+			},
+			wantError: ErrCitation,
+		},
+		{
+			name: "invalid style",
+			input: Input{
+				Markdown:     "text",
+				Bibliography: &Bibliography{Path: "refs.bib", Style: "apa"},
+			},
+			wantError: ErrInvalidBibliography,
 		},
 	}
 
@@ -1157,52 +1430,165 @@ func TestService_validateInput_invalidWatermark(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			input := Input{Markdown: "# Test", Watermark: tt.watermark}
-			err := service.validateInput(input)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("validateInput(%v) error = %v, wantErr %v", input, err, tt.wantErr)
+			input := tt.input
+			input.HTMLOnly = true
+			var result *ConvertResult
+			var err error
+			if tt.chapters != nil {
+				result, err = service.ConvertMany(context.Background(), input, tt.chapters)
+			} else {
+				result, err = service.Convert(context.Background(), input)
+			}
+			if tt.wantError != nil {
+				if !errors.Is(err, tt.wantError) {
+					t.Fatalf("Convert() error = %v, want %v", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Convert() unexpected error: %v", err)
+			}
+			for _, want := range tt.wantHTML {
+				if !strings.Contains(string(result.HTML), want) {
+					t.Errorf("HTML missing %q:\n%s", want, result.HTML)
+				}
 			}
 		})
 	}
 }
 
 // ---------------------------------------------------------------------------
-// TestService_validateInput_invalidPageBreaks - Page Breaks Validation
+// TestService_Convert_landscapeSections - Rotated Named Pages
 // ---------------------------------------------------------------------------
 
-func TestService_validateInput_invalidPageBreaks(t *testing.T) {
+func TestService_Convert_landscapeSections(t *testing.T) {
 	t.Parallel()
 
-	service, err := New()
-	if err != nil {
-		t.Fatalf("New() unexpected error: %v", err)
+	tests := []struct {
+		name          string
+		input         Input
+		wantNumbering bool
+	}{
+		{"without footer", Input{}, false},
+		{"footer is stamped", Input{Footer: &Footer{ShowPageNumber: true}}, true},
+		{"header is stamped", Input{Header: &Header{Right: "{pageNumber}"}}, true},
 	}
-	defer service.Close()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pdfConv := &mockPDFConverter{}
+			service, err := NewConverter(withPDFConverter(pdfConv))
+			if err != nil {
+				t.Fatalf("NewConverter() unexpected error: %v", err)
+			}
+			t.Cleanup(func() { _ = service.Close() })
+
+			input := tt.input
+			input.Markdown = "# Report\n\n:::landscape\n| a | b |\n|---|---|\n| 1 | 2 |\n:::\n\nEnd"
+			if _, err := service.Convert(context.Background(), input); err != nil {
+				t.Fatalf("Convert() unexpected error: %v", err)
+			}
+
+			for _, want := range []string{`<section class="landscape">`, "@page landscape {"} {
+				if !strings.Contains(pdfConv.inputHTML, want) {
+					t.Errorf("HTML missing %q", want)
+				}
+			}
+			if !pdfConv.inputOpts.CSSPageSize {
+				t.Error("pdfOptions.CSSPageSize = false, want true")
+			}
+			if got := pdfConv.inputOpts.Numbering != nil; got != tt.wantNumbering {
+				t.Errorf("pdfOptions.Numbering set = %v, want %v", got, tt.wantNumbering)
+			}
+		})
+	}
+
+	t.Run("no sections leaves page size to Chrome", func(t *testing.T) {
+		t.Parallel()
+
+		pdfConv := &mockPDFConverter{}
+		service, err := NewConverter(withPDFConverter(pdfConv))
+		if err != nil {
+			t.Fatalf("NewConverter() unexpected error: %v", err)
+		}
+		t.Cleanup(func() { _ = service.Close() })
+
+		if _, err := service.Convert(context.Background(), Input{Markdown: "# A", Footer: &Footer{}}); err != nil {
+			t.Fatalf("Convert() unexpected error: %v", err)
+		}
+		if pdfConv.inputOpts.CSSPageSize || pdfConv.inputOpts.Numbering != nil {
+			t.Errorf("pdfOptions = %+v, want no CSS page size or numbering", pdfConv.inputOpts)
+		}
+		if strings.Contains(pdfConv.inputHTML, "@page landscape") {
+			t.Error("HTML contains landscape page rules without landscape sections")
+		}
+	})
+}
+
+// ---------------------------------------------------------------------------
+// TestService_Convert_digitalSignature - Signing Stage
+// ---------------------------------------------------------------------------
+
+func TestService_Convert_digitalSignature(t *testing.T) {
+	t.Parallel()
+
+	t.Run("signs the final render", func(t *testing.T) {
+		t.Parallel()
+
+		sigInjector := &mockSignatureInjector{}
+		pdfConv := &mockPDFConverter{output: chromeLikePDF()}
+		service, err := NewConverter(withPDFConverter(pdfConv), withSignatureInjector(sigInjector))
+		if err != nil {
+			t.Fatalf("NewConverter() unexpected error: %v", err)
+		}
+		t.Cleanup(func() { _ = service.Close() })
+
+		input := Input{
+			Markdown:         "# Contract",
+			TOC:              &TOC{PageNumbers: true},
+			Signature:        &Signature{Name: "Jane"},
+			DigitalSignature: &DigitalSignature{CertFile: testSigningCert, Password: "test", Visible: true},
+		}
+		result, err := service.Convert(context.Background(), input)
+		if err != nil {
+			t.Fatalf("Convert() unexpected error: %v", err)
+		}
+		if !sigInjector.inputData.FieldAnchors {
+			t.Error("SignatureData.FieldAnchors = false, want true for a visible signature")
+		}
+		reports, err := VerifyPDFSignatures(result.PDF, testSigningRoots(t))
+		if err != nil {
+			t.Fatalf("VerifyPDFSignatures() unexpected error: %v", err)
+		}
+		if len(reports) != 1 || !reports[0].Valid() {
+			t.Errorf("VerifyPDFSignatures() = %+v, want one valid signature", reports)
+		}
+	})
 
 	tests := []struct {
-		name       string
-		pageBreaks *PageBreaks
-		wantErr    error
+		name  string
+		input Input
 	}{
 		{
-			name:       "error case: orphans too high",
-			pageBreaks: &PageBreaks{Orphans: MaxOrphans + 1},
-			wantErr:    ErrInvalidOrphans,
+			name:  "missing certificate file",
+			input: Input{DigitalSignature: &DigitalSignature{Reason: "Approval"}},
 		},
 		{
-			name:       "error case: widows too high",
-			pageBreaks: &PageBreaks{Widows: MaxWidows + 1},
-			wantErr:    ErrInvalidWidows,
+			name:  "timestamp URL not HTTP",
+			input: Input{DigitalSignature: &DigitalSignature{CertFile: "cert.pem", TimestampURL: "ftp://tsa.example"}},
 		},
 		{
-			name:       "error case: orphans negative",
-			pageBreaks: &PageBreaks{Orphans: -1},
-			wantErr:    ErrInvalidOrphans,
+			name: "combined with encryption",
+			input: Input{
+				DigitalSignature: &DigitalSignature{CertFile: "cert.pem"},
+				Encryption:       &Encryption{UserPassword: "u"},
+			},
 		},
 		{
-			name:       "error case: widows negative",
-			pageBreaks: &PageBreaks{Widows: -1},
-			wantErr:    ErrInvalidWidows,
+			name:  "visible without signature block",
+			input: Input{DigitalSignature: &DigitalSignature{CertFile: "cert.pem", Visible: true}},
 		},
 	}
 
@@ -1210,248 +1596,1495 @@ func TestService_validateInput_invalidPageBreaks(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			input := Input{Markdown: "# Test", PageBreaks: tt.pageBreaks}
-			err := service.validateInput(input)
-			if !errors.Is(err, tt.wantErr) {
-				t.Errorf("validateInput(%v) error = %v, want %v", input, err, tt.wantErr)
+			service, err := NewConverter(withPDFConverter(&mockPDFConverter{}))
+			if err != nil {
+				t.Fatalf("NewConverter() unexpected error: %v", err)
+			}
+			t.Cleanup(func() { _ = service.Close() })
+
+			tt.input.Markdown = "# Contract"
+			_, err = service.Convert(context.Background(), tt.input)
+			if !errors.Is(err, ErrInvalidDigitalSignature) {
+				t.Errorf("Convert() error = %v, want %v", err, ErrInvalidDigitalSignature)
 			}
 		})
 	}
 }
 
 // ---------------------------------------------------------------------------
-// TestService_CloseNilConverter - Close with Nil Converter
+// TestWithAssetLoader - Asset Loader Option
 // ---------------------------------------------------------------------------
 
-func TestService_CloseNilConverter(t *testing.T) {
+func TestWithAssetLoader(t *testing.T) {
 	t.Parallel()
 
-	service := &Service{
-		pdfConverter: nil,
+	customLoader := &mockAssetLoader{
+		styleContent: "/* custom */",
+		templateSet: &TemplateSet{
+			Name:      "custom",
+			Cover:     "<div>custom cover</div>",
+			Signature: "<div>custom signature</div>",
+		},
 	}
 
-	err := service.Close()
+	service, err := New(WithAssetLoader(customLoader))
 	if err != nil {
-		t.Errorf("Close() with nil pdfConverter should not error, got %v", err)
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+	defer service.Close()
+
+	if service.publicAssetLoader != customLoader {
+		t.Error("publicAssetLoader should be the custom loader")
 	}
 }
 
 // ---------------------------------------------------------------------------
-// TestService_Convert_watermarkCSSOrder - CSS Ordering with Watermark
+// TestWithAssetLoader_UsedByInjectors - Asset Loader Injector Integration
 // ---------------------------------------------------------------------------
 
-func TestService_Convert_watermarkCSSOrder(t *testing.T) {
+func TestWithAssetLoader_UsedByInjectors(t *testing.T) {
 	t.Parallel()
 
-	cssInj := &mockCSSInjector{}
+	// Test that the asset loader is used when creating cover and signature injectors.
+	// We use a mock loader that returns valid templates.
+	loader := &mockAssetLoader{
+		templateSet: NewTemplateSet("test", "<div>cover</div>", "<div>sig</div>"),
+	}
 
-	service, err := New(
-		withPreprocessor(&mockPreprocessor{}),
-		withHTMLConverter(&mockHTMLConverter{}),
-		withCSSInjector(cssInj),
-		withCoverInjector(&mockCoverInjector{}),
-		withTOCInjector(&mockTOCInjector{}),
-		withSignatureInjector(&mockSignatureInjector{}),
-		withPDFConverter(&mockPDFConverter{}),
-	)
+	service, err := New(WithAssetLoader(loader))
 	if err != nil {
 		t.Fatalf("New() unexpected error: %v", err)
 	}
 	defer service.Close()
 
-	ctx := context.Background()
-	input := Input{
-		Markdown: "# Test",
-		CSS:      "body { color: blue; }",
-		Watermark: &Watermark{
-			Text:    "DRAFT",
-			Color:   "#888888",
-			Opacity: 0.1,
-			Angle:   -45,
-		},
+	// Service should have initialized its injectors using the loader
+	if service.coverInjector == nil {
+		t.Error("coverInjector should not be nil")
 	}
+	if service.signatureInjector == nil {
+		t.Error("signatureInjector should not be nil")
+	}
+}
 
-	_, err = service.Convert(ctx, input)
+// ---------------------------------------------------------------------------
+// TestService_Close - Service Cleanup
+// ---------------------------------------------------------------------------
+
+func TestService_Close(t *testing.T) {
+	t.Parallel()
+
+	service, err := New()
 	if err != nil {
-		t.Fatalf("Convert() unexpected error: %v", err)
+		t.Fatalf("New() unexpected error: %v", err)
 	}
 
-	css := cssInj.inputCSS
-
-	// User CSS should be at the end
-	if !strings.HasSuffix(css, "body { color: blue; }") {
-		t.Errorf("user CSS should be at end, got %q", css)
+	// Close should not error
+	if err := service.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
 	}
 
-	// Watermark CSS should contain the watermark text
-	if !strings.Contains(css, "DRAFT") {
-		t.Errorf("CSS should contain watermark text 'DRAFT', got %q", css)
+	// Double close should also not error
+	if err := service.Close(); err != nil {
+		t.Errorf("Close() second call error = %v", err)
 	}
+}
+
+// ---------------------------------------------------------------------------
+// TestToSignatureData - Signature Data Conversion
+// ---------------------------------------------------------------------------
+
+func TestToSignatureData(t *testing.T) {
+	t.Parallel()
+
+	t.Run("edge case: nil returns nil", func(t *testing.T) {
+		t.Parallel()
+		result := toSignatureData(nil)
+		if result != nil {
+			t.Errorf("toSignatureData(nil) = %v, want nil", result)
+		}
+	})
+
+	t.Run("happy path: converts all fields", func(t *testing.T) {
+		t.Parallel()
+
+		sig := &Signature{
+			Name:      "John Doe",
+			Title:     "Developer",
+			Email:     "john@example.com",
+			ImagePath: "/path/to/image.png",
+			Links: []Link{
+				{Label: "GitHub", URL: "https://github.com/john"},
+			},
+		}
+
+		result := toSignatureData(sig)
+
+		if result.Name != sig.Name {
+			t.Errorf("Name = %q, want %q", result.Name, sig.Name)
+		}
+		if result.Title != sig.Title {
+			t.Errorf("Title = %q, want %q", result.Title, sig.Title)
+		}
+		if result.Email != sig.Email {
+			t.Errorf("Email = %q, want %q", result.Email, sig.Email)
+		}
+		if result.ImagePath != sig.ImagePath {
+			t.Errorf("ImagePath = %q, want %q", result.ImagePath, sig.ImagePath)
+		}
+		if len(result.Links) != 1 {
+			t.Fatalf("Links count = %d, want 1", len(result.Links))
+		}
+		if result.Links[0].Label != "GitHub" || result.Links[0].URL != "https://github.com/john" {
+			t.Errorf("Links[0] = %+v, want {GitHub, https://github.com/john}", result.Links[0])
+		}
+	})
+
+	t.Run("happy path: converts extended metadata fields", func(t *testing.T) {
+		t.Parallel()
+
+		sig := &Signature{
+			Name:       "Jane Smith",
+			Phone:      "+1-555-123-4567",
+			Address:    "123 Main St\nCity, State 12345",
+			Department: "Engineering",
+		}
+
+		result := toSignatureData(sig)
+
+		if result.Phone != sig.Phone {
+			t.Errorf("Phone = %q, want %q", result.Phone, sig.Phone)
+		}
+		if result.Address != sig.Address {
+			t.Errorf("Address = %q, want %q", result.Address, sig.Address)
+		}
+		if result.Department != sig.Department {
+			t.Errorf("Department = %q, want %q", result.Department, sig.Department)
+		}
+	})
+}
+
+// ---------------------------------------------------------------------------
+// TestToFooterData - Footer Data Conversion
+// ---------------------------------------------------------------------------
+
+func TestToFooterData(t *testing.T) {
+	t.Parallel()
+
+	t.Run("edge case: nil returns nil", func(t *testing.T) {
+		t.Parallel()
+		result := toFooterData(nil)
+		if result != nil {
+			t.Errorf("toFooterData(nil) = %v, want nil", result)
+		}
+	})
+
+	t.Run("happy path: converts all fields", func(t *testing.T) {
+		t.Parallel()
+
+		footer := &Footer{
+			Position:       "center",
+			ShowPageNumber: true,
+			Date:           "2025-01-15",
+			Status:         "DRAFT",
+			Text:           "Footer",
+		}
+
+		result := toFooterData(footer)
+
+		if result.Position != footer.Position {
+			t.Errorf("Position = %q, want %q", result.Position, footer.Position)
+		}
+		if result.ShowPageNumber != footer.ShowPageNumber {
+			t.Errorf("ShowPageNumber = %v, want %v", result.ShowPageNumber, footer.ShowPageNumber)
+		}
+		if result.Date != footer.Date {
+			t.Errorf("Date = %q, want %q", result.Date, footer.Date)
+		}
+		if result.Status != footer.Status {
+			t.Errorf("Status = %q, want %q", result.Status, footer.Status)
+		}
+		if result.Text != footer.Text {
+			t.Errorf("Text = %q, want %q", result.Text, footer.Text)
+		}
+	})
+
+	t.Run("converts DocumentID field", func(t *testing.T) {
+		t.Parallel()
+
+		footer := &Footer{
+			Position:   "right",
+			DocumentID: "DOC-2024-001",
+		}
+
+		result := toFooterData(footer)
+
+		if result.DocumentID != footer.DocumentID {
+			t.Errorf("DocumentID = %q, want %q", result.DocumentID, footer.DocumentID)
+		}
+	})
+}
+
+// ---------------------------------------------------------------------------
+// TestToHeaderData - Header Data Conversion
+// ---------------------------------------------------------------------------
+
+func TestToHeaderData(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil or empty slots return nil", func(t *testing.T) {
+		t.Parallel()
+
+		if got := toHeaderData(nil, nil); got != nil {
+			t.Errorf("toHeaderData(nil, nil) = %+v, want nil", got)
+		}
+		if got := toHeaderData(&Header{Title: "Only values"}, nil); got != nil {
+			t.Errorf("toHeaderData(no slots) = %+v, want nil", got)
+		}
+	})
+
+	t.Run("values fall back to cover", func(t *testing.T) {
+		t.Parallel()
+
+		got := toHeaderData(
+			&Header{Left: "{title}", Version: "v2"},
+			&Cover{Title: "Cover Title", Version: "v1", DocumentID: "DOC-1", Date: "2025-01-15"},
+		)
+		want := pipeline.HeaderData{
+			Left:       "{title}",
+			Title:      "Cover Title",
+			Version:    "v2",
+			DocumentID: "DOC-1",
+			Date:       "2025-01-15",
+		}
+		if got == nil || *got != want {
+			t.Errorf("toHeaderData() = %+v, want %+v", got, want)
+		}
+	})
+}
+
+// ---------------------------------------------------------------------------
+// TestToCoverData - Cover Data Conversion
+// ---------------------------------------------------------------------------
+
+func TestToCoverData(t *testing.T) {
+	t.Parallel()
+
+	t.Run("edge case: nil returns nil", func(t *testing.T) {
+		t.Parallel()
+		result := toCoverData(nil)
+		if result != nil {
+			t.Errorf("toCoverData(nil) = %v, want nil", result)
+		}
+	})
+
+	t.Run("happy path: converts all fields", func(t *testing.T) {
+		t.Parallel()
+
+		cover := &Cover{
+			Title:        "My Document",
+			Subtitle:     "A Comprehensive Guide",
+			Logo:         "/path/to/logo.png",
+			Author:       "John Doe",
+			AuthorTitle:  "Senior Developer",
+			Organization: "Acme Corp",
+			Date:         "2025-01-15",
+			Version:      "v1.0.0",
+		}
+
+		result := toCoverData(cover)
+
+		if result.Title != cover.Title {
+			t.Errorf("Title = %q, want %q", result.Title, cover.Title)
+		}
+		if result.Subtitle != cover.Subtitle {
+			t.Errorf("Subtitle = %q, want %q", result.Subtitle, cover.Subtitle)
+		}
+		if result.Logo != cover.Logo {
+			t.Errorf("Logo = %q, want %q", result.Logo, cover.Logo)
+		}
+		if result.Author != cover.Author {
+			t.Errorf("Author = %q, want %q", result.Author, cover.Author)
+		}
+		if result.AuthorTitle != cover.AuthorTitle {
+			t.Errorf("AuthorTitle = %q, want %q", result.AuthorTitle, cover.AuthorTitle)
+		}
+		if result.Organization != cover.Organization {
+			t.Errorf("Organization = %q, want %q", result.Organization, cover.Organization)
+		}
+		if result.Date != cover.Date {
+			t.Errorf("Date = %q, want %q", result.Date, cover.Date)
+		}
+		if result.Version != cover.Version {
+			t.Errorf("Version = %q, want %q", result.Version, cover.Version)
+		}
+	})
+
+	t.Run("edge case: empty fields preserved", func(t *testing.T) {
+		t.Parallel()
+
+		cover := &Cover{
+			Title: "Only Title",
+			// All other fields empty
+		}
+
+		result := toCoverData(cover)
+
+		if result.Title != "Only Title" {
+			t.Errorf("Title = %q, want %q", result.Title, "Only Title")
+		}
+		if result.Subtitle != "" {
+			t.Errorf("Subtitle = %q, want empty", result.Subtitle)
+		}
+		if result.Logo != "" {
+			t.Errorf("Logo = %q, want empty", result.Logo)
+		}
+		if result.Author != "" {
+			t.Errorf("Author = %q, want empty", result.Author)
+		}
+	})
+
+	t.Run("happy path: converts extended metadata fields", func(t *testing.T) {
+		t.Parallel()
+
+		cover := &Cover{
+			Title:        "Project Spec",
+			ClientName:   "Acme Corporation",
+			ProjectName:  "Project Phoenix",
+			DocumentType: "Technical Specification",
+			DocumentID:   "DOC-2024-001",
+			Description:  "System design document",
+			Department:   "Engineering",
+		}
+
+		result := toCoverData(cover)
+
+		if result.ClientName != cover.ClientName {
+			t.Errorf("ClientName = %q, want %q", result.ClientName, cover.ClientName)
+		}
+		if result.ProjectName != cover.ProjectName {
+			t.Errorf("ProjectName = %q, want %q", result.ProjectName, cover.ProjectName)
+		}
+		if result.DocumentType != cover.DocumentType {
+			t.Errorf("DocumentType = %q, want %q", result.DocumentType, cover.DocumentType)
+		}
+		if result.DocumentID != cover.DocumentID {
+			t.Errorf("DocumentID = %q, want %q", result.DocumentID, cover.DocumentID)
+		}
+		if result.Description != cover.Description {
+			t.Errorf("Description = %q, want %q", result.Description, cover.Description)
+		}
+		if result.Department != cover.Department {
+			t.Errorf("Department = %q, want %q", result.Department, cover.Department)
+		}
+	})
+}
+
+// ---------------------------------------------------------------------------
+// TestToTOCData - TOC Data Conversion
+// ---------------------------------------------------------------------------
+
+func TestToTOCData(t *testing.T) {
+	t.Parallel()
+
+	t.Run("edge case: nil returns nil", func(t *testing.T) {
+		t.Parallel()
+		result := toTOCData(nil, nil)
+		if result != nil {
+			t.Errorf("toTOCData(nil, nil) = %v, want nil", result)
+		}
+	})
+
+	t.Run("happy path: converts all fields", func(t *testing.T) {
+		t.Parallel()
+
+		toc := &TOC{
+			Title:    "Table of Contents",
+			MinDepth: 2,
+			MaxDepth: 4,
+		}
+
+		result := toTOCData(toc, nil)
+
+		if result.Title != toc.Title {
+			t.Errorf("Title = %q, want %q", result.Title, toc.Title)
+		}
+		if result.MinDepth != toc.MinDepth {
+			t.Errorf("MinDepth = %d, want %d", result.MinDepth, toc.MinDepth)
+		}
+		if result.MaxDepth != toc.MaxDepth {
+			t.Errorf("MaxDepth = %d, want %d", result.MaxDepth, toc.MaxDepth)
+		}
+	})
+
+	t.Run("edge case: zero MinDepth gets default", func(t *testing.T) {
+		t.Parallel()
+
+		toc := &TOC{
+			Title:    "Contents",
+			MinDepth: 0,
+			MaxDepth: 3,
+		}
+
+		result := toTOCData(toc, nil)
+
+		if result.MinDepth != DefaultTOCMinDepth {
+			t.Errorf("MinDepth = %d, want %d (default)", result.MinDepth, DefaultTOCMinDepth)
+		}
+	})
+
+	t.Run("edge case: zero MaxDepth gets default", func(t *testing.T) {
+		t.Parallel()
+
+		toc := &TOC{
+			Title:    "Contents",
+			MaxDepth: 0,
+		}
+
+		result := toTOCData(toc, nil)
+
+		if result.MaxDepth != DefaultTOCMaxDepth {
+			t.Errorf("MaxDepth = %d, want %d (default)", result.MaxDepth, DefaultTOCMaxDepth)
+		}
+	})
+
+	t.Run("edge case: empty title preserved", func(t *testing.T) {
+		t.Parallel()
+
+		toc := &TOC{
+			Title:    "",
+			MaxDepth: 3,
+		}
+
+		result := toTOCData(toc, nil)
+
+		if result.Title != "" {
+			t.Errorf("Title = %q, want empty", result.Title)
+		}
+	})
+}
+
+// ---------------------------------------------------------------------------
+// TestService_validateInput_TOC - TOC Validation
+// ---------------------------------------------------------------------------
+
+func TestService_validateInput_TOC(t *testing.T) {
+	t.Parallel()
+
+	service, err := New()
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+	defer service.Close()
+
+	t.Run("happy path: nil TOC", func(t *testing.T) {
+		t.Parallel()
+		input := Input{Markdown: "# Hello", TOC: nil}
+		err := service.validateInput(input)
+		if err != nil {
+			t.Errorf("validateInput(%v) unexpected error: %v", input, err)
+		}
+	})
+
+	t.Run("happy path: valid TOC", func(t *testing.T) {
+		t.Parallel()
+
+		input := Input{
+			Markdown: "# Hello",
+			TOC:      &TOC{Title: "Contents", MaxDepth: 3},
+		}
+		err := service.validateInput(input)
+		if err != nil {
+			t.Errorf("validateInput(%v) unexpected error: %v", input, err)
+		}
+	})
+
+	t.Run("error case: invalid TOC depth", func(t *testing.T) {
+		t.Parallel()
+
+		input := Input{
+			Markdown: "# Hello",
+			TOC:      &TOC{MaxDepth: 7},
+		}
+		err := service.validateInput(input)
+		if err == nil {
+			t.Fatalf("validateInput(%v) error = nil, want error", input)
+		}
+		if !errors.Is(err, ErrInvalidTOCDepth) {
+			t.Errorf("validateInput(%v) error = %v, want ErrInvalidTOCDepth", input, err)
+		}
+	})
+}
+
+// ---------------------------------------------------------------------------
+// TestService_Convert_recoversPanic - Panic Recovery
+// ---------------------------------------------------------------------------
+
+func TestService_Convert_recoversPanic(t *testing.T) {
+	t.Parallel()
+
+	service, err := New(
+		withPreprocessor(&panicPreprocessor{}),
+		withHTMLConverter(&mockHTMLConverter{}),
+		withCSSInjector(&mockCSSInjector{}),
+		withSignatureInjector(&mockSignatureInjector{}),
+		withPDFConverter(&mockPDFConverter{}),
+	)
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+	defer service.Close()
+
+	ctx := context.Background()
+	input := Input{Markdown: "# Test"}
+	_, err = service.Convert(ctx, input)
+
+	if err == nil {
+		t.Fatalf("Convert(%v, %v) error = nil, want error", ctx, input)
+	}
+	if !strings.Contains(err.Error(), "internal error") {
+		t.Errorf("Convert(%v, %v) error should contain 'internal error', got %q", ctx, input, err.Error())
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestService_Convert_contextCancellation - Context Cancellation Handling
+// ---------------------------------------------------------------------------
+
+func TestService_Convert_contextCancellation(t *testing.T) {
+	t.Parallel()
+
+	service, err := New(
+		withPreprocessor(&mockPreprocessor{}),
+		withHTMLConverter(&mockHTMLConverter{output: "<html></html>"}),
+		withCSSInjector(&mockCSSInjector{}),
+		withSignatureInjector(&mockSignatureInjector{}),
+		withPDFConverter(&mockPDFConverter{}),
+	)
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+	defer service.Close()
+
+	// Cancel context before calling Convert
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	input := Input{Markdown: "# Test"}
+	_, err = service.Convert(ctx, input)
+
+	if err == nil {
+		t.Fatalf("Convert(%v, %v) error = nil, want error", ctx, input)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Convert(%v, %v) error = %v, want context.Canceled", ctx, input, err)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestService_validateInput_invalidWatermark - Watermark Validation
+// ---------------------------------------------------------------------------
+
+func TestService_validateInput_invalidWatermark(t *testing.T) {
+	t.Parallel()
+
+	service, err := New()
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+	defer service.Close()
+
+	tests := []struct {
+		name      string
+		watermark *Watermark
+		wantErr   bool
+	}{
+		{
+			name:      "error case: opacity too high",
+			watermark: &Watermark{Text: "DRAFT", Opacity: 1.5},
+			wantErr:   true,
+		},
+		{
+			name:      "error case: opacity negative",
+			watermark: &Watermark{Text: "DRAFT", Opacity: -0.1},
+			wantErr:   true,
+		},
+		{
+			name:      "error case: angle too high",
+			watermark: &Watermark{Text: "DRAFT", Opacity: 0.5, Angle: 100},
+			wantErr:   true,
+		},
+		{
+			name:      "error case: angle too low",
+			watermark: &Watermark{Text: "DRAFT", Opacity: 0.5, Angle: -100},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			input := Input{Markdown: "# Test", Watermark: tt.watermark}
+			err := service.validateInput(input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateInput(%v) error = %v, wantErr %v", input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestService_validateInput_invalidPageBreaks - Page Breaks Validation
+// ---------------------------------------------------------------------------
+
+func TestService_validateInput_invalidPageBreaks(t *testing.T) {
+	t.Parallel()
+
+	service, err := New()
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+	defer service.Close()
+
+	tests := []struct {
+		name       string
+		pageBreaks *PageBreaks
+		wantErr    error
+	}{
+		{
+			name:       "error case: orphans too high",
+			pageBreaks: &PageBreaks{Orphans: MaxOrphans + 1},
+			wantErr:    ErrInvalidOrphans,
+		},
+		{
+			name:       "error case: widows too high",
+			pageBreaks: &PageBreaks{Widows: MaxWidows + 1},
+			wantErr:    ErrInvalidWidows,
+		},
+		{
+			name:       "error case: orphans negative",
+			pageBreaks: &PageBreaks{Orphans: -1},
+			wantErr:    ErrInvalidOrphans,
+		},
+		{
+			name:       "error case: widows negative",
+			pageBreaks: &PageBreaks{Widows: -1},
+			wantErr:    ErrInvalidWidows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			input := Input{Markdown: "# Test", PageBreaks: tt.pageBreaks}
+			err := service.validateInput(input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("validateInput(%v) error = %v, want %v", input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestService_CloseNilConverter - Close with Nil Converter
+// ---------------------------------------------------------------------------
+
+func TestService_CloseNilConverter(t *testing.T) {
+	t.Parallel()
+
+	service := &Service{
+		pdfConverter: nil,
+	}
+
+	err := service.Close()
+	if err != nil {
+		t.Errorf("Close() with nil pdfConverter should not error, got %v", err)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestService_Convert_watermarkCSSOrder - CSS Ordering with Watermark
+// ---------------------------------------------------------------------------
+
+func TestService_Convert_watermarkCSSOrder(t *testing.T) {
+	t.Parallel()
+
+	cssInj := &mockCSSInjector{}
+
+	service, err := New(
+		withPreprocessor(&mockPreprocessor{}),
+		withHTMLConverter(&mockHTMLConverter{}),
+		withCSSInjector(cssInj),
+		withCoverInjector(&mockCoverInjector{}),
+		withTOCInjector(&mockTOCInjector{}),
+		withSignatureInjector(&mockSignatureInjector{}),
+		withPDFConverter(&mockPDFConverter{}),
+	)
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+	defer service.Close()
+
+	ctx := context.Background()
+	input := Input{
+		Markdown: "# Test",
+		CSS:      "body { color: blue; }",
+		Watermark: &Watermark{
+			Text:    "DRAFT",
+			Color:   "#888888",
+			Opacity: 0.1,
+			Angle:   -45,
+		},
+	}
+
+	_, err = service.Convert(ctx, input)
+	if err != nil {
+		t.Fatalf("Convert() unexpected error: %v", err)
+	}
+
+	css := cssInj.inputCSS
+
+	// User CSS should be at the end
+	if !strings.HasSuffix(css, "body { color: blue; }") {
+		t.Errorf("user CSS should be at end, got %q", css)
+	}
+
+	// Watermark CSS should contain the watermark text
+	if !strings.Contains(css, "DRAFT") {
+		t.Errorf("CSS should contain watermark text 'DRAFT', got %q", css)
+	}
+
+	// Page breaks CSS should be present
+	if !strings.Contains(css, "break-after: avoid") {
+		t.Errorf("CSS should contain page breaks rules, got %q", css)
+	}
+
+	// Verify order: page breaks before watermark before user CSS
+	pageBreaksIdx := strings.Index(css, "break-after")
+	watermarkIdx := strings.Index(css, "DRAFT")
+	userCSSIdx := strings.Index(css, "body { color: blue; }")
+
+	if pageBreaksIdx > watermarkIdx {
+		t.Errorf("page breaks CSS should come before watermark CSS")
+	}
+	if watermarkIdx > userCSSIdx {
+		t.Errorf("watermark CSS should come before user CSS")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestService_Convert_coverInjectorError - Cover Injector Error Handling
+// ---------------------------------------------------------------------------
+
+func TestService_Convert_coverInjectorError(t *testing.T) {
+	t.Parallel()
+
+	coverErr := errors.New("cover template failed")
+
+	service, err := New(
+		withPreprocessor(&mockPreprocessor{}),
+		withHTMLConverter(&mockHTMLConverter{}),
+		withCSSInjector(&mockCSSInjector{}),
+		withCoverInjector(&mockCoverInjector{err: coverErr}),
+		withTOCInjector(&mockTOCInjector{}),
+		withSignatureInjector(&mockSignatureInjector{}),
+		withPDFConverter(&mockPDFConverter{}),
+	)
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+	defer service.Close()
+
+	ctx := context.Background()
+	input := Input{Markdown: "# Hello"}
+	_, err = service.Convert(ctx, input)
+
+	if err == nil {
+		t.Fatalf("Convert(%v, %v) error = nil, want error", ctx, input)
+	}
+	if !errors.Is(err, coverErr) {
+		t.Errorf("Convert(%v, %v) error should wrap %v, got %v", ctx, input, coverErr, err)
+	}
+	if !strings.Contains(err.Error(), "injecting cover") {
+		t.Errorf("Convert(%v, %v) error should mention 'injecting cover', got %q", ctx, input, err.Error())
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestService_Convert_tocInjectorError - TOC Injector Error Handling
+// ---------------------------------------------------------------------------
+
+func TestService_Convert_tocInjectorError(t *testing.T) {
+	t.Parallel()
+
+	tocErr := errors.New("TOC generation failed")
+
+	service, err := New(
+		withPreprocessor(&mockPreprocessor{}),
+		withHTMLConverter(&mockHTMLConverter{}),
+		withCSSInjector(&mockCSSInjector{}),
+		withCoverInjector(&mockCoverInjector{}),
+		withTOCInjector(&mockTOCInjector{err: tocErr}),
+		withSignatureInjector(&mockSignatureInjector{}),
+		withPDFConverter(&mockPDFConverter{}),
+	)
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+	defer service.Close()
+
+	ctx := context.Background()
+	input := Input{Markdown: "# Hello"}
+	_, err = service.Convert(ctx, input)
+
+	if err == nil {
+		t.Fatalf("Convert(%v, %v) error = nil, want error", ctx, input)
+	}
+	if !errors.Is(err, tocErr) {
+		t.Errorf("Convert(%v, %v) error should wrap %v, got %v", ctx, input, tocErr, err)
+	}
+	if !strings.Contains(err.Error(), "injecting TOC") {
+		t.Errorf("Convert(%v, %v) error should mention 'injecting TOC', got %q", ctx, input, err.Error())
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestService_Convert_pdfOptionsTransmission - PDF Options Passing
+// ---------------------------------------------------------------------------
+
+func TestService_Convert_pdfOptionsTransmission(t *testing.T) {
+	t.Parallel()
+
+	pdfConv := &mockPDFConverter{}
+
+	service, err := New(
+		withPreprocessor(&mockPreprocessor{}),
+		withHTMLConverter(&mockHTMLConverter{}),
+		withCSSInjector(&mockCSSInjector{}),
+		withCoverInjector(&mockCoverInjector{}),
+		withTOCInjector(&mockTOCInjector{}),
+		withSignatureInjector(&mockSignatureInjector{}),
+		withPDFConverter(pdfConv),
+	)
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+	defer service.Close()
+
+	ctx := context.Background()
+	input := Input{
+		Markdown: "# Test",
+		Page: &PageSettings{
+			Size:        PageSizeA4,
+			Orientation: OrientationLandscape,
+			Margin:      1.5,
+		},
+		Footer: &Footer{
+			Position:       "center",
+			ShowPageNumber: true,
+			Date:           "2025-01-15",
+			Status:         "FINAL",
+			Text:           "Confidential",
+		},
+	}
+
+	_, err = service.Convert(ctx, input)
+	if err != nil {
+		t.Fatalf("Convert() unexpected error: %v", err)
+	}
+
+	if pdfConv.inputOpts == nil {
+		t.Fatal("PDF options not passed to converter")
+	}
+
+	// Verify page settings
+	if pdfConv.inputOpts.Page == nil {
+		t.Fatal("Page settings not passed to PDF converter")
+	}
+	if pdfConv.inputOpts.Page.Size != PageSizeA4 {
+		t.Errorf("Page.Size = %q, want %q", pdfConv.inputOpts.Page.Size, PageSizeA4)
+	}
+	if pdfConv.inputOpts.Page.Orientation != OrientationLandscape {
+		t.Errorf("Page.Orientation = %q, want %q", pdfConv.inputOpts.Page.Orientation, OrientationLandscape)
+	}
+
+	// Verify footer data
+	if pdfConv.inputOpts.Footer == nil {
+		t.Fatal("Footer not passed to PDF converter")
+	}
+	if pdfConv.inputOpts.Footer.Position != "center" {
+		t.Errorf("Footer.Position = %q, want %q", pdfConv.inputOpts.Footer.Position, "center")
+	}
+	if !pdfConv.inputOpts.Footer.ShowPageNumber {
+		t.Error("Footer.ShowPageNumber = false, want true")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestService_Convert_pageTemplates - Custom Footer/Header Templates
+// ---------------------------------------------------------------------------
+
+func TestService_Convert_pageTemplates(t *testing.T) {
+	t.Parallel()
+
+	newService := func(t *testing.T, pdfConv *mockPDFConverter, footer, header string) *Converter {
+		t.Helper()
+		ts := NewTemplateSet("custom", "<div>cover</div>", "<div>sig</div>")
+		ts.Footer = footer
+		ts.Header = header
+		service, err := NewConverter(
+			WithTemplateSet(ts),
+			withPreprocessor(&mockPreprocessor{}),
+			withHTMLConverter(&mockHTMLConverter{}),
+			withCSSInjector(&mockCSSInjector{}),
+			withTOCInjector(&mockTOCInjector{}),
+			withPDFConverter(pdfConv),
+		)
+		if err != nil {
+			t.Fatalf("NewConverter() unexpected error: %v", err)
+		}
+		t.Cleanup(func() { _ = service.Close() })
+		return service
+	}
+
+	t.Run("renders templates with document data", func(t *testing.T) {
+		t.Parallel()
+
+		pdfConv := &mockPDFConverter{}
+		service := newService(t, pdfConv,
+			`<div>{{.Status}} {{.PageNumber}}/{{.TotalPages}}</div>`,
+			`<div>{{.Title}} {{.DocumentID}}</div>`)
+
+		_, err := service.Convert(context.Background(), Input{
+			Markdown: "# Test",
+			Cover:    &Cover{Title: "Spec"},
+			Footer:   &Footer{Status: "SECRET", DocumentID: "DOC-7"},
+			Header:   &Header{},
+		})
+		if err != nil {
+			t.Fatalf("Convert() unexpected error: %v", err)
+		}
+
+		wantFooter := `<div>SECRET <span class="pageNumber"></span>/<span class="totalPages"></span></div>`
+		if pdfConv.inputOpts.FooterTemplate != wantFooter {
+			t.Errorf("FooterTemplate = %q, want %q", pdfConv.inputOpts.FooterTemplate, wantFooter)
+		}
+		if pdfConv.inputOpts.HeaderTemplate != "<div>Spec DOC-7</div>" {
+			t.Errorf("HeaderTemplate = %q, want %q", pdfConv.inputOpts.HeaderTemplate, "<div>Spec DOC-7</div>")
+		}
+	})
+
+	t.Run("disabled footer skips template", func(t *testing.T) {
+		t.Parallel()
+
+		pdfConv := &mockPDFConverter{}
+		service := newService(t, pdfConv, "<div>footer</div>", "")
+
+		if _, err := service.Convert(context.Background(), Input{Markdown: "# Test"}); err != nil {
+			t.Fatalf("Convert() unexpected error: %v", err)
+		}
+		if pdfConv.inputOpts.FooterTemplate != "" {
+			t.Errorf("FooterTemplate = %q, want empty", pdfConv.inputOpts.FooterTemplate)
+		}
+	})
+
+	t.Run("render error returns ErrPageTemplateRender", func(t *testing.T) {
+		t.Parallel()
+
+		service := newService(t, &mockPDFConverter{}, "{{.Missing}}", "")
+
+		_, err := service.Convert(context.Background(), Input{Markdown: "# Test", Footer: &Footer{}})
+		if !errors.Is(err, ErrPageTemplateRender) {
+			t.Errorf("Convert() error = %v, want ErrPageTemplateRender", err)
+		}
+	})
+
+	t.Run("invalid template fails at construction", func(t *testing.T) {
+		t.Parallel()
+
+		ts := NewTemplateSet("custom", "<div>cover</div>", "<div>sig</div>")
+		ts.Header = "{{.Title"
+		if _, err := NewConverter(WithTemplateSet(ts), withPDFConverter(&mockPDFConverter{})); err == nil {
+			t.Error("NewConverter() error = nil, want template parse error")
+		}
+	})
+}
+
+// ---------------------------------------------------------------------------
+// TestService_Convert_metadata - Document title and PDF properties
+// ---------------------------------------------------------------------------
+
+func TestService_Convert_metadata(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		input        Input
+		wantTitle    string
+		wantAuthor   string
+		wantSubject  string
+		wantKeywords []string
+	}{
+		{
+			name:      "first heading when nothing is set",
+			input:     Input{Markdown: "# Release Notes\n\nBody"},
+			wantTitle: "Release Notes",
+		},
+		{
+			name: "cover provides defaults",
+			input: Input{
+				Markdown: "# Heading",
+				Cover:    &Cover{Title: "Spec", Author: "Ada", Description: "Summary"},
+			},
+			wantTitle:   "Spec",
+			wantAuthor:  "Ada",
+			wantSubject: "Summary",
+		},
+		{
+			name: "metadata overrides cover",
+			input: Input{
+				Markdown: "# Heading",
+				Cover:    &Cover{Title: "Spec", Author: "Ada"},
+				Metadata: &Metadata{Title: "Manual", Subject: "Ops", Keywords: []string{"a", "b"}},
+			},
+			wantTitle:    "Manual",
+			wantAuthor:   "Ada",
+			wantSubject:  "Ops",
+			wantKeywords: []string{"a", "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pdfConv := &mockPDFConverter{}
+			service, err := NewConverter(withPDFConverter(pdfConv))
+			if err != nil {
+				t.Fatalf("NewConverter() unexpected error: %v", err)
+			}
+			t.Cleanup(func() { _ = service.Close() })
+
+			if _, err := service.Convert(context.Background(), tt.input); err != nil {
+				t.Fatalf("Convert() unexpected error: %v", err)
+			}
+
+			wantTag := "<title>" + tt.wantTitle + "</title>"
+			if !strings.Contains(pdfConv.inputHTML, wantTag) {
+				t.Errorf("Convert() HTML missing %q", wantTag)
+			}
+			m := pdfConv.inputOpts.Metadata
+			if m == nil {
+				t.Fatal("pdfOptions.Metadata = nil, want metadata")
+			}
+			if m.Title != tt.wantTitle || m.Author != tt.wantAuthor || m.Subject != tt.wantSubject {
+				t.Errorf("Metadata = {%q, %q, %q}, want {%q, %q, %q}",
+					m.Title, m.Author, m.Subject, tt.wantTitle, tt.wantAuthor, tt.wantSubject)
+			}
+			if strings.Join(m.Keywords, ",") != strings.Join(tt.wantKeywords, ",") {
+				t.Errorf("Metadata.Keywords = %v, want %v", m.Keywords, tt.wantKeywords)
+			}
+			if m.Producer != producerName {
+				t.Errorf("Metadata.Producer = %q, want %q", m.Producer, producerName)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestService_Convert_outline - Outline heading selection
+// ---------------------------------------------------------------------------
+
+func TestService_Convert_outline(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		outline *Outline
+		want    string
+	}{
+		{"nil outline", nil, "[]"},
+		{"default depth skips H4", &Outline{}, "[1:intro 2:setup 3:linux]"},
+		{"custom depth", &Outline{MinDepth: 2, MaxDepth: 2}, "[2:setup]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pdfConv := &mockPDFConverter{}
+			service, err := NewConverter(withPDFConverter(pdfConv))
+			if err != nil {
+				t.Fatalf("NewConverter() unexpected error: %v", err)
+			}
+			t.Cleanup(func() { _ = service.Close() })
+
+			_, err = service.Convert(context.Background(), Input{
+				Markdown: "# Intro\n\n## Setup\n\n### Linux\n\n#### Details\n",
+				Outline:  tt.outline,
+			})
+			if err != nil {
+				t.Fatalf("Convert() unexpected error: %v", err)
+			}
+
+			got := make([]string, 0, len(pdfConv.inputOpts.Outline))
+			for _, h := range pdfConv.inputOpts.Outline {
+				got = append(got, fmt.Sprintf("%d:%s", h.Level, h.ID))
+			}
+			if fmt.Sprint(got) != tt.want {
+				t.Errorf("pdfOptions.Outline = %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestService_Convert_tocPageNumbers - Two-pass render
+// ---------------------------------------------------------------------------
+
+func TestService_Convert_tocPageNumbers(t *testing.T) {
+	t.Parallel()
+
+	markdown := "# Intro\n\n## Setup\n"
+	toc := &TOC{MinDepth: 1, PageNumbers: true}
+
+	t.Run("second render prints pages from the first", func(t *testing.T) {
+		t.Parallel()
+
+		pdfConv := &mockPDFConverter{output: buildTestPDF("",
+			"<</Type /Catalog /Pages 2 0 R /Dests <</intro [3 0 R /XYZ 0 792 0] /setup [4 0 R /XYZ 0 400 0]>>>>",
+			"<</Type /Pages /Count 2 /Kids [3 0 R 4 0 R]>>",
+			"<</Type /Page /Parent 2 0 R /MediaBox [0 0 612 792]>>",
+			"<</Type /Page /Parent 2 0 R /MediaBox [0 0 612 792]>>",
+		)}
+		service, err := NewConverter(withPDFConverter(pdfConv))
+		if err != nil {
+			t.Fatalf("NewConverter() unexpected error: %v", err)
+		}
+		t.Cleanup(func() { _ = service.Close() })
+
+		result, err := service.Convert(context.Background(), Input{Markdown: markdown, TOC: toc})
+		if err != nil {
+			t.Fatalf("Convert() unexpected error: %v", err)
+		}
+		if pdfConv.calls != 2 {
+			t.Errorf("ToPDF() called %d times, want 2", pdfConv.calls)
+		}
+		for _, want := range []string{
+			`<span class="toc-page">1</span>`,
+			`<span class="toc-page">2</span>`,
+			".toc-paged .toc-leader",
+		} {
+			if !strings.Contains(pdfConv.inputHTML, want) {
+				t.Errorf("second render HTML missing %q", want)
+			}
+		}
+		if string(result.HTML) != pdfConv.inputHTML {
+			t.Error("ConvertResult.HTML differs from the second render")
+		}
+	})
+
+	t.Run("HTML only reserves empty page columns", func(t *testing.T) {
+		t.Parallel()
+
+		pdfConv := &mockPDFConverter{}
+		service, err := NewConverter(withPDFConverter(pdfConv))
+		if err != nil {
+			t.Fatalf("NewConverter() unexpected error: %v", err)
+		}
+		t.Cleanup(func() { _ = service.Close() })
+
+		result, err := service.Convert(context.Background(), Input{Markdown: markdown, TOC: toc, HTMLOnly: true})
+		if err != nil {
+			t.Fatalf("Convert() unexpected error: %v", err)
+		}
+		if pdfConv.called {
+			t.Error("ToPDF() called in HTML-only mode")
+		}
+		if !strings.Contains(string(result.HTML), `<span class="toc-page"></span>`) {
+			t.Errorf("HTML missing empty page column:\n%s", result.HTML)
+		}
+	})
+
+	t.Run("unreadable first render fails", func(t *testing.T) {
+		t.Parallel()
+
+		service, err := NewConverter(withPDFConverter(&mockPDFConverter{}))
+		if err != nil {
+			t.Fatalf("NewConverter() unexpected error: %v", err)
+		}
+		t.Cleanup(func() { _ = service.Close() })
+
+		_, err = service.Convert(context.Background(), Input{Markdown: markdown, TOC: toc})
+		if !errors.Is(err, ErrPDFPostProcess) {
+			t.Errorf("Convert() error = %v, want %v", err, ErrPDFPostProcess)
+		}
+	})
+}
+
+// ---------------------------------------------------------------------------
+// TestService_Convert_index - Index pages from the first render
+// ---------------------------------------------------------------------------
+
+func TestService_Convert_index(t *testing.T) {
+	t.Parallel()
+
+	markdown := "# Guide\n\nFailover[[idx:failover]] and [[idx:replication!lag]].\n\n## Later\n\nAgain[[idx:failover]].\n"
+
+	t.Run("second render prints term pages and the TOC lists the index", func(t *testing.T) {
+		t.Parallel()
+
+		pdfConv := &mockPDFConverter{output: buildTestPDF("",
+			"<</Type /Catalog /Pages 2 0 R /Dests <</idx-1 [3 0 R /XYZ 0 792 0] /idx-2 [3 0 R /XYZ 0 400 0] /idx-3 [4 0 R /XYZ 0 400 0]>>>>",
+			"<</Type /Pages /Count 2 /Kids [3 0 R 4 0 R]>>",
+			"<</Type /Page /Parent 2 0 R /MediaBox [0 0 612 792]>>",
+			"<</Type /Page /Parent 2 0 R /MediaBox [0 0 612 792]>>",
+		)}
+		service, err := NewConverter(withPDFConverter(pdfConv))
+		if err != nil {
+			t.Fatalf("NewConverter() unexpected error: %v", err)
+		}
+		t.Cleanup(func() { _ = service.Close() })
+
+		input := Input{Markdown: markdown, Index: &Index{}, TOC: &TOC{}}
+		if _, err := service.Convert(context.Background(), input); err != nil {
+			t.Fatalf("Convert() unexpected error: %v", err)
+		}
+		if pdfConv.calls != 2 {
+			t.Errorf("ToPDF() called %d times, want 2", pdfConv.calls)
+		}
+		for _, want := range []string{
+			`<h2 id="index">Index</h2>`,
+			`failover, <a href="#idx-1">1</a>, <a href="#idx-3">2</a>`,
+			`lag, <a href="#idx-2">1</a>`,
+			`<a href="#index">`,
+		} {
+			if !strings.Contains(pdfConv.inputHTML, want) {
+				t.Errorf("second render HTML missing %q", want)
+			}
+		}
+	})
+
+	t.Run("without terms renders once", func(t *testing.T) {
+		t.Parallel()
+
+		pdfConv := &mockPDFConverter{}
+		service, err := NewConverter(withPDFConverter(pdfConv))
+		if err != nil {
+			t.Fatalf("NewConverter() unexpected error: %v", err)
+		}
+		t.Cleanup(func() { _ = service.Close() })
+
+		if _, err := service.Convert(context.Background(), Input{Markdown: "# Plain\n", Index: &Index{}}); err != nil {
+			t.Fatalf("Convert() unexpected error: %v", err)
+		}
+		if pdfConv.calls != 1 {
+			t.Errorf("ToPDF() called %d times, want 1", pdfConv.calls)
+		}
+	})
 
-	// Page breaks CSS should be present
-	if !strings.Contains(css, "break-after: avoid") {
-		t.Errorf("CSS should contain page breaks rules, got %q", css)
-	}
+	t.Run("disabled hides terms without an index", func(t *testing.T) {
+		t.Parallel()
 
-	// Verify order: page breaks before watermark before user CSS
-	pageBreaksIdx := strings.Index(css, "break-after")
-	watermarkIdx := strings.Index(css, "DRAFT")
-	userCSSIdx := strings.Index(css, "body { color: blue; }")
+		service, err := NewConverter(withPDFConverter(&mockPDFConverter{}))
+		if err != nil {
+			t.Fatalf("NewConverter() unexpected error: %v", err)
+		}
+		t.Cleanup(func() { _ = service.Close() })
 
-	if pageBreaksIdx > watermarkIdx {
-		t.Errorf("page breaks CSS should come before watermark CSS")
-	}
-	if watermarkIdx > userCSSIdx {
-		t.Errorf("watermark CSS should come before user CSS")
-	}
+		result, err := service.Convert(context.Background(), Input{Markdown: markdown, HTMLOnly: true})
+		if err != nil {
+			t.Fatalf("Convert() unexpected error: %v", err)
+		}
+		if html := string(result.HTML); strings.Contains(html, "idx:") || strings.Contains(html, `class="index"`) {
+			t.Errorf("HTML shows terms or an index:\n%s", html)
+		}
+	})
 }
 
 // ---------------------------------------------------------------------------
-// TestService_Convert_coverInjectorError - Cover Injector Error Handling
+// TestService_Convert_lists - Lists of figures and tables
 // ---------------------------------------------------------------------------
 
-func TestService_Convert_coverInjectorError(t *testing.T) {
+func TestService_Convert_lists(t *testing.T) {
 	t.Parallel()
 
-	coverErr := errors.New("cover template failed")
+	markdown := "# Report\n\n## Design\n\n![Arch](arch.png \"Architecture\"){#fig:arch}\n\n" +
+		"| a |\n|---|\n| 1 |\n\nTable: Capacity {#tbl:cap}\n"
 
-	service, err := New(
-		withPreprocessor(&mockPreprocessor{}),
-		withHTMLConverter(&mockHTMLConverter{}),
-		withCSSInjector(&mockCSSInjector{}),
-		withCoverInjector(&mockCoverInjector{err: coverErr}),
-		withTOCInjector(&mockTOCInjector{}),
-		withSignatureInjector(&mockSignatureInjector{}),
-		withPDFConverter(&mockPDFConverter{}),
-	)
-	if err != nil {
-		t.Fatalf("New() unexpected error: %v", err)
-	}
-	defer service.Close()
+	t.Run("lists follow the TOC with default titles", func(t *testing.T) {
+		t.Parallel()
 
-	ctx := context.Background()
-	input := Input{Markdown: "# Hello"}
-	_, err = service.Convert(ctx, input)
+		service, err := NewConverter(withPDFConverter(&mockPDFConverter{}))
+		if err != nil {
+			t.Fatalf("NewConverter() unexpected error: %v", err)
+		}
+		t.Cleanup(func() { _ = service.Close() })
 
-	if err == nil {
-		t.Fatalf("Convert(%v, %v) error = nil, want error", ctx, input)
-	}
-	if !errors.Is(err, coverErr) {
-		t.Errorf("Convert(%v, %v) error should wrap %v, got %v", ctx, input, coverErr, err)
-	}
-	if !strings.Contains(err.Error(), "injecting cover") {
-		t.Errorf("Convert(%v, %v) error should mention 'injecting cover', got %q", ctx, input, err.Error())
-	}
+		input := Input{Markdown: markdown, HTMLOnly: true, TOC: &TOC{}, ListOfFigures: &ListOf{}, ListOfTables: &ListOf{}}
+		result, err := service.Convert(context.Background(), input)
+		if err != nil {
+			t.Fatalf("Convert() unexpected error: %v", err)
+		}
+		html := string(result.HTML)
+		toc := strings.Index(html, `<nav class="toc">`)
+		figures := strings.Index(html, `<h2 class="toc-title">List of Figures</h2>`)
+		tables := strings.Index(html, `<h2 class="toc-title">List of Tables</h2>`)
+		body := strings.Index(html, `<h1 id="report">`)
+		if toc < 0 || figures < toc || tables < figures || body < tables {
+			t.Errorf("HTML order: toc %d, figures %d, tables %d, body %d", toc, figures, tables, body)
+		}
+		for _, want := range []string{
+			`<a href="#fig:arch">Figure 1: Architecture</a>`,
+			`<a href="#tbl:cap">Table 1: Capacity</a>`,
+		} {
+			if !strings.Contains(html, want) {
+				t.Errorf("HTML missing %q", want)
+			}
+		}
+	})
+
+	t.Run("page numbers render twice", func(t *testing.T) {
+		t.Parallel()
+
+		pdfConv := &mockPDFConverter{output: buildTestPDF("",
+			"<</Type /Catalog /Pages 2 0 R /Dests <</fig:arch [4 0 R /XYZ 0 792 0]>>>>",
+			"<</Type /Pages /Count 2 /Kids [3 0 R 4 0 R]>>",
+			"<</Type /Page /Parent 2 0 R /MediaBox [0 0 612 792]>>",
+			"<</Type /Page /Parent 2 0 R /MediaBox [0 0 612 792]>>",
+		)}
+		service, err := NewConverter(withPDFConverter(pdfConv))
+		if err != nil {
+			t.Fatalf("NewConverter() unexpected error: %v", err)
+		}
+		t.Cleanup(func() { _ = service.Close() })
+
+		input := Input{Markdown: markdown, ListOfFigures: &ListOf{Title: "Figures", PageNumbers: true}}
+		if _, err := service.Convert(context.Background(), input); err != nil {
+			t.Fatalf("Convert() unexpected error: %v", err)
+		}
+		if pdfConv.calls != 2 {
+			t.Errorf("ToPDF() called %d times, want 2", pdfConv.calls)
+		}
+		for _, want := range []string{
+			`<nav class="toc toc-figures toc-paged">`,
+			`<span class="toc-page">2</span>`,
+			".toc-paged .toc-leader",
+		} {
+			if !strings.Contains(pdfConv.inputHTML, want) {
+				t.Errorf("second render HTML missing %q", want)
+			}
+		}
+	})
 }
 
 // ---------------------------------------------------------------------------
-// TestService_Convert_tocInjectorError - TOC Injector Error Handling
+// TestService_Convert_footnotes - Page-bottom footnotes
 // ---------------------------------------------------------------------------
 
-func TestService_Convert_tocInjectorError(t *testing.T) {
+func TestService_Convert_footnotes(t *testing.T) {
 	t.Parallel()
 
-	tocErr := errors.New("TOC generation failed")
+	const markdown = "# Brief\n\nClaim[^a] and counterclaim[^b].\n\n[^a]: First source.\n[^b]: Second source.\n"
 
-	service, err := New(
-		withPreprocessor(&mockPreprocessor{}),
-		withHTMLConverter(&mockHTMLConverter{}),
-		withCSSInjector(&mockCSSInjector{}),
-		withCoverInjector(&mockCoverInjector{}),
-		withTOCInjector(&mockTOCInjector{err: tocErr}),
-		withSignatureInjector(&mockSignatureInjector{}),
-		withPDFConverter(&mockPDFConverter{}),
-	)
-	if err != nil {
-		t.Fatalf("New() unexpected error: %v", err)
-	}
-	defer service.Close()
+	t.Run("page placement measures then stamps", func(t *testing.T) {
+		t.Parallel()
 
-	ctx := context.Background()
-	input := Input{Markdown: "# Hello"}
-	_, err = service.Convert(ctx, input)
+		pdfConv := &mockPDFConverter{output: footnotePDF()}
+		service, err := NewConverter(withPDFConverter(pdfConv))
+		if err != nil {
+			t.Fatalf("NewConverter() unexpected error: %v", err)
+		}
+		t.Cleanup(func() { _ = service.Close() })
 
-	if err == nil {
-		t.Fatalf("Convert(%v, %v) error = nil, want error", ctx, input)
-	}
-	if !errors.Is(err, tocErr) {
-		t.Errorf("Convert(%v, %v) error should wrap %v, got %v", ctx, input, tocErr, err)
-	}
-	if !strings.Contains(err.Error(), "injecting TOC") {
-		t.Errorf("Convert(%v, %v) error should mention 'injecting TOC', got %q", ctx, input, err.Error())
-	}
-}
+		input := Input{Markdown: markdown, Footer: &Footer{ShowPageNumber: true},
+			Footnotes: &Footnotes{Placement: FootnotePlacementPage}}
+		result, err := service.Convert(context.Background(), input)
+		if err != nil {
+			t.Fatalf("Convert() unexpected error: %v", err)
+		}
+		if pdfConv.calls != 2 {
+			t.Fatalf("ToPDF() called %d times, want 2", pdfConv.calls)
+		}
+		if pdfConv.allOpts[0].Footnotes != nil {
+			t.Error("measurement render stamped footnotes")
+		}
+		layout := pdfConv.inputOpts.Footnotes
+		if layout == nil || len(layout.notes) != 2 || layout.band != 80 {
+			t.Fatalf("final render footnotes = %+v, want two notes in an 80pt band", layout)
+		}
+		if pdfConv.inputOpts.Numbering == nil {
+			t.Error("final render footer not stamped")
+		}
+		for _, want := range []string{"margin-bottom: 152.00pt;", `data-footnote="2"`} {
+			if !strings.Contains(pdfConv.inputHTML, want) {
+				t.Errorf("final render HTML missing %q", want)
+			}
+		}
+		if strings.Contains(pdfConv.inputHTML, "doc-endnotes") {
+			t.Error("final render HTML kept the endnote list")
+		}
+		if !strings.Contains(string(result.HTML), "doc-endnotes") {
+			t.Error("HTML output lost the endnote list")
+		}
+	})
 
-// ---------------------------------------------------------------------------
-// TestService_Convert_pdfOptionsTransmission - PDF Options Passing
-// ---------------------------------------------------------------------------
+	t.Run("per-page numbering renders three times", func(t *testing.T) {
+		t.Parallel()
 
-func TestService_Convert_pdfOptionsTransmission(t *testing.T) {
-	t.Parallel()
+		pdfConv := &mockPDFConverter{output: footnotePDF()}
+		service, err := NewConverter(withPDFConverter(pdfConv))
+		if err != nil {
+			t.Fatalf("NewConverter() unexpected error: %v", err)
+		}
+		t.Cleanup(func() { _ = service.Close() })
 
-	pdfConv := &mockPDFConverter{}
+		input := Input{Markdown: markdown, Footnotes: &Footnotes{Placement: FootnotePlacementPage, Numbering: FootnoteNumberingPage}}
+		if _, err := service.Convert(context.Background(), input); err != nil {
+			t.Fatalf("Convert() unexpected error: %v", err)
+		}
+		if pdfConv.calls != 3 {
+			t.Errorf("ToPDF() called %d times, want 3", pdfConv.calls)
+		}
+		if got := pdfConv.inputOpts.Footnotes.labels; !slices.Equal(got, []int{1, 2}) {
+			t.Errorf("labels = %v, want [1 2]", got)
+		}
+	})
 
-	service, err := New(
-		withPreprocessor(&mockPreprocessor{}),
-		withHTMLConverter(&mockHTMLConverter{}),
-		withCSSInjector(&mockCSSInjector{}),
-		withCoverInjector(&mockCoverInjector{}),
-		withTOCInjector(&mockTOCInjector{}),
-		withSignatureInjector(&mockSignatureInjector{}),
-		withPDFConverter(pdfConv),
-	)
-	if err != nil {
-		t.Fatalf("New() unexpected error: %v", err)
-	}
-	defer service.Close()
+	t.Run("end placement renders once", func(t *testing.T) {
+		t.Parallel()
 
-	ctx := context.Background()
-	input := Input{
-		Markdown: "# Test",
-		Page: &PageSettings{
-			Size:        PageSizeA4,
-			Orientation: OrientationLandscape,
-			Margin:      1.5,
-		},
-		Footer: &Footer{
-			Position:       "center",
-			ShowPageNumber: true,
-			Date:           "2025-01-15",
-			Status:         "FINAL",
-			Text:           "Confidential",
-		},
-	}
+		pdfConv := &mockPDFConverter{}
+		service, err := NewConverter(withPDFConverter(pdfConv))
+		if err != nil {
+			t.Fatalf("NewConverter() unexpected error: %v", err)
+		}
+		t.Cleanup(func() { _ = service.Close() })
 
-	_, err = service.Convert(ctx, input)
-	if err != nil {
-		t.Fatalf("Convert() unexpected error: %v", err)
-	}
+		input := Input{Markdown: markdown, Footnotes: &Footnotes{Placement: FootnotePlacementEnd}}
+		if _, err := service.Convert(context.Background(), input); err != nil {
+			t.Fatalf("Convert() unexpected error: %v", err)
+		}
+		if pdfConv.calls != 1 || pdfConv.inputOpts.Footnotes != nil || !strings.Contains(pdfConv.inputHTML, "doc-endnotes") {
+			t.Errorf("ToPDF() calls = %d, want one render with endnotes", pdfConv.calls)
+		}
+	})
 
-	if pdfConv.inputOpts == nil {
-		t.Fatal("PDF options not passed to converter")
-	}
+	t.Run("invalid settings are rejected", func(t *testing.T) {
+		t.Parallel()
 
-	// Verify page settings
-	if pdfConv.inputOpts.Page == nil {
-		t.Fatal("Page settings not passed to PDF converter")
-	}
-	if pdfConv.inputOpts.Page.Size != PageSizeA4 {
-		t.Errorf("Page.Size = %q, want %q", pdfConv.inputOpts.Page.Size, PageSizeA4)
-	}
-	if pdfConv.inputOpts.Page.Orientation != OrientationLandscape {
-		t.Errorf("Page.Orientation = %q, want %q", pdfConv.inputOpts.Page.Orientation, OrientationLandscape)
-	}
+		service, err := NewConverter(withPDFConverter(&mockPDFConverter{}))
+		if err != nil {
+			t.Fatalf("NewConverter() unexpected error: %v", err)
+		}
+		t.Cleanup(func() { _ = service.Close() })
 
-	// Verify footer data
-	if pdfConv.inputOpts.Footer == nil {
-		t.Fatal("Footer not passed to PDF converter")
-	}
-	if pdfConv.inputOpts.Footer.Position != "center" {
-		t.Errorf("Footer.Position = %q, want %q", pdfConv.inputOpts.Footer.Position, "center")
-	}
-	if !pdfConv.inputOpts.Footer.ShowPageNumber {
-		t.Error("Footer.ShowPageNumber = false, want true")
-	}
+		input := Input{Markdown: markdown, Footnotes: &Footnotes{Numbering: FootnoteNumberingPage}}
+		if _, err := service.Convert(context.Background(), input); !errors.Is(err, ErrInvalidFootnotes) {
+			t.Errorf("Convert() error = %v, want %v", err, ErrInvalidFootnotes)
+		}
+	})
 }
 
 // ---------------------------------------------------------------------------
@@ -2530,3 +4163,121 @@ func TestService_Convert_frontmatterWithCodeBlocks(t *testing.T) {
 		t.Error("HTML should contain code block tags")
 	}
 }
+
+// ---------------------------------------------------------------------------
+// TestService_ConvertMany - Merged Multi-Chapter Conversion
+// ---------------------------------------------------------------------------
+
+func TestService_ConvertMany(t *testing.T) {
+	t.Parallel()
+
+	t.Run("merges chapters with one TOC and PDF", func(t *testing.T) {
+		t.Parallel()
+
+		pdfConv := &mockPDFConverter{output: []byte("%PDF-1.4 merged")}
+		service, err := New(withPDFConverter(pdfConv))
+		if err != nil {
+			t.Fatalf("New() unexpected error: %v", err)
+		}
+		defer service.Close()
+
+		dir := filepath.Join(t.TempDir(), "book")
+		result, err := service.ConvertMany(context.Background(), Input{
+			TOC: &TOC{Title: "Contents", MinDepth: 1, MaxDepth: 2},
+		}, []Chapter{
+			{Markdown: "# Intro\n\nSee [install](install.md#setup).\n", Path: filepath.Join(dir, "intro.md")},
+			{Markdown: "---\ntitle: ignored\n---\n# Install\n\n## Setup\n", Path: filepath.Join(dir, "install.md")},
+			{Markdown: "# Usage\n\n## Setup\n", Path: filepath.Join(dir, "usage.md")},
+		})
+		if err != nil {
+			t.Fatalf("ConvertMany() unexpected error: %v", err)
+		}
+
+		if string(result.PDF) != "%PDF-1.4 merged" {
+			t.Errorf("ConvertMany().PDF = %q, want %q", result.PDF, "%PDF-1.4 merged")
+		}
+		html := string(result.HTML)
+		for _, want := range []string{
+			`id="chapter-1"`, `id="chapter-3"`,
+			`href="#setup"`, `id="setup-1"`,
+			`class="toc"`,
+		} {
+			if !strings.Contains(html, want) {
+				t.Errorf("ConvertMany().HTML missing %q", want)
+			}
+		}
+		if strings.Count(html, "<body>") != 1 {
+			t.Errorf("ConvertMany().HTML has %d <body> tags, want 1", strings.Count(html, "<body>"))
+		}
+		if strings.Contains(html, "ignored") {
+			t.Error("ConvertMany().HTML contains chapter frontmatter, want stripped")
+		}
+	})
+
+	t.Run("input markdown renders before chapters", func(t *testing.T) {
+		t.Parallel()
+
+		service, err := New(withPDFConverter(&mockPDFConverter{}))
+		if err != nil {
+			t.Fatalf("New() unexpected error: %v", err)
+		}
+		defer service.Close()
+
+		result, err := service.ConvertMany(context.Background(), Input{
+			Markdown: "Preface text",
+			HTMLOnly: true,
+		}, []Chapter{{Markdown: "Chapter text"}})
+		if err != nil {
+			t.Fatalf("ConvertMany() unexpected error: %v", err)
+		}
+
+		html := string(result.HTML)
+		preface, chapter := strings.Index(html, "Preface text"), strings.Index(html, "Chapter text")
+		if preface == -1 || chapter == -1 || preface > chapter {
+			t.Errorf("ConvertMany().HTML preface at %d, chapter at %d, want preface first", preface, chapter)
+		}
+		if result.PDF != nil {
+			t.Errorf("ConvertMany().PDF = %q, want nil for HTMLOnly", result.PDF)
+		}
+	})
+
+	tests := []struct {
+		name     string
+		input    Input
+		chapters []Chapter
+		wantErr  error
+	}{
+		{
+			name:    "no chapters",
+			wantErr: ErrNoChapters,
+		},
+		{
+			name:     "empty chapter",
+			chapters: []Chapter{{Markdown: "# One"}, {Markdown: ""}},
+			wantErr:  ErrEmptyMarkdown,
+		},
+		{
+			name:     "invalid settings",
+			input:    Input{TOC: &TOC{MinDepth: 4, MaxDepth: 2}},
+			chapters: []Chapter{{Markdown: "# One"}},
+			wantErr:  ErrInvalidTOCDepth,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run("error case: "+tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service, err := New(withPDFConverter(&mockPDFConverter{}))
+			if err != nil {
+				t.Fatalf("New() unexpected error: %v", err)
+			}
+			defer service.Close()
+
+			_, err = service.ConvertMany(context.Background(), tt.input, tt.chapters)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ConvertMany() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package picoloom
 import (
 	"fmt"
 	"strings"
+
+	"github.com/alnah/picoloom/v2/internal/pipeline"
 )
 
 // defaultFontFamily is the standard font stack for PDF footers and generated content.
@@ -135,3 +137,126 @@ h3 {
 
 	return buf.String()
 }
+
+// buildTOCPageNumbersCSS lays out TOC entries with page numbers: the entry
+// text, a dotted leader filling the line, then the right-aligned page.
+func buildTOCPageNumbersCSS() string {
+	return `
+/* TOC: page numbers with dot leaders */
+.toc-paged .toc-item a {
+  display: flex;
+  align-items: baseline;
+}
+.toc-paged .toc-leader {
+  flex: 1;
+  min-width: 1em;
+  margin: 0 0.4em;
+  border-bottom: 1px dotted currentColor;
+}
+.toc-paged .toc-page {
+  font-variant-numeric: tabular-nums;
+  text-align: right;
+}
+`
+}
+
+// buildMirroredMarginsCSS swaps the left and right margins on left-hand
+// pages when inner/outer margins are set. Chrome prints every page with the
+// right-hand page margins; the @page :left rule overrides them.
+// Returns empty string if margins are not mirrored.
+func buildMirroredMarginsCSS(page *PageSettings) string {
+	if page == nil || !page.Margins.mirrored() {
+		return ""
+	}
+	_, right, _, left := page.margins()
+	return fmt.Sprintf(`
+/* Mirrored margins: inner margin on the binding side */
+@page :left {
+  margin-left: %.4fin;
+  margin-right: %.4fin;
+}
+`, right, left)
+}
+
+// buildLandscapeCSS prints landscape sections on rotated named pages. The
+// default page gets an explicit size too, since Chrome honors @page sizes
+// for every page once the document sets any.
+func buildLandscapeCSS(page *PageSettings) string {
+	w, h := page.dimensions()
+	long, short := max(w, h), min(w, h)
+	return fmt.Sprintf(`
+/* Landscape sections: rotated named pages */
+@page {
+  size: %.4fin %.4fin;
+}
+@page %s {
+  size: %.4fin %.4fin;
+}
+section.%s {
+  page: %s;
+}
+`, w, h, pipeline.LandscapeClass, long, short, pipeline.LandscapeClass, pipeline.LandscapeClass)
+}
+
+// buildMathCSS keeps display equations on one page. It follows the KaTeX
+// stylesheet, which sets the fonts and layout.
+func buildMathCSS() string {
+	return `
+/* Math: rendered $$...$$ equations */
+.math-display {
+  margin: 1em 0;
+  break-inside: avoid;
+  page-break-inside: avoid;
+}
+.math-display .katex-display {
+  margin: 0;
+}
+`
+}
+
+// buildPageFootnotesCSS lays out page footnotes: notes follow each other
+// without margins, so their measured heights add up, and none is split
+// across pages. The measurement area starts a page of its own. Themes force
+// list margins for Chrome, hence the !important.
+func buildPageFootnotesCSS() string {
+	return `
+/* Footnotes: notes at the foot of the page */
+.footnotes.page-footnotes {
+  margin: 0;
+}
+.page-footnotes ol {
+  margin: 0 !important;
+}
+.page-footnotes li {
+  margin: 0 !important;
+  padding: 0;
+  break-inside: avoid;
+  page-break-inside: avoid;
+}
+.page-footnotes li > p {
+  margin: 0;
+}
+.page-footnotes-measure {
+  display: flow-root;
+  break-before: page;
+  page-break-before: always;
+}
+`
+}
+
+// buildDiagramsCSS centers rendered diagrams and keeps each on one page.
+func buildDiagramsCSS() string {
+	return `
+/* Diagrams: rendered mermaid fences */
+figure.diagram {
+  margin: 1em 0;
+  text-align: center;
+  break-inside: avoid;
+  page-break-inside: avoid;
+}
+figure.diagram svg {
+  max-width: 100%;
+  height: auto;
+}
+`
+}
@@ -5,6 +5,8 @@ package picoloom
 // - buildWatermarkCSS: tests watermark CSS generation with escaping
 // - breakURLPattern: tests URL pattern breaking with dot leader replacement
 // - buildPageBreaksCSS: tests page break CSS generation for headings and orphans/widows
+// - buildMirroredMarginsCSS: tests left-hand page margins for inner/outer margins
+// - buildLandscapeCSS: tests named page sizes for landscape sections
 
 import (
 	"strings"
@@ -437,3 +439,91 @@ func TestBuildPageBreaksCSS(t *testing.T) {
 		})
 	}
 }
+
+// ---------------------------------------------------------------------------
+// TestBuildMirroredMarginsCSS - Inner/Outer Margins
+// ---------------------------------------------------------------------------
+
+func TestBuildMirroredMarginsCSS(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		page *PageSettings
+		want string
+	}{
+		{"nil page", nil, ""},
+		{"uniform margin", &PageSettings{Margin: 1}, ""},
+		{"left and right", &PageSettings{Margins: &Margins{Left: "1in", Right: "2in"}}, ""},
+		{
+			"inner and outer swap on left-hand pages",
+			&PageSettings{Margins: &Margins{Inner: "1.5in", Outer: "18mm"}},
+			"@page :left {\n  margin-left: 0.7087in;\n  margin-right: 1.5000in;\n}",
+		},
+		{
+			"missing outer uses margin",
+			&PageSettings{Margin: 0.75, Margins: &Margins{Inner: "1in"}},
+			"margin-left: 0.7500in;\n  margin-right: 1.0000in;",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := buildMirroredMarginsCSS(tt.page)
+			if tt.want == "" {
+				if got != "" {
+					t.Errorf("buildMirroredMarginsCSS() = %q, want empty", got)
+				}
+				return
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("buildMirroredMarginsCSS() = %q, want to contain %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestBuildLandscapeCSS - Landscape Named Pages
+// ---------------------------------------------------------------------------
+
+func TestBuildLandscapeCSS(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		page *PageSettings
+		want []string
+	}{
+		{
+			"default letter",
+			nil,
+			[]string{"@page {\n  size: 8.5000in 11.0000in;", "@page landscape {\n  size: 11.0000in 8.5000in;", "section.landscape {\n  page: landscape;"},
+		},
+		{
+			"custom size",
+			&PageSettings{Width: "6in", Height: "9in"},
+			[]string{"size: 6.0000in 9.0000in;", "size: 9.0000in 6.0000in;"},
+		},
+		{
+			"landscape document keeps landscape sections wide",
+			&PageSettings{Size: PageSizeA4, Orientation: OrientationLandscape},
+			[]string{"@page {\n  size: 11.6900in 8.2700in;", "@page landscape {\n  size: 11.6900in 8.2700in;"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := buildLandscapeCSS(tt.page)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("buildLandscapeCSS() = %q, want to contain %q", got, want)
+				}
+			}
+		})
+	}
+}
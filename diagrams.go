@@ -0,0 +1,56 @@
+package picoloom
+
+import (
+	"context"
+
+	"github.com/alnah/picoloom/v2/internal/assets"
+	"github.com/alnah/picoloom/v2/internal/pipeline"
+)
+
+// mermaidRenderScript renders each definition in turn and reports the first
+// one Mermaid rejects. securityLevel "strict" disables click handlers and
+// HTML in labels.
+const mermaidRenderScript = `async (sources, theme) => {
+  mermaid.initialize({ startOnLoad: false, theme: theme, securityLevel: "strict" });
+  const svgs = [];
+  for (let i = 0; i < sources.length; i++) {
+    try {
+      const { svg } = await mermaid.render("picoloom-mermaid-" + i, sources[i]);
+      svgs.push(svg);
+    } catch (e) {
+      return { svgs: svgs, failed: i, message: String((e && e.message) || e) };
+    }
+  }
+  return { svgs: svgs, failed: -1 };
+}`
+
+// mermaidResult is the value returned by mermaidRenderScript.
+type mermaidResult struct {
+	SVGs    []string `json:"svgs"`
+	Failed  int      `json:"failed"`
+	Message string   `json:"message"`
+}
+
+// RenderMermaid renders Mermaid definitions in the converter's browser.
+func (c *rodConverter) RenderMermaid(ctx context.Context, sources []string, theme string) ([]string, error) {
+	return c.renderer.RenderMermaid(ctx, sources, theme)
+}
+
+// RenderMermaid renders Mermaid definitions to SVG with the embedded bundle.
+func (r *rodRenderer) RenderMermaid(ctx context.Context, sources []string, theme string) ([]string, error) {
+	script, err := assets.MermaidScript()
+	if err != nil {
+		return nil, err
+	}
+	if theme == "" {
+		theme = DiagramThemeDefault
+	}
+	var res mermaidResult
+	if err := r.evalBundle(ctx, script, mermaidRenderScript, &res, sources, theme); err != nil {
+		return nil, err
+	}
+	if res.Failed >= 0 {
+		return nil, &pipeline.MermaidError{Index: res.Failed, Message: res.Message}
+	}
+	return res.SVGs, nil
+}
@@ -30,6 +30,8 @@
 //  3. HTML injection (CSS, cover page, TOC, signature block)
 //  4. PDF rendering via headless Chrome (go-rod)
 //
+// Pipeline hooks can add stages around them (see Pipeline Hooks).
+//
 // # Configuration
 //
 // Use functional options to customize the converter:
@@ -38,6 +40,7 @@
 //	    picoloom.WithTimeout(2 * time.Minute),
 //	    picoloom.WithStyle("technical"),
 //	    picoloom.WithAssetPath("/path/to/custom/assets"),
+//	    picoloom.WithPDFA(), // PDF/A-2b archival output
 //	)
 //
 // Per-conversion options are passed via Input:
@@ -48,12 +51,53 @@
 //	    CSS:       "body { font-size: 14px; }",
 //	    Page:      &picoloom.PageSettings{Size: "a4"},
 //	    Footer:    &picoloom.Footer{ShowPageNumber: true},
+//	    Header:    &picoloom.Header{Left: "{title}", Right: "{pageNumber}"},
 //	    Cover:     &picoloom.Cover{Title: "Report"},
+//	    Metadata:  &picoloom.Metadata{Keywords: []string{"report"}},
 //	    TOC:       &picoloom.TOC{Title: "Contents"},
 //	    Watermark: &picoloom.Watermark{Text: "DRAFT"},
 //	    Signature: &picoloom.Signature{Name: "John Doe"},
 //	})
 //
+// # Pipeline Hooks
+//
+// Hooks extend the pipeline without replacing its stages. They run in this
+// order around the built-in stages:
+//
+//  1. WithMarkdownTransform, on input.Markdown and each chapter, after
+//     includes and variables and before citations, diagrams and the Markdown
+//     syntax picoloom adds (highlights, landscape sections, ...).
+//  2. WithGoldmarkExtensions, after the built-in extensions, when the
+//     Markdown is converted to HTML.
+//  3. WithHTMLTransform, on the converted document before styles, cover,
+//     TOC, index, lists and signature are added. Raw HTML is already
+//     sanitized, so transforms may add any markup.
+//  4. WithPDFPostProcessor, on the final PDF once footers, headers and
+//     footnotes are stamped, before metadata, outline, PDF/A, encryption and
+//     the digital signature. Drafts printed to locate pages skip it.
+//
+// Hooks of one kind run in the order their options are given, and an error
+// stops the conversion, wrapped in ErrHook. A ConverterPool passes the same
+// hooks to every converter, so they may be called concurrently:
+//
+//	conv, err := picoloom.NewConverter(
+//	    picoloom.WithGoldmarkExtensions(emoji.Emoji),
+//	    picoloom.WithHTMLTransform(picoloom.HTMLTransformFunc(addBanner)),
+//	)
+//
+// # Merging Chapters
+//
+// ConvertMany renders several Markdown files as one document, with a single
+// cover and a TOC spanning every chapter. Links between chapter files become
+// in-document links:
+//
+//	result, err := conv.ConvertMany(ctx, picoloom.Input{
+//	    TOC: &picoloom.TOC{Title: "Contents"},
+//	}, []picoloom.Chapter{
+//	    {Markdown: intro, Path: "book/intro.md"},
+//	    {Markdown: usage, Path: "book/usage.md"},
+//	})
+//
 // # Parallel Processing
 //
 // For batch conversion, use ConverterPool to manage multiple browser instances:
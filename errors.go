@@ -10,6 +10,8 @@ var (
 	ErrBrowserConnect  = errors.New("failed to connect to browser")
 	ErrPageCreate      = errors.New("failed to create browser page")
 	ErrPageLoad        = errors.New("failed to load page")
+	ErrPDFPostProcess  = errors.New("PDF post-processing failed")
+	ErrPDFAConformance = errors.New("document cannot conform to PDF/A-2b")
 	ErrSignatureRender = errors.New("signature template rendering failed")
 
 	// Page settings validation errors.
@@ -20,6 +22,43 @@ var (
 	// Footer validation errors.
 	ErrInvalidFooterPosition = errors.New("invalid footer position")
 
+	// Header validation errors.
+	ErrInvalidHeaderPlaceholder = errors.New("invalid header placeholder")
+
+	// Page numbering validation errors.
+	ErrInvalidPageNumbering = errors.New("invalid page numbering")
+
+	// Diagram errors.
+	ErrInvalidDiagramTheme = errors.New("invalid diagram theme")
+	ErrDiagramRender       = errors.New("diagram rendering failed")
+
+	// Math errors.
+	ErrMathRender = errors.New("math rendering failed")
+
+	// Raw HTML errors.
+	ErrInvalidRawHTML = errors.New("invalid raw HTML settings")
+
+	// Include errors.
+	ErrInclude = errors.New("include failed")
+
+	// Variable errors.
+	ErrInvalidVariable = errors.New("invalid variable name")
+	ErrInterpolation   = errors.New("variable interpolation failed")
+
+	// Cross-reference errors.
+	ErrCrossReference = errors.New("cross-reference resolution failed")
+
+	// Citation errors.
+	ErrInvalidBibliography = errors.New("invalid bibliography settings")
+	ErrCitation            = errors.New("citation processing failed")
+
+	// Footnote errors.
+	ErrInvalidFootnotes = errors.New("invalid footnote settings")
+	ErrFootnoteLayout   = errors.New("footnote layout failed")
+
+	// Hook errors.
+	ErrHook = errors.New("pipeline hook failed")
+
 	// Watermark validation errors.
 	ErrInvalidWatermarkColor = errors.New("invalid watermark color")
 
@@ -27,16 +66,38 @@ var (
 	ErrCoverLogoNotFound = errors.New("cover logo file not found")
 	ErrCoverRender       = errors.New("cover template rendering failed")
 
+	// Footer and header template errors.
+	ErrPageTemplateRender = errors.New("page template rendering failed")
+
 	// Signature validation errors.
 	ErrSignatureImageNotFound = errors.New("signature image file not found")
 
 	// TOC validation errors.
 	ErrInvalidTOCDepth = errors.New("invalid TOC depth")
 
+	// Outline validation errors.
+	ErrInvalidOutlineDepth = errors.New("invalid outline depth")
+
 	// Page breaks validation errors.
 	ErrInvalidOrphans = errors.New("invalid orphans value")
 	ErrInvalidWidows  = errors.New("invalid widows value")
 
+	// Encryption errors.
+	ErrInvalidEncryption = errors.New("invalid encryption settings")
+	ErrPDFEncryption     = errors.New("PDF encryption failed")
+
+	// Digital signature errors.
+	ErrInvalidDigitalSignature = errors.New("invalid digital signature settings")
+	ErrSigningCredentials      = errors.New("cannot load signing credentials")
+	ErrPDFSigning              = errors.New("PDF signing failed")
+	ErrSignatureVerification   = errors.New("digital signature verification failed")
+
+	// Merge errors.
+	ErrNoChapters = errors.New("no chapters to merge")
+
+	// Frontmatter parsing errors.
+	ErrInvalidFrontmatter = errors.New("invalid frontmatter")
+
 	// Asset loading errors.
 	ErrStyleNotFound         = errors.New("style not found")
 	ErrTemplateSetNotFound   = errors.New("template set not found")
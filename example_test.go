@@ -283,6 +283,42 @@ func ExampleNewConverter_withStyle() {
 	// Output: Technical style applied
 }
 
+// ExampleWithMarkdownTransform demonstrates custom syntax with pipeline hooks.
+func ExampleWithMarkdownTransform() {
+	conv, err := picoloom.NewConverter(
+		picoloom.WithMarkdownTransform(picoloom.MarkdownTransformFunc(
+			func(_ context.Context, markdown string) (string, error) {
+				return strings.ReplaceAll(markdown, "(c)", "©"), nil
+			},
+		)),
+		picoloom.WithHTMLTransform(picoloom.HTMLTransformFunc(
+			func(_ context.Context, html string) (string, error) {
+				return strings.Replace(html, "<body>", `<body><p class="brand">ACME</p>`, 1), nil
+			},
+		)),
+	)
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	defer conv.Close()
+
+	result, err := conv.Convert(context.Background(), picoloom.Input{
+		Markdown: "# Notice\n\n(c) ACME Corp.",
+		HTMLOnly: true,
+	})
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+
+	html := string(result.HTML)
+	if strings.Contains(html, "© ACME Corp.") && strings.Contains(html, `<p class="brand">ACME</p>`) {
+		fmt.Println("Hooks applied")
+	}
+	// Output: Hooks applied
+}
+
 // ExampleConverterPool demonstrates parallel batch processing.
 func ExampleConverterPool() {
 	pool := picoloom.NewConverterPool(2)
@@ -0,0 +1,380 @@
+package picoloom
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/alnah/picoloom/v2/internal/assets"
+	"github.com/alnah/picoloom/v2/internal/pdfedit"
+	"github.com/alnah/picoloom/v2/internal/pipeline"
+)
+
+// footnoteSlack absorbs rounding in destination coordinates, in points.
+const footnoteSlack = 0.5
+
+// maxFootnoteBand is the largest share of the page area the footnote band
+// may take.
+const maxFootnoteBand = 0.5
+
+// footnoteLayout places page footnotes from the measurements of a first
+// render. Lengths are in points.
+type footnoteLayout struct {
+	notes   []pipeline.Footnote
+	labels  []int     // Label printed for each note
+	heights []float64 // Height of each note
+	rule    float64   // From the top of the area to the first note: the separator
+	tail    float64   // From the end of the last note to the bottom of the area
+	band    float64   // Height reserved at the foot of every page
+
+	// Page area margins of a right-hand page. Left-hand pages swap left and
+	// right when margins are mirrored.
+	left, right, bottom float64
+	mirrored            bool
+
+	// sheet is an empty document carrying the stylesheet the notes are
+	// printed with.
+	sheet string
+}
+
+// renderFootnotedPDF prints htmlContent with its footnotes at the foot of
+// the page. A first render measures the notes on a page of their own and
+// locates their references; the band they need is then reserved at the
+// bottom of every page, by raising the @page bottom margin, and the notes
+// of each page are stamped into it after printing. Per-page numbering takes
+// one more render, since labels follow the pages of the reserved layout.
+func (c *Converter) renderFootnotedPDF(ctx context.Context, htmlContent string, input Input, final bool) ([]byte, error) {
+	body, notes, err := pipeline.ExtractFootnotes(htmlContent)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFootnoteLayout, err)
+	}
+	if len(notes) == 0 {
+		return c.printPDF(ctx, htmlContent, input, nil, final)
+	}
+
+	labels := make([]int, len(notes))
+	for i := range labels {
+		labels[i] = i + 1
+	}
+	draft := input
+	draft.Encryption = nil
+	pdfBytes, err := c.printPDF(ctx, pipeline.FootnoteMeasurement(body, notes, labels), draft, nil, false)
+	if err != nil {
+		return nil, err
+	}
+	layout, err := measureFootnotes(pdfBytes, notes, input.Page)
+	if err != nil {
+		return nil, err
+	}
+	layout.labels = labels
+	if layout.sheet, err = c.footnoteSheet(ctx, htmlContent, input); err != nil {
+		return nil, err
+	}
+
+	body = c.cssInjector.InjectCSS(ctx, body, layout.reserveCSS())
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if strings.EqualFold(input.Footnotes.Numbering, FootnoteNumberingPage) {
+		if pdfBytes, err = c.printPDF(ctx, body, draft, nil, false); err != nil {
+			return nil, err
+		}
+		doc, pages, err := openPages(pdfBytes)
+		if err != nil {
+			return nil, fmt.Errorf("%w: locating footnotes: %w", ErrFootnoteLayout, err)
+		}
+		refPages, err := footnotePages(doc, pages, notes)
+		if err != nil {
+			return nil, fmt.Errorf("%w: locating footnotes: %w", ErrFootnoteLayout, err)
+		}
+		layout.labels = pageLabels(layout.assign(refPages, len(pages)), len(notes))
+	}
+	return c.printPDF(ctx, pipeline.NumberFootnotes(body, layout.labels), input, layout, final)
+}
+
+// footnoteSheet returns the empty document footnotes are printed on: the
+// document's theme, custom CSS and math styles, without its page rules.
+func (c *Converter) footnoteSheet(ctx context.Context, htmlContent string, input Input) (string, error) {
+	baseCSS, err := c.documentStyle(input)
+	if err != nil {
+		return "", err
+	}
+	css := buildPageFootnotesCSS() + baseCSS
+	if input.CSS != "" {
+		css += "\n" + input.CSS
+	}
+	if pipeline.HasRenderedMath(htmlContent) {
+		katexCSS, err := assets.KatexCSS()
+		if err != nil {
+			return "", fmt.Errorf("%w: %w", ErrMathRender, err)
+		}
+		css = katexCSS + buildMathCSS() + css
+	}
+	return c.cssInjector.InjectCSS(ctx, "<!DOCTYPE html>\n<html><head><meta charset=\"UTF-8\"></head><body></body></html>\n", css), nil
+}
+
+// destinationMark is a named destination located on its page.
+type destinationMark struct {
+	page int // 1-based
+	x, y float64
+}
+
+// measureFootnotes reads the markers of pipeline.FootnoteMeasurement from
+// data: the height of each note and of the separator, the width of the
+// area, and the bottom of the page area, and sizes the band from the pages
+// of the references. The bottom of the page area falls back to the margin
+// of page when Chrome wrote no destination for it.
+func measureFootnotes(data []byte, notes []pipeline.Footnote, page *PageSettings) (*footnoteLayout, error) {
+	doc, pages, err := openPages(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: measuring footnotes: %w", ErrFootnoteLayout, err)
+	}
+	named, err := doc.NamedDestinations()
+	if err != nil {
+		return nil, fmt.Errorf("%w: measuring footnotes: %w", ErrFootnoteLayout, err)
+	}
+	mark := func(id string) (destinationMark, bool) {
+		dest, ok := named[id]
+		if !ok {
+			return destinationMark{}, false
+		}
+		x, y, ok := pdfedit.DestinationPoint(dest)
+		return destinationMark{page: pdfedit.PageNumber(dest, pages), x: x, y: y}, ok
+	}
+
+	start, okStart := mark(pipeline.FootnoteAreaStartID)
+	end, okEnd := mark(pipeline.FootnoteAreaEndID)
+	right, okRight := mark(pipeline.FootnoteAreaRightID)
+	if !okStart || !okEnd || !okRight || start.page == 0 {
+		return nil, fmt.Errorf("%w: footnote area not found in the PDF", ErrFootnoteLayout)
+	}
+
+	l := &footnoteLayout{notes: notes, heights: make([]float64, len(notes)), mirrored: page != nil && page.Margins.mirrored()}
+	var first, last destinationMark
+	for i := range notes {
+		startID, endID := pipeline.FootnoteMeasurementIDs(i + 1)
+		s, okS := mark(startID)
+		e, okE := mark(endID)
+		if !okS || !okE {
+			return nil, fmt.Errorf("%w: footnote %d not found in the PDF", ErrFootnoteLayout, i+1)
+		}
+		if s.page != e.page {
+			return nil, fmt.Errorf("%w: footnote %d does not fit on one page", ErrFootnoteLayout, i+1)
+		}
+		l.heights[i] = s.y - e.y
+		if i == 0 {
+			first = s
+		}
+		last = e
+	}
+	if first.page == start.page {
+		l.rule = start.y - first.y
+	}
+	if last.page == end.page {
+		l.tail = last.y - end.y
+	}
+
+	width, _, err := doc.PageSize(pages[start.page-1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: measuring footnotes: %w", ErrFootnoteLayout, err)
+	}
+	l.left, l.right = start.x, width-right.x
+	if l.mirrored && start.page%2 == 0 {
+		l.left, l.right = l.right, l.left
+	}
+	if bottom, ok := mark(pipeline.FootnotePageBottomID); ok {
+		l.bottom = bottom.y
+	} else {
+		_, _, margin, _ := page.margins()
+		l.bottom = margin * 72
+	}
+
+	refPages, err := footnotePages(doc, pages, notes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: locating footnotes: %w", ErrFootnoteLayout, err)
+	}
+	if err := l.sizeBand(refPages, start.y-l.bottom); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// sizeBand reserves the height of the fullest page, with each note on the
+// page of its reference, up to maxFootnoteBand of areaHeight. Notes past
+// that continue on the next page; a single note taller than that is an
+// error.
+func (l *footnoteLayout) sizeBand(refPages []int, areaHeight float64) error {
+	limit := areaHeight * maxFootnoteBand
+	perPage := make(map[int]float64)
+	for i, h := range l.heights {
+		if single := l.rule + h + l.tail; single > limit {
+			return fmt.Errorf("%w: footnote %d is taller than half the page", ErrFootnoteLayout, i+1)
+		} else if single > l.band {
+			l.band = single
+		}
+		if perPage[refPages[i]] == 0 {
+			perPage[refPages[i]] = l.rule + l.tail
+		}
+		perPage[refPages[i]] += h
+	}
+	for _, h := range perPage {
+		l.band = max(l.band, min(h, limit))
+	}
+	l.band = math.Ceil(l.band)
+	return nil
+}
+
+// reserveCSS raises the bottom margin of every page by the band.
+func (l *footnoteLayout) reserveCSS() string {
+	return fmt.Sprintf(`
+/* Page footnotes: band reserved at the foot of every page */
+@page {
+  margin-bottom: %.2fpt;
+}
+`, l.bottom+l.band)
+}
+
+// assign distributes the notes over pageCount pages: each note goes to the
+// page of its first reference (refPages, 1-based; 0 when unknown follows the
+// previous note), or to the next page when earlier notes fill the band.
+// Notes carried past the last page stay on it.
+// Returns the indexes of the notes of each page, in order.
+func (l *footnoteLayout) assign(refPages []int, pageCount int) [][]int {
+	placed := make([][]int, pageCount)
+	if pageCount == 0 {
+		return placed
+	}
+	page, used := 0, 0.0
+	for i, h := range l.heights {
+		if p := refPages[i] - 1; p > page && p < pageCount {
+			page, used = p, 0
+		}
+		if used > 0 && used+h > l.band+footnoteSlack && page < pageCount-1 {
+			page, used = page+1, 0
+		}
+		if used == 0 {
+			used = l.rule + l.tail
+		}
+		placed[page] = append(placed[page], i)
+		used += h
+	}
+	return placed
+}
+
+// pageLabels numbers the notes from 1 on every page of placed.
+func pageLabels(placed [][]int, count int) []int {
+	labels := make([]int, count)
+	for _, notes := range placed {
+		for n, i := range notes {
+			labels[i] = n + 1
+		}
+	}
+	return labels
+}
+
+// openPages opens a PDF and lists its pages.
+func openPages(data []byte) (*pdfedit.Document, []pdfedit.Ref, error) {
+	doc, err := pdfedit.Open(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	pages, err := doc.Pages()
+	if err != nil {
+		return nil, nil, err
+	}
+	return doc, pages, nil
+}
+
+// footnotePages returns the 1-based page of each note's first reference, or
+// 0 when the PDF has no destination for it.
+func footnotePages(doc *pdfedit.Document, pages []pdfedit.Ref, notes []pipeline.Footnote) ([]int, error) {
+	named, err := doc.NamedDestinations()
+	if err != nil {
+		return nil, err
+	}
+	refPages := make([]int, len(notes))
+	for i, note := range notes {
+		if dest, ok := named[note.RefID]; ok {
+			refPages[i] = pdfedit.PageNumber(dest, pages)
+		}
+	}
+	return refPages, nil
+}
+
+// applyFootnotes stamps the footnotes of opts onto the pages referencing
+// them. Like page numbers, the notes of each page are laid out on a stamp
+// sheet printed by renderSheet, in the band the document reserves for them.
+// Returns data unchanged if opts has no footnotes.
+func applyFootnotes(data []byte, opts *pdfOptions, renderSheet func(sheet string) ([]byte, error)) ([]byte, error) {
+	if opts == nil || opts.Footnotes == nil {
+		return data, nil
+	}
+
+	doc, pages, err := openPages(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrPDFPostProcess, err)
+	}
+	refPages, err := footnotePages(doc, pages, opts.Footnotes.notes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: locating footnotes: %w", ErrPDFPostProcess, err)
+	}
+	sizes := make([][2]float64, len(pages))
+	for i, page := range pages {
+		if sizes[i][0], sizes[i][1], err = doc.PageSize(page); err != nil {
+			return nil, fmt.Errorf("%w: page %d size: %w", ErrPDFPostProcess, i+1, err)
+		}
+	}
+
+	placed := opts.Footnotes.assign(refPages, len(pages))
+	sheetPDF, err := renderSheet(buildFootnoteSheet(opts.Footnotes, placed, sizes))
+	if err != nil {
+		return nil, fmt.Errorf("printing footnotes: %w", err)
+	}
+	update := doc.NewUpdate()
+	if err := stampPages(update, pages, sheetPDF); err != nil {
+		return nil, fmt.Errorf("%w: footnotes: %w", ErrPDFPostProcess, err)
+	}
+	return update.Bytes(), nil
+}
+
+// buildFootnoteSheet lays out the notes placed on every page on a page of
+// the same size (in points), at the bottom of the band above the bottom
+// margin, across the page area.
+func buildFootnoteSheet(l *footnoteLayout, placed [][]int, sizes [][2]float64) string {
+	var css, body strings.Builder
+	css.WriteString("@page { margin: 0; }\n")
+	css.WriteString("html, body { margin: 0; padding: 0; max-width: none; background: none; }\n")
+	css.WriteString(".sheet { position: relative; overflow: hidden; break-after: page; }\n")
+	css.WriteString(".sheet:last-child { break-after: auto; }\n")
+	css.WriteString(".footnote-band { position: absolute; }\n")
+
+	named := make(map[[2]float64]string)
+	for i, size := range sizes {
+		name, ok := named[size]
+		if !ok {
+			name = fmt.Sprintf("size%d", len(named))
+			named[size] = name
+			fmt.Fprintf(&css, "@page %s { size: %.2fpt %.2fpt; }\n", name, size[0], size[1])
+		}
+
+		fmt.Fprintf(&body, `<div class="sheet" style="page: %s; width: %.2fpt; height: %.2fpt;">`, name, size[0], size[1])
+		if i < len(placed) && len(placed[i]) > 0 {
+			left, right := l.left, l.right
+			if l.mirrored && i%2 == 1 {
+				left, right = right, left
+			}
+			notes := make([]pipeline.Footnote, len(placed[i]))
+			labels := make([]int, len(placed[i]))
+			for n, note := range placed[i] {
+				notes[n], labels[n] = l.notes[note], l.labels[note]
+			}
+			fmt.Fprintf(&body, `<div class="footnote-band" style="left: %.2fpt; bottom: %.2fpt; width: %.2fpt;">%s</div>`,
+				left, l.bottom, size[0]-left-right, pipeline.FootnoteArea(notes, labels))
+		}
+		body.WriteString("</div>\n")
+	}
+
+	sheet := strings.Replace(l.sheet, "</head>", "<style>\n"+css.String()+"</style></head>", 1)
+	return strings.Replace(sheet, "<body></body>", "<body>\n"+body.String()+"</body>", 1)
+}
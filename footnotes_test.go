@@ -0,0 +1,235 @@
+package picoloom
+
+// Notes:
+// - Measurements are read from hand-built PDFs whose named destinations
+//   stand in for the markers Chrome prints
+// - applyFootnotes is tested with a fake stamp sheet renderer, as
+//   applyPageNumbering is
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/alnah/picoloom/v2/internal/pdfedit"
+	"github.com/alnah/picoloom/v2/internal/pipeline"
+)
+
+// footnotePDF returns a three-page letter PDF with two references on page 1
+// and the measurement area on page 3: the separator takes 10pt, the notes
+// 20pt and 40pt, the end of the area 10pt, with 72pt margins.
+func footnotePDF() []byte {
+	start1, end1 := pipeline.FootnoteMeasurementIDs(1)
+	start2, end2 := pipeline.FootnoteMeasurementIDs(2)
+	dests := fmt.Sprintf("/fnref:1 [3 0 R /XYZ 100 500 0] /fnref:2 [3 0 R /XYZ 100 400 0] "+
+		"/%s [5 0 R /XYZ 72 720 0] /%s [5 0 R /XYZ 72 710 0] /%s [5 0 R /XYZ 72 690 0] "+
+		"/%s [5 0 R /XYZ 72 690 0] /%s [5 0 R /XYZ 72 650 0] /%s [5 0 R /XYZ 72 640 0] "+
+		"/%s [5 0 R /XYZ 540 640 0] /%s [3 0 R /XYZ 0 72 0]",
+		pipeline.FootnoteAreaStartID, start1, end1, start2, end2,
+		pipeline.FootnoteAreaEndID, pipeline.FootnoteAreaRightID, pipeline.FootnotePageBottomID)
+	return buildTestPDF("",
+		"<</Type /Catalog /Pages 2 0 R /Dests <<"+dests+">>>>",
+		"<</Type /Pages /Count 3 /Kids [3 0 R 4 0 R 5 0 R] /MediaBox [0 0 612 792]>>",
+		"<</Type /Page /Parent 2 0 R>>",
+		"<</Type /Page /Parent 2 0 R>>",
+		"<</Type /Page /Parent 2 0 R>>",
+	)
+}
+
+// testFootnotes returns two notes referenced as in footnotePDF.
+func testFootnotes() []pipeline.Footnote {
+	return []pipeline.Footnote{
+		{RefID: "fnref:1", HTML: "<p>First note.</p>"},
+		{RefID: "fnref:2", HTML: "<p>Second note.</p>"},
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestMeasureFootnotes - Note heights and page area from markers
+// ---------------------------------------------------------------------------
+
+func TestMeasureFootnotes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reads heights, margins and band", func(t *testing.T) {
+		t.Parallel()
+
+		l, err := measureFootnotes(footnotePDF(), testFootnotes(), nil)
+		if err != nil {
+			t.Fatalf("measureFootnotes() unexpected error: %v", err)
+		}
+		if !slices.Equal(l.heights, []float64{20, 40}) {
+			t.Errorf("heights = %v, want [20 40]", l.heights)
+		}
+		got := [6]float64{l.rule, l.tail, l.left, l.right, l.bottom, l.band}
+		want := [6]float64{10, 10, 72, 72, 72, 80}
+		if got != want {
+			t.Errorf("rule, tail, left, right, bottom, band = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("note taller than half the page is an error", func(t *testing.T) {
+		t.Parallel()
+
+		l := &footnoteLayout{heights: []float64{20, 320}, rule: 10, tail: 10}
+		if err := l.sizeBand([]int{1, 1}, 648); !errors.Is(err, ErrFootnoteLayout) {
+			t.Errorf("sizeBand() error = %v, want %v", err, ErrFootnoteLayout)
+		}
+	})
+
+	t.Run("missing area is an error", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := measureFootnotes(numberedPDF(), testFootnotes(), nil); !errors.Is(err, ErrFootnoteLayout) {
+			t.Errorf("measureFootnotes() error = %v, want %v", err, ErrFootnoteLayout)
+		}
+	})
+}
+
+// ---------------------------------------------------------------------------
+// TestFootnoteLayoutAssign - Notes per page with carry-over
+// ---------------------------------------------------------------------------
+
+func TestFootnoteLayoutAssign(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		heights  []float64
+		refPages []int
+		pages    int
+		want     [][]int
+	}{
+		{"notes on their pages", []float64{20, 20, 20}, []int{1, 1, 3}, 3, [][]int{{0, 1}, nil, {2}}},
+		{"full band carries over", []float64{40, 40, 10}, []int{1, 1, 2}, 3, [][]int{{0}, {1, 2}, nil}},
+		{"unknown page follows previous note", []float64{10, 10}, []int{2, 0}, 2, [][]int{nil, {0, 1}}},
+		{"last page keeps overflow", []float64{40, 40}, []int{1, 1}, 1, [][]int{{0, 1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			l := &footnoteLayout{heights: tt.heights, rule: 5, tail: 5, band: 60}
+			got := l.assign(tt.refPages, tt.pages)
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("assign() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageLabels(t *testing.T) {
+	t.Parallel()
+
+	got := pageLabels([][]int{{0, 1}, nil, {2, 3, 4}}, 5)
+	if want := []int{1, 2, 1, 2, 3}; !slices.Equal(got, want) {
+		t.Errorf("pageLabels() = %v, want %v", got, want)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestBuildFootnoteSheet - Notes laid out per page
+// ---------------------------------------------------------------------------
+
+func TestBuildFootnoteSheet(t *testing.T) {
+	t.Parallel()
+
+	l := &footnoteLayout{
+		notes:    testFootnotes(),
+		labels:   []int{1, 1},
+		left:     90,
+		right:    54,
+		bottom:   72,
+		mirrored: true,
+		sheet:    "<html><head><style>p{}</style></head><body></body></html>",
+	}
+	sheet := buildFootnoteSheet(l, [][]int{{0}, {1}}, [][2]float64{{612, 792}, {612, 792}})
+
+	for _, want := range []string{
+		"<style>p{}</style><style>\n@page { margin: 0; }",
+		"@page size0 { size: 612.00pt 792.00pt; }",
+		`style="left: 90.00pt; bottom: 72.00pt; width: 468.00pt;"><div class="footnotes page-footnotes"><ol><li value="1"><p>First note.</p></li>`,
+		`style="left: 54.00pt; bottom: 72.00pt; width: 468.00pt;"><div class="footnotes page-footnotes"><ol><li value="1"><p>Second note.</p></li>`,
+	} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("buildFootnoteSheet() missing %q in:\n%s", want, sheet)
+		}
+	}
+	if strings.Count(sheet, `class="sheet"`) != 2 {
+		t.Errorf("buildFootnoteSheet() sheets = %d, want 2", strings.Count(sheet, `class="sheet"`))
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestApplyFootnotes - Stamped notes
+// ---------------------------------------------------------------------------
+
+func TestApplyFootnotes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil footnotes is no-op", func(t *testing.T) {
+		t.Parallel()
+
+		data := footnotePDF()
+		got, err := applyFootnotes(data, &pdfOptions{}, nil)
+		if err != nil || len(got) != len(data) {
+			t.Errorf("applyFootnotes() = %d bytes, %v, want input unchanged", len(got), err)
+		}
+	})
+
+	t.Run("stamps notes on the referencing page", func(t *testing.T) {
+		t.Parallel()
+
+		l := &footnoteLayout{
+			notes:   testFootnotes(),
+			labels:  []int{1, 2},
+			heights: []float64{20, 40},
+			band:    80,
+			sheet:   "<html><head></head><body></body></html>",
+		}
+		var sheet string
+		got, err := applyFootnotes(footnotePDF(), &pdfOptions{Footnotes: l}, func(html string) ([]byte, error) {
+			sheet = html
+			return sheetPDF(3), nil
+		})
+		if err != nil {
+			t.Fatalf("applyFootnotes() unexpected error: %v", err)
+		}
+		pages := strings.Split(sheet, `<div class="sheet"`)
+		if len(pages) != 4 || !strings.Contains(pages[1], "First note.") || !strings.Contains(pages[1], "Second note.") {
+			t.Errorf("stamp sheet = %s, want both notes on page 1", sheet)
+		}
+
+		doc, err := pdfedit.Open(got)
+		if err != nil {
+			t.Fatalf("pdfedit.Open() unexpected error: %v", err)
+		}
+		refs, err := doc.Pages()
+		if err != nil {
+			t.Fatalf("Pages() unexpected error: %v", err)
+		}
+		page, err := doc.ResolveDict(refs[0])
+		if err != nil {
+			t.Fatalf("page 1 unexpected error: %v", err)
+		}
+		if contents, ok := page["Contents"].(pdfedit.Array); !ok || len(contents) != 2 {
+			t.Errorf("page 1 /Contents = %v, want q and stamp streams", page["Contents"])
+		}
+	})
+
+	t.Run("sheet error is returned", func(t *testing.T) {
+		t.Parallel()
+
+		errSheet := errors.New("no browser")
+		l := &footnoteLayout{notes: testFootnotes(), labels: []int{1, 2}, heights: []float64{20, 40}, band: 80}
+		_, err := applyFootnotes(footnotePDF(), &pdfOptions{Footnotes: l}, func(string) ([]byte, error) {
+			return nil, errSheet
+		})
+		if !errors.Is(err, errSheet) {
+			t.Errorf("applyFootnotes() error = %v, want %v", err, errSheet)
+		}
+	})
+}
@@ -0,0 +1,462 @@
+package picoloom
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alnah/picoloom/v2/internal/pipeline"
+	"github.com/alnah/picoloom/v2/internal/yamlutil"
+)
+
+// Frontmatter holds per-document settings read from a YAML frontmatter block.
+// Set Input.Frontmatter to overlay these values on the Input for one conversion.
+//
+// Empty strings and nil pointers mean "not set" and leave the Input unchanged.
+// A section (Cover, Footer, Watermark, TOC, Index, ListOfFigures,
+// ListOfTables) that is present enables the feature unless its Enabled field
+// is explicitly false.
+//
+// Example frontmatter:
+//
+//	---
+//	title: Release Notes
+//	version: v2.1
+//	documentID: DOC-2025-014
+//	keywords: [release, changelog]
+//	style: technical
+//	watermark:
+//	  text: DRAFT
+//	toc:
+//	  enabled: false
+//	vars:
+//	  client: ACME Corp
+//	bibliography: references.bib
+//	citations:
+//	  style: numeric
+//	---
+type Frontmatter struct {
+	Title        string   `yaml:"title"`
+	Subtitle     string   `yaml:"subtitle"`
+	Version      string   `yaml:"version"`
+	Date         string   `yaml:"date"`
+	ClientName   string   `yaml:"clientName"`
+	ProjectName  string   `yaml:"projectName"`
+	DocumentType string   `yaml:"documentType"`
+	DocumentID   string   `yaml:"documentID"`
+	Description  string   `yaml:"description"`
+	Keywords     []string `yaml:"keywords"` // PDF metadata keywords
+	Style        string   `yaml:"style"`    // Style name, file path, or CSS content (see WithStyle)
+
+	// Vars adds or overrides custom {{ .Vars.name }} values (see Input.Variables).
+	Vars map[string]string `yaml:"vars"`
+
+	// Bibliography sets the BibTeX or CSL-JSON file for citations, relative
+	// to the document, and enables them (see Input.Bibliography).
+	Bibliography string                `yaml:"bibliography"`
+	Citations    *FrontmatterCitations `yaml:"citations"`
+
+	Cover     *FrontmatterCover     `yaml:"cover"`
+	Footer    *FrontmatterFooter    `yaml:"footer"`
+	Watermark *FrontmatterWatermark `yaml:"watermark"`
+	TOC       *FrontmatterTOC       `yaml:"toc"`
+	Index     *FrontmatterIndex     `yaml:"index"`
+
+	ListOfFigures *FrontmatterListOf `yaml:"listOfFigures"`
+	ListOfTables  *FrontmatterListOf `yaml:"listOfTables"`
+
+	Footnotes *FrontmatterFootnotes `yaml:"footnotes"`
+}
+
+// FrontmatterCover overrides cover page settings for one document.
+type FrontmatterCover struct {
+	Enabled        *bool  `yaml:"enabled"`
+	Logo           string `yaml:"logo"`
+	ShowDepartment *bool  `yaml:"showDepartment"`
+}
+
+// FrontmatterFooter overrides footer settings for one document.
+type FrontmatterFooter struct {
+	Enabled        *bool  `yaml:"enabled"`
+	Position       string `yaml:"position"`
+	ShowPageNumber *bool  `yaml:"showPageNumber"`
+	Text           string `yaml:"text"`
+	ShowDocumentID *bool  `yaml:"showDocumentID"`
+}
+
+// FrontmatterWatermark overrides watermark settings for one document.
+type FrontmatterWatermark struct {
+	Enabled *bool    `yaml:"enabled"`
+	Text    string   `yaml:"text"`
+	Color   string   `yaml:"color"`
+	Opacity *float64 `yaml:"opacity"`
+	Angle   *float64 `yaml:"angle"`
+}
+
+// FrontmatterTOC overrides table of contents settings for one document.
+type FrontmatterTOC struct {
+	Enabled  *bool  `yaml:"enabled"`
+	Title    string `yaml:"title"`
+	MinDepth int    `yaml:"minDepth"`
+	MaxDepth int    `yaml:"maxDepth"`
+}
+
+// FrontmatterIndex overrides index settings for one document.
+type FrontmatterIndex struct {
+	Enabled *bool  `yaml:"enabled"`
+	Title   string `yaml:"title"`
+}
+
+// FrontmatterListOf overrides list of figures or tables settings for one
+// document.
+type FrontmatterListOf struct {
+	Enabled *bool  `yaml:"enabled"`
+	Title   string `yaml:"title"`
+}
+
+// FrontmatterFootnotes overrides footnote placement and numbering for one
+// document.
+type FrontmatterFootnotes struct {
+	Placement string `yaml:"placement"`
+	Numbering string `yaml:"numbering"`
+}
+
+// FrontmatterCitations overrides citation settings for one document.
+type FrontmatterCitations struct {
+	Style string `yaml:"style"`
+	Title string `yaml:"title"`
+}
+
+// ParseFrontmatter extracts and parses YAML frontmatter from markdown content.
+// Returns the parsed frontmatter and the markdown body without the frontmatter block.
+// Returns nil frontmatter (and no error) if the content has no frontmatter.
+// Unknown keys are rejected with ErrInvalidFrontmatter so typos surface early.
+func ParseFrontmatter(markdown string) (*Frontmatter, string, error) {
+	raw, body := pipeline.ExtractFrontmatter(markdown)
+	if strings.TrimSpace(raw) == "" {
+		return nil, body, nil
+	}
+
+	var fm Frontmatter
+	if err := yamlutil.UnmarshalStrict([]byte(raw), &fm); err != nil {
+		return nil, "", fmt.Errorf("%w: %w", ErrInvalidFrontmatter, err)
+	}
+	return &fm, body, nil
+}
+
+// IsEnabled reports whether the cover section enables the cover page.
+// A present section counts as enabled unless Enabled is explicitly false.
+func (c *FrontmatterCover) IsEnabled() bool {
+	return c != nil && (c.Enabled == nil || *c.Enabled)
+}
+
+// IsEnabled reports whether the footer section enables the footer.
+// A present section counts as enabled unless Enabled is explicitly false.
+func (f *FrontmatterFooter) IsEnabled() bool {
+	return f != nil && (f.Enabled == nil || *f.Enabled)
+}
+
+// IsEnabled reports whether the watermark section enables the watermark.
+// A present section counts as enabled unless Enabled is explicitly false.
+func (w *FrontmatterWatermark) IsEnabled() bool {
+	return w != nil && (w.Enabled == nil || *w.Enabled)
+}
+
+// IsEnabled reports whether the TOC section enables the table of contents.
+// A present section counts as enabled unless Enabled is explicitly false.
+func (t *FrontmatterTOC) IsEnabled() bool {
+	return t != nil && (t.Enabled == nil || *t.Enabled)
+}
+
+// IsEnabled reports whether the index section enables the index.
+// A present section counts as enabled unless Enabled is explicitly false.
+func (i *FrontmatterIndex) IsEnabled() bool {
+	return i != nil && (i.Enabled == nil || *i.Enabled)
+}
+
+// IsEnabled reports whether the section enables the list.
+// A present section counts as enabled unless Enabled is explicitly false.
+func (l *FrontmatterListOf) IsEnabled() bool {
+	return l != nil && (l.Enabled == nil || *l.Enabled)
+}
+
+// applyFrontmatter overlays frontmatter values on a copy of the input.
+// Pointer fields are copied before modification so the caller's structs are
+// never mutated. The result is validated afterwards by validateInput.
+func applyFrontmatter(input Input) Input {
+	fm := input.Frontmatter
+	if fm == nil {
+		return input
+	}
+
+	input.Cover = applyFrontmatterCover(input.Cover, fm)
+	input.Footer = applyFrontmatterFooter(input.Footer, fm)
+	input.Header = applyFrontmatterHeader(input.Header, fm)
+	input.Metadata = applyFrontmatterMetadata(input.Metadata, fm)
+	input.Watermark = applyFrontmatterWatermark(input.Watermark, fm.Watermark)
+	input.TOC = applyFrontmatterTOC(input.TOC, fm.TOC)
+	input.Index = applyFrontmatterIndex(input.Index, fm.Index)
+	input.ListOfFigures = applyFrontmatterListOf(input.ListOfFigures, fm.ListOfFigures)
+	input.ListOfTables = applyFrontmatterListOf(input.ListOfTables, fm.ListOfTables)
+	input.Variables = applyFrontmatterVariables(input.Variables, fm)
+	input.Bibliography = applyFrontmatterBibliography(input.Bibliography, fm)
+	input.Footnotes = applyFrontmatterFootnotes(input.Footnotes, fm.Footnotes)
+	return input
+}
+
+// applyFrontmatterCover overlays document metadata and cover settings.
+func applyFrontmatterCover(cover *Cover, fm *Frontmatter) *Cover {
+	if fm.Cover != nil && !fm.Cover.IsEnabled() {
+		return nil
+	}
+	if cover == nil {
+		if !fm.Cover.IsEnabled() {
+			return nil
+		}
+		cover = &Cover{}
+	}
+
+	c := *cover
+	overrideString(&c.Title, fm.Title)
+	overrideString(&c.Subtitle, fm.Subtitle)
+	overrideString(&c.Version, fm.Version)
+	overrideString(&c.Date, fm.Date)
+	overrideString(&c.ClientName, fm.ClientName)
+	overrideString(&c.ProjectName, fm.ProjectName)
+	overrideString(&c.DocumentType, fm.DocumentType)
+	overrideString(&c.DocumentID, fm.DocumentID)
+	overrideString(&c.Description, fm.Description)
+	if fm.Cover != nil {
+		overrideString(&c.Logo, fm.Cover.Logo)
+		if fm.Cover.ShowDepartment != nil && !*fm.Cover.ShowDepartment {
+			c.Department = ""
+		}
+	}
+	return &c
+}
+
+// applyFrontmatterFooter overlays document metadata and footer settings.
+// Version maps to Footer.Status, matching how the CLI builds footers.
+func applyFrontmatterFooter(footer *Footer, fm *Frontmatter) *Footer {
+	if fm.Footer != nil && !fm.Footer.IsEnabled() {
+		return nil
+	}
+	if footer == nil {
+		if !fm.Footer.IsEnabled() {
+			return nil
+		}
+		footer = &Footer{}
+	}
+
+	f := *footer
+	overrideString(&f.Date, fm.Date)
+	overrideString(&f.Status, fm.Version)
+	showDocumentID := f.DocumentID != ""
+	if fm.Footer != nil {
+		overrideString(&f.Position, fm.Footer.Position)
+		overrideString(&f.Text, fm.Footer.Text)
+		if fm.Footer.ShowPageNumber != nil {
+			f.ShowPageNumber = *fm.Footer.ShowPageNumber
+		}
+		if fm.Footer.ShowDocumentID != nil {
+			showDocumentID = *fm.Footer.ShowDocumentID
+		}
+	}
+	switch {
+	case !showDocumentID:
+		f.DocumentID = ""
+	case fm.DocumentID != "":
+		f.DocumentID = fm.DocumentID
+	}
+	return &f
+}
+
+// applyFrontmatterHeader overlays document metadata used by header placeholders.
+// Frontmatter never enables a header on its own.
+func applyFrontmatterHeader(header *Header, fm *Frontmatter) *Header {
+	if header == nil {
+		return nil
+	}
+
+	h := *header
+	overrideString(&h.Title, fm.Title)
+	overrideString(&h.Version, fm.Version)
+	overrideString(&h.DocumentID, fm.DocumentID)
+	overrideString(&h.Date, fm.Date)
+	return &h
+}
+
+// applyFrontmatterMetadata overlays PDF document properties, so the title
+// reaches the PDF even when the cover is disabled.
+func applyFrontmatterMetadata(metadata *Metadata, fm *Frontmatter) *Metadata {
+	if metadata == nil {
+		if fm.Title == "" && fm.Description == "" && len(fm.Keywords) == 0 {
+			return nil
+		}
+		metadata = &Metadata{}
+	}
+
+	m := *metadata
+	overrideString(&m.Title, fm.Title)
+	overrideString(&m.Subject, fm.Description)
+	if len(fm.Keywords) > 0 {
+		m.Keywords = fm.Keywords
+	}
+	return &m
+}
+
+// applyFrontmatterWatermark overlays watermark settings.
+// A watermark enabled only by frontmatter starts from library defaults.
+func applyFrontmatterWatermark(watermark *Watermark, fw *FrontmatterWatermark) *Watermark {
+	if fw == nil {
+		return watermark
+	}
+	if !fw.IsEnabled() {
+		return nil
+	}
+
+	w := Watermark{
+		Color:   DefaultWatermarkColor,
+		Opacity: DefaultWatermarkOpacity,
+		Angle:   DefaultWatermarkAngle,
+	}
+	if watermark != nil {
+		w = *watermark
+	}
+	overrideString(&w.Text, fw.Text)
+	overrideString(&w.Color, fw.Color)
+	if fw.Opacity != nil {
+		w.Opacity = *fw.Opacity
+	}
+	if fw.Angle != nil {
+		w.Angle = *fw.Angle
+	}
+	return &w
+}
+
+// applyFrontmatterTOC overlays table of contents settings.
+func applyFrontmatterTOC(toc *TOC, ft *FrontmatterTOC) *TOC {
+	if ft == nil {
+		return toc
+	}
+	if !ft.IsEnabled() {
+		return nil
+	}
+
+	t := TOC{}
+	if toc != nil {
+		t = *toc
+	}
+	overrideString(&t.Title, ft.Title)
+	if ft.MinDepth != 0 {
+		t.MinDepth = ft.MinDepth
+	}
+	if ft.MaxDepth != 0 {
+		t.MaxDepth = ft.MaxDepth
+	}
+	return &t
+}
+
+// applyFrontmatterIndex overlays index settings.
+func applyFrontmatterIndex(index *Index, fi *FrontmatterIndex) *Index {
+	if fi == nil {
+		return index
+	}
+	if !fi.IsEnabled() {
+		return nil
+	}
+
+	i := Index{}
+	if index != nil {
+		i = *index
+	}
+	overrideString(&i.Title, fi.Title)
+	return &i
+}
+
+// applyFrontmatterListOf overlays list of figures or tables settings.
+func applyFrontmatterListOf(list *ListOf, fl *FrontmatterListOf) *ListOf {
+	if fl == nil {
+		return list
+	}
+	if !fl.IsEnabled() {
+		return nil
+	}
+
+	l := ListOf{}
+	if list != nil {
+		l = *list
+	}
+	overrideString(&l.Title, fl.Title)
+	return &l
+}
+
+// applyFrontmatterVariables overlays document metadata and custom variables.
+// Frontmatter never enables interpolation on its own.
+func applyFrontmatterVariables(vars *Variables, fm *Frontmatter) *Variables {
+	if vars == nil {
+		return nil
+	}
+
+	v := *vars
+	overrideString(&v.Document.Title, fm.Title)
+	overrideString(&v.Document.Subtitle, fm.Subtitle)
+	overrideString(&v.Document.Version, fm.Version)
+	overrideString(&v.Document.Date, fm.Date)
+	overrideString(&v.Document.ClientName, fm.ClientName)
+	overrideString(&v.Document.ProjectName, fm.ProjectName)
+	overrideString(&v.Document.DocumentType, fm.DocumentType)
+	overrideString(&v.Document.DocumentID, fm.DocumentID)
+	overrideString(&v.Document.Description, fm.Description)
+	if len(fm.Vars) > 0 {
+		v.Vars = make(map[string]string, len(vars.Vars)+len(fm.Vars))
+		for name, value := range vars.Vars {
+			v.Vars[name] = value
+		}
+		for name, value := range fm.Vars {
+			v.Vars[name] = value
+		}
+	}
+	return &v
+}
+
+// applyFrontmatterBibliography overlays the bibliography file and citation
+// settings. A bibliography file enables citations; citation settings alone
+// only adjust them.
+func applyFrontmatterBibliography(bib *Bibliography, fm *Frontmatter) *Bibliography {
+	if bib == nil && fm.Bibliography == "" {
+		return nil
+	}
+
+	b := Bibliography{}
+	if bib != nil {
+		b = *bib
+	}
+	overrideString(&b.Path, fm.Bibliography)
+	if fm.Citations != nil {
+		overrideString(&b.Style, fm.Citations.Style)
+		overrideString(&b.Title, fm.Citations.Title)
+	}
+	return &b
+}
+
+// applyFrontmatterFootnotes overlays footnote placement and numbering.
+func applyFrontmatterFootnotes(f *Footnotes, ff *FrontmatterFootnotes) *Footnotes {
+	if ff == nil {
+		return f
+	}
+
+	n := Footnotes{}
+	if f != nil {
+		n = *f
+	}
+	overrideString(&n.Placement, ff.Placement)
+	overrideString(&n.Numbering, ff.Numbering)
+	return &n
+}
+
+// overrideString sets *dst to value when value is non-empty.
+func overrideString(dst *string, value string) {
+	if value != "" {
+		*dst = value
+	}
+}
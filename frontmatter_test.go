@@ -0,0 +1,343 @@
+package picoloom
+
+// Notes:
+// - ParseFrontmatter: we test typed parsing, absent/empty blocks, and strict
+//   rejection of unknown keys and wrong types.
+// - applyFrontmatter: we test overlay of document metadata, section enable/disable,
+//   and that the caller's structs are never mutated.
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// TestParseFrontmatter - Frontmatter parsing
+// ---------------------------------------------------------------------------
+
+func TestParseFrontmatter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("parses typed fields and returns body", func(t *testing.T) {
+		t.Parallel()
+
+		md := "---\ntitle: Spec\nversion: v2\nstyle: technical\nwatermark:\n  text: DRAFT\ntoc:\n  enabled: false\n---\n# Body\n"
+		fm, body, err := ParseFrontmatter(md)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if fm == nil {
+			t.Fatal("ParseFrontmatter() frontmatter = nil, want non-nil")
+		}
+		if fm.Title != "Spec" || fm.Version != "v2" || fm.Style != "technical" {
+			t.Errorf("ParseFrontmatter() = %+v, want title/version/style set", fm)
+		}
+		if !fm.Watermark.IsEnabled() || fm.Watermark.Text != "DRAFT" {
+			t.Errorf("Watermark = %+v, want enabled with text DRAFT", fm.Watermark)
+		}
+		if fm.TOC.IsEnabled() {
+			t.Error("TOC.IsEnabled() = true, want false")
+		}
+		if body != "# Body\n" {
+			t.Errorf("body = %q, want %q", body, "# Body\n")
+		}
+	})
+
+	t.Run("no frontmatter returns nil", func(t *testing.T) {
+		t.Parallel()
+
+		fm, body, err := ParseFrontmatter("# Title\n")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if fm != nil {
+			t.Errorf("ParseFrontmatter() frontmatter = %+v, want nil", fm)
+		}
+		if body != "# Title\n" {
+			t.Errorf("body = %q, want %q", body, "# Title\n")
+		}
+	})
+
+	tests := []struct {
+		name string
+		md   string
+	}{
+		{"unknown key", "---\ntitel: Typo\n---\nBody\n"},
+		{"unknown nested key", "---\nfooter:\n  colour: red\n---\nBody\n"},
+		{"wrong type", "---\ntoc:\n  maxDepth: deep\n---\nBody\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, _, err := ParseFrontmatter(tt.md)
+			if !errors.Is(err, ErrInvalidFrontmatter) {
+				t.Errorf("ParseFrontmatter() error = %v, want ErrInvalidFrontmatter", err)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestApplyFrontmatter - Frontmatter overlay on Input
+// ---------------------------------------------------------------------------
+
+func TestApplyFrontmatter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil frontmatter leaves input unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		cover := &Cover{Title: "Base"}
+		got := applyFrontmatter(Input{Cover: cover})
+		if got.Cover != cover {
+			t.Error("applyFrontmatter() replaced Cover, want unchanged")
+		}
+	})
+
+	t.Run("overlays metadata without mutating caller structs", func(t *testing.T) {
+		t.Parallel()
+
+		cover := &Cover{Title: "Base", Author: "Alice", Department: "R&D"}
+		footer := &Footer{Status: "v1", DocumentID: "OLD-1"}
+		showDept := false
+		got := applyFrontmatter(Input{
+			Cover:  cover,
+			Footer: footer,
+			Frontmatter: &Frontmatter{
+				Title:      "Override",
+				Version:    "v2",
+				DocumentID: "DOC-2",
+				Cover:      &FrontmatterCover{ShowDepartment: &showDept},
+			},
+		})
+
+		if got.Cover.Title != "Override" || got.Cover.Author != "Alice" || got.Cover.Version != "v2" {
+			t.Errorf("Cover = %+v, want title/version overridden and author kept", got.Cover)
+		}
+		if got.Cover.Department != "" {
+			t.Errorf("Cover.Department = %q, want empty", got.Cover.Department)
+		}
+		if got.Footer.Status != "v2" || got.Footer.DocumentID != "DOC-2" {
+			t.Errorf("Footer = %+v, want status v2 and document ID DOC-2", got.Footer)
+		}
+		if cover.Title != "Base" || cover.Department != "R&D" || footer.Status != "v1" {
+			t.Error("applyFrontmatter() mutated caller structs")
+		}
+	})
+
+	t.Run("overlays header placeholder metadata", func(t *testing.T) {
+		t.Parallel()
+
+		header := &Header{Left: "{title}", Title: "Base", Version: "v1"}
+		got := applyFrontmatter(Input{
+			Header:      header,
+			Frontmatter: &Frontmatter{Title: "Override"},
+		})
+		if got.Header.Title != "Override" || got.Header.Version != "v1" || got.Header.Left != "{title}" {
+			t.Errorf("Header = %+v, want title overridden and other fields kept", got.Header)
+		}
+		if header.Title != "Base" {
+			t.Error("applyFrontmatter() mutated caller Header")
+		}
+	})
+
+	t.Run("overlays PDF metadata", func(t *testing.T) {
+		t.Parallel()
+
+		metadata := &Metadata{Title: "Base", Author: "Alice"}
+		got := applyFrontmatter(Input{
+			Metadata: metadata,
+			Frontmatter: &Frontmatter{
+				Title:       "Override",
+				Description: "Summary",
+				Keywords:    []string{"go", "pdf"},
+			},
+		})
+		if got.Metadata.Title != "Override" || got.Metadata.Author != "Alice" || got.Metadata.Subject != "Summary" {
+			t.Errorf("Metadata = %+v, want title/subject overridden and author kept", got.Metadata)
+		}
+		if strings.Join(got.Metadata.Keywords, ",") != "go,pdf" {
+			t.Errorf("Metadata.Keywords = %v, want [go pdf]", got.Metadata.Keywords)
+		}
+		if metadata.Title != "Base" {
+			t.Error("applyFrontmatter() mutated caller Metadata")
+		}
+	})
+
+	t.Run("overlays variables without mutating caller map", func(t *testing.T) {
+		t.Parallel()
+
+		vars := &Variables{
+			Document: DocumentVariables{Title: "Base", Version: "v1"},
+			Author:   AuthorVariables{Name: "Alice"},
+			Vars:     map[string]string{"client": "ACME", "region": "EU"},
+		}
+		got := applyFrontmatter(Input{
+			Variables: vars,
+			Frontmatter: &Frontmatter{
+				Version: "v2",
+				Vars:    map[string]string{"client": "Globex", "phase": "2"},
+			},
+		})
+		if got.Variables.Document.Title != "Base" || got.Variables.Document.Version != "v2" || got.Variables.Author.Name != "Alice" {
+			t.Errorf("Variables = %+v, want version overridden and other fields kept", got.Variables)
+		}
+		want := map[string]string{"client": "Globex", "region": "EU", "phase": "2"}
+		if !reflect.DeepEqual(got.Variables.Vars, want) {
+			t.Errorf("Variables.Vars = %v, want %v", got.Variables.Vars, want)
+		}
+		if vars.Vars["client"] != "ACME" || vars.Document.Version != "v1" {
+			t.Error("applyFrontmatter() mutated caller Variables")
+		}
+	})
+
+	t.Run("frontmatter vars do not enable variables", func(t *testing.T) {
+		t.Parallel()
+
+		got := applyFrontmatter(Input{Frontmatter: &Frontmatter{Vars: map[string]string{"a": "b"}}})
+		if got.Variables != nil {
+			t.Errorf("Variables = %+v, want nil", got.Variables)
+		}
+	})
+
+	t.Run("bibliography file enables citations", func(t *testing.T) {
+		t.Parallel()
+
+		got := applyFrontmatter(Input{Frontmatter: &Frontmatter{
+			Bibliography: "refs.bib",
+			Citations:    &FrontmatterCitations{Style: "numeric"},
+		}})
+		want := &Bibliography{Path: "refs.bib", Style: "numeric"}
+		if !reflect.DeepEqual(got.Bibliography, want) {
+			t.Errorf("Bibliography = %+v, want %+v", got.Bibliography, want)
+		}
+	})
+
+	t.Run("citation settings overlay bibliography without mutating caller", func(t *testing.T) {
+		t.Parallel()
+
+		bib := &Bibliography{Path: "refs.json", Title: "Works Cited"}
+		got := applyFrontmatter(Input{
+			Bibliography: bib,
+			Frontmatter:  &Frontmatter{Citations: &FrontmatterCitations{Style: "numeric"}},
+		})
+		want := &Bibliography{Path: "refs.json", Style: "numeric", Title: "Works Cited"}
+		if !reflect.DeepEqual(got.Bibliography, want) {
+			t.Errorf("Bibliography = %+v, want %+v", got.Bibliography, want)
+		}
+		if bib.Style != "" {
+			t.Error("applyFrontmatter() mutated caller Bibliography")
+		}
+	})
+
+	t.Run("citation settings alone do not enable citations", func(t *testing.T) {
+		t.Parallel()
+
+		got := applyFrontmatter(Input{Frontmatter: &Frontmatter{Citations: &FrontmatterCitations{Style: "numeric"}}})
+		if got.Bibliography != nil {
+			t.Errorf("Bibliography = %+v, want nil", got.Bibliography)
+		}
+	})
+
+	t.Run("index section enables and overlays the index", func(t *testing.T) {
+		t.Parallel()
+
+		index := &Index{Title: "Index"}
+		got := applyFrontmatter(Input{Index: index, Frontmatter: &Frontmatter{Index: &FrontmatterIndex{Title: "Terms"}}})
+		if got.Index == nil || got.Index.Title != "Terms" {
+			t.Errorf("Index = %+v, want title Terms", got.Index)
+		}
+		if index.Title != "Index" {
+			t.Error("applyFrontmatter() mutated caller Index")
+		}
+
+		off := false
+		got = applyFrontmatter(Input{Index: index, Frontmatter: &Frontmatter{Index: &FrontmatterIndex{Enabled: &off}}})
+		if got.Index != nil {
+			t.Errorf("Index = %+v, want nil", got.Index)
+		}
+	})
+
+	t.Run("list sections enable lists and keep page numbers", func(t *testing.T) {
+		t.Parallel()
+
+		figures := &ListOf{PageNumbers: true}
+		got := applyFrontmatter(Input{ListOfFigures: figures, Frontmatter: &Frontmatter{
+			ListOfFigures: &FrontmatterListOf{Title: "Figures"},
+			ListOfTables:  &FrontmatterListOf{},
+		}})
+		if want := (ListOf{Title: "Figures", PageNumbers: true}); got.ListOfFigures == nil || *got.ListOfFigures != want {
+			t.Errorf("ListOfFigures = %+v, want %+v", got.ListOfFigures, want)
+		}
+		if got.ListOfTables == nil || *got.ListOfTables != (ListOf{}) {
+			t.Errorf("ListOfTables = %+v, want enabled with defaults", got.ListOfTables)
+		}
+		if figures.Title != "" {
+			t.Error("applyFrontmatter() mutated caller ListOfFigures")
+		}
+
+		off := false
+		got = applyFrontmatter(Input{ListOfFigures: figures, Frontmatter: &Frontmatter{ListOfFigures: &FrontmatterListOf{Enabled: &off}}})
+		if got.ListOfFigures != nil {
+			t.Errorf("ListOfFigures = %+v, want nil", got.ListOfFigures)
+		}
+	})
+
+	t.Run("footnotes section overrides settings", func(t *testing.T) {
+		t.Parallel()
+
+		footnotes := &Footnotes{Placement: FootnotePlacementPage}
+		got := applyFrontmatter(Input{Footnotes: footnotes, Frontmatter: &Frontmatter{
+			Footnotes: &FrontmatterFootnotes{Numbering: FootnoteNumberingPage},
+		}})
+		if want := (Footnotes{Placement: "page", Numbering: "page"}); got.Footnotes == nil || *got.Footnotes != want {
+			t.Errorf("Footnotes = %+v, want %+v", got.Footnotes, want)
+		}
+		if footnotes.Numbering != "" {
+			t.Error("applyFrontmatter() mutated caller Footnotes")
+		}
+	})
+
+	t.Run("metadata alone does not enable cover or footer", func(t *testing.T) {
+		t.Parallel()
+
+		got := applyFrontmatter(Input{Frontmatter: &Frontmatter{Title: "Only"}})
+		if got.Cover != nil || got.Footer != nil || got.Header != nil {
+			t.Errorf("applyFrontmatter() Cover = %v, Footer = %v, Header = %v, want nil", got.Cover, got.Footer, got.Header)
+		}
+	})
+
+	t.Run("sections enable and disable features", func(t *testing.T) {
+		t.Parallel()
+
+		off := false
+		got := applyFrontmatter(Input{
+			TOC:    &TOC{Title: "Contents"},
+			Footer: &Footer{Text: "Base"},
+			Frontmatter: &Frontmatter{
+				TOC:       &FrontmatterTOC{Enabled: &off},
+				Footer:    &FrontmatterFooter{Enabled: &off},
+				Watermark: &FrontmatterWatermark{Text: "DRAFT"},
+				Cover:     &FrontmatterCover{},
+			},
+		})
+
+		if got.TOC != nil || got.Footer != nil {
+			t.Errorf("TOC = %v, Footer = %v, want nil", got.TOC, got.Footer)
+		}
+		if got.Cover == nil {
+			t.Error("Cover = nil, want enabled by cover section")
+		}
+		if got.Watermark == nil {
+			t.Fatal("Watermark = nil, want enabled by watermark section")
+		}
+		want := Watermark{Text: "DRAFT", Color: DefaultWatermarkColor, Opacity: DefaultWatermarkOpacity, Angle: DefaultWatermarkAngle}
+		if *got.Watermark != want {
+			t.Errorf("Watermark = %+v, want %+v", *got.Watermark, want)
+		}
+	})
+}
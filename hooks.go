@@ -0,0 +1,131 @@
+package picoloom
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yuin/goldmark"
+)
+
+// MarkdownTransformer rewrites the Markdown of a document or chapter.
+type MarkdownTransformer interface {
+	TransformMarkdown(ctx context.Context, markdown string) (string, error)
+}
+
+// MarkdownTransformFunc adapts a function to MarkdownTransformer.
+type MarkdownTransformFunc func(ctx context.Context, markdown string) (string, error)
+
+// TransformMarkdown calls f.
+func (f MarkdownTransformFunc) TransformMarkdown(ctx context.Context, markdown string) (string, error) {
+	return f(ctx, markdown)
+}
+
+// HTMLTransformer rewrites the converted HTML document.
+type HTMLTransformer interface {
+	TransformHTML(ctx context.Context, html string) (string, error)
+}
+
+// HTMLTransformFunc adapts a function to HTMLTransformer.
+type HTMLTransformFunc func(ctx context.Context, html string) (string, error)
+
+// TransformHTML calls f.
+func (f HTMLTransformFunc) TransformHTML(ctx context.Context, html string) (string, error) {
+	return f(ctx, html)
+}
+
+// PDFPostProcessor rewrites the printed PDF.
+type PDFPostProcessor interface {
+	ProcessPDF(ctx context.Context, pdf []byte) ([]byte, error)
+}
+
+// PDFPostProcessorFunc adapts a function to PDFPostProcessor.
+type PDFPostProcessorFunc func(ctx context.Context, pdf []byte) ([]byte, error)
+
+// ProcessPDF calls f.
+func (f PDFPostProcessorFunc) ProcessPDF(ctx context.Context, pdf []byte) ([]byte, error) {
+	return f(ctx, pdf)
+}
+
+// WithMarkdownTransform adds a Markdown transform, run on each source after
+// includes are expanded and variables interpolated, and before citations,
+// diagrams and the other Markdown stages. A nil transform is ignored.
+//
+// Example:
+//
+//	conv, err := picoloom.NewConverter(picoloom.WithMarkdownTransform(
+//	    picoloom.MarkdownTransformFunc(func(_ context.Context, md string) (string, error) {
+//	        return strings.ReplaceAll(md, "ACME", "**ACME Corp**"), nil
+//	    }),
+//	))
+func WithMarkdownTransform(t MarkdownTransformer) Option {
+	return func(c *Converter) {
+		if t != nil {
+			c.markdownTransforms = append(c.markdownTransforms, t)
+		}
+	}
+}
+
+// WithHTMLTransform adds an HTML transform, run on the converted document
+// before it is decorated. A nil transform is ignored.
+func WithHTMLTransform(t HTMLTransformer) Option {
+	return func(c *Converter) {
+		if t != nil {
+			c.htmlTransforms = append(c.htmlTransforms, t)
+		}
+	}
+}
+
+// WithGoldmarkExtensions adds goldmark extensions for custom Markdown
+// syntax, after the built-in ones.
+func WithGoldmarkExtensions(extensions ...goldmark.Extender) Option {
+	return func(c *Converter) {
+		c.cfg.goldmarkExtensions = append(c.cfg.goldmarkExtensions, extensions...)
+	}
+}
+
+// WithPDFPostProcessor adds a PDF post-processor, run on the final PDF
+// before metadata, encryption and signing. A nil post-processor is ignored.
+func WithPDFPostProcessor(p PDFPostProcessor) Option {
+	return func(c *Converter) {
+		if p != nil {
+			c.pdfPostProcessors = append(c.pdfPostProcessors, p)
+		}
+	}
+}
+
+// transformMarkdown runs the Markdown transforms on markdown.
+func (c *Converter) transformMarkdown(ctx context.Context, markdown string) (string, error) {
+	for i, t := range c.markdownTransforms {
+		var err error
+		if markdown, err = t.TransformMarkdown(ctx, markdown); err != nil {
+			return "", fmt.Errorf("%w: markdown transform %d: %w", ErrHook, i+1, err)
+		}
+	}
+	return markdown, nil
+}
+
+// transformHTML runs the HTML transforms on htmlContent.
+func (c *Converter) transformHTML(ctx context.Context, htmlContent string) (string, error) {
+	for i, t := range c.htmlTransforms {
+		var err error
+		if htmlContent, err = t.TransformHTML(ctx, htmlContent); err != nil {
+			return "", fmt.Errorf("%w: HTML transform %d: %w", ErrHook, i+1, err)
+		}
+	}
+	return htmlContent, nil
+}
+
+// applyPDFPostProcessors runs the post-processors of opts on data.
+// Returns data unchanged if opts has none.
+func applyPDFPostProcessors(ctx context.Context, data []byte, opts *pdfOptions) ([]byte, error) {
+	if opts == nil {
+		return data, nil
+	}
+	for i, p := range opts.PostProcessors {
+		var err error
+		if data, err = p.ProcessPDF(ctx, data); err != nil {
+			return nil, fmt.Errorf("%w: PDF post-processor %d: %w", ErrHook, i+1, err)
+		}
+	}
+	return data, nil
+}
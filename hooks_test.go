@@ -0,0 +1,242 @@
+package picoloom
+
+// Notes:
+// - Hooks are checked through Convert and ConvertMany with a mock PDF
+//   converter, which records the options of every render; the rod
+//   converter's call to applyPDFPostProcessors is tested directly
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/yuin/goldmark/extension"
+)
+
+// ---------------------------------------------------------------------------
+// TestHooks_order - Hooks around the built-in stages
+// ---------------------------------------------------------------------------
+
+func TestHooks_order(t *testing.T) {
+	t.Parallel()
+
+	var markdownSeen []string
+	var htmlSeen string
+	service, err := NewConverter(
+		withPDFConverter(&mockPDFConverter{}),
+		WithMarkdownTransform(MarkdownTransformFunc(func(_ context.Context, markdown string) (string, error) {
+			markdownSeen = append(markdownSeen, markdown)
+			return markdown + "\nfirst", nil
+		})),
+		WithMarkdownTransform(MarkdownTransformFunc(func(_ context.Context, markdown string) (string, error) {
+			return markdown + " second", nil
+		})),
+		WithHTMLTransform(HTMLTransformFunc(func(_ context.Context, html string) (string, error) {
+			htmlSeen = html
+			return strings.Replace(html, "<body>", `<body><p class="brand">ACME</p>`, 1), nil
+		})),
+	)
+	if err != nil {
+		t.Fatalf("NewConverter() unexpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = service.Close() })
+
+	input := Input{
+		Variables: &Variables{Vars: map[string]string{"client": "Initech"}},
+		TOC:       &TOC{MinDepth: 1},
+		HTMLOnly:  true,
+	}
+	result, err := service.ConvertMany(context.Background(), input, []Chapter{
+		{Markdown: "# One\n\nFor {{ .Vars.client }}.\n", Path: "one.md"},
+		{Markdown: "# Two\n", Path: "two.md"},
+	})
+	if err != nil {
+		t.Fatalf("ConvertMany() unexpected error: %v", err)
+	}
+
+	if len(markdownSeen) != 2 || !strings.Contains(markdownSeen[0], "For Initech.") {
+		t.Errorf("markdown transform saw %q, want each chapter with variables filled", markdownSeen)
+	}
+	if !strings.Contains(htmlSeen, "first second") {
+		t.Errorf("HTML transform saw %q, want the transformed Markdown in order", htmlSeen)
+	}
+	if strings.Contains(htmlSeen, "<style>") || strings.Contains(htmlSeen, "<nav") {
+		t.Errorf("HTML transform saw a decorated document:\n%s", htmlSeen)
+	}
+	html := string(result.HTML)
+	if !strings.Contains(html, `<p class="brand">ACME</p>`) || !strings.Contains(html, "<nav") {
+		t.Errorf("HTML = %s, want the transformed body with its TOC", html)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestHooks_errors - Hook failures stop the conversion
+// ---------------------------------------------------------------------------
+
+func TestHooks_errors(t *testing.T) {
+	t.Parallel()
+
+	errHook := errors.New("hook failed")
+
+	tests := []struct {
+		name    string
+		opt     Option
+		wantMsg string
+	}{
+		{
+			name: "markdown transform",
+			opt: WithMarkdownTransform(MarkdownTransformFunc(func(context.Context, string) (string, error) {
+				return "", errHook
+			})),
+			wantMsg: "markdown transform 1",
+		},
+		{
+			name: "HTML transform",
+			opt: WithHTMLTransform(HTMLTransformFunc(func(context.Context, string) (string, error) {
+				return "", errHook
+			})),
+			wantMsg: "HTML transform 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service, err := NewConverter(withPDFConverter(&mockPDFConverter{}), tt.opt)
+			if err != nil {
+				t.Fatalf("NewConverter() unexpected error: %v", err)
+			}
+			t.Cleanup(func() { _ = service.Close() })
+
+			_, err = service.Convert(context.Background(), Input{Markdown: "# Doc", HTMLOnly: true})
+			if !errors.Is(err, ErrHook) || !errors.Is(err, errHook) {
+				t.Fatalf("Convert() error = %v, want %v wrapping %v", err, ErrHook, errHook)
+			}
+			if !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("Convert() error = %q, want it to name the %s", err, tt.wantMsg)
+			}
+		})
+	}
+
+	t.Run("nil hooks are ignored", func(t *testing.T) {
+		t.Parallel()
+
+		service, err := NewConverter(withPDFConverter(&mockPDFConverter{}),
+			WithMarkdownTransform(nil), WithHTMLTransform(nil), WithPDFPostProcessor(nil))
+		if err != nil {
+			t.Fatalf("NewConverter() unexpected error: %v", err)
+		}
+		t.Cleanup(func() { _ = service.Close() })
+
+		if _, err := service.Convert(context.Background(), Input{Markdown: "# Doc"}); err != nil {
+			t.Errorf("Convert() unexpected error: %v", err)
+		}
+	})
+}
+
+// ---------------------------------------------------------------------------
+// TestWithGoldmarkExtensions - Custom Markdown syntax
+// ---------------------------------------------------------------------------
+
+func TestWithGoldmarkExtensions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		rawHTML *RawHTML
+		want    string
+	}{
+		{"built-in syntax only", nil, nil, "<p>Wait -- what?</p>"},
+		{"extension applied", []Option{WithGoldmarkExtensions(extension.Typographer)}, nil, "Wait &ndash; what?"},
+		{"extension applied with raw HTML", []Option{WithGoldmarkExtensions(extension.Typographer)}, &RawHTML{}, "Wait – what?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service, err := NewConverter(append([]Option{withPDFConverter(&mockPDFConverter{})}, tt.opts...)...)
+			if err != nil {
+				t.Fatalf("NewConverter() unexpected error: %v", err)
+			}
+			t.Cleanup(func() { _ = service.Close() })
+
+			result, err := service.Convert(context.Background(), Input{Markdown: "Wait -- what?", RawHTML: tt.rawHTML, HTMLOnly: true})
+			if err != nil {
+				t.Fatalf("Convert() unexpected error: %v", err)
+			}
+			if !strings.Contains(string(result.HTML), tt.want) {
+				t.Errorf("HTML = %s, want %q", result.HTML, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestWithPDFPostProcessor - Final PDF post-processing
+// ---------------------------------------------------------------------------
+
+func TestWithPDFPostProcessor(t *testing.T) {
+	t.Parallel()
+
+	stamp := PDFPostProcessorFunc(func(_ context.Context, pdf []byte) ([]byte, error) {
+		return pdf, nil
+	})
+
+	t.Run("runs on the final render only", func(t *testing.T) {
+		t.Parallel()
+
+		pdfConv := &mockPDFConverter{output: footnotePDF()}
+		service, err := NewConverter(withPDFConverter(pdfConv), WithPDFPostProcessor(stamp))
+		if err != nil {
+			t.Fatalf("NewConverter() unexpected error: %v", err)
+		}
+		t.Cleanup(func() { _ = service.Close() })
+
+		input := Input{Markdown: "Claim[^a].\n\n[^a]: Source.\n", Footnotes: &Footnotes{Placement: FootnotePlacementPage}}
+		if _, err := service.Convert(context.Background(), input); err != nil {
+			t.Fatalf("Convert() unexpected error: %v", err)
+		}
+		if len(pdfConv.allOpts) != 2 {
+			t.Fatalf("ToPDF() called %d times, want 2", len(pdfConv.allOpts))
+		}
+		if len(pdfConv.allOpts[0].PostProcessors) != 0 || len(pdfConv.allOpts[1].PostProcessors) != 1 {
+			t.Error("want post-processors on the final render only")
+		}
+	})
+
+	t.Run("runs in order and stops on error", func(t *testing.T) {
+		t.Parallel()
+
+		appendByte := func(b byte) PDFPostProcessor {
+			return PDFPostProcessorFunc(func(_ context.Context, pdf []byte) ([]byte, error) {
+				return append(pdf, b), nil
+			})
+		}
+		got, err := applyPDFPostProcessors(context.Background(), []byte("%PDF"),
+			&pdfOptions{PostProcessors: []PDFPostProcessor{appendByte('a'), appendByte('b')}})
+		if err != nil || string(got) != "%PDFab" {
+			t.Errorf("applyPDFPostProcessors() = %q, %v, want %q", got, err, "%PDFab")
+		}
+
+		errPost := errors.New("upload failed")
+		failing := PDFPostProcessorFunc(func(context.Context, []byte) ([]byte, error) { return nil, errPost })
+		_, err = applyPDFPostProcessors(context.Background(), []byte("%PDF"),
+			&pdfOptions{PostProcessors: []PDFPostProcessor{appendByte('a'), failing}})
+		if !errors.Is(err, ErrHook) || !errors.Is(err, errPost) || !strings.Contains(err.Error(), "PDF post-processor 2") {
+			t.Errorf("applyPDFPostProcessors() error = %v, want %v naming post-processor 2", err, ErrHook)
+		}
+	})
+
+	t.Run("nil options is no-op", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := applyPDFPostProcessors(context.Background(), []byte("%PDF"), nil)
+		if err != nil || string(got) != "%PDF" {
+			t.Errorf("applyPDFPostProcessors() = %q, %v, want input unchanged", got, err)
+		}
+	})
+}
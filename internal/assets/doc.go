@@ -30,7 +30,12 @@
 //	└── templates/
 //	    └── {name}/
 //	        ├── cover.html       # Cover page template
-//	        └── signature.html   # Signature block template
+//	        ├── signature.html   # Signature block template
+//	        ├── footer.html      # Page footer template (optional)
+//	        └── header.html      # Page header template (optional)
+//
+// Scripts run by the renderer, the Mermaid and KaTeX bundles, are embedded
+// only and cannot be overridden (see MermaidScript and KatexScript).
 //
 // # Security
 //
@@ -63,7 +63,8 @@ func (e *EmbeddedLoader) LoadStyle(name string) (string, error) {
 }
 
 // LoadTemplateSet loads a set of HTML templates from embedded assets.
-// The name identifies a directory under templates/ containing cover.html and signature.html.
+// The name identifies a directory under templates/ containing cover.html and signature.html,
+// and optionally footer.html and header.html.
 func (e *EmbeddedLoader) LoadTemplateSet(name string) (*TemplateSet, error) {
 	if err := ValidateAssetName(name); err != nil {
 		return nil, err
@@ -87,10 +88,16 @@ func (e *EmbeddedLoader) LoadTemplateSet(name string) (*TemplateSet, error) {
 		return nil, fmt.Errorf("%w: %q missing signature.html", ErrIncompleteTemplateSet, name)
 	}
 
+	// Footer and header are optional
+	footer, _ := templates.ReadFile(basePath + FooterTemplateFile)
+	header, _ := templates.ReadFile(basePath + HeaderTemplateFile)
+
 	return &TemplateSet{
 		Name:      name,
 		Cover:     string(cover),
 		Signature: string(signature),
+		Footer:    string(footer),
+		Header:    string(header),
 	}, nil
 }
 
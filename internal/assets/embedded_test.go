@@ -142,6 +142,44 @@ func TestEmbeddedLoader_LoadTemplateSet(t *testing.T) {
 	}
 }
 
+func TestEmbeddedLoader_LoadStyle_ContainerRules(t *testing.T) {
+	t.Parallel()
+
+	loader := NewEmbeddedLoader()
+	wantRules := []string{
+		".markdown-alert ",
+		".markdown-alert-title",
+		".markdown-alert-note",
+		".markdown-alert-tip",
+		".markdown-alert-important",
+		".markdown-alert-warning",
+		".markdown-alert-caution",
+		".fenced-div",
+		".figure figcaption",
+		"table caption",
+		".figure-label",
+		".table-label",
+		".references .csl-entry",
+		".index .index-entry",
+	}
+
+	for _, name := range AvailableStyles() {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			css, err := loader.LoadStyle(name)
+			if err != nil {
+				t.Fatalf("LoadStyle(%q) unexpected error: %v", name, err)
+			}
+			for _, rule := range wantRules {
+				if !strings.Contains(css, rule) {
+					t.Errorf("LoadStyle(%q) missing rule %q", name, rule)
+				}
+			}
+		})
+	}
+}
+
 func TestEmbeddedLoaderImplementsAssetLoader(t *testing.T) {
 	t.Parallel()
 
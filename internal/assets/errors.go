@@ -28,4 +28,7 @@ var (
 
 	// ErrPathTraversal indicates an attempt to access files outside the base path.
 	ErrPathTraversal = errors.New("path traversal detected")
+
+	// ErrScriptNotFound indicates a bundled script is missing from the binary.
+	ErrScriptNotFound = errors.New("embedded script not found")
 )
@@ -0,0 +1,202 @@
+//go:build ignore
+
+// fetch_scripts downloads the script bundles pinned in scripts/*.version
+// and checks them against the sha256 pinned on the second line of that
+// file, before extracting anything. A bundle installed from the pinned
+// checksum is kept while scripts/.<name>.verified records it.
+//
+// Usage (from internal/assets, or through `make scripts`):
+//
+//	go run fetch_scripts.go        # fetch and verify
+//	go run fetch_scripts.go -pin   # after a version bump: record the new sha256
+//
+// With -pin, review the recorded checksum before committing it.
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// bundle is a downloaded library embedded by the assets package.
+type bundle struct {
+	name    string                                       // Base name of scripts/<name>.version
+	url     string                                       // Download URL, with %s for the version
+	extract func(data []byte) (map[string][]byte, error) // Files to write, by path in scripts/
+}
+
+var bundles = []bundle{
+	{
+		name: "mermaid",
+		url:  "https://cdn.jsdelivr.net/npm/mermaid@%s/dist/mermaid.min.js",
+		extract: func(data []byte) (map[string][]byte, error) {
+			return map[string][]byte{"mermaid.min.js": data}, nil
+		},
+	},
+	{
+		name:    "katex",
+		url:     "https://github.com/KaTeX/KaTeX/releases/download/v%s/katex.tar.gz",
+		extract: extractKatex,
+	},
+}
+
+const scriptsDir = "scripts"
+
+func main() {
+	pin := flag.Bool("pin", false, "record the sha256 of the downloaded bundles in their version files")
+	flag.Parse()
+
+	for _, b := range bundles {
+		if err := fetch(b, *pin); err != nil {
+			fmt.Fprintf(os.Stderr, "fetch_scripts: %s: %v\n", b.name, err)
+			os.Exit(1)
+		}
+	}
+}
+
+// fetch downloads b unless it is already on disk with the pinned checksum.
+func fetch(b bundle, pin bool) error {
+	versionPath := filepath.Join(scriptsDir, b.name+".version")
+	version, sum, err := readPin(versionPath)
+	if err != nil {
+		return err
+	}
+	if sum == "" && !pin {
+		return fmt.Errorf("%s pins no sha256 (run `make scripts-pin` and review the checksum)", versionPath)
+	}
+
+	stampPath := filepath.Join(scriptsDir, "."+b.name+".verified")
+	if !pin && verified(stampPath, sum) {
+		return nil
+	}
+
+	data, err := download(fmt.Sprintf(b.url, version))
+	if err != nil {
+		return err
+	}
+	got := sha256Hex(data)
+	if pin {
+		if err := os.WriteFile(versionPath, []byte(version+"\nsha256:"+got+"\n"), 0o644); err != nil {
+			return err
+		}
+		fmt.Printf("%s %s sha256:%s\n", b.name, version, got)
+	} else if got != sum {
+		return fmt.Errorf("%s %s checksum mismatch: got sha256:%s, want sha256:%s", b.name, version, got, sum)
+	}
+
+	files, err := b.extract(data)
+	if err != nil {
+		return err
+	}
+	stamp := got + "\n"
+	for name, content := range files {
+		target := filepath.Join(scriptsDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, content, 0o644); err != nil {
+			return err
+		}
+		stamp += name + "\n"
+	}
+	return os.WriteFile(stampPath, []byte(stamp), 0o644)
+}
+
+// verified reports whether the stamp at stampPath records sum and every
+// file it lists is still on disk.
+func verified(stampPath, sum string) bool {
+	data, err := os.ReadFile(stampPath)
+	if err != nil {
+		return false
+	}
+	lines := strings.Fields(string(data))
+	if len(lines) < 2 || lines[0] != sum {
+		return false
+	}
+	for _, name := range lines[1:] {
+		if _, err := os.Stat(filepath.Join(scriptsDir, filepath.FromSlash(name))); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// extractKatex returns the script, stylesheet and woff2 fonts of the KaTeX
+// release tarball. The woff and ttf fallbacks are not embedded.
+func extractKatex(data []byte) (map[string][]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(gz)
+	files := make(map[string][]byte)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		name := strings.TrimPrefix(hdr.Name, "katex/")
+		keep := name == "katex.min.js" || name == "katex.min.css" ||
+			(path.Dir(name) == "fonts" && path.Ext(name) == ".woff2")
+		if hdr.Typeflag != tar.TypeReg || !keep {
+			continue
+		}
+		if files[name], err = io.ReadAll(tr); err != nil {
+			return nil, err
+		}
+	}
+	if files["katex.min.js"] == nil || files["katex.min.css"] == nil {
+		return nil, fmt.Errorf("tarball has no katex.min.js or katex.min.css")
+	}
+	return files, nil
+}
+
+// readPin reads a version file: the version, then an optional sha256:<hex> line.
+func readPin(path string) (version, sum string, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", err
+	}
+	lines := strings.Fields(string(data))
+	if len(lines) == 0 {
+		return "", "", fmt.Errorf("%s is empty", path)
+	}
+	if len(lines) > 1 {
+		sum = strings.TrimPrefix(lines[1], "sha256:")
+	}
+	return lines[0], sum, nil
+}
+
+// download returns the body of url.
+func download(url string) ([]byte, error) {
+	client := &http.Client{Timeout: 5 * time.Minute}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
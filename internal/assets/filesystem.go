@@ -79,7 +79,8 @@ func (f *FilesystemLoader) LoadStyle(name string) (string, error) {
 }
 
 // LoadTemplateSet loads a set of HTML templates from the filesystem.
-// Looks for {basePath}/templates/{name}/cover.html and signature.html
+// Looks for {basePath}/templates/{name}/cover.html and signature.html, and
+// the optional footer.html and header.html.
 func (f *FilesystemLoader) LoadTemplateSet(name string) (*TemplateSet, error) {
 	if err := ValidateAssetName(name); err != nil {
 		return nil, err
@@ -119,13 +120,37 @@ func (f *FilesystemLoader) LoadTemplateSet(name string) (*TemplateSet, error) {
 		return nil, fmt.Errorf("%w: %q missing signature.html", ErrIncompleteTemplateSet, name)
 	}
 
+	footer, err := readOptionalTemplate(filepath.Join(dirPath, FooterTemplateFile))
+	if err != nil {
+		return nil, err
+	}
+	header, err := readOptionalTemplate(filepath.Join(dirPath, HeaderTemplateFile))
+	if err != nil {
+		return nil, err
+	}
+
 	return &TemplateSet{
 		Name:      name,
 		Cover:     string(cover),
 		Signature: string(signature),
+		Footer:    footer,
+		Header:    header,
 	}, nil
 }
 
+// readOptionalTemplate reads a template file that a set may omit.
+// Returns an empty string if the file does not exist.
+func readOptionalTemplate(path string) (string, error) {
+	content, err := os.ReadFile(path) // #nosec G304 -- directory validated by caller
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("%w: reading %s: %w", ErrAssetRead, filepath.Base(path), err)
+	}
+	return string(content), nil
+}
+
 // verifyPathContainment ensures the resolved file path is within basePath.
 // Prevents path traversal attacks even if name validation is bypassed.
 // Resolves symlinks to prevent escape via symlink pointing outside basePath.
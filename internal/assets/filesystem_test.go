@@ -164,6 +164,47 @@ func TestFilesystemLoader_LoadTemplateSet(t *testing.T) {
 		if ts.Signature != sigContent {
 			t.Errorf("LoadTemplateSet(\"custom\").Signature = %q, want %q", ts.Signature, sigContent)
 		}
+		if ts.Footer != "" || ts.Header != "" {
+			t.Errorf("LoadTemplateSet(\"custom\") Footer = %q, Header = %q, want empty", ts.Footer, ts.Header)
+		}
+	})
+
+	t.Run("happy path: optional footer and header", func(t *testing.T) {
+		t.Parallel()
+
+		tmpDir := t.TempDir()
+		setDir := filepath.Join(tmpDir, "templates", "custom")
+		if err := os.MkdirAll(setDir, 0755); err != nil {
+			t.Fatalf("failed to create template set dir: %v", err)
+		}
+
+		files := map[string]string{
+			"cover.html":       "<div>cover</div>",
+			"signature.html":   "<div>signature</div>",
+			FooterTemplateFile: "<div>footer</div>",
+			HeaderTemplateFile: "<div>header</div>",
+		}
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(setDir, name), []byte(content), 0644); err != nil {
+				t.Fatalf("failed to write %s: %v", name, err)
+			}
+		}
+
+		loader, err := NewFilesystemLoader(tmpDir)
+		if err != nil {
+			t.Fatalf("NewFilesystemLoader(%q) unexpected error: %v", tmpDir, err)
+		}
+
+		ts, err := loader.LoadTemplateSet("custom")
+		if err != nil {
+			t.Fatalf("LoadTemplateSet(\"custom\") unexpected error: %v", err)
+		}
+		if ts.Footer != files[FooterTemplateFile] {
+			t.Errorf("LoadTemplateSet(\"custom\").Footer = %q, want %q", ts.Footer, files[FooterTemplateFile])
+		}
+		if ts.Header != files[HeaderTemplateFile] {
+			t.Errorf("LoadTemplateSet(\"custom\").Header = %q, want %q", ts.Header, files[HeaderTemplateFile])
+		}
 	})
 
 	t.Run("error case: nonexistent template set", func(t *testing.T) {
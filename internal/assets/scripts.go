@@ -0,0 +1,109 @@
+package assets
+
+import (
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+//go:generate go run fetch_scripts.go
+
+// Bundled script files. Bundles are fetched at the pinned version and
+// checksum by go generate (or `make scripts`) and embedded by builds with
+// -tags bundles; see scripts_embed.go.
+const (
+	mermaidScriptPath  = "scripts/mermaid.min.js"
+	mermaidVersionPath = "scripts/mermaid.version"
+	katexScriptPath    = "scripts/katex.min.js"
+	katexStylePath     = "scripts/katex.min.css"
+	katexVersionPath   = "scripts/katex.version"
+	katexFontDir       = "scripts/fonts/"
+)
+
+var (
+	// Font fallbacks other than woff2, which are not embedded.
+	// Example: ,url(fonts/KaTeX_Main-Regular.woff) format("woff")
+	katexFallbackFontPattern = regexp.MustCompile(`,\s*url\(fonts/[^)]+\.(?:woff|ttf)\)\s*format\("(?:woff|truetype)"\)`)
+
+	// Font reference in the KaTeX stylesheet.
+	// Captures: 1=file name
+	katexFontPattern = regexp.MustCompile(`url\(fonts/([^)]+\.woff2)\)`)
+)
+
+// MermaidScript returns the embedded Mermaid bundle.
+// Returns ErrScriptNotFound if the binary was built without -tags bundles.
+func MermaidScript() (string, error) {
+	return readScript(mermaidScriptPath, "mermaid", MermaidVersion())
+}
+
+// MermaidVersion returns the pinned Mermaid version.
+func MermaidVersion() string {
+	return readVersion(mermaidVersionPath)
+}
+
+// KatexScript returns the embedded KaTeX bundle.
+// Returns ErrScriptNotFound if the binary was built without -tags bundles.
+func KatexScript() (string, error) {
+	return readScript(katexScriptPath, "katex", KatexVersion())
+}
+
+// KatexCSS returns the KaTeX stylesheet with its fonts inlined as data URIs,
+// so rendered math needs no file or network access.
+// Returns ErrScriptNotFound if the stylesheet or a font is missing.
+func KatexCSS() (string, error) {
+	css, err := readScript(katexStylePath, "katex", KatexVersion())
+	if err != nil {
+		return "", err
+	}
+	return inlineKatexFonts(css, func(name string) ([]byte, error) {
+		return scripts.ReadFile(katexFontDir + name)
+	})
+}
+
+// inlineKatexFonts replaces the woff2 font URLs of a KaTeX stylesheet with
+// data URIs read by readFont, and drops the woff and ttf fallbacks.
+func inlineKatexFonts(css string, readFont func(name string) ([]byte, error)) (string, error) {
+	css = katexFallbackFontPattern.ReplaceAllString(css, "")
+
+	var missing string
+	css = katexFontPattern.ReplaceAllStringFunc(css, func(m string) string {
+		name := katexFontPattern.FindStringSubmatch(m)[1]
+		font, err := readFont(name)
+		if err != nil {
+			missing = name
+			return m
+		}
+		return "url(data:font/woff2;base64," + base64.StdEncoding.EncodeToString(font) + ")"
+	})
+	if missing != "" {
+		return "", fmt.Errorf("%w: katex font %s (binary built without -tags bundles)", ErrScriptNotFound, missing)
+	}
+	return css, nil
+}
+
+// KatexVersion returns the pinned KaTeX version.
+func KatexVersion() string {
+	return readVersion(katexVersionPath)
+}
+
+// readScript returns an embedded bundle file, naming the pinned version of
+// the library when it is missing.
+func readScript(path, library, version string) (string, error) {
+	data, err := scripts.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s %s (binary built without -tags bundles)", ErrScriptNotFound, library, version)
+	}
+	return string(data), nil
+}
+
+// readVersion returns the version pinned on the first line of an embedded
+// version file; the second line pins the checksum fetch_scripts.go checks.
+func readVersion(path string) string {
+	data, err := scripts.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	version, _, _ := strings.Cut(string(data), "\n")
+	return strings.TrimSpace(version)
+}
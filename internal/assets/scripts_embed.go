@@ -0,0 +1,15 @@
+//go:build bundles
+
+package assets
+
+import "embed"
+
+// bundled reports whether the script bundles are embedded.
+const bundled = true
+
+// Builds with -tags bundles need the bundles on disk: run `make scripts`
+// first.
+//
+//go:embed scripts/*.version scripts/mermaid.min.js
+//go:embed scripts/katex.min.js scripts/katex.min.css scripts/fonts/*.woff2
+var scripts embed.FS
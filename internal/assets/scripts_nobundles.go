@@ -0,0 +1,14 @@
+//go:build !bundles
+
+package assets
+
+import "embed"
+
+// bundled reports whether the script bundles are embedded.
+const bundled = false
+
+// Without -tags bundles, only the version files are embedded: rendering
+// diagrams or math returns ErrScriptNotFound.
+//
+//go:embed scripts/*.version
+var scripts embed.FS
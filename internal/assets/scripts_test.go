@@ -0,0 +1,94 @@
+package assets
+
+import (
+	"errors"
+	"io/fs"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestScriptVersions(t *testing.T) {
+	t.Parallel()
+
+	semver := regexp.MustCompile(`^\d+\.\d+\.\d+$`)
+	for name, got := range map[string]string{"MermaidVersion": MermaidVersion(), "KatexVersion": KatexVersion()} {
+		if !semver.MatchString(got) {
+			t.Errorf("%s() = %q, want a semantic version", name, got)
+		}
+	}
+}
+
+func TestScripts(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		load    func() (string, error)
+		version string
+		want    string
+	}{
+		{"MermaidScript", MermaidScript, MermaidVersion(), "mermaid"},
+		{"KatexScript", KatexScript, KatexVersion(), "katex"},
+		{"KatexCSS", KatexCSS, KatexVersion(), ".katex"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			script, err := tt.load()
+			if err != nil && bundled {
+				t.Fatalf("%s() unexpected error: %v", tt.name, err)
+			}
+			if err != nil {
+				// Builds without -tags bundles report the missing bundle.
+				if !errors.Is(err, ErrScriptNotFound) {
+					t.Errorf("%s() error = %v, want %v", tt.name, err, ErrScriptNotFound)
+				}
+				if !strings.Contains(err.Error(), tt.version) && !strings.Contains(err.Error(), "font") {
+					t.Errorf("%s() error = %q, want pinned version", tt.name, err)
+				}
+				return
+			}
+			if !strings.Contains(script, tt.want) {
+				t.Errorf("%s() does not look like the bundle", tt.name)
+			}
+		})
+	}
+}
+
+func TestInlineKatexFonts(t *testing.T) {
+	t.Parallel()
+
+	css := `@font-face{font-family:KaTeX_Main;src:url(fonts/KaTeX_Main-Regular.woff2) format("woff2"),url(fonts/KaTeX_Main-Regular.woff) format("woff"),url(fonts/KaTeX_Main-Regular.ttf) format("truetype")}`
+	fonts := map[string][]byte{"KaTeX_Main-Regular.woff2": []byte("wOF2")}
+	readFont := func(name string) ([]byte, error) {
+		if data, ok := fonts[name]; ok {
+			return data, nil
+		}
+		return nil, fs.ErrNotExist
+	}
+
+	t.Run("inlines woff2 and drops fallbacks", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := inlineKatexFonts(css, readFont)
+		if err != nil {
+			t.Fatalf("inlineKatexFonts() unexpected error: %v", err)
+		}
+		want := `@font-face{font-family:KaTeX_Main;src:url(data:font/woff2;base64,d09GMg==) format("woff2")}`
+		if got != want {
+			t.Errorf("inlineKatexFonts() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("missing font returns error", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := inlineKatexFonts(strings.ReplaceAll(css, "Main-Regular", "Math-Italic"), readFont)
+		if !errors.Is(err, ErrScriptNotFound) || !strings.Contains(err.Error(), "KaTeX_Math-Italic.woff2") {
+			t.Errorf("inlineKatexFonts() error = %v, want missing KaTeX_Math-Italic.woff2", err)
+		}
+	})
+}
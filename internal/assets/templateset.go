@@ -1,13 +1,23 @@
 package assets
 
 // TemplateSet holds the HTML templates for document generation.
-// A template set contains cover and signature templates that work together.
+// A template set contains cover and signature templates that work together,
+// plus optional footer and header templates for Chrome's print margins.
 type TemplateSet struct {
 	Name      string // Identifier (name or directory path)
 	Cover     string // Cover page template HTML content
 	Signature string // Signature block template HTML content
+	Footer    string // Page footer template HTML content (optional)
+	Header    string // Page header template HTML content (optional)
 }
 
+// Optional template file names. A template set without them falls back to
+// the generated footer and header.
+const (
+	FooterTemplateFile = "footer.html"
+	HeaderTemplateFile = "header.html"
+)
+
 // DefaultTemplateSetName is the name of the built-in template set.
 const DefaultTemplateSetName = "default"
 
@@ -0,0 +1,491 @@
+package bibliography
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// bibTeXTypes maps BibTeX entry types to CSL types.
+var bibTeXTypes = map[string]string{
+	"article":       "article-journal",
+	"book":          "book",
+	"booklet":       "pamphlet",
+	"inbook":        "chapter",
+	"incollection":  "chapter",
+	"inproceedings": "paper-conference",
+	"conference":    "paper-conference",
+	"proceedings":   "book",
+	"manual":        "report",
+	"report":        "report",
+	"techreport":    "report",
+	"thesis":        "thesis",
+	"phdthesis":     "thesis",
+	"mastersthesis": "thesis",
+	"online":        "webpage",
+	"electronic":    "webpage",
+	"unpublished":   "manuscript",
+	"misc":          "document",
+}
+
+// bibTeXMonths holds the predefined month macros.
+var bibTeXMonths = map[string]string{
+	"jan": "January", "feb": "February", "mar": "March", "apr": "April",
+	"may": "May", "jun": "June", "jul": "July", "aug": "August",
+	"sep": "September", "oct": "October", "nov": "November", "dec": "December",
+}
+
+var (
+	// Accent command with a symbol, e.g. \'e, \'{e} or \"{\i}.
+	// Captures: 1=accent, 2=braced letter, 3=bare letter
+	symbolAccentPattern = regexp.MustCompile(`\\([` + "`" + `'"^~=.])\s*(?:\{(\\?[A-Za-z])\}|(\\?[A-Za-z]))`)
+
+	// Accent command with a letter, e.g. \c{c} or \v s.
+	// Captures: 1=accent, 2=braced letter, 3=bare letter
+	letterAccentPattern = regexp.MustCompile(`\\([cvuHkr])(?:\s*\{(\\?[A-Za-z])\}|\s+(\\?[A-Za-z]))`)
+
+	// Special letters, e.g. \ss or \o{}.
+	// Captures: 1=command
+	specialLetterPattern = regexp.MustCompile(`\\(ss|aa|AA|ae|AE|oe|OE|o|O|l|L|i)(?:\{\}|\b)\s?`)
+
+	// Runs of white space other than non-breaking spaces.
+	spacePattern = regexp.MustCompile(`[ \t\r\n]+`)
+
+	// TeX logos, written as their names.
+	// Captures: 1=name
+	logoPattern = regexp.MustCompile(`\\(TeX|LaTeX|BibTeX)\b(?:\{\})?`)
+
+	// Any other command, dropped so its argument is kept as text.
+	latexCommandPattern = regexp.MustCompile(`\\[A-Za-z]+\*?\s*`)
+
+	// " and " between names, matched case-insensitively.
+	nameSeparatorPattern = regexp.MustCompile(`(?i)^\s+and\s+`)
+)
+
+// accentMarks maps accent commands to combining characters, used when a
+// letter has no precomposed form in accentLetters.
+var accentMarks = map[string]rune{
+	"'": '\u0301', "`": '\u0300', "^": '\u0302', `"`: '\u0308', "~": '\u0303',
+	"=": '\u0304', ".": '\u0307', "c": '\u0327', "v": '\u030C', "u": '\u0306',
+	"H": '\u030B', "k": '\u0328', "r": '\u030A',
+}
+
+// accentLetters maps accent commands to letters and their precomposed forms,
+// position for position.
+var accentLetters = map[string][2]string{
+	"'": {"aeiouyAEIOUYcnszCNSZ", "áéíóúýÁÉÍÓÚÝćńśźĆŃŚŹ"},
+	"`": {"aeiouAEIOU", "àèìòùÀÈÌÒÙ"},
+	"^": {"aeiouAEIOU", "âêîôûÂÊÎÔÛ"},
+	`"`: {"aeiouyAEIOUY", "äëïöüÿÄËÏÖÜŸ"},
+	"~": {"anoANO", "ãñõÃÑÕ"},
+	"=": {"aeiouAEIOU", "āēīōūĀĒĪŌŪ"},
+	".": {"zeZEI", "żėŻĖİ"},
+	"c": {"csCS", "çşÇŞ"},
+	"v": {"cszrenCSZREN", "čšžřěňČŠŽŘĚŇ"},
+	"u": {"agAG", "ăğĂĞ"},
+	"H": {"ouOU", "őűŐŰ"},
+	"k": {"aeAE", "ąęĄĘ"},
+	"r": {"aA", "åÅ"},
+}
+
+// specialLetters maps letter commands to their characters.
+var specialLetters = map[string]string{
+	"ss": "ß", "aa": "å", "AA": "Å", "ae": "æ", "AE": "Æ", "oe": "œ", "OE": "Œ",
+	"o": "ø", "O": "Ø", "l": "ł", "L": "Ł", "i": "ı",
+}
+
+// ParseBibTeX parses BibTeX entries. @string macros and the month macros are
+// expanded, and @comment and @preamble blocks are skipped. LaTeX accents and
+// escapes in values are converted to text.
+func ParseBibTeX(data []byte) ([]Entry, error) {
+	p := &bibTeXParser{src: string(data), macros: make(map[string]string)}
+	var entries []Entry
+	for {
+		at := strings.IndexByte(p.src[p.pos:], '@')
+		if at < 0 {
+			return entries, nil
+		}
+		p.pos += at + 1
+		entryType := strings.ToLower(p.ident())
+		entry, ok, err := p.entry(entryType)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			entries = append(entries, entry)
+		}
+	}
+}
+
+// bibTeXParser reads BibTeX source from pos.
+type bibTeXParser struct {
+	src    string
+	pos    int
+	macros map[string]string
+}
+
+// entry parses the body of an entry of the given type, after the @type.
+// ok is false for @string, @comment and @preamble.
+func (p *bibTeXParser) entry(entryType string) (Entry, bool, error) {
+	p.skipSpace()
+	if p.pos >= len(p.src) || (p.src[p.pos] != '{' && p.src[p.pos] != '(') {
+		return Entry{}, false, nil // An @ outside an entry is a comment
+	}
+	closer := byte('}')
+	if p.src[p.pos] == '(' {
+		closer = ')'
+	}
+	p.pos++
+
+	switch entryType {
+	case "comment", "preamble":
+		return Entry{}, false, p.skipBlock(closer)
+	case "string":
+		name, value, err := p.field()
+		if err != nil {
+			return Entry{}, false, err
+		}
+		p.macros[name] = value
+		p.skipSpace()
+		return Entry{}, false, p.expect(closer)
+	}
+
+	keyEnd := strings.IndexAny(p.src[p.pos:], ",\n"+string(closer))
+	if keyEnd < 0 {
+		return Entry{}, false, p.errorf("unterminated @%s entry", entryType)
+	}
+	key := strings.TrimSpace(p.src[p.pos : p.pos+keyEnd])
+	if key == "" {
+		return Entry{}, false, p.errorf("@%s entry without a key", entryType)
+	}
+	p.pos += keyEnd
+
+	fields := make(map[string]string)
+	for {
+		p.skipSpace()
+		if p.pos < len(p.src) && p.src[p.pos] == ',' {
+			p.pos++
+			p.skipSpace()
+		}
+		if p.pos >= len(p.src) {
+			return Entry{}, false, p.errorf("unterminated entry %q", key)
+		}
+		if p.src[p.pos] == closer {
+			p.pos++
+			break
+		}
+		name, value, err := p.field()
+		if err != nil {
+			return Entry{}, false, fmt.Errorf("entry %q: %w", key, err)
+		}
+		fields[name] = value
+	}
+	return bibTeXEntry(key, entryType, fields), true, nil
+}
+
+// field parses "name = value".
+func (p *bibTeXParser) field() (string, string, error) {
+	p.skipSpace()
+	name := strings.ToLower(p.ident())
+	if name == "" {
+		return "", "", p.errorf("expected field name")
+	}
+	p.skipSpace()
+	if err := p.expect('='); err != nil {
+		return "", "", err
+	}
+	value, err := p.value()
+	return name, value, err
+}
+
+// value parses a value: braced or quoted strings, numbers and macros,
+// joined with #.
+func (p *bibTeXParser) value() (string, error) {
+	var b strings.Builder
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.src) {
+			return "", p.errorf("expected value")
+		}
+		switch c := p.src[p.pos]; {
+		case c == '{':
+			s, err := p.delimited('{', '}')
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(s)
+		case c == '"':
+			s, err := p.delimited('"', '"')
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(s)
+		default:
+			word := p.ident()
+			if word == "" {
+				return "", p.errorf("unexpected %q in value", c)
+			}
+			switch macro, month := p.macros[strings.ToLower(word)], bibTeXMonths[strings.ToLower(word)]; {
+			case macro != "":
+				b.WriteString(macro)
+			case month != "":
+				b.WriteString(month)
+			default:
+				b.WriteString(word)
+			}
+		}
+		p.skipSpace()
+		if p.pos >= len(p.src) || p.src[p.pos] != '#' {
+			return b.String(), nil
+		}
+		p.pos++
+	}
+}
+
+// delimited returns the text between open and close, keeping nested
+// braces. A quote inside braces does not end a quoted value.
+func (p *bibTeXParser) delimited(open, close byte) (string, error) {
+	start := p.pos
+	p.pos++
+	depth := 0
+	for ; p.pos < len(p.src); p.pos++ {
+		switch c := p.src[p.pos]; {
+		case c == '\\':
+			p.pos++
+		case c == close && depth == 0:
+			p.pos++
+			return p.src[start+1 : p.pos-1], nil
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+		}
+	}
+	p.pos = start
+	return "", p.errorf("unterminated %c", open)
+}
+
+// skipBlock skips to the closer matching an opened block.
+func (p *bibTeXParser) skipBlock(closer byte) error {
+	p.pos--
+	open := p.src[p.pos]
+	if _, err := p.delimited(open, closer); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ident reads an identifier: a run of characters that cannot end a value.
+func (p *bibTeXParser) ident() string {
+	start := p.pos
+	for p.pos < len(p.src) && !strings.ContainsRune(" \t\r\n{}()\",=#%", rune(p.src[p.pos])) {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *bibTeXParser) skipSpace() {
+	for p.pos < len(p.src) && unicode.IsSpace(rune(p.src[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *bibTeXParser) expect(c byte) error {
+	if p.pos >= len(p.src) || p.src[p.pos] != c {
+		return p.errorf("expected %q", c)
+	}
+	p.pos++
+	return nil
+}
+
+// errorf reports an error at the current line.
+func (p *bibTeXParser) errorf(format string, args ...any) error {
+	line := strings.Count(p.src[:min(p.pos, len(p.src))], "\n") + 1
+	return fmt.Errorf("%w: line %d: %s", ErrParse, line, fmt.Sprintf(format, args...))
+}
+
+// bibTeXEntry converts BibTeX fields to an Entry.
+func bibTeXEntry(key, entryType string, fields map[string]string) Entry {
+	first := func(names ...string) string {
+		for _, name := range names {
+			if v := fields[name]; v != "" {
+				return latexToText(v)
+			}
+		}
+		return ""
+	}
+
+	cslType, ok := bibTeXTypes[entryType]
+	if !ok {
+		cslType = "document"
+	}
+	e := Entry{
+		ID:             key,
+		Type:           cslType,
+		Authors:        parseBibTeXNames(fields["author"]),
+		Editors:        parseBibTeXNames(fields["editor"]),
+		Title:          first("title"),
+		ContainerTitle: first("journal", "journaltitle", "booktitle"),
+		Publisher:      first("publisher", "institution", "school", "organization"),
+		Year:           first("year"),
+		Volume:         first("volume"),
+		Issue:          first("number", "issue"),
+		Pages:          first("pages"),
+		DOI:            strings.TrimSpace(fields["doi"]),
+		URL:            strings.TrimSpace(fields["url"]),
+	}
+	if date := first("date"); e.Year == "" && len(date) >= 4 {
+		e.Year = date[:4]
+	}
+	return e
+}
+
+// parseBibTeXNames splits an author or editor field on "and" and parses
+// each name: "Family, Given", "Given Family" or "{Literal Name}".
+func parseBibTeXNames(field string) []Name {
+	field = strings.TrimSpace(field)
+	if field == "" {
+		return nil
+	}
+
+	var names []Name
+	depth, start := 0, 0
+	for i := 0; i < len(field); i++ {
+		switch field[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ' ', '\t', '\n':
+			if depth == 0 {
+				if m := nameSeparatorPattern.FindString(field[i:]); m != "" {
+					names = append(names, parseBibTeXName(field[start:i]))
+					i += len(m) - 1
+					start = i + 1
+				}
+			}
+		}
+	}
+	return append(names, parseBibTeXName(field[start:]))
+}
+
+// parseBibTeXName parses one name.
+func parseBibTeXName(raw string) Name {
+	raw = strings.TrimSpace(raw)
+	if strings.HasPrefix(raw, "{") && strings.HasSuffix(raw, "}") && braceDepthZeroOnlyAtEnds(raw) {
+		return Name{Literal: latexToText(raw)}
+	}
+
+	if parts := splitTopLevel(raw, ','); len(parts) > 1 {
+		return Name{
+			Family: latexToText(parts[0]),
+			Given:  latexToText(parts[len(parts)-1]),
+		}
+	}
+
+	words := splitTopLevel(raw, ' ')
+	if len(words) == 1 {
+		return Name{Family: latexToText(words[0])}
+	}
+	// The family name starts at the first lowercase particle ("van", "de")
+	// or is the last word.
+	split := len(words) - 1
+	for i, w := range words[:len(words)-1] {
+		if i > 0 && w != "" && unicode.IsLower(rune(w[0])) {
+			split = i
+			break
+		}
+	}
+	return Name{
+		Family: latexToText(strings.Join(words[split:], " ")),
+		Given:  latexToText(strings.Join(words[:split], " ")),
+	}
+}
+
+// braceDepthZeroOnlyAtEnds reports whether the braces opening and closing s
+// enclose all of it.
+func braceDepthZeroOnlyAtEnds(s string) bool {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 && i != len(s)-1 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// splitTopLevel splits s on sep outside braces, dropping empty parts.
+func splitTopLevel(s string, sep byte) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i <= len(s); i++ {
+		if i < len(s) {
+			switch s[i] {
+			case '{':
+				depth++
+				continue
+			case '}':
+				depth--
+				continue
+			}
+			if s[i] != sep || depth != 0 {
+				continue
+			}
+		}
+		if part := strings.TrimSpace(s[start:i]); part != "" {
+			parts = append(parts, part)
+		}
+		start = i + 1
+	}
+	return parts
+}
+
+// latexToText converts LaTeX markup in a BibTeX value to plain text.
+func latexToText(s string) string {
+	if !strings.ContainsAny(s, `\{}~-`) {
+		return collapseSpace(s)
+	}
+
+	s = symbolAccentPattern.ReplaceAllStringFunc(s, accentReplacer(symbolAccentPattern))
+	s = letterAccentPattern.ReplaceAllStringFunc(s, accentReplacer(letterAccentPattern))
+	s = specialLetterPattern.ReplaceAllStringFunc(s, func(m string) string {
+		return specialLetters[specialLetterPattern.FindStringSubmatch(m)[1]]
+	})
+	s = logoPattern.ReplaceAllString(s, "$1")
+	s = strings.NewReplacer(
+		`\&`, "&", `\%`, "%", `\$`, "$", `\#`, "#", `\_`, "_",
+		`\{`, "\x00", `\}`, "\x01", `\\`, " ",
+		"---", "\u2014", "--", "\u2013", "~", "\u00a0",
+	).Replace(s)
+	s = latexCommandPattern.ReplaceAllString(s, "")
+	s = strings.NewReplacer("{", "", "}", "", "\x00", "{", "\x01", "}").Replace(s)
+	return collapseSpace(s)
+}
+
+// collapseSpace trims s and collapses runs of white space to one space,
+// keeping the non-breaking spaces written as ~.
+func collapseSpace(s string) string {
+	return strings.Trim(spacePattern.ReplaceAllString(s, " "), " ")
+}
+
+// accentReplacer returns a replacement function for an accent pattern.
+func accentReplacer(pattern *regexp.Regexp) func(string) string {
+	return func(m string) string {
+		sub := pattern.FindStringSubmatch(m)
+		accent, letter := sub[1], sub[2]+sub[3]
+		letter = strings.TrimPrefix(letter, `\`) // \i and \j are dotless i and j
+		if forms, ok := accentLetters[accent]; ok {
+			if i := strings.Index(forms[0], letter); i >= 0 {
+				return string([]rune(forms[1])[i])
+			}
+		}
+		return letter + string(accentMarks[accent])
+	}
+}
@@ -0,0 +1,195 @@
+package bibliography
+
+// Notes:
+// - Values are compared after LaTeX conversion, as Entry holds them
+// - Load is tested with files in t.TempDir()
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// TestParseBibTeX - Entries, Fields and Macros
+// ---------------------------------------------------------------------------
+
+func TestParseBibTeX(t *testing.T) {
+	t.Parallel()
+
+	src := `% A comment line
+@string{tug = "TUGboat"}
+@comment{ignored @book{x}}
+@Article{lamport1986,
+  author  = {Leslie Lamport and Knuth, Donald E.},
+  title   = {{LaTeX}: A Document Preparation System},
+  journal = tug # " Journal",
+  volume  = 7,
+  number  = {3},
+  pages   = {10--20},
+  month   = jun,
+  year    = "1986",
+  doi     = {10.1000/xyz}
+}
+@incollection(smith2020,
+  author    = "van der Berg, Jan",
+  editor    = {{World Health Organization}},
+  title     = "Chapter",
+  booktitle = {Collected Works},
+  publisher = {Press}
+)`
+
+	got, err := ParseBibTeX([]byte(src))
+	if err != nil {
+		t.Fatalf("ParseBibTeX() unexpected error: %v", err)
+	}
+
+	want := []Entry{
+		{
+			ID:             "lamport1986",
+			Type:           "article-journal",
+			Authors:        []Name{{Family: "Lamport", Given: "Leslie"}, {Family: "Knuth", Given: "Donald E."}},
+			Title:          "LaTeX: A Document Preparation System",
+			ContainerTitle: "TUGboat Journal",
+			Year:           "1986",
+			Volume:         "7",
+			Issue:          "3",
+			Pages:          "10–20",
+			DOI:            "10.1000/xyz",
+		},
+		{
+			ID:             "smith2020",
+			Type:           "chapter",
+			Authors:        []Name{{Family: "van der Berg", Given: "Jan"}},
+			Editors:        []Name{{Literal: "World Health Organization"}},
+			Title:          "Chapter",
+			ContainerTitle: "Collected Works",
+			Publisher:      "Press",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseBibTeX() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestParseBibTeX_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		wantMsg string
+	}{
+		{"unclosed entry", "@book{a,\n title = {x}", `line 2: unterminated entry "a"`},
+		{"missing key", "@book{\n title = {x}}", "line 1: @book entry without a key"},
+		{"missing equals", "@book{a,\n title {x}}", `entry "a": invalid bibliography: line 2: expected '='`},
+		{"unterminated value", "@book{a,\n title = {x{y}", "line 2: unterminated {"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ParseBibTeX([]byte(tt.input))
+			if !errors.Is(err, ErrParse) {
+				t.Fatalf("ParseBibTeX() error = %v, want %v", err, ErrParse)
+			}
+			if !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("ParseBibTeX() error = %q, want it to contain %q", err.Error(), tt.wantMsg)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestLatexToText - Accents, Escapes and Braces
+// ---------------------------------------------------------------------------
+
+func TestLatexToText(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`Erd\H{o}s`, "Erdős"},
+		{`G{\"o}del`, "Gödel"},
+		{`Fran\c{c}ois`, "François"},
+		{`\'{\i}`, "í"},
+		{`Stra\ss e`, "Straße"},
+		{`The {\TeX}book`, "The TeXbook"},
+		{`R\&D \emph{now}`, "R&D now"},
+		{`pages 1--2 --- done`, "pages 1–2 — done"},
+		{`Dr.~Who`, "Dr.\u00a0Who"},
+		{"  many\n  spaces ", "many spaces"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			if got := latexToText(tt.input); got != tt.want {
+				t.Errorf("latexToText(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestLoad - File Formats
+// ---------------------------------------------------------------------------
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("WriteFile() unexpected error: %v", err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantIDs []string
+		wantErr error
+	}{
+		{"bibtex", write("refs.bib", "@book{a, title={A}}\n@misc{b, title={B}}"), []string{"a", "b"}, nil},
+		{"csl json", write("refs.json", `[{"id": "c", "title": "C"}]`), []string{"c"}, nil},
+		{"duplicate key", write("dup.bib", "@book{a, title={A}}\n@book{a, title={B}}"), nil, ErrParse},
+		{"unsupported extension", write("refs.ris", "TY  - BOOK"), nil, ErrUnsupportedFormat},
+		{"missing file", filepath.Join(dir, "missing.bib"), nil, os.ErrNotExist},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Load(tt.path)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Load() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() unexpected error: %v", err)
+			}
+			for _, id := range tt.wantIDs {
+				if got[id].ID != id {
+					t.Errorf("Load() missing entry %q", id)
+				}
+			}
+			if len(got) != len(tt.wantIDs) {
+				t.Errorf("Load() returned %d entries, want %d", len(got), len(tt.wantIDs))
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package bibliography
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// cslItem is a CSL-JSON item, limited to the variables Entry holds.
+type cslItem struct {
+	ID             cslString `json:"id"`
+	Type           string    `json:"type"`
+	Author         []cslName `json:"author"`
+	Editor         []cslName `json:"editor"`
+	Title          string    `json:"title"`
+	ContainerTitle cslString `json:"container-title"`
+	Publisher      string    `json:"publisher"`
+	Issued         *cslDate  `json:"issued"`
+	Volume         cslString `json:"volume"`
+	Issue          cslString `json:"issue"`
+	Page           cslString `json:"page"`
+	DOI            string    `json:"DOI"`
+	URL            string    `json:"URL"`
+}
+
+type cslName struct {
+	Family   string `json:"family"`
+	Given    string `json:"given"`
+	Literal  string `json:"literal"`
+	Particle string `json:"non-dropping-particle"`
+}
+
+type cslDate struct {
+	DateParts [][]cslString `json:"date-parts"`
+	Literal   string        `json:"literal"`
+	Raw       string        `json:"raw"`
+}
+
+// cslString accepts a JSON string or number, as CSL-JSON writers disagree
+// on fields like volume and page. An array keeps its first element.
+type cslString string
+
+func (s *cslString) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case len(data) == 0 || string(data) == "null":
+		return nil
+	case data[0] == '"':
+		var v string
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		*s = cslString(v)
+	case data[0] == '[':
+		var v []cslString
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		if len(v) > 0 {
+			*s = v[0]
+		}
+	default:
+		var v json.Number
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		*s = cslString(v.String())
+	}
+	return nil
+}
+
+// ParseCSLJSON parses a CSL-JSON array of items, as exported by Zotero and
+// written by pandoc-citeproc.
+func ParseCSLJSON(data []byte) ([]Entry, error) {
+	var items []cslItem
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrParse, err)
+	}
+
+	entries := make([]Entry, 0, len(items))
+	for i, item := range items {
+		if item.ID == "" {
+			return nil, fmt.Errorf("%w: item %d has no id", ErrParse, i+1)
+		}
+		entries = append(entries, Entry{
+			ID:             string(item.ID),
+			Type:           item.Type,
+			Authors:        cslNames(item.Author),
+			Editors:        cslNames(item.Editor),
+			Title:          item.Title,
+			ContainerTitle: string(item.ContainerTitle),
+			Publisher:      item.Publisher,
+			Year:           item.Issued.year(),
+			Volume:         string(item.Volume),
+			Issue:          string(item.Issue),
+			Pages:          strings.ReplaceAll(string(item.Page), "-", "\u2013"),
+			DOI:            item.DOI,
+			URL:            item.URL,
+		})
+	}
+	return entries, nil
+}
+
+func cslNames(names []cslName) []Name {
+	if len(names) == 0 {
+		return nil
+	}
+	out := make([]Name, len(names))
+	for i, n := range names {
+		family := n.Family
+		if n.Particle != "" {
+			family = n.Particle + " " + family
+		}
+		out[i] = Name{Family: family, Given: n.Given, Literal: n.Literal}
+	}
+	return out
+}
+
+// year returns the year of a date, from its date parts or the leading
+// digits of its literal or raw form.
+func (d *cslDate) year() string {
+	if d == nil {
+		return ""
+	}
+	if len(d.DateParts) > 0 && len(d.DateParts[0]) > 0 {
+		return string(d.DateParts[0][0])
+	}
+	for _, s := range []string{d.Raw, d.Literal} {
+		if len(s) >= 4 {
+			if _, err := strconv.Atoi(s[:4]); err == nil {
+				return s[:4]
+			}
+		}
+	}
+	return d.Literal
+}
@@ -0,0 +1,92 @@
+package bibliography
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// TestParseCSLJSON - Items, Names and Dates
+// ---------------------------------------------------------------------------
+
+func TestParseCSLJSON(t *testing.T) {
+	t.Parallel()
+
+	src := `[
+  {
+    "id": "doe2019",
+    "type": "article-journal",
+    "author": [{"family": "Doe", "given": "Jane"}, {"family": "Berg", "given": "Jan", "non-dropping-particle": "van der"}],
+    "title": "Results",
+    "container-title": "Journal",
+    "issued": {"date-parts": [[2019, 5]]},
+    "volume": 12,
+    "issue": "3",
+    "page": "45-67",
+    "DOI": "10.1/x"
+  },
+  {
+    "id": 42,
+    "type": "report",
+    "author": [{"literal": "World Health Organization"}],
+    "title": "Report",
+    "issued": {"raw": "2021-03-01"},
+    "URL": "https://example.com"
+  }
+]`
+
+	got, err := ParseCSLJSON([]byte(src))
+	if err != nil {
+		t.Fatalf("ParseCSLJSON() unexpected error: %v", err)
+	}
+
+	want := []Entry{
+		{
+			ID:             "doe2019",
+			Type:           "article-journal",
+			Authors:        []Name{{Family: "Doe", Given: "Jane"}, {Family: "van der Berg", Given: "Jan"}},
+			Title:          "Results",
+			ContainerTitle: "Journal",
+			Year:           "2019",
+			Volume:         "12",
+			Issue:          "3",
+			Pages:          "45–67",
+			DOI:            "10.1/x",
+		},
+		{
+			ID:      "42",
+			Type:    "report",
+			Authors: []Name{{Literal: "World Health Organization"}},
+			Title:   "Report",
+			Year:    "2021",
+			URL:     "https://example.com",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseCSLJSON() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestParseCSLJSON_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"not an array", `{"id": "a"}`},
+		{"invalid JSON", `[{"id": "a"`},
+		{"missing id", `[{"title": "A"}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := ParseCSLJSON([]byte(tt.input)); !errors.Is(err, ErrParse) {
+				t.Errorf("ParseCSLJSON() error = %v, want %v", err, ErrParse)
+			}
+		})
+	}
+}
@@ -0,0 +1,393 @@
+package bibliography
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Style is a citation style.
+type Style string
+
+// Citation styles.
+const (
+	AuthorDate Style = "author-date" // (Smith 2020), list sorted by author
+	Numeric    Style = "numeric"     // [1], list in order of first citation
+)
+
+// AnchorPrefix prefixes the ID of each entry in the reference list.
+const AnchorPrefix = "ref-"
+
+var (
+	// Characters kept in anchors; others become hyphens.
+	anchorUnsafePattern = regexp.MustCompile(`[^\w:.-]`)
+
+	// Markdown punctuation escaped in bibliography text.
+	markdownSpecialPattern = regexp.MustCompile("[\\\\`*_\\[\\]<>#$@|~=!{}]")
+)
+
+// contributionTypes are CSL types printed as a part ("Title.") of their
+// container (*Journal*) rather than as a standalone work.
+var contributionTypes = map[string]bool{
+	"article":            true,
+	"article-journal":    true,
+	"article-magazine":   true,
+	"article-newspaper":  true,
+	"chapter":            true,
+	"entry":              true,
+	"entry-dictionary":   true,
+	"entry-encyclopedia": true,
+	"paper-conference":   true,
+	"post":               true,
+	"post-weblog":        true,
+	"review":             true,
+}
+
+// Cite is one reference within a citation.
+type Cite struct {
+	Entry          Entry
+	Number         int    // Position in the reference list, for Numeric
+	Prefix         string // Markdown before the reference, e.g. "see"
+	Locator        string // Markdown after it, e.g. "p. 33"
+	SuppressAuthor bool   // Year only in AuthorDate, as for [-@key]
+}
+
+// Anchor returns the ID of an entry in the reference list.
+func Anchor(id string) string {
+	return AnchorPrefix + anchorUnsafePattern.ReplaceAllString(id, "-")
+}
+
+// FormatCitation formats a bracketed citation as Markdown, with each
+// reference linked to its list entry: "(see Smith 2020, p. 3; Doe 2019)" in
+// AuthorDate, "[1, p. 3; 4]" in Numeric.
+func FormatCitation(style Style, cites []Cite) string {
+	parts := make([]string, len(cites))
+	separator := ", "
+	for i, c := range cites {
+		var text string
+		if style == Numeric {
+			text = link(strconv.Itoa(c.Number), c.Entry)
+		} else {
+			label := escape(year(c.Entry))
+			if !c.SuppressAuthor {
+				label = shortAuthors(c.Entry) + " " + label
+			}
+			text = link(label, c.Entry)
+			separator = "; "
+		}
+		if c.Prefix != "" {
+			text = c.Prefix + " " + text
+			separator = "; "
+		}
+		if c.Locator != "" {
+			text += ", " + c.Locator
+			separator = "; "
+		}
+		parts[i] = text
+	}
+	if style == Numeric {
+		return `\[` + strings.Join(parts, separator) + `\]`
+	}
+	return "(" + strings.Join(parts, separator) + ")"
+}
+
+// FormatNarrative formats an in-text citation as Markdown: "Smith (2020)" in
+// AuthorDate, "Smith [1]" in Numeric.
+func FormatNarrative(style Style, c Cite) string {
+	if style == Numeric {
+		return shortAuthors(c.Entry) + ` \[` + link(strconv.Itoa(c.Number), c.Entry) + `\]`
+	}
+	return shortAuthors(c.Entry) + " (" + link(escape(year(c.Entry)), c.Entry) + ")"
+}
+
+// FormatReference formats the reference list entry for e as Markdown,
+// without its number.
+//
+// AuthorDate follows Chicago author-date:
+//
+//	Smith, John, and Jane Doe. 2020. “Title.” *Journal* 12 (3): 45–67.
+//
+// Numeric follows IEEE:
+//
+//	J. Smith and J. Doe, “Title,” *Journal*, vol. 12, no. 3, pp. 45–67, 2020.
+func FormatReference(style Style, e Entry) string {
+	if style == Numeric {
+		return numericReference(e)
+	}
+	return authorDateReference(e)
+}
+
+// SortAuthorDate sorts entries for an author-date reference list: by
+// author, then year, then title.
+func SortAuthorDate(entries []Entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := sortKey(entries[i]), sortKey(entries[j])
+		return a < b
+	})
+}
+
+func sortKey(e Entry) string {
+	return strings.ToLower(nameList(contributors(e), func(n Name, _ int) string {
+		return family(n) + " " + n.Given
+	}, ", ") + "\x00" + year(e) + "\x00" + e.Title)
+}
+
+func authorDateReference(e Entry) string {
+	var b referenceBuilder
+	names, editors := contributors(e), len(e.Authors) == 0 && len(e.Editors) > 0
+	if len(names) > 0 {
+		authors := nameList(names, func(n Name, i int) string {
+			if i == 0 {
+				return inverted(n)
+			}
+			return natural(n)
+		}, ", ")
+		if editors {
+			authors += editorSuffix(len(names))
+		}
+		b.sentence(authors)
+	}
+	b.sentence(escape(year(e)))
+
+	if isContribution(e) {
+		b.sentence("“" + escape(e.Title) + ".”")
+		container := "*" + escape(e.ContainerTitle) + "*"
+		switch {
+		case strings.HasPrefix(e.Type, "article"):
+			if e.Volume != "" {
+				container += " " + escape(e.Volume)
+			}
+			if e.Issue != "" {
+				container += " (" + escape(e.Issue) + ")"
+			}
+			if e.Pages != "" {
+				container += ": " + escape(e.Pages)
+			}
+		default:
+			container = "In " + container
+			if e.Pages != "" {
+				container += ", " + escape(e.Pages)
+			}
+		}
+		b.sentence(container)
+	} else if e.Title != "" {
+		b.sentence("*" + escape(e.Title) + "*")
+	}
+	if e.Publisher != "" && !strings.HasPrefix(e.Type, "article") {
+		b.sentence(escape(e.Publisher))
+	}
+	b.link(e)
+	return b.String()
+}
+
+func numericReference(e Entry) string {
+	var parts []string
+	names := contributors(e)
+	if len(names) > 0 {
+		authors := nameList(names, func(n Name, _ int) string { return initials(n) }, ", ")
+		if len(e.Authors) == 0 {
+			authors += editorSuffix(len(names))
+		}
+		parts = append(parts, authors)
+	}
+
+	var b strings.Builder
+	if isContribution(e) {
+		parts = append(parts, "“"+escape(e.Title)+",” *"+escape(e.ContainerTitle)+"*")
+		if e.Volume != "" {
+			parts = append(parts, "vol. "+escape(e.Volume))
+		}
+		if e.Issue != "" {
+			parts = append(parts, "no. "+escape(e.Issue))
+		}
+		if e.Pages != "" {
+			parts = append(parts, "pp. "+escape(e.Pages))
+		}
+		if e.Publisher != "" && !strings.HasPrefix(e.Type, "article") {
+			parts = append(parts, escape(e.Publisher))
+		}
+		parts = append(parts, escape(year(e)))
+		b.WriteString(strings.Join(parts, ", "))
+	} else {
+		if e.Title != "" {
+			parts = append(parts, "*"+escape(e.Title)+"*")
+		}
+		b.WriteString(strings.Join(parts, ", "))
+		tail := escape(year(e))
+		if e.Publisher != "" {
+			tail = escape(e.Publisher) + ", " + tail
+		}
+		if b.Len() > 0 {
+			b.WriteString(". ")
+		}
+		b.WriteString(tail)
+	}
+	if !strings.HasSuffix(b.String(), ".") {
+		b.WriteString(".")
+	}
+
+	switch {
+	case e.DOI != "":
+		b.WriteString(" doi: " + escape(e.DOI) + ".")
+	case e.URL != "":
+		b.WriteString(" <" + e.URL + ">")
+	}
+	return b.String()
+}
+
+// referenceBuilder joins the sentences of a reference with periods.
+type referenceBuilder struct {
+	strings.Builder
+}
+
+// sentence appends s, with a period unless it already ends a sentence.
+func (b *referenceBuilder) sentence(s string) {
+	if s == "" {
+		return
+	}
+	if b.Len() > 0 {
+		b.WriteString(" ")
+	}
+	b.WriteString(s)
+	if r, _ := utf8.DecodeLastRuneInString(s); !strings.ContainsRune(".?!”", r) {
+		b.WriteString(".")
+	}
+}
+
+// link appends the DOI as a URL, or else the URL.
+func (b *referenceBuilder) link(e Entry) {
+	switch {
+	case e.DOI != "":
+		b.WriteString(" <https://doi.org/" + strings.TrimPrefix(e.DOI, "https://doi.org/") + ">")
+	case e.URL != "":
+		b.WriteString(" <" + e.URL + ">")
+	}
+}
+
+// contributors returns the authors, or the editors of an edited work.
+func contributors(e Entry) []Name {
+	if len(e.Authors) > 0 {
+		return e.Authors
+	}
+	return e.Editors
+}
+
+// isContribution reports whether e is printed as part of a container.
+func isContribution(e Entry) bool {
+	return e.ContainerTitle != "" && (contributionTypes[e.Type] || e.Type == "")
+}
+
+// shortAuthors returns the names used in a citation: "Smith", "Smith and
+// Doe" or "Smith et al.", or the title when there are none.
+func shortAuthors(e Entry) string {
+	names := contributors(e)
+	switch len(names) {
+	case 0:
+		if e.Title == "" {
+			return escape(e.ID)
+		}
+		return "*" + escape(e.Title) + "*"
+	case 1:
+		return escape(family(names[0]))
+	case 2:
+		return escape(family(names[0])) + " and " + escape(family(names[1]))
+	default:
+		return escape(family(names[0])) + " et al."
+	}
+}
+
+// nameList formats names with format and joins them: "A", "A and B",
+// "A, B, and C".
+func nameList(names []Name, format func(Name, int) string, sep string) string {
+	formatted := make([]string, len(names))
+	for i, n := range names {
+		formatted[i] = escape(format(n, i))
+	}
+	switch len(formatted) {
+	case 0:
+		return ""
+	case 1:
+		return formatted[0]
+	case 2:
+		return formatted[0] + " and " + formatted[1]
+	default:
+		return strings.Join(formatted[:len(formatted)-1], sep) + sep + "and " + formatted[len(formatted)-1]
+	}
+}
+
+func editorSuffix(n int) string {
+	if n == 1 {
+		return ", ed."
+	}
+	return ", eds."
+}
+
+// family returns the name a citation uses.
+func family(n Name) string {
+	switch {
+	case n.Literal != "":
+		return n.Literal
+	case n.Family != "":
+		return n.Family
+	}
+	return n.Given
+}
+
+// inverted formats "Family, Given".
+func inverted(n Name) string {
+	if n.Literal != "" || n.Given == "" {
+		return family(n)
+	}
+	return n.Family + ", " + n.Given
+}
+
+// natural formats "Given Family".
+func natural(n Name) string {
+	if n.Literal != "" || n.Given == "" {
+		return family(n)
+	}
+	return n.Given + " " + n.Family
+}
+
+// initials formats "J. R. Family", keeping hyphens: "J.-P. Sartre".
+func initials(n Name) string {
+	if n.Literal != "" || n.Given == "" {
+		return family(n)
+	}
+	var b strings.Builder
+	for i, word := range strings.Fields(n.Given) {
+		if i > 0 {
+			b.WriteString(" ")
+		}
+		for j, part := range strings.Split(word, "-") {
+			if j > 0 {
+				b.WriteString("-")
+			}
+			if r, _ := utf8.DecodeRuneInString(part); unicode.IsLetter(r) {
+				b.WriteString(string(r) + ".")
+			}
+		}
+	}
+	return b.String() + " " + n.Family
+}
+
+// year returns the year of e, or "n.d." (no date).
+func year(e Entry) string {
+	if e.Year == "" {
+		return "n.d."
+	}
+	return e.Year
+}
+
+// link formats text as a Markdown link to e's list entry.
+func link(text string, e Entry) string {
+	return "[" + text + "](#" + Anchor(e.ID) + ")"
+}
+
+// escape backslash-escapes Markdown punctuation in bibliography text.
+func escape(s string) string {
+	return markdownSpecialPattern.ReplaceAllString(s, `\$0`)
+}
@@ -0,0 +1,210 @@
+package bibliography
+
+import (
+	"testing"
+)
+
+var (
+	book = Entry{
+		ID:        "knuth1984",
+		Type:      "book",
+		Authors:   []Name{{Family: "Knuth", Given: "Donald E."}},
+		Title:     "The TeXbook",
+		Publisher: "Addison-Wesley",
+		Year:      "1984",
+	}
+	article = Entry{
+		ID:             "doe2019",
+		Type:           "article-journal",
+		Authors:        []Name{{Family: "Doe", Given: "Jane"}, {Family: "Smith", Given: "John Paul"}},
+		Title:          "Results",
+		ContainerTitle: "Journal",
+		Year:           "2019",
+		Volume:         "12",
+		Issue:          "3",
+		Pages:          "45–67",
+		DOI:            "10.1/x",
+	}
+	chapter = Entry{
+		ID:             "lee",
+		Type:           "chapter",
+		Authors:        []Name{{Family: "Lee", Given: "A."}, {Family: "Kim", Given: "B."}, {Family: "Park", Given: "C."}},
+		Title:          "Methods",
+		ContainerTitle: "Handbook",
+		Publisher:      "Press",
+		Pages:          "1–9",
+	}
+	report = Entry{
+		ID:      "who_2021",
+		Type:    "report",
+		Editors: []Name{{Literal: "World Health Organization"}},
+		Title:   "Report *one*",
+		Year:    "2021",
+		URL:     "https://example.com",
+	}
+)
+
+// ---------------------------------------------------------------------------
+// TestFormatCitation - Parenthetical Citations
+// ---------------------------------------------------------------------------
+
+func TestFormatCitation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		style Style
+		cites []Cite
+		want  string
+	}{
+		{
+			"author-date single",
+			AuthorDate,
+			[]Cite{{Entry: book}},
+			"([Knuth 1984](#ref-knuth1984))",
+		},
+		{
+			"author-date with prefix, locator and suppressed author",
+			AuthorDate,
+			[]Cite{{Entry: article, Prefix: "see", Locator: "p. 3"}, {Entry: book, SuppressAuthor: true}},
+			"(see [Doe and Smith 2019](#ref-doe2019), p. 3; [1984](#ref-knuth1984))",
+		},
+		{
+			"author-date et al. and no date",
+			AuthorDate,
+			[]Cite{{Entry: chapter}},
+			"([Lee et al. n.d.](#ref-lee))",
+		},
+		{
+			"numeric list",
+			Numeric,
+			[]Cite{{Entry: book, Number: 1}, {Entry: article, Number: 3}},
+			`\[[1](#ref-knuth1984), [3](#ref-doe2019)\]`,
+		},
+		{
+			"numeric with locator",
+			Numeric,
+			[]Cite{{Entry: book, Number: 1, Locator: "ch. 2"}, {Entry: article, Number: 2}},
+			`\[[1](#ref-knuth1984), ch. 2; [2](#ref-doe2019)\]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := FormatCitation(tt.style, tt.cites); got != tt.want {
+				t.Errorf("FormatCitation() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatNarrative(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		style Style
+		cite  Cite
+		want  string
+	}{
+		{AuthorDate, Cite{Entry: article}, "Doe and Smith ([2019](#ref-doe2019))"},
+		{Numeric, Cite{Entry: book, Number: 2}, `Knuth \[[2](#ref-knuth1984)\]`},
+		{AuthorDate, Cite{Entry: report}, "World Health Organization ([2021](#ref-who_2021))"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.style)+"/"+tt.cite.Entry.ID, func(t *testing.T) {
+			t.Parallel()
+
+			if got := FormatNarrative(tt.style, tt.cite); got != tt.want {
+				t.Errorf("FormatNarrative() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestFormatReference - Reference List Entries
+// ---------------------------------------------------------------------------
+
+func TestFormatReference(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		style Style
+		entry Entry
+		want  string
+	}{
+		{
+			"author-date book",
+			AuthorDate, book,
+			"Knuth, Donald E. 1984. *The TeXbook*. Addison-Wesley.",
+		},
+		{
+			"author-date article",
+			AuthorDate, article,
+			"Doe, Jane and John Paul Smith. 2019. “Results.” *Journal* 12 (3): 45–67. <https://doi.org/10.1/x>",
+		},
+		{
+			"author-date chapter",
+			AuthorDate, chapter,
+			"Lee, A., B. Kim, and C. Park. n.d. “Methods.” In *Handbook*, 1–9. Press.",
+		},
+		{
+			"author-date edited report escapes Markdown",
+			AuthorDate, report,
+			"World Health Organization, ed. 2021. *Report \\*one\\**. <https://example.com>",
+		},
+		{
+			"numeric book",
+			Numeric, book,
+			"D. E. Knuth, *The TeXbook*. Addison-Wesley, 1984.",
+		},
+		{
+			"numeric article",
+			Numeric, article,
+			"J. Doe and J. P. Smith, “Results,” *Journal*, vol. 12, no. 3, pp. 45–67, 2019. doi: 10.1/x.",
+		},
+		{
+			"numeric chapter",
+			Numeric, chapter,
+			"A. Lee, B. Kim, and C. Park, “Methods,” *Handbook*, pp. 1–9, Press, n.d.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := FormatReference(tt.style, tt.entry); got != tt.want {
+				t.Errorf("FormatReference() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortAuthorDate(t *testing.T) {
+	t.Parallel()
+
+	older := book
+	older.ID, older.Year = "knuth1979", "1979"
+	entries := []Entry{report, book, article, older}
+	SortAuthorDate(entries)
+
+	want := []string{"doe2019", "knuth1979", "knuth1984", "who_2021"}
+	for i, e := range entries {
+		if e.ID != want[i] {
+			t.Errorf("SortAuthorDate()[%d] = %q, want %q", i, e.ID, want[i])
+		}
+	}
+}
+
+func TestAnchor(t *testing.T) {
+	t.Parallel()
+
+	if got, want := Anchor("smith:2020 a/b"), "ref-smith:2020-a-b"; got != want {
+		t.Errorf("Anchor() = %q, want %q", got, want)
+	}
+}
@@ -33,7 +33,9 @@ const (
 	MaxURLLength            = 2048 // Browser limit
 	MaxTextLength           = 500  // Footer/free-form text
 	MaxLabelLength          = 100  // Link label
-	MaxPageSizeLength       = 10   // "letter", "a4", "legal"
+	MaxPageSizeLength       = 10   // "letter", "a4", "executive"
+	MaxLengthValueLength    = 20   // "210mm", "0.75in"
+	MaxDiagramThemeLength   = 10   // "default", "neutral"
 	MaxOrientationLength    = 10   // "portrait", "landscape"
 	MaxWatermarkTextLength  = 50   // "DRAFT", "CONFIDENTIAL"
 	MaxWatermarkColorLength = 20   // "#888888" or color name
@@ -52,24 +54,42 @@ const (
 	MaxDocumentTypeLength = 50  // Document type label
 	MaxDocumentIDLength   = 50  // Document reference ID
 	MaxDescriptionLength  = 500 // Document summary
+	MaxKeywordLength      = 50  // Single PDF keyword
+	MaxKeywords           = 20  // PDF keywords per document
 )
 
 // Config holds all configuration for document generation.
 type Config struct {
-	Author     AuthorConfig     `yaml:"author"`
-	Document   DocumentConfig   `yaml:"document"`
-	Input      InputConfig      `yaml:"input"`
-	Output     OutputConfig     `yaml:"output"`
-	Style      string           `yaml:"style"`   // CSS style name or file path
-	Timeout    string           `yaml:"timeout"` // PDF generation timeout (e.g., "30s", "2m")
-	Footer     FooterConfig     `yaml:"footer"`
-	Signature  SignatureConfig  `yaml:"signature"`
-	Assets     AssetsConfig     `yaml:"assets"`
-	Page       PageConfig       `yaml:"page"`
-	Watermark  WatermarkConfig  `yaml:"watermark"`
-	Cover      CoverConfig      `yaml:"cover"`
-	TOC        TOCConfig        `yaml:"toc"`
-	PageBreaks PageBreaksConfig `yaml:"pageBreaks"`
+	Author        AuthorConfig        `yaml:"author"`
+	Document      DocumentConfig      `yaml:"document"`
+	Input         InputConfig         `yaml:"input"`
+	Output        OutputConfig        `yaml:"output"`
+	Style         string              `yaml:"style"`   // CSS style name or file path
+	Timeout       string              `yaml:"timeout"` // PDF generation timeout (e.g., "30s", "2m")
+	Footer        FooterConfig        `yaml:"footer"`
+	Header        HeaderConfig        `yaml:"header"`
+	PageNumbering PageNumberingConfig `yaml:"pageNumbering"`
+	Diagrams      DiagramsConfig      `yaml:"diagrams"`
+	Math          MathConfig          `yaml:"math"`
+	HTML          HTMLConfig          `yaml:"html"`
+	Interpolation InterpolationConfig `yaml:"interpolation"`
+	Vars          map[string]string   `yaml:"vars"`         // Custom {{ .Vars.name }} values
+	Bibliography  string              `yaml:"bibliography"` // BibTeX or CSL-JSON file for citations
+	Citations     CitationsConfig     `yaml:"citations"`
+	Signature     SignatureConfig     `yaml:"signature"`
+	Assets        AssetsConfig        `yaml:"assets"`
+	Page          PageConfig          `yaml:"page"`
+	Watermark     WatermarkConfig     `yaml:"watermark"`
+	Cover         CoverConfig         `yaml:"cover"`
+	TOC           TOCConfig           `yaml:"toc"`
+	Index         IndexConfig         `yaml:"index"`
+	ListOfFigures ListOfConfig        `yaml:"listOfFigures"`
+	ListOfTables  ListOfConfig        `yaml:"listOfTables"`
+	Footnotes     FootnotesConfig     `yaml:"footnotes"`
+	Outline       OutlineConfig       `yaml:"outline"`
+	PageBreaks    PageBreaksConfig    `yaml:"pageBreaks"`
+	Security      SecurityConfig      `yaml:"security"`
+	Signing       SigningConfig       `yaml:"signing"`
 }
 
 // AuthorConfig holds shared author metadata used by cover and signature.
@@ -117,11 +137,12 @@ type DocumentConfig struct {
 	Version  string `yaml:"version"`  // Version string (used in cover and footer)
 	Date     string `yaml:"date"`     // "auto" = YYYY-MM-DD at startup
 	// Extended metadata fields
-	ClientName   string `yaml:"clientName"`   // Client/customer name
-	ProjectName  string `yaml:"projectName"`  // Project name
-	DocumentType string `yaml:"documentType"` // e.g., "Technical Specification"
-	DocumentID   string `yaml:"documentID"`   // e.g., "DOC-2024-001"
-	Description  string `yaml:"description"`  // Brief document summary
+	ClientName   string   `yaml:"clientName"`   // Client/customer name
+	ProjectName  string   `yaml:"projectName"`  // Project name
+	DocumentType string   `yaml:"documentType"` // e.g., "Technical Specification"
+	DocumentID   string   `yaml:"documentID"`   // e.g., "DOC-2024-001"
+	Description  string   `yaml:"description"`  // Brief document summary
+	Keywords     []string `yaml:"keywords"`     // PDF metadata keywords
 }
 
 // Validate checks document field lengths and date format.
@@ -157,6 +178,14 @@ func (d *DocumentConfig) Validate() error {
 	if err := validateFieldLength("document.description", d.Description, MaxDescriptionLength); err != nil {
 		return err
 	}
+	if len(d.Keywords) > MaxKeywords {
+		return fmt.Errorf("%w: document.keywords (%d keywords, max %d)", ErrFieldTooLong, len(d.Keywords), MaxKeywords)
+	}
+	for _, kw := range d.Keywords {
+		if err := validateFieldLength("document.keywords", kw, MaxKeywordLength); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -185,6 +214,7 @@ type InputConfig struct {
 // OutputConfig defines output destination options.
 type OutputConfig struct {
 	DefaultDir string `yaml:"defaultDir"` // Default output directory (empty = same as source)
+	PDFA       bool   `yaml:"pdfa"`       // Produce PDF/A-2b for archiving
 }
 
 // FooterConfig defines page footer options.
@@ -213,6 +243,155 @@ func (f *FooterConfig) Validate() error {
 	return nil
 }
 
+// HeaderConfig defines running page header options.
+// Slots accept free text with {title}, {version}, {documentID}, {date},
+// {pageNumber} and {totalPages} placeholders, filled from document.*.
+type HeaderConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Left    string `yaml:"left"`   // Left slot text
+	Center  string `yaml:"center"` // Center slot text
+	Right   string `yaml:"right"`  // Right slot text
+}
+
+// Validate checks header field values.
+func (h *HeaderConfig) Validate() error {
+	if err := validateFieldLength("header.left", h.Left, MaxTextLength); err != nil {
+		return err
+	}
+	if err := validateFieldLength("header.center", h.Center, MaxTextLength); err != nil {
+		return err
+	}
+	if err := validateFieldLength("header.right", h.Right, MaxTextLength); err != nil {
+		return err
+	}
+	header := picoloom.Header{Left: h.Left, Center: h.Center, Right: h.Right}
+	if err := header.Validate(); err != nil {
+		return fmt.Errorf("header: %w", err)
+	}
+	return nil
+}
+
+// PageNumberingConfig defines how footer and header page numbers are printed.
+// Front matter is the cover and TOC.
+type PageNumberingConfig struct {
+	Enabled     bool   `yaml:"enabled"`
+	Format      string `yaml:"format"`      // "slash" (3/12), "page-of" (Page 3 of 12), "plain" (default: "slash")
+	StartAt     int    `yaml:"startAt"`     // Number of the first numbered page (default: 1)
+	FrontMatter string `yaml:"frontMatter"` // "continue", "skip", "roman" (default: "continue")
+}
+
+// Validate checks page numbering field values.
+func (n *PageNumberingConfig) Validate() error {
+	if !n.Enabled {
+		return nil
+	}
+	numbering := picoloom.PageNumbering{Format: n.Format, StartAt: n.StartAt, FrontMatter: n.FrontMatter}
+	if err := numbering.Validate(); err != nil {
+		return fmt.Errorf("pageNumbering: %w", err)
+	}
+	return nil
+}
+
+// DiagramsConfig defines how ```mermaid code blocks are rendered.
+type DiagramsConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Theme   string `yaml:"theme"` // "default", "neutral", "dark", "forest", "base" (default: "default")
+}
+
+// Validate checks diagram field values.
+func (d *DiagramsConfig) Validate() error {
+	if !d.Enabled {
+		return nil
+	}
+	if err := validateFieldLength("diagrams.theme", d.Theme, MaxDiagramThemeLength); err != nil {
+		return err
+	}
+	diagrams := picoloom.Diagrams{Theme: d.Theme}
+	if err := diagrams.Validate(); err != nil {
+		return fmt.Errorf("diagrams: %w", err)
+	}
+	return nil
+}
+
+// MathConfig defines how $...$ and $$...$$ TeX is rendered.
+type MathConfig struct {
+	Enabled   bool `yaml:"enabled"`
+	Numbering bool `yaml:"numbering"` // Number display equations (1), (2), ...
+}
+
+// HTMLConfig defines how HTML written in the Markdown is handled.
+type HTMLConfig struct {
+	AllowRaw          bool     `yaml:"allowRaw"`          // Keep raw HTML, sanitized (default: omitted)
+	AllowedTags       []string `yaml:"allowedTags"`       // Elements allowed besides the defaults
+	AllowedAttributes []string `yaml:"allowedAttributes"` // Attributes allowed besides the defaults
+}
+
+// Validate checks the raw HTML allowlist.
+func (h *HTMLConfig) Validate() error {
+	if !h.AllowRaw {
+		return nil
+	}
+	rawHTML := picoloom.RawHTML{AllowedTags: h.AllowedTags, AllowedAttributes: h.AllowedAttributes}
+	if err := rawHTML.Validate(); err != nil {
+		return fmt.Errorf("html: %w", err)
+	}
+	return nil
+}
+
+// InterpolationConfig defines the {{ ... }} variable pass over the Markdown body.
+type InterpolationConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// validateVars checks custom variable names and value lengths.
+func validateVars(vars map[string]string) error {
+	variables := picoloom.Variables{Vars: vars}
+	if err := variables.Validate(); err != nil {
+		return fmt.Errorf("vars: %w", err)
+	}
+	for name, value := range vars {
+		if err := validateFieldLength("vars."+name, value, MaxTextLength); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CitationsConfig defines how [@key] citations and the reference list are
+// formatted. Citations are processed when a bibliography file is set.
+type CitationsConfig struct {
+	Style string `yaml:"style"` // "author-date" (default) or "numeric"
+	Title string `yaml:"title"` // Reference list heading (default: "References")
+}
+
+// Validate checks the citation style and title length.
+func (c *CitationsConfig) Validate() error {
+	if err := validateFieldLength("citations.title", c.Title, MaxTOCTitleLength); err != nil {
+		return err
+	}
+	switch strings.ToLower(c.Style) {
+	case "", picoloom.CitationStyleAuthorDate, picoloom.CitationStyleNumeric:
+		return nil
+	}
+	return fmt.Errorf("citations.style: invalid value %q (must be author-date or numeric)", c.Style)
+}
+
+// FootnotesConfig defines where [^label] footnotes are printed and how they
+// are numbered.
+type FootnotesConfig struct {
+	Placement string `yaml:"placement"` // "end" (default) or "page"
+	Numbering string `yaml:"numbering"` // "document" (default) or "page"
+}
+
+// Validate checks the footnote placement and numbering.
+func (f *FootnotesConfig) Validate() error {
+	footnotes := picoloom.Footnotes{Placement: f.Placement, Numbering: f.Numbering}
+	if err := footnotes.Validate(); err != nil {
+		return fmt.Errorf("footnotes: %w", err)
+	}
+	return nil
+}
+
 // SignatureConfig defines signature block options.
 // Uses author.name, author.title, author.email, author.organization for display.
 type SignatureConfig struct {
@@ -271,9 +450,36 @@ func (a *AssetsConfig) Validate() error {
 
 // PageConfig defines PDF page settings.
 type PageConfig struct {
-	Size        string  `yaml:"size"`        // "letter", "a4", "legal" (default: "letter")
-	Orientation string  `yaml:"orientation"` // "portrait", "landscape" (default: "portrait")
-	Margin      float64 `yaml:"margin"`      // inches (default: 0.5)
+	Size        string        `yaml:"size"`        // "letter", "a4", "legal", "a3", "a5", "b5", "executive", "tabloid" (default: "letter")
+	Orientation string        `yaml:"orientation"` // "portrait", "landscape" (default: "portrait")
+	Margin      float64       `yaml:"margin"`      // inches (default: 0.5)
+	Width       string        `yaml:"width"`       // Custom paper width, e.g. "210mm" (with height, replaces size)
+	Height      string        `yaml:"height"`      // Custom paper height, e.g. "297mm"
+	Margins     MarginsConfig `yaml:"margins"`     // Per-side margins, override margin
+}
+
+// MarginsConfig defines per-side page margins as lengths ("20mm", "2cm",
+// "0.75in", "54pt"). Empty sides use page.margin.
+type MarginsConfig struct {
+	Top    string `yaml:"top"`
+	Right  string `yaml:"right"`
+	Bottom string `yaml:"bottom"`
+	Left   string `yaml:"left"`
+	Inner  string `yaml:"inner"` // Binding side, mirrored on left-hand pages
+	Outer  string `yaml:"outer"`
+}
+
+// IsSet reports whether any side is set.
+func (m MarginsConfig) IsSet() bool {
+	return m != MarginsConfig{}
+}
+
+// ToMargins converts to picoloom.Margins, or nil when no side is set.
+func (m MarginsConfig) ToMargins() *picoloom.Margins {
+	if !m.IsSet() {
+		return nil
+	}
+	return &picoloom.Margins{Top: m.Top, Right: m.Right, Bottom: m.Bottom, Left: m.Left, Inner: m.Inner, Outer: m.Outer}
 }
 
 // Validate checks page field values.
@@ -287,10 +493,11 @@ func (p *PageConfig) Validate() error {
 	// Validate allowed values (empty means use default)
 	if p.Size != "" {
 		switch strings.ToLower(p.Size) {
-		case picoloom.PageSizeLetter, picoloom.PageSizeA4, picoloom.PageSizeLegal:
+		case picoloom.PageSizeLetter, picoloom.PageSizeA4, picoloom.PageSizeLegal, picoloom.PageSizeA3,
+			picoloom.PageSizeA5, picoloom.PageSizeB5, picoloom.PageSizeExecutive, picoloom.PageSizeTabloid:
 			// valid
 		default:
-			return fmt.Errorf("page.size: invalid value %q (must be letter, a4, or legal)", p.Size)
+			return fmt.Errorf("page.size: invalid value %q (must be letter, a4, legal, a3, a5, b5, executive, or tabloid)", p.Size)
 		}
 	}
 	if p.Orientation != "" {
@@ -304,6 +511,25 @@ func (p *PageConfig) Validate() error {
 	if p.Margin != 0 && (p.Margin < picoloom.MinMargin || p.Margin > picoloom.MaxMargin) {
 		return fmt.Errorf("page.margin: must be between %.2f and %.2f, got %.2f", picoloom.MinMargin, picoloom.MaxMargin, p.Margin)
 	}
+	for _, f := range []struct{ name, value string }{
+		{"page.width", p.Width}, {"page.height", p.Height},
+		{"page.margins.top", p.Margins.Top}, {"page.margins.right", p.Margins.Right},
+		{"page.margins.bottom", p.Margins.Bottom}, {"page.margins.left", p.Margins.Left},
+		{"page.margins.inner", p.Margins.Inner}, {"page.margins.outer", p.Margins.Outer},
+	} {
+		if err := validateFieldLength(f.name, f.value, MaxLengthValueLength); err != nil {
+			return err
+		}
+	}
+	// Custom sizes and per-side margins are checked together, as the margins
+	// must fit the page.
+	settings := picoloom.PageSettings{
+		Size: p.Size, Orientation: p.Orientation, Margin: p.Margin,
+		Width: p.Width, Height: p.Height, Margins: p.Margins.ToMargins(),
+	}
+	if err := settings.Validate(); err != nil {
+		return fmt.Errorf("page: %w", err)
+	}
 	return nil
 }
 
@@ -361,6 +587,8 @@ type TOCConfig struct {
 	Title    string `yaml:"title"`    // Empty = no title above TOC
 	MinDepth int    `yaml:"minDepth"` // 1-6, default 2 (skips H1)
 	MaxDepth int    `yaml:"maxDepth"` // 1-6, default 3
+
+	PageNumbers bool `yaml:"pageNumbers"` // Page numbers with dot leaders (renders twice)
 }
 
 // Validate checks TOC field values.
@@ -382,6 +610,53 @@ func (t *TOCConfig) Validate() error {
 	return nil
 }
 
+// IndexConfig defines back-of-book index options.
+type IndexConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Title   string `yaml:"title"` // Index heading (default: "Index")
+}
+
+// Validate checks index field values.
+func (i *IndexConfig) Validate() error {
+	return validateFieldLength("index.title", i.Title, MaxTOCTitleLength)
+}
+
+// ListOfConfig defines list of figures or list of tables options.
+type ListOfConfig struct {
+	Enabled     bool   `yaml:"enabled"`
+	Title       string `yaml:"title"`       // List heading (default: "List of Figures" or "List of Tables")
+	PageNumbers bool   `yaml:"pageNumbers"` // Page numbers with dot leaders (renders twice)
+}
+
+// validate checks list field values; key names the section in errors.
+func (l *ListOfConfig) validate(key string) error {
+	return validateFieldLength(key+".title", l.Title, MaxTOCTitleLength)
+}
+
+// OutlineConfig defines PDF outline (bookmarks) options.
+type OutlineConfig struct {
+	Enabled  bool `yaml:"enabled"`
+	MinDepth int  `yaml:"minDepth"` // 1-6, default 1
+	MaxDepth int  `yaml:"maxDepth"` // 1-6, default 3
+}
+
+// Validate checks outline field values.
+func (o *OutlineConfig) Validate() error {
+	if !o.Enabled {
+		return nil
+	}
+	if o.MinDepth != 0 && (o.MinDepth < 1 || o.MinDepth > 6) {
+		return fmt.Errorf("outline.minDepth: must be between 1 and 6, got %d", o.MinDepth)
+	}
+	if o.MaxDepth != 0 && (o.MaxDepth < 1 || o.MaxDepth > 6) {
+		return fmt.Errorf("outline.maxDepth: must be between 1 and 6, got %d", o.MaxDepth)
+	}
+	if o.MinDepth != 0 && o.MaxDepth != 0 && o.MinDepth > o.MaxDepth {
+		return fmt.Errorf("outline.minDepth (%d) cannot be greater than outline.maxDepth (%d)", o.MinDepth, o.MaxDepth)
+	}
+	return nil
+}
+
 // PageBreaksConfig defines page break options.
 type PageBreaksConfig struct {
 	Enabled  bool `yaml:"enabled"`  // Enable page break features (default: true for orphan/widow)
@@ -407,6 +682,67 @@ func (pb *PageBreaksConfig) Validate() error {
 	return nil
 }
 
+// SecurityConfig defines PDF password protection.
+// Passwords are better supplied through PICOLOOM_USER_PASSWORD and
+// PICOLOOM_OWNER_PASSWORD than stored in a config file.
+type SecurityConfig struct {
+	Enabled       bool   `yaml:"enabled"`
+	UserPassword  string `yaml:"userPassword"`  // Password to open the document
+	OwnerPassword string `yaml:"ownerPassword"` // Password for full access (default: userPassword)
+	AllowPrint    bool   `yaml:"allowPrint"`    // Allow printing
+	AllowCopy     bool   `yaml:"allowCopy"`     // Allow copying text and images
+	AllowModify   bool   `yaml:"allowModify"`   // Allow editing and annotating
+}
+
+// Validate checks password lengths. A missing password is reported at
+// conversion, since passwords may come from the environment.
+func (s *SecurityConfig) Validate() error {
+	if err := validateFieldLength("security.userPassword", s.UserPassword, picoloom.MaxPasswordLength); err != nil {
+		return err
+	}
+	return validateFieldLength("security.ownerPassword", s.OwnerPassword, picoloom.MaxPasswordLength)
+}
+
+// SigningConfig defines the digital signature of the PDF.
+// The key password is better supplied through PICOLOOM_SIGN_PASSWORD than
+// stored in a config file.
+type SigningConfig struct {
+	Enabled      bool   `yaml:"enabled"`
+	CertFile     string `yaml:"certFile"`     // PEM certificate or PKCS#12 archive
+	KeyFile      string `yaml:"keyFile"`      // PEM private key (default: certFile)
+	Password     string `yaml:"password"`     // Key or archive password
+	Name         string `yaml:"name"`         // Signer name (default: certificate common name)
+	Reason       string `yaml:"reason"`       // Reason for signing
+	Location     string `yaml:"location"`     // Where the document was signed
+	ContactInfo  string `yaml:"contactInfo"`  // How to reach the signer
+	Visible      bool   `yaml:"visible"`      // Place the field over the signature block
+	TimestampURL string `yaml:"timestampURL"` // RFC 3161 time-stamping server
+}
+
+// Validate checks field lengths. A missing certificate is reported at
+// conversion, since the file may come from a flag.
+func (s *SigningConfig) Validate() error {
+	fields := []struct {
+		name  string
+		value string
+		max   int
+	}{
+		{"signing.certFile", s.CertFile, MaxURLLength},
+		{"signing.keyFile", s.KeyFile, MaxURLLength},
+		{"signing.name", s.Name, MaxNameLength},
+		{"signing.reason", s.Reason, MaxTextLength},
+		{"signing.location", s.Location, MaxAddressLength},
+		{"signing.contactInfo", s.ContactInfo, MaxTextLength},
+		{"signing.timestampURL", s.TimestampURL, MaxURLLength},
+	}
+	for _, f := range fields {
+		if err := validateFieldLength(f.name, f.value, f.max); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Validate checks field lengths to prevent abuse in multi-tenant scenarios.
 // Called automatically by LoadConfig, but available for consumers
 // who construct Config manually (e.g., API adapters, library users).
@@ -423,6 +759,27 @@ func (c *Config) Validate() error {
 	if err := c.Footer.Validate(); err != nil {
 		return err
 	}
+	if err := c.Header.Validate(); err != nil {
+		return err
+	}
+	if err := c.PageNumbering.Validate(); err != nil {
+		return err
+	}
+	if err := c.Diagrams.Validate(); err != nil {
+		return err
+	}
+	if err := c.HTML.Validate(); err != nil {
+		return err
+	}
+	if err := validateVars(c.Vars); err != nil {
+		return err
+	}
+	if err := validateFieldLength("bibliography", c.Bibliography, MaxURLLength); err != nil {
+		return err
+	}
+	if err := c.Citations.Validate(); err != nil {
+		return err
+	}
 	if err := c.Signature.Validate(); err != nil {
 		return err
 	}
@@ -441,12 +798,228 @@ func (c *Config) Validate() error {
 	if err := c.TOC.Validate(); err != nil {
 		return err
 	}
+	if err := c.Index.Validate(); err != nil {
+		return err
+	}
+	if err := c.ListOfFigures.validate("listOfFigures"); err != nil {
+		return err
+	}
+	if err := c.ListOfTables.validate("listOfTables"); err != nil {
+		return err
+	}
+	if err := c.Footnotes.Validate(); err != nil {
+		return err
+	}
+	if err := c.Outline.Validate(); err != nil {
+		return err
+	}
 	if err := c.PageBreaks.Validate(); err != nil {
 		return err
 	}
+	if err := c.Security.Validate(); err != nil {
+		return err
+	}
+	if err := c.Signing.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
 
+// WithFrontmatter returns a copy of the config with per-document frontmatter
+// merged over the document, style, cover, footer, watermark, TOC, index,
+// list of figures and tables, footnotes, vars, bibliography, and citations
+// sections.
+// Non-empty frontmatter values win; a present section enables its feature
+// unless it sets enabled: false. The merged sections are re-validated so
+// frontmatter obeys the same limits as config files.
+// Returns the config unchanged if fm is nil.
+func (c *Config) WithFrontmatter(fm *picoloom.Frontmatter) (*Config, error) {
+	if fm == nil {
+		return c, nil
+	}
+
+	merged := *c
+	mergeFrontmatterDocument(&merged.Document, fm)
+	if fm.Style != "" {
+		merged.Style = fm.Style
+	}
+	mergeFrontmatterCover(&merged.Cover, fm.Cover)
+	mergeFrontmatterFooter(&merged.Footer, fm.Footer)
+	mergeFrontmatterWatermark(&merged.Watermark, fm.Watermark)
+	mergeFrontmatterTOC(&merged.TOC, fm.TOC)
+	mergeFrontmatterIndex(&merged.Index, fm.Index)
+	mergeFrontmatterListOf(&merged.ListOfFigures, fm.ListOfFigures)
+	mergeFrontmatterListOf(&merged.ListOfTables, fm.ListOfTables)
+	if ff := fm.Footnotes; ff != nil {
+		overrideString(&merged.Footnotes.Placement, ff.Placement)
+		overrideString(&merged.Footnotes.Numbering, ff.Numbering)
+	}
+	merged.Vars = mergeFrontmatterVars(c.Vars, fm.Vars)
+	overrideString(&merged.Bibliography, fm.Bibliography)
+	if fc := fm.Citations; fc != nil {
+		overrideString(&merged.Citations.Style, fc.Style)
+		overrideString(&merged.Citations.Title, fc.Title)
+	}
+
+	if err := merged.validateFrontmatterSections(); err != nil {
+		return nil, fmt.Errorf("frontmatter: %w", err)
+	}
+	return &merged, nil
+}
+
+// validateFrontmatterSections runs the section validators frontmatter can affect.
+func (c *Config) validateFrontmatterSections() error {
+	if err := c.Document.Validate(); err != nil {
+		return err
+	}
+	if err := c.Cover.Validate(); err != nil {
+		return err
+	}
+	if err := c.Footer.Validate(); err != nil {
+		return err
+	}
+	if err := c.Watermark.Validate(); err != nil {
+		return err
+	}
+	if err := c.TOC.Validate(); err != nil {
+		return err
+	}
+	if err := c.Index.Validate(); err != nil {
+		return err
+	}
+	if err := c.ListOfFigures.validate("listOfFigures"); err != nil {
+		return err
+	}
+	if err := c.ListOfTables.validate("listOfTables"); err != nil {
+		return err
+	}
+	if err := c.Footnotes.Validate(); err != nil {
+		return err
+	}
+	if err := validateFieldLength("bibliography", c.Bibliography, MaxURLLength); err != nil {
+		return err
+	}
+	if err := c.Citations.Validate(); err != nil {
+		return err
+	}
+	return validateVars(c.Vars)
+}
+
+func mergeFrontmatterDocument(d *DocumentConfig, fm *picoloom.Frontmatter) {
+	overrideString(&d.Title, fm.Title)
+	overrideString(&d.Subtitle, fm.Subtitle)
+	overrideString(&d.Version, fm.Version)
+	overrideString(&d.Date, fm.Date)
+	overrideString(&d.ClientName, fm.ClientName)
+	overrideString(&d.ProjectName, fm.ProjectName)
+	overrideString(&d.DocumentType, fm.DocumentType)
+	overrideString(&d.DocumentID, fm.DocumentID)
+	overrideString(&d.Description, fm.Description)
+	if len(fm.Keywords) > 0 {
+		d.Keywords = fm.Keywords
+	}
+}
+
+// mergeFrontmatterVars returns a new map of vars with fmVars overlaid, so
+// the shared config map is never mutated.
+func mergeFrontmatterVars(vars, fmVars map[string]string) map[string]string {
+	if len(fmVars) == 0 {
+		return vars
+	}
+	merged := make(map[string]string, len(vars)+len(fmVars))
+	for name, value := range vars {
+		merged[name] = value
+	}
+	for name, value := range fmVars {
+		merged[name] = value
+	}
+	return merged
+}
+
+func mergeFrontmatterCover(c *CoverConfig, fc *picoloom.FrontmatterCover) {
+	if fc == nil {
+		return
+	}
+	c.Enabled = fc.IsEnabled()
+	overrideString(&c.Logo, fc.Logo)
+	overrideBool(&c.ShowDepartment, fc.ShowDepartment)
+}
+
+func mergeFrontmatterFooter(f *FooterConfig, ff *picoloom.FrontmatterFooter) {
+	if ff == nil {
+		return
+	}
+	f.Enabled = ff.IsEnabled()
+	overrideString(&f.Position, ff.Position)
+	overrideString(&f.Text, ff.Text)
+	overrideBool(&f.ShowPageNumber, ff.ShowPageNumber)
+	overrideBool(&f.ShowDocumentID, ff.ShowDocumentID)
+}
+
+func mergeFrontmatterWatermark(w *WatermarkConfig, fw *picoloom.FrontmatterWatermark) {
+	if fw == nil {
+		return
+	}
+	if !w.Enabled && fw.IsEnabled() && fw.Angle == nil {
+		// Mirror CLI flags: a watermark enabled outside the config file
+		// gets the library default angle rather than horizontal text.
+		w.Angle = picoloom.DefaultWatermarkAngle
+	}
+	w.Enabled = fw.IsEnabled()
+	overrideString(&w.Text, fw.Text)
+	overrideString(&w.Color, fw.Color)
+	if fw.Opacity != nil {
+		w.Opacity = *fw.Opacity
+	}
+	if fw.Angle != nil {
+		w.Angle = *fw.Angle
+	}
+}
+
+func mergeFrontmatterTOC(t *TOCConfig, ft *picoloom.FrontmatterTOC) {
+	if ft == nil {
+		return
+	}
+	t.Enabled = ft.IsEnabled()
+	overrideString(&t.Title, ft.Title)
+	if ft.MinDepth != 0 {
+		t.MinDepth = ft.MinDepth
+	}
+	if ft.MaxDepth != 0 {
+		t.MaxDepth = ft.MaxDepth
+	}
+}
+
+func mergeFrontmatterIndex(i *IndexConfig, fi *picoloom.FrontmatterIndex) {
+	if fi == nil {
+		return
+	}
+	i.Enabled = fi.IsEnabled()
+	overrideString(&i.Title, fi.Title)
+}
+
+func mergeFrontmatterListOf(l *ListOfConfig, fl *picoloom.FrontmatterListOf) {
+	if fl == nil {
+		return
+	}
+	l.Enabled = fl.IsEnabled()
+	overrideString(&l.Title, fl.Title)
+}
+
+// overrideString sets *dst to value when value is non-empty.
+func overrideString(dst *string, value string) {
+	if value != "" {
+		*dst = value
+	}
+}
+
+// overrideBool sets *dst to *value when value is non-nil.
+func overrideBool(dst *bool, value *bool) {
+	if value != nil {
+		*dst = *value
+	}
+}
+
 // validateFieldLength checks if a field exceeds its maximum allowed length.
 func validateFieldLength(fieldName, value string, maxLength int) error {
 	if len(value) > maxLength {
@@ -479,12 +1052,14 @@ func DefaultConfig() *Config {
 		Output:     OutputConfig{DefaultDir: ""},
 		Style:      "",
 		Footer:     FooterConfig{Enabled: false},
+		Header:     HeaderConfig{Enabled: false},
 		Signature:  SignatureConfig{Enabled: false},
 		Assets:     AssetsConfig{BasePath: ""},
 		Page:       PageConfig{},
 		Watermark:  WatermarkConfig{Enabled: false},
 		Cover:      CoverConfig{Enabled: false},
 		TOC:        TOCConfig{Enabled: false},
+		Outline:    OutlineConfig{Enabled: false},
 		PageBreaks: PageBreaksConfig{Enabled: false},
 	}
 }
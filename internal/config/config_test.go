@@ -213,13 +213,14 @@ footer:
 		}
 	})
 
-	t.Run("loads input and output directories", func(t *testing.T) {
+	t.Run("loads input and output settings", func(t *testing.T) {
 		dir := t.TempDir()
 		configPath := filepath.Join(dir, "test.yaml")
 		content := `input:
   defaultDir: "/path/to/input"
 output:
   defaultDir: "/path/to/output"
+  pdfa: true
 `
 		if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
 			t.Fatalf("setup WriteFile: %v", err)
@@ -235,6 +236,55 @@ output:
 		if cfg.Output.DefaultDir != "/path/to/output" {
 			t.Errorf("LoadConfig(configPath).Output.DefaultDir = %q, want %q", cfg.Output.DefaultDir, "/path/to/output")
 		}
+		if !cfg.Output.PDFA {
+			t.Error("LoadConfig(configPath).Output.PDFA = false, want true")
+		}
+	})
+
+	t.Run("loads security settings", func(t *testing.T) {
+		dir := t.TempDir()
+		configPath := filepath.Join(dir, "test.yaml")
+		content := `security:
+  enabled: true
+  ownerPassword: "admin"
+  allowPrint: true
+`
+		if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+			t.Fatalf("setup WriteFile: %v", err)
+		}
+
+		cfg, err := LoadConfig(configPath)
+		if err != nil {
+			t.Fatalf("LoadConfig(configPath) unexpected error: %v", err)
+		}
+		want := SecurityConfig{Enabled: true, OwnerPassword: "admin", AllowPrint: true}
+		if cfg.Security != want {
+			t.Errorf("LoadConfig(configPath).Security = %+v, want %+v", cfg.Security, want)
+		}
+	})
+
+	t.Run("loads signing settings", func(t *testing.T) {
+		dir := t.TempDir()
+		configPath := filepath.Join(dir, "test.yaml")
+		content := `signing:
+  enabled: true
+  certFile: "signer.p12"
+  reason: "Approval"
+  visible: true
+  timestampURL: "http://localhost:3180"
+`
+		if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+			t.Fatalf("setup WriteFile: %v", err)
+		}
+
+		cfg, err := LoadConfig(configPath)
+		if err != nil {
+			t.Fatalf("LoadConfig(configPath) unexpected error: %v", err)
+		}
+		want := SigningConfig{Enabled: true, CertFile: "signer.p12", Reason: "Approval", Visible: true, TimestampURL: "http://localhost:3180"}
+		if cfg.Signing != want {
+			t.Errorf("LoadConfig(configPath).Signing = %+v, want %+v", cfg.Signing, want)
+		}
 	})
 
 	t.Run("nonexistent file path returns ErrConfigNotFound", func(t *testing.T) {
@@ -728,7 +778,7 @@ func TestConfig_Validate_Page(t *testing.T) {
 
 	t.Run("invalid size returns error", func(t *testing.T) {
 		t.Parallel()
-		cfg := &Config{Page: PageConfig{Size: "tabloid"}}
+		cfg := &Config{Page: PageConfig{Size: "folio"}}
 		err := cfg.Validate()
 		if err == nil {
 			t.Fatal("Config.Validate() error = nil, want error")
@@ -765,6 +815,61 @@ func TestConfig_Validate_Page(t *testing.T) {
 		}
 	})
 
+	t.Run("new size presets pass", func(t *testing.T) {
+		t.Parallel()
+		for _, size := range []string{"a3", "a5", "b5", "executive", "tabloid"} {
+			cfg := &Config{Page: PageConfig{Size: size}}
+			if err := cfg.Validate(); err != nil {
+				t.Errorf("Config.Validate() size %q unexpected error: %v", size, err)
+			}
+		}
+	})
+
+	t.Run("custom size and per-side margins pass", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{Page: PageConfig{
+			Width: "170mm", Height: "240mm",
+			Margins: MarginsConfig{Top: "2cm", Bottom: "25mm", Inner: "1in", Outer: "54pt"},
+		}}
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("Config.Validate() unexpected error: %v", err)
+		}
+	})
+
+	t.Run("invalid custom size and margins return errors", func(t *testing.T) {
+		t.Parallel()
+		tests := []struct {
+			name    string
+			page    PageConfig
+			wantErr error
+		}{
+			{"width without height", PageConfig{Width: "210mm"}, picoloom.ErrInvalidPageSize},
+			{"unknown unit", PageConfig{Width: "210px", Height: "297mm"}, picoloom.ErrInvalidPageSize},
+			{"margin below minimum", PageConfig{Margins: MarginsConfig{Top: "1mm"}}, picoloom.ErrInvalidMargin},
+			{"inner with left", PageConfig{Margins: MarginsConfig{Inner: "1in", Left: "1in"}}, picoloom.ErrInvalidMargin},
+			{"margins wider than page", PageConfig{Size: "a5", Margins: MarginsConfig{Left: "3in", Right: "3in"}}, picoloom.ErrInvalidMargin},
+		}
+		for _, tt := range tests {
+			cfg := &Config{Page: tt.page}
+			err := cfg.Validate()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("%s: Config.Validate() error = %v, want %v", tt.name, err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "page") {
+				t.Errorf("%s: Config.Validate() error should mention page, got: %v", tt.name, err)
+			}
+		}
+	})
+
+	t.Run("overlong margin value returns error", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{Page: PageConfig{Margins: MarginsConfig{Outer: strings.Repeat("1", MaxLengthValueLength+1)}}}
+		err := cfg.Validate()
+		if !errors.Is(err, ErrFieldTooLong) {
+			t.Errorf("Config.Validate() error = %v, want %v", err, ErrFieldTooLong)
+		}
+	})
+
 	t.Run("invalid orientation returns error", func(t *testing.T) {
 		t.Parallel()
 		cfg := &Config{Page: PageConfig{Orientation: "diagonal"}}
@@ -1122,6 +1227,90 @@ func TestConfig_Validate_TOC(t *testing.T) {
 	})
 }
 
+func TestConfig_Validate_Outline(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		outline OutlineConfig
+		wantErr bool
+	}{
+		{"disabled ignores depths", OutlineConfig{Enabled: false, MaxDepth: 9}, false},
+		{"enabled with defaults", OutlineConfig{Enabled: true}, false},
+		{"enabled with valid depths", OutlineConfig{Enabled: true, MinDepth: 2, MaxDepth: 4}, false},
+		{"maxDepth 7 returns error", OutlineConfig{Enabled: true, MaxDepth: 7}, true},
+		{"negative minDepth returns error", OutlineConfig{Enabled: true, MinDepth: -1}, true},
+		{"minDepth greater than maxDepth returns error", OutlineConfig{Enabled: true, MinDepth: 4, MaxDepth: 2}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &Config{Outline: tt.outline}
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Config.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate_Security(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		security SecurityConfig
+		wantErr  bool
+	}{
+		{"enabled without passwords (from env)", SecurityConfig{Enabled: true}, false},
+		{"max length passwords", SecurityConfig{Enabled: true, UserPassword: strings.Repeat("u", 127), OwnerPassword: strings.Repeat("o", 127)}, false},
+		{"userPassword too long returns error", SecurityConfig{UserPassword: strings.Repeat("u", 128)}, true},
+		{"ownerPassword too long returns error", SecurityConfig{OwnerPassword: strings.Repeat("o", 128)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &Config{Security: tt.security}
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Config.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate_Signing(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		signing SigningConfig
+		wantErr bool
+	}{
+		{"enabled without certificate (from flags)", SigningConfig{Enabled: true}, false},
+		{"typical settings", SigningConfig{Enabled: true, CertFile: "signer.p12", Name: "Jane Doe", Reason: "Approval"}, false},
+		{"name too long returns error", SigningConfig{Name: strings.Repeat("n", MaxNameLength+1)}, true},
+		{"reason too long returns error", SigningConfig{Reason: strings.Repeat("r", MaxTextLength+1)}, true},
+		{"timestampURL too long returns error", SigningConfig{TimestampURL: "http://" + strings.Repeat("a", MaxURLLength)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &Config{Signing: tt.signing}
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Config.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestConfig_Validate_Watermark(t *testing.T) {
 	t.Parallel()
 
@@ -1289,6 +1478,222 @@ func TestConfig_Validate_Footer(t *testing.T) {
 	})
 }
 
+func TestConfig_Validate_Header(t *testing.T) {
+	t.Parallel()
+
+	t.Run("header with placeholders valid", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{Header: HeaderConfig{
+			Enabled: true,
+			Left:    "{title}",
+			Right:   "Page {pageNumber} of {totalPages}",
+		}}
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("Config.Validate() unexpected error: %v", err)
+		}
+	})
+
+	t.Run("header unknown placeholder returns error", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{Header: HeaderConfig{Enabled: true, Center: "{client}"}}
+		err := cfg.Validate()
+		if !errors.Is(err, picoloom.ErrInvalidHeaderPlaceholder) {
+			t.Errorf("Config.Validate() error = %v, want ErrInvalidHeaderPlaceholder", err)
+		}
+	})
+
+	t.Run("header slot too long returns error", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{Header: HeaderConfig{Enabled: true, Right: strings.Repeat("x", MaxTextLength+1)}}
+		err := cfg.Validate()
+		if !errors.Is(err, ErrFieldTooLong) {
+			t.Errorf("Config.Validate() error = %v, want ErrFieldTooLong", err)
+		}
+	})
+}
+
+func TestConfig_Validate_PageNumbering(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		numbering PageNumberingConfig
+		wantErr   error
+	}{
+		{"disabled ignores values", PageNumberingConfig{Format: "bogus"}, nil},
+		{"enabled with defaults", PageNumberingConfig{Enabled: true}, nil},
+		{"enabled with all values", PageNumberingConfig{Enabled: true, Format: "page-of", StartAt: 2, FrontMatter: "roman"}, nil},
+		{"unknown format returns error", PageNumberingConfig{Enabled: true, Format: "x/y"}, picoloom.ErrInvalidPageNumbering},
+		{"unknown frontMatter returns error", PageNumberingConfig{Enabled: true, FrontMatter: "hide"}, picoloom.ErrInvalidPageNumbering},
+		{"negative startAt returns error", PageNumberingConfig{Enabled: true, StartAt: -3}, picoloom.ErrInvalidPageNumbering},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &Config{PageNumbering: tt.numbering}
+			err := cfg.Validate()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Config.Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate_Diagrams(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		diagrams DiagramsConfig
+		wantErr  error
+	}{
+		{"disabled ignores values", DiagramsConfig{Theme: "bogus"}, nil},
+		{"enabled with defaults", DiagramsConfig{Enabled: true}, nil},
+		{"enabled with theme", DiagramsConfig{Enabled: true, Theme: "Forest"}, nil},
+		{"unknown theme returns error", DiagramsConfig{Enabled: true, Theme: "neon"}, picoloom.ErrInvalidDiagramTheme},
+		{"theme too long returns error", DiagramsConfig{Enabled: true, Theme: strings.Repeat("x", MaxDiagramThemeLength+1)}, ErrFieldTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &Config{Diagrams: tt.diagrams}
+			err := cfg.Validate()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Config.Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate_HTML(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		html    HTMLConfig
+		wantErr error
+	}{
+		{"disabled ignores values", HTMLConfig{AllowedTags: []string{"script"}}, nil},
+		{"enabled with defaults", HTMLConfig{AllowRaw: true}, nil},
+		{"enabled with allowlist", HTMLConfig{AllowRaw: true, AllowedTags: []string{"meter"}, AllowedAttributes: []string{"style"}}, nil},
+		{"script tag returns error", HTMLConfig{AllowRaw: true, AllowedTags: []string{"script"}}, picoloom.ErrInvalidRawHTML},
+		{"event handler returns error", HTMLConfig{AllowRaw: true, AllowedAttributes: []string{"onload"}}, picoloom.ErrInvalidRawHTML},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &Config{HTML: tt.html}
+			err := cfg.Validate()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Config.Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate_Vars(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		vars    map[string]string
+		wantErr error
+	}{
+		{"nil vars", nil, nil},
+		{"valid names", map[string]string{"client": "Acme", "doc_ref": "R-1"}, nil},
+		{"invalid name returns error", map[string]string{"client-name": "Acme"}, picoloom.ErrInvalidVariable},
+		{"value too long returns error", map[string]string{"note": strings.Repeat("x", MaxTextLength+1)}, ErrFieldTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &Config{Vars: tt.vars}
+			err := cfg.Validate()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Config.Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate_Citations(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		citations CitationsConfig
+		wantErr   string
+	}{
+		{"defaults", CitationsConfig{}, ""},
+		{"numeric", CitationsConfig{Style: "numeric", Title: "Sources"}, ""},
+		{"case insensitive", CitationsConfig{Style: "Author-Date"}, ""},
+		{"unknown style", CitationsConfig{Style: "apa"}, "citations.style"},
+		{"title too long", CitationsConfig{Title: strings.Repeat("x", MaxTOCTitleLength+1)}, "citations.title"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &Config{Bibliography: "refs.bib", Citations: tt.citations}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Config.Validate() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Config.Validate() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate_Index(t *testing.T) {
+	t.Parallel()
+
+	if err := (&Config{Index: IndexConfig{Enabled: true, Title: "Terms"}}).Validate(); err != nil {
+		t.Errorf("Config.Validate() unexpected error: %v", err)
+	}
+	cfg := &Config{Index: IndexConfig{Title: strings.Repeat("x", MaxTOCTitleLength+1)}}
+	if err := cfg.Validate(); !errors.Is(err, ErrFieldTooLong) || !strings.Contains(err.Error(), "index.title") {
+		t.Errorf("Config.Validate() error = %v, want %v for index.title", err, ErrFieldTooLong)
+	}
+}
+
+func TestConfig_Validate_ListOf(t *testing.T) {
+	t.Parallel()
+
+	if err := (&Config{ListOfFigures: ListOfConfig{Enabled: true, Title: "Figures", PageNumbers: true}}).Validate(); err != nil {
+		t.Errorf("Config.Validate() unexpected error: %v", err)
+	}
+	cfg := &Config{ListOfTables: ListOfConfig{Title: strings.Repeat("x", MaxTOCTitleLength+1)}}
+	if err := cfg.Validate(); !errors.Is(err, ErrFieldTooLong) || !strings.Contains(err.Error(), "listOfTables.title") {
+		t.Errorf("Config.Validate() error = %v, want %v for listOfTables.title", err, ErrFieldTooLong)
+	}
+}
+
+func TestConfig_Validate_Footnotes(t *testing.T) {
+	t.Parallel()
+
+	if err := (&Config{Footnotes: FootnotesConfig{Placement: "page", Numbering: "page"}}).Validate(); err != nil {
+		t.Errorf("Config.Validate() unexpected error: %v", err)
+	}
+	cfg := &Config{Footnotes: FootnotesConfig{Numbering: "page"}}
+	if err := cfg.Validate(); !errors.Is(err, picoloom.ErrInvalidFootnotes) || !strings.Contains(err.Error(), "footnotes:") {
+		t.Errorf("Config.Validate() error = %v, want %v for footnotes", err, picoloom.ErrInvalidFootnotes)
+	}
+}
+
 func TestConfig_Validate_Author(t *testing.T) {
 	t.Parallel()
 
@@ -1508,6 +1913,28 @@ func TestConfig_Validate_Document(t *testing.T) {
 		}
 	})
 
+	t.Run("document.keywords too many returns error", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{Document: DocumentConfig{
+			Keywords: make([]string, MaxKeywords+1),
+		}}
+		err := cfg.Validate()
+		if !errors.Is(err, ErrFieldTooLong) {
+			t.Errorf("Config.Validate() error = %v, want ErrFieldTooLong", err)
+		}
+	})
+
+	t.Run("document.keywords entry too long returns error", func(t *testing.T) {
+		t.Parallel()
+		cfg := &Config{Document: DocumentConfig{
+			Keywords: []string{"ok", string(make([]byte, MaxKeywordLength+1))},
+		}}
+		err := cfg.Validate()
+		if !errors.Is(err, ErrFieldTooLong) {
+			t.Errorf("Config.Validate() error = %v, want ErrFieldTooLong", err)
+		}
+	})
+
 	t.Run("valid extended document fields pass validation", func(t *testing.T) {
 		t.Parallel()
 		cfg := &Config{Document: DocumentConfig{
@@ -1517,6 +1944,7 @@ func TestConfig_Validate_Document(t *testing.T) {
 			DocumentType: "Technical Specification",
 			DocumentID:   "DOC-2024-001",
 			Description:  "Technical specification for the Phoenix system",
+			Keywords:     []string{"phoenix", "specification"},
 		}}
 		err := cfg.Validate()
 		if err != nil {
@@ -1821,3 +2249,201 @@ style: "default"
 		}
 	})
 }
+
+// ---------------------------------------------------------------------------
+// TestConfig_WithFrontmatter - Per-document frontmatter merge
+// ---------------------------------------------------------------------------
+
+func TestConfig_WithFrontmatter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil frontmatter returns config unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := DefaultConfig()
+		got, err := cfg.WithFrontmatter(nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != cfg {
+			t.Error("WithFrontmatter(nil) returned a different config")
+		}
+	})
+
+	t.Run("merges over config without mutating it", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := DefaultConfig()
+		cfg.Document.Title = "Base"
+		cfg.Document.ClientName = "Acme"
+		cfg.Style = "default"
+
+		opacity := 0.3
+		got, err := cfg.WithFrontmatter(&picoloom.Frontmatter{
+			Title:     "Chapter",
+			Version:   "v3",
+			Style:     "technical",
+			Watermark: &picoloom.FrontmatterWatermark{Text: "DRAFT", Opacity: &opacity},
+			Footer:    &picoloom.FrontmatterFooter{Text: "Internal"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got.Document.Title != "Chapter" || got.Document.Version != "v3" || got.Document.ClientName != "Acme" {
+			t.Errorf("Document = %+v, want title/version overridden and client kept", got.Document)
+		}
+		if got.Style != "technical" {
+			t.Errorf("Style = %q, want %q", got.Style, "technical")
+		}
+		if !got.Watermark.Enabled || got.Watermark.Opacity != 0.3 || got.Watermark.Angle != picoloom.DefaultWatermarkAngle {
+			t.Errorf("Watermark = %+v, want enabled with opacity 0.3 and default angle", got.Watermark)
+		}
+		if !got.Footer.Enabled || got.Footer.Text != "Internal" {
+			t.Errorf("Footer = %+v, want enabled with text", got.Footer)
+		}
+		if cfg.Document.Title != "Base" || cfg.Style != "default" || cfg.Watermark.Enabled {
+			t.Error("WithFrontmatter() mutated the receiver")
+		}
+	})
+
+	t.Run("merges vars without mutating config map", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := DefaultConfig()
+		cfg.Vars = map[string]string{"client": "Acme", "region": "EU"}
+		got, err := cfg.WithFrontmatter(&picoloom.Frontmatter{Vars: map[string]string{"client": "Globex"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Vars["client"] != "Globex" || got.Vars["region"] != "EU" {
+			t.Errorf("Vars = %v, want client overridden and region kept", got.Vars)
+		}
+		if cfg.Vars["client"] != "Acme" {
+			t.Error("WithFrontmatter() mutated the config vars")
+		}
+	})
+
+	t.Run("merges bibliography and citations", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := DefaultConfig()
+		cfg.Bibliography = "shared.bib"
+		cfg.Citations.Title = "Works Cited"
+		got, err := cfg.WithFrontmatter(&picoloom.Frontmatter{
+			Bibliography: "refs.json",
+			Citations:    &picoloom.FrontmatterCitations{Style: "numeric"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := CitationsConfig{Style: "numeric", Title: "Works Cited"}
+		if got.Bibliography != "refs.json" || got.Citations != want {
+			t.Errorf("Bibliography = %q, Citations = %+v, want refs.json and %+v", got.Bibliography, got.Citations, want)
+		}
+		if cfg.Bibliography != "shared.bib" || cfg.Citations.Style != "" {
+			t.Error("WithFrontmatter() mutated the receiver")
+		}
+	})
+
+	t.Run("index section enables the index", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := DefaultConfig()
+		got, err := cfg.WithFrontmatter(&picoloom.Frontmatter{Index: &picoloom.FrontmatterIndex{Title: "Terms"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := (IndexConfig{Enabled: true, Title: "Terms"}); got.Index != want {
+			t.Errorf("Index = %+v, want %+v", got.Index, want)
+		}
+		if cfg.Index.Enabled {
+			t.Error("WithFrontmatter() mutated the receiver")
+		}
+	})
+
+	t.Run("list sections enable lists", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := DefaultConfig()
+		cfg.ListOfTables = ListOfConfig{Enabled: true, PageNumbers: true}
+		off := false
+		got, err := cfg.WithFrontmatter(&picoloom.Frontmatter{
+			ListOfFigures: &picoloom.FrontmatterListOf{Title: "Figures"},
+			ListOfTables:  &picoloom.FrontmatterListOf{Enabled: &off},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := (ListOfConfig{Enabled: true, Title: "Figures"}); got.ListOfFigures != want {
+			t.Errorf("ListOfFigures = %+v, want %+v", got.ListOfFigures, want)
+		}
+		if want := (ListOfConfig{PageNumbers: true}); got.ListOfTables != want {
+			t.Errorf("ListOfTables = %+v, want %+v", got.ListOfTables, want)
+		}
+	})
+
+	t.Run("footnotes section overrides settings", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := DefaultConfig()
+		cfg.Footnotes = FootnotesConfig{Placement: "page"}
+		got, err := cfg.WithFrontmatter(&picoloom.Frontmatter{
+			Footnotes: &picoloom.FrontmatterFootnotes{Numbering: "page"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := (FootnotesConfig{Placement: "page", Numbering: "page"}); got.Footnotes != want {
+			t.Errorf("Footnotes = %+v, want %+v", got.Footnotes, want)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		fm      *picoloom.Frontmatter
+		wantErr string
+	}{
+		{
+			name:    "watermark without text",
+			fm:      &picoloom.Frontmatter{Watermark: &picoloom.FrontmatterWatermark{}},
+			wantErr: "frontmatter: watermark.text",
+		},
+		{
+			name:    "toc depth out of range",
+			fm:      &picoloom.Frontmatter{TOC: &picoloom.FrontmatterTOC{MaxDepth: 9}},
+			wantErr: "frontmatter: toc.maxDepth",
+		},
+		{
+			name:    "invalid var name",
+			fm:      &picoloom.Frontmatter{Vars: map[string]string{"a b": "x"}},
+			wantErr: "frontmatter: vars: invalid variable name",
+		},
+		{
+			name:    "citation style invalid",
+			fm:      &picoloom.Frontmatter{Citations: &picoloom.FrontmatterCitations{Style: "apa"}},
+			wantErr: "frontmatter: citations.style",
+		},
+		{
+			name:    "footnote placement invalid",
+			fm:      &picoloom.Frontmatter{Footnotes: &picoloom.FrontmatterFootnotes{Placement: "margin"}},
+			wantErr: "frontmatter: footnotes:",
+		},
+		{
+			name:    "footer position invalid",
+			fm:      &picoloom.Frontmatter{Footer: &picoloom.FrontmatterFooter{Position: "top"}},
+			wantErr: "frontmatter: footer.position",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := DefaultConfig().WithFrontmatter(tt.fm)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("WithFrontmatter() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
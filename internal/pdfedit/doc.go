@@ -0,0 +1,26 @@
+// Package pdfedit edits PDF files produced by Chrome with incremental updates.
+//
+// # Scope
+//
+// The package reads the classic cross-reference tables written by Chrome's
+// PDF backend (Skia), resolves indirect objects, and appends new or replaced
+// objects after the original bytes:
+//
+//	doc, err := pdfedit.Open(pdfBytes)
+//	u := doc.NewUpdate()
+//	ref := u.Add(pdfedit.Dict{"Type": pdfedit.Name("Metadata")})
+//	out := u.Bytes()
+//
+// The original bytes are never rewritten, so earlier revisions stay intact.
+// Cross-reference streams and object streams (PDF 1.5+) are not supported
+// and are reported as ErrUnsupported.
+//
+// Encrypt is the exception: it writes a new file, because every revision
+// of an encrypted document must be encrypted.
+//
+// # Objects
+//
+// PDF objects map to Go values: nil, bool, int64, float64, Name, String,
+// Array, Dict, Ref and *Stream. Stream data is kept as stored in the file
+// (still encoded when the stream has a /Filter).
+package pdfedit
@@ -0,0 +1,259 @@
+package pdfedit
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Sentinel errors for PDF parsing.
+var (
+	ErrMalformed      = errors.New("malformed PDF")
+	ErrUnsupported    = errors.New("unsupported PDF structure")
+	ErrObjectNotFound = errors.New("PDF object not found")
+)
+
+// maxXrefSections bounds the /Prev chain to reject cyclic input.
+const maxXrefSections = 1024
+
+// Document is a parsed PDF ready for incremental updates.
+type Document struct {
+	data      []byte
+	offsets   map[int]int64 // object number -> byte offset (in-use objects)
+	trailer   Dict          // newest trailer
+	startXref int64         // offset of the newest xref section
+	size      int           // trailer /Size
+}
+
+// Open parses the cross-reference sections and trailer of data.
+// Returns ErrUnsupported for cross-reference streams.
+func Open(data []byte) (*Document, error) {
+	if !bytes.HasPrefix(data, []byte("%PDF-")) {
+		return nil, fmt.Errorf("%w: missing %%PDF header", ErrMalformed)
+	}
+
+	idx := bytes.LastIndex(data, []byte("startxref"))
+	if idx < 0 {
+		return nil, fmt.Errorf("%w: missing startxref", ErrMalformed)
+	}
+	p := &parser{data: data, pos: idx + len("startxref")}
+	start, err := p.integer()
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &Document{data: data, offsets: make(map[int]int64), startXref: start}
+	seen := make(map[int64]bool)
+	for offset := start; ; {
+		if seen[offset] || len(seen) >= maxXrefSections {
+			return nil, fmt.Errorf("%w: cyclic xref chain", ErrMalformed)
+		}
+		seen[offset] = true
+
+		trailer, err := doc.readXref(offset)
+		if err != nil {
+			return nil, err
+		}
+		if doc.trailer == nil {
+			doc.trailer = trailer
+		}
+		prev, ok := trailer.Int("Prev")
+		if !ok {
+			break
+		}
+		offset = prev
+	}
+
+	size, ok := doc.trailer.Int("Size")
+	if !ok {
+		return nil, fmt.Errorf("%w: trailer missing /Size", ErrMalformed)
+	}
+	doc.size = int(size)
+	if _, ok := doc.trailer.Ref("Root"); !ok {
+		return nil, fmt.Errorf("%w: trailer missing /Root", ErrMalformed)
+	}
+	return doc, nil
+}
+
+// readXref parses one classic xref section at offset and returns its trailer.
+// Entries already known from a newer section are kept.
+func (d *Document) readXref(offset int64) (Dict, error) {
+	if offset < 0 || offset >= int64(len(d.data)) {
+		return nil, fmt.Errorf("%w: xref offset %d out of range", ErrMalformed, offset)
+	}
+	p := &parser{data: d.data, pos: int(offset)}
+	if kw := p.keyword(); kw != "xref" {
+		if _, err := strconv.Atoi(kw); err == nil {
+			return nil, fmt.Errorf("%w: cross-reference streams", ErrUnsupported)
+		}
+		return nil, fmt.Errorf("%w: expected xref at offset %d", ErrMalformed, offset)
+	}
+
+	for {
+		save := p.pos
+		if p.keyword() == "trailer" {
+			break
+		}
+		p.pos = save
+
+		first, err := p.integer()
+		if err != nil {
+			return nil, err
+		}
+		count, err := p.integer()
+		if err != nil {
+			return nil, err
+		}
+		for i := int64(0); i < count; i++ {
+			off, err := p.integer()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.integer(); err != nil { // generation
+				return nil, err
+			}
+			kind := p.keyword()
+			num := int(first + i)
+			if _, known := d.offsets[num]; known {
+				continue
+			}
+			switch kind {
+			case "n":
+				d.offsets[num] = off
+			case "f":
+				d.offsets[num] = -1 // free in a newer revision shadows older entries
+			default:
+				return nil, fmt.Errorf("%w: invalid xref entry type %q", ErrMalformed, kind)
+			}
+		}
+	}
+
+	obj, err := p.object(0)
+	if err != nil {
+		return nil, err
+	}
+	trailer, ok := obj.(Dict)
+	if !ok {
+		return nil, fmt.Errorf("%w: trailer is not a dictionary", ErrMalformed)
+	}
+	return trailer, nil
+}
+
+// Trailer returns the newest trailer dictionary. Callers must not modify it.
+func (d *Document) Trailer() Dict {
+	return d.trailer
+}
+
+// Object reads the indirect object ref.
+func (d *Document) Object(ref Ref) (Object, error) {
+	off, ok := d.offsets[ref.Num]
+	if !ok || off < 0 {
+		return nil, fmt.Errorf("%w: %d %d R", ErrObjectNotFound, ref.Num, ref.Gen)
+	}
+	if off >= int64(len(d.data)) {
+		return nil, fmt.Errorf("%w: object %d offset out of range", ErrMalformed, ref.Num)
+	}
+
+	p := &parser{data: d.data, pos: int(off)}
+	num, err := p.integer()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.integer(); err != nil {
+		return nil, err
+	}
+	if int(num) != ref.Num {
+		return nil, fmt.Errorf("%w: xref points to object %d, want %d", ErrMalformed, num, ref.Num)
+	}
+	if err := p.expect("obj"); err != nil {
+		return nil, err
+	}
+
+	obj, err := p.object(0)
+	if err != nil {
+		return nil, err
+	}
+	dict, isDict := obj.(Dict)
+	if !isDict {
+		return obj, nil
+	}
+
+	save := p.pos
+	if p.keyword() != "stream" {
+		p.pos = save
+		return dict, nil
+	}
+	return d.readStream(p, dict)
+}
+
+// readStream reads stream data following the "stream" keyword.
+func (d *Document) readStream(p *parser, dict Dict) (*Stream, error) {
+	// The keyword is followed by CRLF or LF before the data.
+	if p.pos < len(p.data) && p.data[p.pos] == '\r' {
+		p.pos++
+	}
+	if p.pos < len(p.data) && p.data[p.pos] == '\n' {
+		p.pos++
+	}
+
+	lengthObj, err := d.Resolve(dict["Length"])
+	if err != nil {
+		return nil, err
+	}
+	length, ok := lengthObj.(int64)
+	if !ok || length < 0 || int64(p.pos)+length > int64(len(p.data)) {
+		return nil, fmt.Errorf("%w: invalid stream length", ErrMalformed)
+	}
+
+	data := p.data[p.pos : p.pos+int(length)]
+	return &Stream{Dict: dict, Data: data}, nil
+}
+
+// Resolve follows obj if it is a reference.
+func (d *Document) Resolve(obj Object) (Object, error) {
+	ref, ok := obj.(Ref)
+	if !ok {
+		return obj, nil
+	}
+	return d.Object(ref)
+}
+
+// ResolveDict resolves obj and requires a dictionary.
+func (d *Document) ResolveDict(obj Object) (Dict, error) {
+	resolved, err := d.Resolve(obj)
+	if err != nil {
+		return nil, err
+	}
+	switch v := resolved.(type) {
+	case Dict:
+		return v, nil
+	case *Stream:
+		return v.Dict, nil
+	}
+	return nil, fmt.Errorf("%w: expected dictionary, got %T", ErrMalformed, resolved)
+}
+
+// Catalog returns the document catalog and its reference.
+func (d *Document) Catalog() (Dict, Ref, error) {
+	ref, _ := d.trailer.Ref("Root")
+	catalog, err := d.ResolveDict(ref)
+	if err != nil {
+		return nil, Ref{}, fmt.Errorf("reading catalog: %w", err)
+	}
+	return catalog, ref, nil
+}
+
+// Info returns the document information dictionary and its reference.
+// ok is false if the trailer has no /Info.
+func (d *Document) Info() (info Dict, ref Ref, ok bool, err error) {
+	ref, ok = d.trailer.Ref("Info")
+	if !ok {
+		return nil, Ref{}, false, nil
+	}
+	info, err = d.ResolveDict(ref)
+	if err != nil {
+		return nil, Ref{}, false, fmt.Errorf("reading info: %w", err)
+	}
+	return info, ref, true, nil
+}
@@ -0,0 +1,152 @@
+package pdfedit
+
+// Notes:
+// - Test PDFs are built by buildPDF in the layout Chrome's Skia backend
+//   writes: %PDF-1.4 header, classic xref table, trailer with /Root and /Info
+// - Cross-reference streams are only tested as an unsupported input
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// buildPDF assembles a PDF whose objects are numbered from 1 in order.
+// trailerExtra is appended inside the trailer dictionary.
+func buildPDF(trailerExtra string, objects ...string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<</Size %d /Root 1 0 R%s>>\nstartxref\n%d\n%%%%EOF", len(objects)+1, trailerExtra, xref)
+	return buf.Bytes()
+}
+
+// skiaPDF returns a two-page PDF shaped like Chrome's output.
+func skiaPDF() []byte {
+	return buildPDF(" /Info 6 0 R",
+		"<</Type /Catalog /Pages 2 0 R>>",
+		"<</Type /Pages /Count 2 /Kids [3 0 R 4 0 R]>>",
+		"<</Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 5 0 R>>",
+		"<</Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 5 0 R>>",
+		"<</Length 9>>\nstream\nBT ET q Q\nendstream",
+		"<</Title (Document) /Producer (Skia/PDF m120) /CreationDate (D:20250115120000+00'00')>>",
+	)
+}
+
+// ---------------------------------------------------------------------------
+// TestOpen - Cross-reference and trailer parsing
+// ---------------------------------------------------------------------------
+
+func TestOpen(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reads catalog and info", func(t *testing.T) {
+		t.Parallel()
+
+		doc, err := Open(skiaPDF())
+		if err != nil {
+			t.Fatalf("Open() unexpected error: %v", err)
+		}
+		catalog, ref, err := doc.Catalog()
+		if err != nil {
+			t.Fatalf("Catalog() unexpected error: %v", err)
+		}
+		if ref != (Ref{Num: 1}) || catalog["Type"] != Name("Catalog") {
+			t.Errorf("Catalog() = %v at %v, want /Catalog at 1 0 R", catalog, ref)
+		}
+		info, _, ok, err := doc.Info()
+		if err != nil || !ok {
+			t.Fatalf("Info() = ok %v, err %v, want info", ok, err)
+		}
+		if got := info["Producer"].(String).Text(); got != "Skia/PDF m120" {
+			t.Errorf("Info() Producer = %q, want %q", got, "Skia/PDF m120")
+		}
+	})
+
+	t.Run("reads streams", func(t *testing.T) {
+		t.Parallel()
+
+		doc, err := Open(skiaPDF())
+		if err != nil {
+			t.Fatalf("Open() unexpected error: %v", err)
+		}
+		obj, err := doc.Object(Ref{Num: 5})
+		if err != nil {
+			t.Fatalf("Object() unexpected error: %v", err)
+		}
+		stream, ok := obj.(*Stream)
+		if !ok || string(stream.Data) != "BT ET q Q" {
+			t.Errorf("Object() = %#v, want stream with data %q", obj, "BT ET q Q")
+		}
+	})
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{"missing header", []byte("hello"), ErrMalformed},
+		{"missing startxref", []byte("%PDF-1.4\n"), ErrMalformed},
+		{"xref stream", []byte("%PDF-1.5\n1 0 obj\n<</Type /XRef>>\nendobj\nstartxref\n9\n%%EOF"), ErrUnsupported},
+		{"missing root", bytes.Replace(buildPDF("", "<</Type /Pages>>"), []byte("/Root 1 0 R"), nil, 1), ErrMalformed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := Open(tt.data); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Open() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestParser - Object syntax
+// ---------------------------------------------------------------------------
+
+func TestParser(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		in   string
+		want Object
+	}{
+		{"integer", "42", int64(42)},
+		{"real", "-1.5", -1.5},
+		{"reference", "12 0 R", Ref{Num: 12}},
+		{"name with escape", "/A#20B", Name("A B")},
+		{"literal string with escapes", `(a\(b\)\\c\101)`, String(`a(b)\cA`)},
+		{"nested parentheses", "(a(b)c)", String("a(b)c")},
+		{"hex string", "<48 69>", String("Hi")},
+		{"array of refs", "[1 0 R 2 0 R]", Array{Ref{Num: 1}, Ref{Num: 2}}},
+		{"dict", "<</A 1 /B [true null]>>", Dict{"A": int64(1), "B": Array{true, nil}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := &parser{data: []byte(tt.in)}
+			got, err := p.object(0)
+			if err != nil {
+				t.Fatalf("object(%q) unexpected error: %v", tt.in, err)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("object(%q) = %#v, want %#v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
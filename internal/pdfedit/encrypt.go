@@ -0,0 +1,345 @@
+package pdfedit
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// MaxPasswordLength is the longest password the AES-256 handler uses;
+// longer UTF-8 passwords are truncated, as the standard requires.
+const MaxPasswordLength = 127
+
+// Permission is a user access permission (ISO 32000-2, Table 22).
+type Permission uint32
+
+// Permissions granted to users who open the document with the user password.
+const (
+	PermPrint         Permission = 1 << 2  // Print, possibly at low quality
+	PermModify        Permission = 1 << 3  // Modify contents
+	PermCopy          Permission = 1 << 4  // Copy or extract text and graphics
+	PermAnnotate      Permission = 1 << 5  // Add or modify annotations and form fields
+	PermFillForms     Permission = 1 << 8  // Fill existing form fields
+	PermAccessibility Permission = 1 << 9  // Extract for accessibility (always granted)
+	PermAssemble      Permission = 1 << 10 // Insert, rotate or delete pages
+	PermPrintHigh     Permission = 1 << 11 // Print at full quality
+)
+
+// permReserved holds the bits that must be set in /P: 7-8 and 13-32.
+const permReserved = 0xFFFFF0C0
+
+// Encryption configures the standard security handler with AES-256
+// (revision 6). An empty OwnerPassword uses UserPassword.
+type Encryption struct {
+	UserPassword  string
+	OwnerPassword string
+	Permissions   Permission
+}
+
+// randReader supplies keys, salts and IVs. Tests may replace it.
+var randReader io.Reader = rand.Reader
+
+// Encrypt returns a copy of the PDF in data, rewritten with every string
+// and stream encrypted. Unlike the other edits, encryption cannot be an
+// incremental update: readers decrypt every revision, so the newest
+// revision of each object is written into a single new file.
+// Returns ErrUnsupported if data is already encrypted.
+func Encrypt(data []byte, enc Encryption) ([]byte, error) {
+	doc, err := Open(data)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := doc.trailer["Encrypt"]; ok {
+		return nil, fmt.Errorf("%w: document is already encrypted", ErrUnsupported)
+	}
+
+	owner := enc.OwnerPassword
+	if owner == "" {
+		owner = enc.UserPassword
+	}
+	key, encryptDict, err := newSecurityHandler(truncatePassword(enc.UserPassword), truncatePassword(owner), enc.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	catalogRef, _ := doc.trailer.Ref("Root")
+	objects := make(map[int]Object, len(doc.offsets)+1)
+	for num, off := range doc.offsets {
+		if off < 0 || num == 0 {
+			continue
+		}
+		obj, err := doc.Object(Ref{Num: num})
+		if err != nil {
+			return nil, err
+		}
+		if num == catalogRef.Num {
+			if obj, err = withExtension(obj); err != nil {
+				return nil, err
+			}
+		}
+		if objects[num], err = encryptObject(obj, key); err != nil {
+			return nil, err
+		}
+	}
+
+	encryptRef := Ref{Num: doc.size}
+	objects[encryptRef.Num] = encryptDict
+
+	trailer := Dict{
+		"Root":    catalogRef,
+		"Encrypt": encryptRef,
+		"ID":      doc.trailer["ID"],
+	}
+	if info, ok := doc.trailer.Ref("Info"); ok {
+		trailer["Info"] = info
+	}
+	if trailer["ID"] == nil {
+		sum := sha256.Sum256(data)
+		id := String(sum[:16])
+		trailer["ID"] = Array{id, id}
+	}
+	return writeFile("1.7", objects, trailer), nil
+}
+
+// newSecurityHandler generates a file key and the matching encryption
+// dictionary (ISO 32000-2, algorithms 8 to 10).
+func newSecurityHandler(user, owner []byte, perms Permission) ([]byte, Dict, error) {
+	random := make([]byte, securityRandomSize)
+	if _, err := io.ReadFull(randReader, random); err != nil {
+		return nil, nil, fmt.Errorf("generating key: %w", err)
+	}
+	return securityHandler(user, owner, perms, random)
+}
+
+// securityRandomSize is the random input of securityHandler: the file key,
+// four 8-byte salts and the 4 random bytes of /Perms.
+const securityRandomSize = 32 + 4*8 + 4
+
+// securityHandler derives the encryption dictionary from random, laid out
+// as securityRandomSize describes.
+func securityHandler(user, owner []byte, perms Permission, random []byte) ([]byte, Dict, error) {
+	key := random[:32]
+	userValidation, userKeySalt := random[32:40], random[40:48]
+	ownerValidation, ownerKeySalt := random[48:56], random[56:64]
+
+	u := append(hashR6(user, userValidation, nil), append(userValidation, userKeySalt...)...)
+	ue, err := aesNoPadding(hashR6(user, userKeySalt, nil), key)
+	if err != nil {
+		return nil, nil, err
+	}
+	o := append(hashR6(owner, ownerValidation, u), append(ownerValidation, ownerKeySalt...)...)
+	oe, err := aesNoPadding(hashR6(owner, ownerKeySalt, u), key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p := uint32(permReserved) | uint32(perms) | uint32(PermAccessibility)
+	permsBlock := make([]byte, 16)
+	binary.LittleEndian.PutUint32(permsBlock, p)
+	binary.LittleEndian.PutUint32(permsBlock[4:], 0xFFFFFFFF)
+	copy(permsBlock[8:], "Tadb")
+	copy(permsBlock[12:], random[64:68])
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	block.Encrypt(permsBlock, permsBlock)
+
+	dict := Dict{
+		"Filter": Name("Standard"),
+		"V":      int64(5),
+		"R":      int64(6),
+		"Length": int64(256),
+		"CF": Dict{"StdCF": Dict{
+			"AuthEvent": Name("DocOpen"),
+			"CFM":       Name("AESV3"),
+			"Length":    int64(32),
+		}},
+		"StmF":            Name("StdCF"),
+		"StrF":            Name("StdCF"),
+		"O":               String(o),
+		"U":               String(u),
+		"OE":              String(oe),
+		"UE":              String(ue),
+		"P":               int64(int32(p)),
+		"Perms":           String(permsBlock),
+		"EncryptMetadata": true,
+	}
+	return key, dict, nil
+}
+
+// hashR6 is the revision 6 password hash (ISO 32000-2, algorithm 2.B).
+func hashR6(password, salt, udata []byte) []byte {
+	h := sha256.New()
+	h.Write(password)
+	h.Write(salt)
+	h.Write(udata)
+	k := h.Sum(nil)
+
+	var e []byte
+	for round := 0; round < 64 || int(e[len(e)-1]) > round-32; round++ {
+		seq := make([]byte, 0, len(password)+len(k)+len(udata))
+		seq = append(append(append(seq, password...), k...), udata...)
+		k1 := bytes.Repeat(seq, 64)
+
+		block, _ := aes.NewCipher(k[:16]) // 16-byte key cannot fail
+		e = make([]byte, len(k1))
+		cipher.NewCBCEncrypter(block, k[16:32]).CryptBlocks(e, k1)
+
+		sum := 0
+		for _, b := range e[:16] {
+			sum += int(b)
+		}
+		switch sum % 3 {
+		case 0:
+			s := sha256.Sum256(e)
+			k = s[:]
+		case 1:
+			s := sha512.Sum384(e)
+			k = s[:]
+		default:
+			s := sha512.Sum512(e)
+			k = s[:]
+		}
+	}
+	return k[:32]
+}
+
+// aesNoPadding encrypts data, a multiple of the block size, with
+// AES-256-CBC and a zero IV, as used for /UE and /OE.
+func aesNoPadding(key, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(data))
+	cipher.NewCBCEncrypter(block, make([]byte, aes.BlockSize)).CryptBlocks(out, data)
+	return out, nil
+}
+
+// aesEncrypt encrypts data with AES-256-CBC and PKCS#7 padding, prefixed
+// by a random IV, as the AESV3 crypt filter requires.
+func aesEncrypt(key, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	pad := aes.BlockSize - len(data)%aes.BlockSize
+	out := make([]byte, aes.BlockSize+len(data)+pad)
+	if _, err := io.ReadFull(randReader, out[:aes.BlockSize]); err != nil {
+		return nil, fmt.Errorf("generating IV: %w", err)
+	}
+	copy(out[aes.BlockSize:], data)
+	for i := len(out) - pad; i < len(out); i++ {
+		out[i] = byte(pad)
+	}
+	cipher.NewCBCEncrypter(block, out[:aes.BlockSize]).CryptBlocks(out[aes.BlockSize:], out[aes.BlockSize:])
+	return out, nil
+}
+
+// encryptObject returns obj with every string and stream encrypted.
+func encryptObject(obj Object, key []byte) (Object, error) {
+	switch v := obj.(type) {
+	case String:
+		out, err := aesEncrypt(key, []byte(v))
+		return String(out), err
+	case Array:
+		out := make(Array, len(v))
+		for i, item := range v {
+			var err error
+			if out[i], err = encryptObject(item, key); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case Dict:
+		out := make(Dict, len(v))
+		for k, item := range v {
+			var err error
+			if out[k], err = encryptObject(item, key); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case *Stream:
+		dict, err := encryptObject(v.Dict, key)
+		if err != nil {
+			return nil, err
+		}
+		data, err := aesEncrypt(key, v.Data)
+		if err != nil {
+			return nil, err
+		}
+		return &Stream{Dict: dict.(Dict), Data: data}, nil
+	}
+	return obj, nil
+}
+
+// withExtension declares the Adobe extension level 8 that AES-256 needs in
+// a PDF 1.7 file.
+func withExtension(obj Object) (Object, error) {
+	catalog, ok := obj.(Dict)
+	if !ok {
+		return nil, fmt.Errorf("%w: catalog is not a dictionary", ErrMalformed)
+	}
+	catalog = catalog.Clone()
+	catalog["Extensions"] = Dict{"ADBE": Dict{
+		"BaseVersion":    Name("1.7"),
+		"ExtensionLevel": int64(8),
+	}}
+	return catalog, nil
+}
+
+// truncatePassword limits a UTF-8 password to MaxPasswordLength bytes.
+func truncatePassword(s string) []byte {
+	if len(s) > MaxPasswordLength {
+		s = s[:MaxPasswordLength]
+	}
+	return []byte(s)
+}
+
+// writeFile writes a complete PDF with one cross-reference section.
+// Object numbers missing from objects are written as free entries.
+func writeFile(version string, objects map[int]Object, trailer Dict) []byte {
+	size := 1
+	for num := range objects {
+		size = max(size, num+1)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%%PDF-%s\n%%\xe2\xe3\xcf\xd3\n", version)
+	offsets := make([]int, size)
+	for num := 1; num < size; num++ {
+		obj, ok := objects[num]
+		if !ok {
+			continue
+		}
+		offsets[num] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n", num)
+		writeObject(&buf, obj)
+		buf.WriteString("\nendobj\n")
+	}
+
+	xrefOffset := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n", size)
+	for num := range size {
+		if _, ok := objects[num]; !ok || num == 0 {
+			buf.WriteString("0000000000 65535 f \n")
+			continue
+		}
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offsets[num])
+	}
+
+	trailer = trailer.Clone()
+	trailer["Size"] = int64(size)
+	buf.WriteString("trailer\n")
+	writeDict(&buf, trailer)
+	fmt.Fprintf(&buf, "\nstartxref\n%d\n%%%%EOF\n", xrefOffset)
+	return buf.Bytes()
+}
@@ -0,0 +1,306 @@
+package pdfedit
+
+// Notes:
+// - No decrypting reader is available, so tests derive the file key from
+//   /UE the way a reader does (ISO 32000-2, algorithm 2.A) and decrypt
+//   strings and streams with it
+// - No reference tool is available either: the known-answer vectors in
+//   TestHashR6 and TestSecurityHandler were computed by a separate
+//   implementation of algorithms 2.B and 8 to 10 on Node's crypto module,
+//   written from ISO 32000-2 rather than from this code
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// fileKey validates password against /U and decrypts the file key from /UE.
+func fileKey(t *testing.T, encrypt Dict, password string) []byte {
+	t.Helper()
+
+	u := []byte(encrypt["U"].(String))
+	ue := []byte(encrypt["UE"].(String))
+	if len(u) != 48 || len(ue) != 32 {
+		t.Fatalf("U, UE lengths = %d, %d, want 48, 32", len(u), len(ue))
+	}
+	pw := []byte(password)
+	if !bytes.Equal(hashR6(pw, u[32:40], nil), u[:32]) {
+		t.Fatalf("password %q does not validate against /U", password)
+	}
+	block, err := aes.NewCipher(hashR6(pw, u[40:48], nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := make([]byte, 32)
+	cipher.NewCBCDecrypter(block, make([]byte, aes.BlockSize)).CryptBlocks(key, ue)
+	return key
+}
+
+// decrypt reverses aesEncrypt.
+func decrypt(t *testing.T, key, data []byte) string {
+	t.Helper()
+
+	if len(data) < 2*aes.BlockSize || len(data)%aes.BlockSize != 0 {
+		t.Fatalf("encrypted length %d is not IV plus whole blocks", len(data))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := make([]byte, len(data)-aes.BlockSize)
+	cipher.NewCBCDecrypter(block, data[:aes.BlockSize]).CryptBlocks(out, data[aes.BlockSize:])
+	pad := int(out[len(out)-1])
+	if pad < 1 || pad > aes.BlockSize {
+		t.Fatalf("invalid padding %d", pad)
+	}
+	return string(out[:len(out)-pad])
+}
+
+// encrypted encrypts data and opens the result.
+func encrypted(t *testing.T, data []byte, enc Encryption) (*Document, Dict) {
+	t.Helper()
+
+	out, err := Encrypt(data, enc)
+	if err != nil {
+		t.Fatalf("Encrypt() unexpected error: %v", err)
+	}
+	if !bytes.HasPrefix(out, []byte("%PDF-1.7\n")) {
+		t.Errorf("Encrypt() header = %q, want %%PDF-1.7", out[:9])
+	}
+	doc, err := Open(out)
+	if err != nil {
+		t.Fatalf("Open(encrypted) unexpected error: %v", err)
+	}
+	dict, err := doc.ResolveDict(doc.Trailer()["Encrypt"])
+	if err != nil {
+		t.Fatalf("ResolveDict(/Encrypt) unexpected error: %v", err)
+	}
+	return doc, dict
+}
+
+// ---------------------------------------------------------------------------
+// TestEncrypt - AES-256 security handler and object encryption
+// ---------------------------------------------------------------------------
+
+func TestEncrypt(t *testing.T) {
+	t.Parallel()
+
+	t.Run("encrypts strings and streams", func(t *testing.T) {
+		t.Parallel()
+
+		doc, dict := encrypted(t, skiaPDF(), Encryption{UserPassword: "user", OwnerPassword: "owner"})
+		for key, want := range map[Name]Object{"Filter": Name("Standard"), "V": int64(5), "R": int64(6), "StmF": Name("StdCF")} {
+			if dict[key] != want {
+				t.Errorf("/Encrypt /%s = %v, want %v", key, dict[key], want)
+			}
+		}
+		key := fileKey(t, dict, "user")
+
+		info, _, ok, err := doc.Info()
+		if err != nil || !ok {
+			t.Fatalf("Info() = ok %v, err %v, want info", ok, err)
+		}
+		if got := decrypt(t, key, []byte(info["Title"].(String))); got != "Document" {
+			t.Errorf("decrypted Title = %q, want %q", got, "Document")
+		}
+		obj, err := doc.Object(Ref{Num: 5})
+		if err != nil {
+			t.Fatalf("Object() unexpected error: %v", err)
+		}
+		if got := decrypt(t, key, obj.(*Stream).Data); got != "BT ET q Q" {
+			t.Errorf("decrypted stream = %q, want %q", got, "BT ET q Q")
+		}
+	})
+
+	t.Run("owner password unlocks the same key", func(t *testing.T) {
+		t.Parallel()
+
+		_, dict := encrypted(t, skiaPDF(), Encryption{UserPassword: "user", OwnerPassword: "owner"})
+		userKey := fileKey(t, dict, "user")
+
+		o := []byte(dict["O"].(String))
+		u := []byte(dict["U"].(String))
+		if !bytes.Equal(hashR6([]byte("owner"), o[32:40], u), o[:32]) {
+			t.Fatal("owner password does not validate against /O")
+		}
+		block, _ := aes.NewCipher(hashR6([]byte("owner"), o[40:48], u))
+		ownerKey := make([]byte, 32)
+		cipher.NewCBCDecrypter(block, make([]byte, aes.BlockSize)).CryptBlocks(ownerKey, []byte(dict["OE"].(String)))
+		if !bytes.Equal(ownerKey, userKey) {
+			t.Error("owner and user passwords decrypt different file keys")
+		}
+	})
+
+	t.Run("permissions are recorded in P and Perms", func(t *testing.T) {
+		t.Parallel()
+
+		_, dict := encrypted(t, skiaPDF(), Encryption{UserPassword: "u", Permissions: PermPrint | PermPrintHigh})
+		want := uint32(permReserved) | uint32(PermPrint|PermPrintHigh|PermAccessibility)
+		if got := uint32(dict["P"].(int64)); got != want {
+			t.Errorf("/P = %#x, want %#x", got, want)
+		}
+
+		block, _ := aes.NewCipher(fileKey(t, dict, "u"))
+		perms := make([]byte, 16)
+		block.Decrypt(perms, []byte(dict["Perms"].(String)))
+		if got := binary.LittleEndian.Uint32(perms); got != want || string(perms[9:12]) != "adb" || perms[8] != 'T' {
+			t.Errorf("decrypted /Perms = %x, want P %#x and \"Tadb\"", perms, want)
+		}
+	})
+
+	t.Run("empty owner password uses the user password", func(t *testing.T) {
+		t.Parallel()
+
+		_, dict := encrypted(t, skiaPDF(), Encryption{UserPassword: "secret"})
+		o := []byte(dict["O"].(String))
+		if !bytes.Equal(hashR6([]byte("secret"), o[32:40], []byte(dict["U"].(String))), o[:32]) {
+			t.Error("user password does not validate against /O")
+		}
+	})
+
+	t.Run("long passwords are truncated", func(t *testing.T) {
+		t.Parallel()
+
+		long := strings.Repeat("p", MaxPasswordLength+10)
+		_, dict := encrypted(t, skiaPDF(), Encryption{UserPassword: long})
+		fileKey(t, dict, long[:MaxPasswordLength])
+	})
+
+	t.Run("writes the newest revision once", func(t *testing.T) {
+		t.Parallel()
+
+		doc, err := Open(skiaPDF())
+		if err != nil {
+			t.Fatalf("Open() unexpected error: %v", err)
+		}
+		u := doc.NewUpdate()
+		u.Set(Ref{Num: 6}, Dict{"Title": String("Updated")})
+		out, err := Encrypt(u.Bytes(), Encryption{UserPassword: "u"})
+		if err != nil {
+			t.Fatalf("Encrypt() unexpected error: %v", err)
+		}
+		if bytes.Count(out, []byte("%%EOF")) != 1 || bytes.Contains(out, []byte("/Prev")) {
+			t.Error("Encrypt() kept earlier revisions")
+		}
+
+		enc, err := Open(out)
+		if err != nil {
+			t.Fatalf("Open(encrypted) unexpected error: %v", err)
+		}
+		dict, _ := enc.ResolveDict(enc.Trailer()["Encrypt"])
+		info, _, _, _ := enc.Info()
+		if got := decrypt(t, fileKey(t, dict, "u"), []byte(info["Title"].(String))); got != "Updated" {
+			t.Errorf("decrypted Title = %q, want %q", got, "Updated")
+		}
+	})
+
+	t.Run("declares the AES-256 extension and an ID", func(t *testing.T) {
+		t.Parallel()
+
+		doc, _ := encrypted(t, skiaPDF(), Encryption{UserPassword: "u"})
+		catalog, _, err := doc.Catalog()
+		if err != nil {
+			t.Fatalf("Catalog() unexpected error: %v", err)
+		}
+		ext, _ := doc.ResolveDict(catalog["Extensions"])
+		adbe, _ := doc.ResolveDict(ext["ADBE"])
+		if adbe["ExtensionLevel"] != int64(8) {
+			t.Errorf("catalog /Extensions = %v, want ADBE level 8", ext)
+		}
+		if id, ok := doc.Trailer()["ID"].(Array); !ok || len(id) != 2 {
+			t.Errorf("trailer /ID = %v, want two strings", doc.Trailer()["ID"])
+		}
+	})
+
+	t.Run("rejects encrypted input", func(t *testing.T) {
+		t.Parallel()
+
+		out, err := Encrypt(skiaPDF(), Encryption{UserPassword: "u"})
+		if err != nil {
+			t.Fatalf("Encrypt() unexpected error: %v", err)
+		}
+		if _, err := Encrypt(out, Encryption{UserPassword: "u"}); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("Encrypt(encrypted) error = %v, want %v", err, ErrUnsupported)
+		}
+	})
+}
+
+// ---------------------------------------------------------------------------
+// TestHashR6 - Revision 6 password hash known answers
+// ---------------------------------------------------------------------------
+
+func TestHashR6(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		password string
+		salt     string
+		udata    []byte
+		want     string
+	}{
+		{
+			name:     "user password",
+			password: "user",
+			salt:     "0123456789abcdef",
+			want:     "14386db4da04e85f8e877aa3c04d0930ba9ed1e7256776612e04991cc134b804",
+		},
+		{
+			name:     "longest password with owner data",
+			password: strings.Repeat("p", MaxPasswordLength),
+			salt:     "fedcba9876543210",
+			udata:    bytes.Repeat([]byte{0xa5}, 48),
+			want:     "822f3c76da2dc9bc079c2e3dd719c55a3092fef569f490b91ff820cebdf3499d",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			salt, _ := hex.DecodeString(tt.salt)
+			if got := hex.EncodeToString(hashR6([]byte(tt.password), salt, tt.udata)); got != tt.want {
+				t.Errorf("hashR6() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestSecurityHandler - Encryption dictionary known answers
+// ---------------------------------------------------------------------------
+
+func TestSecurityHandler(t *testing.T) {
+	t.Parallel()
+
+	random := make([]byte, securityRandomSize)
+	for i := range random {
+		random[i] = byte(i)
+	}
+	key, dict, err := securityHandler([]byte("user"), []byte("owner"), PermPrint, random)
+	if err != nil {
+		t.Fatalf("securityHandler() unexpected error: %v", err)
+	}
+	if !bytes.Equal(key, random[:32]) {
+		t.Errorf("securityHandler() key = %x, want %x", key, random[:32])
+	}
+
+	want := map[Name]string{
+		"U":     "0883bdd9f6387104b4382dc453dea14d56ec345fc7e06b5dc5e22d4cdb744d7f202122232425262728292a2b2c2d2e2f",
+		"UE":    "0aced4b8d236ce53b71feba657b9267d9a27e4ccc510f93c30e3a198b59a9b25",
+		"O":     "641957c838a6af724badd497b43e3b232414ff58c797fd80cb5b3aa706837b6a303132333435363738393a3b3c3d3e3f",
+		"OE":    "e324f0d67ebebc2337de7cce144767b118f16fd0e9f5f64a7a6b5cf657a41a41",
+		"Perms": "b08627fb3dcb6e6a8abcae34e4a11c11",
+	}
+	for name, w := range want {
+		if got := hex.EncodeToString([]byte(dict[name].(String))); got != w {
+			t.Errorf("/%s = %s, want %s", name, got, w)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package pdfedit
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"sync"
+)
+
+// sRGBDescription names the sRGB profile in the ICC data and output intent.
+const sRGBDescription = "sRGB IEC61966-2.1"
+
+// sRGBCurvePoints is the number of samples of the sRGB tone curve.
+const sRGBCurvePoints = 1024
+
+// SRGBProfile returns an ICC v2 display profile for sRGB, built once.
+// The primaries are the Bradford-adapted D50 values of IEC 61966-2-1.
+var SRGBProfile = sync.OnceValue(buildSRGBProfile)
+
+// iccTag is one entry of an ICC tag table.
+type iccTag struct {
+	sig  string
+	data []byte
+}
+
+// buildSRGBProfile assembles the profile header, tag table and tag data.
+func buildSRGBProfile() []byte {
+	trc := iccCurve()
+	tags := []iccTag{
+		{"desc", iccDescription(sRGBDescription)},
+		{"cprt", iccText("No copyright, use freely")},
+		{"wtpt", iccXYZ(0.9642, 1.0, 0.8249)},
+		{"rXYZ", iccXYZ(0.4360747, 0.2225045, 0.0139322)},
+		{"gXYZ", iccXYZ(0.3850649, 0.7168786, 0.0971045)},
+		{"bXYZ", iccXYZ(0.1430804, 0.0606169, 0.7141733)},
+		{"rTRC", trc},
+		{"gTRC", trc},
+		{"bTRC", trc},
+	}
+
+	const headerSize = 128
+	offset := headerSize + 4 + 12*len(tags)
+	var table, data bytes.Buffer
+	_ = binary.Write(&table, binary.BigEndian, uint32(len(tags)))
+	for _, tag := range tags {
+		table.WriteString(tag.sig)
+		_ = binary.Write(&table, binary.BigEndian, []uint32{uint32(offset + data.Len()), uint32(len(tag.data))})
+		data.Write(tag.data)
+		for data.Len()%4 != 0 {
+			data.WriteByte(0)
+		}
+	}
+
+	header := make([]byte, headerSize)
+	binary.BigEndian.PutUint32(header[0:], uint32(offset+data.Len()))
+	binary.BigEndian.PutUint32(header[8:], 0x02100000) // version 2.1
+	copy(header[12:], "mntr")
+	copy(header[16:], "RGB ")
+	copy(header[20:], "XYZ ")
+	for i, v := range []uint16{2025, 1, 1, 0, 0, 0} {
+		binary.BigEndian.PutUint16(header[24+2*i:], v)
+	}
+	copy(header[36:], "acsp")
+	copy(header[68:], iccXYZ(0.9642, 1.0, 0.8249)[8:]) // PCS illuminant D50
+
+	out := make([]byte, 0, offset+data.Len())
+	out = append(out, header...)
+	out = append(out, table.Bytes()...)
+	return append(out, data.Bytes()...)
+}
+
+// iccXYZ encodes an XYZType tag.
+func iccXYZ(x, y, z float64) []byte {
+	b := make([]byte, 20)
+	copy(b, "XYZ ")
+	for i, v := range []float64{x, y, z} {
+		binary.BigEndian.PutUint32(b[8+4*i:], uint32(int32(math.Round(v*65536))))
+	}
+	return b
+}
+
+// iccText encodes a textType tag.
+func iccText(s string) []byte {
+	b := make([]byte, 8, 8+len(s)+1)
+	copy(b, "text")
+	b = append(b, s...)
+	return append(b, 0)
+}
+
+// iccDescription encodes a v2 textDescriptionType tag with ASCII text only.
+func iccDescription(s string) []byte {
+	var b bytes.Buffer
+	b.WriteString("desc\x00\x00\x00\x00")
+	_ = binary.Write(&b, binary.BigEndian, uint32(len(s)+1))
+	b.WriteString(s)
+	b.WriteByte(0)
+	b.Write(make([]byte, 4+4+2+1+67)) // no Unicode or ScriptCode text
+	return b.Bytes()
+}
+
+// iccCurve encodes the sRGB tone curve as a sampled curveType tag.
+func iccCurve() []byte {
+	b := make([]byte, 12+2*sRGBCurvePoints)
+	copy(b, "curv")
+	binary.BigEndian.PutUint32(b[8:], sRGBCurvePoints)
+	for i := range sRGBCurvePoints {
+		v := float64(i) / (sRGBCurvePoints - 1)
+		if v <= 0.04045 {
+			v /= 12.92
+		} else {
+			v = math.Pow((v+0.055)/1.055, 2.4)
+		}
+		binary.BigEndian.PutUint16(b[12+2*i:], uint16(math.Round(v*65535)))
+	}
+	return b
+}
@@ -0,0 +1,174 @@
+package pdfedit
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Metadata describes the document in the Info dictionary and XMP packet.
+// Empty fields are left out.
+type Metadata struct {
+	Title    string
+	Author   string
+	Subject  string
+	Keywords []string
+	Creator  string    // Application that created the source document
+	Producer string    // Application that produced the PDF
+	ModDate  time.Time // Modification time; zero keeps any existing value
+	PDFA     bool      // Declare PDF/A-2b conformance in the XMP packet
+}
+
+// SetMetadata writes m into the Info dictionary and a matching XMP
+// /Metadata stream on the catalog. Existing Info entries that m does not
+// set, such as /CreationDate, are kept.
+func SetMetadata(u *Update, m Metadata) error {
+	info := Dict{}
+	infoRef, hasInfo := Ref{}, false
+	if old, ref, ok, err := u.doc.Info(); err != nil {
+		return err
+	} else if ok {
+		info, infoRef, hasInfo = old.Clone(), ref, true
+	}
+
+	setText := func(key Name, value string) {
+		if value != "" {
+			info[key] = TextString(value)
+		}
+	}
+	setText("Title", m.Title)
+	setText("Author", m.Author)
+	setText("Subject", m.Subject)
+	setText("Keywords", strings.Join(m.Keywords, ", "))
+	setText("Creator", m.Creator)
+	setText("Producer", m.Producer)
+	if !m.ModDate.IsZero() {
+		info["ModDate"] = String(FormatDate(m.ModDate))
+		if _, ok := info["CreationDate"]; !ok {
+			info["CreationDate"] = String(FormatDate(m.ModDate))
+		}
+	}
+
+	if hasInfo {
+		u.Set(infoRef, info)
+	} else {
+		u.SetTrailer("Info", u.Add(info))
+	}
+
+	catalog, catalogRef, err := u.Catalog()
+	if err != nil {
+		return err
+	}
+	catalog["Metadata"] = u.Add(&Stream{
+		Dict: Dict{"Type": Name("Metadata"), "Subtype": Name("XML")},
+		Data: []byte(buildXMP(info, m.PDFA)),
+	})
+	u.Set(catalogRef, catalog)
+	return nil
+}
+
+// buildXMP renders an XMP packet mirroring the Info dictionary, so both
+// stay consistent as PDF/A requires. pdfa adds the PDF/A-2b identification.
+func buildXMP(info Dict, pdfa bool) string {
+	text := func(key Name) string {
+		s, _ := info[key].(String)
+		return xmlEscape(s.Text())
+	}
+	date := func(key Name) string {
+		s, _ := info[key].(String)
+		t, err := ParseDate(string(s))
+		if err != nil {
+			return ""
+		}
+		return t.Format(time.RFC3339)
+	}
+
+	var b strings.Builder
+	b.WriteString("<?xpacket begin=\"\ufeff\" id=\"W5M0MpCehiHzreSzNTczkc9d\"?>\n")
+	b.WriteString(`<x:xmpmeta xmlns:x="adobe:ns:meta/">` + "\n")
+	b.WriteString(`<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">` + "\n")
+	b.WriteString(`<rdf:Description rdf:about=""` +
+		` xmlns:dc="http://purl.org/dc/elements/1.1/"` +
+		` xmlns:xmp="http://ns.adobe.com/xap/1.0/"` +
+		` xmlns:pdf="http://ns.adobe.com/pdf/1.3/"` +
+		` xmlns:pdfaid="http://www.aiim.org/pdfa/ns/id/">` + "\n")
+	b.WriteString("<dc:format>application/pdf</dc:format>\n")
+	if pdfa {
+		b.WriteString("<pdfaid:part>2</pdfaid:part>\n<pdfaid:conformance>B</pdfaid:conformance>\n")
+	}
+
+	if v := text("Title"); v != "" {
+		fmt.Fprintf(&b, "<dc:title><rdf:Alt><rdf:li xml:lang=\"x-default\">%s</rdf:li></rdf:Alt></dc:title>\n", v)
+	}
+	if v := text("Author"); v != "" {
+		fmt.Fprintf(&b, "<dc:creator><rdf:Seq><rdf:li>%s</rdf:li></rdf:Seq></dc:creator>\n", v)
+	}
+	if v := text("Subject"); v != "" {
+		fmt.Fprintf(&b, "<dc:description><rdf:Alt><rdf:li xml:lang=\"x-default\">%s</rdf:li></rdf:Alt></dc:description>\n", v)
+	}
+	if v := text("Keywords"); v != "" {
+		fmt.Fprintf(&b, "<pdf:Keywords>%s</pdf:Keywords>\n", v)
+	}
+	if v := text("Producer"); v != "" {
+		fmt.Fprintf(&b, "<pdf:Producer>%s</pdf:Producer>\n", v)
+	}
+	if v := text("Creator"); v != "" {
+		fmt.Fprintf(&b, "<xmp:CreatorTool>%s</xmp:CreatorTool>\n", v)
+	}
+	if v := date("CreationDate"); v != "" {
+		fmt.Fprintf(&b, "<xmp:CreateDate>%s</xmp:CreateDate>\n", v)
+	}
+	if v := date("ModDate"); v != "" {
+		fmt.Fprintf(&b, "<xmp:ModifyDate>%s</xmp:ModifyDate>\n", v)
+		fmt.Fprintf(&b, "<xmp:MetadataDate>%s</xmp:MetadataDate>\n", v)
+	}
+
+	b.WriteString("</rdf:Description>\n</rdf:RDF>\n</x:xmpmeta>\n")
+	b.WriteString(`<?xpacket end="w"?>`)
+	return b.String()
+}
+
+// xmlEscape escapes text for XML element content.
+func xmlEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// FormatDate formats t as a PDF date string ("D:20250115120000+01'00'").
+func FormatDate(t time.Time) string {
+	_, offset := t.Zone()
+	sign := '+'
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+	return fmt.Sprintf("D:%s%c%02d'%02d'", t.Format("20060102150405"), sign, offset/3600, offset%3600/60)
+}
+
+// ParseDate parses a PDF date string. Missing trailing fields default to
+// their minimum, and a missing time zone means UTC.
+func ParseDate(s string) (time.Time, error) {
+	s = strings.TrimPrefix(s, "D:")
+	digits := 0
+	for digits < len(s) && digits < 14 && s[digits] >= '0' && s[digits] <= '9' {
+		digits++
+	}
+	if digits < 4 || digits%2 != 0 {
+		return time.Time{}, fmt.Errorf("%w: invalid date %q", ErrMalformed, s)
+	}
+	stamp := s[:digits] + "0101000000"[digits-4:]
+	tz := strings.ReplaceAll(s[digits:], "'", "")
+
+	loc := time.UTC
+	if len(tz) >= 3 && (tz[0] == '+' || tz[0] == '-') {
+		var h, m int
+		if _, err := fmt.Sscanf(tz[1:], "%02d%02d", &h, &m); err != nil {
+			m = 0
+		}
+		offset := h*3600 + m*60
+		if tz[0] == '-' {
+			offset = -offset
+		}
+		loc = time.FixedZone("", offset)
+	}
+	return time.ParseInLocation("20060102150405", stamp, loc)
+}
@@ -0,0 +1,133 @@
+package pdfedit
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// TestSetMetadata - Info dictionary and XMP packet
+// ---------------------------------------------------------------------------
+
+func TestSetMetadata(t *testing.T) {
+	t.Parallel()
+
+	doc, err := Open(skiaPDF())
+	if err != nil {
+		t.Fatalf("Open() unexpected error: %v", err)
+	}
+	u := doc.NewUpdate()
+	err = SetMetadata(u, Metadata{
+		Title:    "Rapport annuel",
+		Author:   "Zoë & Co",
+		Subject:  "Summary",
+		Keywords: []string{"finance", "2025"},
+		Creator:  "picoloom",
+		Producer: "picoloom",
+		ModDate:  time.Date(2025, 2, 1, 10, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("SetMetadata() unexpected error: %v", err)
+	}
+
+	updated, err := Open(u.Bytes())
+	if err != nil {
+		t.Fatalf("Open(updated) unexpected error: %v", err)
+	}
+
+	info, _, ok, err := updated.Info()
+	if err != nil || !ok {
+		t.Fatalf("Info() = ok %v, err %v, want info", ok, err)
+	}
+	wantInfo := map[Name]string{
+		"Title":        "Rapport annuel",
+		"Author":       "Zoë & Co",
+		"Keywords":     "finance, 2025",
+		"Producer":     "picoloom",
+		"CreationDate": "D:20250115120000+00'00'",
+		"ModDate":      "D:20250201100000+00'00'",
+	}
+	for key, want := range wantInfo {
+		if got := info[key].(String).Text(); got != want {
+			t.Errorf("Info[%s] = %q, want %q", key, got, want)
+		}
+	}
+
+	catalog, _, err := updated.Catalog()
+	if err != nil {
+		t.Fatalf("Catalog() unexpected error: %v", err)
+	}
+	obj, err := updated.Resolve(catalog["Metadata"])
+	if err != nil {
+		t.Fatalf("resolving /Metadata: %v", err)
+	}
+	stream, ok := obj.(*Stream)
+	if !ok {
+		t.Fatalf("/Metadata = %T, want stream", obj)
+	}
+	xmp := string(stream.Data)
+	for _, want := range []string{
+		`<rdf:li xml:lang="x-default">Rapport annuel</rdf:li>`,
+		"<rdf:li>Zoë &amp; Co</rdf:li>",
+		"<pdf:Keywords>finance, 2025</pdf:Keywords>",
+		"<xmp:CreateDate>2025-01-15T12:00:00Z</xmp:CreateDate>",
+		"<xmp:ModifyDate>2025-02-01T10:00:00Z</xmp:ModifyDate>",
+	} {
+		if !strings.Contains(xmp, want) {
+			t.Errorf("XMP missing %q in:\n%s", want, xmp)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestDates - PDF date formatting and parsing
+// ---------------------------------------------------------------------------
+
+func TestDates(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		in   string
+		want time.Time
+	}{
+		{"full with offset", "D:20250115120000+01'00'", time.Date(2025, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{"zulu", "D:20250115120000Z", time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)},
+		{"year only", "D:2025", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseDate(tt.in)
+			if err != nil {
+				t.Fatalf("ParseDate(%q) unexpected error: %v", tt.in, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseDate(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+			if back, err := ParseDate(FormatDate(got)); err != nil || !back.Equal(got) {
+				t.Errorf("ParseDate(FormatDate(%v)) = %v, %v, want round trip", got, back, err)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestTextString - Text string encoding
+// ---------------------------------------------------------------------------
+
+func TestTextString(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []string{"Plain", "Café", "日本語"} {
+		if got := TextString(s).Text(); got != s {
+			t.Errorf("TextString(%q).Text() = %q, want round trip", s, got)
+		}
+	}
+	if got := TextString("Plain"); got != String("Plain") {
+		t.Errorf("TextString(ASCII) = %q, want plain bytes", got)
+	}
+}
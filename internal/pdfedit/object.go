@@ -0,0 +1,211 @@
+package pdfedit
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"unicode/utf16"
+)
+
+// Object is a PDF object: nil, bool, int64, float64, Name, String, Array,
+// Dict, Ref or *Stream.
+type Object any
+
+// Name is a PDF name, stored without the leading slash.
+type Name string
+
+// String is a PDF string, stored as raw bytes.
+type String string
+
+// Array is a PDF array.
+type Array []Object
+
+// Dict is a PDF dictionary.
+type Dict map[Name]Object
+
+// Ref is an indirect object reference ("12 0 R").
+type Ref struct {
+	Num int
+	Gen int
+}
+
+// Stream is a PDF stream. Data is stored as it appears in the file.
+// /Length is written from len(Data) and need not be set.
+type Stream struct {
+	Dict Dict
+	Data []byte
+}
+
+// TextString encodes s as a PDF text string: plain bytes for printable
+// ASCII, UTF-16BE with a byte order mark otherwise.
+func TextString(s string) String {
+	ascii := true
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x20 || s[i] > 0x7e {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return String(s)
+	}
+
+	units := utf16.Encode([]rune(s))
+	buf := make([]byte, 2, 2+2*len(units))
+	buf[0], buf[1] = 0xfe, 0xff
+	for _, u := range units {
+		buf = append(buf, byte(u>>8), byte(u))
+	}
+	return String(buf)
+}
+
+// Text decodes a PDF text string (UTF-16BE with BOM, or PDFDocEncoding
+// treated as Latin-1).
+func (s String) Text() string {
+	b := []byte(s)
+	if len(b) >= 2 && b[0] == 0xfe && b[1] == 0xff {
+		units := make([]uint16, 0, (len(b)-2)/2)
+		for i := 2; i+1 < len(b); i += 2 {
+			units = append(units, uint16(b[i])<<8|uint16(b[i+1]))
+		}
+		return string(utf16.Decode(units))
+	}
+	runes := make([]rune, len(b))
+	for i, c := range b {
+		runes[i] = rune(c)
+	}
+	return string(runes)
+}
+
+// Clone returns a shallow copy of d.
+func (d Dict) Clone() Dict {
+	out := make(Dict, len(d)+1)
+	for k, v := range d {
+		out[k] = v
+	}
+	return out
+}
+
+// Ref returns the reference stored at key, if any.
+func (d Dict) Ref(key Name) (Ref, bool) {
+	r, ok := d[key].(Ref)
+	return r, ok
+}
+
+// Int returns the integer stored at key, if any.
+func (d Dict) Int(key Name) (int64, bool) {
+	n, ok := d[key].(int64)
+	return n, ok
+}
+
+// writeObject serializes obj in PDF syntax.
+func writeObject(buf *bytes.Buffer, obj Object) {
+	switch v := obj.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case int:
+		buf.WriteString(strconv.Itoa(v))
+	case int64:
+		buf.WriteString(strconv.FormatInt(v, 10))
+	case float64:
+		buf.WriteString(strconv.FormatFloat(v, 'f', -1, 64))
+	case Name:
+		writeName(buf, v)
+	case String:
+		writeString(buf, v)
+	case Ref:
+		fmt.Fprintf(buf, "%d %d R", v.Num, v.Gen)
+	case Array:
+		buf.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteByte(' ')
+			}
+			writeObject(buf, item)
+		}
+		buf.WriteByte(']')
+	case Dict:
+		writeDict(buf, v)
+	case *Stream:
+		d := v.Dict.Clone()
+		d["Length"] = int64(len(v.Data))
+		writeDict(buf, d)
+		buf.WriteString("\nstream\n")
+		buf.Write(v.Data)
+		buf.WriteString("\nendstream")
+	case rawObject:
+		buf.Write(v)
+	default:
+		panic(fmt.Sprintf("pdfedit: unsupported object type %T", obj))
+	}
+}
+
+// writeDict writes keys in sorted order so output is deterministic.
+func writeDict(buf *bytes.Buffer, d Dict) {
+	keys := make([]string, 0, len(d))
+	for k := range d {
+		keys = append(keys, string(k))
+	}
+	sort.Strings(keys)
+
+	buf.WriteString("<<")
+	for _, k := range keys {
+		writeName(buf, Name(k))
+		buf.WriteByte(' ')
+		writeObject(buf, d[Name(k)])
+	}
+	buf.WriteString(">>")
+}
+
+// writeName escapes delimiters, whitespace and non-printable bytes as #xx.
+func writeName(buf *bytes.Buffer, n Name) {
+	buf.WriteByte('/')
+	for i := 0; i < len(n); i++ {
+		c := n[i]
+		if c < 0x21 || c > 0x7e || c == '#' || isDelimiter(c) {
+			fmt.Fprintf(buf, "#%02X", c)
+			continue
+		}
+		buf.WriteByte(c)
+	}
+}
+
+// writeString writes a literal string, escaping parentheses, backslashes
+// and non-printable bytes.
+func writeString(buf *bytes.Buffer, s String) {
+	buf.WriteByte('(')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '(' || c == ')' || c == '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(c)
+		case c < 0x20 || c > 0x7e:
+			fmt.Fprintf(buf, "\\%03o", c)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	buf.WriteByte(')')
+}
+
+// isDelimiter reports whether c is a PDF delimiter character.
+func isDelimiter(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+// isWhitespace reports whether c is a PDF whitespace character.
+func isWhitespace(c byte) bool {
+	switch c {
+	case 0, '\t', '\n', '\f', '\r', ' ':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,254 @@
+package pdfedit
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxOutlineItems bounds outline traversal to reject cyclic input.
+const maxOutlineItems = 100000
+
+// OutlineEntry is one bookmark of a document outline.
+type OutlineEntry struct {
+	Title string
+	Level int    // Entries nest under the previous entry with a lower level
+	Dest  Object // Destination: explicit array, name, or string
+}
+
+// NamedDestinations returns the destinations declared in the catalog /Dests
+// dictionary and the /Names /Dests name tree, keyed by name.
+func (d *Document) NamedDestinations() (map[string]Object, error) {
+	catalog, _, err := d.Catalog()
+	if err != nil {
+		return nil, err
+	}
+
+	dests := make(map[string]Object)
+	if obj, ok := catalog["Dests"]; ok {
+		dict, err := d.ResolveDict(obj)
+		if err != nil {
+			return nil, fmt.Errorf("reading /Dests: %w", err)
+		}
+		for name, value := range dict {
+			if dest, err := d.destination(value); err == nil {
+				dests[string(name)] = dest
+			}
+		}
+	}
+
+	if obj, ok := catalog["Names"]; ok {
+		names, err := d.ResolveDict(obj)
+		if err != nil {
+			return nil, fmt.Errorf("reading /Names: %w", err)
+		}
+		if tree, ok := names["Dests"]; ok {
+			if err := d.walkNameTree(tree, dests, 0, make(map[Ref]bool)); err != nil {
+				return nil, fmt.Errorf("reading /Names /Dests: %w", err)
+			}
+		}
+	}
+	return dests, nil
+}
+
+// walkNameTree collects the leaf entries of a name tree into out.
+func (d *Document) walkNameTree(obj Object, out map[string]Object, depth int, seen map[Ref]bool) error {
+	if depth > maxNesting {
+		return fmt.Errorf("%w: name tree too deep", ErrMalformed)
+	}
+	if ref, ok := obj.(Ref); ok {
+		if seen[ref] {
+			return fmt.Errorf("%w: cyclic name tree", ErrMalformed)
+		}
+		seen[ref] = true
+	}
+	node, err := d.ResolveDict(obj)
+	if err != nil {
+		return err
+	}
+
+	if kids, ok := node["Kids"].(Array); ok {
+		for _, kid := range kids {
+			if err := d.walkNameTree(kid, out, depth+1, seen); err != nil {
+				return err
+			}
+		}
+	}
+	pairs, err := d.Resolve(node["Names"])
+	if err != nil {
+		return err
+	}
+	arr, _ := pairs.(Array)
+	for i := 0; i+1 < len(arr); i += 2 {
+		key, ok := arr[i].(String)
+		if !ok {
+			continue
+		}
+		if dest, err := d.destination(arr[i+1]); err == nil {
+			out[string(key)] = dest
+		}
+	}
+	return nil
+}
+
+// destination resolves a named destination value, which is either the
+// destination itself or a dictionary holding it under /D.
+func (d *Document) destination(obj Object) (Object, error) {
+	resolved, err := d.Resolve(obj)
+	if err != nil {
+		return nil, err
+	}
+	if dict, ok := resolved.(Dict); ok {
+		return d.Resolve(dict["D"])
+	}
+	return resolved, nil
+}
+
+// OutlineItems returns the items of the existing outline in document order.
+// Level is the item's depth in the outline tree, starting at 1.
+// Returns nil if the document has no outline.
+func (d *Document) OutlineItems() ([]OutlineEntry, error) {
+	catalog, _, err := d.Catalog()
+	if err != nil {
+		return nil, err
+	}
+	obj, ok := catalog["Outlines"]
+	if !ok {
+		return nil, nil
+	}
+	root, err := d.ResolveDict(obj)
+	if err != nil {
+		return nil, fmt.Errorf("reading /Outlines: %w", err)
+	}
+
+	var items []OutlineEntry
+	seen := make(map[Ref]bool)
+	var walk func(first Object, level int) error
+	walk = func(first Object, level int) error {
+		if level > maxNesting {
+			return fmt.Errorf("%w: outline too deep", ErrMalformed)
+		}
+		for next := first; next != nil; {
+			ref, ok := next.(Ref)
+			if !ok || seen[ref] || len(seen) >= maxOutlineItems {
+				return fmt.Errorf("%w: invalid outline item link", ErrMalformed)
+			}
+			seen[ref] = true
+
+			item, err := d.ResolveDict(ref)
+			if err != nil {
+				return err
+			}
+			title, _ := item["Title"].(String)
+			items = append(items, OutlineEntry{
+				Title: title.Text(),
+				Level: level,
+				Dest:  d.itemDestination(item),
+			})
+			if err := walk(item["First"], level+1); err != nil {
+				return err
+			}
+			next = item["Next"]
+		}
+		return nil
+	}
+	if err := walk(root["First"], 1); err != nil {
+		return nil, fmt.Errorf("reading outline: %w", err)
+	}
+	return items, nil
+}
+
+// itemDestination returns an outline item's /Dest, or the /D of its GoTo
+// action. Returns nil if the item has neither.
+func (d *Document) itemDestination(item Dict) Object {
+	if dest, ok := item["Dest"]; ok {
+		resolved, _ := d.Resolve(dest)
+		return resolved
+	}
+	action, err := d.ResolveDict(item["A"])
+	if err != nil || action["S"] != Name("GoTo") {
+		return nil
+	}
+	dest, _ := d.Resolve(action["D"])
+	return dest
+}
+
+// outlineNode is an entry placed in the outline tree.
+type outlineNode struct {
+	entry    OutlineEntry
+	ref      Ref
+	children []*outlineNode
+}
+
+// SetOutline replaces the document outline with entries, nested by Level,
+// and asks viewers to show it when the document opens. Top-level items
+// start expanded and deeper items collapsed.
+// Does nothing if entries is empty.
+func SetOutline(u *Update, entries []OutlineEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	root := &outlineNode{ref: u.Reserve()}
+	stack := []*outlineNode{root}
+	for _, e := range entries {
+		for len(stack) > 1 && stack[len(stack)-1].entry.Level >= e.Level {
+			stack = stack[:len(stack)-1]
+		}
+		node := &outlineNode{entry: e, ref: u.Reserve()}
+		parent := stack[len(stack)-1]
+		parent.children = append(parent.children, node)
+		stack = append(stack, node)
+	}
+
+	visible := len(root.children)
+	for _, top := range root.children {
+		visible += len(top.children)
+	}
+	u.Set(root.ref, Dict{
+		"Type":  Name("Outlines"),
+		"First": root.children[0].ref,
+		"Last":  root.children[len(root.children)-1].ref,
+		"Count": int64(visible),
+	})
+	writeOutlineChildren(u, root, true)
+
+	catalog, catalogRef, err := u.Catalog()
+	if err != nil {
+		return err
+	}
+	catalog["Outlines"] = root.ref
+	catalog["PageMode"] = Name("UseOutlines")
+	u.Set(catalogRef, catalog)
+	return nil
+}
+
+// writeOutlineChildren writes the items under parent, linking siblings.
+// open expands the items' own children.
+func writeOutlineChildren(u *Update, parent *outlineNode, open bool) {
+	for i, node := range parent.children {
+		item := Dict{
+			"Title":  TextString(strings.TrimSpace(node.entry.Title)),
+			"Parent": parent.ref,
+		}
+		if node.entry.Dest != nil {
+			item["Dest"] = node.entry.Dest
+		}
+		if i > 0 {
+			item["Prev"] = parent.children[i-1].ref
+		}
+		if i+1 < len(parent.children) {
+			item["Next"] = parent.children[i+1].ref
+		}
+		if n := len(node.children); n > 0 {
+			item["First"] = node.children[0].ref
+			item["Last"] = node.children[n-1].ref
+			if open {
+				item["Count"] = int64(n)
+			} else {
+				item["Count"] = int64(-n)
+			}
+			writeOutlineChildren(u, node, false)
+		}
+		u.Set(node.ref, item)
+	}
+}
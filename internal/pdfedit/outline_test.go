@@ -0,0 +1,182 @@
+package pdfedit
+
+import (
+	"fmt"
+	"testing"
+)
+
+// outlinePDF returns a two-page PDF with named destinations in both the
+// catalog /Dests dictionary and a /Names name tree, and a Chrome-style
+// outline of two nested items.
+func outlinePDF() []byte {
+	return buildPDF("",
+		"<</Type /Catalog /Pages 2 0 R /Dests 5 0 R /Names <</Dests 6 0 R>> /Outlines 8 0 R>>",
+		"<</Type /Pages /Count 2 /Kids [3 0 R 4 0 R]>>",
+		"<</Type /Page /Parent 2 0 R /MediaBox [0 0 612 792]>>",
+		"<</Type /Page /Parent 2 0 R /MediaBox [0 0 612 792]>>",
+		"<</intro [3 0 R /XYZ 0 792 0]>>",
+		"<</Kids [7 0 R]>>",
+		"<</Names [(usage) <</D [4 0 R /XYZ 0 500 0]>>]>>",
+		"<</Type /Outlines /First 9 0 R /Last 9 0 R /Count 2>>",
+		"<</Title (Intro) /Parent 8 0 R /First 10 0 R /Last 10 0 R /Dest [3 0 R /XYZ 0 792 0]>>",
+		"<</Title (Usage) /Parent 9 0 R /A <</S /GoTo /D [4 0 R /XYZ 0 500 0]>>>>",
+	)
+}
+
+// ---------------------------------------------------------------------------
+// TestNamedDestinations - /Dests dictionary and name tree
+// ---------------------------------------------------------------------------
+
+func TestNamedDestinations(t *testing.T) {
+	t.Parallel()
+
+	doc, err := Open(outlinePDF())
+	if err != nil {
+		t.Fatalf("Open() unexpected error: %v", err)
+	}
+	dests, err := doc.NamedDestinations()
+	if err != nil {
+		t.Fatalf("NamedDestinations() unexpected error: %v", err)
+	}
+
+	want := map[string]string{
+		"intro": fmt.Sprint(Array{Ref{Num: 3}, Name("XYZ"), int64(0), int64(792), int64(0)}),
+		"usage": fmt.Sprint(Array{Ref{Num: 4}, Name("XYZ"), int64(0), int64(500), int64(0)}),
+	}
+	if len(dests) != len(want) {
+		t.Fatalf("NamedDestinations() = %v, want %d entries", dests, len(want))
+	}
+	for name, dest := range want {
+		if got := fmt.Sprint(dests[name]); got != dest {
+			t.Errorf("NamedDestinations()[%q] = %s, want %s", name, got, dest)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestOutline - Reading and replacing the outline
+// ---------------------------------------------------------------------------
+
+func TestOutline(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reads items with levels and destinations", func(t *testing.T) {
+		t.Parallel()
+
+		doc, err := Open(outlinePDF())
+		if err != nil {
+			t.Fatalf("Open() unexpected error: %v", err)
+		}
+		items, err := doc.OutlineItems()
+		if err != nil {
+			t.Fatalf("OutlineItems() unexpected error: %v", err)
+		}
+		if len(items) != 2 {
+			t.Fatalf("OutlineItems() = %v, want 2 items", items)
+		}
+		if items[0].Title != "Intro" || items[0].Level != 1 || items[1].Title != "Usage" || items[1].Level != 2 {
+			t.Errorf("OutlineItems() = %+v, want Intro (1) then Usage (2)", items)
+		}
+		if dest, ok := items[1].Dest.(Array); !ok || dest[0] != (Ref{Num: 4}) {
+			t.Errorf("OutlineItems()[1].Dest = %v, want GoTo destination on page 4 0 R", items[1].Dest)
+		}
+	})
+
+	t.Run("document without outline", func(t *testing.T) {
+		t.Parallel()
+
+		doc, err := Open(skiaPDF())
+		if err != nil {
+			t.Fatalf("Open() unexpected error: %v", err)
+		}
+		if items, err := doc.OutlineItems(); err != nil || items != nil {
+			t.Errorf("OutlineItems() = %v, %v, want nil, nil", items, err)
+		}
+	})
+
+	t.Run("cyclic outline returns ErrMalformed", func(t *testing.T) {
+		t.Parallel()
+
+		doc, err := Open(buildPDF("",
+			"<</Type /Catalog /Pages 2 0 R /Outlines 3 0 R>>",
+			"<</Type /Pages /Count 0 /Kids []>>",
+			"<</Type /Outlines /First 4 0 R>>",
+			"<</Title (Loop) /Next 4 0 R>>",
+		))
+		if err != nil {
+			t.Fatalf("Open() unexpected error: %v", err)
+		}
+		if _, err := doc.OutlineItems(); err == nil {
+			t.Error("OutlineItems() error = nil, want ErrMalformed")
+		}
+	})
+
+	t.Run("writes nested outline", func(t *testing.T) {
+		t.Parallel()
+
+		doc, err := Open(skiaPDF())
+		if err != nil {
+			t.Fatalf("Open() unexpected error: %v", err)
+		}
+		page := Array{Ref{Num: 3}, Name("Fit")}
+		u := doc.NewUpdate()
+		err = SetOutline(u, []OutlineEntry{
+			{Title: "Intro", Level: 1, Dest: page},
+			{Title: "Setup", Level: 2, Dest: page},
+			{Title: "Deep", Level: 4, Dest: page},
+			{Title: "Usage", Level: 2, Dest: page},
+			{Title: "Appendix", Level: 1, Dest: page},
+		})
+		if err != nil {
+			t.Fatalf("SetOutline() unexpected error: %v", err)
+		}
+
+		updated, err := Open(u.Bytes())
+		if err != nil {
+			t.Fatalf("Open(updated) unexpected error: %v", err)
+		}
+		catalog, _, err := updated.Catalog()
+		if err != nil {
+			t.Fatalf("Catalog() unexpected error: %v", err)
+		}
+		if catalog["PageMode"] != Name("UseOutlines") {
+			t.Errorf("catalog /PageMode = %v, want /UseOutlines", catalog["PageMode"])
+		}
+		root, err := updated.ResolveDict(catalog["Outlines"])
+		if err != nil {
+			t.Fatalf("ResolveDict(/Outlines) unexpected error: %v", err)
+		}
+		if root["Count"] != int64(4) {
+			t.Errorf("outline root /Count = %v, want 4 (two top-level items, two open children)", root["Count"])
+		}
+
+		items, err := updated.OutlineItems()
+		if err != nil {
+			t.Fatalf("OutlineItems() unexpected error: %v", err)
+		}
+		var got []string
+		for _, item := range items {
+			got = append(got, fmt.Sprintf("%d:%s", item.Level, item.Title))
+		}
+		want := "[1:Intro 2:Setup 3:Deep 2:Usage 1:Appendix]"
+		if fmt.Sprint(got) != want {
+			t.Errorf("OutlineItems() = %v, want %s", got, want)
+		}
+	})
+
+	t.Run("empty entries leave the document unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		doc, err := Open(skiaPDF())
+		if err != nil {
+			t.Fatalf("Open() unexpected error: %v", err)
+		}
+		u := doc.NewUpdate()
+		if err := SetOutline(u, nil); err != nil {
+			t.Fatalf("SetOutline(nil) unexpected error: %v", err)
+		}
+		if len(u.Bytes()) != len(skiaPDF()) {
+			t.Error("SetOutline(nil) modified the document")
+		}
+	})
+}
@@ -0,0 +1,64 @@
+package pdfedit
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Page label numbering styles (PDF 32000-1, 12.4.2).
+const (
+	LabelNone       = ""  // Prefix only, no number
+	LabelDecimal    = "D" // 1, 2, 3
+	LabelRomanUpper = "R" // I, II, III
+	LabelRomanLower = "r" // i, ii, iii
+	LabelAlphaUpper = "A" // A, B, C
+	LabelAlphaLower = "a" // a, b, c
+)
+
+// PageLabelRange labels the pages from FirstPage up to the next range.
+type PageLabelRange struct {
+	FirstPage int    // 0-based index of the first page in the range
+	Style     string // Numbering style, one of the Label constants
+	Prefix    string // Text before the number
+	Start     int    // Number of the first page (default: 1)
+}
+
+// SetPageLabels sets the catalog /PageLabels, the page numbers viewers
+// display instead of physical page indexes. The first range must start at
+// page 0.
+func SetPageLabels(u *Update, ranges []PageLabelRange) error {
+	if len(ranges) == 0 {
+		return nil
+	}
+	sorted := append([]PageLabelRange(nil), ranges...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].FirstPage < sorted[j].FirstPage })
+	if sorted[0].FirstPage != 0 {
+		return fmt.Errorf("%w: first page label range starts at page %d", ErrUnsupported, sorted[0].FirstPage)
+	}
+
+	nums := make(Array, 0, 2*len(sorted))
+	for i, r := range sorted {
+		if i > 0 && r.FirstPage == sorted[i-1].FirstPage {
+			return fmt.Errorf("%w: two page label ranges start at page %d", ErrUnsupported, r.FirstPage)
+		}
+		label := Dict{}
+		if r.Style != LabelNone {
+			label["S"] = Name(r.Style)
+		}
+		if r.Prefix != "" {
+			label["P"] = TextString(r.Prefix)
+		}
+		if r.Start > 1 {
+			label["St"] = int64(r.Start)
+		}
+		nums = append(nums, int64(r.FirstPage), label)
+	}
+
+	catalog, catalogRef, err := u.Catalog()
+	if err != nil {
+		return err
+	}
+	catalog["PageLabels"] = Dict{"Nums": nums}
+	u.Set(catalogRef, catalog)
+	return nil
+}
@@ -0,0 +1,80 @@
+package pdfedit
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// TestSetPageLabels - Catalog /PageLabels number tree
+// ---------------------------------------------------------------------------
+
+func TestSetPageLabels(t *testing.T) {
+	t.Parallel()
+
+	t.Run("writes ranges in page order", func(t *testing.T) {
+		t.Parallel()
+
+		doc, err := Open(skiaPDF())
+		if err != nil {
+			t.Fatalf("Open() unexpected error: %v", err)
+		}
+		u := doc.NewUpdate()
+		err = SetPageLabels(u, []PageLabelRange{
+			{FirstPage: 2, Style: LabelDecimal, Start: 5},
+			{FirstPage: 0, Style: LabelRomanLower},
+			{FirstPage: 1, Style: LabelNone, Prefix: "Cover"},
+		})
+		if err != nil {
+			t.Fatalf("SetPageLabels() unexpected error: %v", err)
+		}
+
+		out, err := Open(u.Bytes())
+		if err != nil {
+			t.Fatalf("Open(updated) unexpected error: %v", err)
+		}
+		catalog, _, err := out.Catalog()
+		if err != nil {
+			t.Fatalf("Catalog() unexpected error: %v", err)
+		}
+		labels, _ := catalog["PageLabels"].(Dict)
+		want := Array{
+			int64(0), Dict{"S": Name("r")},
+			int64(1), Dict{"P": String("Cover")},
+			int64(2), Dict{"S": Name("D"), "St": int64(5)},
+		}
+		if got := fmt.Sprint(labels["Nums"]); got != fmt.Sprint(want) {
+			t.Errorf("/PageLabels /Nums = %s, want %s", got, fmt.Sprint(want))
+		}
+	})
+
+	t.Run("first range must start at page 0", func(t *testing.T) {
+		t.Parallel()
+
+		doc, err := Open(skiaPDF())
+		if err != nil {
+			t.Fatalf("Open() unexpected error: %v", err)
+		}
+		err = SetPageLabels(doc.NewUpdate(), []PageLabelRange{{FirstPage: 1, Style: LabelDecimal}})
+		if !errors.Is(err, ErrUnsupported) {
+			t.Errorf("SetPageLabels() error = %v, want %v", err, ErrUnsupported)
+		}
+	})
+
+	t.Run("no ranges leaves the catalog unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		doc, err := Open(skiaPDF())
+		if err != nil {
+			t.Fatalf("Open() unexpected error: %v", err)
+		}
+		u := doc.NewUpdate()
+		if err := SetPageLabels(u, nil); err != nil {
+			t.Fatalf("SetPageLabels() unexpected error: %v", err)
+		}
+		if out := u.Bytes(); len(out) != len(skiaPDF()) {
+			t.Errorf("SetPageLabels(nil) wrote an update")
+		}
+	})
+}
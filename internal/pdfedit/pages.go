@@ -0,0 +1,141 @@
+package pdfedit
+
+import (
+	"fmt"
+	"math"
+)
+
+// maxPages bounds page tree traversal to reject hostile input.
+const maxPages = 1000000
+
+// Pages returns the page objects in document order.
+func (d *Document) Pages() ([]Ref, error) {
+	catalog, _, err := d.Catalog()
+	if err != nil {
+		return nil, err
+	}
+	root, ok := catalog["Pages"].(Ref)
+	if !ok {
+		return nil, fmt.Errorf("%w: catalog missing /Pages", ErrMalformed)
+	}
+
+	var pages []Ref
+	if err := d.walkPages(root, &pages, 0, make(map[Ref]bool)); err != nil {
+		return nil, fmt.Errorf("reading page tree: %w", err)
+	}
+	return pages, nil
+}
+
+// walkPages appends the leaves of the page tree node ref to out.
+func (d *Document) walkPages(ref Ref, out *[]Ref, depth int, seen map[Ref]bool) error {
+	if depth > maxNesting {
+		return fmt.Errorf("%w: page tree too deep", ErrMalformed)
+	}
+	if seen[ref] || len(*out) >= maxPages {
+		return fmt.Errorf("%w: cyclic page tree", ErrMalformed)
+	}
+	seen[ref] = true
+
+	node, err := d.ResolveDict(ref)
+	if err != nil {
+		return err
+	}
+	kids, isTree := node["Kids"]
+	if !isTree || node["Type"] == Name("Page") {
+		*out = append(*out, ref)
+		return nil
+	}
+	arr, err := d.Resolve(kids)
+	if err != nil {
+		return err
+	}
+	list, _ := arr.(Array)
+	for _, kid := range list {
+		kidRef, ok := kid.(Ref)
+		if !ok {
+			return fmt.Errorf("%w: page tree kid is not a reference", ErrMalformed)
+		}
+		if err := d.walkPages(kidRef, out, depth+1, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PageNumber returns the 1-based page a destination points to, or 0 if it
+// does not target one of pages. dest is an explicit destination array.
+func PageNumber(dest Object, pages []Ref) int {
+	arr, ok := dest.(Array)
+	if !ok || len(arr) == 0 {
+		return 0
+	}
+	switch target := arr[0].(type) {
+	case Ref:
+		for i, page := range pages {
+			if page == target {
+				return i + 1
+			}
+		}
+	case int64: // Remote-style page index, used by some producers
+		if target >= 0 && int(target) < len(pages) {
+			return int(target) + 1
+		}
+	}
+	return 0
+}
+
+// DestinationPoint returns the left and top coordinates of an /XYZ
+// destination, in points from the bottom-left corner of its page. ok is false
+// for other destinations and for /XYZ destinations that leave either
+// coordinate unchanged (null).
+func DestinationPoint(dest Object) (left, top float64, ok bool) {
+	arr, isArray := dest.(Array)
+	if !isArray || len(arr) < 4 || arr[1] != Name("XYZ") {
+		return 0, 0, false
+	}
+	left, okLeft := number(arr[2])
+	top, okTop := number(arr[3])
+	if !okLeft || !okTop {
+		return 0, 0, false
+	}
+	return left, top, true
+}
+
+// number returns the value of a numeric object.
+func number(obj Object) (float64, bool) {
+	switch n := obj.(type) {
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// PageSize returns the width and height of a page's media box, in points.
+func (d *Document) PageSize(page Ref) (width, height float64, err error) {
+	dict, err := d.ResolveDict(page)
+	if err != nil {
+		return 0, 0, err
+	}
+	obj, err := d.Resolve(inherited(d, dict, "MediaBox"))
+	if err != nil {
+		return 0, 0, err
+	}
+	box, ok := obj.(Array)
+	if !ok || len(box) != 4 {
+		return 0, 0, fmt.Errorf("%w: page without /MediaBox", ErrMalformed)
+	}
+	var coords [4]float64
+	for i, v := range box {
+		switch n := v.(type) {
+		case int64:
+			coords[i] = float64(n)
+		case float64:
+			coords[i] = n
+		default:
+			return 0, 0, fmt.Errorf("%w: /MediaBox is not numeric", ErrMalformed)
+		}
+	}
+	return math.Abs(coords[2] - coords[0]), math.Abs(coords[3] - coords[1]), nil
+}
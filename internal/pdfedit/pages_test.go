@@ -0,0 +1,124 @@
+package pdfedit
+
+import (
+	"errors"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// TestPages - Page tree traversal
+// ---------------------------------------------------------------------------
+
+func TestPages(t *testing.T) {
+	t.Parallel()
+
+	t.Run("flattens nested page tree", func(t *testing.T) {
+		t.Parallel()
+
+		doc, err := Open(buildPDF("",
+			"<</Type /Catalog /Pages 2 0 R>>",
+			"<</Type /Pages /Count 3 /Kids [3 0 R 4 0 R]>>",
+			"<</Type /Pages /Parent 2 0 R /Count 2 /Kids [5 0 R 6 0 R]>>",
+			"<</Type /Page /Parent 2 0 R>>",
+			"<</Type /Page /Parent 3 0 R>>",
+			"<</Type /Page /Parent 3 0 R>>",
+		))
+		if err != nil {
+			t.Fatalf("Open() unexpected error: %v", err)
+		}
+		pages, err := doc.Pages()
+		if err != nil {
+			t.Fatalf("Pages() unexpected error: %v", err)
+		}
+		want := []Ref{{Num: 5}, {Num: 6}, {Num: 4}}
+		if len(pages) != len(want) {
+			t.Fatalf("Pages() = %v, want %v", pages, want)
+		}
+		for i := range want {
+			if pages[i] != want[i] {
+				t.Errorf("Pages()[%d] = %v, want %v", i, pages[i], want[i])
+			}
+		}
+	})
+
+	t.Run("rejects cyclic page tree", func(t *testing.T) {
+		t.Parallel()
+
+		doc, err := Open(buildPDF("",
+			"<</Type /Catalog /Pages 2 0 R>>",
+			"<</Type /Pages /Kids [2 0 R]>>",
+		))
+		if err != nil {
+			t.Fatalf("Open() unexpected error: %v", err)
+		}
+		if _, err := doc.Pages(); !errors.Is(err, ErrMalformed) {
+			t.Errorf("Pages() error = %v, want %v", err, ErrMalformed)
+		}
+	})
+}
+
+// ---------------------------------------------------------------------------
+// TestPageNumber - Destination to page number
+// ---------------------------------------------------------------------------
+
+func TestPageNumber(t *testing.T) {
+	t.Parallel()
+
+	pages := []Ref{{Num: 3}, {Num: 4}}
+	tests := []struct {
+		name string
+		dest Object
+		want int
+	}{
+		{"first page", Array{Ref{Num: 3}, Name("XYZ"), int64(0), int64(792), int64(0)}, 1},
+		{"second page", Array{Ref{Num: 4}, Name("Fit")}, 2},
+		{"page index", Array{int64(1), Name("Fit")}, 2},
+		{"unknown page", Array{Ref{Num: 9}, Name("Fit")}, 0},
+		{"named destination", String("intro"), 0},
+		{"empty array", Array{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := PageNumber(tt.dest, pages); got != tt.want {
+				t.Errorf("PageNumber(%v) = %d, want %d", tt.dest, got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestDestinationPoint - Coordinates of /XYZ destinations
+// ---------------------------------------------------------------------------
+
+func TestDestinationPoint(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		dest      Object
+		wantLeft  float64
+		wantTop   float64
+		wantFound bool
+	}{
+		{"integer coordinates", Array{Ref{Num: 3}, Name("XYZ"), int64(72), int64(700), int64(0)}, 72, 700, true},
+		{"real coordinates", Array{Ref{Num: 3}, Name("XYZ"), 54.5, 612.25, nil}, 54.5, 612.25, true},
+		{"null top", Array{Ref{Num: 3}, Name("XYZ"), int64(72), nil, int64(0)}, 0, 0, false},
+		{"fit destination", Array{Ref{Num: 3}, Name("Fit")}, 0, 0, false},
+		{"named destination", String("intro"), 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			left, top, ok := DestinationPoint(tt.dest)
+			if ok != tt.wantFound || left != tt.wantLeft || top != tt.wantTop {
+				t.Errorf("DestinationPoint(%v) = (%v, %v, %v), want (%v, %v, %v)",
+					tt.dest, left, top, ok, tt.wantLeft, tt.wantTop, tt.wantFound)
+			}
+		})
+	}
+}
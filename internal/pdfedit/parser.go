@@ -0,0 +1,288 @@
+package pdfedit
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+)
+
+// maxNesting bounds array/dictionary depth to reject hostile input.
+const maxNesting = 64
+
+// parser reads PDF tokens and objects from a byte slice.
+type parser struct {
+	data []byte
+	pos  int
+}
+
+// skipSpace skips whitespace and comments.
+func (p *parser) skipSpace() {
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		switch {
+		case isWhitespace(c):
+			p.pos++
+		case c == '%':
+			for p.pos < len(p.data) && p.data[p.pos] != '\n' && p.data[p.pos] != '\r' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// keyword reads a run of regular characters (a keyword or number).
+func (p *parser) keyword() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		if isWhitespace(c) || isDelimiter(c) {
+			break
+		}
+		p.pos++
+	}
+	return string(p.data[start:p.pos])
+}
+
+// expect consumes kw or returns an error.
+func (p *parser) expect(kw string) error {
+	at := p.pos
+	if got := p.keyword(); got != kw {
+		return fmt.Errorf("%w: expected %q at offset %d, got %q", ErrMalformed, kw, at, got)
+	}
+	return nil
+}
+
+// integer reads a non-negative integer token.
+func (p *parser) integer() (int64, error) {
+	at := p.pos
+	tok := p.keyword()
+	n, err := strconv.ParseInt(tok, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%w: expected integer at offset %d, got %q", ErrMalformed, at, tok)
+	}
+	return n, nil
+}
+
+// object parses the next object. References ("1 0 R") are recognized by
+// looking ahead after an integer.
+func (p *parser) object(depth int) (Object, error) {
+	if depth > maxNesting {
+		return nil, fmt.Errorf("%w: nesting too deep", ErrMalformed)
+	}
+	p.skipSpace()
+	if p.pos >= len(p.data) {
+		return nil, fmt.Errorf("%w: unexpected end of data", ErrMalformed)
+	}
+
+	switch c := p.data[p.pos]; {
+	case c == '/':
+		return p.name()
+	case c == '(':
+		return p.literalString()
+	case c == '<' && p.pos+1 < len(p.data) && p.data[p.pos+1] == '<':
+		return p.dict(depth)
+	case c == '<':
+		return p.hexString()
+	case c == '[':
+		return p.array(depth)
+	}
+
+	at := p.pos
+	tok := p.keyword()
+	switch tok {
+	case "":
+		return nil, fmt.Errorf("%w: unexpected %q at offset %d", ErrMalformed, p.data[at], at)
+	case "null":
+		return nil, nil
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+
+	if n, err := strconv.ParseInt(tok, 10, 64); err == nil {
+		if ref, ok := p.refAfter(n); ok {
+			return ref, nil
+		}
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(tok, 64); err == nil {
+		return f, nil
+	}
+	return nil, fmt.Errorf("%w: unexpected token %q at offset %d", ErrMalformed, tok, at)
+}
+
+// refAfter checks whether num is followed by "gen R" and consumes it if so.
+func (p *parser) refAfter(num int64) (Ref, bool) {
+	save := p.pos
+	gen, err := strconv.ParseInt(p.keyword(), 10, 64)
+	if err == nil && num >= 0 && gen >= 0 && p.keyword() == "R" {
+		return Ref{Num: int(num), Gen: int(gen)}, true
+	}
+	p.pos = save
+	return Ref{}, false
+}
+
+// name parses "/Name" with #xx escapes.
+func (p *parser) name() (Name, error) {
+	p.pos++ // '/'
+	var buf []byte
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		if isWhitespace(c) || isDelimiter(c) {
+			break
+		}
+		if c == '#' && p.pos+2 < len(p.data) {
+			if v, err := strconv.ParseUint(string(p.data[p.pos+1:p.pos+3]), 16, 8); err == nil {
+				buf = append(buf, byte(v))
+				p.pos += 3
+				continue
+			}
+		}
+		buf = append(buf, c)
+		p.pos++
+	}
+	return Name(buf), nil
+}
+
+// literalString parses "(...)" with nesting and escapes.
+func (p *parser) literalString() (String, error) {
+	p.pos++ // '('
+	var buf []byte
+	depth := 1
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		p.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return String(buf), nil
+			}
+		case '\\':
+			if p.pos >= len(p.data) {
+				return "", fmt.Errorf("%w: unterminated string", ErrMalformed)
+			}
+			buf = p.escape(buf)
+			continue
+		}
+		buf = append(buf, c)
+	}
+	return "", fmt.Errorf("%w: unterminated string", ErrMalformed)
+}
+
+// escape decodes one backslash escape in a literal string.
+func (p *parser) escape(buf []byte) []byte {
+	c := p.data[p.pos]
+	p.pos++
+	switch c {
+	case 'n':
+		return append(buf, '\n')
+	case 'r':
+		return append(buf, '\r')
+	case 't':
+		return append(buf, '\t')
+	case 'b':
+		return append(buf, '\b')
+	case 'f':
+		return append(buf, '\f')
+	case '\r':
+		if p.pos < len(p.data) && p.data[p.pos] == '\n' {
+			p.pos++
+		}
+		return buf // line continuation
+	case '\n':
+		return buf
+	}
+	if c >= '0' && c <= '7' {
+		v := int(c - '0')
+		for i := 0; i < 2 && p.pos < len(p.data) && p.data[p.pos] >= '0' && p.data[p.pos] <= '7'; i++ {
+			v = v*8 + int(p.data[p.pos]-'0')
+			p.pos++
+		}
+		return append(buf, byte(v))
+	}
+	return append(buf, c)
+}
+
+// hexString parses "<...>".
+func (p *parser) hexString() (String, error) {
+	p.pos++ // '<'
+	end := bytes.IndexByte(p.data[p.pos:], '>')
+	if end < 0 {
+		return "", fmt.Errorf("%w: unterminated hex string", ErrMalformed)
+	}
+	var digits []byte
+	for _, c := range p.data[p.pos : p.pos+end] {
+		if !isWhitespace(c) {
+			digits = append(digits, c)
+		}
+	}
+	p.pos += end + 1
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	out := make([]byte, len(digits)/2)
+	for i := range out {
+		v, err := strconv.ParseUint(string(digits[2*i:2*i+2]), 16, 8)
+		if err != nil {
+			return "", fmt.Errorf("%w: invalid hex string", ErrMalformed)
+		}
+		out[i] = byte(v)
+	}
+	return String(out), nil
+}
+
+// array parses "[...]".
+func (p *parser) array(depth int) (Array, error) {
+	p.pos++ // '['
+	arr := Array{}
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.data) {
+			return nil, fmt.Errorf("%w: unterminated array", ErrMalformed)
+		}
+		if p.data[p.pos] == ']' {
+			p.pos++
+			return arr, nil
+		}
+		obj, err := p.object(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		arr = append(arr, obj)
+	}
+}
+
+// dict parses "<<...>>".
+func (p *parser) dict(depth int) (Dict, error) {
+	p.pos += 2 // '<<'
+	d := Dict{}
+	for {
+		p.skipSpace()
+		if p.pos+1 >= len(p.data) {
+			return nil, fmt.Errorf("%w: unterminated dictionary", ErrMalformed)
+		}
+		if p.data[p.pos] == '>' && p.data[p.pos+1] == '>' {
+			p.pos += 2
+			return d, nil
+		}
+		if p.data[p.pos] != '/' {
+			return nil, fmt.Errorf("%w: expected name key at offset %d", ErrMalformed, p.pos)
+		}
+		key, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		val, err := p.object(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		d[key] = val
+	}
+}
@@ -0,0 +1,336 @@
+package pdfedit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+// ErrPDFAViolation reports content that PDF/A-2b forbids and that cannot be
+// repaired in an incremental update.
+var ErrPDFAViolation = errors.New("PDF/A-2b violation")
+
+// Annotation flags (PDF 32000-1, 12.5.3).
+const (
+	annotInvisible = 1
+	annotHidden    = 2
+	annotPrint     = 4
+	annotNoView    = 32
+)
+
+// standardBlendModes lists the blend modes PDF/A-2 allows.
+var standardBlendModes = map[Name]bool{
+	"Normal": true, "Compatible": true, "Multiply": true, "Screen": true,
+	"Overlay": true, "Darken": true, "Lighten": true, "ColorDodge": true,
+	"ColorBurn": true, "HardLight": true, "SoftLight": true, "Difference": true,
+	"Exclusion": true, "Hue": true, "Saturation": true, "Color": true,
+	"Luminosity": true,
+}
+
+// SetPDFA checks that the document can conform to PDF/A-2b and adds what a
+// plain PDF lacks: an sRGB output intent, a trailer /ID and the print flag
+// on annotations. The XMP identification is written by SetMetadata when
+// Metadata.PDFA is set.
+//
+// Returns ErrPDFAViolation for encryption, unembedded fonts, transfer
+// functions, non-standard blend modes, and other content PDF/A forbids.
+func SetPDFA(u *Update) error {
+	doc := u.doc
+	if err := checkPDFAHeader(doc.data); err != nil {
+		return err
+	}
+	if _, ok := doc.trailer["Encrypt"]; ok {
+		return fmt.Errorf("%w: document is encrypted", ErrPDFAViolation)
+	}
+
+	catalog, catalogRef, err := u.Catalog()
+	if err != nil {
+		return err
+	}
+	if err := checkPDFACatalog(doc, catalog); err != nil {
+		return err
+	}
+
+	pages, err := doc.Pages()
+	if err != nil {
+		return err
+	}
+	check := &pdfaChecker{doc: doc, seen: make(map[Ref]bool)}
+	for i, page := range pages {
+		dict, err := doc.ResolveDict(page)
+		if err != nil {
+			return err
+		}
+		if err := check.resources(dict["Resources"], 0); err != nil {
+			return fmt.Errorf("page %d: %w", i+1, err)
+		}
+		if err := setPrintFlags(u, page, dict); err != nil {
+			return fmt.Errorf("page %d: %w", i+1, err)
+		}
+	}
+
+	profile := SRGBProfile()
+	catalog["OutputIntents"] = Array{Dict{
+		"Type":                      Name("OutputIntent"),
+		"S":                         Name("GTS_PDFA1"),
+		"OutputConditionIdentifier": String(sRGBDescription),
+		"RegistryName":              String("http://www.color.org"),
+		"Info":                      String(sRGBDescription),
+		"DestOutputProfile": u.Add(&Stream{
+			Dict: Dict{"N": int64(3)},
+			Data: profile,
+		}),
+	}}
+	u.Set(catalogRef, catalog)
+
+	if _, ok := doc.trailer["ID"]; !ok {
+		sum := sha256.Sum256(doc.data)
+		id := String(sum[:16])
+		u.SetTrailer("ID", Array{id, id})
+	}
+	return nil
+}
+
+// checkPDFAHeader requires a PDF 1.x header of version 1.7 or lower,
+// followed by a comment of at least four binary bytes.
+func checkPDFAHeader(data []byte) error {
+	if len(data) < 9 || !bytes.HasPrefix(data, []byte("%PDF-1.")) || data[7] < '0' || data[7] > '7' {
+		return fmt.Errorf("%w: PDF version is not 1.0-1.7", ErrPDFAViolation)
+	}
+	rest := bytes.TrimLeft(data[8:], "\r\n")
+	if len(rest) < 5 || rest[0] != '%' {
+		return fmt.Errorf("%w: header lacks a binary comment", ErrPDFAViolation)
+	}
+	for _, c := range rest[1:5] {
+		if c < 128 {
+			return fmt.Errorf("%w: header lacks a binary comment", ErrPDFAViolation)
+		}
+	}
+	return nil
+}
+
+// checkPDFACatalog rejects document-level JavaScript and additional actions.
+func checkPDFACatalog(doc *Document, catalog Dict) error {
+	if _, ok := catalog["AA"]; ok {
+		return fmt.Errorf("%w: document additional actions", ErrPDFAViolation)
+	}
+	names, ok := catalog["Names"]
+	if !ok {
+		return nil
+	}
+	dict, err := doc.ResolveDict(names)
+	if err != nil {
+		return err
+	}
+	if _, ok := dict["JavaScript"]; ok {
+		return fmt.Errorf("%w: document JavaScript", ErrPDFAViolation)
+	}
+	return nil
+}
+
+// pdfaChecker walks resource dictionaries, visiting shared objects once.
+type pdfaChecker struct {
+	doc  *Document
+	seen map[Ref]bool
+}
+
+// visit reports whether obj is a reference not seen before, or a direct object.
+func (c *pdfaChecker) visit(obj Object) bool {
+	ref, ok := obj.(Ref)
+	if !ok {
+		return true
+	}
+	if c.seen[ref] {
+		return false
+	}
+	c.seen[ref] = true
+	return true
+}
+
+// resources checks the fonts and graphics states of a resource dictionary,
+// and of the form XObjects it uses.
+func (c *pdfaChecker) resources(obj Object, depth int) error {
+	if obj == nil || !c.visit(obj) {
+		return nil
+	}
+	if depth > maxNesting {
+		return fmt.Errorf("%w: resources nested too deep", ErrMalformed)
+	}
+	res, err := c.doc.ResolveDict(obj)
+	if err != nil {
+		return err
+	}
+
+	fonts, err := c.entries(res["Font"])
+	if err != nil {
+		return err
+	}
+	for name, font := range fonts {
+		if err := c.font(name, font); err != nil {
+			return err
+		}
+	}
+
+	states, err := c.entries(res["ExtGState"])
+	if err != nil {
+		return err
+	}
+	for name, gs := range states {
+		if err := c.graphicsState(name, gs); err != nil {
+			return err
+		}
+	}
+
+	xobjects, err := c.entries(res["XObject"])
+	if err != nil {
+		return err
+	}
+	for _, xobj := range xobjects {
+		if !c.visit(xobj) {
+			continue
+		}
+		dict, err := c.doc.ResolveDict(xobj)
+		if err != nil {
+			return err
+		}
+		switch dict["Subtype"] {
+		case Name("PS"):
+			return fmt.Errorf("%w: PostScript XObject", ErrPDFAViolation)
+		case Name("Form"):
+			if _, ok := dict["Ref"]; ok {
+				return fmt.Errorf("%w: reference XObject", ErrPDFAViolation)
+			}
+			if err := c.resources(dict["Resources"], depth+1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// entries resolves a resource category such as /Font, which may be absent.
+func (c *pdfaChecker) entries(obj Object) (Dict, error) {
+	if obj == nil {
+		return nil, nil
+	}
+	return c.doc.ResolveDict(obj)
+}
+
+// font requires an embedded font program. Type 3 fonts are exempt: their
+// glyphs are content streams.
+func (c *pdfaChecker) font(name Name, obj Object) error {
+	if !c.visit(obj) {
+		return nil
+	}
+	font, err := c.doc.ResolveDict(obj)
+	if err != nil {
+		return err
+	}
+	switch font["Subtype"] {
+	case Name("Type3"):
+		return nil
+	case Name("Type0"):
+		kids, err := c.doc.Resolve(font["DescendantFonts"])
+		if err != nil {
+			return err
+		}
+		if arr, ok := kids.(Array); ok && len(arr) > 0 {
+			if font, err = c.doc.ResolveDict(arr[0]); err != nil {
+				return err
+			}
+		}
+	}
+
+	descriptor, err := c.doc.ResolveDict(font["FontDescriptor"])
+	if err == nil {
+		for _, key := range []Name{"FontFile", "FontFile2", "FontFile3"} {
+			if _, ok := descriptor[key]; ok {
+				return nil
+			}
+		}
+	}
+	base, _ := font["BaseFont"].(Name)
+	if base == "" {
+		base = name
+	}
+	return fmt.Errorf("%w: font %s is not embedded", ErrPDFAViolation, base)
+}
+
+// graphicsState rejects transfer functions and non-standard blend modes.
+func (c *pdfaChecker) graphicsState(name Name, obj Object) error {
+	if !c.visit(obj) {
+		return nil
+	}
+	gs, err := c.doc.ResolveDict(obj)
+	if err != nil {
+		return err
+	}
+	if _, ok := gs["TR"]; ok {
+		return fmt.Errorf("%w: graphics state %s uses a transfer function", ErrPDFAViolation, name)
+	}
+	if tr2, ok := gs["TR2"]; ok && tr2 != Name("Default") {
+		return fmt.Errorf("%w: graphics state %s uses a transfer function", ErrPDFAViolation, name)
+	}
+
+	var modes Array
+	switch bm := gs["BM"].(type) {
+	case Name:
+		modes = Array{bm}
+	case Array:
+		modes = bm
+	}
+	for _, mode := range modes {
+		if n, _ := mode.(Name); !standardBlendModes[n] {
+			return fmt.Errorf("%w: graphics state %s uses blend mode %v", ErrPDFAViolation, name, mode)
+		}
+	}
+	return nil
+}
+
+// setPrintFlags makes every annotation of a page printable and visible, as
+// PDF/A requires. Annotations other than links and popups must also carry
+// an appearance stream.
+func setPrintFlags(u *Update, pageRef Ref, page Dict) error {
+	obj, err := u.doc.Resolve(page["Annots"])
+	if err != nil {
+		return err
+	}
+	annots, _ := obj.(Array)
+
+	inlineChanged := false
+	for i, item := range annots {
+		annot, err := u.doc.ResolveDict(item)
+		if err != nil {
+			return err
+		}
+		subtype, _ := annot["Subtype"].(Name)
+		if subtype == "Popup" {
+			continue
+		}
+		if _, ok := annot["AP"]; !ok && subtype != "Link" {
+			return fmt.Errorf("%w: %s annotation without appearance", ErrPDFAViolation, subtype)
+		}
+
+		flags, _ := annot["F"].(int64)
+		fixed := (flags | annotPrint) &^ (annotInvisible | annotHidden | annotNoView)
+		if fixed == flags {
+			continue
+		}
+		annot = annot.Clone()
+		annot["F"] = fixed
+		if ref, ok := item.(Ref); ok {
+			u.Set(ref, annot)
+			continue
+		}
+		annots[i] = annot
+		inlineChanged = true
+	}
+
+	if inlineChanged {
+		page = page.Clone()
+		page["Annots"] = annots
+		u.Set(pageRef, page)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package pdfedit
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// pdfaPDF returns a one-page PDF with an embedded Type 0 font, a blending
+// graphics state and a link annotation without flags, as Chrome writes them.
+// catalogExtra, resources and extra objects (numbered from 9) exercise
+// violations.
+func pdfaPDF(catalogExtra, resources string, extra ...string) []byte {
+	if resources == "" {
+		resources = "/Font <</F1 5 0 R>> /ExtGState <</G1 <</BM /Multiply /ca 0.5>>>>"
+	}
+	objects := []string{
+		"<</Type /Catalog /Pages 2 0 R" + catalogExtra + ">>",
+		"<</Type /Pages /Count 1 /Kids [3 0 R]>>",
+		"<</Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Resources <<" + resources + ">> /Annots [4 0 R]>>",
+		"<</Type /Annot /Subtype /Link /Rect [0 0 10 10] /Dest [3 0 R /Fit]>>",
+		"<</Type /Font /Subtype /Type0 /BaseFont /Inter /DescendantFonts [6 0 R]>>",
+		"<</Type /Font /Subtype /CIDFontType2 /BaseFont /Inter /FontDescriptor 7 0 R>>",
+		"<</Type /FontDescriptor /FontName /Inter /FontFile2 8 0 R>>",
+		"<</Length 4>>\nstream\nglyf\nendstream",
+	}
+	return buildPDF("", append(objects, extra...)...)
+}
+
+// ---------------------------------------------------------------------------
+// TestSetPDFA - Output intent, trailer ID, annotation flags and violations
+// ---------------------------------------------------------------------------
+
+func TestSetPDFA(t *testing.T) {
+	t.Parallel()
+
+	t.Run("adds output intent, ID and print flags", func(t *testing.T) {
+		t.Parallel()
+
+		doc, err := Open(pdfaPDF("", ""))
+		if err != nil {
+			t.Fatalf("Open() unexpected error: %v", err)
+		}
+		u := doc.NewUpdate()
+		if err := SetPDFA(u); err != nil {
+			t.Fatalf("SetPDFA() unexpected error: %v", err)
+		}
+		updated, err := Open(u.Bytes())
+		if err != nil {
+			t.Fatalf("Open(updated) unexpected error: %v", err)
+		}
+
+		catalog, _, err := updated.Catalog()
+		if err != nil {
+			t.Fatalf("Catalog() unexpected error: %v", err)
+		}
+		intents, ok := catalog["OutputIntents"].(Array)
+		if !ok || len(intents) != 1 {
+			t.Fatalf("catalog /OutputIntents = %v, want one intent", catalog["OutputIntents"])
+		}
+		intent := intents[0].(Dict)
+		if intent["S"] != Name("GTS_PDFA1") {
+			t.Errorf("output intent /S = %v, want /GTS_PDFA1", intent["S"])
+		}
+		obj, err := updated.Resolve(intent["DestOutputProfile"])
+		if err != nil {
+			t.Fatalf("resolving /DestOutputProfile: %v", err)
+		}
+		if profile, ok := obj.(*Stream); !ok || !bytes.Equal(profile.Data, SRGBProfile()) || profile.Dict["N"] != int64(3) {
+			t.Errorf("/DestOutputProfile = %T, want sRGB profile stream with /N 3", obj)
+		}
+
+		if id, ok := updated.Trailer()["ID"].(Array); !ok || len(id) != 2 {
+			t.Errorf("trailer /ID = %v, want two strings", updated.Trailer()["ID"])
+		}
+		annot, err := updated.ResolveDict(Ref{Num: 4})
+		if err != nil {
+			t.Fatalf("resolving annotation: %v", err)
+		}
+		if annot["F"] != int64(annotPrint) {
+			t.Errorf("annotation /F = %v, want %d", annot["F"], annotPrint)
+		}
+	})
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"encrypted", bytes.Replace(pdfaPDF("", "", "<</Filter /Standard>>"), []byte("/Root 1 0 R"), []byte("/Root 1 0 R /Encrypt 9 0 R"), 1)},
+		{"unembedded font", pdfaPDF("", "/Font <</F1 9 0 R>>", "<</Type /Font /Subtype /Type1 /BaseFont /Helvetica>>")},
+		{"transfer function", pdfaPDF("", "/ExtGState <</G1 <</TR /Identity>>>>")},
+		{"non-standard blend mode", pdfaPDF("", "/ExtGState <</G1 <</BM /Glow>>>>")},
+		{"form with unembedded font", pdfaPDF("", "/XObject <</X1 9 0 R>>",
+			"<</Type /XObject /Subtype /Form /Resources <</Font <</F2 10 0 R>>>> /Length 0>>\nstream\n\nendstream",
+			"<</Type /Font /Subtype /TrueType /BaseFont /Arial>>")},
+		{"binary comment missing", bytes.Replace(pdfaPDF("", ""), []byte("%\xe2\xe3\xcf\xd3"), []byte("%abcd"), 1)},
+		{"document JavaScript", pdfaPDF(" /Names <</JavaScript <<>>>>", "")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			doc, err := Open(tt.data)
+			if err != nil {
+				t.Fatalf("Open() unexpected error: %v", err)
+			}
+			if err := SetPDFA(doc.NewUpdate()); !errors.Is(err, ErrPDFAViolation) {
+				t.Errorf("SetPDFA() error = %v, want %v", err, ErrPDFAViolation)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestSetMetadata_PDFA - XMP identification
+// ---------------------------------------------------------------------------
+
+func TestSetMetadata_PDFA(t *testing.T) {
+	t.Parallel()
+
+	doc, err := Open(skiaPDF())
+	if err != nil {
+		t.Fatalf("Open() unexpected error: %v", err)
+	}
+	u := doc.NewUpdate()
+	if err := SetMetadata(u, Metadata{Title: "Archive", PDFA: true}); err != nil {
+		t.Fatalf("SetMetadata() unexpected error: %v", err)
+	}
+	updated, err := Open(u.Bytes())
+	if err != nil {
+		t.Fatalf("Open(updated) unexpected error: %v", err)
+	}
+	catalog, _, err := updated.Catalog()
+	if err != nil {
+		t.Fatalf("Catalog() unexpected error: %v", err)
+	}
+	obj, err := updated.Resolve(catalog["Metadata"])
+	if err != nil {
+		t.Fatalf("resolving /Metadata: %v", err)
+	}
+	xmp := string(obj.(*Stream).Data)
+	for _, want := range []string{"<pdfaid:part>2</pdfaid:part>", "<pdfaid:conformance>B</pdfaid:conformance>"} {
+		if !strings.Contains(xmp, want) {
+			t.Errorf("XMP missing %q in:\n%s", want, xmp)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestSRGBProfile - ICC profile structure
+// ---------------------------------------------------------------------------
+
+func TestSRGBProfile(t *testing.T) {
+	t.Parallel()
+
+	p := SRGBProfile()
+	if size := binary.BigEndian.Uint32(p[0:]); int(size) != len(p) {
+		t.Errorf("profile size field = %d, want %d", size, len(p))
+	}
+	if string(p[12:24]) != "mntrRGB XYZ " || string(p[36:40]) != "acsp" {
+		t.Errorf("profile header = %q, want display RGB profile with acsp signature", p[12:40])
+	}
+	count := int(binary.BigEndian.Uint32(p[128:]))
+	for i := range count {
+		entry := p[132+12*i:]
+		offset, size := binary.BigEndian.Uint32(entry[4:]), binary.BigEndian.Uint32(entry[8:])
+		if offset%4 != 0 || int(offset+size) > len(p) {
+			t.Errorf("tag %q at %d+%d, want aligned and inside %d bytes", entry[:4], offset, size, len(p))
+		}
+	}
+	if count != 9 {
+		t.Errorf("profile has %d tags, want 9", count)
+	}
+}
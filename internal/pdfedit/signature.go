@@ -0,0 +1,372 @@
+package pdfedit
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultSignatureSize is the space reserved for a signature when
+// SignatureField.Size is zero. It fits an RSA-4096 signature with a short
+// chain and a timestamp token.
+const DefaultSignatureSize = 16384
+
+// ErrSignatureSize reports a signature larger than the space reserved for it.
+var ErrSignatureSize = errors.New("signature exceeds reserved space")
+
+// Annotation flag locking a field against edits (PDF 32000-1, 12.5.3).
+const annotLocked = 128
+
+// byteRangePlaceholder is written before signing and patched in place,
+// so the number of digits must match the patched values.
+const byteRangePlaceholder = "[0 0000000000 0000000000 0000000000]"
+
+// rawObject is written verbatim. It holds the placeholders Sign patches
+// after the file layout is known.
+type rawObject []byte
+
+// SignatureField describes a signature added with Sign.
+type SignatureField struct {
+	Name        string    // Field name (/T); "Signature1" if empty
+	SignerName  string    // Signer shown by readers (/Name)
+	Reason      string    // Reason for signing
+	Location    string    // Where the document was signed
+	ContactInfo string    // How to reach the signer
+	Time        time.Time // Signing time (/M); omitted if zero
+	Page        int       // 1-based page holding the widget; 0 means the first page
+	// Rect is the visible field in default user space (llx, lly, urx, ury).
+	// A zero Rect makes the signature invisible.
+	Rect [4]float64
+	Size int // Bytes reserved for the signature; 0 means DefaultSignatureSize
+}
+
+// SignFunc signs the bytes covered by the signature's /ByteRange and
+// returns a DER-encoded CMS SignedData.
+type SignFunc func(signed []byte) ([]byte, error)
+
+// Sign appends a signature field to data as a final incremental update.
+// sign receives the whole file except the /Contents placeholder and its
+// result is written into that placeholder, so no edit may follow.
+//
+// Returns ErrUnsupported for encrypted input and ErrSignatureSize when the
+// signature does not fit in field.Size bytes.
+func Sign(data []byte, field SignatureField, sign SignFunc) ([]byte, error) {
+	doc, err := Open(data)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := doc.trailer["Encrypt"]; ok {
+		return nil, fmt.Errorf("%w: signing an encrypted document", ErrUnsupported)
+	}
+	size := field.Size
+	if size <= 0 {
+		size = DefaultSignatureSize
+	}
+
+	pages, err := doc.Pages()
+	if err != nil {
+		return nil, err
+	}
+	pageIndex := max(field.Page, 1) - 1
+	if pageIndex >= len(pages) {
+		return nil, fmt.Errorf("%w: signature page %d of %d", ErrObjectNotFound, field.Page, len(pages))
+	}
+	pageRef := pages[pageIndex]
+	page, err := doc.ResolveDict(pageRef)
+	if err != nil {
+		return nil, err
+	}
+
+	u := doc.NewUpdate()
+	sigRef := u.Add(signatureDict(field, size))
+	widgetRef := u.Add(signatureWidget(u, field, sigRef, pageRef))
+
+	annots, err := appendArray(doc, page["Annots"], widgetRef)
+	if err != nil {
+		return nil, fmt.Errorf("reading page /Annots: %w", err)
+	}
+	page = page.Clone()
+	page["Annots"] = annots
+	u.Set(pageRef, page)
+
+	catalog, catalogRef, err := u.Catalog()
+	if err != nil {
+		return nil, err
+	}
+	form := Dict{}
+	if obj, ok := catalog["AcroForm"]; ok {
+		if form, err = doc.ResolveDict(obj); err != nil {
+			return nil, fmt.Errorf("reading /AcroForm: %w", err)
+		}
+		form = form.Clone()
+	}
+	if form["Fields"], err = appendArray(doc, form["Fields"], widgetRef); err != nil {
+		return nil, fmt.Errorf("reading /AcroForm /Fields: %w", err)
+	}
+	form["SigFlags"] = int64(3) // SignaturesExist | AppendOnly
+	catalog["AcroForm"] = form
+	u.Set(catalogRef, catalog)
+
+	return fillSignature(u.Bytes(), len(data), size, sign)
+}
+
+// signatureDict builds the signature value with its placeholders.
+func signatureDict(field SignatureField, size int) Dict {
+	contents := make([]byte, 0, 2*size+2)
+	contents = append(contents, '<')
+	contents = append(contents, bytes.Repeat([]byte("0"), 2*size)...)
+	contents = append(contents, '>')
+
+	sig := Dict{
+		"Type":      Name("Sig"),
+		"Filter":    Name("Adobe.PPKLite"),
+		"SubFilter": Name("ETSI.CAdES.detached"),
+		"ByteRange": rawObject(byteRangePlaceholder),
+		"Contents":  rawObject(contents),
+	}
+	if !field.Time.IsZero() {
+		sig["M"] = String(FormatDate(field.Time))
+	}
+	for key, value := range map[Name]string{
+		"Name":        field.SignerName,
+		"Reason":      field.Reason,
+		"Location":    field.Location,
+		"ContactInfo": field.ContactInfo,
+	} {
+		if value != "" {
+			sig[key] = TextString(value)
+		}
+	}
+	return sig
+}
+
+// signatureWidget builds the merged field and widget annotation. The
+// appearance is empty: a visible field sits over content the page already
+// draws.
+func signatureWidget(u *Update, field SignatureField, sigRef, pageRef Ref) Dict {
+	name := field.Name
+	if name == "" {
+		name = "Signature1"
+	}
+	r := field.Rect
+	rect := Array{r[0], r[1], r[2], r[3]}
+	appearance := u.Add(&Stream{Dict: Dict{
+		"Type":    Name("XObject"),
+		"Subtype": Name("Form"),
+		"BBox":    Array{0.0, 0.0, r[2] - r[0], r[3] - r[1]},
+	}})
+	return Dict{
+		"Type":    Name("Annot"),
+		"Subtype": Name("Widget"),
+		"FT":      Name("Sig"),
+		"T":       TextString(name),
+		"V":       sigRef,
+		"Rect":    rect,
+		"P":       pageRef,
+		"F":       int64(annotPrint | annotLocked),
+		"AP":      Dict{"N": appearance},
+	}
+}
+
+// appendArray returns a direct copy of the array at obj with ref appended.
+func appendArray(doc *Document, obj Object, ref Ref) (Array, error) {
+	if obj == nil {
+		return Array{ref}, nil
+	}
+	resolved, err := doc.Resolve(obj)
+	if err != nil {
+		return nil, err
+	}
+	arr, ok := resolved.(Array)
+	if !ok {
+		return nil, fmt.Errorf("%w: expected array, got %T", ErrMalformed, resolved)
+	}
+	return append(append(Array{}, arr...), ref), nil
+}
+
+// fillSignature patches the placeholders past offset start: the byte range
+// around /Contents, then the signature itself.
+func fillSignature(out []byte, start, size int, sign SignFunc) ([]byte, error) {
+	rangeAt := bytes.Index(out[start:], []byte(byteRangePlaceholder))
+	contentsAt := bytes.Index(out[start:], []byte("<"+string(bytes.Repeat([]byte("0"), 2*size))+">"))
+	if rangeAt < 0 || contentsAt < 0 {
+		return nil, fmt.Errorf("%w: signature placeholders not found", ErrMalformed)
+	}
+	rangeAt += start
+	contentsAt += start
+	contentsEnd := contentsAt + 2*size + 2
+
+	byteRange := fmt.Sprintf("[0 %010d %010d %010d]", contentsAt, contentsEnd, len(out)-contentsEnd)
+	copy(out[rangeAt:], byteRange)
+
+	signed := make([]byte, 0, len(out)-(contentsEnd-contentsAt))
+	signed = append(signed, out[:contentsAt]...)
+	signed = append(signed, out[contentsEnd:]...)
+	der, err := sign(signed)
+	if err != nil {
+		return nil, err
+	}
+	if len(der) > size {
+		return nil, fmt.Errorf("%w: %d bytes, %d reserved", ErrSignatureSize, len(der), size)
+	}
+	hex.Encode(out[contentsAt+1:], der)
+	return out, nil
+}
+
+// Signature is a signature field read back from a document.
+type Signature struct {
+	Field       string // Field name (/T)
+	SubFilter   string // Signature format, e.g. "ETSI.CAdES.detached"
+	SignerName  string
+	Reason      string
+	Location    string
+	ContactInfo string
+	Time        time.Time // Claimed signing time (/M); zero if absent
+	Contents    []byte    // DER-encoded signature, zero-padded to the reserved size
+	Signed      []byte    // File bytes covered by /ByteRange
+	// WholeFile reports whether the byte range covers the entire file
+	// except the signature, i.e. nothing was appended after signing.
+	WholeFile bool
+}
+
+// Signatures returns the signed signature fields of the interactive form,
+// in field order.
+func (d *Document) Signatures() ([]Signature, error) {
+	catalog, _, err := d.Catalog()
+	if err != nil {
+		return nil, err
+	}
+	obj, ok := catalog["AcroForm"]
+	if !ok {
+		return nil, nil
+	}
+	form, err := d.ResolveDict(obj)
+	if err != nil {
+		return nil, fmt.Errorf("reading /AcroForm: %w", err)
+	}
+	fields, err := d.Resolve(form["Fields"])
+	if err != nil {
+		return nil, fmt.Errorf("reading /AcroForm /Fields: %w", err)
+	}
+	list, _ := fields.(Array)
+
+	var sigs []Signature
+	seen := make(map[Ref]bool)
+	for _, f := range list {
+		if err := d.collectSignatures(f, "", &sigs, 0, seen); err != nil {
+			return nil, err
+		}
+	}
+	return sigs, nil
+}
+
+// collectSignatures appends the signed /Sig fields under the field obj.
+// prefix is the parent's fully qualified name.
+func (d *Document) collectSignatures(obj Object, prefix string, out *[]Signature, depth int, seen map[Ref]bool) error {
+	if depth > maxNesting {
+		return fmt.Errorf("%w: field tree too deep", ErrMalformed)
+	}
+	if ref, ok := obj.(Ref); ok {
+		if seen[ref] {
+			return fmt.Errorf("%w: cyclic field tree", ErrMalformed)
+		}
+		seen[ref] = true
+	}
+	field, err := d.ResolveDict(obj)
+	if err != nil {
+		return err
+	}
+	name := prefix
+	if t, ok := field["T"].(String); ok {
+		if name != "" {
+			name += "."
+		}
+		name += t.Text()
+	}
+
+	if kids, ok := field["Kids"]; ok {
+		arr, err := d.Resolve(kids)
+		if err != nil {
+			return err
+		}
+		list, _ := arr.(Array)
+		for _, kid := range list {
+			if err := d.collectSignatures(kid, name, out, depth+1, seen); err != nil {
+				return err
+			}
+		}
+	}
+	if field["FT"] != Name("Sig") || field["V"] == nil {
+		return nil
+	}
+	value, err := d.ResolveDict(field["V"])
+	if err != nil {
+		return fmt.Errorf("reading signature %q: %w", name, err)
+	}
+	sig, err := d.readSignature(value)
+	if err != nil {
+		return fmt.Errorf("reading signature %q: %w", name, err)
+	}
+	sig.Field = name
+	*out = append(*out, sig)
+	return nil
+}
+
+// readSignature decodes a signature dictionary and extracts the bytes its
+// /ByteRange covers.
+func (d *Document) readSignature(value Dict) (Signature, error) {
+	text := func(key Name) string {
+		s, _ := value[key].(String)
+		return s.Text()
+	}
+	contents, ok := value["Contents"].(String)
+	if !ok {
+		return Signature{}, fmt.Errorf("%w: missing /Contents", ErrMalformed)
+	}
+	subFilter, _ := value["SubFilter"].(Name)
+	sig := Signature{
+		SubFilter:   string(subFilter),
+		SignerName:  text("Name"),
+		Reason:      text("Reason"),
+		Location:    text("Location"),
+		ContactInfo: text("ContactInfo"),
+		Contents:    []byte(contents),
+	}
+	if m, ok := value["M"].(String); ok {
+		if t, err := ParseDate(string(m)); err == nil {
+			sig.Time = t
+		}
+	}
+
+	ranges, _ := value["ByteRange"].(Array)
+	if len(ranges) == 0 || len(ranges)%2 != 0 {
+		return Signature{}, fmt.Errorf("%w: invalid /ByteRange", ErrMalformed)
+	}
+	end := int64(0)
+	for i := 0; i < len(ranges); i += 2 {
+		off, ok1 := ranges[i].(int64)
+		n, ok2 := ranges[i+1].(int64)
+		if !ok1 || !ok2 || off < end || n < 0 || off+n > int64(len(d.data)) {
+			return Signature{}, fmt.Errorf("%w: /ByteRange %v out of bounds", ErrMalformed, ranges)
+		}
+		sig.Signed = append(sig.Signed, d.data[off:off+n]...)
+		end = off + n
+	}
+	sig.WholeFile = len(ranges) == 4 && ranges[0] == int64(0) && end == int64(len(d.data)) &&
+		isHexString(d.data[ranges[0].(int64)+ranges[1].(int64):ranges[2].(int64)], sig.Contents)
+	return sig, nil
+}
+
+// isHexString reports whether raw is the hex string <...> of value and
+// nothing else. A /ByteRange gap holding more than /Contents could hide a
+// second signature dictionary.
+func isHexString(raw, value []byte) bool {
+	if len(raw) < 2 || raw[0] != '<' || raw[len(raw)-1] != '>' {
+		return false
+	}
+	decoded := make([]byte, hex.DecodedLen(len(raw)-2))
+	n, err := hex.Decode(decoded, raw[1:len(raw)-1])
+	return err == nil && bytes.Equal(decoded[:n], value)
+}
@@ -0,0 +1,291 @@
+package pdfedit
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// signWith signs data with a fake signer that records the signed bytes.
+func signWith(t *testing.T, data []byte, field SignatureField, der []byte) ([]byte, []byte) {
+	t.Helper()
+
+	var signed []byte
+	out, err := Sign(data, field, func(b []byte) ([]byte, error) {
+		signed = append([]byte(nil), b...)
+		return der, nil
+	})
+	if err != nil {
+		t.Fatalf("Sign() unexpected error: %v", err)
+	}
+	return out, signed
+}
+
+// ---------------------------------------------------------------------------
+// TestSign - Signature field and byte range
+// ---------------------------------------------------------------------------
+
+func TestSign(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reads back the signature", func(t *testing.T) {
+		t.Parallel()
+
+		when := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+		out, signed := signWith(t, skiaPDF(), SignatureField{
+			SignerName: "Ada Lovelace",
+			Reason:     "Approval",
+			Location:   "Zürich",
+			Time:       when,
+		}, []byte{0x30, 0x03, 0x02, 0x01, 0x00})
+
+		doc, err := Open(out)
+		if err != nil {
+			t.Fatalf("Open(signed) unexpected error: %v", err)
+		}
+		sigs, err := doc.Signatures()
+		if err != nil {
+			t.Fatalf("Signatures() unexpected error: %v", err)
+		}
+		if len(sigs) != 1 {
+			t.Fatalf("Signatures() returned %d signatures, want 1", len(sigs))
+		}
+		sig := sigs[0]
+		if sig.Field != "Signature1" || sig.SignerName != "Ada Lovelace" || sig.Reason != "Approval" || sig.Location != "Zürich" {
+			t.Errorf("signature = %+v, want field Signature1 by Ada Lovelace", sig)
+		}
+		if !sig.Time.Equal(when) {
+			t.Errorf("signature time = %v, want %v", sig.Time, when)
+		}
+		if sig.SubFilter != "ETSI.CAdES.detached" {
+			t.Errorf("SubFilter = %q, want ETSI.CAdES.detached", sig.SubFilter)
+		}
+		if !bytes.Equal(sig.Signed, signed) {
+			t.Error("signature byte range differs from the bytes passed to sign")
+		}
+		if !sig.WholeFile {
+			t.Error("WholeFile = false, want true")
+		}
+		if len(sig.Contents) != DefaultSignatureSize || !bytes.HasPrefix(sig.Contents, []byte{0x30, 0x03, 0x02, 0x01, 0x00}) {
+			t.Errorf("Contents = %x..., want DER zero-padded to %d bytes", sig.Contents[:8], DefaultSignatureSize)
+		}
+		if !bytes.HasPrefix(out, skiaPDF()) {
+			t.Error("Sign() rewrote the original bytes")
+		}
+	})
+
+	t.Run("links the widget to the page and form", func(t *testing.T) {
+		t.Parallel()
+
+		out, _ := signWith(t, skiaPDF(), SignatureField{Name: "Approval", Page: 2, Rect: [4]float64{72, 100, 540, 200}}, []byte{0x30, 0x00})
+		doc, err := Open(out)
+		if err != nil {
+			t.Fatalf("Open(signed) unexpected error: %v", err)
+		}
+		catalog, _, _ := doc.Catalog()
+		form, err := doc.ResolveDict(catalog["AcroForm"])
+		if err != nil {
+			t.Fatalf("ResolveDict(/AcroForm) unexpected error: %v", err)
+		}
+		if form["SigFlags"] != int64(3) {
+			t.Errorf("/SigFlags = %v, want 3", form["SigFlags"])
+		}
+		fields, _ := form["Fields"].(Array)
+		if len(fields) != 1 {
+			t.Fatalf("/Fields = %v, want one field", fields)
+		}
+		widget, _ := doc.ResolveDict(fields[0])
+		if got := widget["Rect"]; !equalArray(got, Array{int64(72), int64(100), int64(540), int64(200)}) {
+			t.Errorf("widget /Rect = %v, want [72 100 540 200]", got)
+		}
+
+		page, _ := doc.ResolveDict(Ref{Num: 4})
+		annots, _ := page["Annots"].(Array)
+		if len(annots) != 1 || annots[0] != fields[0] {
+			t.Errorf("page 2 /Annots = %v, want the widget %v", annots, fields[0])
+		}
+		if first, _ := doc.ResolveDict(Ref{Num: 3}); first["Annots"] != nil {
+			t.Errorf("page 1 /Annots = %v, want none", first["Annots"])
+		}
+	})
+
+	t.Run("later updates leave the byte range short", func(t *testing.T) {
+		t.Parallel()
+
+		out, _ := signWith(t, skiaPDF(), SignatureField{}, []byte{0x30, 0x00})
+		doc, _ := Open(out)
+		u := doc.NewUpdate()
+		u.Set(Ref{Num: 6}, Dict{"Title": String("Changed")})
+
+		changed, err := Open(u.Bytes())
+		if err != nil {
+			t.Fatalf("Open(updated) unexpected error: %v", err)
+		}
+		sigs, err := changed.Signatures()
+		if err != nil || len(sigs) != 1 {
+			t.Fatalf("Signatures() = %d, %v, want one signature", len(sigs), err)
+		}
+		if sigs[0].WholeFile {
+			t.Error("WholeFile = true after an update, want false")
+		}
+	})
+
+	t.Run("byte range gap must be exactly the contents", func(t *testing.T) {
+		t.Parallel()
+
+		out, _ := signWith(t, skiaPDF(), SignatureField{}, []byte{0x30, 0x00})
+		pattern := regexp.MustCompile(`/ByteRange \[0 (\d{10}) (\d{10}) (\d{10})\]`)
+		m := pattern.FindSubmatchIndex(out)
+		if m == nil {
+			t.Fatal("signed output has no patched /ByteRange")
+		}
+		num := func(i int) int {
+			n, _ := strconv.Atoi(string(out[m[2*i]:m[2*i+1]]))
+			return n
+		}
+		r1, r2, r3 := num(1), num(2), num(3)
+
+		tests := []struct {
+			name       string
+			r1, r2, r3 int
+		}{
+			{"widened before contents", r1 - 1, r2, r3},
+			{"widened after contents", r1, r2 + 1, r3 - 1},
+			{"moved off contents", r1 - 10, r2 - 10, r3 + 10},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				tampered := bytes.Clone(out)
+				copy(tampered[m[0]:m[1]], fmt.Sprintf("/ByteRange [0 %010d %010d %010d]", tt.r1, tt.r2, tt.r3))
+				doc, err := Open(tampered)
+				if err != nil {
+					t.Fatalf("Open(tampered) unexpected error: %v", err)
+				}
+				sigs, err := doc.Signatures()
+				if err != nil || len(sigs) != 1 {
+					t.Fatalf("Signatures() = %d, %v, want one signature", len(sigs), err)
+				}
+				if sigs[0].WholeFile {
+					t.Error("WholeFile = true with a tampered /ByteRange, want false")
+				}
+			})
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		encrypted, err := Encrypt(skiaPDF(), Encryption{UserPassword: "u"})
+		if err != nil {
+			t.Fatalf("Encrypt() unexpected error: %v", err)
+		}
+		sign := func([]byte) ([]byte, error) { return make([]byte, 64), nil }
+		signErr := errors.New("signer failed")
+
+		tests := []struct {
+			name  string
+			data  []byte
+			field SignatureField
+			sign  SignFunc
+			want  error
+		}{
+			{"signature too large", skiaPDF(), SignatureField{Size: 32}, sign, ErrSignatureSize},
+			{"page out of range", skiaPDF(), SignatureField{Page: 3}, sign, ErrObjectNotFound},
+			{"encrypted input", encrypted, SignatureField{}, sign, ErrUnsupported},
+			{"signer error", skiaPDF(), SignatureField{}, func([]byte) ([]byte, error) { return nil, signErr }, signErr},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				if _, err := Sign(tt.data, tt.field, tt.sign); !errors.Is(err, tt.want) {
+					t.Errorf("Sign() error = %v, want %v", err, tt.want)
+				}
+			})
+		}
+	})
+}
+
+// ---------------------------------------------------------------------------
+// TestSignatures - Reading signature fields
+// ---------------------------------------------------------------------------
+
+func TestSignatures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    int
+		wantErr error
+	}{
+		{
+			name: "no form",
+			data: skiaPDF(),
+			want: 0,
+		},
+		{
+			name: "unsigned field is skipped",
+			data: buildPDF("",
+				"<</Type /Catalog /Pages 2 0 R /AcroForm <</Fields [4 0 R]>>>>",
+				"<</Type /Pages /Count 1 /Kids [3 0 R]>>",
+				"<</Type /Page /Parent 2 0 R /MediaBox [0 0 612 792]>>",
+				"<</FT /Sig /T (Empty)>>",
+			),
+			want: 0,
+		},
+		{
+			name: "byte range past end of file",
+			data: buildPDF("",
+				"<</Type /Catalog /Pages 2 0 R /AcroForm <</Fields [4 0 R]>>>>",
+				"<</Type /Pages /Count 1 /Kids [3 0 R]>>",
+				"<</Type /Page /Parent 2 0 R /MediaBox [0 0 612 792]>>",
+				"<</FT /Sig /T (Bad) /V <</Contents <3000> /ByteRange [0 10 20 999999]>>>>",
+			),
+			wantErr: ErrMalformed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			doc, err := Open(tt.data)
+			if err != nil {
+				t.Fatalf("Open() unexpected error: %v", err)
+			}
+			sigs, err := doc.Signatures()
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Signatures() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Signatures() unexpected error: %v", err)
+			}
+			if len(sigs) != tt.want {
+				t.Errorf("Signatures() returned %d, want %d", len(sigs), tt.want)
+			}
+		})
+	}
+}
+
+// equalArray compares arrays of scalars.
+func equalArray(got Object, want Array) bool {
+	arr, ok := got.(Array)
+	if !ok || len(arr) != len(want) {
+		return false
+	}
+	for i := range arr {
+		if arr[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,270 @@
+package pdfedit
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+)
+
+// maxStreamSize bounds decoded content streams to reject hostile input.
+const maxStreamSize = 64 << 20
+
+// PageImporter copies pages of another document into an update as form
+// XObjects. Objects shared between pages, such as fonts, are copied once.
+type PageImporter struct {
+	c objectCopier
+}
+
+// NewPageImporter returns an importer copying pages of src into u.
+func NewPageImporter(u *Update, src *Document) *PageImporter {
+	return &PageImporter{c: objectCopier{u: u, src: src, refs: make(map[Ref]Ref)}}
+}
+
+// Import copies page as a form XObject, with every resource it uses, and
+// returns the form's reference. Draw it with StampPage.
+func (p *PageImporter) Import(page Ref) (Ref, error) {
+	src := p.c.src
+	dict, err := src.ResolveDict(page)
+	if err != nil {
+		return Ref{}, err
+	}
+	content, err := pageContent(src, dict["Contents"])
+	if err != nil {
+		return Ref{}, fmt.Errorf("reading page content: %w", err)
+	}
+
+	resources, err := p.c.copy(inherited(src, dict, "Resources"), 0)
+	if err != nil {
+		return Ref{}, fmt.Errorf("copying page resources: %w", err)
+	}
+	box, err := src.Resolve(inherited(src, dict, "MediaBox"))
+	if err != nil {
+		return Ref{}, err
+	}
+	if _, ok := box.(Array); !ok {
+		return Ref{}, fmt.Errorf("%w: page without /MediaBox", ErrMalformed)
+	}
+
+	form := Dict{
+		"Type":    Name("XObject"),
+		"Subtype": Name("Form"),
+		"BBox":    box,
+	}
+	if resources != nil {
+		form["Resources"] = resources
+	}
+	return p.c.u.Add(&Stream{Dict: form, Data: content}), nil
+}
+
+// StampPage draws form over the content of page. The page content is
+// wrapped in q/Q so its graphics state cannot leak into the stamp, and the
+// stamp is marked as an artifact for tagged documents.
+func StampPage(u *Update, page Ref, form Ref) error {
+	obj, err := u.Object(page)
+	if err != nil {
+		return err
+	}
+	dict, ok := obj.(Dict)
+	if !ok {
+		return fmt.Errorf("%w: page is not a dictionary", ErrMalformed)
+	}
+	dict = dict.Clone()
+
+	resources := Dict{}
+	if obj := inherited(u.doc, dict, "Resources"); obj != nil {
+		if resources, err = u.doc.ResolveDict(obj); err != nil {
+			return err
+		}
+		resources = resources.Clone()
+	}
+	xobjects := Dict{}
+	if existing, ok := resources["XObject"]; ok {
+		if xobjects, err = u.doc.ResolveDict(existing); err != nil {
+			return err
+		}
+		xobjects = xobjects.Clone()
+	}
+	name := Name(fmt.Sprintf("PLStamp%d", form.Num))
+	xobjects[name] = form
+	resources["XObject"] = xobjects
+	dict["Resources"] = resources
+
+	contents := Array{u.Add(&Stream{Dict: Dict{}, Data: []byte("q\n")})}
+	switch existing := dict["Contents"].(type) {
+	case Array:
+		contents = append(contents, existing...)
+	case nil:
+	default:
+		contents = append(contents, existing)
+	}
+	var stamp bytes.Buffer
+	stamp.WriteString("\nQ\n/Artifact BMC\nq\n")
+	writeName(&stamp, name)
+	stamp.WriteString(" Do\nQ\nEMC\n")
+	contents = append(contents, u.Add(&Stream{Dict: Dict{}, Data: stamp.Bytes()}))
+	dict["Contents"] = contents
+
+	u.Set(page, dict)
+	return nil
+}
+
+// inherited returns the page attribute key, looking it up in the ancestors
+// of page when the page itself does not set it.
+func inherited(d *Document, page Dict, key Name) Object {
+	node := page
+	for depth := 0; depth <= maxNesting; depth++ {
+		if value, ok := node[key]; ok {
+			return value
+		}
+		parent, err := d.ResolveDict(node["Parent"])
+		if err != nil {
+			return nil
+		}
+		node = parent
+	}
+	return nil
+}
+
+// pageContent returns the decoded content of a page. Arrays of streams are
+// joined with whitespace, as they are when drawn.
+func pageContent(d *Document, contents Object) ([]byte, error) {
+	obj, err := d.Resolve(contents)
+	if err != nil {
+		return nil, err
+	}
+	var parts []Object
+	switch v := obj.(type) {
+	case nil:
+		return nil, nil
+	case *Stream:
+		parts = []Object{v}
+	case Array:
+		parts = v
+	default:
+		return nil, fmt.Errorf("%w: page /Contents is %T", ErrMalformed, obj)
+	}
+
+	var buf bytes.Buffer
+	for _, part := range parts {
+		resolved, err := d.Resolve(part)
+		if err != nil {
+			return nil, err
+		}
+		stream, ok := resolved.(*Stream)
+		if !ok {
+			return nil, fmt.Errorf("%w: page content is not a stream", ErrMalformed)
+		}
+		data, err := decodeStream(stream)
+		if err != nil {
+			return nil, err
+		}
+		if buf.Len() > 0 {
+			buf.WriteByte('\n')
+		}
+		buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeStream returns the data of an unfiltered or FlateDecode stream.
+func decodeStream(s *Stream) ([]byte, error) {
+	switch filter := s.Dict["Filter"].(type) {
+	case nil:
+		return s.Data, nil
+	case Name:
+		if filter == "FlateDecode" {
+			break
+		}
+		return nil, fmt.Errorf("%w: stream filter %s", ErrUnsupported, filter)
+	case Array:
+		if len(filter) == 1 && filter[0] == Name("FlateDecode") {
+			break
+		}
+		return nil, fmt.Errorf("%w: stream filters %v", ErrUnsupported, filter)
+	default:
+		return nil, fmt.Errorf("%w: invalid stream filter", ErrMalformed)
+	}
+	if _, ok := s.Dict["DecodeParms"]; ok {
+		return nil, fmt.Errorf("%w: stream decode parameters", ErrUnsupported)
+	}
+
+	r, err := zlib.NewReader(bytes.NewReader(s.Data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformed, err)
+	}
+	defer func() { _ = r.Close() }()
+	data, err := io.ReadAll(io.LimitReader(r, maxStreamSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformed, err)
+	}
+	if len(data) > maxStreamSize {
+		return nil, fmt.Errorf("%w: stream larger than %d bytes", ErrUnsupported, maxStreamSize)
+	}
+	return data, nil
+}
+
+// objectCopier copies objects of another document into an update,
+// renumbering indirect objects and copying each of them once.
+type objectCopier struct {
+	u    *Update
+	src  *Document
+	refs map[Ref]Ref
+}
+
+// copy returns obj with every reference it reaches copied into the update.
+func (c *objectCopier) copy(obj Object, depth int) (Object, error) {
+	if depth > maxNesting {
+		return nil, fmt.Errorf("%w: objects nested too deep", ErrMalformed)
+	}
+	switch v := obj.(type) {
+	case Ref:
+		if ref, ok := c.refs[v]; ok {
+			return ref, nil
+		}
+		ref := c.u.Reserve()
+		c.refs[v] = ref
+		resolved, err := c.src.Object(v)
+		if err != nil {
+			return nil, err
+		}
+		copied, err := c.copy(resolved, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		c.u.Set(ref, copied)
+		return ref, nil
+	case Dict:
+		out := make(Dict, len(v))
+		for k, item := range v {
+			if k == "Parent" {
+				continue
+			}
+			copied, err := c.copy(item, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = copied
+		}
+		return out, nil
+	case Array:
+		out := make(Array, len(v))
+		for i, item := range v {
+			copied, err := c.copy(item, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = copied
+		}
+		return out, nil
+	case *Stream:
+		streamDict := v.Dict.Clone()
+		delete(streamDict, "Length") // Rewritten from the data
+		dict, err := c.copy(streamDict, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		return &Stream{Dict: dict.(Dict), Data: v.Data}, nil
+	}
+	return obj, nil
+}
@@ -0,0 +1,225 @@
+package pdfedit
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// stampSheetPDF returns a one-page PDF shaped like a Chrome stamp sheet: a
+// compressed content stream drawing text with an embedded font resource.
+func stampSheetPDF(t *testing.T) []byte {
+	t.Helper()
+
+	var content bytes.Buffer
+	zw := zlib.NewWriter(&content)
+	_, _ = zw.Write([]byte("BT /F1 10 Tf 72 20 Td (3/12) Tj ET"))
+	_ = zw.Close()
+
+	return buildPDF("",
+		"<</Type /Catalog /Pages 2 0 R>>",
+		"<</Type /Pages /Count 1 /Kids [3 0 R] /MediaBox [0 0 612 792]>>",
+		"<</Type /Page /Parent 2 0 R /Resources <</Font <</F1 5 0 R>>>> /Contents 4 0 R>>",
+		fmt.Sprintf("<</Length %d /Filter /FlateDecode>>\nstream\n%s\nendstream", content.Len(), content.Bytes()),
+		"<</Type /Font /Subtype /TrueType /BaseFont /AAAAAA+Sans /FontDescriptor 6 0 R>>",
+		"<</Type /FontDescriptor /FontName /AAAAAA+Sans /FontFile2 7 0 R>>",
+		"<</Length 4>>\nstream\nfont\nendstream",
+	)
+}
+
+// ---------------------------------------------------------------------------
+// TestPageImporter - Copying pages as form XObjects
+// ---------------------------------------------------------------------------
+
+func TestPageImporter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("copies decoded content and resources", func(t *testing.T) {
+		t.Parallel()
+
+		src, err := Open(stampSheetPDF(t))
+		if err != nil {
+			t.Fatalf("Open(sheet) unexpected error: %v", err)
+		}
+		doc, err := Open(skiaPDF())
+		if err != nil {
+			t.Fatalf("Open() unexpected error: %v", err)
+		}
+		u := doc.NewUpdate()
+		importer := NewPageImporter(u, src)
+		formRef, err := importer.Import(Ref{Num: 3})
+		if err != nil {
+			t.Fatalf("Import() unexpected error: %v", err)
+		}
+		again, err := importer.Import(Ref{Num: 3})
+		if err != nil {
+			t.Fatalf("Import() again unexpected error: %v", err)
+		}
+
+		out, err := Open(u.Bytes())
+		if err != nil {
+			t.Fatalf("Open(updated) unexpected error: %v", err)
+		}
+		obj, err := out.Object(formRef)
+		if err != nil {
+			t.Fatalf("Object(form) unexpected error: %v", err)
+		}
+		form, ok := obj.(*Stream)
+		if !ok {
+			t.Fatalf("form = %T, want *Stream", obj)
+		}
+		if form.Dict["Subtype"] != Name("Form") || form.Dict["Filter"] != nil {
+			t.Errorf("form dict = %v, want unfiltered /Form", form.Dict)
+		}
+		if !strings.Contains(string(form.Data), "(3/12) Tj") {
+			t.Errorf("form data = %q, want decoded page content", form.Data)
+		}
+		if got := fmt.Sprint(form.Dict["BBox"]); got != fmt.Sprint(Array{int64(0), int64(0), int64(612), int64(792)}) {
+			t.Errorf("form /BBox = %s, want inherited media box", got)
+		}
+
+		resources, err := out.ResolveDict(form.Dict["Resources"])
+		if err != nil {
+			t.Fatalf("form resources unexpected error: %v", err)
+		}
+		fonts, _ := resources["Font"].(Dict)
+		font, err := out.ResolveDict(fonts["F1"])
+		if err != nil {
+			t.Fatalf("copied font unexpected error: %v", err)
+		}
+		descriptor, err := out.ResolveDict(font["FontDescriptor"])
+		if err != nil {
+			t.Fatalf("copied font descriptor unexpected error: %v", err)
+		}
+		file, err := out.Resolve(descriptor["FontFile2"])
+		if err != nil {
+			t.Fatalf("copied font file unexpected error: %v", err)
+		}
+		if s, ok := file.(*Stream); !ok || string(s.Data) != "font" {
+			t.Errorf("copied font file = %v, want embedded font stream", file)
+		}
+
+		obj, err = out.Object(again)
+		if err != nil {
+			t.Fatalf("Object(second form) unexpected error: %v", err)
+		}
+		if got := obj.(*Stream).Dict["Resources"]; fmt.Sprint(got) != fmt.Sprint(form.Dict["Resources"]) {
+			t.Errorf("second import resources = %v, want shared %v", got, form.Dict["Resources"])
+		}
+	})
+
+	t.Run("unsupported filter returns ErrUnsupported", func(t *testing.T) {
+		t.Parallel()
+
+		src, err := Open(buildPDF("",
+			"<</Type /Catalog /Pages 2 0 R>>",
+			"<</Type /Pages /Count 1 /Kids [3 0 R]>>",
+			"<</Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R>>",
+			"<</Length 2 /Filter /LZWDecode>>\nstream\nxx\nendstream",
+		))
+		if err != nil {
+			t.Fatalf("Open() unexpected error: %v", err)
+		}
+		u := src.NewUpdate()
+		if _, err := NewPageImporter(u, src).Import(Ref{Num: 3}); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("Import() error = %v, want %v", err, ErrUnsupported)
+		}
+	})
+}
+
+// ---------------------------------------------------------------------------
+// TestStampPage - Drawing a form XObject over a page
+// ---------------------------------------------------------------------------
+
+func TestStampPage(t *testing.T) {
+	t.Parallel()
+
+	doc, err := Open(skiaPDF())
+	if err != nil {
+		t.Fatalf("Open() unexpected error: %v", err)
+	}
+	u := doc.NewUpdate()
+	form := u.Add(&Stream{Dict: Dict{"Type": Name("XObject"), "Subtype": Name("Form")}})
+	if err := StampPage(u, Ref{Num: 3}, form); err != nil {
+		t.Fatalf("StampPage() unexpected error: %v", err)
+	}
+
+	out, err := Open(u.Bytes())
+	if err != nil {
+		t.Fatalf("Open(updated) unexpected error: %v", err)
+	}
+	page, err := out.ResolveDict(Ref{Num: 3})
+	if err != nil {
+		t.Fatalf("page unexpected error: %v", err)
+	}
+
+	contents, ok := page["Contents"].(Array)
+	if !ok || len(contents) != 3 || contents[1] != (Ref{Num: 5}) {
+		t.Fatalf("page /Contents = %v, want [q, original, stamp]", page["Contents"])
+	}
+	var drawn []string
+	for _, ref := range contents {
+		obj, err := out.Resolve(ref)
+		if err != nil {
+			t.Fatalf("content stream unexpected error: %v", err)
+		}
+		drawn = append(drawn, string(obj.(*Stream).Data))
+	}
+	joined := strings.Join(drawn, "")
+	name := fmt.Sprintf("/PLStamp%d Do", form.Num)
+	if !strings.HasPrefix(joined, "q\n") || !strings.Contains(joined, "Q\n/Artifact BMC\nq\n"+name) {
+		t.Errorf("page content = %q, want original wrapped in q/Q, then %s", joined, name)
+	}
+
+	resources, _ := page["Resources"].(Dict)
+	xobjects, _ := resources["XObject"].(Dict)
+	if xobjects[Name(name[1:len(name)-3])] != form {
+		t.Errorf("page /Resources = %v, want the form in /XObject", page["Resources"])
+	}
+
+	other, err := out.ResolveDict(Ref{Num: 4})
+	if err != nil {
+		t.Fatalf("page 2 unexpected error: %v", err)
+	}
+	if other["Contents"] != (Ref{Num: 5}) {
+		t.Errorf("unstamped page /Contents = %v, want unchanged", other["Contents"])
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestPageSize - Media box dimensions
+// ---------------------------------------------------------------------------
+
+func TestPageSize(t *testing.T) {
+	t.Parallel()
+
+	doc, err := Open(buildPDF("",
+		"<</Type /Catalog /Pages 2 0 R>>",
+		"<</Type /Pages /Count 2 /Kids [3 0 R 4 0 R] /MediaBox [0 0 612 792]>>",
+		"<</Type /Page /Parent 2 0 R>>",
+		"<</Type /Page /Parent 2 0 R /MediaBox [0 0 841.89 595.28]>>",
+	))
+	if err != nil {
+		t.Fatalf("Open() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		page          Ref
+		width, height float64
+	}{
+		{Ref{Num: 3}, 612, 792},
+		{Ref{Num: 4}, 841.89, 595.28},
+	}
+	for _, tt := range tests {
+		w, h, err := doc.PageSize(tt.page)
+		if err != nil {
+			t.Fatalf("PageSize(%v) unexpected error: %v", tt.page, err)
+		}
+		if w != tt.width || h != tt.height {
+			t.Errorf("PageSize(%v) = %v x %v, want %v x %v", tt.page, w, h, tt.width, tt.height)
+		}
+	}
+}
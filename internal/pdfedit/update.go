@@ -0,0 +1,132 @@
+package pdfedit
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+// Update collects objects for one incremental update of a Document.
+type Update struct {
+	doc     *Document
+	objects map[int]Object
+	nextNum int
+	trailer Dict
+}
+
+// NewUpdate starts an incremental update.
+func (d *Document) NewUpdate() *Update {
+	return &Update{
+		doc:     d,
+		objects: make(map[int]Object),
+		nextNum: d.size,
+		trailer: Dict{},
+	}
+}
+
+// Add appends a new object and returns its reference.
+func (u *Update) Add(obj Object) Ref {
+	ref := Ref{Num: u.nextNum}
+	u.nextNum++
+	u.objects[ref.Num] = obj
+	return ref
+}
+
+// Reserve allocates a reference for an object set later with Set, so
+// objects can refer to each other before they are built.
+func (u *Update) Reserve() Ref {
+	return u.Add(nil)
+}
+
+// Set replaces the object at ref.
+func (u *Update) Set(ref Ref, obj Object) {
+	u.objects[ref.Num] = obj
+}
+
+// Catalog returns a copy of the catalog as modified so far in this update,
+// so several edits to the catalog can be combined before Set.
+func (u *Update) Catalog() (Dict, Ref, error) {
+	ref, _ := u.doc.trailer.Ref("Root")
+	if obj, ok := u.objects[ref.Num].(Dict); ok {
+		return obj.Clone(), ref, nil
+	}
+	catalog, ref, err := u.doc.Catalog()
+	if err != nil {
+		return nil, Ref{}, err
+	}
+	return catalog.Clone(), ref, nil
+}
+
+// Object returns the object at ref as modified so far in this update.
+func (u *Update) Object(ref Ref) (Object, error) {
+	if obj, ok := u.objects[ref.Num]; ok {
+		return obj, nil
+	}
+	return u.doc.Object(ref)
+}
+
+// Document returns the document being updated.
+func (u *Update) Document() *Document {
+	return u.doc
+}
+
+// SetTrailer sets a trailer entry (e.g. /Info) in the new revision.
+func (u *Update) SetTrailer(key Name, value Object) {
+	u.trailer[key] = value
+}
+
+// Bytes returns the original PDF followed by the update.
+// Returns the original bytes unchanged if nothing was added.
+func (u *Update) Bytes() []byte {
+	if len(u.objects) == 0 && len(u.trailer) == 0 {
+		return u.doc.data
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(len(u.doc.data) + 4096)
+	buf.Write(u.doc.data)
+	if !bytes.HasSuffix(u.doc.data, []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+
+	nums := make([]int, 0, len(u.objects))
+	for num := range u.objects {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+
+	offsets := make(map[int]int, len(nums))
+	for _, num := range nums {
+		offsets[num] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n", num)
+		writeObject(&buf, u.objects[num])
+		buf.WriteString("\nendobj\n")
+	}
+
+	xrefOffset := buf.Len()
+	buf.WriteString("xref\n")
+	for i := 0; i < len(nums); {
+		j := i
+		for j+1 < len(nums) && nums[j+1] == nums[j]+1 {
+			j++
+		}
+		fmt.Fprintf(&buf, "%d %d\n", nums[i], j-i+1)
+		for _, num := range nums[i : j+1] {
+			fmt.Fprintf(&buf, "%010d 00000 n \n", offsets[num])
+		}
+		i = j + 1
+	}
+
+	trailer := u.doc.trailer.Clone()
+	delete(trailer, "XRefStm")
+	for k, v := range u.trailer {
+		trailer[k] = v
+	}
+	trailer["Size"] = int64(max(u.nextNum, u.doc.size))
+	trailer["Prev"] = u.doc.startXref
+
+	buf.WriteString("trailer\n")
+	writeDict(&buf, trailer)
+	fmt.Fprintf(&buf, "\nstartxref\n%d\n%%%%EOF\n", xrefOffset)
+	return buf.Bytes()
+}
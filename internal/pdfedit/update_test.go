@@ -0,0 +1,99 @@
+package pdfedit
+
+import (
+	"bytes"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// TestUpdate - Incremental updates
+// ---------------------------------------------------------------------------
+
+func TestUpdate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("appends objects readable after reopening", func(t *testing.T) {
+		t.Parallel()
+
+		original := skiaPDF()
+		doc, err := Open(original)
+		if err != nil {
+			t.Fatalf("Open() unexpected error: %v", err)
+		}
+
+		u := doc.NewUpdate()
+		added := u.Add(Dict{"Type": Name("Test"), "Text": String("a (b)")})
+		catalog, ref, err := u.Catalog()
+		if err != nil {
+			t.Fatalf("Catalog() unexpected error: %v", err)
+		}
+		catalog["Test"] = added
+		u.Set(ref, catalog)
+		out := u.Bytes()
+
+		if !bytes.HasPrefix(out, original) {
+			t.Error("Bytes() rewrote the original revision")
+		}
+
+		updated, err := Open(out)
+		if err != nil {
+			t.Fatalf("Open(updated) unexpected error: %v", err)
+		}
+		newCatalog, _, err := updated.Catalog()
+		if err != nil {
+			t.Fatalf("Catalog() unexpected error: %v", err)
+		}
+		if newCatalog["Test"] != added || newCatalog["Pages"] != (Ref{Num: 2}) {
+			t.Errorf("updated catalog = %v, want Test %v and original Pages", newCatalog, added)
+		}
+		obj, err := updated.Object(added)
+		if err != nil {
+			t.Fatalf("Object(%v) unexpected error: %v", added, err)
+		}
+		if got := obj.(Dict)["Text"]; got != String("a (b)") {
+			t.Errorf("added Text = %q, want %q", got, "a (b)")
+		}
+		if size, _ := updated.Trailer().Int("Size"); size != int64(added.Num+1) {
+			t.Errorf("trailer Size = %d, want %d", size, added.Num+1)
+		}
+		if _, _, ok, _ := updated.Info(); !ok {
+			t.Error("updated trailer lost /Info")
+		}
+	})
+
+	t.Run("no changes returns original bytes", func(t *testing.T) {
+		t.Parallel()
+
+		original := skiaPDF()
+		doc, err := Open(original)
+		if err != nil {
+			t.Fatalf("Open() unexpected error: %v", err)
+		}
+		if out := doc.NewUpdate().Bytes(); !bytes.Equal(out, original) {
+			t.Error("Bytes() changed an unmodified document")
+		}
+	})
+
+	t.Run("streams round trip with length", func(t *testing.T) {
+		t.Parallel()
+
+		doc, err := Open(skiaPDF())
+		if err != nil {
+			t.Fatalf("Open() unexpected error: %v", err)
+		}
+		u := doc.NewUpdate()
+		ref := u.Add(&Stream{Dict: Dict{"Type": Name("Metadata")}, Data: []byte("<x>endstream</x>")})
+
+		updated, err := Open(u.Bytes())
+		if err != nil {
+			t.Fatalf("Open(updated) unexpected error: %v", err)
+		}
+		obj, err := updated.Object(ref)
+		if err != nil {
+			t.Fatalf("Object(%v) unexpected error: %v", ref, err)
+		}
+		if s, ok := obj.(*Stream); !ok || string(s.Data) != "<x>endstream</x>" {
+			t.Errorf("Object(%v) = %#v, want stream data preserved", ref, obj)
+		}
+	})
+}
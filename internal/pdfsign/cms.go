@@ -0,0 +1,235 @@
+package pdfsign
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// CMS content types, attributes and algorithms (RFC 5652, RFC 5035,
+// RFC 3161, RFC 5754, RFC 5758).
+var (
+	oidSignedData           = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	oidTSTInfo              = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 1, 4}
+	oidAttrContentType      = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 3}
+	oidAttrMessageDigest    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+	oidAttrSigningCertV2    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 2, 47}
+	oidAttrTimeStampToken   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 2, 14}
+	oidSHA1                 = asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}
+	oidSHA256               = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidSHA384               = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 2}
+	oidSHA512               = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 3}
+	oidRSAEncryption        = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 1}
+	oidSHA1WithRSA          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 5}
+	oidSHA256WithRSA        = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 11}
+	oidSHA384WithRSA        = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 12}
+	oidSHA512WithRSA        = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 13}
+	oidECDSAWithSHA256      = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}
+	oidECDSAWithSHA384      = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 3}
+	oidECDSAWithSHA512      = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 4}
+	oidECPublicKey          = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidECDSAWithSHA1        = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 1}
+	sha256AlgorithmID       = pkix.AlgorithmIdentifier{Algorithm: oidSHA256}
+	asn1NullParameters      = asn1.RawValue{Tag: asn1.TagNull}
+	rsaSignatureAlgorithmID = pkix.AlgorithmIdentifier{Algorithm: oidRSAEncryption, Parameters: asn1NullParameters}
+)
+
+// contentInfo is ContentInfo (RFC 5652, 3).
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"explicit,optional,tag:0"`
+}
+
+// signedData is SignedData (RFC 5652, 5.1). Certificates holds the
+// [0] IMPLICIT SET OF Certificate as raw DER.
+type signedData struct {
+	Version          int
+	DigestAlgorithms []pkix.AlgorithmIdentifier `asn1:"set"`
+	EncapContentInfo encapContentInfo
+	Certificates     asn1.RawValue `asn1:"optional,tag:0"`
+	CRLs             asn1.RawValue `asn1:"optional,tag:1"`
+	SignerInfos      []signerInfo  `asn1:"set"`
+}
+
+// encapContentInfo is EncapsulatedContentInfo (RFC 5652, 5.2). Detached
+// signatures leave EContent empty.
+type encapContentInfo struct {
+	EContentType asn1.ObjectIdentifier
+	EContent     asn1.RawValue `asn1:"explicit,optional,tag:0"`
+}
+
+// signerInfo is SignerInfo (RFC 5652, 5.3). SID is either an
+// IssuerAndSerialNumber or a [0] SubjectKeyIdentifier.
+type signerInfo struct {
+	Version            int
+	SID                asn1.RawValue
+	DigestAlgorithm    pkix.AlgorithmIdentifier
+	SignedAttrs        asn1.RawValue `asn1:"optional,tag:0"`
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          []byte
+	UnsignedAttrs      asn1.RawValue `asn1:"optional,tag:1"`
+}
+
+// issuerAndSerial is IssuerAndSerialNumber (RFC 5652, 10.2.4).
+type issuerAndSerial struct {
+	Issuer asn1.RawValue
+	Serial *big.Int
+}
+
+// attribute is Attribute (RFC 5652, 5.3). Values holds the SET OF values.
+type attribute struct {
+	Type   asn1.ObjectIdentifier
+	Values asn1.RawValue
+}
+
+// signingCertificateV2 is SigningCertificateV2 (RFC 5035, 3) naming the
+// signer certificate by its SHA-256 hash, the default algorithm.
+type signingCertificateV2 struct {
+	Certs []essCertIDv2
+}
+
+// essCertIDv2 is ESSCertIDv2 (RFC 5035, 4).
+type essCertIDv2 struct {
+	CertHash []byte
+}
+
+// Options configure Sign.
+type Options struct {
+	// TimestampURL is an RFC 3161 time-stamping server. When set, the
+	// signature carries a timestamp token proving when it existed.
+	TimestampURL string
+}
+
+// Sign returns a detached CMS SignedData over content, signed with creds
+// using SHA-256. Signed attributes bind the signer certificate, as CAdES
+// requires. The context bounds the timestamp request, if any.
+func Sign(ctx context.Context, content []byte, creds *Credentials, opts Options) ([]byte, error) {
+	digest := sha256.Sum256(content)
+	certHash := sha256.Sum256(creds.Certificate.Raw)
+
+	attrs, err := encodeAttributes(
+		attributeValue{oidAttrContentType, oidData},
+		attributeValue{oidAttrMessageDigest, digest[:]},
+		attributeValue{oidAttrSigningCertV2, signingCertificateV2{Certs: []essCertIDv2{{CertHash: certHash[:]}}}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	signatureAlg, err := signatureAlgorithm(creds.Key)
+	if err != nil {
+		return nil, err
+	}
+	signedAttrs, err := marshalSet(attrs)
+	if err != nil {
+		return nil, err
+	}
+	attrsDigest := sha256.Sum256(signedAttrs)
+	signature, err := creds.Key.Sign(rand.Reader, attrsDigest[:], crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("signing: %w", err)
+	}
+
+	sid, err := asn1.Marshal(issuerAndSerial{
+		Issuer: asn1.RawValue{FullBytes: creds.Certificate.RawIssuer},
+		Serial: creds.Certificate.SerialNumber,
+	})
+	if err != nil {
+		return nil, err
+	}
+	signer := signerInfo{
+		Version:            1,
+		SID:                asn1.RawValue{FullBytes: sid},
+		DigestAlgorithm:    sha256AlgorithmID,
+		SignedAttrs:        asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: attrs},
+		SignatureAlgorithm: signatureAlg,
+		Signature:          signature,
+	}
+
+	if opts.TimestampURL != "" {
+		token, err := requestTimestamp(ctx, opts.TimestampURL, signature)
+		if err != nil {
+			return nil, err
+		}
+		unsigned, err := encodeAttributes(attributeValue{oidAttrTimeStampToken, asn1.RawValue{FullBytes: token}})
+		if err != nil {
+			return nil, err
+		}
+		signer.UnsignedAttrs = asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 1, IsCompound: true, Bytes: unsigned}
+	}
+
+	var certs []byte
+	for _, cert := range append([]*x509.Certificate{creds.Certificate}, creds.Chain...) {
+		certs = append(certs, cert.Raw...)
+	}
+	sd := signedData{
+		Version:          1,
+		DigestAlgorithms: []pkix.AlgorithmIdentifier{sha256AlgorithmID},
+		EncapContentInfo: encapContentInfo{EContentType: oidData},
+		Certificates:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: certs},
+		SignerInfos:      []signerInfo{signer},
+	}
+	inner, err := asn1.Marshal(sd)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(contentInfo{
+		ContentType: oidSignedData,
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: inner},
+	})
+}
+
+// signatureAlgorithm identifies the SHA-256 signature key produces.
+func signatureAlgorithm(key crypto.Signer) (pkix.AlgorithmIdentifier, error) {
+	switch key.Public().(type) {
+	case *rsa.PublicKey:
+		return rsaSignatureAlgorithmID, nil
+	case *ecdsa.PublicKey:
+		return pkix.AlgorithmIdentifier{Algorithm: oidECDSAWithSHA256}, nil
+	default:
+		return pkix.AlgorithmIdentifier{}, fmt.Errorf("%w: %T keys", ErrUnsupported, key.Public())
+	}
+}
+
+// attributeValue is a single-valued attribute before encoding.
+type attributeValue struct {
+	typ   asn1.ObjectIdentifier
+	value any
+}
+
+// encodeAttributes returns the content of the SET OF attrs, sorted as DER
+// requires.
+func encodeAttributes(attrs ...attributeValue) ([]byte, error) {
+	encoded := make([][]byte, 0, len(attrs))
+	for _, a := range attrs {
+		der, err := asn1.Marshal(a.value)
+		if err != nil {
+			return nil, fmt.Errorf("encoding attribute %s: %w", a.typ, err)
+		}
+		set, err := marshalSet(der)
+		if err != nil {
+			return nil, err
+		}
+		attr, err := asn1.Marshal(attribute{Type: a.typ, Values: asn1.RawValue{FullBytes: set}})
+		if err != nil {
+			return nil, fmt.Errorf("encoding attribute %s: %w", a.typ, err)
+		}
+		encoded = append(encoded, attr)
+	}
+	sort.Slice(encoded, func(i, j int) bool { return bytes.Compare(encoded[i], encoded[j]) < 0 })
+	return bytes.Join(encoded, nil), nil
+}
+
+// marshalSet wraps DER-encoded elements in a SET.
+func marshalSet(content []byte) ([]byte, error) {
+	return asn1.Marshal(asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: content})
+}
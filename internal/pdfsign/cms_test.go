@@ -0,0 +1,207 @@
+package pdfsign
+
+// Notes:
+// - Certificates are generated per test: a root CA, an intermediate and a
+//   leaf, so chain building is exercised with the intermediate embedded
+// - Timestamps come from testTSA, an in-process RFC 3161 server whose
+//   tokens are signed by a certificate of the same root
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// testCert is a generated certificate and its key.
+type testCert struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+// newTestCert issues a certificate for cn, self-signed when parent is nil.
+func newTestCert(t *testing.T, cn string, parent *testCert, key crypto.Signer, isCA bool, usage ...x509.ExtKeyUsage) *testCert {
+	t.Helper()
+
+	if key == nil {
+		k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key = k
+	}
+	serial, _ := rand.Int(rand.Reader, big.NewInt(1<<62))
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           usage,
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+	if isCA {
+		tmpl.KeyUsage |= x509.KeyUsageCertSign
+	}
+	issuer, issuerKey := tmpl, key
+	if parent != nil {
+		issuer, issuerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, issuer, key.Public(), issuerKey)
+	if err != nil {
+		t.Fatalf("CreateCertificate(%s) unexpected error: %v", cn, err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCert{cert: cert, key: key}
+}
+
+// testPKI is a root, an intermediate and a signer issued by it.
+type testPKI struct {
+	root, intermediate, signer *testCert
+}
+
+func newTestPKI(t *testing.T, signerKey crypto.Signer) *testPKI {
+	t.Helper()
+
+	root := newTestCert(t, "Test Root", nil, nil, true)
+	intermediate := newTestCert(t, "Test Intermediate", root, nil, true)
+	signer := newTestCert(t, "Test Signer", intermediate, signerKey, false)
+	return &testPKI{root: root, intermediate: intermediate, signer: signer}
+}
+
+func (p *testPKI) credentials() *Credentials {
+	return &Credentials{Key: p.signer.key, Certificate: p.signer.cert, Chain: []*x509.Certificate{p.intermediate.cert}}
+}
+
+func (p *testPKI) roots() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(p.root.cert)
+	return pool
+}
+
+// ---------------------------------------------------------------------------
+// TestSignVerify - Detached CMS round trip
+// ---------------------------------------------------------------------------
+
+func TestSignVerify(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		key  crypto.Signer
+	}{
+		{"ECDSA P-256", nil},
+		{"RSA 2048", rsaKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pki := newTestPKI(t, tt.key)
+			content := []byte("%PDF-1.7 signed bytes")
+			sig, err := Sign(context.Background(), content, pki.credentials(), Options{})
+			if err != nil {
+				t.Fatalf("Sign() unexpected error: %v", err)
+			}
+
+			res, err := Verify(sig, content, VerifyOptions{Roots: pki.roots()})
+			if err != nil {
+				t.Fatalf("Verify() unexpected error: %v", err)
+			}
+			if !res.Signer.Equal(pki.signer.cert) {
+				t.Errorf("Signer = %s, want Test Signer", res.Signer.Subject)
+			}
+			if len(res.Certificates) != 2 {
+				t.Errorf("Certificates = %d, want signer and intermediate", len(res.Certificates))
+			}
+			if res.TrustError != nil {
+				t.Errorf("TrustError = %v, want nil", res.TrustError)
+			}
+			if !res.Timestamp.IsZero() {
+				t.Errorf("Timestamp = %v, want zero", res.Timestamp)
+			}
+		})
+	}
+
+	t.Run("modified content", func(t *testing.T) {
+		t.Parallel()
+
+		pki := newTestPKI(t, nil)
+		sig, err := Sign(context.Background(), []byte("original"), pki.credentials(), Options{})
+		if err != nil {
+			t.Fatalf("Sign() unexpected error: %v", err)
+		}
+		if _, err := Verify(sig, []byte("modified"), VerifyOptions{Roots: pki.roots()}); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("Verify() error = %v, want %v", err, ErrInvalidSignature)
+		}
+	})
+
+	t.Run("modified signature", func(t *testing.T) {
+		t.Parallel()
+
+		pki := newTestPKI(t, nil)
+		sig, err := Sign(context.Background(), []byte("content"), pki.credentials(), Options{})
+		if err != nil {
+			t.Fatalf("Sign() unexpected error: %v", err)
+		}
+		sig[len(sig)-5] ^= 0xff
+		if _, err := Verify(sig, []byte("content"), VerifyOptions{Roots: pki.roots()}); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("Verify() error = %v, want %v", err, ErrInvalidSignature)
+		}
+	})
+
+	t.Run("untrusted root is reported, not rejected", func(t *testing.T) {
+		t.Parallel()
+
+		pki := newTestPKI(t, nil)
+		sig, err := Sign(context.Background(), []byte("content"), pki.credentials(), Options{})
+		if err != nil {
+			t.Fatalf("Sign() unexpected error: %v", err)
+		}
+		res, err := Verify(sig, []byte("content"), VerifyOptions{Roots: x509.NewCertPool()})
+		if err != nil {
+			t.Fatalf("Verify() unexpected error: %v", err)
+		}
+		if res.TrustError == nil {
+			t.Error("TrustError = nil, want an unknown authority error")
+		}
+	})
+
+	t.Run("zero padding after the signature is ignored", func(t *testing.T) {
+		t.Parallel()
+
+		pki := newTestPKI(t, nil)
+		sig, err := Sign(context.Background(), []byte("content"), pki.credentials(), Options{})
+		if err != nil {
+			t.Fatalf("Sign() unexpected error: %v", err)
+		}
+		padded := append(sig, make([]byte, 256)...)
+		if _, err := Verify(padded, []byte("content"), VerifyOptions{Roots: pki.roots()}); err != nil {
+			t.Errorf("Verify(padded) unexpected error: %v", err)
+		}
+	})
+
+	t.Run("not a signature", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := Verify([]byte("garbage"), nil, VerifyOptions{}); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("Verify() error = %v, want %v", err, ErrInvalidSignature)
+		}
+	})
+}
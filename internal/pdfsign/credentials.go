@@ -0,0 +1,180 @@
+package pdfsign
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Sentinel errors for credential loading.
+var (
+	ErrMalformed         = errors.New("malformed signing credentials")
+	ErrUnsupported       = errors.New("unsupported signing credentials")
+	ErrNoCertificate     = errors.New("no certificate found")
+	ErrNoPrivateKey      = errors.New("no private key found")
+	ErrKeyMismatch       = errors.New("private key does not match any certificate")
+	ErrIncorrectPassword = errors.New("incorrect password")
+)
+
+// Credentials hold a signing key and the certificate that identifies it.
+type Credentials struct {
+	Key         crypto.Signer
+	Certificate *x509.Certificate
+	// Chain holds the other certificates found with the key, usually the
+	// issuing CAs. They are embedded so verifiers can build the path.
+	Chain []*x509.Certificate
+}
+
+// encryptedPrivateKeyInfo is EncryptedPrivateKeyInfo (RFC 5958, 3).
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+// LoadCredentials reads the signing certificate and key.
+//
+// certFile is a PKCS#12 archive (.p12, .pfx, or any non-PEM file) holding
+// both, or a PEM file of certificates. A PEM keyFile holds the private key;
+// when empty, the key is read from certFile. password decrypts the archive
+// or an encrypted PKCS#8 key and is ignored otherwise.
+//
+// The signing certificate is the one matching the key; the others are
+// returned as its chain.
+func LoadCredentials(certFile, keyFile, password string) (*Credentials, error) {
+	certData, err := os.ReadFile(certFile) // #nosec G304 -- user-provided path
+	if err != nil {
+		return nil, fmt.Errorf("reading certificate: %w", err)
+	}
+
+	ext := strings.ToLower(filepath.Ext(certFile))
+	if ext == ".p12" || ext == ".pfx" || !bytes.Contains(certData, []byte("-----BEGIN")) {
+		key, certs, err := decodePKCS12(certData, password)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", certFile, err)
+		}
+		return pairKey(key, certs)
+	}
+
+	certs, key, err := decodePEM(certData, password)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", certFile, err)
+	}
+	if keyFile != "" {
+		keyData, err := os.ReadFile(keyFile) // #nosec G304 -- user-provided path
+		if err != nil {
+			return nil, fmt.Errorf("reading private key: %w", err)
+		}
+		if _, key, err = decodePEM(keyData, password); err != nil {
+			return nil, fmt.Errorf("reading %s: %w", keyFile, err)
+		}
+	}
+	return pairKey(key, certs)
+}
+
+// decodePEM collects the certificates and the first private key of a PEM
+// file.
+func decodePEM(data []byte, password string) ([]*x509.Certificate, crypto.Signer, error) {
+	var (
+		certs []*x509.Certificate
+		key   crypto.Signer
+	)
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		switch block.Type {
+		case "CERTIFICATE":
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%w: %w", ErrMalformed, err)
+			}
+			certs = append(certs, cert)
+		case "PRIVATE KEY", "RSA PRIVATE KEY", "EC PRIVATE KEY", "ENCRYPTED PRIVATE KEY":
+			if key != nil {
+				continue
+			}
+			if _, ok := block.Headers["DEK-Info"]; ok {
+				return nil, nil, fmt.Errorf("%w: legacy PEM encryption, convert the key with \"openssl pkcs8 -topk8\"", ErrUnsupported)
+			}
+			der := block.Bytes
+			if block.Type == "ENCRYPTED PRIVATE KEY" {
+				var info encryptedPrivateKeyInfo
+				if _, err := asn1.Unmarshal(der, &info); err != nil {
+					return nil, nil, fmt.Errorf("%w: %w", ErrMalformed, err)
+				}
+				plain, err := decryptPBE(info.Algorithm, password, info.EncryptedData)
+				if err != nil {
+					return nil, nil, err
+				}
+				der = plain
+			}
+			k, err := parsePrivateKey(der)
+			if err != nil {
+				if block.Type == "ENCRYPTED PRIVATE KEY" {
+					return nil, nil, ErrIncorrectPassword
+				}
+				return nil, nil, err
+			}
+			key = k
+		}
+	}
+	return certs, key, nil
+}
+
+// parsePrivateKey parses a PKCS#8, PKCS#1 or SEC 1 private key usable for
+// signing.
+func parsePrivateKey(der []byte) (crypto.Signer, error) {
+	var (
+		key any
+		err error
+	)
+	if key, err = x509.ParsePKCS8PrivateKey(der); err != nil {
+		if key, err = x509.ParsePKCS1PrivateKey(der); err != nil {
+			if key, err = x509.ParseECPrivateKey(der); err != nil {
+				return nil, fmt.Errorf("%w: unrecognized private key", ErrMalformed)
+			}
+		}
+	}
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return k, nil
+	case *ecdsa.PrivateKey:
+		return k, nil
+	default:
+		return nil, fmt.Errorf("%w: %T keys (use RSA or ECDSA)", ErrUnsupported, key)
+	}
+}
+
+// pairKey finds the certificate whose public key matches key.
+func pairKey(key crypto.Signer, certs []*x509.Certificate) (*Credentials, error) {
+	if len(certs) == 0 {
+		return nil, ErrNoCertificate
+	}
+	if key == nil {
+		return nil, ErrNoPrivateKey
+	}
+	type equaler interface{ Equal(crypto.PublicKey) bool }
+	pub, ok := key.Public().(equaler)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T keys", ErrUnsupported, key)
+	}
+	for i, cert := range certs {
+		if pub.Equal(cert.PublicKey) {
+			chain := append(append([]*x509.Certificate{}, certs[:i]...), certs[i+1:]...)
+			return &Credentials{Key: key, Certificate: cert, Chain: chain}, nil
+		}
+	}
+	return nil, ErrKeyMismatch
+}
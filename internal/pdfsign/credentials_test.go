@@ -0,0 +1,211 @@
+package pdfsign
+
+// Notes:
+// - testdata holds one ECDSA key and self-signed certificate, exported by
+//   OpenSSL 3 as: an AES-256 PKCS#8 key (signer-enc.key), a PKCS#12 archive
+//   with PBES2/AES-256 and a SHA-256 MAC (signer-aes.p12), and a legacy
+//   archive with triple DES and a SHA-1 MAC (signer-3des.p12). The password
+//   is "test"
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writePEM writes PEM blocks to a file in dir and returns its path.
+func writePEM(t *testing.T, dir, name string, blocks ...*pem.Block) string {
+	t.Helper()
+
+	var data []byte
+	for _, b := range blocks {
+		data = append(data, pem.EncodeToMemory(b)...)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func certBlock(c *testCert) *pem.Block {
+	return &pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw}
+}
+
+func keyBlock(t *testing.T, c *testCert) *pem.Block {
+	t.Helper()
+
+	der, err := x509.MarshalPKCS8PrivateKey(c.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &pem.Block{Type: "PRIVATE KEY", Bytes: der}
+}
+
+// ---------------------------------------------------------------------------
+// TestLoadCredentials - PEM and PKCS#12 inputs
+// ---------------------------------------------------------------------------
+
+func TestLoadCredentials(t *testing.T) {
+	t.Parallel()
+
+	t.Run("PEM files", func(t *testing.T) {
+		t.Parallel()
+
+		pki := newTestPKI(t, nil)
+		dir := t.TempDir()
+		tests := []struct {
+			name     string
+			certFile string
+			keyFile  string
+		}{
+			{
+				name:     "separate key file",
+				certFile: writePEM(t, dir, "chain.pem", certBlock(pki.intermediate), certBlock(pki.signer)),
+				keyFile:  writePEM(t, dir, "key.pem", keyBlock(t, pki.signer)),
+			},
+			{
+				name:     "key in certificate file",
+				certFile: writePEM(t, dir, "all.pem", keyBlock(t, pki.signer), certBlock(pki.signer), certBlock(pki.intermediate)),
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				creds, err := LoadCredentials(tt.certFile, tt.keyFile, "")
+				if err != nil {
+					t.Fatalf("LoadCredentials() unexpected error: %v", err)
+				}
+				if !creds.Certificate.Equal(pki.signer.cert) {
+					t.Errorf("Certificate = %s, want the certificate matching the key", creds.Certificate.Subject)
+				}
+				if len(creds.Chain) != 1 || !creds.Chain[0].Equal(pki.intermediate.cert) {
+					t.Errorf("Chain = %d certificates, want the intermediate", len(creds.Chain))
+				}
+			})
+		}
+	})
+
+	t.Run("encrypted files", func(t *testing.T) {
+		t.Parallel()
+
+		want, err := os.ReadFile(filepath.Join("testdata", "signer.crt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		block, _ := pem.Decode(want)
+
+		tests := []struct {
+			name     string
+			certFile string
+			keyFile  string
+		}{
+			{"encrypted PKCS#8 key", "signer.crt", "signer-enc.key"},
+			{"PKCS#12 with AES", "signer-aes.p12", ""},
+			{"PKCS#12 with triple DES", "signer-3des.p12", ""},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				keyFile := tt.keyFile
+				if keyFile != "" {
+					keyFile = filepath.Join("testdata", keyFile)
+				}
+				creds, err := LoadCredentials(filepath.Join("testdata", tt.certFile), keyFile, "test")
+				if err != nil {
+					t.Fatalf("LoadCredentials() unexpected error: %v", err)
+				}
+				if string(creds.Certificate.Raw) != string(block.Bytes) {
+					t.Errorf("Certificate = %s, want testdata/signer.crt", creds.Certificate.Subject)
+				}
+
+				_, err = LoadCredentials(filepath.Join("testdata", tt.certFile), keyFile, "wrong")
+				if !errors.Is(err, ErrIncorrectPassword) {
+					t.Errorf("LoadCredentials(wrong password) error = %v, want %v", err, ErrIncorrectPassword)
+				}
+			})
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		pki := newTestPKI(t, nil)
+		other := newTestCert(t, "Other", nil, nil, false)
+		_, edKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		edDER, err := x509.MarshalPKCS8PrivateKey(edKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dir := t.TempDir()
+		legacy := keyBlock(t, pki.signer)
+		legacy.Type = "EC PRIVATE KEY"
+		legacy.Headers = map[string]string{"Proc-Type": "4,ENCRYPTED", "DEK-Info": "AES-256-CBC,00"}
+
+		tests := []struct {
+			name     string
+			certFile string
+			keyFile  string
+			want     error
+		}{
+			{
+				name:     "key for another certificate",
+				certFile: writePEM(t, dir, "other.pem", certBlock(other)),
+				keyFile:  writePEM(t, dir, "signer.key", keyBlock(t, pki.signer)),
+				want:     ErrKeyMismatch,
+			},
+			{
+				name:     "no key",
+				certFile: writePEM(t, dir, "cert.pem", certBlock(pki.signer)),
+				want:     ErrNoPrivateKey,
+			},
+			{
+				name:     "no certificate",
+				certFile: writePEM(t, dir, "key-only.pem", keyBlock(t, pki.signer)),
+				want:     ErrNoCertificate,
+			},
+			{
+				name:     "legacy PEM encryption",
+				certFile: writePEM(t, dir, "legacy.pem", certBlock(pki.signer), legacy),
+				want:     ErrUnsupported,
+			},
+			{
+				name:     "Ed25519 key",
+				certFile: writePEM(t, dir, "ed.pem", certBlock(pki.signer), &pem.Block{Type: "PRIVATE KEY", Bytes: edDER}),
+				want:     ErrUnsupported,
+			},
+			{
+				name:     "not PKCS#12",
+				certFile: filepath.Join(dir, "garbage.p12"),
+				want:     ErrMalformed,
+			},
+			{
+				name:     "missing file",
+				certFile: filepath.Join(dir, "missing.pem"),
+				want:     os.ErrNotExist,
+			},
+		}
+		if err := os.WriteFile(filepath.Join(dir, "garbage.p12"), []byte("garbage"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				if _, err := LoadCredentials(tt.certFile, tt.keyFile, ""); !errors.Is(err, tt.want) {
+					t.Errorf("LoadCredentials() error = %v, want %v", err, tt.want)
+				}
+			})
+		}
+	})
+}
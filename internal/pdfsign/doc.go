@@ -0,0 +1,20 @@
+// Package pdfsign creates and checks the CMS signatures embedded in signed
+// PDF files.
+//
+// # Scope
+//
+// LoadCredentials reads a certificate and private key from PEM files or a
+// PKCS#12 archive. Sign builds a detached CMS SignedData over the bytes a
+// PDF signature covers, in the CAdES form PAdES baseline signatures use,
+// and can add an RFC 3161 timestamp from a time-stamping server:
+//
+//	creds, err := pdfsign.LoadCredentials("signer.p12", "", password)
+//	der, err := pdfsign.Sign(ctx, signed, creds, pdfsign.Options{TimestampURL: url})
+//
+// Verify checks such a signature against the signed bytes and reports the
+// signer, the timestamp and whether the certificate chains to a trusted
+// root. Placing the signature in the PDF is left to internal/pdfedit.
+//
+// Only the standard library is used: PKCS#12 support covers the PBES2
+// (AES) and triple-DES schemes OpenSSL writes, not RC2.
+package pdfsign
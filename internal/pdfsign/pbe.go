@@ -0,0 +1,243 @@
+package pdfsign
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/pbkdf2"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"hash"
+	"unicode/utf16"
+)
+
+// Password-based encryption algorithms (PKCS#5, PKCS#12).
+var (
+	oidPBES2          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidPBEWithSHA3DES = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 1, 3}
+	oidPBEWithSHARC2  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 1, 6}
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHMACWithSHA384 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 10}
+	oidHMACWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+	oidAES128CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+	oidDESEDE3CBC     = asn1.ObjectIdentifier{1, 2, 840, 113549, 3, 7}
+)
+
+// maxPBEIterations bounds key derivation work to reject hostile input.
+const maxPBEIterations = 10_000_000
+
+// Key material identifiers of the PKCS#12 key derivation (RFC 7292, B.3).
+const (
+	pkcs12KeyMaterial byte = 1
+	pkcs12IVMaterial  byte = 2
+	pkcs12MACMaterial byte = 3
+)
+
+// pbes2Params is PBES2-params (RFC 8018, A.4).
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+// pbkdf2Params is PBKDF2-params (RFC 8018, A.2).
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                      `asn1:"optional"`
+	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// pbeParams is pkcs-12PbeParams (RFC 7292, C).
+type pbeParams struct {
+	Salt       []byte
+	Iterations int
+}
+
+// decryptPBE decrypts data encrypted with a password-based scheme and
+// removes the padding. A padding error usually means a wrong password.
+func decryptPBE(alg pkix.AlgorithmIdentifier, password string, data []byte) ([]byte, error) {
+	var (
+		block cipher.Block
+		iv    []byte
+		err   error
+	)
+	switch {
+	case alg.Algorithm.Equal(oidPBES2):
+		block, iv, err = pbes2Cipher(alg.Parameters.FullBytes, password)
+	case alg.Algorithm.Equal(oidPBEWithSHA3DES):
+		var params pbeParams
+		if _, err := asn1.Unmarshal(alg.Parameters.FullBytes, &params); err != nil {
+			return nil, fmt.Errorf("%w: PBE parameters: %w", ErrMalformed, err)
+		}
+		if params.Iterations < 1 || params.Iterations > maxPBEIterations {
+			return nil, fmt.Errorf("%w: %d PBE iterations", ErrMalformed, params.Iterations)
+		}
+		pw := bmpPassword(password)
+		key := pkcs12KDF(sha1.New, pw, params.Salt, pkcs12KeyMaterial, params.Iterations, 24)
+		iv = pkcs12KDF(sha1.New, pw, params.Salt, pkcs12IVMaterial, params.Iterations, des.BlockSize)
+		block, err = des.NewTripleDESCipher(key)
+	case alg.Algorithm.Equal(oidPBEWithSHARC2):
+		return nil, fmt.Errorf("%w: RC2 encryption, re-export with \"openssl pkcs12 -export -certpbe AES-256-CBC\"", ErrUnsupported)
+	default:
+		return nil, fmt.Errorf("%w: encryption algorithm %s", ErrUnsupported, alg.Algorithm)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 || len(data)%block.BlockSize() != 0 {
+		return nil, fmt.Errorf("%w: encrypted data is not a whole number of blocks", ErrMalformed)
+	}
+	out := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(out, data)
+	pad := int(out[len(out)-1])
+	if pad < 1 || pad > block.BlockSize() {
+		return nil, ErrIncorrectPassword
+	}
+	for _, b := range out[len(out)-pad:] {
+		if int(b) != pad {
+			return nil, ErrIncorrectPassword
+		}
+	}
+	return out[:len(out)-pad], nil
+}
+
+// pbes2Cipher derives the PBKDF2 key and returns the block cipher and IV
+// described by PBES2 parameters.
+func pbes2Cipher(raw []byte, password string) (cipher.Block, []byte, error) {
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(raw, &params); err != nil {
+		return nil, nil, fmt.Errorf("%w: PBES2 parameters: %w", ErrMalformed, err)
+	}
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+		return nil, nil, fmt.Errorf("%w: key derivation %s", ErrUnsupported, params.KeyDerivationFunc.Algorithm)
+	}
+	var kdf pbkdf2Params
+	if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdf); err != nil {
+		return nil, nil, fmt.Errorf("%w: PBKDF2 parameters: %w", ErrMalformed, err)
+	}
+	if kdf.IterationCount < 1 || kdf.IterationCount > maxPBEIterations {
+		return nil, nil, fmt.Errorf("%w: %d PBKDF2 iterations", ErrMalformed, kdf.IterationCount)
+	}
+	prf := sha1.New
+	switch alg := kdf.PRF.Algorithm; {
+	case len(alg) == 0 || alg.Equal(oidHMACWithSHA1):
+	case alg.Equal(oidHMACWithSHA256):
+		prf = sha256.New
+	case alg.Equal(oidHMACWithSHA384):
+		prf = sha512.New384
+	case alg.Equal(oidHMACWithSHA512):
+		prf = sha512.New
+	default:
+		return nil, nil, fmt.Errorf("%w: PBKDF2 function %s", ErrUnsupported, alg)
+	}
+
+	scheme := params.EncryptionScheme
+	var keyLen int
+	switch {
+	case scheme.Algorithm.Equal(oidAES128CBC):
+		keyLen = 16
+	case scheme.Algorithm.Equal(oidAES192CBC):
+		keyLen = 24
+	case scheme.Algorithm.Equal(oidAES256CBC):
+		keyLen = 32
+	case scheme.Algorithm.Equal(oidDESEDE3CBC):
+		keyLen = 24
+	default:
+		return nil, nil, fmt.Errorf("%w: encryption algorithm %s", ErrUnsupported, scheme.Algorithm)
+	}
+	var iv []byte
+	if _, err := asn1.Unmarshal(scheme.Parameters.FullBytes, &iv); err != nil {
+		return nil, nil, fmt.Errorf("%w: cipher IV: %w", ErrMalformed, err)
+	}
+
+	key, err := pbkdf2.Key(prf, password, kdf.Salt, kdf.IterationCount, keyLen)
+	if err != nil {
+		return nil, nil, err
+	}
+	var block cipher.Block
+	if scheme.Algorithm.Equal(oidDESEDE3CBC) {
+		block, err = des.NewTripleDESCipher(key)
+	} else {
+		block, err = aes.NewCipher(key)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(iv) != block.BlockSize() {
+		return nil, nil, fmt.Errorf("%w: IV is %d bytes, want %d", ErrMalformed, len(iv), block.BlockSize())
+	}
+	return block, iv, nil
+}
+
+// bmpPassword encodes a password for the PKCS#12 key derivation: UTF-16BE
+// with a two-byte terminator (RFC 7292, B.1).
+func bmpPassword(password string) []byte {
+	units := utf16.Encode([]rune(password))
+	out := make([]byte, 0, 2*len(units)+2)
+	for _, u := range units {
+		out = append(out, byte(u>>8), byte(u))
+	}
+	return append(out, 0, 0)
+}
+
+// pkcs12KDF derives size bytes of key material with the PKCS#12 key
+// derivation function (RFC 7292, B.2). id selects keys, IVs or MAC keys.
+func pkcs12KDF(newHash func() hash.Hash, password, salt []byte, id byte, iterations, size int) []byte {
+	h := newHash()
+	u, v := h.Size(), h.BlockSize()
+
+	fill := func(src []byte) []byte {
+		if len(src) == 0 {
+			return nil
+		}
+		out := make([]byte, v*((len(src)+v-1)/v))
+		for i := range out {
+			out[i] = src[i%len(src)]
+		}
+		return out
+	}
+	d := make([]byte, v)
+	for i := range d {
+		d[i] = id
+	}
+	input := append(fill(salt), fill(password)...)
+
+	out := make([]byte, 0, size+u)
+	for len(out) < size {
+		h.Reset()
+		h.Write(d)
+		h.Write(input)
+		a := h.Sum(nil)
+		for range iterations - 1 {
+			h.Reset()
+			h.Write(a)
+			a = h.Sum(a[:0])
+		}
+		out = append(out, a...)
+
+		// Each v-byte block of input becomes (block + B + 1) mod 2^(8v),
+		// where B repeats a to v bytes.
+		b := make([]byte, v)
+		for i := range b {
+			b[i] = a[i%u]
+		}
+		for j := 0; j < len(input); j += v {
+			carry := 1
+			for k := v - 1; k >= 0; k-- {
+				sum := int(input[j+k]) + int(b[k]) + carry
+				input[j+k] = byte(sum)
+				carry = sum >> 8
+			}
+		}
+	}
+	return out[:size]
+}
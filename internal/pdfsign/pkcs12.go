@@ -0,0 +1,225 @@
+package pdfsign
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"hash"
+)
+
+// PKCS#12 content and bag types (RFC 7292).
+var (
+	oidData               = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidEncryptedData      = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 6}
+	oidKeyBag             = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 1}
+	oidShroudedKeyBag     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 2}
+	oidCertBag            = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 3}
+	oidX509CertificateBag = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 22, 1}
+)
+
+// pfx is PFX (RFC 7292, 4).
+type pfx struct {
+	Version  int
+	AuthSafe contentInfo
+	MacData  macData `asn1:"optional"`
+}
+
+// macData is MacData (RFC 7292, 4).
+type macData struct {
+	Mac        digestInfo
+	MacSalt    []byte
+	Iterations int `asn1:"optional,default:1"`
+}
+
+// digestInfo is DigestInfo (RFC 8017, 9.2).
+type digestInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	Digest    []byte
+}
+
+// encryptedData is EncryptedData (RFC 5652, 8) with its
+// EncryptedContentInfo inlined.
+type encryptedData struct {
+	Version          int
+	ContentInfo      encryptedContentInfo
+	UnprotectedAttrs asn1.RawValue `asn1:"optional,tag:1"`
+}
+
+// encryptedContentInfo is EncryptedContentInfo (RFC 5652, 6.1).
+type encryptedContentInfo struct {
+	ContentType      asn1.ObjectIdentifier
+	Algorithm        pkix.AlgorithmIdentifier
+	EncryptedContent asn1.RawValue `asn1:"optional,tag:0"`
+}
+
+// safeBag is SafeBag (RFC 7292, 4.2).
+type safeBag struct {
+	ID         asn1.ObjectIdentifier
+	Value      asn1.RawValue `asn1:"explicit,tag:0"`
+	Attributes asn1.RawValue `asn1:"optional,set"`
+}
+
+// certBag is CertBag (RFC 7292, 4.2.3).
+type certBag struct {
+	ID   asn1.ObjectIdentifier
+	Data []byte `asn1:"explicit,tag:0"`
+}
+
+// decodePKCS12 extracts the private key and certificates of a DER-encoded
+// PKCS#12 archive, checking its MAC with password.
+func decodePKCS12(data []byte, password string) (crypto.Signer, []*x509.Certificate, error) {
+	var archive pfx
+	if rest, err := asn1.Unmarshal(data, &archive); err != nil {
+		return nil, nil, fmt.Errorf("%w: PKCS#12: %w", ErrMalformed, err)
+	} else if len(rest) > 0 {
+		return nil, nil, fmt.Errorf("%w: PKCS#12: trailing data", ErrMalformed)
+	}
+	if archive.Version != 3 {
+		return nil, nil, fmt.Errorf("%w: PKCS#12 version %d", ErrUnsupported, archive.Version)
+	}
+	if !archive.AuthSafe.ContentType.Equal(oidData) {
+		return nil, nil, fmt.Errorf("%w: public-key protected PKCS#12", ErrUnsupported)
+	}
+	var authSafe []byte
+	if _, err := asn1.Unmarshal(archive.AuthSafe.Content.Bytes, &authSafe); err != nil {
+		return nil, nil, fmt.Errorf("%w: PKCS#12 content: %w", ErrMalformed, err)
+	}
+	if len(archive.MacData.Mac.Digest) > 0 {
+		if err := verifyMAC(archive.MacData, authSafe, password); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var contents []contentInfo
+	if _, err := asn1.Unmarshal(authSafe, &contents); err != nil {
+		return nil, nil, fmt.Errorf("%w: PKCS#12 safe: %w", ErrMalformed, err)
+	}
+	var (
+		key   crypto.Signer
+		certs []*x509.Certificate
+	)
+	for _, ci := range contents {
+		bags, err := safeBags(ci, password)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, bag := range bags {
+			switch {
+			case bag.ID.Equal(oidCertBag):
+				var cb certBag
+				if _, err := asn1.Unmarshal(bag.Value.Bytes, &cb); err != nil {
+					return nil, nil, fmt.Errorf("%w: certificate bag: %w", ErrMalformed, err)
+				}
+				if !cb.ID.Equal(oidX509CertificateBag) {
+					continue
+				}
+				cert, err := x509.ParseCertificate(cb.Data)
+				if err != nil {
+					return nil, nil, fmt.Errorf("%w: %w", ErrMalformed, err)
+				}
+				certs = append(certs, cert)
+			case bag.ID.Equal(oidKeyBag) && key == nil:
+				if key, err = parsePrivateKey(bag.Value.Bytes); err != nil {
+					return nil, nil, err
+				}
+			case bag.ID.Equal(oidShroudedKeyBag) && key == nil:
+				var info encryptedPrivateKeyInfo
+				if _, err := asn1.Unmarshal(bag.Value.Bytes, &info); err != nil {
+					return nil, nil, fmt.Errorf("%w: key bag: %w", ErrMalformed, err)
+				}
+				der, err := decryptPBE(info.Algorithm, password, info.EncryptedData)
+				if err != nil {
+					return nil, nil, err
+				}
+				if key, err = parsePrivateKey(der); err != nil {
+					return nil, nil, err
+				}
+			}
+		}
+	}
+	return key, certs, nil
+}
+
+// verifyMAC checks the archive MAC, which fails first on a wrong password.
+func verifyMAC(mac macData, content []byte, password string) error {
+	var newHash func() hash.Hash
+	switch alg := mac.Mac.Algorithm.Algorithm; {
+	case alg.Equal(oidSHA1):
+		newHash = sha1.New
+	case alg.Equal(oidSHA256):
+		newHash = sha256.New
+	case alg.Equal(oidSHA384):
+		newHash = sha512.New384
+	case alg.Equal(oidSHA512):
+		newHash = sha512.New
+	default:
+		return fmt.Errorf("%w: MAC algorithm %s", ErrUnsupported, alg)
+	}
+	if mac.Iterations < 1 || mac.Iterations > maxPBEIterations {
+		return fmt.Errorf("%w: %d MAC iterations", ErrMalformed, mac.Iterations)
+	}
+
+	key := pkcs12KDF(newHash, bmpPassword(password), mac.MacSalt, pkcs12MACMaterial, mac.Iterations, newHash().Size())
+	h := hmac.New(newHash, key)
+	h.Write(content)
+	if !hmac.Equal(h.Sum(nil), mac.Mac.Digest) {
+		return ErrIncorrectPassword
+	}
+	return nil
+}
+
+// safeBags decodes the bags of one authenticated safe entry, decrypting it
+// if needed.
+func safeBags(ci contentInfo, password string) ([]safeBag, error) {
+	var data []byte
+	switch {
+	case ci.ContentType.Equal(oidData):
+		if _, err := asn1.Unmarshal(ci.Content.Bytes, &data); err != nil {
+			return nil, fmt.Errorf("%w: PKCS#12 data: %w", ErrMalformed, err)
+		}
+	case ci.ContentType.Equal(oidEncryptedData):
+		var ed encryptedData
+		if _, err := asn1.Unmarshal(ci.Content.Bytes, &ed); err != nil {
+			return nil, fmt.Errorf("%w: PKCS#12 encrypted data: %w", ErrMalformed, err)
+		}
+		encrypted, err := implicitOctets(ed.ContentInfo.EncryptedContent)
+		if err != nil {
+			return nil, err
+		}
+		if data, err = decryptPBE(ed.ContentInfo.Algorithm, password, encrypted); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: PKCS#12 content type %s", ErrUnsupported, ci.ContentType)
+	}
+
+	var bags []safeBag
+	if _, err := asn1.Unmarshal(data, &bags); err != nil {
+		return nil, fmt.Errorf("%w: PKCS#12 bags: %w", ErrMalformed, err)
+	}
+	return bags, nil
+}
+
+// implicitOctets returns the content of an implicitly tagged OCTET STRING,
+// joining the segments of the constructed form some encoders use.
+func implicitOctets(v asn1.RawValue) ([]byte, error) {
+	if !v.IsCompound {
+		return v.Bytes, nil
+	}
+	var out []byte
+	for rest := v.Bytes; len(rest) > 0; {
+		var segment []byte
+		var err error
+		if rest, err = asn1.Unmarshal(rest, &segment); err != nil {
+			return nil, fmt.Errorf("%w: encrypted content: %w", ErrMalformed, err)
+		}
+		out = append(out, segment...)
+	}
+	return out, nil
+}
@@ -0,0 +1,164 @@
+package pdfsign
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrTimestamp reports a failed or rejected RFC 3161 timestamp request.
+var ErrTimestamp = errors.New("timestamp request failed")
+
+// maxTimestampResponse bounds the time-stamping server response.
+const maxTimestampResponse = 1 << 20
+
+// timeStampReq is TimeStampReq (RFC 3161, 2.4.1).
+type timeStampReq struct {
+	Version        int
+	MessageImprint messageImprint
+	Nonce          *big.Int `asn1:"optional"`
+	CertReq        bool     `asn1:"optional"`
+}
+
+// messageImprint is MessageImprint (RFC 3161, 2.4.1).
+type messageImprint struct {
+	HashAlgorithm pkix.AlgorithmIdentifier
+	HashedMessage []byte
+}
+
+// timeStampResp is TimeStampResp (RFC 3161, 2.4.2).
+type timeStampResp struct {
+	Status         pkiStatusInfo
+	TimeStampToken asn1.RawValue `asn1:"optional"`
+}
+
+// pkiStatusInfo is PKIStatusInfo (RFC 3161, 2.4.2).
+type pkiStatusInfo struct {
+	Status       int
+	StatusString []string       `asn1:"optional,utf8"`
+	FailInfo     asn1.BitString `asn1:"optional"`
+}
+
+// tstInfo is TSTInfo (RFC 3161, 2.4.2). GenTime is kept raw because Go's
+// GeneralizedTime parser rejects the fractional seconds many servers send.
+type tstInfo struct {
+	Version        int
+	Policy         asn1.ObjectIdentifier
+	MessageImprint messageImprint
+	SerialNumber   *big.Int
+	GenTime        asn1.RawValue
+	Accuracy       accuracy `asn1:"optional"`
+	Ordering       bool     `asn1:"optional"`
+	Nonce          *big.Int `asn1:"optional"`
+}
+
+// accuracy is Accuracy (RFC 3161, 2.4.2).
+type accuracy struct {
+	Seconds int `asn1:"optional"`
+	Millis  int `asn1:"optional,tag:0"`
+	Micros  int `asn1:"optional,tag:1"`
+}
+
+// requestTimestamp asks the server at url to timestamp signature and
+// returns the token, a CMS SignedData over a TSTInfo.
+func requestTimestamp(ctx context.Context, url string, signature []byte) ([]byte, error) {
+	imprint := sha256.Sum256(signature)
+	nonce, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 64))
+	if err != nil {
+		return nil, err
+	}
+	body, err := asn1.Marshal(timeStampReq{
+		Version:        1,
+		MessageImprint: messageImprint{HashAlgorithm: sha256AlgorithmID, HashedMessage: imprint[:]},
+		Nonce:          nonce,
+		CertReq:        true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrTimestamp, err)
+	}
+	req.Header.Set("Content-Type", "application/timestamp-query")
+	req.Header.Set("Accept", "application/timestamp-reply")
+	resp, err := http.DefaultClient.Do(req) // #nosec G107 -- user-configured server
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrTimestamp, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: server returned %s", ErrTimestamp, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxTimestampResponse))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrTimestamp, err)
+	}
+
+	var tsResp timeStampResp
+	if _, err := asn1.Unmarshal(data, &tsResp); err != nil {
+		return nil, fmt.Errorf("%w: malformed response: %w", ErrTimestamp, err)
+	}
+	if s := tsResp.Status.Status; s != 0 && s != 1 { // granted, grantedWithMods
+		return nil, fmt.Errorf("%w: status %d %s", ErrTimestamp, s, strings.Join(tsResp.Status.StatusString, "; "))
+	}
+	token := tsResp.TimeStampToken.FullBytes
+	info, err := parseTimestampToken(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrTimestamp, err)
+	}
+	if !bytes.Equal(info.MessageImprint.HashedMessage, imprint[:]) {
+		return nil, fmt.Errorf("%w: token does not match the signature", ErrTimestamp)
+	}
+	if info.Nonce == nil || info.Nonce.Cmp(nonce) != 0 {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrTimestamp)
+	}
+	return token, nil
+}
+
+// parseTimestampToken decodes the TSTInfo a timestamp token encapsulates,
+// without checking the token signature.
+func parseTimestampToken(token []byte) (*tstInfo, error) {
+	sd, _, err := parseSignedData(token)
+	if err != nil {
+		return nil, err
+	}
+	info, _, err := decodeTSTInfo(sd)
+	return info, err
+}
+
+// decodeTSTInfo decodes the TSTInfo encapsulated in sd and returns it with
+// its encoding, the content the token signs.
+func decodeTSTInfo(sd *signedData) (*tstInfo, []byte, error) {
+	if !sd.EncapContentInfo.EContentType.Equal(oidTSTInfo) {
+		return nil, nil, fmt.Errorf("%w: token content is %s, not TSTInfo", ErrInvalidSignature, sd.EncapContentInfo.EContentType)
+	}
+	var content []byte
+	if _, err := asn1.Unmarshal(sd.EncapContentInfo.EContent.Bytes, &content); err != nil {
+		return nil, nil, fmt.Errorf("%w: token content: %w", ErrInvalidSignature, err)
+	}
+	var info tstInfo
+	if _, err := asn1.Unmarshal(content, &info); err != nil {
+		return nil, nil, fmt.Errorf("%w: TSTInfo: %w", ErrInvalidSignature, err)
+	}
+	return &info, content, nil
+}
+
+// genTime parses the GeneralizedTime of a TSTInfo.
+func (t *tstInfo) genTime() (time.Time, error) {
+	if t.GenTime.Tag != asn1.TagGeneralizedTime {
+		return time.Time{}, fmt.Errorf("%w: TSTInfo genTime is not a GeneralizedTime", ErrInvalidSignature)
+	}
+	return time.Parse("20060102150405Z0700", string(t.GenTime.Bytes))
+}
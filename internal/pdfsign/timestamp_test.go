@@ -0,0 +1,210 @@
+package pdfsign
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// testTSA is an RFC 3161 server for tests. Zero fields grant every request.
+type testTSA struct {
+	cert       *testCert
+	status     int  // PKIStatus to return
+	httpStatus int  // HTTP status; 0 means 200
+	badImprint bool // Timestamp other data than requested
+	genTime    time.Time
+}
+
+// newTestTSA issues a time-stamping certificate from pki's root.
+func newTestTSA(t *testing.T, pki *testPKI) *testTSA {
+	t.Helper()
+
+	cert := newTestCert(t, "Test TSA", pki.root, nil, false, x509.ExtKeyUsageTimeStamping)
+	return &testTSA{cert: cert, genTime: time.Now().UTC().Truncate(time.Millisecond)}
+}
+
+func (tsa *testTSA) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if tsa.httpStatus != 0 {
+		w.WriteHeader(tsa.httpStatus)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	var req timeStampReq
+	if _, err := asn1.Unmarshal(body, &req); err != nil || r.Header.Get("Content-Type") != "application/timestamp-query" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	resp := timeStampResp{Status: pkiStatusInfo{Status: tsa.status}}
+	if tsa.status == 0 {
+		token, err := tsa.token(req)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		resp.TimeStampToken = asn1.RawValue{FullBytes: token}
+	} else {
+		resp.Status.StatusString = []string{"request rejected"}
+	}
+	der, _ := asn1.Marshal(resp)
+	w.Header().Set("Content-Type", "application/timestamp-reply")
+	_, _ = w.Write(der)
+}
+
+// token builds a timestamp token for req, signed by the TSA certificate.
+func (tsa *testTSA) token(req timeStampReq) ([]byte, error) {
+	imprint := req.MessageImprint
+	if tsa.badImprint {
+		sum := sha256.Sum256([]byte("other"))
+		imprint.HashedMessage = sum[:]
+	}
+	info, err := asn1.Marshal(tstInfo{
+		Version:        1,
+		Policy:         asn1.ObjectIdentifier{1, 2, 3, 4},
+		MessageImprint: imprint,
+		SerialNumber:   big.NewInt(1),
+		GenTime:        asn1.RawValue{Tag: asn1.TagGeneralizedTime, Bytes: []byte(tsa.genTime.Format("20060102150405.999Z"))},
+		Nonce:          req.Nonce,
+	})
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256(info)
+	attrs, err := encodeAttributes(
+		attributeValue{oidAttrContentType, oidTSTInfo},
+		attributeValue{oidAttrMessageDigest, digest[:]},
+	)
+	if err != nil {
+		return nil, err
+	}
+	signed, err := marshalSet(attrs)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(signed)
+	signature, err := tsa.cert.key.Sign(rand.Reader, sum[:], crypto.SHA256)
+	if err != nil {
+		return nil, err
+	}
+	sid, err := asn1.Marshal(issuerAndSerial{Issuer: asn1.RawValue{FullBytes: tsa.cert.cert.RawIssuer}, Serial: tsa.cert.cert.SerialNumber})
+	if err != nil {
+		return nil, err
+	}
+	content, err := asn1.Marshal(info)
+	if err != nil {
+		return nil, err
+	}
+	sd, err := asn1.Marshal(signedData{
+		Version:          3,
+		DigestAlgorithms: []pkix.AlgorithmIdentifier{sha256AlgorithmID},
+		EncapContentInfo: encapContentInfo{
+			EContentType: oidTSTInfo,
+			EContent:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: content},
+		},
+		Certificates: asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: tsa.cert.cert.Raw},
+		SignerInfos: []signerInfo{{
+			Version:            1,
+			SID:                asn1.RawValue{FullBytes: sid},
+			DigestAlgorithm:    sha256AlgorithmID,
+			SignedAttrs:        asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: attrs},
+			SignatureAlgorithm: pkix.AlgorithmIdentifier{Algorithm: oidECDSAWithSHA256},
+			Signature:          signature,
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(contentInfo{
+		ContentType: oidSignedData,
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: sd},
+	})
+}
+
+// ---------------------------------------------------------------------------
+// TestSign_Timestamp - RFC 3161 timestamp tokens
+// ---------------------------------------------------------------------------
+
+func TestSign_Timestamp(t *testing.T) {
+	t.Parallel()
+
+	t.Run("token is embedded and verified", func(t *testing.T) {
+		t.Parallel()
+
+		pki := newTestPKI(t, nil)
+		tsa := newTestTSA(t, pki)
+		server := httptest.NewServer(tsa)
+		defer server.Close()
+
+		content := []byte("content")
+		sig, err := Sign(context.Background(), content, pki.credentials(), Options{TimestampURL: server.URL})
+		if err != nil {
+			t.Fatalf("Sign() unexpected error: %v", err)
+		}
+		res, err := Verify(sig, content, VerifyOptions{Roots: pki.roots()})
+		if err != nil {
+			t.Fatalf("Verify() unexpected error: %v", err)
+		}
+		if !res.Timestamp.Equal(tsa.genTime) {
+			t.Errorf("Timestamp = %v, want %v", res.Timestamp, tsa.genTime)
+		}
+		if res.TimestampAuthority == nil || !res.TimestampAuthority.Equal(tsa.cert.cert) {
+			t.Error("TimestampAuthority is not the TSA certificate")
+		}
+		if res.TrustError != nil {
+			t.Errorf("TrustError = %v, want nil", res.TrustError)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		pki := newTestPKI(t, nil)
+		tests := []struct {
+			name  string
+			setup func(*testTSA)
+		}{
+			{"rejected", func(tsa *testTSA) { tsa.status = 2 }},
+			{"HTTP error", func(tsa *testTSA) { tsa.httpStatus = http.StatusServiceUnavailable }},
+			{"token for other data", func(tsa *testTSA) { tsa.badImprint = true }},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				tsa := newTestTSA(t, pki)
+				tt.setup(tsa)
+				server := httptest.NewServer(tsa)
+				defer server.Close()
+
+				_, err := Sign(context.Background(), []byte("content"), pki.credentials(), Options{TimestampURL: server.URL})
+				if !errors.Is(err, ErrTimestamp) {
+					t.Errorf("Sign() error = %v, want %v", err, ErrTimestamp)
+				}
+			})
+		}
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		t.Parallel()
+
+		pki := newTestPKI(t, nil)
+		server := httptest.NewServer(newTestTSA(t, pki))
+		defer server.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := Sign(ctx, []byte("content"), pki.credentials(), Options{TimestampURL: server.URL}); !errors.Is(err, context.Canceled) {
+			t.Errorf("Sign() error = %v, want %v", err, context.Canceled)
+		}
+	})
+}
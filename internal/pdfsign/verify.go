@@ -0,0 +1,319 @@
+package pdfsign
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidSignature reports a signature that is malformed or does not
+// match the signed content.
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// VerifyOptions configure Verify.
+type VerifyOptions struct {
+	// Roots are the trusted root certificates. nil uses the system roots.
+	Roots *x509.CertPool
+	// CurrentTime checks certificate validity when the signature has no
+	// timestamp. Zero means now.
+	CurrentTime time.Time
+}
+
+// Result describes a signature that matches its content.
+type Result struct {
+	Signer       *x509.Certificate
+	Certificates []*x509.Certificate // All certificates embedded in the signature
+	// Timestamp is the time certified by the signature's timestamp token,
+	// zero without one. TimestampAuthority signed that token.
+	Timestamp          time.Time
+	TimestampAuthority *x509.Certificate
+	// TrustError explains why the signer or the timestamp authority does
+	// not chain to a trusted root. nil means both are trusted.
+	TrustError error
+}
+
+// Verify checks a detached CMS signature over content and, if present, its
+// timestamp token.
+//
+// Returns ErrInvalidSignature when either does not match. A signature from
+// an untrusted certificate is still returned, with Result.TrustError set.
+// Certificates are checked at the timestamp time when there is one.
+func Verify(sig, content []byte, opts VerifyOptions) (*Result, error) {
+	sd, certs, err := parseSignedData(sig)
+	if err != nil {
+		return nil, err
+	}
+	si, signer, err := verifySignerInfo(sd, certs, content)
+	if err != nil {
+		return nil, err
+	}
+	res := &Result{Signer: signer, Certificates: certs}
+
+	at := opts.CurrentTime
+	if at.IsZero() {
+		at = time.Now()
+	}
+	if token, ok, err := findAttribute(si.UnsignedAttrs.Bytes, oidAttrTimeStampToken); err != nil {
+		return nil, err
+	} else if ok {
+		stamp, tsa, tsaCerts, err := verifyTimestamp(token, si.Signature)
+		if err != nil {
+			return nil, err
+		}
+		res.Timestamp, res.TimestampAuthority, at = stamp, tsa, stamp
+		if err := verifyChain(tsa, tsaCerts, opts.Roots, stamp, x509.ExtKeyUsageTimeStamping); err != nil {
+			res.TrustError = fmt.Errorf("timestamp authority: %w", err)
+		}
+	}
+	if err := verifyChain(signer, certs, opts.Roots, at, x509.ExtKeyUsageAny); err != nil {
+		res.TrustError = err
+	}
+	return res, nil
+}
+
+// verifyTimestamp checks that token is a signed timestamp over signature
+// and returns the certified time and the authority's certificates.
+func verifyTimestamp(token, signature []byte) (time.Time, *x509.Certificate, []*x509.Certificate, error) {
+	sd, certs, err := parseSignedData(token)
+	if err != nil {
+		return time.Time{}, nil, nil, err
+	}
+	info, content, err := decodeTSTInfo(sd)
+	if err != nil {
+		return time.Time{}, nil, nil, err
+	}
+	_, tsa, err := verifySignerInfo(sd, certs, content)
+	if err != nil {
+		return time.Time{}, nil, nil, fmt.Errorf("timestamp: %w", err)
+	}
+
+	h, err := hashFor(info.MessageImprint.HashAlgorithm.Algorithm)
+	if err != nil {
+		return time.Time{}, nil, nil, err
+	}
+	imprint := h.New()
+	imprint.Write(signature)
+	if !bytes.Equal(imprint.Sum(nil), info.MessageImprint.HashedMessage) {
+		return time.Time{}, nil, nil, fmt.Errorf("%w: timestamp is for another signature", ErrInvalidSignature)
+	}
+	stamp, err := info.genTime()
+	if err != nil {
+		return time.Time{}, nil, nil, err
+	}
+	return stamp, tsa, certs, nil
+}
+
+// parseSignedData decodes a CMS ContentInfo holding SignedData and its
+// embedded certificates. Trailing bytes, such as the zero padding of a PDF
+// /Contents string, are ignored.
+func parseSignedData(der []byte) (*signedData, []*x509.Certificate, error) {
+	var ci contentInfo
+	if _, err := asn1.Unmarshal(der, &ci); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	if !ci.ContentType.Equal(oidSignedData) {
+		return nil, nil, fmt.Errorf("%w: content type %s is not SignedData", ErrInvalidSignature, ci.ContentType)
+	}
+	var sd signedData
+	if _, err := asn1.Unmarshal(ci.Content.Bytes, &sd); err != nil {
+		return nil, nil, fmt.Errorf("%w: SignedData: %w", ErrInvalidSignature, err)
+	}
+	if len(sd.SignerInfos) == 0 {
+		return nil, nil, fmt.Errorf("%w: no signer", ErrInvalidSignature)
+	}
+	certs, err := x509.ParseCertificates(sd.Certificates.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: certificates: %w", ErrInvalidSignature, err)
+	}
+	return &sd, certs, nil
+}
+
+// verifySignerInfo checks the first signer of sd against content and
+// returns it with its certificate.
+func verifySignerInfo(sd *signedData, certs []*x509.Certificate, content []byte) (signerInfo, *x509.Certificate, error) {
+	si := sd.SignerInfos[0]
+	cert, err := findSigner(si.SID, certs)
+	if err != nil {
+		return si, nil, err
+	}
+	h, err := hashFor(si.DigestAlgorithm.Algorithm)
+	if err != nil {
+		return si, nil, err
+	}
+	digest := h.New()
+	digest.Write(content)
+
+	signed := content
+	if len(si.SignedAttrs.Bytes) > 0 {
+		md, ok, err := findAttribute(si.SignedAttrs.Bytes, oidAttrMessageDigest)
+		if err != nil {
+			return si, nil, err
+		}
+		var value []byte
+		if ok {
+			_, err = asn1.Unmarshal(md, &value)
+		}
+		if !ok || err != nil || !bytes.Equal(value, digest.Sum(nil)) {
+			return si, nil, fmt.Errorf("%w: content digest mismatch, the document was modified", ErrInvalidSignature)
+		}
+		if err := checkSigningCertificate(si.SignedAttrs.Bytes, cert); err != nil {
+			return si, nil, err
+		}
+		if signed, err = marshalSet(si.SignedAttrs.Bytes); err != nil {
+			return si, nil, err
+		}
+	}
+	if err := checkSignature(cert.PublicKey, si.SignatureAlgorithm.Algorithm, h, signed, si.Signature); err != nil {
+		return si, nil, err
+	}
+	return si, cert, nil
+}
+
+// findSigner returns the certificate identified by a SignerIdentifier.
+func findSigner(sid asn1.RawValue, certs []*x509.Certificate) (*x509.Certificate, error) {
+	if sid.Class == asn1.ClassContextSpecific && sid.Tag == 0 {
+		for _, cert := range certs {
+			if bytes.Equal(cert.SubjectKeyId, sid.Bytes) {
+				return cert, nil
+			}
+		}
+		return nil, fmt.Errorf("%w: signer certificate not embedded", ErrInvalidSignature)
+	}
+	var ias issuerAndSerial
+	if _, err := asn1.Unmarshal(sid.FullBytes, &ias); err != nil {
+		return nil, fmt.Errorf("%w: signer identifier: %w", ErrInvalidSignature, err)
+	}
+	for _, cert := range certs {
+		if cert.SerialNumber.Cmp(ias.Serial) == 0 && bytes.Equal(cert.RawIssuer, ias.Issuer.FullBytes) {
+			return cert, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: signer certificate not embedded", ErrInvalidSignature)
+}
+
+// findAttribute returns the first value of the attribute typ in the
+// content of a SET OF Attribute.
+func findAttribute(attrs []byte, typ asn1.ObjectIdentifier) ([]byte, bool, error) {
+	for rest := attrs; len(rest) > 0; {
+		var a attribute
+		var err error
+		if rest, err = asn1.Unmarshal(rest, &a); err != nil {
+			return nil, false, fmt.Errorf("%w: attributes: %w", ErrInvalidSignature, err)
+		}
+		if !a.Type.Equal(typ) {
+			continue
+		}
+		var value asn1.RawValue
+		if _, err := asn1.Unmarshal(a.Values.Bytes, &value); err != nil {
+			return nil, false, fmt.Errorf("%w: attribute %s: %w", ErrInvalidSignature, typ, err)
+		}
+		return value.FullBytes, true, nil
+	}
+	return nil, false, nil
+}
+
+// checkSigningCertificate checks that a signingCertificateV2 attribute, if
+// present, names cert.
+func checkSigningCertificate(attrs []byte, cert *x509.Certificate) error {
+	raw, ok, err := findAttribute(attrs, oidAttrSigningCertV2)
+	if err != nil || !ok {
+		return err
+	}
+	var sc signingCertificateV2
+	if _, err := asn1.Unmarshal(raw, &sc); err != nil || len(sc.Certs) == 0 {
+		// Entries with a non-default hash algorithm do not parse here;
+		// the signature itself still binds the attribute.
+		return nil
+	}
+	sum := sha256.Sum256(cert.Raw)
+	if !bytes.Equal(sc.Certs[0].CertHash, sum[:]) {
+		return fmt.Errorf("%w: signing certificate attribute names another certificate", ErrInvalidSignature)
+	}
+	return nil
+}
+
+// signatureAlgorithms lists the signature algorithms that name their hash.
+// rsaEncryption and id-ecPublicKey use the signer's digest algorithm.
+var signatureAlgorithms = []struct {
+	oid  asn1.ObjectIdentifier
+	hash crypto.Hash
+	rsa  bool
+}{
+	{oidSHA1WithRSA, crypto.SHA1, true},
+	{oidSHA256WithRSA, crypto.SHA256, true},
+	{oidSHA384WithRSA, crypto.SHA384, true},
+	{oidSHA512WithRSA, crypto.SHA512, true},
+	{oidECDSAWithSHA1, crypto.SHA1, false},
+	{oidECDSAWithSHA256, crypto.SHA256, false},
+	{oidECDSAWithSHA384, crypto.SHA384, false},
+	{oidECDSAWithSHA512, crypto.SHA512, false},
+}
+
+// checkSignature verifies an RSA PKCS#1 v1.5 or ECDSA signature over
+// signed. A signature algorithm naming a hash overrides h.
+func checkSignature(pub any, alg asn1.ObjectIdentifier, h crypto.Hash, signed, signature []byte) error {
+	isRSA, isECDSA := alg.Equal(oidRSAEncryption), alg.Equal(oidECPublicKey)
+	for _, a := range signatureAlgorithms {
+		if alg.Equal(a.oid) {
+			isRSA, isECDSA, h = a.rsa, !a.rsa, a.hash
+		}
+	}
+
+	digest := h.New()
+	digest.Write(signed)
+	sum := digest.Sum(nil)
+	switch key := pub.(type) {
+	case *rsa.PublicKey:
+		if isRSA && rsa.VerifyPKCS1v15(key, h, sum, signature) == nil {
+			return nil
+		}
+	case *ecdsa.PublicKey:
+		if isECDSA && ecdsa.VerifyASN1(key, sum, signature) {
+			return nil
+		}
+	default:
+		return fmt.Errorf("%w: %T signer keys", ErrUnsupported, pub)
+	}
+	return fmt.Errorf("%w: signature does not verify", ErrInvalidSignature)
+}
+
+// hashFor maps a digest algorithm identifier to its hash.
+func hashFor(oid asn1.ObjectIdentifier) (crypto.Hash, error) {
+	switch {
+	case oid.Equal(oidSHA1):
+		return crypto.SHA1, nil
+	case oid.Equal(oidSHA256):
+		return crypto.SHA256, nil
+	case oid.Equal(oidSHA384):
+		return crypto.SHA384, nil
+	case oid.Equal(oidSHA512):
+		return crypto.SHA512, nil
+	default:
+		return 0, fmt.Errorf("%w: digest algorithm %s", ErrUnsupported, oid)
+	}
+}
+
+// verifyChain checks that cert chains to a root in roots (or the system
+// roots) at time at, using certs as intermediates.
+func verifyChain(cert *x509.Certificate, certs []*x509.Certificate, roots *x509.CertPool, at time.Time, usage x509.ExtKeyUsage) error {
+	intermediates := x509.NewCertPool()
+	for _, c := range certs {
+		if c != cert {
+			intermediates.AddCert(c)
+		}
+	}
+	_, err := cert.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   at,
+		KeyUsages:     []x509.ExtKeyUsage{usage},
+	})
+	return err
+}
@@ -0,0 +1,231 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/alnah/picoloom/v2/internal/bibliography"
+)
+
+// BibliographyMarker on its own line marks where the reference list goes.
+const BibliographyMarker = "[bibliography]"
+
+// Fenced div classes of the generated reference list.
+const (
+	ReferencesClass = "references"
+	ReferenceClass  = "csl-entry"
+)
+
+// ErrUnknownCitation is returned for a bracketed citation of a key missing
+// from the bibliography.
+var ErrUnknownCitation = errors.New("unknown citation key")
+
+// citationKey matches a citation key: word characters and inner punctuation,
+// as in pandoc.
+const citationKey = `\w(?:[\w:.#$%&+?<>~/-]*\w)?`
+
+var (
+	// Bracketed citation group, or narrative citation after a boundary.
+	// Captures: 1=group contents, 2=boundary, 3=narrative key
+	citationPattern = regexp.MustCompile(`\[([^\[\]]*@[^\[\]]*)\]|(^|[\s(*_"'])@(` + citationKey + `)`)
+
+	// One citation within a group: "see -@key, p. 3".
+	// Captures: 1=prefix, 2=suppress-author dash, 3=key, 4=locator
+	citationItemPattern = regexp.MustCompile(`(?s)^\s*(?:(.*?)\s+)?(-?)@(` + citationKey + `)(?:\s*,\s*(.*?))?\s*$`)
+
+	// Cross-reference keys, left to CrossRefExtension.
+	crossRefKeyPattern = regexp.MustCompile(`^(?:fig|tbl|sec):`)
+)
+
+// CitationProcessor replaces citations with formatted links to a reference
+// list. Numbers are assigned in order of first citation, and carry over
+// between calls to Cite, so chapters share one reference list.
+type CitationProcessor struct {
+	entries map[string]bibliography.Entry
+	style   bibliography.Style
+	cited   []string       // keys in order of first citation
+	numbers map[string]int // key to position in cited, from 1
+}
+
+// NewCitationProcessor creates a processor for entries keyed by ID.
+func NewCitationProcessor(entries map[string]bibliography.Entry, style bibliography.Style) *CitationProcessor {
+	return &CitationProcessor{
+		entries: entries,
+		style:   style,
+		numbers: make(map[string]int),
+	}
+}
+
+// Cite formats the citations in content. A bracketed group such as
+// [see @smith2020, p. 3; -@doe2019] becomes a parenthetical citation, and a
+// bare @smith2020 a narrative one. Groups followed by "(", "[" or ":" are
+// links, and groups of cross-references are left alone. An unknown key in a
+// group is an error naming its line; an unknown bare key is text, so email
+// addresses and handles survive. Frontmatter, fenced code blocks and code
+// spans are left as written.
+func (p *CitationProcessor) Cite(content string) (string, error) {
+	if !strings.Contains(content, "@") {
+		return content, nil
+	}
+
+	content = normalizeLineEndings(content)
+	frontmatter := ""
+	if loc := yamlFrontmatter.FindStringIndex(content); loc != nil {
+		frontmatter, content = content[:loc[1]], content[loc[1]:]
+	}
+	masked, code := maskCode(content)
+
+	var b strings.Builder
+	last := 0
+	for _, m := range citationPattern.FindAllStringSubmatchIndex(masked, -1) {
+		start, end := m[0], m[1]
+		var formatted string
+		if m[2] >= 0 {
+			if !isCitationGroup(masked, start, end) {
+				continue
+			}
+			cites, ok, err := p.group(masked[m[2]:m[3]])
+			if err != nil {
+				line := strings.Count(frontmatter, "\n") + strings.Count(masked[:start], "\n") + 1
+				return "", fmt.Errorf("line %d: %w", line, err)
+			}
+			if !ok {
+				continue
+			}
+			formatted = bibliography.FormatCitation(p.style, cites)
+		} else {
+			c, ok := p.cite(masked[m[6]:m[7]])
+			if !ok {
+				continue
+			}
+			start = m[5]
+			formatted = bibliography.FormatNarrative(p.style, c)
+		}
+		b.WriteString(masked[last:start])
+		b.WriteString(formatted)
+		last = end
+	}
+	b.WriteString(masked[last:])
+
+	return frontmatter + unmaskCode(b.String(), code), nil
+}
+
+// isCitationGroup reports whether the brackets at content[start:end] are not
+// part of an image, link or link reference definition, nor escaped.
+func isCitationGroup(content string, start, end int) bool {
+	if start > 0 && strings.ContainsRune(`!\`, rune(content[start-1])) {
+		return false
+	}
+	return end == len(content) || !strings.ContainsRune("([:", rune(content[end]))
+}
+
+// group parses the items of a bracketed group. ok is false when the group is
+// not a citation: an item without a key, or a cross-reference.
+func (p *CitationProcessor) group(contents string) (cites []bibliography.Cite, ok bool, err error) {
+	for _, item := range strings.Split(contents, ";") {
+		m := citationItemPattern.FindStringSubmatch(item)
+		if m == nil || crossRefKeyPattern.MatchString(m[3]) {
+			return nil, false, nil
+		}
+		c, known := p.cite(m[3])
+		if !known {
+			return nil, false, fmt.Errorf("%w: @%s", ErrUnknownCitation, m[3])
+		}
+		c.Prefix = strings.TrimSpace(m[1])
+		c.SuppressAuthor = m[2] == "-"
+		c.Locator = strings.TrimSpace(m[4])
+		cites = append(cites, c)
+	}
+	return cites, true, nil
+}
+
+// cite looks up key and numbers it on first citation.
+func (p *CitationProcessor) cite(key string) (bibliography.Cite, bool) {
+	entry, ok := p.entries[key]
+	if !ok {
+		return bibliography.Cite{}, false
+	}
+	if _, seen := p.numbers[key]; !seen {
+		p.cited = append(p.cited, key)
+		p.numbers[key] = len(p.cited)
+	}
+	return bibliography.Cite{Entry: entry, Number: p.numbers[key]}, true
+}
+
+// References returns the reference list for the entries cited so far, as a
+// Markdown heading at level followed by a fenced div with one entry per
+// reference, anchored for the citation links. Numeric lists follow citation
+// order; author-date lists are sorted by author. Returns "" when nothing was
+// cited.
+func (p *CitationProcessor) References(title string, level int) string {
+	if len(p.cited) == 0 {
+		return ""
+	}
+	entries := make([]bibliography.Entry, len(p.cited))
+	for i, key := range p.cited {
+		entries[i] = p.entries[key]
+	}
+	if p.style != bibliography.Numeric {
+		bibliography.SortAuthorDate(entries)
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.Repeat("#", level) + " " + title + "\n\n")
+	b.WriteString(":::: " + ReferencesClass + "\n\n")
+	for _, e := range entries {
+		b.WriteString("::: " + ReferenceClass + " {#" + bibliography.Anchor(e.ID) + "}\n")
+		if p.style == bibliography.Numeric {
+			b.WriteString(`\[` + strconv.Itoa(p.numbers[e.ID]) + `\] `)
+		}
+		b.WriteString(bibliography.FormatReference(p.style, e) + "\n:::\n\n")
+	}
+	b.WriteString("::::\n")
+	return b.String()
+}
+
+// InsertBibliography replaces the first BibliographyMarker line in content
+// with section, outside code blocks, and reports whether a marker was found.
+func InsertBibliography(content, section string) (string, bool) {
+	if !strings.Contains(content, BibliographyMarker) {
+		return content, false
+	}
+	lines := strings.Split(normalizeLineEndings(content), "\n")
+	fence := ""
+	for i, line := range lines {
+		var inCode bool
+		if fence, inCode = trackCodeFence(fence, line); inCode {
+			continue
+		}
+		if strings.TrimSpace(line) == BibliographyMarker {
+			lines[i] = strings.TrimSuffix(section, "\n")
+			return strings.Join(lines, "\n"), true
+		}
+	}
+	return content, false
+}
+
+// SectionLevel returns the heading level for a document-wide section such as
+// the reference list: 1 when the sources hold several top-level headings,
+// as chapters do, and 2 below a single title.
+func SectionLevel(sources ...string) int {
+	h1 := 0
+	for _, source := range sources {
+		fence := ""
+		for _, line := range strings.Split(stripFrontmatter(normalizeLineEndings(source)), "\n") {
+			var inCode bool
+			if fence, inCode = trackCodeFence(fence, line); inCode {
+				continue
+			}
+			if m := atxHeadingPattern.FindStringSubmatch(line); m != nil && m[2] == "#" {
+				h1++
+			}
+		}
+	}
+	if h1 > 1 {
+		return 1
+	}
+	return 2
+}
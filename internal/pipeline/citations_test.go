@@ -0,0 +1,225 @@
+package pipeline
+
+// Notes:
+// - Formatting is tested in internal/bibliography; these tests cover finding
+//   citations in Markdown, numbering, and placing the reference list
+// - citationEntries is shared, read-only test data
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/alnah/picoloom/v2/internal/bibliography"
+)
+
+var citationEntries = map[string]bibliography.Entry{
+	"knuth1984": {ID: "knuth1984", Type: "book", Authors: []bibliography.Name{{Family: "Knuth", Given: "Donald"}}, Title: "The TeXbook", Year: "1984"},
+	"doe2019":   {ID: "doe2019", Type: "book", Authors: []bibliography.Name{{Family: "Doe", Given: "Jane"}}, Title: "Results", Year: "2019"},
+}
+
+// ---------------------------------------------------------------------------
+// TestCitationProcessor_Cite - Citation Syntax
+// ---------------------------------------------------------------------------
+
+func TestCitationProcessor_Cite(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		style bibliography.Style
+		input string
+		want  string
+	}{
+		{
+			"bracketed group",
+			bibliography.AuthorDate,
+			"As shown [see @doe2019, p. 3; -@knuth1984].",
+			"As shown (see [Doe 2019](#ref-doe2019), p. 3; [1984](#ref-knuth1984)).",
+		},
+		{
+			"narrative",
+			bibliography.AuthorDate,
+			"@knuth1984 wrote it.",
+			"Knuth ([1984](#ref-knuth1984)) wrote it.",
+		},
+		{
+			"numeric numbers in order of first citation",
+			bibliography.Numeric,
+			"[@doe2019] then @knuth1984 and [@knuth1984; @doe2019]",
+			`\[[1](#ref-doe2019)\] then Knuth \[[2](#ref-knuth1984)\] and \[[2](#ref-knuth1984), [1](#ref-doe2019)\]`,
+		},
+		{
+			"email and unknown narrative key stay text",
+			bibliography.AuthorDate,
+			"Mail doe@doe2019.org or ask @someone.",
+			"Mail doe@doe2019.org or ask @someone.",
+		},
+		{
+			"links, images and escaped brackets stay text",
+			bibliography.AuthorDate,
+			"[@doe2019](https://x.org) ![@doe2019](x.png) \\[@doe2019] [@doe2019]: https://x.org",
+			"[@doe2019](https://x.org) ![@doe2019](x.png) \\[@doe2019] [@doe2019]: https://x.org",
+		},
+		{
+			"group without keys stays text",
+			bibliography.AuthorDate,
+			"[follow @someone on social media]",
+			"[follow @someone on social media]",
+		},
+		{
+			"cross-references stay text",
+			bibliography.AuthorDate,
+			"[@fig:arch] and @sec:intro",
+			"[@fig:arch] and @sec:intro",
+		},
+		{
+			"code stays text",
+			bibliography.AuthorDate,
+			"`[@doe2019]`\n\n```\n@doe2019\n```",
+			"`[@doe2019]`\n\n```\n@doe2019\n```",
+		},
+		{
+			"frontmatter stays text",
+			bibliography.AuthorDate,
+			"---\nauthor: \"@doe2019\"\n---\n[@doe2019]",
+			"---\nauthor: \"@doe2019\"\n---\n([Doe 2019](#ref-doe2019))",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := NewCitationProcessor(citationEntries, tt.style)
+			got, err := p.Cite(tt.input)
+			if err != nil {
+				t.Fatalf("Cite() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Cite() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCitationProcessor_Cite_UnknownKey(t *testing.T) {
+	t.Parallel()
+
+	p := NewCitationProcessor(citationEntries, bibliography.AuthorDate)
+	_, err := p.Cite("---\ntitle: x\n---\n\nIntro.\n\nSee [@doe2019; @nobody].")
+	if !errors.Is(err, ErrUnknownCitation) {
+		t.Fatalf("Cite() error = %v, want %v", err, ErrUnknownCitation)
+	}
+	if want := "line 7: unknown citation key: @nobody"; err.Error() != want {
+		t.Errorf("Cite() error = %q, want %q", err.Error(), want)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestCitationProcessor_References - Reference List
+// ---------------------------------------------------------------------------
+
+func TestCitationProcessor_References(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nothing cited", func(t *testing.T) {
+		t.Parallel()
+
+		p := NewCitationProcessor(citationEntries, bibliography.AuthorDate)
+		if got := p.References("References", 2); got != "" {
+			t.Errorf("References() = %q, want empty", got)
+		}
+	})
+
+	t.Run("author-date sorted by author", func(t *testing.T) {
+		t.Parallel()
+
+		p := NewCitationProcessor(citationEntries, bibliography.AuthorDate)
+		if _, err := p.Cite("[@knuth1984; @doe2019]"); err != nil {
+			t.Fatalf("Cite() unexpected error: %v", err)
+		}
+		want := "## References\n\n:::: references\n\n" +
+			"::: csl-entry {#ref-doe2019}\nDoe, Jane. 2019. *Results*.\n:::\n\n" +
+			"::: csl-entry {#ref-knuth1984}\nKnuth, Donald. 1984. *The TeXbook*.\n:::\n\n" +
+			"::::\n"
+		if got := p.References("References", 2); got != want {
+			t.Errorf("References() =\n%q\nwant\n%q", got, want)
+		}
+	})
+
+	t.Run("numeric in citation order across calls", func(t *testing.T) {
+		t.Parallel()
+
+		p := NewCitationProcessor(citationEntries, bibliography.Numeric)
+		for _, chapter := range []string{"[@knuth1984]", "[@doe2019]"} {
+			if _, err := p.Cite(chapter); err != nil {
+				t.Fatalf("Cite() unexpected error: %v", err)
+			}
+		}
+		got := p.References("Sources", 1)
+		if !strings.HasPrefix(got, "# Sources\n") {
+			t.Errorf("References() heading = %q, want # Sources", got)
+		}
+		knuth, doe := strings.Index(got, `\[1\] D. Knuth`), strings.Index(got, `\[2\] J. Doe`)
+		if knuth < 0 || doe < knuth {
+			t.Errorf("References() = %q, want [1] Knuth before [2] Doe", got)
+		}
+	})
+}
+
+// ---------------------------------------------------------------------------
+// TestInsertBibliography - Marker Placement
+// ---------------------------------------------------------------------------
+
+func TestInsertBibliography(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		input     string
+		want      string
+		wantFound bool
+	}{
+		{"replaces marker line", "Text\n\n[bibliography]\n\nMore", "Text\n\n## Refs\n\nMore", true},
+		{"first marker only", "[bibliography]\n[bibliography]", "## Refs\n[bibliography]", true},
+		{"marker in code is skipped", "```\n[bibliography]\n```", "```\n[bibliography]\n```", false},
+		{"inline marker is text", "See [bibliography] below", "See [bibliography] below", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, found := InsertBibliography(tt.input, "## Refs\n")
+			if got != tt.want || found != tt.wantFound {
+				t.Errorf("InsertBibliography() = %q, %v, want %q, %v", got, found, tt.want, tt.wantFound)
+			}
+		})
+	}
+}
+
+func TestSectionLevel(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		sources []string
+		want    int
+	}{
+		{"single title", []string{"# Title\n\n## Part"}, 2},
+		{"no headings", []string{"text"}, 2},
+		{"chapters", []string{"# One", "# Two"}, 1},
+		{"headings in code are skipped", []string{"# One\n\n```\n# comment\n```"}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := SectionLevel(tt.sources...); got != tt.want {
+				t.Errorf("SectionLevel() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package pipeline
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// Classes written for alerts and fenced divs. Alert classes match GitHub's,
+// so stylesheets written for GitHub apply.
+const (
+	AlertClass      = "markdown-alert"
+	AlertTitleClass = "markdown-alert-title"
+	FencedDivClass  = "fenced-div"
+)
+
+// alertTitles maps GitHub alert types to their titles.
+var alertTitles = map[string]string{
+	"note":      "Note",
+	"tip":       "Tip",
+	"important": "Important",
+	"warning":   "Warning",
+	"caution":   "Caution",
+}
+
+var (
+	// First line of a GitHub alert blockquote.
+	// Captures: 1=type
+	alertMarkerPattern = regexp.MustCompile(`(?i)^\[!(note|tip|important|warning|caution)\]$`)
+
+	// Fenced div opening line: colons, then a name, attributes, or both.
+	// Captures: 1=colons, 2=name, 3=attributes without braces
+	fencedDivOpenPattern = regexp.MustCompile(`^(:{3,})[ \t]*([A-Za-z][\w-]*)?[ \t]*(?:\{([^}]*)\})?[ \t]*$`)
+
+	// Fenced div closing line.
+	fencedDivClosePattern = regexp.MustCompile(`^:{3,}[ \t]*$`)
+
+	// Class and id values accepted in fenced div attributes.
+	attrNamePattern = regexp.MustCompile(`^[A-Za-z][\w:.-]*$`)
+)
+
+// ContainerExtension renders GitHub alerts (a blockquote starting with
+// [!NOTE], [!TIP], [!IMPORTANT], [!WARNING] or [!CAUTION]) as callouts, and
+// fenced divs (:::name{.class #id} ... :::) as <div> elements. Fenced divs
+// nest; a ::: line closes the innermost one, and an unclosed div runs to the
+// end of its container.
+var ContainerExtension goldmark.Extender = &containerExtension{}
+
+type containerExtension struct{}
+
+func (e *containerExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithBlockParsers(util.Prioritized(&fencedDivParser{}, 160)),
+		parser.WithASTTransformers(util.Prioritized(&alertTransformer{}, 100)),
+	)
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(util.Prioritized(&containerRenderer{}, 160)))
+}
+
+// KindAlert is the NodeKind of GitHub alerts.
+var KindAlert = ast.NewNodeKind("Alert")
+
+// Alert is a blockquote marked as a GitHub alert.
+type Alert struct {
+	ast.BaseBlock
+	AlertType string // Lowercase alert type, e.g. "warning"
+}
+
+// Kind implements ast.Node.
+func (n *Alert) Kind() ast.NodeKind { return KindAlert }
+
+// Dump implements ast.Node.
+func (n *Alert) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"AlertType": n.AlertType}, nil)
+}
+
+// KindFencedDiv is the NodeKind of fenced divs.
+var KindFencedDiv = ast.NewNodeKind("FencedDiv")
+
+// FencedDiv is a :::name{.class #id} container.
+type FencedDiv struct {
+	ast.BaseBlock
+	Classes []string // Name first, then .class attributes
+	ID      string
+}
+
+// Kind implements ast.Node.
+func (n *FencedDiv) Kind() ast.NodeKind { return KindFencedDiv }
+
+// Dump implements ast.Node.
+func (n *FencedDiv) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{
+		"Classes": strings.Join(n.Classes, " "),
+		"ID":      n.ID,
+	}, nil)
+}
+
+// fencedDivParser parses :::name{.class #id} containers.
+type fencedDivParser struct{}
+
+func (p *fencedDivParser) Trigger() []byte {
+	return []byte{':'}
+}
+
+func (p *fencedDivParser) Open(_ ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, _ := reader.PeekLine()
+	pos := pc.BlockOffset()
+	if pos < 0 {
+		return nil, parser.NoChildren
+	}
+	m := fencedDivOpenPattern.FindStringSubmatch(strings.TrimRight(string(line[pos:]), "\r\n"))
+	if m == nil || (m[2] == "" && strings.TrimSpace(m[3]) == "") {
+		return nil, parser.NoChildren
+	}
+
+	node := &FencedDiv{}
+	if m[2] != "" {
+		node.Classes = append(node.Classes, m[2])
+	}
+	for _, attr := range strings.Fields(m[3]) {
+		switch {
+		case strings.HasPrefix(attr, ".") && attrNamePattern.MatchString(attr[1:]):
+			node.Classes = append(node.Classes, attr[1:])
+		case strings.HasPrefix(attr, "#") && attrNamePattern.MatchString(attr[1:]):
+			node.ID = attr[1:]
+		}
+	}
+	reader.AdvanceToEOL()
+	return node, parser.HasChildren
+}
+
+func (p *fencedDivParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	line, _ := reader.PeekLine()
+	if line == nil {
+		return parser.Close
+	}
+	if fencedDivClosePattern.Match(bytes.TrimSpace(line)) && !hasOpenInnerBlock(node, pc) {
+		w, _ := util.IndentWidth(line, reader.LineOffset())
+		if w < 4 {
+			reader.AdvanceToEOL()
+			return parser.Close
+		}
+	}
+	return parser.Continue | parser.HasChildren
+}
+
+// hasOpenInnerBlock reports whether a block that owns a ::: line, a nested
+// fenced div or a code or math block, is open inside node.
+func hasOpenInnerBlock(node ast.Node, pc parser.Context) bool {
+	inside := false
+	for _, b := range pc.OpenedBlocks() {
+		if b.Node == node {
+			inside = true
+			continue
+		}
+		if !inside {
+			continue
+		}
+		switch b.Node.Kind() {
+		case KindFencedDiv, KindMathBlock, ast.KindFencedCodeBlock, ast.KindHTMLBlock:
+			return true
+		}
+	}
+	return false
+}
+
+func (p *fencedDivParser) Close(_ ast.Node, _ text.Reader, _ parser.Context) {}
+
+func (p *fencedDivParser) CanInterruptParagraph() bool {
+	return true
+}
+
+func (p *fencedDivParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+// alertTransformer replaces blockquotes whose first line is an alert marker
+// with Alert nodes.
+type alertTransformer struct{}
+
+func (t *alertTransformer) Transform(doc *ast.Document, reader text.Reader, _ parser.Context) {
+	source := reader.Source()
+	var quotes []*ast.Blockquote
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if q, ok := n.(*ast.Blockquote); ok && entering {
+			quotes = append(quotes, q)
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, q := range quotes {
+		para, ok := q.FirstChild().(*ast.Paragraph)
+		if !ok || para.Lines().Len() == 0 {
+			continue
+		}
+		first := para.Lines().At(0)
+		m := alertMarkerPattern.FindSubmatch(bytes.TrimSpace(first.Value(source)))
+		if m == nil {
+			continue
+		}
+
+		// Drop the marker line; a paragraph holding only the marker goes too.
+		for c := para.FirstChild(); c != nil; {
+			next := c.NextSibling()
+			textNode, isText := c.(*ast.Text)
+			if !isText || textNode.Segment.Start >= first.Stop {
+				break
+			}
+			para.RemoveChild(para, c)
+			c = next
+		}
+		if !para.HasChildren() {
+			q.RemoveChild(q, para)
+		}
+
+		alert := &Alert{AlertType: strings.ToLower(string(m[1]))}
+		for c := q.FirstChild(); c != nil; {
+			next := c.NextSibling()
+			alert.AppendChild(alert, c)
+			c = next
+		}
+		q.Parent().ReplaceChild(q.Parent(), q, alert)
+	}
+}
+
+// containerRenderer writes alerts and fenced divs.
+type containerRenderer struct{}
+
+func (r *containerRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindAlert, r.renderAlert)
+	reg.Register(KindFencedDiv, r.renderFencedDiv)
+}
+
+func (r *containerRenderer) renderAlert(w util.BufWriter, _ []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		_, _ = w.WriteString("</div>\n")
+		return ast.WalkContinue, nil
+	}
+	n := node.(*Alert)
+	_, _ = w.WriteString(`<div class="` + AlertClass + ` ` + AlertClass + `-` + n.AlertType + `">` + "\n")
+	_, _ = w.WriteString(`<p class="` + AlertTitleClass + `">` + alertTitles[n.AlertType] + "</p>\n")
+	return ast.WalkContinue, nil
+}
+
+func (r *containerRenderer) renderFencedDiv(w util.BufWriter, _ []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		_, _ = w.WriteString("</div>\n")
+		return ast.WalkContinue, nil
+	}
+	n := node.(*FencedDiv)
+	classes := append([]string{FencedDivClass}, n.Classes...)
+	_, _ = w.WriteString(`<div class="`)
+	_, _ = w.Write(util.EscapeHTML([]byte(strings.Join(classes, " "))))
+	_, _ = w.WriteString(`"`)
+	if n.ID != "" {
+		_, _ = w.WriteString(` id="`)
+		_, _ = w.Write(util.EscapeHTML([]byte(n.ID)))
+		_, _ = w.WriteString(`"`)
+	}
+	_, _ = w.WriteString(">\n")
+	return ast.WalkContinue, nil
+}
@@ -0,0 +1,152 @@
+package pipeline
+
+// Notes:
+// - ContainerExtension is tested through NewGoldmarkConverter output
+// - mathBody (math_test.go) extracts the HTML body
+
+import "testing"
+
+// ---------------------------------------------------------------------------
+// TestContainerExtension_Alerts - GitHub alert blockquotes
+// ---------------------------------------------------------------------------
+
+func TestContainerExtension_Alerts(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			"note",
+			"> [!NOTE]\n> Read *this*.",
+			"<div class=\"markdown-alert markdown-alert-note\">\n<p class=\"markdown-alert-title\">Note</p>\n<p>Read <em>this</em>.</p>\n</div>",
+		},
+		{
+			"type is case insensitive",
+			"> [!warning]\n>\n> Hot.",
+			"<div class=\"markdown-alert markdown-alert-warning\">\n<p class=\"markdown-alert-title\">Warning</p>\n<p>Hot.</p>\n</div>",
+		},
+		{
+			"marker only",
+			"> [!TIP]",
+			"<div class=\"markdown-alert markdown-alert-tip\">\n<p class=\"markdown-alert-title\">Tip</p>\n</div>",
+		},
+		{
+			"unknown type stays a blockquote",
+			"> [!DANGER]\n> x",
+			"<blockquote>\n<p>[!DANGER]<br />\nx</p>\n</blockquote>",
+		},
+		{
+			"text after marker stays a blockquote",
+			"> [!NOTE] inline\n> x",
+			"<blockquote>\n<p>[!NOTE] inline<br />\nx</p>\n</blockquote>",
+		},
+		{
+			"marker on later line stays a blockquote",
+			"> x\n> [!NOTE]",
+			"<blockquote>\n<p>x<br />\n[!NOTE]</p>\n</blockquote>",
+		},
+		{
+			"nested in blockquote",
+			"> > [!CAUTION]\n> > y",
+			"<blockquote>\n<div class=\"markdown-alert markdown-alert-caution\">\n<p class=\"markdown-alert-title\">Caution</p>\n<p>y</p>\n</div>\n</blockquote>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := mathBody(t, tt.input); got != tt.want {
+				t.Errorf("ToHTML() body = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestContainerExtension_FencedDivs - ::: containers
+// ---------------------------------------------------------------------------
+
+func TestContainerExtension_FencedDivs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			"name",
+			":::aside\nText\n:::\nafter",
+			"<div class=\"fenced-div aside\">\n<p>Text</p>\n</div>\n<p>after</p>",
+		},
+		{
+			"name with attributes",
+			"::: note {.wide .boxed #n1}\nx\n:::",
+			"<div class=\"fenced-div note wide boxed\" id=\"n1\">\n<p>x</p>\n</div>",
+		},
+		{
+			"attributes only",
+			":::{#only}\nx\n:::",
+			"<div class=\"fenced-div\" id=\"only\">\n<p>x</p>\n</div>",
+		},
+		{
+			"invalid attributes ignored",
+			":::box{.ok .\"bad #9}\nx\n:::",
+			"<div class=\"fenced-div box ok\">\n<p>x</p>\n</div>",
+		},
+		{
+			"bare colons are text",
+			":::\nx",
+			"<p>:::<br />\nx</p>",
+		},
+		{
+			"nested",
+			":::outer\n:::inner\nx\n:::\ny\n::::",
+			"<div class=\"fenced-div outer\">\n<div class=\"fenced-div inner\">\n<p>x</p>\n</div>\n<p>y</p>\n</div>",
+		},
+		{
+			"interrupts paragraph",
+			"Para\n:::box\nin\n:::",
+			"<p>Para</p>\n<div class=\"fenced-div box\">\n<p>in</p>\n</div>",
+		},
+		{
+			"closer in fenced code",
+			":::box\n```\n:::\n```\n:::",
+			"<div class=\"fenced-div box\">\n<pre><code>:::\n</code></pre>\n</div>",
+		},
+		{
+			"unclosed runs to end",
+			":::box\nx\n\ny",
+			"<div class=\"fenced-div box\">\n<p>x</p>\n<p>y</p>\n</div>",
+		},
+		{
+			"alert inside",
+			":::box\n> [!IMPORTANT]\n> z\n:::",
+			"<div class=\"fenced-div box\">\n<div class=\"markdown-alert markdown-alert-important\">\n<p class=\"markdown-alert-title\">Important</p>\n<p>z</p>\n</div>\n</div>",
+		},
+		{
+			"in list item",
+			"- item\n  :::box\n  in\n  :::\n- two",
+			"<ul>\n<li>item\n<div class=\"fenced-div box\">\n<p>in</p>\n</div>\n</li>\n<li>two</li>\n</ul>",
+		},
+		{
+			"in fenced code unchanged",
+			"```\n:::box\n:::\n```",
+			"<pre><code>:::box\n:::\n</code></pre>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := mathBody(t, tt.input); got != tt.want {
+				t.Errorf("ToHTML() body = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
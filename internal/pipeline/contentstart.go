@@ -0,0 +1,39 @@
+package pipeline
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ContentStartID is the ID of the marker MarkContentStart places before the
+// first body element. Chrome writes a named destination for it, which
+// locates the first page after the front matter.
+const ContentStartID = "picoloom-content-start"
+
+// frontMatterNavPattern matches an injected TOC or list of figures or tables.
+var frontMatterNavPattern = regexp.MustCompile(`(?is)<nav class="toc(?: [^"]*)?">.*?</nav>`)
+
+// MarkContentStart inserts the content start marker after the front matter:
+// the TOC and lists if there are any, otherwise the cover. Call it after
+// cover, TOC and list injection. Returns htmlContent unchanged if there is
+// no front matter.
+func MarkContentStart(htmlContent string) string {
+	var loc []int
+	if locs := frontMatterNavPattern.FindAllStringIndex(htmlContent, -1); len(locs) > 0 {
+		loc = locs[len(locs)-1]
+	}
+	if loc == nil {
+		loc = coverEndPattern.FindStringIndex(htmlContent)
+	}
+	if loc == nil {
+		return htmlContent
+	}
+
+	// The empty link makes the marker a link target, the only elements
+	// Chrome writes named destinations for.
+	var b strings.Builder
+	b.WriteString(htmlContent[:loc[1]])
+	b.WriteString(`<div id="` + ContentStartID + `"><a href="#` + ContentStartID + `"></a></div>`)
+	b.WriteString(htmlContent[loc[1]:])
+	return b.String()
+}
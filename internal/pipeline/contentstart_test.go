@@ -0,0 +1,46 @@
+package pipeline
+
+// Notes:
+// - The marker follows the last TOC nav (TOC or list) when there is one,
+//   otherwise the cover
+// - Documents without front matter are left unchanged
+
+import "testing"
+
+// ---------------------------------------------------------------------------
+// TestMarkContentStart - Marker after the front matter
+// ---------------------------------------------------------------------------
+
+func TestMarkContentStart(t *testing.T) {
+	t.Parallel()
+
+	const (
+		cover   = `<section class="cover"><div>Title</div></section><span data-cover-end></span>`
+		toc     = `<nav class="toc"><div><a href="#a">A</a></div></nav>`
+		paged   = `<nav class="toc toc-paged"><div><a href="#a">A</a></div></nav>`
+		figures = `<nav class="toc toc-figures"><div><a href="#f">F</a></div></nav>`
+		marker  = `<div id="` + ContentStartID + `"><a href="#` + ContentStartID + `"></a></div>`
+	)
+
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"after TOC", "<body>" + cover + toc + "<h1>A</h1></body>", "<body>" + cover + toc + marker + "<h1>A</h1></body>"},
+		{"after TOC without cover", "<body>" + toc + "<p>x</p></body>", "<body>" + toc + marker + "<p>x</p></body>"},
+		{"after paged TOC and lists", "<body>" + paged + figures + "<p>x</p></body>", "<body>" + paged + figures + marker + "<p>x</p></body>"},
+		{"after cover", "<body>" + cover + "<p>x</p></body>", "<body>" + cover + marker + "<p>x</p></body>"},
+		{"no front matter", "<body><p>x</p></body>", "<body><p>x</p></body>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := MarkContentStart(tt.html); got != tt.want {
+				t.Errorf("MarkContentStart(%q) = %q, want %q", tt.html, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,430 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	extast "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// Labels and classes written for numbered figures and tables and for
+// cross-references. NumberCrossRefs adds the numbers.
+const (
+	FigureLabel      = "Figure"
+	TableLabel       = "Table"
+	FigureClass      = "figure"
+	FigureLabelClass = "figure-label"
+	TableLabelClass  = "table-label"
+	CrossRefClass    = "crossref"
+)
+
+// Sentinel errors for cross-references.
+var (
+	ErrUnresolvedReference = errors.New("unresolved cross-reference")
+	ErrDuplicateLabel      = errors.New("duplicate cross-reference label")
+)
+
+var (
+	// Cross-reference at the start of the remaining line.
+	// Captures: 1=type (fig, tbl, sec), 2=label
+	crossRefPattern = regexp.MustCompile(`^@(fig|tbl|sec):(\w+(?:[-.]\w+)*)`)
+
+	// Figure label written after a standalone image.
+	// Captures: 1=id
+	figureIDPattern = regexp.MustCompile(`^\s*(?:\{#(fig:\w+(?:[-.]\w+)*)\})?\s*$`)
+
+	// Table label at the end of a table caption.
+	// Captures: 1=id
+	tableIDPattern = regexp.MustCompile(`\s*\{#(tbl:\w+(?:[-.]\w+)*)\}\s*$`)
+
+	// Prefix of a table caption paragraph.
+	tableCaptionPattern = regexp.MustCompile(`^` + TableLabel + `:[ \t]*`)
+
+	// Numbered figure or captioned table.
+	// Captures: 1=figure id, 2=table id
+	numberedElementPattern = regexp.MustCompile(`<figure class="` + FigureClass + `"(?: id="([^"]*)")?>|<table(?: id="([^"]*)")?>\s*<caption>`)
+
+	// Figure or table label awaiting its number.
+	// Captures: 1=class
+	numberLabelPattern = regexp.MustCompile(`<span class="(` + FigureLabelClass + `|` + TableLabelClass + `)">(?:` + FigureLabel + `|` + TableLabel + `)</span>`)
+
+	// Cross-reference link written by the goldmark extension.
+	// Captures: 1=target id, 2=type, 3=label
+	crossRefLinkPattern = regexp.MustCompile(`<a class="` + CrossRefClass + `" href="#([^"]*)">@(fig|tbl|sec):([^<]*)</a>`)
+)
+
+// CrossRefExtension numbers figures and tables and parses references to
+// them. A standalone image with a title becomes a figure captioned with the
+// title, and a {#fig:label} after the image gives it an ID:
+//
+//	![Overview](arch.png "System architecture"){#fig:architecture}
+//
+// A paragraph starting with "Table:" right after (or before) a table is its
+// caption, with an optional {#tbl:label} at the end. @fig:label, @tbl:label
+// and @sec:heading-id become links; NumberCrossRefs numbers the document and
+// fills in their text.
+var CrossRefExtension goldmark.Extender = &crossRefExtension{}
+
+type crossRefExtension struct{}
+
+func (e *crossRefExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithInlineParsers(util.Prioritized(&crossRefParser{}, 150)),
+		parser.WithASTTransformers(util.Prioritized(&crossRefTransformer{}, 110)),
+	)
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(util.Prioritized(&crossRefRenderer{}, 150)))
+}
+
+// KindFigure is the NodeKind of numbered figures.
+var KindFigure = ast.NewNodeKind("Figure")
+
+// Figure is a standalone image with a caption.
+type Figure struct {
+	ast.BaseBlock
+	ID      string // "fig:label", or empty
+	Caption []byte // Image title, or alt text when the image has none
+}
+
+// Kind implements ast.Node.
+func (n *Figure) Kind() ast.NodeKind { return KindFigure }
+
+// Dump implements ast.Node.
+func (n *Figure) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"ID": n.ID, "Caption": string(n.Caption)}, nil)
+}
+
+// KindTableCaption is the NodeKind of table captions.
+var KindTableCaption = ast.NewNodeKind("TableCaption")
+
+// TableCaption is the caption of a table, its first child.
+type TableCaption struct {
+	ast.BaseBlock
+}
+
+// Kind implements ast.Node.
+func (n *TableCaption) Kind() ast.NodeKind { return KindTableCaption }
+
+// Dump implements ast.Node.
+func (n *TableCaption) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+// KindCrossRef is the NodeKind of cross-references.
+var KindCrossRef = ast.NewNodeKind("CrossRef")
+
+// CrossRef is an @fig:, @tbl: or @sec: reference.
+type CrossRef struct {
+	ast.BaseInline
+	RefType string // "fig", "tbl" or "sec"
+	Label   string
+}
+
+// Kind implements ast.Node.
+func (n *CrossRef) Kind() ast.NodeKind { return KindCrossRef }
+
+// Dump implements ast.Node.
+func (n *CrossRef) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"RefType": n.RefType, "Label": n.Label}, nil)
+}
+
+// Target returns the ID the reference points at: the heading ID for
+// sections, "fig:label" or "tbl:label" otherwise.
+func (n *CrossRef) Target() string {
+	if n.RefType == "sec" {
+		return n.Label
+	}
+	return n.RefType + ":" + n.Label
+}
+
+// crossRefParser parses @fig:label, @tbl:label and @sec:label. An @ inside
+// a word, as in an email address, is text.
+type crossRefParser struct{}
+
+func (p *crossRefParser) Trigger() []byte {
+	return []byte{'@'}
+}
+
+func (p *crossRefParser) Parse(_ ast.Node, block text.Reader, _ parser.Context) ast.Node {
+	if prev := block.PrecendingCharacter(); unicode.IsLetter(prev) || unicode.IsDigit(prev) || prev == '_' {
+		return nil
+	}
+	line, _ := block.PeekLine()
+	m := crossRefPattern.FindSubmatch(line)
+	if m == nil {
+		return nil
+	}
+	block.Advance(len(m[0]))
+	return &CrossRef{RefType: string(m[1]), Label: string(m[2])}
+}
+
+// crossRefTransformer turns standalone titled images into figures and
+// "Table:" paragraphs next to tables into captions.
+type crossRefTransformer struct{}
+
+func (t *crossRefTransformer) Transform(doc *ast.Document, reader text.Reader, _ parser.Context) {
+	source := reader.Source()
+	var paras []*ast.Paragraph
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if p, ok := n.(*ast.Paragraph); ok && entering {
+			paras = append(paras, p)
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, para := range paras {
+		if !toFigure(para, source) {
+			toTableCaption(para, source)
+		}
+	}
+}
+
+// toFigure replaces para with a Figure if it holds only an image with a
+// title or a {#fig:label}.
+func toFigure(para *ast.Paragraph, source []byte) bool {
+	img, ok := para.FirstChild().(*ast.Image)
+	if !ok {
+		return false
+	}
+	var rest strings.Builder
+	for c := img.NextSibling(); c != nil; c = c.NextSibling() {
+		t, ok := c.(*ast.Text)
+		if !ok {
+			return false
+		}
+		rest.Write(t.Value(source))
+		if t.SoftLineBreak() || t.HardLineBreak() {
+			rest.WriteByte('\n')
+		}
+	}
+	m := figureIDPattern.FindStringSubmatch(rest.String())
+	if m == nil || (len(img.Title) == 0 && m[1] == "") {
+		return false
+	}
+
+	fig := &Figure{ID: m[1], Caption: img.Title}
+	if len(fig.Caption) == 0 {
+		fig.Caption = inlineText(img, source)
+	}
+	img.Title = nil
+	fig.AppendChild(fig, img)
+	para.Parent().ReplaceChild(para.Parent(), para, fig)
+	return true
+}
+
+// toTableCaption moves a "Table: ..." paragraph into the adjacent table
+// that has no caption yet, preferring the table before it.
+func toTableCaption(para *ast.Paragraph, source []byte) {
+	first, ok := para.FirstChild().(*ast.Text)
+	if !ok {
+		return
+	}
+	prefix := tableCaptionPattern.Find(first.Value(source))
+	if prefix == nil {
+		return
+	}
+	table := captionlessTable(para.PreviousSibling())
+	if table == nil {
+		table = captionlessTable(para.NextSibling())
+	}
+	if table == nil {
+		return
+	}
+
+	// Text may be split at spaces, so trimming continues into neighbors.
+	first.Segment = first.Segment.WithStart(first.Segment.Start + len(prefix))
+	for c := ast.Node(first); c != nil; c = c.NextSibling() {
+		t, ok := c.(*ast.Text)
+		if !ok {
+			break
+		}
+		if t.Segment = t.Segment.TrimLeftSpace(source); t.Segment.Len() > 0 {
+			break
+		}
+	}
+	if last, ok := para.LastChild().(*ast.Text); ok {
+		value := last.Value(source)
+		if loc := tableIDPattern.FindSubmatchIndex(value); loc != nil {
+			table.SetAttributeString("id", value[loc[2]:loc[3]])
+			last.Segment = last.Segment.WithStop(last.Segment.Start + loc[0])
+			for c := ast.Node(last); c != nil; c = c.PreviousSibling() {
+				t, ok := c.(*ast.Text)
+				if !ok {
+					break
+				}
+				if t.Segment = t.Segment.TrimRightSpace(source); t.Segment.Len() > 0 {
+					break
+				}
+			}
+		}
+	}
+
+	caption := &TableCaption{}
+	for c := para.FirstChild(); c != nil; {
+		next := c.NextSibling()
+		caption.AppendChild(caption, c)
+		c = next
+	}
+	para.Parent().RemoveChild(para.Parent(), para)
+	table.InsertBefore(table, table.FirstChild(), caption)
+}
+
+// captionlessTable returns n if it is a table without a caption.
+func captionlessTable(n ast.Node) *extast.Table {
+	table, ok := n.(*extast.Table)
+	if !ok {
+		return nil
+	}
+	if _, captioned := table.FirstChild().(*TableCaption); captioned {
+		return nil
+	}
+	return table
+}
+
+// inlineText returns the text of n's descendants.
+func inlineText(n ast.Node, source []byte) []byte {
+	var b []byte
+	_ = ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if t, ok := c.(*ast.Text); ok && entering {
+			b = append(b, t.Value(source)...)
+		}
+		return ast.WalkContinue, nil
+	})
+	return b
+}
+
+// crossRefRenderer writes figures, table captions and references.
+type crossRefRenderer struct{}
+
+func (r *crossRefRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindFigure, r.renderFigure)
+	reg.Register(KindTableCaption, r.renderTableCaption)
+	reg.Register(KindCrossRef, r.renderCrossRef)
+}
+
+func (r *crossRefRenderer) renderFigure(w util.BufWriter, _ []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*Figure)
+	if entering {
+		_, _ = w.WriteString(`<figure class="` + FigureClass + `"`)
+		if n.ID != "" {
+			_, _ = w.WriteString(` id="`)
+			_, _ = w.Write(util.EscapeHTML([]byte(n.ID)))
+			_, _ = w.WriteString(`"`)
+		}
+		_, _ = w.WriteString(">\n")
+		return ast.WalkContinue, nil
+	}
+	_, _ = w.WriteString("\n<figcaption>" + `<span class="` + FigureLabelClass + `">` + FigureLabel + "</span>")
+	if len(n.Caption) > 0 {
+		_, _ = w.WriteString(": ")
+		html.DefaultWriter.Write(w, n.Caption)
+	}
+	_, _ = w.WriteString("</figcaption>\n</figure>\n")
+	return ast.WalkContinue, nil
+}
+
+func (r *crossRefRenderer) renderTableCaption(w util.BufWriter, _ []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		_, _ = w.WriteString("</caption>\n")
+		return ast.WalkContinue, nil
+	}
+	_, _ = w.WriteString(`<caption><span class="` + TableLabelClass + `">` + TableLabel + "</span>")
+	if node.HasChildren() {
+		_, _ = w.WriteString(": ")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *crossRefRenderer) renderCrossRef(w util.BufWriter, _ []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*CrossRef)
+	_, _ = w.WriteString(`<a class="` + CrossRefClass + `" href="#`)
+	_, _ = w.Write(util.EscapeHTML([]byte(n.Target())))
+	_, _ = w.WriteString(`">@` + n.RefType + ":")
+	_, _ = w.Write(util.EscapeHTML([]byte(n.Label)))
+	_, _ = w.WriteString("</a>")
+	return ast.WalkContinue, nil
+}
+
+// NumberCrossRefs numbers the figures and tables written by
+// CrossRefExtension in document order and replaces the text of each
+// reference with its target's label: "Figure 2", "Table 1", or a section's
+// heading text. It runs on the assembled document, so numbers continue
+// across chapters. A reference without a target, or a figure or table
+// label used twice, is an error.
+func NumberCrossRefs(htmlContent string) (string, error) {
+	if !strings.Contains(htmlContent, `class="`+CrossRefClass+`"`) && !numberLabelPattern.MatchString(htmlContent) {
+		return htmlContent, nil
+	}
+
+	labels := make(map[string]string)
+	figures, tables := 0, 0
+	for _, m := range numberedElementPattern.FindAllStringSubmatch(htmlContent, -1) {
+		id, label := m[2], TableLabel+" "+strconv.Itoa(tables+1)
+		if strings.HasPrefix(m[0], "<figure") {
+			figures++
+			id, label = m[1], FigureLabel+" "+strconv.Itoa(figures)
+		} else {
+			tables++
+		}
+		if id == "" {
+			continue
+		}
+		if _, dup := labels[id]; dup {
+			return "", fmt.Errorf("%w: %s", ErrDuplicateLabel, id)
+		}
+		labels[id] = label
+	}
+
+	figures, tables = 0, 0
+	htmlContent = numberLabelPattern.ReplaceAllStringFunc(htmlContent, func(m string) string {
+		if strings.Contains(m, FigureLabelClass) {
+			figures++
+			return `<span class="` + FigureLabelClass + `">` + FigureLabel + " " + strconv.Itoa(figures) + "</span>"
+		}
+		tables++
+		return `<span class="` + TableLabelClass + `">` + TableLabel + " " + strconv.Itoa(tables) + "</span>"
+	})
+
+	sections := make(map[string]string)
+	for _, m := range headingPattern.FindAllStringSubmatch(htmlContent, -1) {
+		sections[m[2]] = strings.TrimSpace(htmlTagPattern.ReplaceAllString(m[3], ""))
+	}
+
+	var unresolved []string
+	seen := make(map[string]bool)
+	htmlContent = crossRefLinkPattern.ReplaceAllStringFunc(htmlContent, func(link string) string {
+		m := crossRefLinkPattern.FindStringSubmatch(link)
+		target, refType := m[1], m[2]
+		label, ok := labels[target]
+		if refType == "sec" {
+			label, ok = sections[target]
+		} else if ok && !strings.HasPrefix(target, refType+":") {
+			ok = false
+		}
+		if !ok {
+			if ref := "@" + refType + ":" + m[3]; !seen[ref] {
+				seen[ref] = true
+				unresolved = append(unresolved, ref)
+			}
+			return link
+		}
+		return `<a class="` + CrossRefClass + `" href="#` + target + `">` + label + "</a>"
+	})
+	if len(unresolved) > 0 {
+		return "", fmt.Errorf("%w: %s", ErrUnresolvedReference, strings.Join(unresolved, ", "))
+	}
+	return htmlContent, nil
+}
@@ -0,0 +1,280 @@
+package pipeline
+
+// Notes:
+// - CrossRefExtension is tested through NewGoldmarkConverter output
+// - mathBody (math_test.go) extracts the HTML body
+// - NumberCrossRefs is tested on converter output, and on merged chapters
+//   for numbering across chapters
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// TestCrossRefExtension_Figures - Standalone images with titles
+// ---------------------------------------------------------------------------
+
+func TestCrossRefExtension_Figures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			"titled image",
+			`![Arch](arch.png "System architecture")`,
+			"<figure class=\"figure\">\n<img src=\"arch.png\" alt=\"Arch\" />\n<figcaption><span class=\"figure-label\">Figure</span>: System architecture</figcaption>\n</figure>",
+		},
+		{
+			"label sets ID",
+			`![Arch](arch.png "System architecture"){#fig:arch}`,
+			"<figure class=\"figure\" id=\"fig:arch\">\n<img src=\"arch.png\" alt=\"Arch\" />\n<figcaption><span class=\"figure-label\">Figure</span>: System architecture</figcaption>\n</figure>",
+		},
+		{
+			"label without title uses alt text",
+			"![Data *flow*](flow.png) {#fig:flow}",
+			"<figure class=\"figure\" id=\"fig:flow\">\n<img src=\"flow.png\" alt=\"Data flow\" />\n<figcaption><span class=\"figure-label\">Figure</span>: Data flow</figcaption>\n</figure>",
+		},
+		{
+			"caption is escaped",
+			`![x](x.png "A <b> & B")`,
+			"<figure class=\"figure\">\n<img src=\"x.png\" alt=\"x\" />\n<figcaption><span class=\"figure-label\">Figure</span>: A &lt;b&gt; &amp; B</figcaption>\n</figure>",
+		},
+		{
+			"untitled image stays inline",
+			"![x](x.png)",
+			"<p><img src=\"x.png\" alt=\"x\" /></p>",
+		},
+		{
+			"image with text stays inline",
+			`See ![x](x.png "Title") here`,
+			"<p>See <img src=\"x.png\" alt=\"x\" title=\"Title\" /> here</p>",
+		},
+		{
+			"label of another type stays text",
+			"![x](x.png){#tbl:x}",
+			"<p><img src=\"x.png\" alt=\"x\" />{#tbl:x}</p>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := mathBody(t, tt.input); got != tt.want {
+				t.Errorf("ToHTML() body = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestCrossRefExtension_TableCaptions - "Table:" paragraphs
+// ---------------------------------------------------------------------------
+
+func TestCrossRefExtension_TableCaptions(t *testing.T) {
+	t.Parallel()
+
+	const table = "| a |\n|---|\n| 1 |"
+	const rendered = "<thead>\n<tr>\n<th>a</th>\n</tr>\n</thead>\n<tbody>\n<tr>\n<td>1</td>\n</tr>\n</tbody>\n</table>"
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			"caption after table",
+			table + "\n\nTable: Quarterly *results* {#tbl:q}",
+			"<table id=\"tbl:q\">\n<caption><span class=\"table-label\">Table</span>: Quarterly <em>results</em></caption>\n" + rendered,
+		},
+		{
+			"caption before table",
+			"Table: Totals\n\n" + table,
+			"<table>\n<caption><span class=\"table-label\">Table</span>: Totals</caption>\n" + rendered,
+		},
+		{
+			"caption between tables goes to the first",
+			table + "\n\nTable: First\n\n" + table,
+			"<table>\n<caption><span class=\"table-label\">Table</span>: First</caption>\n" + rendered + "\n<table>\n" + rendered,
+		},
+		{
+			"caption away from a table stays a paragraph",
+			"Table: Lonely",
+			"<p>Table: Lonely</p>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := mathBody(t, tt.input); got != tt.want {
+				t.Errorf("ToHTML() body = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestCrossRefExtension_References - @fig:, @tbl: and @sec: links
+// ---------------------------------------------------------------------------
+
+func TestCrossRefExtension_References(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			"figure reference",
+			"See @fig:arch.",
+			"<p>See <a class=\"crossref\" href=\"#fig:arch\">@fig:arch</a>.</p>",
+		},
+		{
+			"section reference targets heading ID",
+			"(@sec:getting-started)",
+			"<p>(<a class=\"crossref\" href=\"#getting-started\">@sec:getting-started</a>)</p>",
+		},
+		{
+			"email address stays text",
+			"mail me@fig:x",
+			"<p>mail me@fig:x</p>",
+		},
+		{
+			"unknown type stays text",
+			"@eq:one",
+			"<p>@eq:one</p>",
+		},
+		{
+			"code span stays text",
+			"`@fig:arch`",
+			"<p><code>@fig:arch</code></p>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := mathBody(t, tt.input); got != tt.want {
+				t.Errorf("ToHTML() body = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestNumberCrossRefs - Numbering and reference resolution
+// ---------------------------------------------------------------------------
+
+func TestNumberCrossRefs(t *testing.T) {
+	t.Parallel()
+
+	markdown := "# Getting Started\n\n" +
+		"See @fig:b, @fig:a, @tbl:t and @sec:getting-started.\n\n" +
+		"![A](a.png \"First\"){#fig:a}\n\n" +
+		"![B](b.png \"Second\"){#fig:b}\n\n" +
+		"| x |\n|---|\n| 1 |\n\nTable: Totals {#tbl:t}\n"
+
+	got, err := NumberCrossRefs(mathBody(t, markdown))
+	if err != nil {
+		t.Fatalf("NumberCrossRefs() unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		`<a class="crossref" href="#fig:b">Figure 2</a>`,
+		`<a class="crossref" href="#fig:a">Figure 1</a>`,
+		`<a class="crossref" href="#tbl:t">Table 1</a>`,
+		`<a class="crossref" href="#getting-started">Getting Started</a>`,
+		`<span class="figure-label">Figure 1</span>: First`,
+		`<span class="figure-label">Figure 2</span>: Second`,
+		`<span class="table-label">Table 1</span>: Totals`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("NumberCrossRefs() missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestNumberCrossRefs_AcrossChapters(t *testing.T) {
+	t.Parallel()
+
+	merged, err := MergeChapters([]ChapterHTML{
+		{HTML: mathBody(t, "![A](a.png \"One\"){#fig:a}\n\nSee @fig:a.")},
+		{HTML: mathBody(t, "![A](a.png \"Two\"){#fig:a}\n\nSee @fig:a.")},
+	})
+	if err != nil {
+		t.Fatalf("MergeChapters() unexpected error: %v", err)
+	}
+
+	got, err := NumberCrossRefs(merged)
+	if err != nil {
+		t.Fatalf("NumberCrossRefs() unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		`<a class="crossref" href="#fig:a">Figure 1</a>`,
+		`<a class="crossref" href="#fig:a-1">Figure 2</a>`,
+		`<span class="figure-label">Figure 2</span>: Two`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("NumberCrossRefs() missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestNumberCrossRefs_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr error
+		wantMsg string
+	}{
+		{
+			name:    "missing figure",
+			input:   "See @fig:nope and @fig:nope.",
+			wantErr: ErrUnresolvedReference,
+			wantMsg: "unresolved cross-reference: @fig:nope",
+		},
+		{
+			name:    "missing section",
+			input:   "# Intro\n\nSee @sec:outro.",
+			wantErr: ErrUnresolvedReference,
+			wantMsg: "unresolved cross-reference: @sec:outro",
+		},
+		{
+			name:    "table reference to a figure",
+			input:   "![A](a.png \"A\"){#fig:a}\n\n@tbl:a",
+			wantErr: ErrUnresolvedReference,
+			wantMsg: "unresolved cross-reference: @tbl:a",
+		},
+		{
+			name:    "duplicate label",
+			input:   "![A](a.png \"A\"){#fig:a}\n\n![B](b.png \"B\"){#fig:a}",
+			wantErr: ErrDuplicateLabel,
+			wantMsg: "duplicate cross-reference label: fig:a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NumberCrossRefs(mathBody(t, tt.input))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NumberCrossRefs() error = %v, want %v", err, tt.wantErr)
+			}
+			if err.Error() != tt.wantMsg {
+				t.Errorf("NumberCrossRefs() error = %q, want %q", err.Error(), tt.wantMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Diagram placeholders wrap the index of an extracted block in its own
+// paragraph, so Goldmark renders each as <p>placeholder</p>, replaced by the
+// rendered diagram after HTML generation.
+const (
+	DiagramStartPlaceholder = "\uE004" // U+E004: Private Use Area
+	DiagramEndPlaceholder   = "\uE005" // U+E005: Private Use Area
+)
+
+var (
+	// Opening fence of a mermaid code block, indented up to 3 spaces.
+	// Captures: 1=indent, 2=fence
+	mermaidFencePattern = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})[ \\t]*mermaid(?:[ \\t].*)?$")
+
+	// Diagram placeholder paragraph in Goldmark output.
+	// Captures: 1=block index
+	diagramPlaceholderPattern = regexp.MustCompile(`<p>` + DiagramStartPlaceholder + `(\d+)` + DiagramEndPlaceholder + `</p>`)
+)
+
+// DiagramBlock is a diagram definition found in the Markdown source.
+type DiagramBlock struct {
+	Label  string // Names the source in errors, e.g. "chapter 2" ("" for none)
+	Line   int    // 1-based line of the opening fence
+	Source string // Definition between the fences
+}
+
+// MermaidRenderer renders Mermaid definitions to SVG markup, one per source.
+// A definition Mermaid rejects is reported as a *MermaidError.
+type MermaidRenderer interface {
+	RenderMermaid(ctx context.Context, sources []string, theme string) ([]string, error)
+}
+
+// MermaidError reports the definition a MermaidRenderer could not render.
+type MermaidError struct {
+	Index   int    // Index of the source passed to RenderMermaid
+	Message string // Mermaid's error message
+}
+
+func (e *MermaidError) Error() string {
+	return fmt.Sprintf("diagram %d: %s", e.Index+1, e.Message)
+}
+
+// ExtractMermaidBlocks replaces ```mermaid code fences with placeholder
+// paragraphs and returns the definitions in document order. Line endings are
+// normalized first; line numbers count from the start of markdown, so run it
+// before frontmatter is stripped. Fences nested in other code blocks are left
+// as is, and an unclosed fence runs to the end of the document, as in
+// CommonMark. Placeholders number the blocks from first, so the blocks of
+// several sources can be rendered in one batch after they are merged.
+func ExtractMermaidBlocks(markdown string, first int) (string, []DiagramBlock) {
+	markdown = normalizeLineEndings(markdown)
+	if !strings.Contains(markdown, "mermaid") {
+		return markdown, nil
+	}
+
+	lines := strings.Split(markdown, "\n")
+	out := make([]string, 0, len(lines))
+	var blocks []DiagramBlock
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		m := mermaidFencePattern.FindStringSubmatch(line)
+		if m == nil {
+			// Skip over other code blocks whole.
+			if f := codeFencePattern.FindStringSubmatch(line); f != nil {
+				end := closingFence(lines, i+1, f[1])
+				out = append(out, lines[i:end]...)
+				i = end - 1
+			} else {
+				out = append(out, line)
+			}
+			continue
+		}
+
+		indent, fence := len(m[1]), m[2]
+		end := closingFence(lines, i+1, fence)
+		body := make([]string, 0, end-i)
+		for _, l := range lines[i+1 : min(end, len(lines))] {
+			if isClosingFence(l, fence) {
+				break
+			}
+			body = append(body, trimIndent(l, indent))
+		}
+		blocks = append(blocks, DiagramBlock{Line: i + 1, Source: strings.Join(body, "\n")})
+		placeholder := DiagramStartPlaceholder + strconv.Itoa(first+len(blocks)-1) + DiagramEndPlaceholder
+		out = append(out, "", placeholder, "")
+		i = end - 1
+	}
+	return strings.Join(out, "\n"), blocks
+}
+
+// closingFence returns the index after the line closing fence, searching
+// from start, or len(lines) if the block is unclosed.
+func closingFence(lines []string, start int, fence string) int {
+	for i := start; i < len(lines); i++ {
+		if isClosingFence(lines[i], fence) {
+			return i + 1
+		}
+	}
+	return len(lines)
+}
+
+// isClosingFence reports whether line closes a block opened by fence: the
+// same character, at least as long, with nothing after it.
+func isClosingFence(line, fence string) bool {
+	m := codeFencePattern.FindStringSubmatch(line)
+	return m != nil && m[1][0] == fence[0] && len(m[1]) >= len(fence) &&
+		strings.TrimSpace(line) == m[1]
+}
+
+// trimIndent removes up to n leading spaces, as CommonMark does for the
+// content of an indented fence.
+func trimIndent(line string, n int) string {
+	for i := 0; i < n && strings.HasPrefix(line, " "); i++ {
+		line = line[1:]
+	}
+	return line
+}
+
+// RenderDiagrams renders blocks with r and returns the HTML figure of each.
+// A rejected definition is reported with its label and the line of its
+// opening fence.
+func RenderDiagrams(ctx context.Context, r MermaidRenderer, blocks []DiagramBlock, theme string) ([]string, error) {
+	if len(blocks) == 0 {
+		return nil, nil
+	}
+
+	sources := make([]string, len(blocks))
+	for i, b := range blocks {
+		sources[i] = b.Source
+	}
+	svgs, err := r.RenderMermaid(ctx, sources, theme)
+	var mermaidErr *MermaidError
+	if errors.As(err, &mermaidErr) && mermaidErr.Index >= 0 && mermaidErr.Index < len(blocks) {
+		b := blocks[mermaidErr.Index]
+		err = fmt.Errorf("mermaid block at line %d: %s", b.Line, mermaidErr.Message)
+		if b.Label != "" {
+			err = fmt.Errorf("%s: %w", b.Label, err)
+		}
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(svgs) != len(blocks) {
+		return nil, fmt.Errorf("renderer returned %d diagrams, want %d", len(svgs), len(blocks))
+	}
+
+	figures := make([]string, len(svgs))
+	for i, svg := range svgs {
+		figures[i] = `<figure class="diagram diagram-mermaid">` + svg + `</figure>`
+	}
+	return figures, nil
+}
+
+// InsertDiagrams replaces the placeholder paragraphs left by
+// ExtractMermaidBlocks with the rendered figures.
+func InsertDiagrams(html string, figures []string) string {
+	if len(figures) == 0 {
+		return html
+	}
+	return diagramPlaceholderPattern.ReplaceAllStringFunc(html, func(m string) string {
+		i, err := strconv.Atoi(diagramPlaceholderPattern.FindStringSubmatch(m)[1])
+		if err != nil || i >= len(figures) {
+			return ""
+		}
+		return figures[i]
+	})
+}
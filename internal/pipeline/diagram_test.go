@@ -0,0 +1,221 @@
+package pipeline
+
+// Notes:
+// - Line numbers count from the top of the source, frontmatter included
+// - RenderDiagrams is tested with a fake MermaidRenderer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// fakeMermaid renders each source as an SVG holding its index.
+type fakeMermaid struct {
+	err   error
+	count int // Number of SVGs to return, -1 = one per source
+}
+
+func (f *fakeMermaid) RenderMermaid(_ context.Context, sources []string, _ string) ([]string, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	n := len(sources)
+	if f.count >= 0 {
+		n = f.count
+	}
+	svgs := make([]string, n)
+	for i := range svgs {
+		svgs[i] = fmt.Sprintf("<svg>%d</svg>", i)
+	}
+	return svgs, nil
+}
+
+// placeholder returns the placeholder paragraph of block i.
+func placeholder(i int) string {
+	return fmt.Sprintf("\n%s%d%s\n", DiagramStartPlaceholder, i, DiagramEndPlaceholder)
+}
+
+// ---------------------------------------------------------------------------
+// TestExtractMermaidBlocks - Fence detection
+// ---------------------------------------------------------------------------
+
+func TestExtractMermaidBlocks(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		input      string
+		first      int
+		wantMD     string
+		wantBlocks []DiagramBlock
+	}{
+		{
+			name:   "no mermaid unchanged",
+			input:  "# Title\n\n```go\nx\n```",
+			wantMD: "# Title\n\n```go\nx\n```",
+		},
+		{
+			name:       "backtick fence",
+			input:      "# Title\n```mermaid\ngraph TD\n  A --> B\n```\nafter",
+			wantMD:     "# Title\n" + placeholder(0) + "\nafter",
+			wantBlocks: []DiagramBlock{{Line: 2, Source: "graph TD\n  A --> B"}},
+		},
+		{
+			name:       "placeholders numbered from first",
+			input:      "```mermaid\ngraph\n```\n```mermaid\npie\n```",
+			first:      3,
+			wantMD:     placeholder(3) + "\n" + placeholder(4),
+			wantBlocks: []DiagramBlock{{Line: 1, Source: "graph"}, {Line: 4, Source: "pie"}},
+		},
+		{
+			name:       "tilde fence with attributes and CRLF",
+			input:      "a\r\n\r\n~~~~ mermaid {theme: dark}\r\npie\r\n~~~~\r\n",
+			wantMD:     "a\n\n" + placeholder(0) + "\n",
+			wantBlocks: []DiagramBlock{{Line: 3, Source: "pie"}},
+		},
+		{
+			name:       "indented fence strips indent",
+			input:      "  ```mermaid\n  graph LR\n    A\n  ```",
+			wantMD:     placeholder(0),
+			wantBlocks: []DiagramBlock{{Line: 1, Source: "graph LR\n  A"}},
+		},
+		{
+			name:       "shorter fence does not close",
+			input:      "````mermaid\ngraph\n```\n````\nend",
+			wantMD:     placeholder(0) + "\nend",
+			wantBlocks: []DiagramBlock{{Line: 1, Source: "graph\n```"}},
+		},
+		{
+			name:       "unclosed fence runs to end",
+			input:      "```mermaid\ngraph",
+			wantMD:     placeholder(0),
+			wantBlocks: []DiagramBlock{{Line: 1, Source: "graph"}},
+		},
+		{
+			name:       "fence inside another code block unchanged",
+			input:      "````markdown\n```mermaid\ngraph\n```\n````\n```mermaid\npie\n```",
+			wantMD:     "````markdown\n```mermaid\ngraph\n```\n````\n" + placeholder(0),
+			wantBlocks: []DiagramBlock{{Line: 6, Source: "pie"}},
+		},
+		{
+			name:   "mermaidjs info string is not mermaid",
+			input:  "```mermaidjs\ngraph\n```",
+			wantMD: "```mermaidjs\ngraph\n```",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotMD, gotBlocks := ExtractMermaidBlocks(tt.input, tt.first)
+			if gotMD != tt.wantMD {
+				t.Errorf("ExtractMermaidBlocks() markdown = %q, want %q", gotMD, tt.wantMD)
+			}
+			if fmt.Sprint(gotBlocks) != fmt.Sprint(tt.wantBlocks) {
+				t.Errorf("ExtractMermaidBlocks() blocks = %+v, want %+v", gotBlocks, tt.wantBlocks)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestRenderDiagrams - Rendering and error lines
+// ---------------------------------------------------------------------------
+
+func TestRenderDiagrams(t *testing.T) {
+	t.Parallel()
+
+	blocks := []DiagramBlock{{Line: 3, Source: "graph"}, {Line: 12, Source: "pie"}}
+
+	t.Run("wraps each SVG in a figure", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := RenderDiagrams(context.Background(), &fakeMermaid{count: -1}, blocks, "dark")
+		if err != nil {
+			t.Fatalf("RenderDiagrams() unexpected error: %v", err)
+		}
+		want := []string{
+			`<figure class="diagram diagram-mermaid"><svg>0</svg></figure>`,
+			`<figure class="diagram diagram-mermaid"><svg>1</svg></figure>`,
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("RenderDiagrams() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("mermaid error names the line", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := RenderDiagrams(context.Background(), &fakeMermaid{err: &MermaidError{Index: 1, Message: "bad syntax"}}, blocks, "")
+		if err == nil || err.Error() != "mermaid block at line 12: bad syntax" {
+			t.Errorf("RenderDiagrams() error = %v, want line 12", err)
+		}
+	})
+
+	t.Run("mermaid error names the source label", func(t *testing.T) {
+		t.Parallel()
+
+		labeled := []DiagramBlock{{Line: 3, Source: "graph"}, {Label: "chapter 2", Line: 5, Source: "pie"}}
+		_, err := RenderDiagrams(context.Background(), &fakeMermaid{err: &MermaidError{Index: 1, Message: "bad syntax"}}, labeled, "")
+		if err == nil || err.Error() != "chapter 2: mermaid block at line 5: bad syntax" {
+			t.Errorf("RenderDiagrams() error = %v, want chapter 2 line 5", err)
+		}
+	})
+
+	t.Run("other errors are returned", func(t *testing.T) {
+		t.Parallel()
+
+		errBrowser := errors.New("browser gone")
+		_, err := RenderDiagrams(context.Background(), &fakeMermaid{err: errBrowser}, blocks, "")
+		if !errors.Is(err, errBrowser) {
+			t.Errorf("RenderDiagrams() error = %v, want %v", err, errBrowser)
+		}
+	})
+
+	t.Run("count mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := RenderDiagrams(context.Background(), &fakeMermaid{count: 1}, blocks, "")
+		if err == nil {
+			t.Error("RenderDiagrams() error = nil, want error")
+		}
+	})
+
+	t.Run("no blocks skips the renderer", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := RenderDiagrams(context.Background(), &fakeMermaid{err: errors.New("called")}, nil, "")
+		if got != nil || err != nil {
+			t.Errorf("RenderDiagrams() = %v, %v, want nil, nil", got, err)
+		}
+	})
+}
+
+// ---------------------------------------------------------------------------
+// TestInsertDiagrams - Placeholder replacement after Goldmark
+// ---------------------------------------------------------------------------
+
+func TestInsertDiagrams(t *testing.T) {
+	t.Parallel()
+
+	md, blocks := ExtractMermaidBlocks("Intro\n```mermaid\ngraph\n```\nOutro", 0)
+	html, err := NewGoldmarkConverter().ToHTML(context.Background(), md)
+	if err != nil {
+		t.Fatalf("ToHTML() unexpected error: %v", err)
+	}
+	if len(blocks) != 1 {
+		t.Fatalf("ExtractMermaidBlocks() blocks = %d, want 1", len(blocks))
+	}
+
+	got := InsertDiagrams(html, []string{"<figure>D</figure>"})
+	if !strings.Contains(got, "<p>Intro</p>\n<figure>D</figure>\n<p>Outro</p>") {
+		t.Errorf("InsertDiagrams() = %s, want figure between paragraphs", got)
+	}
+	if strings.Contains(got, DiagramStartPlaceholder) {
+		t.Errorf("InsertDiagrams() left a placeholder: %q", got)
+	}
+}
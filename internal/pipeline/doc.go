@@ -7,6 +7,7 @@
 //   - Cover page injection
 //   - Table of contents generation and injection
 //   - Signature block injection
+//   - Multi-chapter merging (book mode)
 //
 // PDF generation is handled separately by the root md2pdf package using
 // headless Chrome (go-rod). This separation keeps the pipeline focused on
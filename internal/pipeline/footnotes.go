@@ -0,0 +1,251 @@
+package pipeline
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Classes of the page footnote area. The area keeps goldmark's "footnotes"
+// class, so themes style page footnotes and endnotes alike.
+const (
+	PageFootnotesClass       = "page-footnotes"
+	FootnoteMeasurementClass = "page-footnotes-measure"
+)
+
+// IDs of the markers FootnoteMeasurement places around the footnote area.
+// Chrome writes a named destination for each, whose coordinates locate the
+// area on the page.
+const (
+	FootnoteAreaStartID  = "picoloom-footnotes-start"  // Before the area
+	FootnoteAreaEndID    = "picoloom-footnotes-end"    // After the area
+	FootnoteAreaRightID  = "picoloom-footnotes-right"  // Right edge of the area
+	FootnotePageBottomID = "picoloom-footnotes-bottom" // Bottom of the page area
+)
+
+var (
+	// Endnote list goldmark writes at the end of the document, or of each
+	// chapter once chapters are merged.
+	footnotesBlockPattern = regexp.MustCompile(`<div class="footnotes" role="doc-endnotes">`)
+
+	// Footnote reference.
+	// Captures: 1=reference ID, 2=note ID
+	footnoteRefPattern = regexp.MustCompile(`<sup id="([^"]*)"><a href="#([^"]*)" class="footnote-ref" role="doc-noteref">[^<]*</a></sup>`)
+
+	// Link back from a note to its reference, with the space before it.
+	footnoteBackrefPattern = regexp.MustCompile(`(?:&nbsp;|&#160;|\x{a0})?<a href="[^"]*" class="footnote-backref"[^>]*>[^<]*</a>`)
+
+	// Reference rewritten by ExtractFootnotes.
+	// Captures: 1=tag up to the label, 2=note number
+	pageFootnoteRefPattern = regexp.MustCompile(`(<sup id="[^"]*" data-footnote="(\d+)"><a href="#[^"]*" class="footnote-ref" role="doc-noteref">)[^<]*</a>`)
+)
+
+// Footnote is a note taken out of goldmark's endnote list.
+type Footnote struct {
+	RefID string // ID of the note's first reference
+	HTML  string // Note content, without the links back to the text
+}
+
+// ExtractFootnotes removes goldmark's endnote lists and returns their notes
+// in the order of their first reference. Each reference becomes a link to
+// itself, so Chrome writes a named destination locating it, and is
+// numbered with its note's position until NumberFootnotes relabels it.
+// Notes without a reference are dropped, as goldmark does.
+func ExtractFootnotes(htmlContent string) (string, []Footnote, error) {
+	contents := make(map[string]string)
+	var b strings.Builder
+	rest := htmlContent
+	for {
+		loc := footnotesBlockPattern.FindStringIndex(rest)
+		if loc == nil {
+			break
+		}
+		end := closingDivEnd(rest[loc[0]:])
+		if end < 0 {
+			return "", nil, fmt.Errorf("unterminated footnote list")
+		}
+		if err := collectFootnotes(rest[loc[0]:loc[0]+end], contents); err != nil {
+			return "", nil, fmt.Errorf("parsing footnote list: %w", err)
+		}
+		b.WriteString(rest[:loc[0]])
+		rest = rest[loc[0]+end:]
+	}
+	b.WriteString(rest)
+	if len(contents) == 0 {
+		return htmlContent, nil, nil
+	}
+
+	var notes []Footnote
+	numbers := make(map[string]int)
+	body := footnoteRefPattern.ReplaceAllStringFunc(b.String(), func(ref string) string {
+		m := footnoteRefPattern.FindStringSubmatch(ref)
+		content, ok := contents[m[2]]
+		if !ok {
+			return ref
+		}
+		n, seen := numbers[m[2]]
+		if !seen {
+			notes = append(notes, Footnote{RefID: m[1], HTML: content})
+			n = len(notes)
+			numbers[m[2]] = n
+		}
+		label := strconv.Itoa(n)
+		return `<sup id="` + m[1] + `" data-footnote="` + label + `"><a href="#` + m[1] +
+			`" class="footnote-ref" role="doc-noteref">` + label + `</a></sup>`
+	})
+	return body, notes, nil
+}
+
+// closingDivEnd returns the offset just past the end tag closing the div s
+// starts with, or -1 if it is not closed.
+func closingDivEnd(s string) int {
+	z := html.NewTokenizer(strings.NewReader(s))
+	depth, offset := 0, 0
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			return -1
+		}
+		offset += len(z.Raw())
+		name, _ := z.TagName()
+		if string(name) != "div" {
+			continue
+		}
+		switch tt {
+		case html.StartTagToken:
+			depth++
+		case html.EndTagToken:
+			if depth--; depth == 0 {
+				return offset
+			}
+		}
+	}
+}
+
+// collectFootnotes adds the content of each note of an endnote list to
+// contents, keyed by the note's ID.
+func collectFootnotes(block string, contents map[string]string) error {
+	doc, _, err := parseHTML(block)
+	if err != nil {
+		return err
+	}
+	var walk func(n *html.Node) error
+	walk = func(n *html.Node) error {
+		if n.Type == html.ElementNode && n.Data == "li" {
+			if id := nodeID(n); id != "" {
+				var buf strings.Builder
+				for c := n.FirstChild; c != nil; c = c.NextSibling {
+					if err := html.Render(&buf, c); err != nil {
+						return err
+					}
+				}
+				contents[id] = strings.TrimSpace(footnoteBackrefPattern.ReplaceAllString(buf.String(), ""))
+				return nil
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if err := walk(c); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return walk(doc)
+}
+
+// nodeID returns the id attribute of n, or "".
+func nodeID(n *html.Node) string {
+	for _, attr := range n.Attr {
+		if attr.Key == "id" {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// NumberFootnotes relabels the references left by ExtractFootnotes: the
+// references of note i (from 1) show labels[i-1].
+func NumberFootnotes(htmlContent string, labels []int) string {
+	return pageFootnoteRefPattern.ReplaceAllStringFunc(htmlContent, func(ref string) string {
+		m := pageFootnoteRefPattern.FindStringSubmatch(ref)
+		n, _ := strconv.Atoi(m[2])
+		if n < 1 || n > len(labels) {
+			return ref
+		}
+		return m[1] + strconv.Itoa(labels[n-1]) + "</a>"
+	})
+}
+
+// FootnoteArea returns notes as the footnote area of a page: a list
+// numbered with labels, under the separator rule the theme draws.
+func FootnoteArea(notes []Footnote, labels []int) string {
+	var b strings.Builder
+	writeFootnoteArea(&b, notes, labels, nil)
+	return b.String()
+}
+
+// FootnoteMeasurementIDs returns the IDs of the markers FootnoteMeasurement
+// places at the start and end of note i (from 1).
+func FootnoteMeasurementIDs(i int) (start, end string) {
+	prefix := "picoloom-footnote-" + strconv.Itoa(i)
+	return prefix + "-start", prefix + "-end"
+}
+
+// FootnoteMeasurement inserts every note, as one footnote area, on a page of
+// its own at the end of the body. Markers around the area and each note
+// locate them in the PDF, giving the height of the separator and of each
+// note, the width of the area, and the bottom of the page area.
+func FootnoteMeasurement(htmlContent string, notes []Footnote, labels []int) string {
+	var b strings.Builder
+	b.WriteString(`<div class="` + FootnoteMeasurementClass + `">`)
+	writeMarker(&b, FootnoteAreaStartID, "")
+	writeFootnoteArea(&b, notes, labels, func(i int) (string, string) {
+		var start, end strings.Builder
+		startID, endID := FootnoteMeasurementIDs(i)
+		writeMarker(&start, startID, "")
+		writeMarker(&end, endID, "")
+		return start.String(), end.String()
+	})
+	writeMarker(&b, FootnoteAreaEndID, "")
+	writeMarker(&b, FootnoteAreaRightID, "width: 0; margin-left: auto;")
+	writeMarker(&b, FootnotePageBottomID, "position: fixed; bottom: 0; left: 0;")
+	b.WriteString(`</div>`)
+
+	if idx := strings.LastIndex(strings.ToLower(htmlContent), "</body>"); idx != -1 {
+		return htmlContent[:idx] + b.String() + htmlContent[idx:]
+	}
+	return htmlContent + b.String()
+}
+
+// writeFootnoteArea writes the footnote area. markers, if set, returns the
+// markers written at the start and end of note i (from 1).
+func writeFootnoteArea(b *strings.Builder, notes []Footnote, labels []int, markers func(i int) (string, string)) {
+	b.WriteString(`<div class="footnotes ` + PageFootnotesClass + `"><ol>`)
+	for i, note := range notes {
+		label := i + 1
+		if i < len(labels) {
+			label = labels[i]
+		}
+		b.WriteString(`<li value="` + strconv.Itoa(label) + `">`)
+		start, end := "", ""
+		if markers != nil {
+			start, end = markers(i + 1)
+		}
+		b.WriteString(start + note.HTML + end + `</li>`)
+	}
+	b.WriteString(`</ol></div>`)
+}
+
+// writeMarker writes an empty link target with the given ID and style.
+// The link makes it a target, the only elements Chrome writes named
+// destinations for.
+func writeMarker(b *strings.Builder, id, style string) {
+	attr := ""
+	if style != "" {
+		attr = ` style="` + style + `"`
+	}
+	b.WriteString(`<div id="` + id + `"` + attr + `><a href="#` + id + `"></a></div>`)
+}
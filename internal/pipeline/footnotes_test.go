@@ -0,0 +1,187 @@
+package pipeline
+
+// Notes:
+// - ExtractFootnotes is tested on goldmark output, single and merged, since
+//   it matches the markup goldmark writes and MergeChapters re-renders
+// - Marker placement is checked as markup; their coordinates only exist in
+//   a PDF printed by Chrome
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// footnotesHTML converts markdown with goldmark's footnote extension.
+func footnotesHTML(t *testing.T, markdown string) string {
+	t.Helper()
+
+	got, err := NewGoldmarkConverter().ToHTML(context.Background(), markdown)
+	if err != nil {
+		t.Fatalf("ToHTML() unexpected error: %v", err)
+	}
+	return got
+}
+
+// ---------------------------------------------------------------------------
+// TestExtractFootnotes - Endnote lists to page footnotes
+// ---------------------------------------------------------------------------
+
+func TestExtractFootnotes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("takes notes out in reference order", func(t *testing.T) {
+		t.Parallel()
+
+		content := footnotesHTML(t, "First[^b] then[^a] and[^b] again.\n\n"+
+			"[^a]: Note *A*.\n\n    Second paragraph.\n[^b]: Note B.\n")
+		got, notes, err := ExtractFootnotes(content)
+		if err != nil {
+			t.Fatalf("ExtractFootnotes() unexpected error: %v", err)
+		}
+
+		want := []Footnote{
+			{RefID: "fnref:1", HTML: "<p>Note B.</p>"},
+			{RefID: "fnref:2", HTML: "<p>Note <em>A</em>.</p>\n<p>Second paragraph.</p>"},
+		}
+		if len(notes) != len(want) {
+			t.Fatalf("ExtractFootnotes() notes = %q, want %q", notes, want)
+		}
+		for i := range want {
+			if notes[i] != want[i] {
+				t.Errorf("note %d = %q, want %q", i+1, notes[i], want[i])
+			}
+		}
+		for _, ref := range []string{
+			`First<sup id="fnref:1" data-footnote="1"><a href="#fnref:1" class="footnote-ref" role="doc-noteref">1</a></sup>`,
+			`then<sup id="fnref:2" data-footnote="2"><a href="#fnref:2" class="footnote-ref" role="doc-noteref">2</a></sup>`,
+			`and<sup id="fnref1:1" data-footnote="1"><a href="#fnref1:1" class="footnote-ref" role="doc-noteref">1</a></sup>`,
+		} {
+			if !strings.Contains(got, ref) {
+				t.Errorf("ExtractFootnotes() missing reference %q in:\n%s", ref, got)
+			}
+		}
+		if strings.Contains(got, "doc-endnotes") || strings.Contains(got, "footnote-backref") {
+			t.Errorf("ExtractFootnotes() left the endnote list:\n%s", got)
+		}
+	})
+
+	t.Run("numbers notes across merged chapters", func(t *testing.T) {
+		t.Parallel()
+
+		merged, err := MergeChapters([]ChapterHTML{
+			{HTML: footnotesHTML(t, "# One\n\nA[^1].\n\n[^1]: First.\n")},
+			{HTML: footnotesHTML(t, "# Two\n\nB[^1].\n\n[^1]: Second.\n")},
+		})
+		if err != nil {
+			t.Fatalf("MergeChapters() unexpected error: %v", err)
+		}
+		got, notes, err := ExtractFootnotes(merged)
+		if err != nil {
+			t.Fatalf("ExtractFootnotes() unexpected error: %v", err)
+		}
+		if len(notes) != 2 || notes[0].HTML != "<p>First.</p>" || notes[1].HTML != "<p>Second.</p>" {
+			t.Fatalf("ExtractFootnotes() notes = %q, want First and Second", notes)
+		}
+		if !strings.Contains(got, `data-footnote="2"><a href="#`+notes[1].RefID+`" class="footnote-ref" role="doc-noteref">2</a>`) {
+			t.Errorf("ExtractFootnotes() did not number the second chapter's note 2:\n%s", got)
+		}
+	})
+
+	t.Run("document without footnotes is unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		content := footnotesHTML(t, "# Plain\n\nText.\n")
+		got, notes, err := ExtractFootnotes(content)
+		if err != nil || got != content || notes != nil {
+			t.Errorf("ExtractFootnotes() = %q, %q, %v, want content unchanged", got, notes, err)
+		}
+	})
+
+	t.Run("unterminated list is an error", func(t *testing.T) {
+		t.Parallel()
+
+		if _, _, err := ExtractFootnotes(`<p>x</p><div class="footnotes" role="doc-endnotes"><ol><li id="fn:1">`); err == nil {
+			t.Error("ExtractFootnotes() expected error, got nil")
+		}
+	})
+}
+
+// ---------------------------------------------------------------------------
+// TestNumberFootnotes - Reference labels
+// ---------------------------------------------------------------------------
+
+func TestNumberFootnotes(t *testing.T) {
+	t.Parallel()
+
+	content, _, err := ExtractFootnotes(footnotesHTML(t, "A[^x] B[^y] C[^x].\n\n[^x]: X.\n[^y]: Y.\n"))
+	if err != nil {
+		t.Fatalf("ExtractFootnotes() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		labels []int
+		want   []string
+	}{
+		{"per page labels", []int{1, 1}, []string{`role="doc-noteref">1</a>`, `role="doc-noteref">1</a>`, `role="doc-noteref">1</a>`}},
+		{"custom labels", []int{7, 8}, []string{`role="doc-noteref">7</a>`, `role="doc-noteref">8</a>`, `role="doc-noteref">7</a>`}},
+		{"missing labels keep numbers", nil, []string{`role="doc-noteref">1</a>`, `role="doc-noteref">2</a>`, `role="doc-noteref">1</a>`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := NumberFootnotes(content, tt.labels)
+			refs := pageFootnoteRefPattern.FindAllString(got, -1)
+			if len(refs) != len(tt.want) {
+				t.Fatalf("NumberFootnotes() refs = %q, want %d", refs, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.HasSuffix(refs[i], want) {
+					t.Errorf("reference %d = %q, want suffix %q", i+1, refs[i], want)
+				}
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestFootnoteArea - Page footnote markup
+// ---------------------------------------------------------------------------
+
+func TestFootnoteArea(t *testing.T) {
+	t.Parallel()
+
+	notes := []Footnote{{RefID: "r1", HTML: "<p>One.</p>"}, {RefID: "r2", HTML: "<p>Two.</p>"}}
+
+	t.Run("numbers notes with labels", func(t *testing.T) {
+		t.Parallel()
+
+		got := FootnoteArea(notes, []int{3, 4})
+		want := `<div class="footnotes page-footnotes"><ol>` +
+			`<li value="3"><p>One.</p></li><li value="4"><p>Two.</p></li></ol></div>`
+		if got != want {
+			t.Errorf("FootnoteArea() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("measurement brackets notes with markers", func(t *testing.T) {
+		t.Parallel()
+
+		got := FootnoteMeasurement("<html><body><p>x</p></body></html>", notes, []int{1, 2})
+		start, end := FootnoteMeasurementIDs(2)
+		for _, want := range []string{
+			`<p>x</p><div class="page-footnotes-measure"><div id="picoloom-footnotes-start"><a href="#picoloom-footnotes-start"></a></div>`,
+			`<li value="2"><div id="` + start + `"><a href="#` + start + `"></a></div><p>Two.</p><div id="` + end + `"><a href="#` + end + `"></a></div></li>`,
+			`</ol></div><div id="picoloom-footnotes-end">`,
+			`<div id="picoloom-footnotes-bottom" style="position: fixed; bottom: 0; left: 0;">`,
+			`</div></body></html>`,
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("FootnoteMeasurement() missing %q in:\n%s", want, got)
+			}
+		}
+	})
+}
@@ -81,6 +81,26 @@ type SignatureData struct {
 	Phone      string
 	Address    string
 	Department string
+	// FieldAnchors surrounds the block with SignatureFieldStartID and
+	// SignatureFieldEndID, so its position can be read from the PDF.
+	FieldAnchors bool
+}
+
+// IDs of the elements that delimit the signature block when
+// SignatureData.FieldAnchors is set. Chrome writes a named destination for
+// each, which locates the visible digital signature field.
+const (
+	SignatureFieldStartID = "picoloom-signature-start"
+	SignatureFieldEndID   = "picoloom-signature-end"
+)
+
+// signatureAnchors returns the markers placed before and after the signature
+// block. The empty links make the markers link targets, the only elements
+// Chrome writes named destinations for.
+func signatureAnchors() (start, end string) {
+	links := `<a href="#` + SignatureFieldStartID + `"></a><a href="#` + SignatureFieldEndID + `"></a>`
+	return `<div id="` + SignatureFieldStartID + `">` + links + "</div>\n",
+		`<div id="` + SignatureFieldEndID + `"></div>` + "\n"
 }
 
 // SignatureLink represents a clickable link in the signature block.
@@ -129,6 +149,10 @@ func (s *SignatureInjection) InjectSignature(ctx context.Context, htmlContent st
 	}
 
 	signatureHTML := buf.String()
+	if data.FieldAnchors {
+		start, end := signatureAnchors()
+		signatureHTML = start + signatureHTML + end
+	}
 	lowerHTML := strings.ToLower(htmlContent)
 
 	// Try inserting before </body>
@@ -225,11 +249,28 @@ type FooterData struct {
 	DocumentID     string // Document reference number
 }
 
+// HeaderData holds running header configuration for PDF generation.
+// Slots contain free text with {placeholder} markers resolved at render time.
+type HeaderData struct {
+	Left       string
+	Center     string
+	Right      string
+	Title      string
+	Version    string
+	DocumentID string
+	Date       string
+}
+
 // TOCData holds TOC configuration for injection.
 type TOCData struct {
 	Title    string
 	MinDepth int // Minimum heading level (default: 2, skips H1)
 	MaxDepth int // Maximum heading level (default: 3)
+
+	// PageNumbers maps heading IDs to page numbers. A non-nil map adds a
+	// dot leader and page column to every entry; headings missing from the
+	// map get an empty column, which keeps the layout stable between passes.
+	PageNumbers map[string]int
 }
 
 // TOCInjector defines the contract for TOC injection into HTML.
@@ -336,20 +377,20 @@ func (n *numberingState) next(level int) (numStr string, effectiveDepth int) {
 
 // generateNumberedTOC creates HTML for a numbered table of contents.
 // Uses <div> elements instead of <ul>/<li> to avoid CSS list-style conflicts.
-func generateNumberedTOC(headings []headingInfo, title string) string {
+// A non-nil pages map adds page columns (see TOCData.PageNumbers).
+func generateNumberedTOC(headings []headingInfo, title string, pages map[string]int) string {
 	if len(headings) == 0 {
 		return ""
 	}
 
 	var buf strings.Builder
-	buf.WriteString(`<nav class="toc">`)
-
-	if title != "" {
-		buf.WriteString(`<h2 class="toc-title">`)
-		buf.WriteString(html.EscapeString(title))
-		buf.WriteString(`</h2>`)
+	if pages != nil {
+		buf.WriteString(`<nav class="toc toc-paged">`)
+	} else {
+		buf.WriteString(`<nav class="toc">`)
 	}
 
+	writeTOCTitle(&buf, title)
 	buf.WriteString(`<div class="toc-list">`)
 
 	numbering := newNumberingState()
@@ -366,19 +407,50 @@ func generateNumberedTOC(headings []headingInfo, title string) string {
 		if indent > 0 {
 			buf.WriteString(fmt.Sprintf(` style="padding-left:%.1fem"`, indent))
 		}
-		buf.WriteString(`><a href="#`)
-		buf.WriteString(html.EscapeString(h.ID))
-		buf.WriteString(`">`)
-		buf.WriteString(num)
-		buf.WriteString(` `)
-		buf.WriteString(html.EscapeString(h.Text))
-		buf.WriteString(`</a></div>`)
+		buf.WriteString(`>`)
+		writeTOCLink(&buf, h.ID, num+" "+h.Text, pages)
+		buf.WriteString(`</div>`)
 	}
 
 	buf.WriteString(`</div></nav>`)
 	return buf.String()
 }
 
+// writeTOCTitle writes the heading of a TOC nav, if any.
+func writeTOCTitle(buf *strings.Builder, title string) {
+	if title == "" {
+		return
+	}
+	buf.WriteString(`<h2 class="toc-title">`)
+	buf.WriteString(html.EscapeString(title))
+	buf.WriteString(`</h2>`)
+}
+
+// writeTOCLink writes the link of a TOC item. A non-nil pages map adds a dot
+// leader and page column.
+func writeTOCLink(buf *strings.Builder, id, text string, pages map[string]int) {
+	buf.WriteString(`<a href="#`)
+	buf.WriteString(html.EscapeString(id))
+	buf.WriteString(`">`)
+	if pages == nil {
+		buf.WriteString(html.EscapeString(text))
+		buf.WriteString(`</a>`)
+		return
+	}
+	buf.WriteString(`<span class="toc-text">`)
+	buf.WriteString(html.EscapeString(text))
+	buf.WriteString(`</span><span class="toc-leader"></span><span class="toc-page">`)
+	if page, ok := pages[id]; ok {
+		buf.WriteString(strconv.Itoa(page))
+	}
+	buf.WriteString(`</span></a>`)
+}
+
+// coverEndPattern matches the end of the cover page.
+// Note: We use <span data-cover-end> instead of <!-- cover-end --> comment
+// because html/template strips HTML comments for security reasons.
+var coverEndPattern = regexp.MustCompile(`(?i)</div>\s*</section>\s*<span[^>]*data-cover-end[^>]*>\s*</span>`)
+
 // TOCInjection implements TOCInjector.
 type TOCInjection struct{}
 
@@ -406,7 +478,7 @@ func (t *TOCInjection) InjectTOC(ctx context.Context, htmlContent string, data *
 	}
 
 	// Generate TOC HTML
-	tocHTML := generateNumberedTOC(headings, data.Title)
+	tocHTML := generateNumberedTOC(headings, data.Title, data.PageNumbers)
 	if tocHTML == "" {
 		return htmlContent, nil
 	}
@@ -414,9 +486,6 @@ func (t *TOCInjection) InjectTOC(ctx context.Context, htmlContent string, data *
 	lowerHTML := strings.ToLower(htmlContent)
 
 	// Try inserting after cover page marker.
-	// Note: We use <span data-cover-end> instead of <!-- cover-end --> comment
-	// because html/template strips HTML comments for security reasons.
-	coverEndPattern := regexp.MustCompile(`(?i)</div>\s*</section>\s*<span[^>]*data-cover-end[^>]*>\s*</span>`)
 	if loc := coverEndPattern.FindStringIndex(htmlContent); loc != nil {
 		insertPos := loc[1]
 		return htmlContent[:insertPos] + tocHTML + htmlContent[insertPos:], nil
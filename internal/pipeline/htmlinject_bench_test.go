@@ -202,7 +202,7 @@ func BenchmarkGenerateNumberedTOC(b *testing.B) {
 			b.ResetTimer()
 
 			for i := 0; i < b.N; i++ {
-				result := generateNumberedTOC(headings, "Table of Contents")
+				result := generateNumberedTOC(headings, "Table of Contents", nil)
 				_ = result
 			}
 		})
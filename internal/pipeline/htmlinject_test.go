@@ -332,6 +332,48 @@ func TestSignatureInjection_InjectSignature(t *testing.T) {
 			t.Error("empty title should not render empty <em> tag")
 		}
 	})
+
+	t.Run("field anchors surround the block", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		html := "<html><body>Content</body></html>"
+		tests := []struct {
+			name    string
+			anchors bool
+		}{
+			{"enabled", true},
+			{"disabled", false},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				got, err := injector.InjectSignature(ctx, html, &SignatureData{Name: "Jane", FieldAnchors: tt.anchors})
+				if err != nil {
+					t.Fatalf("SignatureInjection.InjectSignature() unexpected error: %v", err)
+				}
+
+				start := strings.Index(got, `id="`+SignatureFieldStartID+`"`)
+				block := strings.Index(got, "signature-block")
+				end := strings.Index(got, `id="`+SignatureFieldEndID+`"`)
+				if !tt.anchors {
+					if start != -1 || end != -1 {
+						t.Error("anchors should not be rendered")
+					}
+					return
+				}
+				if start == -1 || end == -1 || start > block || block > end {
+					t.Errorf("anchors should surround the signature block, got %q", got)
+				}
+				for _, id := range []string{SignatureFieldStartID, SignatureFieldEndID} {
+					if !strings.Contains(got, `href="#`+id+`"`) {
+						t.Errorf("missing link to %q, Chrome only writes destinations for link targets", id)
+					}
+				}
+			})
+		}
+	})
 }
 
 func TestSignatureInjection_InjectSignature_ContextCancellation(t *testing.T) {
@@ -1013,6 +1055,7 @@ func TestGenerateNumberedTOC(t *testing.T) {
 		name         string
 		headings     []headingInfo
 		title        string
+		pages        map[string]int
 		wantEmpty    bool
 		wantContains []string
 	}{
@@ -1132,13 +1175,37 @@ func TestGenerateNumberedTOC(t *testing.T) {
 				`A &amp; B &lt; C &gt; D`,
 			},
 		},
+		// Page numbers
+		{
+			name: "page numbers add leader and page columns",
+			headings: []headingInfo{
+				{Level: 1, ID: "intro", Text: "Intro"},
+				{Level: 2, ID: "usage", Text: "Usage"},
+			},
+			pages: map[string]int{"intro": 2, "usage": 5},
+			wantContains: []string{
+				`<nav class="toc toc-paged">`,
+				`<span class="toc-text">1. Intro</span><span class="toc-leader"></span><span class="toc-page">2</span>`,
+				`<span class="toc-text">1.1. Usage</span><span class="toc-leader"></span><span class="toc-page">5</span>`,
+			},
+		},
+		{
+			name: "unknown heading gets empty page column",
+			headings: []headingInfo{
+				{Level: 1, ID: "intro", Text: "Intro"},
+			},
+			pages: map[string]int{},
+			wantContains: []string{
+				`<span class="toc-page"></span>`,
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			got := generateNumberedTOC(tt.headings, tt.title)
+			got := generateNumberedTOC(tt.headings, tt.title, tt.pages)
 
 			if tt.wantEmpty {
 				if got != "" {
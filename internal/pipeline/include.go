@@ -0,0 +1,244 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MaxIncludeDepth limits nested includes, as a backstop to cycle detection.
+const MaxIncludeDepth = 16
+
+// Sentinel errors for include directives.
+var (
+	ErrInvalidInclude     = errors.New("invalid include directive")
+	ErrIncludeCycle       = errors.New("include cycle")
+	ErrIncludeOutsideRoot = errors.New("include outside source directory")
+	ErrIncludeDepth       = errors.New("includes nested too deeply")
+)
+
+var (
+	// Include directive on its own line, indented up to 3 spaces.
+	// Captures: 1=arguments
+	includePattern = regexp.MustCompile(`^ {0,3}!include\(([^)]*)\)[ \t]*$`)
+
+	// Line range option: "3-10", "3-" or "3".
+	// Captures: 1=first line, 2=dash, 3=last line
+	lineRangePattern = regexp.MustCompile(`^(\d+)(-)?(\d*)$`)
+
+	// ATX heading marker.
+	// Captures: 1=indent, 2=hashes, 3=rest of line
+	atxHeadingPattern = regexp.MustCompile(`^( {0,3})(#{1,6})([ \t].*|)$`)
+)
+
+// include is a parsed !include(path, lines=A-B, shift=N) directive.
+type include struct {
+	path  string
+	first int // 1-based first line, 0 for the whole file
+	last  int // 1-based last line, 0 for the end of the file
+	shift int // Heading levels to add (negative to remove)
+}
+
+// ExpandIncludes replaces !include(path) lines with the content of the named
+// Markdown file. Paths resolve against the directory of the file holding the
+// directive (sourceDir for the document itself) and must stay under
+// sourceDir. Options follow the path:
+//
+//	!include(parts/glossary.md, lines=5-20, shift=1)
+//
+// lines keeps a 1-based inclusive range ("5-20", "5-" or "5"), and shift
+// moves ATX headings down (or up, when negative) by that many levels,
+// clamped to h1-h6. An included file's frontmatter is dropped unless a line
+// range is given. Included files may include others; cycles are errors.
+// Directives inside fenced code blocks are left as is, and a directive in a
+// document without sourceDir is an error.
+func ExpandIncludes(markdown, sourceDir string) (string, error) {
+	if !strings.Contains(markdown, "!include(") {
+		return markdown, nil
+	}
+	root := ""
+	if sourceDir != "" {
+		var err error
+		if root, err = filepath.Abs(sourceDir); err != nil {
+			return "", fmt.Errorf("resolving source directory: %w", err)
+		}
+	}
+	return expandIncludes(normalizeLineEndings(markdown), root, root, nil)
+}
+
+// expandIncludes expands the directives of one file. dir is that file's
+// directory, and stack holds the files being expanded, outermost first.
+func expandIncludes(markdown, dir, root string, stack []string) (string, error) {
+	lines := strings.Split(markdown, "\n")
+	fence := ""
+	for i, line := range lines {
+		var inCode bool
+		if fence, inCode = trackCodeFence(fence, line); inCode {
+			continue
+		}
+		m := includePattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		content, err := expandInclude(m[1], dir, root, stack)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", includeLocation(stack, root, i+1), err)
+		}
+		lines[i] = content
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// expandInclude reads, trims and expands the file named by one directive.
+func expandInclude(args, dir, root string, stack []string) (string, error) {
+	inc, err := parseInclude(args)
+	if err != nil {
+		return "", err
+	}
+	if root == "" {
+		return "", fmt.Errorf("%w: no source directory to resolve %s against", ErrInvalidInclude, inc.path)
+	}
+
+	path := filepath.Join(dir, inc.path)
+	if filepath.IsAbs(inc.path) || !isPathUnderDir(path, root) {
+		return "", fmt.Errorf("%w: %s", ErrIncludeOutsideRoot, inc.path)
+	}
+	for _, open := range stack {
+		if open == path {
+			return "", fmt.Errorf("%w: %s", ErrIncludeCycle, includeChain(append(stack, path), root))
+		}
+	}
+	if len(stack) >= MaxIncludeDepth {
+		return "", fmt.Errorf("%w: more than %d levels", ErrIncludeDepth, MaxIncludeDepth)
+	}
+
+	data, err := os.ReadFile(path) // #nosec G304 -- path checked against root above
+	if err != nil {
+		return "", fmt.Errorf("reading %s: %w", inc.path, err)
+	}
+	content := normalizeLineEndings(string(data))
+	if inc.first > 0 {
+		content, err = selectLines(strings.TrimSuffix(content, "\n"), inc.first, inc.last)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", inc.path, err)
+		}
+	} else {
+		content = strings.TrimSuffix(stripFrontmatter(content), "\n")
+	}
+
+	content, err = expandIncludes(content, filepath.Dir(path), root, append(stack, path))
+	if err != nil {
+		return "", err
+	}
+	return shiftHeadings(content, inc.shift), nil
+}
+
+// parseInclude parses the arguments of an include directive.
+func parseInclude(args string) (include, error) {
+	parts := strings.Split(args, ",")
+	inc := include{path: strings.Trim(strings.TrimSpace(parts[0]), `"'`)}
+	if inc.path == "" {
+		return include{}, fmt.Errorf("%w: missing path", ErrInvalidInclude)
+	}
+
+	for _, opt := range parts[1:] {
+		key, value, ok := strings.Cut(strings.TrimSpace(opt), "=")
+		if !ok {
+			return include{}, fmt.Errorf("%w: option %q is not key=value", ErrInvalidInclude, strings.TrimSpace(opt))
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		switch key {
+		case "lines":
+			m := lineRangePattern.FindStringSubmatch(value)
+			if m == nil {
+				return include{}, fmt.Errorf("%w: lines=%q (want 5-20, 5- or 5)", ErrInvalidInclude, value)
+			}
+			inc.first, _ = strconv.Atoi(m[1])
+			switch {
+			case m[3] != "":
+				inc.last, _ = strconv.Atoi(m[3])
+			case m[2] == "":
+				inc.last = inc.first
+			}
+			if inc.first < 1 || (inc.last != 0 && inc.last < inc.first) {
+				return include{}, fmt.Errorf("%w: lines=%q", ErrInvalidInclude, value)
+			}
+		case "shift":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < -5 || n > 5 {
+				return include{}, fmt.Errorf("%w: shift=%q (want -5 to 5)", ErrInvalidInclude, value)
+			}
+			inc.shift = n
+		default:
+			return include{}, fmt.Errorf("%w: unknown option %q", ErrInvalidInclude, key)
+		}
+	}
+	return inc, nil
+}
+
+// selectLines returns lines first to last (1-based, inclusive) of content.
+// last 0 means the end of the content.
+func selectLines(content string, first, last int) (string, error) {
+	lines := strings.Split(content, "\n")
+	if first > len(lines) {
+		return "", fmt.Errorf("%w: line %d is past the end (%d lines)", ErrInvalidInclude, first, len(lines))
+	}
+	if last == 0 || last > len(lines) {
+		last = len(lines)
+	}
+	return strings.Join(lines[first-1:last], "\n"), nil
+}
+
+// shiftHeadings moves ATX headings outside fenced code blocks by shift
+// levels, keeping them between h1 and h6.
+func shiftHeadings(content string, shift int) string {
+	if shift == 0 {
+		return content
+	}
+	lines := strings.Split(content, "\n")
+	fence := ""
+	for i, line := range lines {
+		var inCode bool
+		if fence, inCode = trackCodeFence(fence, line); inCode {
+			continue
+		}
+		m := atxHeadingPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		level := min(max(len(m[2])+shift, 1), 6)
+		lines[i] = m[1] + strings.Repeat("#", level) + m[3]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// includeLocation names a line of the file being expanded, relative to root.
+func includeLocation(stack []string, root string, line int) string {
+	if len(stack) == 0 {
+		return fmt.Sprintf("line %d", line)
+	}
+	return fmt.Sprintf("%s line %d", relativeTo(root, stack[len(stack)-1]), line)
+}
+
+// includeChain formats the files of a cycle, relative to root.
+func includeChain(stack []string, root string) string {
+	names := make([]string, len(stack))
+	for i, path := range stack {
+		names[i] = relativeTo(root, path)
+	}
+	return strings.Join(names, " -> ")
+}
+
+// relativeTo returns path relative to root, or path itself if it has none.
+func relativeTo(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,159 @@
+package pipeline
+
+// Notes:
+// - Each test writes its fragments to t.TempDir()
+// - Error messages are checked for the location of the failing directive
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFiles writes name -> content files under dir, creating directories.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("MkdirAll() unexpected error: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("WriteFile() unexpected error: %v", err)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestExpandIncludes - Resolution, ranges and heading shifts
+// ---------------------------------------------------------------------------
+
+func TestExpandIncludes(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"disclaimer.md":        "Provided as is.\n",
+		"fm.md":                "---\ntitle: Fragment\n---\nBody\n",
+		"numbers.md":           "one\ntwo\nthree\nfour\n",
+		"section.md":           "# Glossary\n\n```\n# not a heading\n```\n\n###### Deep\n",
+		"parts/outer.md":       "Outer\n!include(inner/inner.md)\n",
+		"parts/inner/inner.md": "Inner\n",
+		"crlf.md":              "a\r\nb\r\n",
+	})
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"no directive unchanged", "# Title\n\ntext", "# Title\n\ntext"},
+		{"whole file", "Intro\n\n!include(disclaimer.md)\n\nEnd", "Intro\n\nProvided as is.\n\nEnd"},
+		{"quoted path and spacing", `  !include( "disclaimer.md" )  `, "Provided as is."},
+		{"frontmatter dropped", "!include(fm.md)", "Body"},
+		{"line range", "!include(numbers.md, lines=2-3)", "two\nthree"},
+		{"open line range", "!include(numbers.md, lines=3-)", "three\nfour"},
+		{"single line", "!include(numbers.md, lines=4)", "four"},
+		{"range past end is clamped", "!include(numbers.md, lines=3-99)", "three\nfour"},
+		{"frontmatter kept with line range", "!include(fm.md, lines=1-2)", "---\ntitle: Fragment"},
+		{
+			"headings shifted outside code",
+			"!include(section.md, shift=1)",
+			"## Glossary\n\n```\n# not a heading\n```\n\n###### Deep",
+		},
+		{"negative shift stops at h1", "!include(section.md, shift=-2, lines=1)", "# Glossary"},
+		{"nested include resolves from its file", "!include(parts/outer.md)", "Outer\nInner"},
+		{"line endings normalized", "!include(crlf.md)", "a\nb"},
+		{"directive in fenced code unchanged", "```\n!include(disclaimer.md)\n```", "```\n!include(disclaimer.md)\n```"},
+		{"inline mention unchanged", "Use `!include(x.md)` to include.", "Use `!include(x.md)` to include."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ExpandIncludes(tt.input, dir)
+			if err != nil {
+				t.Fatalf("ExpandIncludes(%q) unexpected error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("ExpandIncludes(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestExpandIncludes_Errors - Safety, cycles and bad directives
+// ---------------------------------------------------------------------------
+
+func TestExpandIncludes_Errors(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	dir := filepath.Join(root, "docs")
+	writeFiles(t, root, map[string]string{
+		"secret.md":      "secret\n",
+		"docs/a.md":      "A\n!include(b.md)\n",
+		"docs/b.md":      "B\n\n!include(a.md)\n",
+		"docs/self.md":   "!include(self.md)\n",
+		"docs/short.md":  "only\n",
+		"docs/escape.md": "!include(../secret.md)\n",
+	})
+
+	tests := []struct {
+		name      string
+		input     string
+		sourceDir string
+		wantErr   error
+		wantMsg   string
+	}{
+		{"parent directory", "x\n!include(../secret.md)", dir, ErrIncludeOutsideRoot, "line 2: include outside source directory: ../secret.md"},
+		{"absolute path", "!include(" + filepath.Join(root, "secret.md") + ")", dir, ErrIncludeOutsideRoot, ""},
+		{"nested escape", "!include(escape.md)", dir, ErrIncludeOutsideRoot, "escape.md line 1: "},
+		{"cycle", "!include(a.md)", dir, ErrIncludeCycle, "include cycle: a.md -> b.md -> a.md"},
+		{"self include", "!include(self.md)", dir, ErrIncludeCycle, "self.md -> self.md"},
+		{"missing file", "!include(nope.md)", dir, os.ErrNotExist, "reading nope.md"},
+		{"no source directory", "!include(a.md)", "", ErrInvalidInclude, "no source directory"},
+		{"empty path", "!include()", dir, ErrInvalidInclude, "missing path"},
+		{"unknown option", "!include(a.md, depth=2)", dir, ErrInvalidInclude, `unknown option "depth"`},
+		{"option without value", "!include(a.md, lines)", dir, ErrInvalidInclude, ""},
+		{"bad range", "!include(a.md, lines=3-1)", dir, ErrInvalidInclude, ""},
+		{"zero line", "!include(a.md, lines=0)", dir, ErrInvalidInclude, ""},
+		{"range past end", "!include(short.md, lines=5)", dir, ErrInvalidInclude, "past the end"},
+		{"shift out of range", "!include(a.md, shift=9)", dir, ErrInvalidInclude, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ExpandIncludes(tt.input, tt.sourceDir)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ExpandIncludes(%q) error = %v, want %v", tt.input, err, tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("ExpandIncludes(%q) error = %q, want it to contain %q", tt.input, err, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestExpandIncludes_Depth(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	files := map[string]string{}
+	for i := 0; i <= MaxIncludeDepth; i++ {
+		files[filepath.Join("d", strings.Repeat("n/", i), "f.md")] = "!include(n/f.md)\n"
+	}
+	writeFiles(t, dir, files)
+
+	_, err := ExpandIncludes("!include(d/f.md)", dir)
+	if !errors.Is(err, ErrIncludeDepth) {
+		t.Errorf("ExpandIncludes() error = %v, want %v", err, ErrIncludeDepth)
+	}
+}
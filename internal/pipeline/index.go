@@ -0,0 +1,293 @@
+package pipeline
+
+import (
+	"html"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// Marker and classes for the back-of-book index. InjectIndex builds the
+// index from the terms written by IndexExtension.
+const (
+	IndexMarker        = "[index]"
+	IndexClass         = "index"
+	IndexTermClass     = "index-term"
+	IndexGroupClass    = "index-group"
+	IndexLetterClass   = "index-letter"
+	IndexEntryClass    = "index-entry"
+	IndexSubentryClass = "index-subentry"
+
+	// indexSymbolsLetter heads the group of terms that do not start with a
+	// letter.
+	indexSymbolsLetter = "#"
+)
+
+var (
+	// Index term at the start of the remaining line.
+	// Captures: 1=term, with an optional !subterm
+	indexTermPattern = regexp.MustCompile(`^\[\[idx:([^\[\]\n]+)\]\]`)
+
+	// Index term written by the goldmark extension.
+	// Captures: 1=term, 2=subterm
+	indexSpanPattern = regexp.MustCompile(`<span class="` + IndexTermClass + `" data-term="([^"]*)"(?: data-subterm="([^"]*)")?></span>`)
+
+	// Paragraph holding only the index marker.
+	indexMarkerPattern = regexp.MustCompile(`<p>\s*` + regexp.QuoteMeta(IndexMarker) + `\s*</p>\n?`)
+
+	// Top-level heading, to pick the level of the index heading.
+	h1Pattern = regexp.MustCompile(`(?i)<h1[\s>]`)
+)
+
+// IndexData holds the settings for InjectIndex.
+type IndexData struct {
+	Title string // Index heading; empty for none
+
+	// Pages maps term IDs to the pages found by a previous render.
+	Pages map[string]int
+}
+
+// IndexExtension parses index terms. [[idx:failover]] marks a term and
+// [[idx:replication!failover]] a sub-entry under replication. Terms are
+// written as empty spans that InjectIndex numbers and collects.
+var IndexExtension goldmark.Extender = &indexExtension{}
+
+type indexExtension struct{}
+
+func (e *indexExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithInlineParsers(util.Prioritized(&indexTermParser{}, 150)))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(util.Prioritized(&indexTermRenderer{}, 150)))
+}
+
+// KindIndexTerm is the NodeKind of index terms.
+var KindIndexTerm = ast.NewNodeKind("IndexTerm")
+
+// IndexTerm marks the place a term is indexed.
+type IndexTerm struct {
+	ast.BaseInline
+	Term    string
+	Subterm string // Empty for a main entry
+}
+
+// Kind implements ast.Node.
+func (n *IndexTerm) Kind() ast.NodeKind { return KindIndexTerm }
+
+// Dump implements ast.Node.
+func (n *IndexTerm) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Term": n.Term, "Subterm": n.Subterm}, nil)
+}
+
+// indexTermParser parses [[idx:term]] and [[idx:term!subterm]]. It runs
+// before the link parser, which would otherwise take the brackets.
+type indexTermParser struct{}
+
+func (p *indexTermParser) Trigger() []byte {
+	return []byte{'['}
+}
+
+func (p *indexTermParser) Parse(_ ast.Node, block text.Reader, _ parser.Context) ast.Node {
+	line, _ := block.PeekLine()
+	m := indexTermPattern.FindSubmatch(line)
+	if m == nil {
+		return nil
+	}
+	term, subterm, _ := strings.Cut(string(m[1]), "!")
+	term, subterm = strings.TrimSpace(term), strings.TrimSpace(subterm)
+	if term == "" {
+		return nil
+	}
+	block.Advance(len(m[0]))
+	return &IndexTerm{Term: term, Subterm: subterm}
+}
+
+// indexTermRenderer writes index terms as empty spans.
+type indexTermRenderer struct{}
+
+func (r *indexTermRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindIndexTerm, r.renderIndexTerm)
+}
+
+func (r *indexTermRenderer) renderIndexTerm(w util.BufWriter, _ []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*IndexTerm)
+	_, _ = w.WriteString(`<span class="` + IndexTermClass + `" data-term="`)
+	_, _ = w.Write(util.EscapeHTML([]byte(n.Term)))
+	_, _ = w.WriteString(`"`)
+	if n.Subterm != "" {
+		_, _ = w.WriteString(` data-subterm="`)
+		_, _ = w.Write(util.EscapeHTML([]byte(n.Subterm)))
+		_, _ = w.WriteString(`"`)
+	}
+	_, _ = w.WriteString("></span>")
+	return ast.WalkContinue, nil
+}
+
+// HasIndexTerms reports whether htmlContent holds terms for InjectIndex.
+func HasIndexTerms(htmlContent string) bool {
+	return indexSpanPattern.MatchString(htmlContent)
+}
+
+// indexEntry is a term with the IDs of the places it is indexed.
+type indexEntry struct {
+	term string
+	refs []string
+	subs map[string]*indexEntry
+}
+
+func (e *indexEntry) sub(term string) *indexEntry {
+	if e.subs == nil {
+		e.subs = make(map[string]*indexEntry)
+	}
+	s, ok := e.subs[term]
+	if !ok {
+		s = &indexEntry{term: term}
+		e.subs[term] = s
+	}
+	return s
+}
+
+// InjectIndex gives each index term an ID ("idx-1", "idx-2", ...) in
+// document order and writes the index at the first [index] paragraph, or
+// at the end of the body. Entries are sorted and grouped by first letter;
+// each reference links to its term and shows its page from data.Pages.
+// Without a page, as in HTML output, references show their position
+// ("[1]", "[2]", ...). Terms are numbered the same way on every call, so
+// the pages of a previous render apply. If data is nil, returns htmlContent
+// unchanged.
+func InjectIndex(htmlContent string, data *IndexData) string {
+	if data == nil {
+		return htmlContent
+	}
+
+	root := &indexEntry{}
+	count := 0
+	htmlContent = indexSpanPattern.ReplaceAllStringFunc(htmlContent, func(span string) string {
+		m := indexSpanPattern.FindStringSubmatch(span)
+		count++
+		id := "idx-" + strconv.Itoa(count)
+		entry := root.sub(html.UnescapeString(m[1]))
+		if m[2] != "" {
+			entry = entry.sub(html.UnescapeString(m[2]))
+		}
+		entry.refs = append(entry.refs, id)
+		return `<span class="` + IndexTermClass + `" id="` + id + `"></span>`
+	})
+
+	var section string
+	if count > 0 {
+		level := "2"
+		if len(h1Pattern.FindAllStringIndex(htmlContent, 2)) > 1 {
+			level = "1"
+		}
+		section = generateIndex(root, data, level, uniqueID(htmlContent, "index"))
+	}
+
+	if loc := indexMarkerPattern.FindStringIndex(htmlContent); loc != nil {
+		return htmlContent[:loc[0]] + section + htmlContent[loc[1]:]
+	}
+	if section == "" {
+		return htmlContent
+	}
+	if idx := strings.LastIndex(strings.ToLower(htmlContent), "</body>"); idx != -1 {
+		return htmlContent[:idx] + section + htmlContent[idx:]
+	}
+	return htmlContent + section
+}
+
+// generateIndex writes the index section.
+func generateIndex(root *indexEntry, data *IndexData, level, id string) string {
+	var buf strings.Builder
+	buf.WriteString(`<section class="` + IndexClass + `">` + "\n")
+	if data.Title != "" {
+		buf.WriteString(`<h` + level + ` id="` + id + `">` + html.EscapeString(data.Title) + `</h` + level + ">\n")
+	}
+
+	letter := ""
+	for _, entry := range sortedEntries(root) {
+		if l := indexLetter(entry.term); l != letter {
+			if letter != "" {
+				buf.WriteString("</div>\n")
+			}
+			letter = l
+			buf.WriteString(`<div class="` + IndexGroupClass + `"><div class="` + IndexLetterClass + `">` + html.EscapeString(letter) + "</div>\n")
+		}
+		writeIndexEntry(&buf, entry, IndexEntryClass, data.Pages)
+		for _, sub := range sortedEntries(entry) {
+			writeIndexEntry(&buf, sub, IndexEntryClass+" "+IndexSubentryClass, data.Pages)
+		}
+	}
+	buf.WriteString("</div>\n</section>\n")
+	return buf.String()
+}
+
+// writeIndexEntry writes a term and links to its references, one per page.
+func writeIndexEntry(buf *strings.Builder, entry *indexEntry, class string, pages map[string]int) {
+	buf.WriteString(`<div class="` + class + `">` + html.EscapeString(entry.term))
+	seen := make(map[int]bool)
+	for i, id := range entry.refs {
+		label := "[" + strconv.Itoa(i+1) + "]"
+		if page, ok := pages[id]; ok {
+			if seen[page] {
+				continue
+			}
+			seen[page] = true
+			label = strconv.Itoa(page)
+		}
+		buf.WriteString(`, <a href="#` + id + `">` + label + "</a>")
+	}
+	buf.WriteString("</div>\n")
+}
+
+// sortedEntries returns the sub-entries of e sorted without regard to case,
+// terms that do not start with a letter first.
+func sortedEntries(e *indexEntry) []*indexEntry {
+	entries := make([]*indexEntry, 0, len(e.subs))
+	for _, s := range e.subs {
+		entries = append(entries, s)
+	}
+	slices.SortFunc(entries, func(a, b *indexEntry) int {
+		aSym, bSym := indexLetter(a.term) == indexSymbolsLetter, indexLetter(b.term) == indexSymbolsLetter
+		if aSym != bSym {
+			if aSym {
+				return -1
+			}
+			return 1
+		}
+		if c := strings.Compare(strings.ToLower(a.term), strings.ToLower(b.term)); c != 0 {
+			return c
+		}
+		return strings.Compare(a.term, b.term)
+	})
+	return entries
+}
+
+// indexLetter returns the group heading for term.
+func indexLetter(term string) string {
+	r, _ := utf8.DecodeRuneInString(term)
+	if !unicode.IsLetter(r) {
+		return indexSymbolsLetter
+	}
+	return string(unicode.ToUpper(r))
+}
+
+// uniqueID returns id, or id with a numeric suffix if htmlContent already
+// uses it.
+func uniqueID(htmlContent, id string) string {
+	candidate := id
+	for n := 1; strings.Contains(htmlContent, `id="`+candidate+`"`); n++ {
+		candidate = id + "-" + strconv.Itoa(n)
+	}
+	return candidate
+}
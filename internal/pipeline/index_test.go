@@ -0,0 +1,169 @@
+package pipeline
+
+// Notes:
+// - IndexExtension is tested through NewGoldmarkConverter output, with
+//   mathBody (math_test.go) extracting the HTML body
+// - InjectIndex is tested on converter output, with and without pages
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// TestIndexExtension - [[idx:term]] syntax
+// ---------------------------------------------------------------------------
+
+func TestIndexExtension(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			"term",
+			"Use failover[[idx:failover]] here.",
+			`<p>Use failover<span class="index-term" data-term="failover"></span> here.</p>`,
+		},
+		{
+			"sub-entry is trimmed and escaped",
+			"[[idx: replication ! R&D <1> ]]",
+			`<p><span class="index-term" data-term="replication" data-subterm="R&amp;D &lt;1&gt;"></span></p>`,
+		},
+		{
+			"empty term stays text",
+			"[[idx: ]]",
+			"<p>[[idx: ]]</p>",
+		},
+		{
+			"links stay links",
+			"[[idx]](x.md) and [idx:a](y.md)",
+			`<p><a href="x.md">[idx]</a> and <a href="y.md">idx:a</a></p>`,
+		},
+		{
+			"code stays text",
+			"`[[idx:a]]`",
+			"<p><code>[[idx:a]]</code></p>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := mathBody(t, tt.input); got != tt.want {
+				t.Errorf("ToHTML() body = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestInjectIndex - Index generation
+// ---------------------------------------------------------------------------
+
+func indexHTML(t *testing.T, markdown string) string {
+	t.Helper()
+
+	got, err := NewGoldmarkConverter().ToHTML(context.Background(), markdown)
+	if err != nil {
+		t.Fatalf("ToHTML() unexpected error: %v", err)
+	}
+	return got
+}
+
+func TestInjectIndex(t *testing.T) {
+	t.Parallel()
+
+	const doc = "# Guide\n\n" +
+		"Failover[[idx:failover]] and [[idx:Backups]].\n\n" +
+		"## Later\n\n" +
+		"Again[[idx:failover]], [[idx:replication!lag]], [[idx:replication!async]] and [[idx:2PC]]."
+
+	t.Run("nil data leaves content unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		content := indexHTML(t, doc)
+		if got := InjectIndex(content, nil); got != content {
+			t.Errorf("InjectIndex() changed content:\n%s", got)
+		}
+	})
+
+	t.Run("numbers terms and appends sorted groups", func(t *testing.T) {
+		t.Parallel()
+
+		got := InjectIndex(indexHTML(t, doc), &IndexData{Title: "Index"})
+		for _, want := range []string{
+			`Failover<span class="index-term" id="idx-1"></span>`,
+			`Again<span class="index-term" id="idx-3"></span>`,
+			`<section class="index">` + "\n" + `<h2 id="index">Index</h2>`,
+			`<div class="index-group"><div class="index-letter">#</div>` + "\n" +
+				`<div class="index-entry">2PC, <a href="#idx-6">[1]</a></div>` + "\n</div>\n" +
+				`<div class="index-group"><div class="index-letter">B</div>` + "\n" +
+				`<div class="index-entry">Backups, <a href="#idx-2">[1]</a></div>` + "\n</div>\n" +
+				`<div class="index-group"><div class="index-letter">F</div>` + "\n" +
+				`<div class="index-entry">failover, <a href="#idx-1">[1]</a>, <a href="#idx-3">[2]</a></div>` + "\n</div>\n" +
+				`<div class="index-group"><div class="index-letter">R</div>` + "\n" +
+				`<div class="index-entry">replication</div>` + "\n" +
+				`<div class="index-entry index-subentry">async, <a href="#idx-5">[1]</a></div>` + "\n" +
+				`<div class="index-entry index-subentry">lag, <a href="#idx-4">[1]</a></div>` + "\n" +
+				"</div>\n</section>\n</body>",
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("InjectIndex() missing %q in:\n%s", want, got)
+			}
+		}
+	})
+
+	t.Run("pages replace positions once per page", func(t *testing.T) {
+		t.Parallel()
+
+		pages := map[string]int{"idx-1": 4, "idx-3": 4, "idx-2": 9}
+		got := InjectIndex(indexHTML(t, doc), &IndexData{Title: "Index", Pages: pages})
+		for _, want := range []string{
+			`<div class="index-entry">failover, <a href="#idx-1">4</a></div>`,
+			`<div class="index-entry">Backups, <a href="#idx-2">9</a></div>`,
+			`<div class="index-entry">2PC, <a href="#idx-6">[1]</a></div>`,
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("InjectIndex() missing %q", want)
+			}
+		}
+	})
+
+	t.Run("marker places index and chapters get a top-level heading", func(t *testing.T) {
+		t.Parallel()
+
+		content := indexHTML(t, "# One\n\n[[idx:a]]\n\n[index]\n\n# Index")
+		got := InjectIndex(content, &IndexData{Title: "Terms"})
+		if !strings.Contains(got, `<h1 id="index-1">Terms</h1>`) {
+			t.Errorf("InjectIndex() missing top-level heading with a free ID:\n%s", got)
+		}
+		if strings.Contains(got, IndexMarker) || strings.Index(got, "Terms") > strings.Index(got, `id="index">`) {
+			t.Errorf("InjectIndex() did not replace the marker:\n%s", got)
+		}
+	})
+
+	t.Run("marker without terms is removed", func(t *testing.T) {
+		t.Parallel()
+
+		got := InjectIndex(indexHTML(t, "Text\n\n[index]"), &IndexData{Title: "Index"})
+		if strings.Contains(got, IndexMarker) || strings.Contains(got, `class="index"`) {
+			t.Errorf("InjectIndex() = %s, want marker removed and no index", got)
+		}
+	})
+}
+
+func TestHasIndexTerms(t *testing.T) {
+	t.Parallel()
+
+	if !HasIndexTerms(indexHTML(t, "[[idx:a]]")) {
+		t.Error("HasIndexTerms() = false, want true")
+	}
+	if HasIndexTerms(indexHTML(t, "[index]")) {
+		t.Error("HasIndexTerms() = true, want false")
+	}
+}
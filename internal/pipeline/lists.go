@@ -0,0 +1,135 @@
+package pipeline
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Classes of the lists of figures and tables. Both are TOC navs, so they
+// share the TOC's styles.
+const (
+	FiguresListClass = "toc-figures"
+	TablesListClass  = "toc-tables"
+)
+
+var (
+	// Figure or captioned table opening tag, to give it an ID.
+	// Captures: 1=figure ID, 2=table ID
+	captionedOpenPattern = regexp.MustCompile(`<figure class="` + FigureClass + `"(?: id="([^"]*)")?>|<table(?: id="([^"]*)")?>(?:\s*<caption>)`)
+
+	// Numbered figure with its caption.
+	// Captures: 1=id, 2=caption HTML
+	figureCaptionPattern = regexp.MustCompile(`(?s)<figure class="` + FigureClass + `" id="([^"]*)">.*?<figcaption>(.*?)</figcaption>`)
+
+	// Captioned table.
+	// Captures: 1=id, 2=caption HTML
+	tableCaptionHTMLPattern = regexp.MustCompile(`(?s)<table id="([^"]*)">\s*<caption>(.*?)</caption>`)
+)
+
+// ListData holds the settings for a list of figures or tables.
+type ListData struct {
+	Title string // Heading; empty for none
+
+	// PageNumbers maps element IDs to page numbers, as TOCData.PageNumbers.
+	PageNumbers map[string]int
+}
+
+// listEntry is one line of a list of figures or tables.
+type listEntry struct {
+	ID   string
+	Text string // "Figure 1: Caption"
+}
+
+// InjectLists writes a list of figures and a list of tables after the TOC,
+// or after the cover when there is no TOC, from the numbered figures and
+// captioned tables of NumberCrossRefs. Elements without an ID get one
+// ("figure-1", "table-1") so the entries can link to them. A nil data, or a
+// document without elements of that kind, adds no list.
+func InjectLists(htmlContent string, figures, tables *ListData) string {
+	if figures == nil && tables == nil {
+		return htmlContent
+	}
+
+	figureCount, tableCount := 0, 0
+	htmlContent = captionedOpenPattern.ReplaceAllStringFunc(htmlContent, func(tag string) string {
+		m := captionedOpenPattern.FindStringSubmatch(tag)
+		if strings.HasPrefix(tag, "<figure") {
+			figureCount++
+			if m[1] != "" {
+				return tag
+			}
+			id := uniqueID(htmlContent, "figure-"+strconv.Itoa(figureCount))
+			return `<figure class="` + FigureClass + `" id="` + id + `">`
+		}
+		tableCount++
+		if m[2] != "" {
+			return tag
+		}
+		id := uniqueID(htmlContent, "table-"+strconv.Itoa(tableCount))
+		return `<table id="` + id + `">` + strings.TrimPrefix(tag, "<table>")
+	})
+
+	var lists strings.Builder
+	if figures != nil {
+		lists.WriteString(generateList(FiguresListClass, figures, listEntries(figureCaptionPattern, htmlContent)))
+	}
+	if tables != nil {
+		lists.WriteString(generateList(TablesListClass, tables, listEntries(tableCaptionHTMLPattern, htmlContent)))
+	}
+	if lists.Len() == 0 {
+		return htmlContent
+	}
+	return insertFrontMatter(htmlContent, lists.String())
+}
+
+// listEntries returns the ID and caption text of each match of pattern.
+func listEntries(pattern *regexp.Regexp, htmlContent string) []listEntry {
+	var entries []listEntry
+	for _, m := range pattern.FindAllStringSubmatch(htmlContent, -1) {
+		entries = append(entries, listEntry{ID: m[1], Text: stripHTMLTags(m[2])})
+	}
+	return entries
+}
+
+// generateList writes a list as a TOC nav with one item per entry.
+func generateList(class string, data *ListData, entries []listEntry) string {
+	if len(entries) == 0 {
+		return ""
+	}
+
+	var buf strings.Builder
+	buf.WriteString(`<nav class="toc ` + class)
+	if data.PageNumbers != nil {
+		buf.WriteString(` toc-paged`)
+	}
+	buf.WriteString(`">`)
+	writeTOCTitle(&buf, data.Title)
+	buf.WriteString(`<div class="toc-list">`)
+	for _, e := range entries {
+		buf.WriteString(`<div class="toc-item">`)
+		writeTOCLink(&buf, e.ID, e.Text, data.PageNumbers)
+		buf.WriteString(`</div>`)
+	}
+	buf.WriteString(`</div></nav>`)
+	return buf.String()
+}
+
+// insertFrontMatter inserts block after the last front matter nav (the TOC
+// or a list), or after the cover, or at the start of the body.
+func insertFrontMatter(htmlContent, block string) string {
+	if locs := frontMatterNavPattern.FindAllStringIndex(htmlContent, -1); len(locs) > 0 {
+		pos := locs[len(locs)-1][1]
+		return htmlContent[:pos] + block + htmlContent[pos:]
+	}
+	if loc := coverEndPattern.FindStringIndex(htmlContent); loc != nil {
+		return htmlContent[:loc[1]] + block + htmlContent[loc[1]:]
+	}
+	if idx := strings.Index(strings.ToLower(htmlContent), "<body"); idx != -1 {
+		if closeIdx := strings.Index(htmlContent[idx:], ">"); closeIdx != -1 {
+			pos := idx + closeIdx + 1
+			return htmlContent[:pos] + block + htmlContent[pos:]
+		}
+	}
+	return block + htmlContent
+}
@@ -0,0 +1,132 @@
+package pipeline
+
+// Notes:
+// - InjectLists is tested on converter output numbered by NumberCrossRefs,
+//   through listsHTML
+// - Placement after the TOC and cover reuses the fixtures of
+//   contentstart_test.go in spirit: plain strings stand in for injections
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// listsHTML converts markdown and numbers its figures and tables.
+func listsHTML(t *testing.T, markdown string) string {
+	t.Helper()
+
+	got, err := NewGoldmarkConverter().ToHTML(context.Background(), markdown)
+	if err != nil {
+		t.Fatalf("ToHTML() unexpected error: %v", err)
+	}
+	if got, err = NumberCrossRefs(got); err != nil {
+		t.Fatalf("NumberCrossRefs() unexpected error: %v", err)
+	}
+	return got
+}
+
+// ---------------------------------------------------------------------------
+// TestInjectLists - Lists of figures and tables
+// ---------------------------------------------------------------------------
+
+func TestInjectLists(t *testing.T) {
+	t.Parallel()
+
+	const doc = "# Report\n\n" +
+		"![Arch](arch.png \"System architecture\"){#fig:arch}\n\n" +
+		"![Flow](flow.png \"Data flow\")\n\n" +
+		"| a |\n|---|\n| 1 |\n\nTable: Capacity\n\n" +
+		"| b |\n|---|\n| 2 |\n"
+
+	t.Run("nil data leaves content unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		content := listsHTML(t, doc)
+		if got := InjectLists(content, nil, nil); got != content {
+			t.Errorf("InjectLists() changed content:\n%s", got)
+		}
+	})
+
+	t.Run("lists figures and captioned tables with links", func(t *testing.T) {
+		t.Parallel()
+
+		got := InjectLists(listsHTML(t, doc), &ListData{Title: "List of Figures"}, &ListData{Title: "List of Tables"})
+		want := `<body>` +
+			`<nav class="toc toc-figures"><h2 class="toc-title">List of Figures</h2><div class="toc-list">` +
+			`<div class="toc-item"><a href="#fig:arch">Figure 1: System architecture</a></div>` +
+			`<div class="toc-item"><a href="#figure-2">Figure 2: Data flow</a></div></div></nav>` +
+			`<nav class="toc toc-tables"><h2 class="toc-title">List of Tables</h2><div class="toc-list">` +
+			`<div class="toc-item"><a href="#table-1">Table 1: Capacity</a></div></div></nav>`
+		if !strings.Contains(got, want) {
+			t.Errorf("InjectLists() missing lists after <body>:\n%s", got)
+		}
+		for _, target := range []string{`<figure class="figure" id="figure-2">`, `<table id="table-1">`} {
+			if !strings.Contains(got, target) {
+				t.Errorf("InjectLists() missing link target %q", target)
+			}
+		}
+		if strings.Count(got, "<table>") != 1 {
+			t.Errorf("InjectLists() gave an uncaptioned table an ID:\n%s", got)
+		}
+	})
+
+	t.Run("page numbers add page columns", func(t *testing.T) {
+		t.Parallel()
+
+		got := InjectLists(listsHTML(t, doc), &ListData{PageNumbers: map[string]int{"fig:arch": 3}}, nil)
+		for _, want := range []string{
+			`<nav class="toc toc-figures toc-paged"><div class="toc-list">`,
+			`<span class="toc-text">Figure 1: System architecture</span><span class="toc-leader"></span><span class="toc-page">3</span>`,
+			`<span class="toc-text">Figure 2: Data flow</span><span class="toc-leader"></span><span class="toc-page"></span>`,
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("InjectLists() missing %q", want)
+			}
+		}
+		if strings.Contains(got, TablesListClass) {
+			t.Error("InjectLists() added a list of tables without data")
+		}
+	})
+
+	t.Run("document without figures gets no list", func(t *testing.T) {
+		t.Parallel()
+
+		content := listsHTML(t, "# Plain\n\n| a |\n|---|\n| 1 |\n")
+		if got := InjectLists(content, &ListData{}, &ListData{}); strings.Contains(got, "<nav") {
+			t.Errorf("InjectLists() = %s, want no list", got)
+		}
+	})
+}
+
+func TestInsertFrontMatter(t *testing.T) {
+	t.Parallel()
+
+	const (
+		cover = `<section class="cover"><div>Title</div></section><span data-cover-end></span>`
+		toc   = `<nav class="toc toc-paged"><div><a href="#a">A</a></div></nav>`
+		list  = `<nav class="toc toc-figures"></nav>`
+	)
+
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"after TOC", "<body>" + cover + toc + "<p>x</p></body>", "<body>" + cover + toc + "[L]<p>x</p></body>"},
+		{"after previous list", "<body>" + toc + list + "<p>x</p></body>", "<body>" + toc + list + "[L]<p>x</p></body>"},
+		{"after cover", "<body>" + cover + "<p>x</p></body>", "<body>" + cover + "[L]<p>x</p></body>"},
+		{"after body", `<body class="x"><p>x</p></body>`, `<body class="x">[L]<p>x</p></body>`},
+		{"prepended", "<p>x</p>", "[L]<p>x</p>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := insertFrontMatter(tt.html, "[L]"); got != tt.want {
+				t.Errorf("insertFrontMatter() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
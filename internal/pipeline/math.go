@@ -0,0 +1,365 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// Math classes written by the goldmark extension. The TeX is kept with its
+// delimiters, so math reads as written until RenderMath replaces it.
+const (
+	MathInlineClass  = "math math-inline"
+	MathDisplayClass = "math math-display"
+)
+
+var (
+	// Math element written by the goldmark extension.
+	// Captures: 1=tag, 2=kind (inline, display), 3=escaped TeX with delimiters
+	mathElementPattern = regexp.MustCompile(`(?s)<(span|div) class="math math-(inline|display)">(.*?)</(?:span|div)>`)
+
+	// Explicit equation tag, \tag{...} or \tag*{...}.
+	mathTagPattern = regexp.MustCompile(`\\tag\*?\s*\{`)
+
+	// Commands that opt a display equation out of numbering.
+	mathNoNumberPattern = regexp.MustCompile(`\\(?:notag|nonumber)\b`)
+)
+
+// MathExtension parses $...$ inline math and $$...$$ display math, in
+// paragraphs or as a block of their own. Dollars follow Pandoc's rules: the
+// opening $ is not followed by a space, the closing $ is not preceded by a
+// space nor followed by a digit, so "$5 and $10" stays text. Inline math
+// does not span lines or backticks.
+var MathExtension goldmark.Extender = &mathExtension{}
+
+type mathExtension struct{}
+
+func (e *mathExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithBlockParsers(util.Prioritized(&mathBlockParser{}, 150)),
+		parser.WithInlineParsers(util.Prioritized(&mathInlineParser{}, 150)),
+	)
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(util.Prioritized(&mathRenderer{}, 150)))
+}
+
+// KindMath is the NodeKind of inline math.
+var KindMath = ast.NewNodeKind("Math")
+
+// Math is inline math. Display is true for $$...$$ inside a paragraph.
+type Math struct {
+	ast.BaseInline
+	Display bool
+	TeX     []byte
+}
+
+// Kind implements ast.Node.
+func (n *Math) Kind() ast.NodeKind { return KindMath }
+
+// Dump implements ast.Node.
+func (n *Math) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{
+		"Display": strconv.FormatBool(n.Display),
+		"TeX":     string(n.TeX),
+	}, nil)
+}
+
+// KindMathBlock is the NodeKind of display math blocks.
+var KindMathBlock = ast.NewNodeKind("MathBlock")
+
+// MathBlock is display math opened by $$ at the start of a line.
+type MathBlock struct {
+	ast.BaseBlock
+	TeX    []byte
+	closed bool
+}
+
+// Kind implements ast.Node.
+func (n *MathBlock) Kind() ast.NodeKind { return KindMathBlock }
+
+// IsRaw implements ast.Node.
+func (n *MathBlock) IsRaw() bool { return true }
+
+// Dump implements ast.Node.
+func (n *MathBlock) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"TeX": string(n.TeX)}, nil)
+}
+
+// mathInlineParser parses $...$ and $$...$$ within a line.
+type mathInlineParser struct{}
+
+func (p *mathInlineParser) Trigger() []byte {
+	return []byte{'$'}
+}
+
+func (p *mathInlineParser) Parse(_ ast.Node, block text.Reader, _ parser.Context) ast.Node {
+	line, seg := block.PeekLine()
+	line = trimLineEnding(line)
+
+	opener := 0
+	for opener < len(line) && line[opener] == '$' {
+		opener++
+	}
+	if opener > 2 {
+		// A longer run is text, consumed whole so it is not reparsed as math.
+		block.Advance(opener)
+		return ast.NewTextSegment(seg.WithStop(seg.Start + opener))
+	}
+
+	end := closingDollars(line, opener)
+	if end < 0 {
+		return nil
+	}
+	block.Advance(end + opener)
+	return &Math{Display: opener == 2, TeX: append([]byte(nil), line[opener:end]...)}
+}
+
+// closingDollars returns the index of the delimiter closing math opened by
+// n dollars at the start of line, or -1 if there is none.
+func closingDollars(line []byte, n int) int {
+	if n == 1 && (len(line) < 2 || isSpace(line[1])) {
+		return -1
+	}
+	for i := n; i < len(line); i++ {
+		switch {
+		case line[i] == '\\':
+			i++ // \$ is a literal dollar
+		case line[i] == '`':
+			return -1 // code spans bind tighter than math
+		case line[i] != '$':
+		case n == 2:
+			if i+1 < len(line) && line[i+1] == '$' && i > n {
+				return i
+			}
+		case !isSpace(line[i-1]) && (i+1 == len(line) || !isDigit(line[i+1])):
+			return i
+		}
+	}
+	return -1
+}
+
+// mathBlockParser parses $$ display math starting a line, up to the line
+// ending with $$. Like a code fence, an unclosed block runs to the end of
+// its container.
+type mathBlockParser struct{}
+
+func (p *mathBlockParser) Trigger() []byte {
+	return []byte{'$'}
+}
+
+func (p *mathBlockParser) Open(_ ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, _ := reader.PeekLine()
+	pos := pc.BlockOffset()
+	if pos < 0 || !strings.HasPrefix(string(line[pos:]), "$$") || strings.HasPrefix(string(line[pos:]), "$$$") {
+		return nil, parser.NoChildren
+	}
+	rest := strings.TrimSpace(string(line[pos+2:]))
+	node := &MathBlock{}
+	if strings.Contains(rest, "$$") {
+		tex, ok := strings.CutSuffix(rest, "$$")
+		if !ok || strings.Contains(tex, "$$") {
+			return nil, parser.NoChildren // $$a$$ and text is a paragraph
+		}
+		node.TeX = []byte(strings.TrimSpace(tex))
+		node.closed = true
+	} else if rest != "" {
+		node.TeX = []byte(rest + "\n")
+	}
+	reader.AdvanceToEOL()
+	return node, parser.NoChildren
+}
+
+func (p *mathBlockParser) Continue(node ast.Node, reader text.Reader, _ parser.Context) parser.State {
+	n := node.(*MathBlock)
+	if n.closed {
+		return parser.Close
+	}
+	line, _ := reader.PeekLine()
+	if line == nil {
+		return parser.Close
+	}
+	trimmed := strings.TrimSpace(string(line))
+	reader.AdvanceToEOL()
+	if tex, ok := strings.CutSuffix(trimmed, "$$"); ok {
+		n.TeX = append(n.TeX, tex...)
+		n.TeX = []byte(strings.TrimSpace(string(n.TeX)))
+		n.closed = true
+		return parser.Close
+	}
+	n.TeX = append(n.TeX, trimLineEnding(line)...)
+	n.TeX = append(n.TeX, '\n')
+	return parser.Continue | parser.NoChildren
+}
+
+func (p *mathBlockParser) Close(node ast.Node, _ text.Reader, _ parser.Context) {
+	n := node.(*MathBlock)
+	n.TeX = []byte(strings.TrimSpace(string(n.TeX)))
+}
+
+func (p *mathBlockParser) CanInterruptParagraph() bool {
+	return true
+}
+
+func (p *mathBlockParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+// mathRenderer writes math nodes as elements holding the escaped TeX.
+type mathRenderer struct{}
+
+func (r *mathRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindMath, r.renderMath)
+	reg.Register(KindMathBlock, r.renderMathBlock)
+}
+
+func (r *mathRenderer) renderMath(w util.BufWriter, _ []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkSkipChildren, nil
+	}
+	n := node.(*Math)
+	class, delim := MathInlineClass, "$"
+	if n.Display {
+		class, delim = MathDisplayClass, "$$"
+	}
+	_, _ = w.WriteString(`<span class="` + class + `">` + delim)
+	_, _ = w.Write(util.EscapeHTML(n.TeX))
+	_, _ = w.WriteString(delim + "</span>")
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *mathRenderer) renderMathBlock(w util.BufWriter, _ []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkSkipChildren, nil
+	}
+	n := node.(*MathBlock)
+	_, _ = w.WriteString(`<div class="` + MathDisplayClass + `">$$`)
+	_, _ = w.Write(util.EscapeHTML(n.TeX))
+	_, _ = w.WriteString("$$</div>\n")
+	return ast.WalkSkipChildren, nil
+}
+
+func trimLineEnding(line []byte) []byte {
+	for len(line) > 0 && (line[len(line)-1] == '\n' || line[len(line)-1] == '\r') {
+		line = line[:len(line)-1]
+	}
+	return line
+}
+
+func isSpace(c byte) bool { return c == ' ' || c == '\t' }
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+// MathRenderer renders TeX to HTML markup, one per source. display[i] is
+// true for display math. A source the renderer rejects is reported as a
+// *MathError.
+type MathRenderer interface {
+	RenderMath(ctx context.Context, sources []string, display []bool) ([]string, error)
+}
+
+// MathError reports the expression a MathRenderer could not render.
+type MathError struct {
+	Index   int    // Index of the source passed to RenderMath
+	Message string // Renderer's error message
+}
+
+func (e *MathError) Error() string {
+	return fmt.Sprintf("math %d: %s", e.Index+1, e.Message)
+}
+
+// mathElement is a math element found in the HTML.
+type mathElement struct {
+	tag     string
+	display bool
+	tex     string // Without delimiters
+	source  string // As written, for error messages
+}
+
+// HasMath reports whether html holds math written by MathExtension.
+func HasMath(html string) bool {
+	return strings.Contains(html, `class="math math-`)
+}
+
+// HasRenderedMath reports whether html holds math rendered by RenderMath.
+func HasRenderedMath(html string) bool {
+	return strings.Contains(html, `class="katex`)
+}
+
+// RenderMath renders the math written by MathExtension with r, in document
+// order. With numbering, display equations are numbered (1), (2), ...
+// unless they set their own \tag or opt out with \notag or \nonumber.
+func RenderMath(ctx context.Context, r MathRenderer, htmlContent string, numbering bool) (string, error) {
+	matches := mathElementPattern.FindAllStringSubmatchIndex(htmlContent, -1)
+	if len(matches) == 0 {
+		return htmlContent, nil
+	}
+
+	elements := make([]mathElement, len(matches))
+	sources := make([]string, len(matches))
+	display := make([]bool, len(matches))
+	equation := 0
+	for i, m := range matches {
+		source := html.UnescapeString(htmlContent[m[6]:m[7]])
+		el := mathElement{tag: htmlContent[m[2]:m[3]], display: htmlContent[m[4]:m[5]] == "display", source: source}
+		delim := "$"
+		if el.display {
+			delim = "$$"
+		}
+		el.tex = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(source, delim), delim))
+		if el.display && numbering {
+			switch {
+			case mathNoNumberPattern.MatchString(el.tex):
+				el.tex = mathNoNumberPattern.ReplaceAllString(el.tex, "")
+			case !mathTagPattern.MatchString(el.tex):
+				equation++
+				el.tex += `\tag{` + strconv.Itoa(equation) + `}`
+			}
+		}
+		elements[i], sources[i], display[i] = el, el.tex, el.display
+	}
+
+	rendered, err := r.RenderMath(ctx, sources, display)
+	var mathErr *MathError
+	if errors.As(err, &mathErr) && mathErr.Index >= 0 && mathErr.Index < len(elements) {
+		return "", fmt.Errorf("math %q: %s", truncate(elements[mathErr.Index].source, 60), mathErr.Message)
+	}
+	if err != nil {
+		return "", err
+	}
+	if len(rendered) != len(elements) {
+		return "", fmt.Errorf("renderer returned %d expressions, want %d", len(rendered), len(elements))
+	}
+
+	var b strings.Builder
+	last := 0
+	for i, m := range matches {
+		el := elements[i]
+		class := MathInlineClass
+		if el.display {
+			class = MathDisplayClass
+		}
+		b.WriteString(htmlContent[last:m[0]])
+		b.WriteString(`<` + el.tag + ` class="` + class + `">` + rendered[i] + `</` + el.tag + `>`)
+		last = m[1]
+	}
+	b.WriteString(htmlContent[last:])
+	return b.String(), nil
+}
+
+// truncate shortens s to at most n runes, marking the cut with an ellipsis.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
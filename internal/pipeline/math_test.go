@@ -0,0 +1,185 @@
+package pipeline
+
+// Notes:
+// - MathExtension is tested through NewGoldmarkConverter output
+// - RenderMath is tested with a fake MathRenderer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// fakeKaTeX renders each source as a marked-up copy of its TeX.
+type fakeKaTeX struct {
+	err     error
+	sources []string
+	display []bool
+	short   bool // Return one expression too few
+}
+
+func (f *fakeKaTeX) RenderMath(_ context.Context, sources []string, display []bool) ([]string, error) {
+	f.sources, f.display = sources, display
+	if f.err != nil {
+		return nil, f.err
+	}
+	out := make([]string, len(sources))
+	for i, s := range sources {
+		out[i] = "<k>" + s + "</k>"
+	}
+	if f.short {
+		out = out[1:]
+	}
+	return out, nil
+}
+
+// mathBody converts markdown and returns the HTML body.
+func mathBody(t *testing.T, markdown string) string {
+	t.Helper()
+
+	got, err := NewGoldmarkConverter().ToHTML(context.Background(), markdown)
+	if err != nil {
+		t.Fatalf("ToHTML() unexpected error: %v", err)
+	}
+	start := strings.Index(got, "<body>\n") + len("<body>\n")
+	return strings.TrimSpace(got[start:strings.Index(got, "</body>")])
+}
+
+// ---------------------------------------------------------------------------
+// TestMathExtension - Dollar delimiters
+// ---------------------------------------------------------------------------
+
+func TestMathExtension(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"inline", "Energy $E=mc^2$ here", `<p>Energy <span class="math math-inline">$E=mc^2$</span> here</p>`},
+		{"TeX is raw", "$a_1 < b_2*c*$", `<p><span class="math math-inline">$a_1 &lt; b_2*c*$</span></p>`},
+		{"escaped dollar inside", `$\$5$`, `<p><span class="math math-inline">$\$5$</span></p>`},
+		{"prices stay text", "$5 and $10", "<p>$5 and $10</p>"},
+		{"space after opener is text", "$ x$", "<p>$ x$</p>"},
+		{"space before closer is text", "$x $", "<p>$x $</p>"},
+		{"closer before digit is text", "$x$1", "<p>$x$1</p>"},
+		{"escaped opener is text", `\$x$`, "<p>$x$</p>"},
+		{"code span wins", "`$x$`", "<p><code>$x$</code></p>"},
+		{"code span inside wins", "$5 or `$y$`", "<p>$5 or <code>$y$</code></p>"},
+		{"inline display", "so $$x^2$$ holds", `<p>so <span class="math math-display">$$x^2$$</span> holds</p>`},
+		{"three dollars are text", "$$$x$$$", "<p>$$$x$$$</p>"},
+		{"single line block", "$$ a+b $$", `<div class="math math-display">$$a+b$$</div>`},
+		{
+			"multi line block",
+			"Before\n$$\n\\int_0^1 x\\,dx\n\n= \\frac{1}{2}\n$$\nAfter",
+			"<p>Before</p>\n<div class=\"math math-display\">$$\\int_0^1 x\\,dx\n\n= \\frac{1}{2}$$</div>\n<p>After</p>",
+		},
+		{"content on fence lines", "$$a\nb$$", "<div class=\"math math-display\">$$a\nb$$</div>"},
+		{"block in blockquote", "> $$\n> x\n> $$", "<blockquote>\n<div class=\"math math-display\">$$x$$</div>\n</blockquote>"},
+		{"fenced code unchanged", "```\n$$\nx\n$$\n```", "<pre><code>$$\nx\n$$\n</code></pre>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := mathBody(t, tt.input); got != tt.want {
+				t.Errorf("ToHTML() body = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestRenderMath - Rendering, numbering and errors
+// ---------------------------------------------------------------------------
+
+func TestRenderMath(t *testing.T) {
+	t.Parallel()
+
+	html := `<p>Let <span class="math math-inline">$x &lt; 1$</span>.</p>
+<div class="math math-display">$$a$$</div>
+<div class="math math-display">$$b \tag{*}$$</div>
+<div class="math math-display">$$c \notag$$</div>
+<p><span class="math math-display">$$d$$</span></p>`
+
+	t.Run("renders in place without numbering", func(t *testing.T) {
+		t.Parallel()
+
+		r := &fakeKaTeX{}
+		got, err := RenderMath(context.Background(), r, html, false)
+		if err != nil {
+			t.Fatalf("RenderMath() unexpected error: %v", err)
+		}
+		wantSources := []string{"x < 1", "a", `b \tag{*}`, `c \notag`, "d"}
+		if fmt.Sprint(r.sources) != fmt.Sprint(wantSources) {
+			t.Errorf("RenderMath() sources = %q, want %q", r.sources, wantSources)
+		}
+		if fmt.Sprint(r.display) != "[false true true true true]" {
+			t.Errorf("RenderMath() display = %v, want inline then display", r.display)
+		}
+		for _, want := range []string{
+			`<p>Let <span class="math math-inline"><k>x < 1</k></span>.</p>`,
+			`<div class="math math-display"><k>a</k></div>`,
+			`<p><span class="math math-display"><k>d</k></span></p>`,
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("RenderMath() = %s, want %q", got, want)
+			}
+		}
+	})
+
+	t.Run("numbers display equations", func(t *testing.T) {
+		t.Parallel()
+
+		r := &fakeKaTeX{}
+		if _, err := RenderMath(context.Background(), r, html, true); err != nil {
+			t.Fatalf("RenderMath() unexpected error: %v", err)
+		}
+		want := []string{"x < 1", `a\tag{1}`, `b \tag{*}`, "c ", `d\tag{2}`}
+		if fmt.Sprint(r.sources) != fmt.Sprint(want) {
+			t.Errorf("RenderMath() sources = %q, want %q", r.sources, want)
+		}
+	})
+
+	t.Run("error names the expression", func(t *testing.T) {
+		t.Parallel()
+
+		r := &fakeKaTeX{err: &MathError{Index: 1, Message: "Undefined control sequence"}}
+		_, err := RenderMath(context.Background(), r, html, false)
+		if err == nil || err.Error() != `math "$$a$$": Undefined control sequence` {
+			t.Errorf("RenderMath() error = %v, want the failing expression", err)
+		}
+	})
+
+	t.Run("other errors are returned", func(t *testing.T) {
+		t.Parallel()
+
+		errBrowser := errors.New("browser gone")
+		_, err := RenderMath(context.Background(), &fakeKaTeX{err: errBrowser}, html, false)
+		if !errors.Is(err, errBrowser) {
+			t.Errorf("RenderMath() error = %v, want %v", err, errBrowser)
+		}
+	})
+
+	t.Run("count mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := RenderMath(context.Background(), &fakeKaTeX{short: true}, html, false); err == nil {
+			t.Error("RenderMath() error = nil, want error")
+		}
+	})
+
+	t.Run("no math skips the renderer", func(t *testing.T) {
+		t.Parallel()
+
+		r := &fakeKaTeX{err: errors.New("called")}
+		got, err := RenderMath(context.Background(), r, "<p>$5</p>", true)
+		if got != "<p>$5</p>" || err != nil {
+			t.Errorf("RenderMath() = %q, %v, want input unchanged", got, err)
+		}
+	})
+}
@@ -11,6 +11,7 @@ import (
 	highlighting "github.com/yuin/goldmark-highlighting/v2"
 	"github.com/yuin/goldmark/extension"
 	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
 	"github.com/yuin/goldmark/renderer/html"
 )
 
@@ -22,7 +23,7 @@ const htmlTemplate = `<!DOCTYPE html>
 <html>
 <head>
 <meta charset="utf-8">
-<title>Document</title>
+<title>` + DefaultTitle + `</title>
 </head>
 <body>
 %s
@@ -39,27 +40,52 @@ type GoldmarkConverter struct {
 	md goldmark.Markdown
 }
 
-// NewGoldmarkConverter creates a GoldmarkConverter with GFM extensions and syntax highlighting.
-func NewGoldmarkConverter() *GoldmarkConverter {
+// NewGoldmarkConverter creates a GoldmarkConverter with GFM extensions, math,
+// alerts, fenced divs, cross-references, index terms and syntax highlighting.
+// Raw HTML in the Markdown is omitted. extensions are added after the
+// built-in ones.
+func NewGoldmarkConverter(extensions ...goldmark.Extender) *GoldmarkConverter {
+	return newGoldmarkConverter(false, extensions)
+}
+
+// NewRawHTMLGoldmarkConverter creates a GoldmarkConverter like
+// NewGoldmarkConverter that writes raw HTML from the Markdown as is. Its
+// output must go through SanitizeHTML.
+func NewRawHTMLGoldmarkConverter(extensions ...goldmark.Extender) *GoldmarkConverter {
+	return newGoldmarkConverter(true, extensions)
+}
+
+func newGoldmarkConverter(rawHTML bool, extensions []goldmark.Extender) *GoldmarkConverter {
+	rendererOptions := []renderer.Option{
+		html.WithHardWraps(), // Treat newlines as <br>
+		html.WithXHTML(),     // Self-closing tags
+		// Note: WithUnsafe() is only used for raw HTML, whose output is
+		// sanitized. The ==highlight== feature uses placeholders converted
+		// after Goldmark.
+	}
+	if rawHTML {
+		rendererOptions = append(rendererOptions, html.WithUnsafe())
+	}
+
 	md := goldmark.New(
 		goldmark.WithExtensions(
 			extension.GFM,      // Tables, strikethrough, autolinks, task lists
 			extension.Footnote, // [^1] footnotes
+			MathExtension,      // $inline$ and $$display$$ math
+			ContainerExtension, // > [!NOTE] alerts and :::name fenced divs
+			CrossRefExtension,  // Numbered figures and tables, @fig:label references
+			IndexExtension,     // [[idx:term]] index terms
 			highlighting.NewHighlighting(
 				highlighting.WithFormatOptions(
 					chromahtml.WithClasses(true), // CSS classes for smaller HTML and external stylesheet control
 				),
 			),
 		),
+		goldmark.WithExtensions(extensions...), // Caller extensions, after the built-in ones
 		goldmark.WithParserOptions(
 			parser.WithAutoHeadingID(), // Generate IDs for headings (required for TOC)
 		),
-		goldmark.WithRendererOptions(
-			html.WithHardWraps(), // Treat newlines as <br>
-			html.WithXHTML(),     // Self-closing tags
-			// Note: WithUnsafe() intentionally NOT used for security.
-			// The ==highlight== feature uses placeholders converted after Goldmark.
-		),
+		goldmark.WithRendererOptions(rendererOptions...),
 	)
 	return &GoldmarkConverter{md: md}
 }
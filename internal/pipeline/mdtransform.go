@@ -15,6 +15,17 @@ const (
 	MarkEndPlaceholder   = "\uE001" // U+E001: Private Use Area end
 )
 
+// Landscape section placeholders stand alone in their own paragraph, so
+// Goldmark renders each as <p>placeholder</p>, replaced by the section tags
+// after HTML generation.
+const (
+	LandscapeStartPlaceholder = "\uE002" // U+E002: :::landscape
+	LandscapeEndPlaceholder   = "\uE003" // U+E003: closing :::
+)
+
+// LandscapeClass is the class of sections printed on landscape pages.
+const LandscapeClass = "landscape"
+
 // Precompiled regex patterns for performance.
 var (
 	// Line ending normalization
@@ -32,7 +43,17 @@ var (
 	// Only matches at start of document (after optional horizontal whitespace).
 	// Uses [ \t]* instead of \s* to avoid matching across blank lines,
 	// consistent with real-world frontmatter parsers (Jekyll, Hugo).
-	yamlFrontmatter = regexp.MustCompile(`(?s)^[ \t]*---\s*\n.*?\n---\s*\n`)
+	// Captures: 1=YAML content between the delimiters.
+	yamlFrontmatter = regexp.MustCompile(`(?s)^[ \t]*---\s*\n(.*?)\n---\s*\n`)
+
+	// Landscape directive lines: ":::landscape" opens, ":::" (or a longer
+	// run of colons) closes.
+	landscapeOpenPattern  = regexp.MustCompile(`^:::[ \t]*landscape[ \t]*$`)
+	directiveClosePattern = regexp.MustCompile(`^:{3,}[ \t]*$`)
+
+	// Fenced code block delimiters, indented up to 3 spaces.
+	// Captures: 1=fence
+	codeFencePattern = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})")
 )
 
 // MarkdownPreprocessor defines the contract for markdown preprocessing.
@@ -52,6 +73,7 @@ func (p *CommonMarkPreprocessor) PreprocessMarkdown(ctx context.Context, content
 
 	content = normalizeLineEndings(content)
 	content = stripFrontmatter(content)
+	content = convertLandscapeDirectives(content)
 	content = convertHighlights(content)
 	content = compressBlankLines(content)
 	return content
@@ -71,6 +93,88 @@ func stripFrontmatter(content string) string {
 	return yamlFrontmatter.ReplaceAllLiteralString(content, "")
 }
 
+// ExtractFrontmatter splits YAML frontmatter from the start of markdown content.
+// Line endings are normalized before matching, so the returned body uses \n.
+// Returns the YAML content between the delimiters (without them) and the
+// remaining markdown. If no well-formed frontmatter is present, frontmatter is
+// empty and body is the normalized content.
+func ExtractFrontmatter(content string) (frontmatter, body string) {
+	content = normalizeLineEndings(content)
+	loc := yamlFrontmatter.FindStringSubmatchIndex(content)
+	if loc == nil {
+		return "", content
+	}
+	return content[loc[2]:loc[3]], content[loc[1]:]
+}
+
+// convertLandscapeDirectives replaces ":::landscape" and its closing ":::"
+// with placeholder paragraphs. Directives inside fenced code blocks are left
+// as is, nested landscape blocks are merged into the outer one, other fenced
+// divs keep their own closing lines, and an unclosed block runs to the end of
+// the document.
+func convertLandscapeDirectives(content string) string {
+	if !strings.Contains(content, ":::") {
+		return content
+	}
+
+	lines := strings.Split(content, "\n")
+	out := make([]string, 0, len(lines))
+	fence := ""
+	var open []bool // Open fenced divs, innermost last; true for landscape
+	landscapes := 0
+	for _, line := range lines {
+		var inCode bool
+		if fence, inCode = trackCodeFence(fence, line); inCode {
+			out = append(out, line)
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case landscapeOpenPattern.MatchString(line):
+			if landscapes == 0 {
+				out = append(out, "", LandscapeStartPlaceholder, "")
+			}
+			open = append(open, true)
+			landscapes++
+		case len(open) > 0 && directiveClosePattern.MatchString(trimmed):
+			isLandscape := open[len(open)-1]
+			open = open[:len(open)-1]
+			if !isLandscape {
+				out = append(out, line)
+				break
+			}
+			landscapes--
+			if landscapes == 0 {
+				out = append(out, "", LandscapeEndPlaceholder, "")
+			}
+		default:
+			if m := fencedDivOpenPattern.FindStringSubmatch(trimmed); m != nil && (m[2] != "" || strings.TrimSpace(m[3]) != "") {
+				open = append(open, false)
+			}
+			out = append(out, line)
+		}
+	}
+	if landscapes > 0 {
+		out = append(out, "", LandscapeEndPlaceholder, "")
+	}
+	return strings.Join(out, "\n")
+}
+
+// trackCodeFence updates the open fence (empty outside code blocks) for one
+// line and reports whether the line is a fence or inside a fenced block.
+func trackCodeFence(fence, line string) (string, bool) {
+	if m := codeFencePattern.FindStringSubmatch(line); m != nil {
+		switch {
+		case fence == "":
+			return m[1], true
+		case m[1][0] == fence[0] && len(m[1]) >= len(fence) && strings.TrimSpace(line) == m[1]:
+			return "", true
+		}
+	}
+	return fence, fence != ""
+}
+
 // compressBlankLines limits consecutive blank lines to 2 maximum.
 func compressBlankLines(content string) string {
 	return multipleBlankLines.ReplaceAllString(content, "\n\n")
@@ -93,3 +197,24 @@ func ConvertMarkPlaceholders(content string) string {
 		MarkEndPlaceholder, "</mark>",
 	)
 }
+
+// ConvertLandscapePlaceholders converts landscape placeholder paragraphs to
+// <section class="landscape"> tags. Called after Goldmark HTML conversion,
+// like ConvertMarkPlaceholders.
+func ConvertLandscapePlaceholders(content string) string {
+	if !strings.Contains(content, LandscapeStartPlaceholder) {
+		return content
+	}
+	return strings.NewReplacer(
+		"<p>"+LandscapeStartPlaceholder+"</p>", `<section class="`+LandscapeClass+`">`,
+		"<p>"+LandscapeEndPlaceholder+"</p>", "</section>",
+		LandscapeStartPlaceholder, "",
+		LandscapeEndPlaceholder, "",
+	).Replace(content)
+}
+
+// HasLandscapeSections reports whether the document contains landscape
+// sections.
+func HasLandscapeSections(html string) bool {
+	return strings.Contains(html, `<section class="`+LandscapeClass+`">`)
+}
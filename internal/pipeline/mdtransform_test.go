@@ -225,6 +225,135 @@ func TestConvertMarkPlaceholders(t *testing.T) {
 	}
 }
 
+func TestConvertLandscapeDirectives(t *testing.T) {
+	t.Parallel()
+
+	open := "\n" + LandscapeStartPlaceholder + "\n\n"
+	closing := "\n" + LandscapeEndPlaceholder + "\n\n"
+	closingAtEnd := "\n" + LandscapeEndPlaceholder + "\n"
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "no directive unchanged",
+			input:    "# Title\n\ntext",
+			expected: "# Title\n\ntext",
+		},
+		{
+			name:     "block replaced by placeholder paragraphs",
+			input:    "before\n:::landscape\n| a | b |\n:::\nafter",
+			expected: "before\n" + open + "| a | b |\n" + closing + "after",
+		},
+		{
+			name:     "spacing after colons",
+			input:    "::: landscape \nwide\n:::",
+			expected: open + "wide\n" + closingAtEnd,
+		},
+		{
+			name:     "unclosed block runs to end",
+			input:    ":::landscape\nwide",
+			expected: open + "wide\n" + closingAtEnd,
+		},
+		{
+			name:     "nested block merged into outer",
+			input:    ":::landscape\na\n:::landscape\nb\n:::\nc\n:::",
+			expected: open + "a\nb\nc\n" + closingAtEnd,
+		},
+		{
+			name:     "closing colons without open block unchanged",
+			input:    "text\n:::\nmore",
+			expected: "text\n:::\nmore",
+		},
+		{
+			name:     "directive in fenced code unchanged",
+			input:    "```markdown\n:::landscape\n:::\n```",
+			expected: "```markdown\n:::landscape\n:::\n```",
+		},
+		{
+			name:     "directive after tilde fence is converted",
+			input:    "~~~\n```\n~~~\n:::landscape\nx\n:::",
+			expected: "~~~\n```\n~~~\n" + open + "x\n" + closingAtEnd,
+		},
+		{
+			name:     "indented directive unchanged",
+			input:    "  :::landscape\nx",
+			expected: "  :::landscape\nx",
+		},
+		{
+			name:     "fenced div inside block keeps its closer",
+			input:    ":::landscape\n:::note\nx\n:::\ny\n:::",
+			expected: open + ":::note\nx\n:::\ny\n" + closingAtEnd,
+		},
+		{
+			name:     "block inside fenced div",
+			input:    ":::note\n:::landscape\nx\n:::\n:::",
+			expected: ":::note\n" + open + "x\n" + closing + ":::",
+		},
+		{
+			name:     "longer closing colons",
+			input:    ":::landscape\nx\n::::\nafter",
+			expected: open + "x\n" + closing + "after",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := convertLandscapeDirectives(tt.input)
+			if got != tt.expected {
+				t.Errorf("convertLandscapeDirectives(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConvertLandscapePlaceholders(t *testing.T) {
+	t.Parallel()
+
+	t.Run("goldmark output becomes a section", func(t *testing.T) {
+		t.Parallel()
+
+		md := (&CommonMarkPreprocessor{}).PreprocessMarkdown(context.Background(), "Intro\n:::landscape\n| a | b |\n|---|---|\n| 1 | 2 |\n:::\nOutro")
+		html, err := NewGoldmarkConverter().ToHTML(context.Background(), md)
+		if err != nil {
+			t.Fatalf("ToHTML() unexpected error: %v", err)
+		}
+		got := ConvertLandscapePlaceholders(html)
+
+		want := `<section class="landscape">` + "\n<table>"
+		if !strings.Contains(got, want) || !strings.Contains(got, "</table>\n</section>") {
+			t.Errorf("ConvertLandscapePlaceholders() = %s, want table wrapped in landscape section", got)
+		}
+		if !HasLandscapeSections(got) {
+			t.Error("HasLandscapeSections() = false, want true")
+		}
+	})
+
+	t.Run("stray placeholders removed", func(t *testing.T) {
+		t.Parallel()
+
+		got := ConvertLandscapePlaceholders("<li>" + LandscapeStartPlaceholder + "x" + LandscapeEndPlaceholder + "</li>")
+		if got != "<li>x</li>" {
+			t.Errorf("ConvertLandscapePlaceholders() = %q, want %q", got, "<li>x</li>")
+		}
+	})
+
+	t.Run("no placeholders unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		if got := ConvertLandscapePlaceholders("<p>text</p>"); got != "<p>text</p>" {
+			t.Errorf("ConvertLandscapePlaceholders() = %q, want input unchanged", got)
+		}
+		if HasLandscapeSections("<p>text</p>") {
+			t.Error("HasLandscapeSections() = true, want false")
+		}
+	})
+}
+
 func TestStripFrontmatter(t *testing.T) {
 	t.Parallel()
 
@@ -349,6 +478,68 @@ func TestStripFrontmatter(t *testing.T) {
 	}
 }
 
+func TestExtractFrontmatter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		input           string
+		wantFrontmatter string
+		wantBody        string
+	}{
+		{
+			name:            "basic frontmatter",
+			input:           "---\ntitle: Test\n---\n# Content",
+			wantFrontmatter: "title: Test",
+			wantBody:        "# Content",
+		},
+		{
+			name:            "multiple keys",
+			input:           "---\ntitle: Test\nversion: v1\n---\nContent",
+			wantFrontmatter: "title: Test\nversion: v1",
+			wantBody:        "Content",
+		},
+		{
+			name:            "CRLF line endings are normalized",
+			input:           "---\r\ntitle: Test\r\n---\r\nContent\r\n",
+			wantFrontmatter: "title: Test",
+			wantBody:        "Content\n",
+		},
+		{
+			name:            "no frontmatter",
+			input:           "# Title\n\nContent",
+			wantFrontmatter: "",
+			wantBody:        "# Title\n\nContent",
+		},
+		{
+			name:            "unclosed frontmatter is left in body",
+			input:           "---\ntitle: Test\nContent",
+			wantFrontmatter: "",
+			wantBody:        "---\ntitle: Test\nContent",
+		},
+		{
+			name:            "horizontal rule later in document is not frontmatter",
+			input:           "Intro\n---\ntitle: Test\n---\nContent",
+			wantFrontmatter: "",
+			wantBody:        "Intro\n---\ntitle: Test\n---\nContent",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotFrontmatter, gotBody := ExtractFrontmatter(tt.input)
+			if gotFrontmatter != tt.wantFrontmatter {
+				t.Errorf("ExtractFrontmatter() frontmatter = %q, want %q", gotFrontmatter, tt.wantFrontmatter)
+			}
+			if gotBody != tt.wantBody {
+				t.Errorf("ExtractFrontmatter() body = %q, want %q", gotBody, tt.wantBody)
+			}
+		})
+	}
+}
+
 func TestCommonMarkPreprocessor_PreprocessMarkdown_CancelledContext(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,193 @@
+package pipeline
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// ChapterAnchorPrefix prefixes the ID of the <section> wrapping each merged chapter.
+// The first chapter is "chapter-1".
+const ChapterAnchorPrefix = "chapter-"
+
+// ChapterHTML is one converted chapter passed to MergeChapters.
+type ChapterHTML struct {
+	HTML      string // HTML document or fragment produced by an HTMLConverter
+	Path      string // Markdown source path (optional, enables links from other chapters)
+	SourceDir string // Base directory for relative links and images (optional)
+}
+
+// MergeChapters combines chapters into one HTML5 document, in order.
+//
+// Each chapter body is wrapped in <section class="chapter" id="chapter-N">.
+// Element IDs that collide with an earlier chapter are suffixed ("setup-1"),
+// and "#fragment" links are updated to match. Relative links to another
+// chapter's Markdown file ("install.md#setup") become in-document anchors.
+// Remaining relative paths are rewritten against the chapter's SourceDir,
+// as RewriteRelativePaths does for a single document.
+func MergeChapters(chapters []ChapterHTML) (string, error) {
+	bodies := make([]*html.Node, len(chapters))
+	for i, ch := range chapters {
+		doc, isFragment, err := parseHTML(ch.HTML)
+		if err != nil {
+			return "", fmt.Errorf("parsing chapter %d: %w", i+1, err)
+		}
+		bodies[i] = doc
+		if !isFragment {
+			if body := findElement(doc, "body"); body != nil {
+				bodies[i] = body
+			}
+		}
+	}
+
+	// Reserve chapter anchors first so content IDs never shadow them.
+	used := make(map[string]bool, len(chapters))
+	for i := range chapters {
+		used[chapterAnchor(i)] = true
+	}
+	idMaps := make([]map[string]string, len(chapters))
+	for i, body := range bodies {
+		idMaps[i] = make(map[string]string)
+		dedupeIDs(body, used, idMaps[i])
+	}
+
+	chapterIndex := make(map[string]int, len(chapters))
+	for i, ch := range chapters {
+		if ch.Path == "" {
+			continue
+		}
+		if abs, err := filepath.Abs(ch.Path); err == nil {
+			chapterIndex[abs] = i
+		}
+	}
+
+	var buf strings.Builder
+	for i, ch := range chapters {
+		sourceDir := ch.SourceDir
+		if sourceDir == "" && ch.Path != "" {
+			sourceDir = filepath.Dir(ch.Path)
+		}
+		var absSourceDir string
+		if sourceDir != "" {
+			var err error
+			if absSourceDir, err = filepath.Abs(sourceDir); err != nil {
+				return "", err
+			}
+		}
+
+		rewriteChapterLinks(bodies[i], absSourceDir, idMaps, i, chapterIndex)
+		if absSourceDir != "" {
+			rewriteNode(bodies[i], absSourceDir)
+		}
+
+		fmt.Fprintf(&buf, "<section class=\"chapter\" id=\"%s\">\n", chapterAnchor(i))
+		for c := bodies[i].FirstChild; c != nil; c = c.NextSibling {
+			if err := html.Render(&buf, c); err != nil {
+				return "", fmt.Errorf("rendering chapter %d: %w", i+1, err)
+			}
+		}
+		buf.WriteString("\n</section>\n")
+	}
+
+	return fmt.Sprintf(htmlTemplate, buf.String()), nil
+}
+
+// chapterAnchor returns the ID of the section wrapping chapter i (0-based).
+func chapterAnchor(i int) string {
+	return ChapterAnchorPrefix + strconv.Itoa(i+1)
+}
+
+// findElement returns the first element named tag in document order.
+func findElement(n *html.Node, tag string) *html.Node {
+	if n.Type == html.ElementNode && n.Data == tag {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findElement(c, tag); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// dedupeIDs renames IDs already present in used, using the same "-N" suffix
+// scheme as goldmark's auto heading IDs, and records renames in idMap.
+func dedupeIDs(n *html.Node, used map[string]bool, idMap map[string]string) {
+	if n.Type == html.ElementNode {
+		for i, attr := range n.Attr {
+			if attr.Key != "id" || attr.Val == "" {
+				continue
+			}
+			id := attr.Val
+			for suffix := 1; used[id]; suffix++ {
+				id = attr.Val + "-" + strconv.Itoa(suffix)
+			}
+			used[id] = true
+			if id != attr.Val {
+				idMap[attr.Val] = id
+				n.Attr[i].Val = id
+			}
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		dedupeIDs(c, used, idMap)
+	}
+}
+
+// rewriteChapterLinks points a[href] at merged anchors: "#id" links follow
+// renamed IDs, and links to another chapter's Markdown file become "#id" or
+// the chapter anchor.
+func rewriteChapterLinks(n *html.Node, sourceDir string, idMaps []map[string]string, current int, chapterIndex map[string]int) {
+	if n.Type == html.ElementNode && n.Data == "a" {
+		for i, attr := range n.Attr {
+			if attr.Key != "href" {
+				continue
+			}
+			if href, ok := resolveChapterLink(attr.Val, sourceDir, idMaps, current, chapterIndex); ok {
+				n.Attr[i].Val = href
+			}
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		rewriteChapterLinks(c, sourceDir, idMaps, current, chapterIndex)
+	}
+}
+
+// resolveChapterLink returns the in-document href for a link, or false if
+// the link does not target a merged chapter.
+func resolveChapterLink(href, sourceDir string, idMaps []map[string]string, current int, chapterIndex map[string]int) (string, bool) {
+	if fragment, ok := strings.CutPrefix(href, "#"); ok {
+		if renamed, ok := idMaps[current][fragment]; ok {
+			return "#" + renamed, true
+		}
+		return "", false
+	}
+
+	if sourceDir == "" || !isRelativePath(href) {
+		return "", false
+	}
+	target, fragment, _ := strings.Cut(href, "#")
+	ext := strings.ToLower(filepath.Ext(target))
+	if ext != ".md" && ext != ".markdown" {
+		return "", false
+	}
+	if unescaped, err := url.PathUnescape(target); err == nil {
+		target = unescaped
+	}
+
+	idx, ok := chapterIndex[filepath.Join(sourceDir, filepath.FromSlash(target))]
+	if !ok {
+		return "", false
+	}
+	if fragment == "" {
+		return "#" + chapterAnchor(idx), true
+	}
+	if renamed, ok := idMaps[idx][fragment]; ok {
+		return "#" + renamed, true
+	}
+	return "#" + fragment, true
+}
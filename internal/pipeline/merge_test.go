@@ -0,0 +1,170 @@
+package pipeline
+
+// Notes:
+// - Tests MergeChapters through its public API only
+// - Chapter HTML is written by hand in the shape goldmark produces (full
+//   document from htmlTemplate), so no converter is needed
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// TestMergeChapters - Multi-chapter document assembly
+// ---------------------------------------------------------------------------
+
+func TestMergeChapters(t *testing.T) {
+	t.Parallel()
+
+	docsDir := "/docs"
+	if runtime.GOOS == "windows" {
+		docsDir = `C:\docs`
+	}
+	doc := func(body string) string { return fmt.Sprintf(htmlTemplate, body) }
+
+	tests := []struct {
+		name         string
+		chapters     []ChapterHTML
+		wantContains []string
+		wantExcludes []string
+	}{
+		{
+			name: "wraps chapters in order in a single document",
+			chapters: []ChapterHTML{
+				{HTML: doc(`<h1 id="intro">Intro</h1>`)},
+				{HTML: `<h1 id="usage">Usage</h1>`},
+			},
+			wantContains: []string{
+				`<section class="chapter" id="chapter-1">`,
+				`<section class="chapter" id="chapter-2">`,
+				`<h1 id="intro">Intro</h1>`,
+				`<h1 id="usage">Usage</h1>`,
+			},
+		},
+		{
+			name: "duplicate heading IDs get suffixes and local links follow",
+			chapters: []ChapterHTML{
+				{HTML: doc(`<h2 id="setup">Setup</h2>`)},
+				{HTML: doc(`<h2 id="setup">Setup</h2><p><a href="#setup">again</a></p>`)},
+			},
+			wantContains: []string{`<h2 id="setup">`, `<h2 id="setup-1">`, `href="#setup-1"`},
+		},
+		{
+			name: "footnote IDs are unique across chapters",
+			chapters: []ChapterHTML{
+				{HTML: doc(`<sup id="fnref:1"><a href="#fn:1">1</a></sup><li id="fn:1">A</li>`)},
+				{HTML: doc(`<sup id="fnref:1"><a href="#fn:1">1</a></sup><li id="fn:1">B</li>`)},
+			},
+			wantContains: []string{`id="fn:1-1"`, `href="#fn:1-1"`, `id="fnref:1-1"`},
+		},
+		{
+			name: "content IDs never shadow chapter anchors",
+			chapters: []ChapterHTML{
+				{HTML: doc(`<h1 id="chapter-2">Chapter 2</h1>`)},
+				{HTML: doc(`<p>x</p>`)},
+			},
+			wantContains: []string{`<h1 id="chapter-2-1">`},
+		},
+		{
+			name: "links between chapters become anchors",
+			chapters: []ChapterHTML{
+				{
+					HTML: doc(`<a href="install.md">install</a> <a href="./install.md#setup">setup</a>`),
+					Path: filepath.Join(docsDir, "intro.md"),
+				},
+				{
+					HTML: doc(`<h2 id="prereq">Prereq</h2><h2 id="setup">Setup</h2>`),
+					Path: filepath.Join(docsDir, "install.md"),
+				},
+			},
+			wantContains: []string{`href="#chapter-2"`, `href="#setup"`},
+			wantExcludes: []string{`install.md`},
+		},
+		{
+			name: "cross-chapter fragment follows renamed ID",
+			chapters: []ChapterHTML{
+				{
+					HTML: doc(`<h2 id="setup">Setup</h2>`),
+					Path: filepath.Join(docsDir, "a.md"),
+				},
+				{
+					HTML: doc(`<h2 id="setup">Setup</h2>`),
+					Path: filepath.Join(docsDir, "b.md"),
+				},
+				{
+					HTML: doc(`<a href="b.md#setup">b</a>`),
+					Path: filepath.Join(docsDir, "c.md"),
+				},
+			},
+			wantContains: []string{`href="#setup-1"`},
+		},
+		{
+			name: "links to non-chapter markdown and images use file URLs",
+			chapters: []ChapterHTML{
+				{
+					HTML: doc(`<a href="other.md">other</a><img src="img/logo.png"/>`),
+					Path: filepath.Join(docsDir, "intro.md"),
+				},
+			},
+			wantContains: []string{`href="file://`, `src="file://`},
+		},
+		{
+			name: "external links unchanged",
+			chapters: []ChapterHTML{
+				{
+					HTML: doc(`<a href="https://example.com/x.md">x</a>`),
+					Path: filepath.Join(docsDir, "intro.md"),
+				},
+			},
+			wantContains: []string{`href="https://example.com/x.md"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := MergeChapters(tt.chapters)
+			if err != nil {
+				t.Fatalf("MergeChapters() unexpected error: %v", err)
+			}
+			if strings.Count(got, "<body>") != 1 {
+				t.Errorf("MergeChapters() body count = %d, want 1", strings.Count(got, "<body>"))
+			}
+			for _, want := range tt.wantContains {
+				if !strings.Contains(got, want) {
+					t.Errorf("MergeChapters() missing %q in:\n%s", want, got)
+				}
+			}
+			for _, exclude := range tt.wantExcludes {
+				if strings.Contains(got, exclude) {
+					t.Errorf("MergeChapters() should not contain %q in:\n%s", exclude, got)
+				}
+			}
+		})
+	}
+}
+
+func TestMergeChapters_TOCSpansChapters(t *testing.T) {
+	t.Parallel()
+
+	merged, err := MergeChapters([]ChapterHTML{
+		{HTML: fmt.Sprintf(htmlTemplate, `<h2 id="overview">Overview</h2>`)},
+		{HTML: fmt.Sprintf(htmlTemplate, `<h2 id="overview">Overview</h2>`)},
+	})
+	if err != nil {
+		t.Fatalf("MergeChapters() unexpected error: %v", err)
+	}
+
+	headings := extractHeadings(merged, 2, 3)
+	if len(headings) != 2 {
+		t.Fatalf("extractHeadings() = %d headings, want 2", len(headings))
+	}
+	if headings[0].ID == headings[1].ID {
+		t.Errorf("heading IDs = %q and %q, want distinct", headings[0].ID, headings[1].ID)
+	}
+}
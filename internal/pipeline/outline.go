@@ -0,0 +1,23 @@
+package pipeline
+
+// Heading is a document heading with an anchor ID.
+type Heading struct {
+	Level int    // 1-6
+	ID    string // anchor ID
+	Text  string // heading text content
+}
+
+// ExtractHeadings returns the headings between minDepth and maxDepth in
+// document order. Headings without IDs are skipped, since nothing can link
+// to them.
+func ExtractHeadings(htmlContent string, minDepth, maxDepth int) []Heading {
+	infos := extractHeadings(htmlContent, minDepth, maxDepth)
+	if len(infos) == 0 {
+		return nil
+	}
+	headings := make([]Heading, len(infos))
+	for i, h := range infos {
+		headings[i] = Heading(h)
+	}
+	return headings
+}
@@ -0,0 +1,73 @@
+package pipeline
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+)
+
+// ErrPageTemplateRender is returned when a header or footer template fails to render.
+var ErrPageTemplateRender = errors.New("page template rendering failed")
+
+// Chrome fills elements with these classes when printing headers and footers.
+const (
+	chromePageNumber = `<span class="pageNumber"></span>`
+	chromeTotalPages = `<span class="totalPages"></span>`
+	chromeTitle      = `<span class="title"></span>`
+)
+
+// PageTemplateData holds the values available to custom header.html and
+// footer.html templates.
+type PageTemplateData struct {
+	CoverData // Document metadata: {{.Title}}, {{.Version}}, {{.DocumentID}}, ...
+
+	Status string // Footer status text
+	Text   string // Footer custom text
+
+	// Chrome placeholders, filled per page at print time.
+	PageNumber template.HTML
+	TotalPages template.HTML
+	PageTitle  template.HTML // Document <title>
+}
+
+// NewPageTemplateData returns data with Chrome placeholders set.
+// A nil cover leaves document metadata empty.
+func NewPageTemplateData(cover *CoverData) *PageTemplateData {
+	data := &PageTemplateData{
+		PageNumber: chromePageNumber,
+		TotalPages: chromeTotalPages,
+		PageTitle:  chromeTitle,
+	}
+	if cover != nil {
+		data.CoverData = *cover
+	}
+	return data
+}
+
+// PageTemplate renders a user-defined header or footer for Chrome's
+// HeaderTemplate/FooterTemplate print options.
+type PageTemplate struct {
+	tmpl *template.Template
+}
+
+// NewPageTemplate creates a PageTemplate from template content.
+// Returns error if the template cannot be parsed.
+func NewPageTemplate(name, tmplContent string) (*PageTemplate, error) {
+	tmpl, err := template.New(name).Parse(tmplContent)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s template: %w", name, err)
+	}
+
+	return &PageTemplate{tmpl: tmpl}, nil
+}
+
+// Render executes the template against data.
+// Returns error if template rendering fails.
+func (p *PageTemplate) Render(data *PageTemplateData) (string, error) {
+	var buf bytes.Buffer
+	if err := p.tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrPageTemplateRender, err)
+	}
+	return buf.String(), nil
+}
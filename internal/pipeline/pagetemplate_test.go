@@ -0,0 +1,106 @@
+package pipeline
+
+// Notes:
+// - Tests NewPageTemplate/Render through the public API only
+// - Chrome placeholders must survive html/template escaping as raw spans
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// TestPageTemplate - Custom header/footer rendering
+// ---------------------------------------------------------------------------
+
+func TestPageTemplate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		tmpl         string
+		data         *PageTemplateData
+		wantContains []string
+		wantExcludes []string
+	}{
+		{
+			name: "renders metadata and chrome placeholders",
+			tmpl: `<div>{{.DocumentID}} {{.Status}} Page {{.PageNumber}} of {{.TotalPages}} {{.PageTitle}}</div>`,
+			data: func() *PageTemplateData {
+				d := NewPageTemplateData(&CoverData{DocumentID: "DOC-1"})
+				d.Status = "CONFIDENTIAL"
+				return d
+			}(),
+			wantContains: []string{
+				"DOC-1 CONFIDENTIAL",
+				`<span class="pageNumber"></span>`,
+				`<span class="totalPages"></span>`,
+				`<span class="title"></span>`,
+			},
+		},
+		{
+			name:         "escapes metadata",
+			tmpl:         `<div>{{.Title}}</div>`,
+			data:         NewPageTemplateData(&CoverData{Title: "<script>x</script>"}),
+			wantContains: []string{"&lt;script&gt;"},
+			wantExcludes: []string{"<script>"},
+		},
+		{
+			name:         "nil cover leaves metadata empty",
+			tmpl:         `<div>[{{.Title}}]</div>`,
+			data:         NewPageTemplateData(nil),
+			wantContains: []string{"<div>[]</div>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pt, err := NewPageTemplate("footer", tt.tmpl)
+			if err != nil {
+				t.Fatalf("NewPageTemplate() unexpected error: %v", err)
+			}
+			got, err := pt.Render(tt.data)
+			if err != nil {
+				t.Fatalf("Render() unexpected error: %v", err)
+			}
+			for _, want := range tt.wantContains {
+				if !strings.Contains(got, want) {
+					t.Errorf("Render() missing %q in %q", want, got)
+				}
+			}
+			for _, exclude := range tt.wantExcludes {
+				if strings.Contains(got, exclude) {
+					t.Errorf("Render() should not contain %q in %q", exclude, got)
+				}
+			}
+		})
+	}
+}
+
+func TestPageTemplate_Errors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid syntax fails to parse", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := NewPageTemplate("header", "{{.Title"); err == nil {
+			t.Error("NewPageTemplate() error = nil, want parse error")
+		}
+	})
+
+	t.Run("unknown field fails to render", func(t *testing.T) {
+		t.Parallel()
+
+		pt, err := NewPageTemplate("header", "{{.Nope}}")
+		if err != nil {
+			t.Fatalf("NewPageTemplate() unexpected error: %v", err)
+		}
+		_, err = pt.Render(NewPageTemplateData(nil))
+		if !errors.Is(err, ErrPageTemplateRender) {
+			t.Errorf("Render() error = %v, want ErrPageTemplateRender", err)
+		}
+	})
+}
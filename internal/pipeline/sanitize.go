@@ -0,0 +1,190 @@
+package pipeline
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// DefaultAllowedTags are the elements SanitizeHTML keeps: everything the
+// Markdown renderer and its extensions write, plus common inline and block
+// HTML such as <kbd>, <sub> and <details>.
+var DefaultAllowedTags = []string{
+	"a", "abbr", "article", "aside", "b", "bdi", "bdo", "blockquote", "br",
+	"caption", "center", "cite", "code", "col", "colgroup", "dd", "del",
+	"details", "dfn", "div", "dl", "dt", "em", "figcaption", "figure",
+	"h1", "h2", "h3", "h4", "h5", "h6", "hr", "i", "img", "input", "ins",
+	"kbd", "li", "mark", "ol", "p", "pre", "q", "rp", "rt", "ruby", "s",
+	"samp", "section", "small", "span", "strong", "sub", "summary", "sup",
+	"table", "tbody", "td", "tfoot", "th", "thead", "time", "tr", "u", "ul",
+	"var", "wbr",
+}
+
+// DefaultAllowedAttributes are the attributes SanitizeHTML keeps on any
+// allowed element. data-* and aria-* attributes are always kept.
+var DefaultAllowedAttributes = []string{
+	"align", "alt", "checked", "cite", "class", "colspan", "datetime", "dir",
+	"disabled", "height", "href", "id", "lang", "name", "open", "reversed",
+	"role", "rowspan", "scope", "span", "src", "start", "tabindex", "title",
+	"type", "value", "width",
+}
+
+// urlAttributes hold URLs, checked for script schemes and data: URLs.
+var urlAttributes = map[string]bool{
+	"action": true, "background": true, "cite": true, "formaction": true,
+	"href": true, "longdesc": true, "poster": true, "src": true,
+	"srcset": true, "xlink:href": true,
+}
+
+// dataImagePattern matches the data: URLs kept in <img src>: raster images,
+// after normalizeURL.
+var dataImagePattern = regexp.MustCompile(`^data:image/(?:png|jpeg|gif|webp)[;,]`)
+
+// rawTextTags are removed with their content when not allowed: their
+// content is code or markup, not text to keep.
+var rawTextTags = map[string]bool{
+	"embed": true, "frame": true, "frameset": true, "iframe": true,
+	"noembed": true, "noframes": true, "noscript": true, "object": true,
+	"script": true, "style": true, "template": true, "textarea": true,
+	"title": true, "xmp": true,
+}
+
+// HTMLAllowlist lists the elements and attributes SanitizeHTML keeps, in
+// addition to the defaults.
+type HTMLAllowlist struct {
+	Tags       []string
+	Attributes []string
+}
+
+// SanitizeHTML removes from the body of htmlContent every element and
+// attribute outside the default allowlist and allow, and comments. Removed
+// elements keep their text, except scripts, styles and embedded content,
+// which are removed whole. Scripts, on* event handlers, javascript: and
+// vbscript: URLs, and data: URLs other than raster images in <img src> are
+// removed even when allowed.
+func SanitizeHTML(htmlContent string, allow HTMLAllowlist) (string, error) {
+	doc, isFragment, err := parseHTML(htmlContent)
+	if err != nil {
+		return "", err
+	}
+
+	s := newSanitizer(allow)
+	root := doc
+	if !isFragment {
+		if body := findBody(doc); body != nil {
+			root = body
+		}
+	}
+	s.sanitizeChildren(root)
+	return renderHTML(doc, isFragment)
+}
+
+// sanitizer holds the allowlist as sets of lowercase names.
+type sanitizer struct {
+	tags  map[string]bool
+	attrs map[string]bool
+}
+
+func newSanitizer(allow HTMLAllowlist) *sanitizer {
+	s := &sanitizer{tags: make(map[string]bool), attrs: make(map[string]bool)}
+	for _, tags := range [][]string{DefaultAllowedTags, allow.Tags} {
+		for _, tag := range tags {
+			s.tags[strings.ToLower(tag)] = true
+		}
+	}
+	for _, attrs := range [][]string{DefaultAllowedAttributes, allow.Attributes} {
+		for _, attr := range attrs {
+			s.attrs[strings.ToLower(attr)] = true
+		}
+	}
+	delete(s.tags, "script")
+	return s
+}
+
+// sanitizeChildren sanitizes the children of n, unwrapping or removing the
+// elements that are not allowed.
+func (s *sanitizer) sanitizeChildren(n *html.Node) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		switch c.Type {
+		case html.CommentNode:
+			n.RemoveChild(c)
+		case html.ElementNode:
+			s.sanitizeChildren(c)
+			switch {
+			case s.tags[c.Data]:
+				c.Attr = s.sanitizeAttrs(c.Data, c.Attr)
+			case rawTextTags[c.Data]:
+				n.RemoveChild(c)
+			default:
+				// Unwrap: move the sanitized children up in place of c.
+				for gc := c.FirstChild; gc != nil; gc = c.FirstChild {
+					c.RemoveChild(gc)
+					n.InsertBefore(gc, c)
+				}
+				n.RemoveChild(c)
+			}
+		}
+		c = next
+	}
+}
+
+// sanitizeAttrs returns the allowed attributes of attrs, on element tag.
+func (s *sanitizer) sanitizeAttrs(tag string, attrs []html.Attribute) []html.Attribute {
+	kept := attrs[:0]
+	for _, attr := range attrs {
+		key := strings.ToLower(attr.Key)
+		if attr.Namespace != "" {
+			key = attr.Namespace + ":" + key
+		}
+		allowed := s.attrs[key] || strings.HasPrefix(key, "data-") || strings.HasPrefix(key, "aria-")
+		if !allowed || strings.HasPrefix(key, "on") {
+			continue
+		}
+		if urlAttributes[key] && isUnsafeURL(tag, key, attr.Val) {
+			continue
+		}
+		kept = append(kept, attr)
+	}
+	return kept
+}
+
+// isUnsafeURL reports whether url, in attribute key of element tag, uses
+// the javascript: or vbscript: scheme, or is a data: URL other than a raster
+// image in <img src>: a data:text/html link runs its own scripts.
+func isUnsafeURL(tag, key, url string) bool {
+	url = normalizeURL(url)
+	switch {
+	case strings.HasPrefix(url, "javascript:"), strings.HasPrefix(url, "vbscript:"):
+		return true
+	case strings.HasPrefix(url, "data:"):
+		return tag != "img" || key != "src" || !dataImagePattern.MatchString(url)
+	}
+	return false
+}
+
+// normalizeURL lowercases url and drops whitespace and control characters,
+// which browsers ignore in the scheme.
+func normalizeURL(url string) string {
+	url = strings.Map(func(r rune) rune {
+		if r <= ' ' {
+			return -1
+		}
+		return r
+	}, url)
+	return strings.ToLower(url)
+}
+
+// findBody returns the body element of doc, or nil.
+func findBody(n *html.Node) *html.Node {
+	if n.Type == html.ElementNode && n.Data == "body" {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if body := findBody(c); body != nil {
+			return body
+		}
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package pipeline
+
+// Notes:
+// - The default allowlist is checked against goldmark output with every
+//   extension, since later stages match the markup it writes
+// - Expected output is html.Render's serialization, as in pathrewrite tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// ---------------------------------------------------------------------------
+// TestSanitizeHTML - Allowlist sanitizer
+// ---------------------------------------------------------------------------
+
+func TestSanitizeHTML(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		allow HTMLAllowlist
+		want  string
+	}{
+		{
+			name:  "keeps allowed elements",
+			input: `<p>Press <kbd>Ctrl</kbd>, H<sub>2</sub>O<br/></p><details open=""><summary>More</summary><div class="note">x</div></details>`,
+			want:  `<p>Press <kbd>Ctrl</kbd>, H<sub>2</sub>O<br/></p><details open=""><summary>More</summary><div class="note">x</div></details>`,
+		},
+		{
+			name:  "removes scripts and styles with their content",
+			input: `<p>a<script>alert(1)</script>b</p><style>p{}</style><iframe src="x"></iframe>`,
+			want:  `<p>ab</p>`,
+		},
+		{
+			name:  "unwraps unknown elements",
+			input: `<p><font color="red">red <b>bold</b></font></p><form><button>Go</button></form>`,
+			want:  `<p>red <b>bold</b></p>Go`,
+		},
+		{
+			name:  "removes event handlers and unknown attributes",
+			input: `<img src="a.png" alt="A" onerror="alert(1)" style="width:1px" data-x="1"/><div ONCLICK="x" aria-label="L">d</div>`,
+			want:  `<img src="a.png" alt="A" data-x="1"/><div aria-label="L">d</div>`,
+		},
+		{
+			name:  "removes script URLs",
+			input: `<a href="javascript:alert(1)">a</a><a href=" JaVa&#09;Script:x">b</a><a href="vbscript:x">c</a><a href="https://example.com">d</a>`,
+			want:  `<a>a</a><a>b</a><a>c</a><a href="https://example.com">d</a>`,
+		},
+		{
+			name:  "removes data URLs except raster images",
+			input: `<a href="data:text/html,&lt;script&gt;alert(1)&lt;/script&gt;">a</a><a href=" DATA:text/html;base64,PHNjcmlwdD4=">b</a><img src="data:image/svg+xml,&lt;svg/&gt;"/><img src="data:image/png;base64,iVBORw0K"/><img srcset="data:image/png;base64,iVBORw0K"/><img src="data:IMAGE/WEBP;base64,UklGR"/>`,
+			want:  `<a>a</a><a>b</a><img/><img src="data:image/png;base64,iVBORw0K"/><img/><img src="data:IMAGE/WEBP;base64,UklGR"/>`,
+		},
+		{
+			name:  "removes comments",
+			input: `<p>a<!-- hidden -->b</p>`,
+			want:  `<p>ab</p>`,
+		},
+		{
+			name:  "allowlist adds elements and attributes",
+			input: `<p style="color: red"><u>x</u><abbr title="t">y</abbr><meter value="1">z</meter></p>`,
+			allow: HTMLAllowlist{Tags: []string{"METER"}, Attributes: []string{"style"}},
+			want:  `<p style="color: red"><u>x</u><abbr title="t">y</abbr><meter value="1">z</meter></p>`,
+		},
+		{
+			name:  "script and handlers stay removed when allowed",
+			input: `<p onclick="x">a<script>b</script></p>`,
+			allow: HTMLAllowlist{Tags: []string{"script"}, Attributes: []string{"onclick"}},
+			want:  `<p>a</p>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := SanitizeHTML(tt.input, tt.allow)
+			if err != nil {
+				t.Fatalf("SanitizeHTML() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("SanitizeHTML() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("leaves head of a document alone", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := SanitizeHTML(`<!DOCTYPE html><html><head><meta charset="utf-8"/><title>T</title></head><body><p>x</p><script>y</script></body></html>`, HTMLAllowlist{})
+		if err != nil {
+			t.Fatalf("SanitizeHTML() unexpected error: %v", err)
+		}
+		want := `<!DOCTYPE html><html><head><meta charset="utf-8"/><title>T</title></head><body><p>x</p></body></html>`
+		if got != want {
+			t.Errorf("SanitizeHTML() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("default allowlist keeps Markdown output", func(t *testing.T) {
+		t.Parallel()
+
+		markdown := "# Title {#top}\n\n" +
+			"Text with *em*, **strong**, ~~del~~, `code`, [link](https://example.com), " +
+			"$x^2$, [[idx:term]] and a note[^1].\nNew line.\n\n" +
+			"![Diagram](a.png){#fig:a}\n\n" +
+			"| A | B |\n|:--|--:|\n| 1 | 2 |\n\n: Numbers {#tbl:n}\n\n" +
+			"- [x] done\n- [ ] todo\n\n3. three\n\n" +
+			"> [!NOTE]\n> Alert.\n\n" +
+			":::warning{.box #w}\nFenced.\n:::\n\n" +
+			"```go\nfunc main() {}\n```\n\n$$\nE = mc^2\n$$\n\n---\n\n" +
+			"See @fig:a.\n\n[^1]: The note.\n"
+		content, err := NewGoldmarkConverter().ToHTML(context.Background(), markdown)
+		if err != nil {
+			t.Fatalf("ToHTML() unexpected error: %v", err)
+		}
+		doc, isFragment, err := parseHTML(content)
+		if err != nil {
+			t.Fatalf("parseHTML() unexpected error: %v", err)
+		}
+		want, err := renderHTML(doc, isFragment)
+		if err != nil {
+			t.Fatalf("renderHTML() unexpected error: %v", err)
+		}
+
+		got, err := SanitizeHTML(content, HTMLAllowlist{})
+		if err != nil {
+			t.Fatalf("SanitizeHTML() unexpected error: %v", err)
+		}
+		if got != want {
+			t.Errorf("SanitizeHTML() changed Markdown output:\ngot:  %s\nwant: %s", got, want)
+		}
+	})
+}
+
+// ---------------------------------------------------------------------------
+// TestRawHTMLGoldmarkConverter - Raw HTML passthrough
+// ---------------------------------------------------------------------------
+
+func TestRawHTMLGoldmarkConverter(t *testing.T) {
+	t.Parallel()
+
+	markdown := "Line one<br>line two, <kbd>Ctrl</kbd>.\n\n<details>\n<summary>More</summary>\n\nHidden *text*.\n\n</details>\n"
+
+	tests := []struct {
+		name      string
+		converter *GoldmarkConverter
+		want      []string
+		notWant   []string
+	}{
+		{
+			name:      "default converter omits raw HTML",
+			converter: NewGoldmarkConverter(),
+			want:      []string{"<!-- raw HTML omitted -->"},
+			notWant:   []string{"<kbd>", "<details>"},
+		},
+		{
+			name:      "raw HTML converter keeps it",
+			converter: NewRawHTMLGoldmarkConverter(),
+			want:      []string{"<br>", "<kbd>Ctrl</kbd>", "<details>", "<summary>More</summary>", "<p>Hidden <em>text</em>.</p>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.converter.ToHTML(context.Background(), markdown)
+			if err != nil {
+				t.Fatalf("ToHTML() unexpected error: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("ToHTML() missing %q in:\n%s", want, got)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(got, notWant) {
+					t.Errorf("ToHTML() contains %q in:\n%s", notWant, got)
+				}
+			}
+		})
+	}
+}
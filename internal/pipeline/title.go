@@ -0,0 +1,50 @@
+package pipeline
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// DefaultTitle is the placeholder <title> of generated HTML documents.
+const DefaultTitle = "Document"
+
+// titlePattern matches the first <title> element.
+// Captures: 1=text
+var titlePattern = regexp.MustCompile(`(?is)<title>(.*?)</title>`)
+
+// SetTitle replaces the text of the first <title> element with title.
+// Returns htmlContent unchanged if title is empty or there is no <title>.
+func SetTitle(htmlContent, title string) string {
+	if title == "" {
+		return htmlContent
+	}
+	loc := titlePattern.FindStringSubmatchIndex(htmlContent)
+	if loc == nil {
+		return htmlContent
+	}
+	return htmlContent[:loc[2]] + html.EscapeString(title) + htmlContent[loc[3]:]
+}
+
+// DocumentTitle returns the text of the first <title> element.
+// Returns "" if there is none or it still holds DefaultTitle.
+func DocumentTitle(htmlContent string) string {
+	m := titlePattern.FindStringSubmatch(htmlContent)
+	if m == nil {
+		return ""
+	}
+	title := html.UnescapeString(strings.TrimSpace(m[1]))
+	if title == DefaultTitle {
+		return ""
+	}
+	return title
+}
+
+// FirstHeading returns the text of the first H1 with an ID, or "".
+func FirstHeading(htmlContent string) string {
+	headings := extractHeadings(htmlContent, 1, 1)
+	if len(headings) == 0 {
+		return ""
+	}
+	return headings[0].Text
+}
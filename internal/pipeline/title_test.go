@@ -0,0 +1,98 @@
+package pipeline
+
+// Notes:
+// - Titles are escaped on write and unescaped on read
+// - DefaultTitle reads back as "" so callers can detect an unset title
+
+import (
+	"fmt"
+	"testing"
+)
+
+const titleDoc = "<html><head><title>" + DefaultTitle + "</title></head><body>%s</body></html>"
+
+// ---------------------------------------------------------------------------
+// TestSetTitle - <title> replacement
+// ---------------------------------------------------------------------------
+
+func TestSetTitle(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		html  string
+		title string
+		want  string
+	}{
+		{"replaces default", "<head><title>Document</title></head>", "Guide", "<head><title>Guide</title></head>"},
+		{"escapes markup", "<title>Document</title>", "A & <B>", "<title>A &amp; &lt;B&gt;</title>"},
+		{"empty title is no-op", "<title>Document</title>", "", "<title>Document</title>"},
+		{"missing title element", "<body></body>", "Guide", "<body></body>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := SetTitle(tt.html, tt.title); got != tt.want {
+				t.Errorf("SetTitle(%q, %q) = %q, want %q", tt.html, tt.title, got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestDocumentTitle - <title> extraction
+// ---------------------------------------------------------------------------
+
+func TestDocumentTitle(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"custom title", "<title>A &amp; B</title>", "A & B"},
+		{"default title", "<title>Document</title>", ""},
+		{"no title", "<body></body>", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := DocumentTitle(tt.html); got != tt.want {
+				t.Errorf("DocumentTitle(%q) = %q, want %q", tt.html, got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestFirstHeading - H1 extraction
+// ---------------------------------------------------------------------------
+
+func TestFirstHeading(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"first h1", `<h2 id="a">Intro</h2><h1 id="b">Main <em>Title</em></h1><h1 id="c">Other</h1>`, "Main Title"},
+		{"no h1", `<h2 id="a">Intro</h2>`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			html := fmt.Sprintf(titleDoc, tt.body)
+			if got := FirstHeading(html); got != tt.want {
+				t.Errorf("FirstHeading() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package pipeline
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// Code placeholders wrap the index of a code span or fenced code line taken
+// out of the template, so actions inside code are never executed.
+const (
+	codeStartPlaceholder = "\uE006" // U+E006: Private Use Area
+	codeEndPlaceholder   = "\uE007" // U+E007: Private Use Area
+)
+
+var (
+	// Masked code in the template output.
+	// Captures: 1=code index
+	codePlaceholderPattern = regexp.MustCompile(codeStartPlaceholder + `(\d+)` + codeEndPlaceholder)
+
+	// Location prefix of text/template errors.
+	// Captures: 1=line and column
+	templateErrorPattern = regexp.MustCompile(`^template: markdown:(\d+(?::\d+)?): (?:executing "markdown" at )?`)
+)
+
+// VariableInterpolator substitutes template variables in Markdown.
+type VariableInterpolator interface {
+	InterpolateVariables(content string, data any) (string, error)
+}
+
+// InterpolateVariables executes {{ ... }} actions in the Markdown body with
+// text/template against data, for example {{ .Document.Version }}.
+// Frontmatter, fenced code blocks and code spans are left as written, and a
+// reference to a missing field or map key is an error. Write {{"{{"}} for
+// literal braces. Errors name the line in content.
+func (p *CommonMarkPreprocessor) InterpolateVariables(content string, data any) (string, error) {
+	if !strings.Contains(content, "{{") {
+		return content, nil
+	}
+
+	content = normalizeLineEndings(content)
+	frontmatter := ""
+	if loc := yamlFrontmatter.FindStringIndex(content); loc != nil {
+		frontmatter, content = content[:loc[1]], content[loc[1]:]
+	}
+	masked, code := maskCode(content)
+
+	// Leading newlines keep template line numbers aligned with the source.
+	pad := strings.Repeat("\n", strings.Count(frontmatter, "\n"))
+	tmpl, err := template.New("markdown").Option("missingkey=error").Parse(pad + masked)
+	if err != nil {
+		return "", templateError(err)
+	}
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", templateError(err)
+	}
+
+	return frontmatter + unmaskCode(buf.String()[len(pad):], code), nil
+}
+
+// templateError rewrites a text/template error as "line N:C: message".
+func templateError(err error) error {
+	msg := err.Error()
+	if m := templateErrorPattern.FindStringSubmatch(msg); m != nil {
+		return fmt.Errorf("line %s: %s", m[1], msg[len(m[0]):])
+	}
+	return err
+}
+
+// maskCode replaces fenced code lines and code spans with placeholders and
+// returns the masked content with the code, indexed by placeholder.
+func maskCode(content string) (string, []string) {
+	var code []string
+	mask := func(s string) string {
+		code = append(code, s)
+		return codeStartPlaceholder + strconv.Itoa(len(code)-1) + codeEndPlaceholder
+	}
+
+	lines := strings.Split(content, "\n")
+	fence := ""
+	for i, line := range lines {
+		var inCode bool
+		if fence, inCode = trackCodeFence(fence, line); inCode {
+			lines[i] = mask(line)
+		}
+	}
+	return maskCodeSpans(strings.Join(lines, "\n"), mask), code
+}
+
+// unmaskCode restores the code taken out by maskCode.
+func unmaskCode(content string, code []string) string {
+	return codePlaceholderPattern.ReplaceAllStringFunc(content, func(m string) string {
+		idx, err := strconv.Atoi(codePlaceholderPattern.FindStringSubmatch(m)[1])
+		if err != nil || idx >= len(code) {
+			return m
+		}
+		return code[idx]
+	})
+}
+
+// maskCodeSpans replaces `code spans` in content with mask(span). A span
+// closes at the next backtick run of the same length within its paragraph;
+// an unmatched run is text.
+func maskCodeSpans(content string, mask func(string) string) string {
+	if !strings.Contains(content, "`") {
+		return content
+	}
+	var b strings.Builder
+	for i := 0; i < len(content); {
+		if content[i] == '\\' {
+			end := min(i+2, len(content))
+			b.WriteString(content[i:end])
+			i = end
+			continue
+		}
+		if content[i] != '`' {
+			b.WriteByte(content[i])
+			i++
+			continue
+		}
+
+		n := backtickRun(content, i)
+		end := closingBacktickRun(content, i+n, n)
+		if end < 0 {
+			b.WriteString(content[i : i+n])
+			i += n
+			continue
+		}
+		b.WriteString(mask(content[i:end]))
+		i = end
+	}
+	return b.String()
+}
+
+// backtickRun returns the length of the backtick run starting at i.
+func backtickRun(s string, i int) int {
+	n := 0
+	for i+n < len(s) && s[i+n] == '`' {
+		n++
+	}
+	return n
+}
+
+// closingBacktickRun returns the end of the first run of exactly n backticks
+// at or after i, before a blank line, or -1.
+func closingBacktickRun(s string, i, n int) int {
+	for i < len(s) {
+		switch {
+		case strings.HasPrefix(s[i:], "\n\n"):
+			return -1
+		case s[i] == '`':
+			run := backtickRun(s, i)
+			if run == n {
+				return i + n
+			}
+			i += run
+		default:
+			i++
+		}
+	}
+	return -1
+}
@@ -0,0 +1,108 @@
+package pipeline
+
+// Notes:
+// - Data is a plain struct and map, standing in for picoloom.Variables
+// - Error messages are checked for the source line of the failing action
+
+import (
+	"strings"
+	"testing"
+)
+
+type testDocument struct {
+	Title   string
+	Version string
+}
+
+type testVariables struct {
+	Document testDocument
+	Vars     map[string]string
+}
+
+// ---------------------------------------------------------------------------
+// TestInterpolateVariables - Substitution and code masking
+// ---------------------------------------------------------------------------
+
+func TestInterpolateVariables(t *testing.T) {
+	t.Parallel()
+
+	data := testVariables{
+		Document: testDocument{Title: "Guide", Version: "v2.1"},
+		Vars:     map[string]string{"client": "ACME"},
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"no action unchanged", "Plain text\r\nwith CRLF", "Plain text\r\nwith CRLF"},
+		{"document field", "Release {{ .Document.Version }} of {{.Document.Title}}.", "Release v2.1 of Guide."},
+		{"custom variable", "For {{ .Vars.client }}.", "For ACME."},
+		{"code span untouched", "Write `{{ .Vars.client }}` for {{ .Vars.client }}.", "Write `{{ .Vars.client }}` for ACME."},
+		{"double backtick span untouched", "``a ` {{ .X }}`` {{ .Vars.client }}", "``a ` {{ .X }}`` ACME"},
+		{"unmatched backtick is text", "a ` b {{ .Vars.client }}", "a ` b ACME"},
+		{"span does not cross paragraphs", "a `\n\n{{ .Vars.client }} `", "a `\n\nACME `"},
+		{"escaped backtick is text", "\\`{{ .Vars.client }}`", "\\`ACME`"},
+		{
+			"fenced code untouched",
+			"{{ .Vars.client }}\n```go\nt := \"{{ .Name }}\"\n```\n{{ .Document.Version }}",
+			"ACME\n```go\nt := \"{{ .Name }}\"\n```\nv2.1",
+		},
+		{"frontmatter untouched", "---\ntitle: \"{{ x }}\"\n---\n{{ .Document.Title }}", "---\ntitle: \"{{ x }}\"\n---\nGuide"},
+		{"literal braces", `{{"{{"}} .Vars.client }}`, "{{ .Vars.client }}"},
+		{"conditional", "{{ if .Vars.client }}for {{ .Vars.client }}{{ end }}", "for ACME"},
+	}
+
+	p := &CommonMarkPreprocessor{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := p.InterpolateVariables(tt.input, data)
+			if err != nil {
+				t.Fatalf("InterpolateVariables(%q) unexpected error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("InterpolateVariables(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestInterpolateVariables_Errors - Unknown variables and bad syntax
+// ---------------------------------------------------------------------------
+
+func TestInterpolateVariables_Errors(t *testing.T) {
+	t.Parallel()
+
+	data := testVariables{Vars: map[string]string{"client": "ACME"}}
+
+	tests := []struct {
+		name    string
+		input   string
+		wantMsg string
+	}{
+		{"unknown custom variable", "a\n{{ .Vars.clinet }}", `line 2:8: <.Vars.clinet>: map has no entry for key "clinet"`},
+		{"unknown field", "{{ .Document.Owner }}", "line 1:12: "},
+		{"unknown section", "{{ .Client }}", "line 1:3: "},
+		{"line counted after frontmatter", "---\na: b\n---\n\n{{ .Nope }}", "line 5:"},
+		{"syntax error", "{{ .Vars.client ", "line 1: "},
+	}
+
+	p := &CommonMarkPreprocessor{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := p.InterpolateVariables(tt.input, data)
+			if err == nil {
+				t.Fatalf("InterpolateVariables(%q) error = nil, want error", tt.input)
+			}
+			if !strings.HasPrefix(err.Error(), tt.wantMsg) {
+				t.Errorf("InterpolateVariables(%q) error = %q, want prefix %q", tt.input, err, tt.wantMsg)
+			}
+		})
+	}
+}
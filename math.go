@@ -0,0 +1,53 @@
+package picoloom
+
+import (
+	"context"
+
+	"github.com/alnah/picoloom/v2/internal/assets"
+	"github.com/alnah/picoloom/v2/internal/pipeline"
+)
+
+// katexRenderScript renders each expression in turn and reports the first
+// one KaTeX rejects. trust is off, so \href, \includegraphics and other
+// commands that reach outside the document are refused.
+const katexRenderScript = `(sources, display) => {
+  const html = [];
+  for (let i = 0; i < sources.length; i++) {
+    try {
+      html.push(katex.renderToString(sources[i], {
+        displayMode: display[i], throwOnError: true, trust: false, strict: "ignore",
+      }));
+    } catch (e) {
+      return { html: html, failed: i, message: String((e && e.message) || e) };
+    }
+  }
+  return { html: html, failed: -1 };
+}`
+
+// katexResult is the value returned by katexRenderScript.
+type katexResult struct {
+	HTML    []string `json:"html"`
+	Failed  int      `json:"failed"`
+	Message string   `json:"message"`
+}
+
+// RenderMath renders TeX expressions in the converter's browser.
+func (c *rodConverter) RenderMath(ctx context.Context, sources []string, display []bool) ([]string, error) {
+	return c.renderer.RenderMath(ctx, sources, display)
+}
+
+// RenderMath renders TeX expressions to HTML with the embedded KaTeX bundle.
+func (r *rodRenderer) RenderMath(ctx context.Context, sources []string, display []bool) ([]string, error) {
+	script, err := assets.KatexScript()
+	if err != nil {
+		return nil, err
+	}
+	var res katexResult
+	if err := r.evalBundle(ctx, script, katexRenderScript, &res, sources, display); err != nil {
+		return nil, err
+	}
+	if res.Failed >= 0 {
+		return nil, &pipeline.MathError{Index: res.Failed, Message: res.Message}
+	}
+	return res.HTML, nil
+}
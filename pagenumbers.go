@@ -0,0 +1,281 @@
+package picoloom
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alnah/picoloom/v2/internal/pdfedit"
+	"github.com/alnah/picoloom/v2/internal/pipeline"
+)
+
+// pageNumberer computes the page numbers printed on a document.
+// Pages are indexed from 0.
+type pageNumberer struct {
+	format     string
+	front      string
+	startAt    int
+	frontPages int // Pages before the first body page
+	pageCount  int
+}
+
+// newPageNumberer applies numbering defaults. frontPages counts the cover
+// and TOC pages; it is ignored when front matter continues the numbering.
+func newPageNumberer(n *PageNumbering, frontPages, pageCount int) *pageNumberer {
+	p := &pageNumberer{
+		format:     strings.ToLower(n.Format),
+		front:      strings.ToLower(n.FrontMatter),
+		startAt:    n.StartAt,
+		frontPages: frontPages,
+		pageCount:  pageCount,
+	}
+	if p.startAt == 0 {
+		p.startAt = 1
+	}
+	if p.front == "" || p.front == FrontMatterContinue || p.frontPages >= pageCount {
+		p.front = FrontMatterContinue
+		p.frontPages = 0
+	}
+	return p
+}
+
+// number returns the arabic number of page i, or 0 on front matter pages.
+func (p *pageNumberer) number(i int) int {
+	if i < p.frontPages {
+		return 0
+	}
+	return p.startAt + i - p.frontPages
+}
+
+// total returns the number printed as the page count: the last arabic number.
+func (p *pageNumberer) total() int {
+	return p.number(p.pageCount - 1)
+}
+
+// text returns the bare number of page i: arabic, roman on roman front
+// matter, or "" on skipped front matter.
+func (p *pageNumberer) text(i int) string {
+	if i >= p.frontPages {
+		return strconv.Itoa(p.number(i))
+	}
+	if p.front == FrontMatterRoman {
+		return toRoman(i + 1)
+	}
+	return ""
+}
+
+// label returns the page number of page i in the configured format.
+// Front matter pages show their bare number.
+func (p *pageNumberer) label(i int) string {
+	text := p.text(i)
+	if i < p.frontPages {
+		return text
+	}
+	switch p.format {
+	case PageNumberFormatPageOf:
+		return fmt.Sprintf("Page %s of %d", text, p.total())
+	case PageNumberFormatPlain:
+		return text
+	}
+	return fmt.Sprintf("%s/%d", text, p.total())
+}
+
+// labelRanges returns the PDF page labels matching the printed numbers.
+// Skipped front matter pages get empty labels.
+func (p *pageNumberer) labelRanges() []pdfedit.PageLabelRange {
+	body := pdfedit.PageLabelRange{FirstPage: p.frontPages, Style: pdfedit.LabelDecimal, Start: p.startAt}
+	switch {
+	case p.frontPages == 0:
+		return []pdfedit.PageLabelRange{body}
+	case p.front == FrontMatterRoman:
+		return []pdfedit.PageLabelRange{{FirstPage: 0, Style: pdfedit.LabelRomanLower, Start: 1}, body}
+	}
+	return []pdfedit.PageLabelRange{{FirstPage: 0, Style: pdfedit.LabelNone}, body}
+}
+
+// romanNumerals lists roman numeral values in descending order.
+var romanNumerals = []struct {
+	value  int
+	symbol string
+}{
+	{1000, "m"}, {900, "cm"}, {500, "d"}, {400, "cd"}, {100, "c"}, {90, "xc"},
+	{50, "l"}, {40, "xl"}, {10, "x"}, {9, "ix"}, {5, "v"}, {4, "iv"}, {1, "i"},
+}
+
+// toRoman returns n in lowercase roman numerals.
+func toRoman(n int) string {
+	var b strings.Builder
+	for _, r := range romanNumerals {
+		for n >= r.value {
+			b.WriteString(r.symbol)
+			n -= r.value
+		}
+	}
+	return b.String()
+}
+
+// frontMatterPages returns the number of pages before the content start
+// marker, or 0 when the document has none.
+func frontMatterPages(doc *pdfedit.Document, pages []pdfedit.Ref) (int, error) {
+	named, err := doc.NamedDestinations()
+	if err != nil {
+		return 0, err
+	}
+	dest, ok := named[pipeline.ContentStartID]
+	if !ok {
+		return 0, nil
+	}
+	if page := pdfedit.PageNumber(dest, pages); page > 0 {
+		return page - 1, nil
+	}
+	return 0, nil
+}
+
+// applyPageNumbering sets page labels and, when the document has a footer or
+// header, stamps them with the configured numbers. Chrome's templates print
+// the same page counter on every page, so the footer and header of each page
+// are laid out on a stamp sheet instead, printed by renderSheet at the
+// document's page sizes, and drawn over the matching page.
+// Returns data unchanged if opts sets no numbering.
+func applyPageNumbering(data []byte, opts *pdfOptions, renderSheet func(sheet string) ([]byte, error)) ([]byte, error) {
+	if opts == nil || opts.Numbering == nil {
+		return data, nil
+	}
+
+	doc, err := pdfedit.Open(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrPDFPostProcess, err)
+	}
+	pages, err := doc.Pages()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrPDFPostProcess, err)
+	}
+	front, err := frontMatterPages(doc, pages)
+	if err != nil {
+		return nil, fmt.Errorf("%w: locating front matter: %w", ErrPDFPostProcess, err)
+	}
+	numberer := newPageNumberer(opts.Numbering, front, len(pages))
+
+	update := doc.NewUpdate()
+	if err := pdfedit.SetPageLabels(update, numberer.labelRanges()); err != nil {
+		return nil, fmt.Errorf("%w: page labels: %w", ErrPDFPostProcess, err)
+	}
+	if !opts.hasFooter() && !opts.hasHeader() {
+		return update.Bytes(), nil
+	}
+
+	sizes := make([][2]float64, len(pages))
+	for i, page := range pages {
+		if sizes[i][0], sizes[i][1], err = doc.PageSize(page); err != nil {
+			return nil, fmt.Errorf("%w: page %d size: %w", ErrPDFPostProcess, i+1, err)
+		}
+	}
+	sheetPDF, err := renderSheet(buildStampSheet(opts, numberer, sizes))
+	if err != nil {
+		return nil, fmt.Errorf("printing page numbers: %w", err)
+	}
+	if err := stampPages(update, pages, sheetPDF); err != nil {
+		return nil, fmt.Errorf("%w: page numbers: %w", ErrPDFPostProcess, err)
+	}
+	return update.Bytes(), nil
+}
+
+// stampPages draws each page of the stamp sheet over the matching page.
+func stampPages(u *pdfedit.Update, pages []pdfedit.Ref, sheetPDF []byte) error {
+	sheet, err := pdfedit.Open(sheetPDF)
+	if err != nil {
+		return err
+	}
+	sheetPages, err := sheet.Pages()
+	if err != nil {
+		return err
+	}
+	if len(sheetPages) != len(pages) {
+		return fmt.Errorf("stamp sheet has %d pages, want %d", len(sheetPages), len(pages))
+	}
+
+	importer := pdfedit.NewPageImporter(u, sheet)
+	for i, page := range pages {
+		form, err := importer.Import(sheetPages[i])
+		if err != nil {
+			return fmt.Errorf("page %d: %w", i+1, err)
+		}
+		if err := pdfedit.StampPage(u, page, form); err != nil {
+			return fmt.Errorf("page %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// chromeClassPattern matches the empty elements Chrome fills in custom
+// footer and header templates.
+// Captures: 1=start tag, 2=class
+var chromeClassPattern = regexp.MustCompile(`(<span\b[^>]*\bclass="(pageNumber|totalPages|title|date)"[^>]*>)\s*</span>`)
+
+// buildStampSheet lays out the footer and header of every page on a page of
+// the same size (in points), in the margins the document reserves for them.
+func buildStampSheet(opts *pdfOptions, n *pageNumberer, sizes [][2]float64) string {
+	top, _, bottom, _ := pageMargins(opts)
+	title := ""
+	if opts.Metadata != nil {
+		title = opts.Metadata.Title
+	}
+	date := time.Now().Format("1/2/2006")
+	fillChrome := func(tmpl string, i int) string {
+		return chromeClassPattern.ReplaceAllStringFunc(tmpl, func(m string) string {
+			sub := chromeClassPattern.FindStringSubmatch(m)
+			value := ""
+			switch sub[2] {
+			case "pageNumber":
+				value = n.text(i)
+			case "totalPages":
+				value = strconv.Itoa(n.total())
+			case "title":
+				value = title
+			case "date":
+				value = date
+			}
+			return sub[1] + html.EscapeString(value) + "</span>"
+		})
+	}
+
+	var css, body strings.Builder
+	css.WriteString("@page { margin: 0; }\n")
+	css.WriteString("html, body { margin: 0; padding: 0; }\n")
+	css.WriteString(".sheet { position: relative; overflow: hidden; break-after: page; }\n")
+	css.WriteString(".sheet:last-child { break-after: auto; }\n")
+	css.WriteString(".band { position: absolute; left: 0; right: 0; display: flex; align-items: center; }\n")
+
+	named := make(map[[2]float64]string)
+	for i, size := range sizes {
+		name, ok := named[size]
+		if !ok {
+			name = fmt.Sprintf("size%d", len(named))
+			named[size] = name
+			fmt.Fprintf(&css, "@page %s { size: %.2fpt %.2fpt; }\n", name, size[0], size[1])
+		}
+
+		fmt.Fprintf(&body, `<div class="sheet" style="page: %s; width: %.2fpt; height: %.2fpt;">`, name, size[0], size[1])
+		if opts.hasHeader() {
+			header := opts.HeaderTemplate
+			if header == "" {
+				header = renderHeader(opts.Header, html.EscapeString(n.text(i)), strconv.Itoa(n.total()))
+			}
+			fmt.Fprintf(&body, `<div class="band" style="top: 0; height: %.2fin;">%s</div>`, top, fillChrome(header, i))
+		}
+		if opts.hasFooter() {
+			footer := opts.FooterTemplate
+			if footer == "" {
+				footer = renderFooter(opts.Footer, html.EscapeString(n.label(i)))
+			}
+			fmt.Fprintf(&body, `<div class="band" style="bottom: 0; height: %.2fin;">%s</div>`, bottom, fillChrome(footer, i))
+		}
+		body.WriteString("</div>\n")
+	}
+
+	return "<!DOCTYPE html>\n<html><head><meta charset=\"UTF-8\"><style>\n" + css.String() +
+		"</style></head><body>\n" + body.String() + "</body></html>\n"
+}
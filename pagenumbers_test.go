@@ -0,0 +1,264 @@
+package picoloom
+
+// Notes:
+// - Front matter pages are counted from the content start named destination
+// - applyPageNumbering is tested with a fake stamp sheet renderer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/alnah/picoloom/v2/internal/pdfedit"
+	"github.com/alnah/picoloom/v2/internal/pipeline"
+)
+
+// numberedPDF returns a three-page PDF whose body starts on page 3, after a
+// cover and a TOC page.
+func numberedPDF() []byte {
+	return buildTestPDF("",
+		"<</Type /Catalog /Pages 2 0 R /Dests <</"+pipeline.ContentStartID+" [5 0 R /XYZ 0 792 0]>>>>",
+		"<</Type /Pages /Count 3 /Kids [3 0 R 4 0 R 5 0 R] /MediaBox [0 0 612 792]>>",
+		"<</Type /Page /Parent 2 0 R>>",
+		"<</Type /Page /Parent 2 0 R>>",
+		"<</Type /Page /Parent 2 0 R /MediaBox [0 0 792 612]>>",
+	)
+}
+
+// sheetPDF returns a stamp sheet with one empty page per document page.
+func sheetPDF(pages int) []byte {
+	objects := []string{"<</Type /Catalog /Pages 2 0 R>>", ""}
+	var kids []string
+	for i := range pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", i+3))
+		objects = append(objects, "<</Type /Page /Parent 2 0 R /MediaBox [0 0 612 792]>>")
+	}
+	objects[1] = fmt.Sprintf("<</Type /Pages /Count %d /Kids [%s]>>", pages, strings.Join(kids, " "))
+	return buildTestPDF("", objects...)
+}
+
+// ---------------------------------------------------------------------------
+// TestPageNumberer - Printed page numbers
+// ---------------------------------------------------------------------------
+
+func TestPageNumberer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		numbering PageNumbering
+		want      []string // label of each of 4 pages, 2 of them front matter
+	}{
+		{"defaults continue", PageNumbering{}, []string{"1/4", "2/4", "3/4", "4/4"}},
+		{"start offset", PageNumbering{StartAt: 5}, []string{"5/8", "6/8", "7/8", "8/8"}},
+		{"page of", PageNumbering{Format: PageNumberFormatPageOf}, []string{"Page 1 of 4", "Page 2 of 4", "Page 3 of 4", "Page 4 of 4"}},
+		{"plain", PageNumbering{Format: PageNumberFormatPlain}, []string{"1", "2", "3", "4"}},
+		{"skip front matter", PageNumbering{FrontMatter: FrontMatterSkip}, []string{"", "", "1/2", "2/2"}},
+		{"roman front matter", PageNumbering{FrontMatter: "Roman", Format: "PAGE-OF"}, []string{"i", "ii", "Page 1 of 2", "Page 2 of 2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			n := newPageNumberer(&tt.numbering, 2, 4)
+			var got []string
+			for i := range 4 {
+				got = append(got, n.label(i))
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("labels = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("front matter covering every page continues", func(t *testing.T) {
+		t.Parallel()
+
+		n := newPageNumberer(&PageNumbering{FrontMatter: FrontMatterSkip}, 2, 2)
+		if got := n.label(0); got != "1/2" {
+			t.Errorf("label(0) = %q, want %q", got, "1/2")
+		}
+	})
+}
+
+// ---------------------------------------------------------------------------
+// TestToRoman - Roman numerals
+// ---------------------------------------------------------------------------
+
+func TestToRoman(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{1, "i"}, {4, "iv"}, {9, "ix"}, {14, "xiv"}, {40, "xl"}, {1994, "mcmxciv"},
+	}
+	for _, tt := range tests {
+		if got := toRoman(tt.n); got != tt.want {
+			t.Errorf("toRoman(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestBuildStampSheet - Footer and header layout per page
+// ---------------------------------------------------------------------------
+
+func TestBuildStampSheet(t *testing.T) {
+	t.Parallel()
+
+	t.Run("generated footer and header", func(t *testing.T) {
+		t.Parallel()
+
+		opts := &pdfOptions{
+			Footer: &pipeline.FooterData{ShowPageNumber: true, Text: "Internal"},
+			Header: &pipeline.HeaderData{Right: "{pageNumber} of {totalPages}"},
+		}
+		n := newPageNumberer(&PageNumbering{FrontMatter: FrontMatterRoman}, 1, 2)
+		sheet := buildStampSheet(opts, n, [][2]float64{{612, 792}, {792, 612}})
+
+		for _, want := range []string{
+			"@page size0 { size: 612.00pt 792.00pt; }",
+			"@page size1 { size: 792.00pt 612.00pt; }",
+			"i - Internal",
+			"1/1 - Internal",
+			"i of 1",
+			"1 of 1",
+			"height: 0.75in",
+		} {
+			if !strings.Contains(sheet, want) {
+				t.Errorf("buildStampSheet() missing %q in:\n%s", want, sheet)
+			}
+		}
+		if strings.Contains(sheet, "pageNumber") {
+			t.Errorf("buildStampSheet() left a Chrome placeholder:\n%s", sheet)
+		}
+	})
+
+	t.Run("custom template placeholders are filled", func(t *testing.T) {
+		t.Parallel()
+
+		opts := &pdfOptions{
+			Footer:         &pipeline.FooterData{},
+			FooterTemplate: `<div>Page <span class="pageNumber"></span> of <span class="totalPages"></span> - <span class="title"></span></div>`,
+			Metadata:       &pdfedit.Metadata{Title: "A & B"},
+		}
+		n := newPageNumberer(&PageNumbering{StartAt: 3}, 0, 1)
+		sheet := buildStampSheet(opts, n, [][2]float64{{612, 792}})
+
+		want := `Page <span class="pageNumber">3</span> of <span class="totalPages">3</span> - <span class="title">A &amp; B</span>`
+		if !strings.Contains(sheet, want) {
+			t.Errorf("buildStampSheet() missing %q in:\n%s", want, sheet)
+		}
+	})
+}
+
+// ---------------------------------------------------------------------------
+// TestApplyPageNumbering - Page labels and stamped footers
+// ---------------------------------------------------------------------------
+
+func TestApplyPageNumbering(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil numbering is no-op", func(t *testing.T) {
+		t.Parallel()
+
+		data := numberedPDF()
+		got, err := applyPageNumbering(data, &pdfOptions{}, nil)
+		if err != nil || len(got) != len(data) {
+			t.Errorf("applyPageNumbering() = %d bytes, %v, want input unchanged", len(got), err)
+		}
+	})
+
+	t.Run("labels only without footer or header", func(t *testing.T) {
+		t.Parallel()
+
+		opts := &pdfOptions{Numbering: &PageNumbering{FrontMatter: FrontMatterRoman}}
+		got, err := applyPageNumbering(numberedPDF(), opts, func(string) ([]byte, error) {
+			t.Error("renderSheet called without footer or header")
+			return nil, nil
+		})
+		if err != nil {
+			t.Fatalf("applyPageNumbering() unexpected error: %v", err)
+		}
+
+		doc, err := pdfedit.Open(got)
+		if err != nil {
+			t.Fatalf("pdfedit.Open() unexpected error: %v", err)
+		}
+		catalog, _, err := doc.Catalog()
+		if err != nil {
+			t.Fatalf("Catalog() unexpected error: %v", err)
+		}
+		labels, _ := catalog["PageLabels"].(pdfedit.Dict)
+		want := "[0 map[S:r] 2 map[S:D]]"
+		if got := fmt.Sprint(labels["Nums"]); got != want {
+			t.Errorf("/PageLabels /Nums = %s, want %s", got, want)
+		}
+	})
+
+	t.Run("stamps every page", func(t *testing.T) {
+		t.Parallel()
+
+		opts := &pdfOptions{
+			Footer:    &pipeline.FooterData{ShowPageNumber: true},
+			Numbering: &PageNumbering{FrontMatter: FrontMatterSkip},
+		}
+		var sheet string
+		got, err := applyPageNumbering(numberedPDF(), opts, func(html string) ([]byte, error) {
+			sheet = html
+			return sheetPDF(3), nil
+		})
+		if err != nil {
+			t.Fatalf("applyPageNumbering() unexpected error: %v", err)
+		}
+		if !strings.Contains(sheet, "1/1") || !strings.Contains(sheet, "size: 792.00pt 612.00pt") {
+			t.Errorf("stamp sheet = %s, want body page 1/1 at landscape size", sheet)
+		}
+
+		doc, err := pdfedit.Open(got)
+		if err != nil {
+			t.Fatalf("pdfedit.Open() unexpected error: %v", err)
+		}
+		pages, err := doc.Pages()
+		if err != nil {
+			t.Fatalf("Pages() unexpected error: %v", err)
+		}
+		for i, ref := range pages {
+			page, err := doc.ResolveDict(ref)
+			if err != nil {
+				t.Fatalf("page %d unexpected error: %v", i+1, err)
+			}
+			if contents, ok := page["Contents"].(pdfedit.Array); !ok || len(contents) != 2 {
+				t.Errorf("page %d /Contents = %v, want q and stamp streams", i+1, page["Contents"])
+			}
+		}
+	})
+
+	t.Run("sheet page count mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		opts := &pdfOptions{Footer: &pipeline.FooterData{}, Numbering: &PageNumbering{}}
+		_, err := applyPageNumbering(numberedPDF(), opts, func(string) ([]byte, error) {
+			return sheetPDF(2), nil
+		})
+		if !errors.Is(err, ErrPDFPostProcess) {
+			t.Errorf("applyPageNumbering() error = %v, want %v", err, ErrPDFPostProcess)
+		}
+	})
+
+	t.Run("sheet render error is returned", func(t *testing.T) {
+		t.Parallel()
+
+		opts := &pdfOptions{Header: &pipeline.HeaderData{}, Numbering: &PageNumbering{}}
+		_, err := applyPageNumbering(numberedPDF(), opts, func(string) ([]byte, error) {
+			return nil, ErrPDFGeneration
+		})
+		if !errors.Is(err, ErrPDFGeneration) {
+			t.Errorf("applyPageNumbering() error = %v, want %v", err, ErrPDFGeneration)
+		}
+	})
+}
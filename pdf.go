@@ -12,6 +12,7 @@ import (
 
 	"github.com/alnah/picoloom/v2/internal/fileutil"
 	"github.com/alnah/picoloom/v2/internal/hints"
+	"github.com/alnah/picoloom/v2/internal/pdfedit"
 	"github.com/alnah/picoloom/v2/internal/pipeline"
 	"github.com/alnah/picoloom/v2/internal/process"
 	"github.com/go-rod/rod"
@@ -35,21 +36,55 @@ type pdfRenderer interface {
 
 // pdfOptions holds options for PDF generation.
 type pdfOptions struct {
-	Footer *pipeline.FooterData
-	Page   *PageSettings
+	Footer         *pipeline.FooterData
+	Header         *pipeline.HeaderData
+	FooterTemplate string // Rendered custom footer, replaces the generated one
+	HeaderTemplate string // Rendered custom header, replaces the generated one
+	Page           *PageSettings
+	Metadata       *pdfedit.Metadata   // Document properties, nil skips post-processing
+	Outline        []pipeline.Heading  // Headings to bookmark, nil = no outline
+	PDFA           bool                // Post-process into PDF/A-2b
+	Encryption     *pdfedit.Encryption // Password protection, applied last
+
+	// Numbering replaces Chrome's page numbers: the footer and header are
+	// printed on a separate sheet and stamped onto each page.
+	Numbering *PageNumbering
+	// Sheet prints the stamp sheet itself: edge to edge at the CSS page size.
+	Sheet bool
+	// CSSPageSize prints each page at its @page size, for landscape sections.
+	CSSPageSize bool
+	// Footnotes are stamped at the foot of the pages referencing them, in
+	// the band the document reserves.
+	Footnotes *footnoteLayout
+	// PostProcessors run on the final PDF, after stamping and before
+	// post-processing. Drafts have none.
+	PostProcessors []PDFPostProcessor
 }
 
 // footerMarginExtra is added to bottom margin when footer is active.
 const footerMarginExtra = 0.25
 
-// Page dimensions in inches (ISO/ANSI standards).
+// headerMarginExtra is added to top margin when header is active.
+const headerMarginExtra = 0.25
+
+// Page dimensions in inches (ISO/ANSI/JIS standards).
 const (
-	letterWidthInches  = 8.5
-	letterHeightInches = 11.0
-	a4WidthInches      = 8.27  // 210mm
-	a4HeightInches     = 11.69 // 297mm
-	legalWidthInches   = 8.5
-	legalHeightInches  = 14.0
+	letterWidthInches     = 8.5
+	letterHeightInches    = 11.0
+	a4WidthInches         = 8.27  // 210mm
+	a4HeightInches        = 11.69 // 297mm
+	legalWidthInches      = 8.5
+	legalHeightInches     = 14.0
+	a3WidthInches         = 11.69 // 297mm
+	a3HeightInches        = 16.54 // 420mm
+	a5WidthInches         = 5.83  // 148mm
+	a5HeightInches        = 8.27  // 210mm
+	b5WidthInches         = 6.93  // 176mm (ISO)
+	b5HeightInches        = 9.84  // 250mm
+	executiveWidthInches  = 7.25
+	executiveHeightInches = 10.5
+	tabloidWidthInches    = 11.0
+	tabloidHeightInches   = 17.0
 )
 
 // Footer styling constants.
@@ -61,9 +96,14 @@ const (
 
 // pageDimensions maps page size to (width, height) in inches.
 var pageDimensions = map[string]struct{ width, height float64 }{
-	PageSizeLetter: {letterWidthInches, letterHeightInches},
-	PageSizeA4:     {a4WidthInches, a4HeightInches},
-	PageSizeLegal:  {legalWidthInches, legalHeightInches},
+	PageSizeLetter:    {letterWidthInches, letterHeightInches},
+	PageSizeA4:        {a4WidthInches, a4HeightInches},
+	PageSizeLegal:     {legalWidthInches, legalHeightInches},
+	PageSizeA3:        {a3WidthInches, a3HeightInches},
+	PageSizeA5:        {a5WidthInches, a5HeightInches},
+	PageSizeB5:        {b5WidthInches, b5HeightInches},
+	PageSizeExecutive: {executiveWidthInches, executiveHeightInches},
+	PageSizeTabloid:   {tabloidWidthInches, tabloidHeightInches},
 }
 
 // rodRenderer implements pdfRenderer using go-rod.
@@ -261,85 +301,254 @@ func renderOperationContext(ctx context.Context, fallbackTimeout time.Duration)
 
 // resolvePageDimensions returns width, height, margin, and bottom margin.
 // Applies defaults for nil/zero values, swaps for landscape, adds footer space.
+// margin is the uniform PageSettings.Margin; see pageMargins for per-side
+// margins.
 func resolvePageDimensions(page *PageSettings, hasFooter bool) (w, h, margin, bottomMargin float64) {
-	// Apply defaults
-	size := PageSizeLetter
-	orientation := OrientationPortrait
+	w, h = page.dimensions()
+
 	margin = DefaultMargin
+	if page != nil && page.Margin > 0 {
+		margin = page.Margin
+	}
 
-	if page != nil {
-		if page.Size != "" {
-			size = strings.ToLower(page.Size)
-		}
-		if page.Orientation != "" {
-			orientation = strings.ToLower(page.Orientation)
+	// Bottom margin: add extra space for footer
+	bottomMargin = margin
+	if hasFooter {
+		bottomMargin = margin + footerMarginExtra
+	}
+
+	return w, h, margin, bottomMargin
+}
+
+// dimensions returns the paper width and height in inches, after
+// orientation. Unknown sizes and lengths fall back to letter; Validate
+// reports them.
+func (p *PageSettings) dimensions() (w, h float64) {
+	size := PageSizeLetter
+	orientation := OrientationPortrait
+	if p != nil {
+		if p.Size != "" {
+			size = strings.ToLower(p.Size)
 		}
-		if page.Margin > 0 {
-			margin = page.Margin
+		if p.Orientation != "" {
+			orientation = strings.ToLower(p.Orientation)
 		}
 	}
 
-	// Get dimensions for size
 	dims, ok := pageDimensions[size]
 	if !ok {
-		dims = pageDimensions[PageSizeLetter] // fallback
+		dims = pageDimensions[PageSizeLetter]
 	}
 	w, h = dims.width, dims.height
+	if p != nil && p.Width != "" && p.Height != "" {
+		cw, werr := ParseLength(p.Width)
+		ch, herr := ParseLength(p.Height)
+		if werr == nil && herr == nil {
+			w, h = cw, ch
+		}
+	}
 
-	// Swap for landscape
 	if orientation == OrientationLandscape {
 		w, h = h, w
 	}
+	return w, h
+}
+
+// margins returns the top, right, bottom and left margins in inches, with
+// defaults applied. With Inner and Outer, left and right are those of a
+// right-hand page.
+func (p *PageSettings) margins() (top, right, bottom, left float64) {
+	margin := DefaultMargin
+	if p != nil && p.Margin > 0 {
+		margin = p.Margin
+	}
+	top, right, bottom, left = margin, margin, margin, margin
+	if p == nil || p.Margins == nil {
+		return top, right, bottom, left
+	}
 
-	// Bottom margin: add extra space for footer
-	bottomMargin = margin
-	if hasFooter {
-		bottomMargin = margin + footerMarginExtra
+	side := func(length string, fallback float64) float64 {
+		if length == "" {
+			return fallback
+		}
+		if inches, err := ParseLength(length); err == nil {
+			return inches
+		}
+		return fallback
+	}
+	m := p.Margins
+	top = side(m.Top, margin)
+	bottom = side(m.Bottom, margin)
+	left = side(m.Inner, side(m.Left, margin))
+	right = side(m.Outer, side(m.Right, margin))
+	return top, right, bottom, left
+}
+
+// pageMargins returns the top, right, bottom and left margins in inches,
+// with room for the header and footer.
+func pageMargins(opts *pdfOptions) (top, right, bottom, left float64) {
+	var page *PageSettings
+	if opts != nil {
+		page = opts.Page
 	}
+	top, right, bottom, left = page.margins()
 
-	return w, h, margin, bottomMargin
+	if opts.hasHeader() {
+		top += headerMarginExtra
+	}
+	if opts.hasFooter() {
+		bottom += footerMarginExtra
+	}
+	return top, right, bottom, left
+}
+
+// hasFooter reports whether a footer is printed.
+func (o *pdfOptions) hasFooter() bool {
+	return o != nil && (o.Footer != nil || o.FooterTemplate != "")
+}
+
+// hasHeader reports whether a header is printed.
+func (o *pdfOptions) hasHeader() bool {
+	return o != nil && (o.Header != nil || o.HeaderTemplate != "")
 }
 
-// buildPDFOptions constructs proto.PagePrintToPDF with page settings and optional footer.
+// buildPDFOptions constructs proto.PagePrintToPDF with page settings and optional header/footer.
 func (r *rodRenderer) buildPDFOptions(opts *pdfOptions) *proto.PagePrintToPDF {
-	hasFooter := opts != nil && opts.Footer != nil
+	if opts != nil && opts.Sheet {
+		return &proto.PagePrintToPDF{
+			PreferCSSPageSize: true,
+			MarginTop:         toFloatPtr(0),
+			MarginBottom:      toFloatPtr(0),
+			MarginLeft:        toFloatPtr(0),
+			MarginRight:       toFloatPtr(0),
+		}
+	}
+
+	hasFooter := opts.hasFooter()
+	hasHeader := opts.hasHeader()
 	var page *PageSettings
 	if opts != nil {
 		page = opts.Page
 	}
 
-	w, h, margin, bottomMargin := resolvePageDimensions(page, hasFooter)
+	w, h, _, _ := resolvePageDimensions(page, hasFooter)
+	topMargin, rightMargin, bottomMargin, leftMargin := pageMargins(opts)
 
 	pdfOpts := &proto.PagePrintToPDF{
 		PaperWidth:      toFloatPtr(w),
 		PaperHeight:     toFloatPtr(h),
-		MarginTop:       toFloatPtr(margin),
+		MarginTop:       toFloatPtr(topMargin),
 		MarginBottom:    toFloatPtr(bottomMargin),
-		MarginLeft:      toFloatPtr(margin),
-		MarginRight:     toFloatPtr(margin),
+		MarginLeft:      toFloatPtr(leftMargin),
+		MarginRight:     toFloatPtr(rightMargin),
 		PrintBackground: true,
 	}
+	if opts != nil && opts.CSSPageSize {
+		pdfOpts.PreferCSSPageSize = true
+	}
 
-	if hasFooter {
+	// Chrome's own outline supplies the heading destinations that
+	// post-processing rebuilds within the configured depth.
+	if opts != nil && len(opts.Outline) > 0 {
+		pdfOpts.GenerateTaggedPDF = true
+		pdfOpts.GenerateDocumentOutline = true
+	}
+
+	// Numbered footers and headers are stamped after printing; the margins
+	// stay reserved for them.
+	if opts != nil && opts.Numbering != nil {
+		return pdfOpts
+	}
+
+	if hasFooter || hasHeader {
 		pdfOpts.DisplayHeaderFooter = true
 		pdfOpts.HeaderTemplate = "<span></span>" // Empty header
+		pdfOpts.FooterTemplate = "<span></span>" // Empty footer
+	}
+	switch {
+	case hasHeader && opts.HeaderTemplate != "":
+		pdfOpts.HeaderTemplate = opts.HeaderTemplate
+	case hasHeader:
+		pdfOpts.HeaderTemplate = buildHeaderTemplate(opts.Header)
+	}
+	switch {
+	case hasFooter && opts.FooterTemplate != "":
+		pdfOpts.FooterTemplate = opts.FooterTemplate
+	case hasFooter:
 		pdfOpts.FooterTemplate = buildFooterTemplate(opts.Footer)
 	}
 
 	return pdfOpts
 }
 
+// Chrome fills elements with these classes when printing headers and footers.
+const (
+	chromePageNumber = `<span class="pageNumber"></span>`
+	chromeTotalPages = `<span class="totalPages"></span>`
+)
+
+// buildHeaderTemplate generates an HTML template for Chrome's native header.
+// Left, center and right slots share the page width; placeholders become
+// escaped values or Chrome's pageNumber/totalPages classes.
+func buildHeaderTemplate(data *pipeline.HeaderData) string {
+	return renderHeader(data, chromePageNumber, chromeTotalPages)
+}
+
+// renderHeader renders the header with pageNumber and totalPages as the
+// page placeholders, given as HTML.
+func renderHeader(data *pipeline.HeaderData, pageNumber, totalPages string) string {
+	if data == nil {
+		return "<span></span>"
+	}
+
+	slot := func(text, align string) string {
+		return fmt.Sprintf(`<span style="flex: 1; text-align: %s;">%s</span>`, align, renderHeaderSlot(text, data, pageNumber, totalPages))
+	}
+
+	return fmt.Sprintf(`<div style="font-size: %s; font-family: %s; color: %s; width: 100%%; display: flex; padding: 0 %s;">%s%s%s</div>`,
+		footerFontSize, defaultFontFamily, footerColor, footerPaddingH,
+		slot(data.Left, "left"), slot(data.Center, "center"), slot(data.Right, "right"))
+}
+
+// renderHeaderSlot escapes slot text and substitutes its placeholders.
+// Unknown placeholders are kept literally (Header.Validate rejects them earlier).
+func renderHeaderSlot(text string, data *pipeline.HeaderData, pageNumber, totalPages string) string {
+	return headerPlaceholderPattern.ReplaceAllStringFunc(html.EscapeString(text), func(m string) string {
+		switch m[1 : len(m)-1] {
+		case HeaderPlaceholderTitle:
+			return html.EscapeString(data.Title)
+		case HeaderPlaceholderVersion:
+			return html.EscapeString(data.Version)
+		case HeaderPlaceholderDocumentID:
+			return html.EscapeString(data.DocumentID)
+		case HeaderPlaceholderDate:
+			return html.EscapeString(data.Date)
+		case HeaderPlaceholderPageNumber:
+			return pageNumber
+		case HeaderPlaceholderTotalPages:
+			return totalPages
+		}
+		return m
+	})
+}
+
 // buildFooterTemplate generates an HTML template for Chrome's native footer.
 // Supports pageNumber, totalPages, date placeholders via CSS classes.
 func buildFooterTemplate(data *pipeline.FooterData) string {
+	return renderFooter(data, chromePageNumber+"/"+chromeTotalPages)
+}
+
+// renderFooter renders the footer with pageNumber, given as HTML, as its
+// page number part. An empty pageNumber leaves the part out.
+func renderFooter(data *pipeline.FooterData, pageNumber string) string {
 	if data == nil {
 		return "<span></span>"
 	}
 
 	var parts []string
 
-	if data.ShowPageNumber {
-		parts = append(parts, `<span class="pageNumber"></span>/<span class="totalPages"></span>`)
+	if data.ShowPageNumber && pageNumber != "" {
+		parts = append(parts, pageNumber)
 	}
 	if data.Date != "" {
 		parts = append(parts, html.EscapeString(data.Date))
@@ -399,7 +608,28 @@ func (c *rodConverter) ToPDF(ctx context.Context, htmlContent string, opts *pdfO
 	}
 	defer cleanup()
 
-	return c.renderer.RenderFromFile(ctx, tmpPath, opts)
+	pdfBytes, err := c.renderer.RenderFromFile(ctx, tmpPath, opts)
+	if err != nil {
+		return nil, err
+	}
+	renderSheet := func(sheet string) ([]byte, error) {
+		sheetPath, cleanupSheet, err := fileutil.WriteTempFile(sheet, "html")
+		if err != nil {
+			return nil, err
+		}
+		defer cleanupSheet()
+		return c.renderer.RenderFromFile(ctx, sheetPath, &pdfOptions{Sheet: true})
+	}
+	if pdfBytes, err = applyFootnotes(pdfBytes, opts, renderSheet); err != nil {
+		return nil, err
+	}
+	if pdfBytes, err = applyPageNumbering(pdfBytes, opts, renderSheet); err != nil {
+		return nil, err
+	}
+	if pdfBytes, err = applyPDFPostProcessors(ctx, pdfBytes, opts); err != nil {
+		return nil, err
+	}
+	return postProcessPDF(pdfBytes, opts)
 }
 
 // Close releases browser resources.
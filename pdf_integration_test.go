@@ -11,11 +11,15 @@ package picoloom
 import (
 	"bytes"
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/alnah/picoloom/v2/internal/assets"
+	"github.com/alnah/picoloom/v2/internal/pdfedit"
 	"github.com/alnah/picoloom/v2/internal/pipeline"
 )
 
@@ -118,6 +122,93 @@ func TestRodConverter_ToPDF_Integration(t *testing.T) {
 
 		assertValidPDF(t, data)
 	})
+
+	t.Run("with metadata", func(t *testing.T) {
+		t.Parallel()
+
+		html := `<!DOCTYPE html>
+<html>
+<head><title>Test</title></head>
+<body><h1>Document with Metadata</h1></body>
+</html>`
+
+		converter := newRodConverter(defaultTimeout)
+		opts := &pdfOptions{
+			Metadata: &pdfedit.Metadata{Title: "Chrome Metadata", Author: "Ada", Producer: producerName},
+		}
+		data, err := converter.ToPDF(ctx, html, opts)
+		if err != nil {
+			t.Fatalf("ToPDF() unexpected error: %v", err)
+		}
+
+		assertValidPDF(t, data)
+		doc, err := pdfedit.Open(data)
+		if err != nil {
+			t.Fatalf("pdfedit.Open() unexpected error: %v", err)
+		}
+		info, _, ok, err := doc.Info()
+		if err != nil || !ok {
+			t.Fatalf("Info() = ok %v, err %v, want info", ok, err)
+		}
+		if got, _ := info["Title"].(pdfedit.String); got.Text() != "Chrome Metadata" {
+			t.Errorf("Info /Title = %q, want %q", got.Text(), "Chrome Metadata")
+		}
+	})
+	t.Run("with PDF/A", func(t *testing.T) {
+		t.Parallel()
+
+		html := `<!DOCTYPE html>
+<html>
+<head><title>Test</title></head>
+<body><h1>Archived Document</h1><p style="opacity:0.5"><a href="#x">Link</a></p></body>
+</html>`
+
+		converter := newRodConverter(defaultTimeout)
+		data, err := converter.ToPDF(ctx, html, &pdfOptions{PDFA: true})
+		if err != nil {
+			t.Fatalf("ToPDF() unexpected error: %v", err)
+		}
+
+		assertValidPDF(t, data)
+		doc, err := pdfedit.Open(data)
+		if err != nil {
+			t.Fatalf("pdfedit.Open() unexpected error: %v", err)
+		}
+		catalog, _, err := doc.Catalog()
+		if err != nil {
+			t.Fatalf("Catalog() unexpected error: %v", err)
+		}
+		if _, ok := catalog["OutputIntents"]; !ok {
+			t.Error("catalog missing /OutputIntents")
+		}
+	})
+
+	t.Run("with encryption", func(t *testing.T) {
+		t.Parallel()
+
+		html := `<!DOCTYPE html>
+<html>
+<head><title>Confidential</title></head>
+<body><h1>Confidential</h1></body>
+</html>`
+
+		converter := newRodConverter(defaultTimeout)
+		data, err := converter.ToPDF(ctx, html, &pdfOptions{
+			Encryption: &pdfedit.Encryption{UserPassword: "secret", Permissions: pdfedit.PermPrint},
+		})
+		if err != nil {
+			t.Fatalf("ToPDF() unexpected error: %v", err)
+		}
+
+		assertValidPDF(t, data)
+		doc, err := pdfedit.Open(data)
+		if err != nil {
+			t.Fatalf("pdfedit.Open() unexpected error: %v", err)
+		}
+		if _, ok := doc.Trailer()["Encrypt"]; !ok {
+			t.Error("trailer missing /Encrypt")
+		}
+	})
 }
 
 // ---------------------------------------------------------------------------
@@ -208,6 +299,27 @@ func TestService_Convert_Integration(t *testing.T) {
 		assertValidPDF(t, data.PDF)
 	})
 
+	t.Run("with TOC page numbers", func(t *testing.T) {
+		t.Parallel()
+
+		service := acquireService(t)
+		input := Input{
+			Markdown:   "# Guide\n\n## Intro\n\nText\n\n## Usage\n\nText\n",
+			TOC:        &TOC{PageNumbers: true},
+			PageBreaks: &PageBreaks{BeforeH2: true},
+		}
+
+		data, err := service.Convert(ctx, input)
+		if err != nil {
+			t.Fatalf("Convert() unexpected error: %v", err)
+		}
+
+		assertValidPDF(t, data.PDF)
+		if !strings.Contains(string(data.HTML), `<span class="toc-page">3</span>`) {
+			t.Errorf("HTML missing TOC page 3 for Usage:\n%s", data.HTML)
+		}
+	})
+
 	t.Run("write to file", func(t *testing.T) {
 		t.Parallel()
 
@@ -299,3 +411,74 @@ func TestRodRenderer_RenderFromFile_ContextDeadlineExceeded(t *testing.T) {
 		t.Errorf("RenderFromFile() error = %v, want context.DeadlineExceeded", err)
 	}
 }
+
+// ---------------------------------------------------------------------------
+// TestRodRenderer_RenderMermaid_Integration - Embedded Mermaid bundle
+// ---------------------------------------------------------------------------
+
+func TestRodRenderer_RenderMermaid_Integration(t *testing.T) {
+	t.Parallel()
+
+	if _, err := assets.MermaidScript(); errors.Is(err, assets.ErrScriptNotFound) {
+		t.Skip("mermaid bundle not embedded (built without -tags bundles)")
+	}
+
+	renderer := newRodRenderer(testTimeout)
+	defer renderer.Close()
+
+	t.Run("renders SVG", func(t *testing.T) {
+		svgs, err := renderer.RenderMermaid(context.Background(), []string{"graph TD\n  A --> B", "pie\n  \"a\" : 1"}, DiagramThemeNeutral)
+		if err != nil {
+			t.Fatalf("RenderMermaid() unexpected error: %v", err)
+		}
+		if len(svgs) != 2 {
+			t.Fatalf("RenderMermaid() returned %d SVGs, want 2", len(svgs))
+		}
+		for i, svg := range svgs {
+			if !strings.HasPrefix(svg, "<svg") {
+				t.Errorf("RenderMermaid() svg %d = %.40q, want <svg prefix", i, svg)
+			}
+		}
+	})
+
+	t.Run("reports the rejected definition", func(t *testing.T) {
+		_, err := renderer.RenderMermaid(context.Background(), []string{"graph TD\n  A --> B", "not a diagram"}, "")
+		var mermaidErr *pipeline.MermaidError
+		if !errors.As(err, &mermaidErr) || mermaidErr.Index != 1 {
+			t.Errorf("RenderMermaid() error = %v, want MermaidError for index 1", err)
+		}
+	})
+}
+
+// ---------------------------------------------------------------------------
+// TestRodRenderer_RenderMath_Integration - Embedded KaTeX bundle
+// ---------------------------------------------------------------------------
+
+func TestRodRenderer_RenderMath_Integration(t *testing.T) {
+	t.Parallel()
+
+	if _, err := assets.KatexScript(); errors.Is(err, assets.ErrScriptNotFound) {
+		t.Skip("katex bundle not embedded (built without -tags bundles)")
+	}
+
+	renderer := newRodRenderer(testTimeout)
+	defer renderer.Close()
+
+	t.Run("renders inline and numbered display math", func(t *testing.T) {
+		got, err := renderer.RenderMath(context.Background(), []string{"E=mc^2", `\int_0^1 x\,dx \tag{1}`}, []bool{false, true})
+		if err != nil {
+			t.Fatalf("RenderMath() unexpected error: %v", err)
+		}
+		if len(got) != 2 || !strings.Contains(got[0], `class="katex"`) || !strings.Contains(got[1], "katex-display") {
+			t.Errorf("RenderMath() = %q, want KaTeX markup", got)
+		}
+	})
+
+	t.Run("reports the rejected expression", func(t *testing.T) {
+		_, err := renderer.RenderMath(context.Background(), []string{"x", `\frac{1`}, []bool{false, false})
+		var mathErr *pipeline.MathError
+		if !errors.As(err, &mathErr) || mathErr.Index != 1 {
+			t.Errorf("RenderMath() error = %v, want MathError for index 1", err)
+		}
+	})
+}
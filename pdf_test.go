@@ -9,6 +9,7 @@ package picoloom
 import (
 	"context"
 	"errors"
+	"math"
 	"strings"
 	"testing"
 	"time"
@@ -502,9 +503,36 @@ func TestResolvePageDimensions(t *testing.T) {
 			wantMargin:       0.5,
 			wantBottomMargin: 0.5,
 		},
+		{
+			name:             "a5 portrait",
+			page:             &PageSettings{Size: "a5", Orientation: "portrait", Margin: 0.5},
+			hasFooter:        false,
+			wantW:            5.83,
+			wantH:            8.27,
+			wantMargin:       0.5,
+			wantBottomMargin: 0.5,
+		},
+		{
+			name:             "custom size replaces preset",
+			page:             &PageSettings{Size: "a4", Width: "6in", Height: "9in"},
+			hasFooter:        false,
+			wantW:            6,
+			wantH:            9,
+			wantMargin:       DefaultMargin,
+			wantBottomMargin: DefaultMargin,
+		},
+		{
+			name:             "custom size landscape",
+			page:             &PageSettings{Width: "6in", Height: "9in", Orientation: "landscape"},
+			hasFooter:        false,
+			wantW:            9,
+			wantH:            6,
+			wantMargin:       DefaultMargin,
+			wantBottomMargin: DefaultMargin,
+		},
 		{
 			name:             "unknown size falls back to letter",
-			page:             &PageSettings{Size: "tabloid", Orientation: "portrait", Margin: 0.5},
+			page:             &PageSettings{Size: "folio", Orientation: "portrait", Margin: 0.5},
 			hasFooter:        false,
 			wantW:            8.5,
 			wantH:            11.0,
@@ -562,6 +590,61 @@ func TestResolvePageDimensions(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// TestPageMargins - Per-Side Margins
+// ---------------------------------------------------------------------------
+
+func TestPageMargins(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		opts *pdfOptions
+		want [4]float64 // top, right, bottom, left
+	}{
+		{"nil opts uses default", nil, [4]float64{0.5, 0.5, 0.5, 0.5}},
+		{
+			"uniform margin with header and footer",
+			&pdfOptions{
+				Page:   &PageSettings{Margin: 1},
+				Header: &pipeline.HeaderData{},
+				Footer: &pipeline.FooterData{},
+			},
+			[4]float64{1 + headerMarginExtra, 1, 1 + footerMarginExtra, 1},
+		},
+		{
+			"per-side margins override margin",
+			&pdfOptions{Page: &PageSettings{Margin: 1, Margins: &Margins{Top: "0.5in", Left: "72pt"}}},
+			[4]float64{0.5, 1, 1, 1},
+		},
+		{
+			"metric margins",
+			&pdfOptions{Page: &PageSettings{Margins: &Margins{Right: "25.4mm", Bottom: "5.08cm"}}},
+			[4]float64{0.5, 1, 2, 0.5},
+		},
+		{
+			"inner and outer use right-hand page sides",
+			&pdfOptions{Page: &PageSettings{Margins: &Margins{Inner: "1.5in", Outer: "0.75in"}}},
+			[4]float64{0.5, 0.75, 0.5, 1.5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			top, right, bottom, left := pageMargins(tt.opts)
+			got := [4]float64{top, right, bottom, left}
+			for i := range got {
+				if math.Abs(got[i]-tt.want[i]) > 1e-9 {
+					t.Errorf("pageMargins() = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
 // ---------------------------------------------------------------------------
 // TestRodRenderer_Close_Idempotent - Close Idempotency
 // ---------------------------------------------------------------------------
@@ -661,6 +744,36 @@ func TestBuildPDFOptions(t *testing.T) {
 		}
 	})
 
+	t.Run("css page size prefers @page sizes", func(t *testing.T) {
+		t.Parallel()
+
+		pdfOpts := renderer.buildPDFOptions(&pdfOptions{CSSPageSize: true})
+		if !pdfOpts.PreferCSSPageSize {
+			t.Error("buildPDFOptions(opts).PreferCSSPageSize = false, want true")
+		}
+		if *pdfOpts.PaperWidth != letterWidthInches {
+			t.Errorf("buildPDFOptions(opts).PaperWidth = %v, want %v fallback", *pdfOpts.PaperWidth, letterWidthInches)
+		}
+		if renderer.buildPDFOptions(nil).PreferCSSPageSize {
+			t.Error("buildPDFOptions(nil).PreferCSSPageSize = true, want false")
+		}
+	})
+
+	t.Run("with per-side margins sets each side", func(t *testing.T) {
+		t.Parallel()
+
+		opts := &pdfOptions{
+			Page: &PageSettings{Width: "6in", Height: "9in", Margins: &Margins{Top: "1in", Right: "0.75in", Bottom: "1.25in", Left: "1.5in"}},
+		}
+		pdfOpts := renderer.buildPDFOptions(opts)
+
+		got := [6]float64{*pdfOpts.PaperWidth, *pdfOpts.PaperHeight, *pdfOpts.MarginTop, *pdfOpts.MarginRight, *pdfOpts.MarginBottom, *pdfOpts.MarginLeft}
+		want := [6]float64{6, 9, 1, 0.75, 1.25, 1.5}
+		if got != want {
+			t.Errorf("buildPDFOptions(opts) paper and margins = %v, want %v", got, want)
+		}
+	})
+
 	t.Run("with page settings and footer", func(t *testing.T) {
 		t.Parallel()
 
@@ -681,6 +794,159 @@ func TestBuildPDFOptions(t *testing.T) {
 			t.Errorf("buildPDFOptions(opts).DisplayHeaderFooter = false, want true")
 		}
 	})
+
+	t.Run("with header increases top margin only", func(t *testing.T) {
+		t.Parallel()
+
+		opts := &pdfOptions{Header: &pipeline.HeaderData{Left: "{title}", Title: "Spec"}}
+		pdfOpts := renderer.buildPDFOptions(opts)
+
+		expectedTop := DefaultMargin + headerMarginExtra
+		if *pdfOpts.MarginTop != expectedTop {
+			t.Errorf("buildPDFOptions(opts).MarginTop = %v, want %v", *pdfOpts.MarginTop, expectedTop)
+		}
+		if *pdfOpts.MarginBottom != DefaultMargin {
+			t.Errorf("buildPDFOptions(opts).MarginBottom = %v, want %v", *pdfOpts.MarginBottom, DefaultMargin)
+		}
+		if !pdfOpts.DisplayHeaderFooter {
+			t.Errorf("buildPDFOptions(opts).DisplayHeaderFooter = false, want true")
+		}
+		if !strings.Contains(pdfOpts.HeaderTemplate, "Spec") {
+			t.Errorf("buildPDFOptions(opts).HeaderTemplate = %q, want title", pdfOpts.HeaderTemplate)
+		}
+		if pdfOpts.FooterTemplate != "<span></span>" {
+			t.Errorf("buildPDFOptions(opts).FooterTemplate = %q, want empty span", pdfOpts.FooterTemplate)
+		}
+	})
+
+	t.Run("outline enables Chrome document outline", func(t *testing.T) {
+		t.Parallel()
+
+		opts := &pdfOptions{Outline: []pipeline.Heading{{Level: 1, ID: "intro", Text: "Intro"}}}
+		pdfOpts := renderer.buildPDFOptions(opts)
+
+		if !pdfOpts.GenerateDocumentOutline || !pdfOpts.GenerateTaggedPDF {
+			t.Errorf("buildPDFOptions(opts) GenerateDocumentOutline = %v, GenerateTaggedPDF = %v, want true",
+				pdfOpts.GenerateDocumentOutline, pdfOpts.GenerateTaggedPDF)
+		}
+		if renderer.buildPDFOptions(nil).GenerateDocumentOutline {
+			t.Error("buildPDFOptions(nil).GenerateDocumentOutline = true, want false")
+		}
+	})
+
+	t.Run("custom footer template replaces generated footer", func(t *testing.T) {
+		t.Parallel()
+
+		custom := `<div>Page <span class="pageNumber"></span></div>`
+		opts := &pdfOptions{
+			Footer:         &pipeline.FooterData{ShowPageNumber: true},
+			FooterTemplate: custom,
+		}
+		pdfOpts := renderer.buildPDFOptions(opts)
+
+		if pdfOpts.FooterTemplate != custom {
+			t.Errorf("buildPDFOptions(opts).FooterTemplate = %q, want %q", pdfOpts.FooterTemplate, custom)
+		}
+		expectedBottom := DefaultMargin + footerMarginExtra
+		if *pdfOpts.MarginBottom != expectedBottom {
+			t.Errorf("buildPDFOptions(opts).MarginBottom = %v, want %v", *pdfOpts.MarginBottom, expectedBottom)
+		}
+	})
+
+	t.Run("numbering keeps margins without Chrome footer", func(t *testing.T) {
+		t.Parallel()
+
+		opts := &pdfOptions{
+			Footer:    &pipeline.FooterData{ShowPageNumber: true},
+			Numbering: &PageNumbering{},
+		}
+		pdfOpts := renderer.buildPDFOptions(opts)
+
+		if pdfOpts.DisplayHeaderFooter {
+			t.Error("buildPDFOptions(opts).DisplayHeaderFooter = true, want false (footer is stamped)")
+		}
+		expectedBottom := DefaultMargin + footerMarginExtra
+		if *pdfOpts.MarginBottom != expectedBottom {
+			t.Errorf("buildPDFOptions(opts).MarginBottom = %v, want %v", *pdfOpts.MarginBottom, expectedBottom)
+		}
+	})
+
+	t.Run("stamp sheet prints edge to edge at CSS page size", func(t *testing.T) {
+		t.Parallel()
+
+		pdfOpts := renderer.buildPDFOptions(&pdfOptions{Sheet: true})
+
+		if !pdfOpts.PreferCSSPageSize || pdfOpts.PrintBackground || pdfOpts.DisplayHeaderFooter {
+			t.Errorf("buildPDFOptions(sheet) = %+v, want CSS page size, no background, no header/footer", pdfOpts)
+		}
+		for _, m := range []*float64{pdfOpts.MarginTop, pdfOpts.MarginBottom, pdfOpts.MarginLeft, pdfOpts.MarginRight} {
+			if m == nil || *m != 0 {
+				t.Fatalf("buildPDFOptions(sheet) margins not zero: %+v", pdfOpts)
+			}
+		}
+	})
+}
+
+// ---------------------------------------------------------------------------
+// TestBuildHeaderTemplate - Chrome Header Template Generation
+// ---------------------------------------------------------------------------
+
+func TestBuildHeaderTemplate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		data     *pipeline.HeaderData
+		wantPart []string
+		wantNot  string
+	}{
+		{
+			name:     "nil data returns empty span",
+			data:     nil,
+			wantPart: []string{"<span></span>"},
+		},
+		{
+			name:     "slots keep their alignment",
+			data:     &pipeline.HeaderData{Left: "L", Center: "C", Right: "R"},
+			wantPart: []string{`text-align: left;">L<`, `text-align: center;">C<`, `text-align: right;">R<`},
+		},
+		{
+			name: "metadata placeholders",
+			data: &pipeline.HeaderData{
+				Left:  "{title} v{version}",
+				Right: "{documentID} {date}",
+				Title: "Spec", Version: "2.1", DocumentID: "DOC-1", Date: "2025-01-15",
+			},
+			wantPart: []string{"Spec v2.1", "DOC-1 2025-01-15"},
+		},
+		{
+			name:     "page number placeholders",
+			data:     &pipeline.HeaderData{Right: "Page {pageNumber} of {totalPages}"},
+			wantPart: []string{`Page <span class="pageNumber"></span> of <span class="totalPages"></span>`},
+		},
+		{
+			name:     "free text and values are escaped",
+			data:     &pipeline.HeaderData{Left: "<b>{title}</b>", Title: "A & B"},
+			wantPart: []string{"&lt;b&gt;A &amp; B&lt;/b&gt;"},
+			wantNot:  "<b>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := buildHeaderTemplate(tt.data)
+			for _, want := range tt.wantPart {
+				if !strings.Contains(got, want) {
+					t.Errorf("buildHeaderTemplate() = %q, want to contain %q", got, want)
+				}
+			}
+			if tt.wantNot != "" && strings.Contains(got, tt.wantNot) {
+				t.Errorf("buildHeaderTemplate() = %q, should not contain %q", got, tt.wantNot)
+			}
+		})
+	}
 }
 
 // ---------------------------------------------------------------------------
@@ -690,7 +956,10 @@ func TestBuildPDFOptions(t *testing.T) {
 func TestPageDimensions_AllSizesPresent(t *testing.T) {
 	t.Parallel()
 
-	requiredSizes := []string{PageSizeLetter, PageSizeA4, PageSizeLegal}
+	requiredSizes := []string{
+		PageSizeLetter, PageSizeA4, PageSizeLegal, PageSizeA3,
+		PageSizeA5, PageSizeB5, PageSizeExecutive, PageSizeTabloid,
+	}
 
 	for _, size := range requiredSizes {
 		if _, ok := pageDimensions[size]; !ok {
@@ -0,0 +1,147 @@
+package picoloom
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/alnah/picoloom/v2/internal/pdfedit"
+	"github.com/alnah/picoloom/v2/internal/pipeline"
+)
+
+// producerName identifies picoloom as Creator and Producer in PDF metadata.
+const producerName = "picoloom"
+
+// postProcessPDF applies the edits Chrome cannot make while printing, as one
+// incremental update appended to the rendered PDF. Encryption comes last and
+// rewrites the whole file.
+// Returns data unchanged if opts requests no edits.
+func postProcessPDF(data []byte, opts *pdfOptions) ([]byte, error) {
+	if opts == nil || (opts.Metadata == nil && len(opts.Outline) == 0 && !opts.PDFA && opts.Encryption == nil) {
+		return data, nil
+	}
+
+	doc, err := pdfedit.Open(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrPDFPostProcess, err)
+	}
+	update := doc.NewUpdate()
+	metadata := opts.Metadata
+	if opts.PDFA {
+		m := pdfedit.Metadata{}
+		if metadata != nil {
+			m = *metadata
+		}
+		m.PDFA = true
+		metadata = &m
+	}
+	if metadata != nil {
+		if err := pdfedit.SetMetadata(update, *metadata); err != nil {
+			return nil, fmt.Errorf("%w: metadata: %w", ErrPDFPostProcess, err)
+		}
+	}
+	if len(opts.Outline) > 0 {
+		if err := setOutline(update, opts.Outline); err != nil {
+			return nil, fmt.Errorf("%w: outline: %w", ErrPDFPostProcess, err)
+		}
+	}
+	if opts.PDFA {
+		if err := pdfedit.SetPDFA(update); errors.Is(err, pdfedit.ErrPDFAViolation) {
+			return nil, fmt.Errorf("%w: %w", ErrPDFAConformance, err)
+		} else if err != nil {
+			return nil, fmt.Errorf("%w: PDF/A: %w", ErrPDFPostProcess, err)
+		}
+	}
+	if opts.Encryption != nil {
+		out, err := pdfedit.Encrypt(update.Bytes(), *opts.Encryption)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrPDFEncryption, err)
+		}
+		return out, nil
+	}
+	return update.Bytes(), nil
+}
+
+// setOutline bookmarks headings at the destinations Chrome wrote for them:
+// the named destination matching the heading ID when the heading is a link
+// target (e.g. from the TOC), otherwise the next item of Chrome's outline
+// with the same text. Headings found in neither are left out.
+func setOutline(u *pdfedit.Update, headings []pipeline.Heading) error {
+	doc := u.Document()
+	named, err := doc.NamedDestinations()
+	if err != nil {
+		return err
+	}
+	items, err := doc.OutlineItems()
+	if err != nil {
+		return err
+	}
+
+	entries := make([]pdfedit.OutlineEntry, 0, len(headings))
+	next := 0
+	for _, h := range headings {
+		dest, ok := named[h.ID]
+		if !ok {
+			text := normalizeSpace(h.Text)
+			for i := next; i < len(items); i++ {
+				if items[i].Dest != nil && normalizeSpace(items[i].Title) == text {
+					dest, ok = items[i].Dest, true
+					next = i + 1
+					break
+				}
+			}
+		}
+		if !ok {
+			continue
+		}
+		entries = append(entries, pdfedit.OutlineEntry{Title: h.Text, Level: h.Level, Dest: dest})
+	}
+	return pdfedit.SetOutline(u, entries)
+}
+
+// normalizeSpace collapses runs of whitespace for text comparison.
+func normalizeSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// destinationPages maps each named destination to the 1-based page it targets.
+// Chrome writes a named destination for every internal link target, so this
+// covers all headings linked from the TOC and all index terms. The cover
+// counts as page 1, as in the page numbers Chrome prints in footers. With
+// numbering, pages are renumbered as printed and front matter pages are left
+// out.
+func destinationPages(data []byte, numbering *PageNumbering) (map[string]int, error) {
+	doc, err := pdfedit.Open(data)
+	if err != nil {
+		return nil, err
+	}
+	pageRefs, err := doc.Pages()
+	if err != nil {
+		return nil, err
+	}
+	named, err := doc.NamedDestinations()
+	if err != nil {
+		return nil, err
+	}
+
+	var numberer *pageNumberer
+	if numbering != nil {
+		front, err := frontMatterPages(doc, pageRefs)
+		if err != nil {
+			return nil, err
+		}
+		numberer = newPageNumberer(numbering, front, len(pageRefs))
+	}
+
+	pages := make(map[string]int, len(named))
+	for name, dest := range named {
+		page := pdfedit.PageNumber(dest, pageRefs)
+		if page > 0 && numberer != nil {
+			page = numberer.number(page - 1)
+		}
+		if page > 0 {
+			pages[name] = page
+		}
+	}
+	return pages, nil
+}
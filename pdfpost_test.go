@@ -0,0 +1,304 @@
+package picoloom
+
+// Notes:
+// - Tests postProcessPDF on minimal PDFs shaped like Chrome's output
+// - The incremental update is read back with internal/pdfedit
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alnah/picoloom/v2/internal/pdfedit"
+	"github.com/alnah/picoloom/v2/internal/pipeline"
+)
+
+// buildTestPDF assembles a PDF whose objects are numbered from 1 in order,
+// with a classic xref table as Chrome writes it. Object 1 is the catalog.
+func buildTestPDF(trailerExtra string, objects ...string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<</Size %d /Root 1 0 R%s>>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, trailerExtra, xref)
+	return buf.Bytes()
+}
+
+// chromeLikePDF returns a one-page PDF with an /Info dictionary titled
+// "Document", as Chrome writes it.
+func chromeLikePDF() []byte {
+	return buildTestPDF(" /Info 4 0 R",
+		"<</Type /Catalog /Pages 2 0 R>>",
+		"<</Type /Pages /Count 1 /Kids [3 0 R]>>",
+		"<</Type /Page /Parent 2 0 R /MediaBox [0 0 612 792]>>",
+		"<</Title (Document) /Creator (Chromium) /Producer (Skia/PDF m120) /CreationDate (D:20250115120000+00'00')>>",
+	)
+}
+
+// chromeOutlinePDF returns a two-page PDF with a named destination for
+// "setup" (a TOC link target) and Chrome's outline of every heading,
+// including the cover title.
+func chromeOutlinePDF() []byte {
+	return buildTestPDF("",
+		"<</Type /Catalog /Pages 2 0 R /Dests <</setup [4 0 R /XYZ 0 400 0]>> /Outlines 5 0 R>>",
+		"<</Type /Pages /Count 2 /Kids [3 0 R 4 0 R]>>",
+		"<</Type /Page /Parent 2 0 R /MediaBox [0 0 612 792]>>",
+		"<</Type /Page /Parent 2 0 R /MediaBox [0 0 612 792]>>",
+		"<</Type /Outlines /First 6 0 R /Last 7 0 R /Count 3>>",
+		"<</Title (Cover Title) /Parent 5 0 R /Next 7 0 R /Dest [3 0 R /XYZ 0 792 0]>>",
+		"<</Title (Guide) /Parent 5 0 R /Prev 6 0 R /First 8 0 R /Last 8 0 R /Dest [3 0 R /XYZ 0 600 0]>>",
+		"<</Title (  Install   steps ) /Parent 7 0 R /Dest [4 0 R /XYZ 0 700 0]>>",
+	)
+}
+
+// ---------------------------------------------------------------------------
+// TestPostProcessPDF - Metadata update
+// ---------------------------------------------------------------------------
+
+func TestPostProcessPDF(t *testing.T) {
+	t.Parallel()
+
+	t.Run("writes info and XMP", func(t *testing.T) {
+		t.Parallel()
+
+		opts := &pdfOptions{Metadata: &pdfedit.Metadata{
+			Title:    "Release Notes",
+			Author:   "Ada",
+			Keywords: []string{"go", "pdf"},
+			Producer: producerName,
+			ModDate:  time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC),
+		}}
+		got, err := postProcessPDF(chromeLikePDF(), opts)
+		if err != nil {
+			t.Fatalf("postProcessPDF() unexpected error: %v", err)
+		}
+
+		doc, err := pdfedit.Open(got)
+		if err != nil {
+			t.Fatalf("pdfedit.Open() unexpected error: %v", err)
+		}
+		info, _, ok, err := doc.Info()
+		if err != nil || !ok {
+			t.Fatalf("Info() = ok %v, err %v, want info", ok, err)
+		}
+		wantInfo := map[pdfedit.Name]string{
+			"Title":        "Release Notes",
+			"Author":       "Ada",
+			"Keywords":     "go, pdf",
+			"Producer":     producerName,
+			"CreationDate": "D:20250115120000+00'00'",
+		}
+		for key, want := range wantInfo {
+			if s, _ := info[key].(pdfedit.String); s.Text() != want {
+				t.Errorf("Info /%s = %q, want %q", key, s.Text(), want)
+			}
+		}
+
+		catalog, _, err := doc.Catalog()
+		if err != nil {
+			t.Fatalf("Catalog() unexpected error: %v", err)
+		}
+		obj, err := doc.Resolve(catalog["Metadata"])
+		if err != nil {
+			t.Fatalf("Resolve(/Metadata) unexpected error: %v", err)
+		}
+		stream, ok := obj.(*pdfedit.Stream)
+		if !ok || !strings.Contains(string(stream.Data), "Release Notes") {
+			t.Errorf("catalog /Metadata = %#v, want XMP stream with title", obj)
+		}
+	})
+
+	t.Run("nil metadata returns input unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		in := []byte("%PDF-1.4 mock")
+		got, err := postProcessPDF(in, &pdfOptions{})
+		if err != nil {
+			t.Fatalf("postProcessPDF() unexpected error: %v", err)
+		}
+		if !bytes.Equal(got, in) {
+			t.Errorf("postProcessPDF() = %q, want input unchanged", got)
+		}
+	})
+
+	t.Run("invalid PDF returns ErrPDFPostProcess", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := postProcessPDF([]byte("%PDF-1.4 mock"), &pdfOptions{Metadata: &pdfedit.Metadata{Title: "x"}})
+		if !errors.Is(err, ErrPDFPostProcess) {
+			t.Errorf("postProcessPDF() error = %v, want ErrPDFPostProcess", err)
+		}
+	})
+
+	t.Run("PDF/A adds output intent and identification", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := postProcessPDF(chromeLikePDF(), &pdfOptions{PDFA: true})
+		if err != nil {
+			t.Fatalf("postProcessPDF() unexpected error: %v", err)
+		}
+		doc, err := pdfedit.Open(got)
+		if err != nil {
+			t.Fatalf("pdfedit.Open() unexpected error: %v", err)
+		}
+		catalog, _, err := doc.Catalog()
+		if err != nil {
+			t.Fatalf("Catalog() unexpected error: %v", err)
+		}
+		if _, ok := catalog["OutputIntents"]; !ok {
+			t.Error("catalog missing /OutputIntents")
+		}
+		obj, err := doc.Resolve(catalog["Metadata"])
+		if err != nil {
+			t.Fatalf("Resolve(/Metadata) unexpected error: %v", err)
+		}
+		if stream, ok := obj.(*pdfedit.Stream); !ok || !strings.Contains(string(stream.Data), "<pdfaid:part>2</pdfaid:part>") {
+			t.Errorf("catalog /Metadata = %#v, want XMP with PDF/A identification", obj)
+		}
+	})
+
+	t.Run("PDF/A violation returns ErrPDFAConformance", func(t *testing.T) {
+		t.Parallel()
+
+		data := buildTestPDF("",
+			"<</Type /Catalog /Pages 2 0 R>>",
+			"<</Type /Pages /Count 1 /Kids [3 0 R]>>",
+			"<</Type /Page /Parent 2 0 R /Resources <</Font <</F1 <</Type /Font /Subtype /Type1 /BaseFont /Helvetica>>>>>>>>",
+		)
+		_, err := postProcessPDF(data, &pdfOptions{PDFA: true})
+		if !errors.Is(err, ErrPDFAConformance) {
+			t.Errorf("postProcessPDF() error = %v, want ErrPDFAConformance", err)
+		}
+	})
+
+	t.Run("encryption rewrites the file with metadata", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := postProcessPDF(chromeLikePDF(), &pdfOptions{
+			Metadata:   &pdfedit.Metadata{Title: "Secret"},
+			Encryption: &pdfedit.Encryption{UserPassword: "pw"},
+		})
+		if err != nil {
+			t.Fatalf("postProcessPDF() unexpected error: %v", err)
+		}
+		if bytes.Count(got, []byte("%%EOF")) != 1 {
+			t.Error("postProcessPDF() kept the unencrypted revision")
+		}
+		doc, err := pdfedit.Open(got)
+		if err != nil {
+			t.Fatalf("pdfedit.Open() unexpected error: %v", err)
+		}
+		if _, ok := doc.Trailer()["Encrypt"]; !ok {
+			t.Error("trailer missing /Encrypt")
+		}
+		if bytes.Contains(got, []byte("(Secret)")) {
+			t.Error("title written in clear text")
+		}
+	})
+
+	t.Run("encryption failure returns ErrPDFEncryption", func(t *testing.T) {
+		t.Parallel()
+
+		data := buildTestPDF(" /Encrypt 2 0 R", "<</Type /Catalog>>", "<</Filter /Standard>>")
+		_, err := postProcessPDF(data, &pdfOptions{Encryption: &pdfedit.Encryption{UserPassword: "pw"}})
+		if !errors.Is(err, ErrPDFEncryption) {
+			t.Errorf("postProcessPDF() error = %v, want ErrPDFEncryption", err)
+		}
+	})
+
+	t.Run("rebuilds outline from headings", func(t *testing.T) {
+		t.Parallel()
+
+		opts := &pdfOptions{Outline: []pipeline.Heading{
+			{Level: 1, ID: "guide", Text: "Guide"},
+			{Level: 2, ID: "install-steps", Text: "Install steps"},
+			{Level: 2, ID: "setup", Text: "Setup"},
+			{Level: 2, ID: "missing", Text: "Not rendered"},
+		}}
+		got, err := postProcessPDF(chromeOutlinePDF(), opts)
+		if err != nil {
+			t.Fatalf("postProcessPDF() unexpected error: %v", err)
+		}
+
+		doc, err := pdfedit.Open(got)
+		if err != nil {
+			t.Fatalf("pdfedit.Open() unexpected error: %v", err)
+		}
+		items, err := doc.OutlineItems()
+		if err != nil {
+			t.Fatalf("OutlineItems() unexpected error: %v", err)
+		}
+		var titles []string
+		for _, item := range items {
+			titles = append(titles, fmt.Sprintf("%d:%s@%v", item.Level, item.Title, item.Dest.(pdfedit.Array)[0]))
+		}
+		want := "[1:Guide@{3 0} 2:Install steps@{4 0} 2:Setup@{4 0}]"
+		if fmt.Sprint(titles) != want {
+			t.Errorf("outline = %v, want %s", titles, want)
+		}
+	})
+}
+
+// ---------------------------------------------------------------------------
+// TestDestinationPages - Named destinations to page numbers
+// ---------------------------------------------------------------------------
+
+func TestDestinationPages(t *testing.T) {
+	t.Parallel()
+
+	t.Run("maps destinations to pages", func(t *testing.T) {
+		t.Parallel()
+
+		pages, err := destinationPages(buildTestPDF("",
+			"<</Type /Catalog /Pages 2 0 R /Dests <</intro [3 0 R /XYZ 0 792 0] /setup [4 0 R /XYZ 0 400 0] /gone [9 0 R /Fit]>>>>",
+			"<</Type /Pages /Count 2 /Kids [3 0 R 4 0 R]>>",
+			"<</Type /Page /Parent 2 0 R /MediaBox [0 0 612 792]>>",
+			"<</Type /Page /Parent 2 0 R /MediaBox [0 0 612 792]>>",
+		), nil)
+		if err != nil {
+			t.Fatalf("destinationPages() unexpected error: %v", err)
+		}
+		want := map[string]int{"intro": 1, "setup": 2}
+		if fmt.Sprint(pages) != fmt.Sprint(want) {
+			t.Errorf("destinationPages() = %v, want %v", pages, want)
+		}
+	})
+
+	t.Run("numbers pages after front matter", func(t *testing.T) {
+		t.Parallel()
+
+		pages, err := destinationPages(buildTestPDF("",
+			"<</Type /Catalog /Pages 2 0 R /Dests <</toc-title [3 0 R /Fit] /"+pipeline.ContentStartID+" [4 0 R /Fit] /setup [5 0 R /XYZ 0 400 0]>>>>",
+			"<</Type /Pages /Count 3 /Kids [3 0 R 4 0 R 5 0 R] /MediaBox [0 0 612 792]>>",
+			"<</Type /Page /Parent 2 0 R>>",
+			"<</Type /Page /Parent 2 0 R>>",
+			"<</Type /Page /Parent 2 0 R>>",
+		), &PageNumbering{FrontMatter: FrontMatterRoman, StartAt: 10})
+		if err != nil {
+			t.Fatalf("destinationPages() unexpected error: %v", err)
+		}
+		want := map[string]int{pipeline.ContentStartID: 10, "setup": 11}
+		if fmt.Sprint(pages) != fmt.Sprint(want) {
+			t.Errorf("destinationPages() = %v, want %v", pages, want)
+		}
+	})
+
+	t.Run("rejects unreadable PDF", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := destinationPages([]byte("%PDF-1.4 mock"), nil); !errors.Is(err, pdfedit.ErrMalformed) {
+			t.Errorf("destinationPages() error = %v, want %v", err, pdfedit.ErrMalformed)
+		}
+	})
+}
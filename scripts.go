@@ -0,0 +1,49 @@
+package picoloom
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// evalBundle evaluates a bundled library on a blank page of the shared
+// browser, then calls the JavaScript function fn with args and decodes its
+// result into out. The library comes from the binary, so nothing is fetched
+// from the network.
+func (r *rodRenderer) evalBundle(ctx context.Context, bundle, fn string, out any, args ...any) error {
+	if err := r.ensureBrowser(ctx); err != nil {
+		return err
+	}
+
+	page, err := r.browser.Page(proto.TargetCreateTarget{URL: "about:blank"})
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrPageCreate, err)
+	}
+	defer func() { _ = page.Close() }()
+
+	evalCtx, cancel, err := renderOperationContext(ctx, r.timeout)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	pageWithCtx := page.Context(evalCtx)
+
+	loaded, err := proto.RuntimeEvaluate{Expression: bundle}.Call(pageWithCtx)
+	if err != nil {
+		return fmt.Errorf("loading script: %w", err)
+	}
+	if loaded.ExceptionDetails != nil {
+		return fmt.Errorf("loading script: %s", loaded.ExceptionDetails.Text)
+	}
+
+	obj, err := pageWithCtx.Evaluate(rod.Eval(fn, args...).ByPromise())
+	if err != nil {
+		return err
+	}
+	if err := obj.Value.Unmarshal(out); err != nil {
+		return fmt.Errorf("reading script output: %w", err)
+	}
+	return nil
+}
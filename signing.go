@@ -0,0 +1,199 @@
+package picoloom
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	"github.com/alnah/picoloom/v2/internal/pdfedit"
+	"github.com/alnah/picoloom/v2/internal/pdfsign"
+	"github.com/alnah/picoloom/v2/internal/pipeline"
+)
+
+// signPDF adds a digital signature to the rendered PDF. The signing
+// certificate and key are read from disk on each call.
+func signPDF(ctx context.Context, data []byte, ds *DigitalSignature) ([]byte, error) {
+	creds, err := pdfsign.LoadCredentials(ds.CertFile, ds.KeyFile, ds.Password)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSigningCredentials, err)
+	}
+
+	field := pdfedit.SignatureField{
+		SignerName:  ds.Name,
+		Reason:      ds.Reason,
+		Location:    ds.Location,
+		ContactInfo: ds.ContactInfo,
+		Time:        time.Now(),
+	}
+	if field.SignerName == "" {
+		field.SignerName = creds.Certificate.Subject.CommonName
+	}
+	if ds.Visible {
+		page, rect, ok, err := signatureFieldRect(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: locating signature block: %w", ErrPDFSigning, err)
+		}
+		if ok {
+			field.Page, field.Rect = page, rect
+		}
+	}
+
+	opts := pdfsign.Options{TimestampURL: ds.TimestampURL}
+	signed, err := pdfedit.Sign(data, field, func(content []byte) ([]byte, error) {
+		return pdfsign.Sign(ctx, content, creds, opts)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrPDFSigning, err)
+	}
+	return signed, nil
+}
+
+// signatureFieldRect locates the signature block from the destinations
+// Chrome wrote for its start and end markers (see pipeline.SignatureData).
+// The field spans the block's height and the page width inside the left
+// margin, mirrored on the right. A block split across pages gets a field
+// on its last page, from the top margin, assumed equal to the left one.
+// ok is false when the markers are missing.
+func signatureFieldRect(data []byte) (page int, rect [4]float64, ok bool, err error) {
+	doc, err := pdfedit.Open(data)
+	if err != nil {
+		return 0, rect, false, err
+	}
+	pageRefs, err := doc.Pages()
+	if err != nil {
+		return 0, rect, false, err
+	}
+	named, err := doc.NamedDestinations()
+	if err != nil {
+		return 0, rect, false, err
+	}
+	startPage, left, top, ok := destinationPoint(named[pipeline.SignatureFieldStartID], pageRefs)
+	if !ok {
+		return 0, rect, false, nil
+	}
+	endPage, _, bottom, ok := destinationPoint(named[pipeline.SignatureFieldEndID], pageRefs)
+	if !ok || endPage < startPage {
+		return 0, rect, false, nil
+	}
+
+	pageDict, err := doc.ResolveDict(pageRefs[endPage-1])
+	if err != nil {
+		return 0, rect, false, err
+	}
+	box, ok := pageDict["MediaBox"].(pdfedit.Array)
+	if !ok || len(box) != 4 {
+		return 0, rect, false, nil
+	}
+	var media [4]float64
+	for i, v := range box {
+		if media[i], ok = number(v); !ok {
+			return 0, rect, false, nil
+		}
+	}
+	if endPage != startPage {
+		top = media[3] - (left - media[0])
+	}
+	if bottom >= top {
+		return 0, rect, false, nil
+	}
+	return endPage, [4]float64{left, bottom, media[2] - (left - media[0]), top}, true, nil
+}
+
+// destinationPoint returns the page and position of an explicit /XYZ
+// destination, as Chrome writes for link targets.
+func destinationPoint(dest pdfedit.Object, pageRefs []pdfedit.Ref) (page int, x, y float64, ok bool) {
+	arr, isArray := dest.(pdfedit.Array)
+	if !isArray || len(arr) < 4 || arr[1] != pdfedit.Name("XYZ") {
+		return 0, 0, 0, false
+	}
+	if page = pdfedit.PageNumber(arr, pageRefs); page == 0 {
+		return 0, 0, 0, false
+	}
+	x, okX := number(arr[2])
+	y, okY := number(arr[3])
+	return page, x, y, okX && okY
+}
+
+// number converts a PDF numeric object to float64.
+func number(obj pdfedit.Object) (float64, bool) {
+	switch v := obj.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// SignatureReport is the verification result of one signed field.
+type SignatureReport struct {
+	Field       string    // Signature field name
+	SignerName  string    // Signer name declared in the PDF
+	Reason      string    // Reason for signing
+	Location    string    // Where the document was signed
+	SigningTime time.Time // Time claimed by the signer; zero if absent
+
+	// Signer is the signing certificate; nil when Err reports a signature
+	// that could not be decoded.
+	Signer *x509.Certificate
+	// Timestamp is the time certified by an RFC 3161 timestamp token,
+	// zero without one.
+	Timestamp time.Time
+	// CoversDocument reports whether the signature covers the whole file.
+	// False means content was appended after signing.
+	CoversDocument bool
+
+	// Err wraps ErrSignatureVerification when the signature does not match
+	// the signed bytes. nil means they are intact.
+	Err error
+	// TrustErr explains why the signer does not chain to a trusted root.
+	// nil means it does.
+	TrustErr error
+}
+
+// Valid reports whether the signature is intact, trusted and covers the
+// whole document.
+func (r *SignatureReport) Valid() bool {
+	return r.Err == nil && r.TrustErr == nil && r.CoversDocument
+}
+
+// VerifyPDFSignatures checks every digital signature of a PDF against the
+// trusted roots, or the system roots when roots is nil. Returns an empty
+// slice for an unsigned PDF, and ErrSignatureVerification when the file
+// cannot be read.
+func VerifyPDFSignatures(data []byte, roots *x509.CertPool) ([]SignatureReport, error) {
+	doc, err := pdfedit.Open(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSignatureVerification, err)
+	}
+	sigs, err := doc.Signatures()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSignatureVerification, err)
+	}
+
+	reports := make([]SignatureReport, 0, len(sigs))
+	for _, sig := range sigs {
+		report := SignatureReport{
+			Field:          sig.Field,
+			SignerName:     sig.SignerName,
+			Reason:         sig.Reason,
+			Location:       sig.Location,
+			SigningTime:    sig.Time,
+			CoversDocument: sig.WholeFile,
+		}
+		switch sig.SubFilter {
+		case "ETSI.CAdES.detached", "adbe.pkcs7.detached":
+			res, err := pdfsign.Verify(sig.Contents, sig.Signed, pdfsign.VerifyOptions{Roots: roots})
+			if err != nil {
+				report.Err = fmt.Errorf("%w: %w", ErrSignatureVerification, err)
+				break
+			}
+			report.Signer, report.Timestamp, report.TrustErr = res.Signer, res.Timestamp, res.TrustError
+		default:
+			report.Err = fmt.Errorf("%w: unsupported signature format %q", ErrSignatureVerification, sig.SubFilter)
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
@@ -0,0 +1,266 @@
+package picoloom
+
+// Notes:
+// - Signs minimal Chrome-like PDFs with the self-signed test credentials of
+//   internal/pdfsign/testdata (password "test")
+// - Signatures are read back with VerifyPDFSignatures, trusting the test
+//   certificate as a root
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testSigningCert is a PKCS#12 archive holding the test signer.
+var testSigningCert = filepath.Join("internal", "pdfsign", "testdata", "signer-aes.p12")
+
+// testSigningRoots trusts the self-signed test signer.
+func testSigningRoots(t *testing.T) *x509.CertPool {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("internal", "pdfsign", "testdata", "signer.crt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, _ := pem.Decode(data)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return pool
+}
+
+// signatureBlockPDF returns a two-page PDF with the destinations Chrome
+// writes for the signature block markers.
+func signatureBlockPDF(start, end string) []byte {
+	return buildTestPDF("",
+		"<</Type /Catalog /Pages 2 0 R /Dests <</picoloom-signature-start "+start+" /picoloom-signature-end "+end+">>>>",
+		"<</Type /Pages /Count 2 /Kids [3 0 R 4 0 R]>>",
+		"<</Type /Page /Parent 2 0 R /MediaBox [0 0 612 792]>>",
+		"<</Type /Page /Parent 2 0 R /MediaBox [0 0 612 792]>>",
+	)
+}
+
+// ---------------------------------------------------------------------------
+// TestSignPDF - Signing and verification round trip
+// ---------------------------------------------------------------------------
+
+func TestSignPDF(t *testing.T) {
+	t.Parallel()
+
+	ds := &DigitalSignature{CertFile: testSigningCert, Password: "test", Reason: "Approval", Location: "Paris"}
+
+	t.Run("verifies against the signer's root", func(t *testing.T) {
+		t.Parallel()
+
+		signed, err := signPDF(context.Background(), chromeLikePDF(), ds)
+		if err != nil {
+			t.Fatalf("signPDF() unexpected error: %v", err)
+		}
+		reports, err := VerifyPDFSignatures(signed, testSigningRoots(t))
+		if err != nil {
+			t.Fatalf("VerifyPDFSignatures() unexpected error: %v", err)
+		}
+		if len(reports) != 1 {
+			t.Fatalf("VerifyPDFSignatures() = %d reports, want 1", len(reports))
+		}
+		r := reports[0]
+		if !r.Valid() {
+			t.Errorf("Valid() = false: Err = %v, TrustErr = %v, CoversDocument = %v", r.Err, r.TrustErr, r.CoversDocument)
+		}
+		if r.SignerName != "picoloom test signer" {
+			t.Errorf("SignerName = %q, want the certificate common name", r.SignerName)
+		}
+		if r.Reason != "Approval" || r.Location != "Paris" {
+			t.Errorf("Reason, Location = %q, %q, want %q, %q", r.Reason, r.Location, "Approval", "Paris")
+		}
+		if r.SigningTime.IsZero() {
+			t.Error("SigningTime is zero, want the signing time")
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		failingTSA := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		t.Cleanup(failingTSA.Close)
+
+		tests := []struct {
+			name string
+			ds   DigitalSignature
+			want error
+		}{
+			{"wrong password", DigitalSignature{CertFile: testSigningCert, Password: "wrong"}, ErrSigningCredentials},
+			{"missing certificate", DigitalSignature{CertFile: "missing.p12"}, ErrSigningCredentials},
+			{"timestamp server error", DigitalSignature{CertFile: testSigningCert, Password: "test", TimestampURL: failingTSA.URL}, ErrPDFSigning},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				if _, err := signPDF(context.Background(), chromeLikePDF(), &tt.ds); !errors.Is(err, tt.want) {
+					t.Errorf("signPDF() error = %v, want %v", err, tt.want)
+				}
+			})
+		}
+	})
+}
+
+// ---------------------------------------------------------------------------
+// TestSignatureFieldRect - Visible field placement
+// ---------------------------------------------------------------------------
+
+func TestSignatureFieldRect(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		data     []byte
+		wantOK   bool
+		wantPage int
+		wantRect [4]float64
+	}{
+		{
+			name:     "block on one page",
+			data:     signatureBlockPDF("[3 0 R /XYZ 72 300 0]", "[3 0 R /XYZ 72 220.5 0]"),
+			wantOK:   true,
+			wantPage: 1,
+			wantRect: [4]float64{72, 220.5, 540, 300},
+		},
+		{
+			name:     "block split across pages",
+			data:     signatureBlockPDF("[3 0 R /XYZ 72 60 0]", "[4 0 R /XYZ 72 650 0]"),
+			wantOK:   true,
+			wantPage: 2,
+			wantRect: [4]float64{72, 650, 540, 720},
+		},
+		{
+			name: "markers missing",
+			data: chromeLikePDF(),
+		},
+		{
+			name: "empty block",
+			data: signatureBlockPDF("[3 0 R /XYZ 72 300 0]", "[3 0 R /XYZ 72 300 0]"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			page, rect, ok, err := signatureFieldRect(tt.data)
+			if err != nil {
+				t.Fatalf("signatureFieldRect() unexpected error: %v", err)
+			}
+			if ok != tt.wantOK || page != tt.wantPage || rect != tt.wantRect {
+				t.Errorf("signatureFieldRect() = %d, %v, %v, want %d, %v, %v", page, rect, ok, tt.wantPage, tt.wantRect, tt.wantOK)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestVerifyPDFSignatures - Tampering and trust
+// ---------------------------------------------------------------------------
+
+func TestVerifyPDFSignatures(t *testing.T) {
+	t.Parallel()
+
+	signed, err := signPDF(context.Background(), chromeLikePDF(), &DigitalSignature{CertFile: testSigningCert, Password: "test"})
+	if err != nil {
+		t.Fatalf("signPDF() unexpected error: %v", err)
+	}
+
+	t.Run("unsigned PDF", func(t *testing.T) {
+		t.Parallel()
+
+		reports, err := VerifyPDFSignatures(chromeLikePDF(), nil)
+		if err != nil {
+			t.Fatalf("VerifyPDFSignatures() unexpected error: %v", err)
+		}
+		if len(reports) != 0 {
+			t.Errorf("VerifyPDFSignatures() = %d reports, want 0", len(reports))
+		}
+	})
+
+	t.Run("not a PDF", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := VerifyPDFSignatures([]byte("garbage"), nil); !errors.Is(err, ErrSignatureVerification) {
+			t.Errorf("VerifyPDFSignatures() error = %v, want %v", err, ErrSignatureVerification)
+		}
+	})
+
+	tests := []struct {
+		name         string
+		data         func() []byte
+		roots        *x509.CertPool
+		wantErr      bool
+		wantTrustErr bool
+		wantCovers   bool
+	}{
+		{
+			name: "modified content",
+			data: func() []byte {
+				data := append([]byte(nil), signed...)
+				data[9] ^= 0xff // Header comment, covered by the byte range
+				return data
+			},
+			roots:      testSigningRoots(t),
+			wantErr:    true,
+			wantCovers: true,
+		},
+		{
+			name:       "appended content",
+			data:       func() []byte { return append(append([]byte(nil), signed...), "% note\n"...) },
+			roots:      testSigningRoots(t),
+			wantCovers: false,
+		},
+		{
+			name:         "untrusted signer",
+			data:         func() []byte { return signed },
+			roots:        x509.NewCertPool(),
+			wantTrustErr: true,
+			wantCovers:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			reports, err := VerifyPDFSignatures(tt.data(), tt.roots)
+			if err != nil {
+				t.Fatalf("VerifyPDFSignatures() unexpected error: %v", err)
+			}
+			if len(reports) != 1 {
+				t.Fatalf("VerifyPDFSignatures() = %d reports, want 1", len(reports))
+			}
+			r := reports[0]
+			if (r.Err != nil) != tt.wantErr {
+				t.Errorf("Err = %v, wantErr %v", r.Err, tt.wantErr)
+			}
+			if r.Err != nil && !errors.Is(r.Err, ErrSignatureVerification) {
+				t.Errorf("Err = %v, want %v", r.Err, ErrSignatureVerification)
+			}
+			if (r.TrustErr != nil) != tt.wantTrustErr {
+				t.Errorf("TrustErr = %v, wantTrustErr %v", r.TrustErr, tt.wantTrustErr)
+			}
+			if r.CoversDocument != tt.wantCovers {
+				t.Errorf("CoversDocument = %v, want %v", r.CoversDocument, tt.wantCovers)
+			}
+			if r.Valid() {
+				t.Error("Valid() = true, want false")
+			}
+		})
+	}
+}
@@ -2,18 +2,27 @@ package picoloom
 
 import (
 	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/alnah/picoloom/v2/internal/assets"
 	"github.com/alnah/picoloom/v2/internal/fileutil"
+	"github.com/yuin/goldmark"
 )
 
 // Page size constants.
 const (
-	PageSizeLetter = "letter"
-	PageSizeA4     = "a4"
-	PageSizeLegal  = "legal"
+	PageSizeLetter    = "letter"
+	PageSizeA4        = "a4"
+	PageSizeLegal     = "legal"
+	PageSizeA3        = "a3"
+	PageSizeA5        = "a5"
+	PageSizeB5        = "b5"
+	PageSizeExecutive = "executive"
+	PageSizeTabloid   = "tabloid"
 )
 
 // Orientation constants.
@@ -29,6 +38,16 @@ const (
 	DefaultMargin = 0.5
 )
 
+// Custom paper size bounds in inches.
+const (
+	MinPageDimension = 1.0
+	MaxPageDimension = 100.0
+)
+
+// minContentInches is the smallest content width or height the margins
+// must leave on the page.
+const minContentInches = 1.0
+
 // Orphan/widow bounds for page break control.
 const (
 	MinOrphans     = 1
@@ -41,9 +60,69 @@ const (
 
 // PageSettings configures PDF page dimensions.
 type PageSettings struct {
-	Size        string  // "letter", "a4", "legal"
+	Size        string  // "letter", "a4", "legal", "a3", "a5", "b5", "executive", "tabloid"
 	Orientation string  // "portrait", "landscape"
 	Margin      float64 // inches, applied to all sides
+
+	// Width and Height set a custom paper size in place of Size, as lengths
+	// like "210mm" (see ParseLength). Set both or neither. Landscape swaps
+	// them, as it does for a named size.
+	Width  string
+	Height string
+
+	// Margins overrides Margin on individual sides (optional).
+	Margins *Margins
+}
+
+// Margins sets page margins per side as lengths like "20mm" (see
+// ParseLength). Empty sides use PageSettings.Margin.
+//
+// Inner and Outer replace Left and Right for bound documents: the inner
+// margin is on the binding side, left on right-hand pages and right on
+// left-hand pages. The first page is a right-hand page.
+type Margins struct {
+	Top    string
+	Right  string
+	Bottom string
+	Left   string
+	Inner  string
+	Outer  string
+}
+
+// Length units accepted by ParseLength.
+const (
+	UnitMillimeter = "mm"
+	UnitCentimeter = "cm"
+	UnitInch       = "in"
+	UnitPoint      = "pt"
+)
+
+// lengthPattern matches a positive number with an optional unit.
+// Captures: 1=number, 2=unit
+var lengthPattern = regexp.MustCompile(`^(\d+(?:\.\d*)?|\.\d+)\s*([a-zA-Z]*)$`)
+
+// ParseLength converts a length such as "210mm", "2.5cm", "1in" or "72pt"
+// to inches. A number without a unit is in inches.
+func ParseLength(s string) (float64, error) {
+	m := lengthPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return 0, fmt.Errorf("invalid length %q", s)
+	}
+	value, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid length %q", s)
+	}
+	switch strings.ToLower(m[2]) {
+	case UnitMillimeter:
+		return value / 25.4, nil
+	case UnitCentimeter:
+		return value / 2.54, nil
+	case "", UnitInch:
+		return value, nil
+	case UnitPoint:
+		return value / 72, nil
+	}
+	return 0, fmt.Errorf("invalid length %q (unit must be mm, cm, in, or pt)", s)
 }
 
 // DefaultPageSettings returns page settings with default values.
@@ -78,13 +157,79 @@ func (p *PageSettings) Validate() error {
 		return fmt.Errorf("%w: %.2f (must be between %.2f and %.2f)", ErrInvalidMargin, p.Margin, MinMargin, MaxMargin)
 	}
 
+	if err := p.validateCustomSize(); err != nil {
+		return err
+	}
+	if err := p.Margins.validate(); err != nil {
+		return err
+	}
+
+	// The margins must leave room for content.
+	w, h := p.dimensions()
+	top, right, bottom, left := p.margins()
+	if w-left-right < minContentInches || h-top-bottom < minContentInches {
+		return fmt.Errorf("%w: margins leave less than %.0fin of content on a %.2fin x %.2fin page", ErrInvalidMargin, minContentInches, w, h)
+	}
+
+	return nil
+}
+
+// validateCustomSize checks Width and Height.
+func (p *PageSettings) validateCustomSize() error {
+	if p.Width == "" && p.Height == "" {
+		return nil
+	}
+	if p.Width == "" || p.Height == "" {
+		return fmt.Errorf("%w: custom size needs both width and height", ErrInvalidPageSize)
+	}
+	for _, length := range []string{p.Width, p.Height} {
+		inches, err := ParseLength(length)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidPageSize, err)
+		}
+		if inches < MinPageDimension || inches > MaxPageDimension {
+			return fmt.Errorf("%w: %q (must be between %.0fin and %.0fin)", ErrInvalidPageSize, length, MinPageDimension, MaxPageDimension)
+		}
+	}
+	return nil
+}
+
+// mirrored reports whether left and right margins swap on left-hand pages.
+func (m *Margins) mirrored() bool {
+	return m != nil && (m.Inner != "" || m.Outer != "")
+}
+
+// validate checks that each set side is a length within margin bounds.
+func (m *Margins) validate() error {
+	if m == nil {
+		return nil
+	}
+	if m.mirrored() && (m.Left != "" || m.Right != "") {
+		return fmt.Errorf("%w: inner/outer margins replace left/right margins", ErrInvalidMargin)
+	}
+	for _, side := range []struct{ name, length string }{
+		{"top", m.Top}, {"right", m.Right}, {"bottom", m.Bottom},
+		{"left", m.Left}, {"inner", m.Inner}, {"outer", m.Outer},
+	} {
+		if side.length == "" {
+			continue
+		}
+		inches, err := ParseLength(side.length)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrInvalidMargin, side.name, err)
+		}
+		if inches < MinMargin || inches > MaxMargin {
+			return fmt.Errorf("%w: %s %q (must be between %.2fin and %.2fin)", ErrInvalidMargin, side.name, side.length, MinMargin, MaxMargin)
+		}
+	}
 	return nil
 }
 
 // isValidPageSize checks if size is a known page size (case-insensitive).
 func isValidPageSize(size string) bool {
 	switch strings.ToLower(size) {
-	case PageSizeLetter, PageSizeA4, PageSizeLegal:
+	case PageSizeLetter, PageSizeA4, PageSizeLegal, PageSizeA3, PageSizeA5,
+		PageSizeB5, PageSizeExecutive, PageSizeTabloid:
 		return true
 	}
 	return false
@@ -128,16 +273,71 @@ func (pb *PageBreaks) Validate() error {
 // Input contains conversion parameters.
 type Input struct {
 	Markdown   string        // Markdown content (required)
-	SourceDir  string        // Base directory for resolving relative paths (optional)
+	SourceDir  string        // Base directory for relative paths and !include (optional)
 	CSS        string        // Custom CSS (optional)
 	Footer     *Footer       // Footer config (optional)
+	Header     *Header       // Running page header config (optional)
 	Signature  *Signature    // Signature config (optional)
 	Page       *PageSettings // Page settings (optional, nil = defaults)
 	Watermark  *Watermark    // Watermark config (optional)
 	Cover      *Cover        // Cover page config (optional)
 	TOC        *TOC          // Table of contents config (optional)
+	Outline    *Outline      // PDF bookmarks config (optional)
 	PageBreaks *PageBreaks   // Page break config (optional)
+	Metadata   *Metadata     // PDF document properties (optional, nil = from Cover)
+	Encryption *Encryption   // Password protection (optional, nil = unencrypted)
 	HTMLOnly   bool          // If true, skip PDF generation (for debugging)
+
+	// Diagrams renders mermaid code fences as SVG (optional, nil = shown as code).
+	Diagrams *Diagrams
+
+	// Math renders $...$ and $$...$$ TeX with KaTeX (optional, nil = shown as written).
+	Math *Math
+
+	// RawHTML keeps HTML written in the Markdown, sanitized with an allowlist
+	// (optional, nil = raw HTML omitted).
+	RawHTML *RawHTML
+
+	// Variables fills {{ ... }} references in the body (optional, nil = left as written).
+	Variables *Variables
+
+	// Bibliography formats [@key] citations and adds a reference list
+	// (optional, nil = citations left as written).
+	Bibliography *Bibliography
+
+	// Index lists [[idx:term]] terms with their page numbers
+	// (optional, nil = terms are not listed).
+	Index *Index
+
+	// ListOfFigures and ListOfTables list captioned figures and tables after
+	// the TOC (optional, nil = no list).
+	ListOfFigures *ListOf
+	ListOfTables  *ListOf
+
+	// Footnotes prints footnotes at the foot of the page that references
+	// them (optional, nil = listed at the end of the document).
+	Footnotes *Footnotes
+
+	// PageNumbering restyles the page numbers of Footer and Header and sets
+	// matching PDF page labels (optional, nil = Chrome's numbering).
+	PageNumbering *PageNumbering
+
+	// DigitalSignature signs the PDF with a certificate (optional, nil = unsigned).
+	DigitalSignature *DigitalSignature
+
+	// Frontmatter overlays per-document settings on the fields above (optional).
+	// Use ParseFrontmatter to read it from the markdown content.
+	Frontmatter *Frontmatter
+}
+
+// Chapter is one Markdown source in a merged document (see Converter.ConvertMany).
+type Chapter struct {
+	Markdown string // Markdown content (required)
+
+	// Path is the chapter's source file (optional). Relative images resolve
+	// against its directory, and links to this file from other chapters
+	// become in-document links.
+	Path string
 }
 
 // ConvertResult holds both HTML and PDF output from conversion.
@@ -184,6 +384,102 @@ func (w *Watermark) Validate() error {
 	return nil
 }
 
+// Metadata sets the document properties written to the PDF Info dictionary
+// and XMP packet. Empty fields fall back to the Cover: Title to Cover.Title,
+// Author to Cover.Author and Subject to Cover.Description. The title also
+// becomes the HTML <title>, with the first H1 as a last resort.
+type Metadata struct {
+	Title    string   // Document title
+	Author   string   // Author name
+	Subject  string   // Document summary
+	Keywords []string // Search keywords
+}
+
+// MaxPasswordLength is the longest password AES-256 PDF encryption accepts,
+// in bytes of UTF-8.
+const MaxPasswordLength = 127
+
+// Encryption protects the PDF with AES-256 (PDF 2.0 security handler).
+// Readers ask for UserPassword to open the document; OwnerPassword unlocks
+// full access and defaults to UserPassword. An empty UserPassword lets
+// anyone open the document with the restricted permissions below, so only
+// the owner password can lift them.
+//
+// Permissions apply to users who open the document with the user password.
+// They are honored by conforming readers, not enforced by the encryption.
+type Encryption struct {
+	UserPassword  string // Password to open the document (optional)
+	OwnerPassword string // Password for full access (default: UserPassword)
+	AllowPrint    bool   // Allow printing at full quality
+	AllowCopy     bool   // Allow copying text and images
+	AllowModify   bool   // Allow editing, annotating, form filling and page assembly
+}
+
+// Validate checks that encryption settings are valid.
+// Returns nil if e is nil (nil means no encryption).
+func (e *Encryption) Validate() error {
+	if e == nil {
+		return nil
+	}
+	if e.UserPassword == "" && e.OwnerPassword == "" {
+		return fmt.Errorf("%w: a user or owner password is required", ErrInvalidEncryption)
+	}
+	if len(e.UserPassword) > MaxPasswordLength {
+		return fmt.Errorf("%w: user password is %d bytes (max %d)", ErrInvalidEncryption, len(e.UserPassword), MaxPasswordLength)
+	}
+	if len(e.OwnerPassword) > MaxPasswordLength {
+		return fmt.Errorf("%w: owner password is %d bytes (max %d)", ErrInvalidEncryption, len(e.OwnerPassword), MaxPasswordLength)
+	}
+	return nil
+}
+
+// DigitalSignature signs the PDF with a local certificate, as a CAdES
+// detached signature over the whole file. The signature is added last, so
+// it cannot be combined with Encryption.
+//
+// CertFile is a PEM file or a PKCS#12 archive (.p12, .pfx). The private key
+// is read from KeyFile, or from CertFile when KeyFile is empty. Password
+// decrypts an encrypted key or archive. Supported keys are RSA and ECDSA.
+type DigitalSignature struct {
+	CertFile    string // Certificate (and chain) file (required)
+	KeyFile     string // Private key file (optional, default: CertFile)
+	Password    string // Key or archive password (optional)
+	Name        string // Signer name shown by readers (default: certificate common name)
+	Reason      string // Reason for signing (optional)
+	Location    string // Where the document was signed (optional)
+	ContactInfo string // How to reach the signer (optional)
+
+	// Visible places the signature field over the signature block. It
+	// requires Input.Signature; the field is invisible if the block cannot
+	// be located in the PDF.
+	Visible bool
+
+	// TimestampURL is an RFC 3161 time-stamping server (optional). Its
+	// token proves the signature existed at that time.
+	TimestampURL string
+}
+
+// Validate checks that digital signature settings are valid.
+// Returns nil if s is nil (nil means no digital signature).
+//
+// Credentials are read at signing time, so a missing or undecryptable file
+// fails the conversion with ErrSigningCredentials.
+func (s *DigitalSignature) Validate() error {
+	if s == nil {
+		return nil
+	}
+	if s.CertFile == "" {
+		return fmt.Errorf("%w: a certificate file is required", ErrInvalidDigitalSignature)
+	}
+	if s.TimestampURL != "" {
+		u, err := url.Parse(s.TimestampURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: timestamp URL %q must be an http or https URL", ErrInvalidDigitalSignature, s.TimestampURL)
+		}
+	}
+	return nil
+}
+
 // Cover configures the cover page.
 type Cover struct {
 	Title        string // Document title (required)
@@ -229,6 +525,10 @@ type TOC struct {
 	Title    string // Title above TOC (empty = no title)
 	MinDepth int    // 1-6, minimum heading level to include (default: 2, skips H1)
 	MaxDepth int    // 1-6, maximum heading level to include (default: 3)
+
+	// PageNumbers prints each entry's page number after a dot leader.
+	// Pages are read from a first render, so the PDF is rendered twice.
+	PageNumbers bool
 }
 
 // Validate checks that TOC settings are valid.
@@ -252,6 +552,38 @@ func (t *TOC) Validate() error {
 	return nil
 }
 
+// Outline depth defaults.
+const (
+	DefaultOutlineMinDepth = 1
+	DefaultOutlineMaxDepth = 3
+)
+
+// Outline configures the PDF outline (bookmarks) built from document headings.
+// Each bookmark points at its heading's page; deeper headings nest under the
+// previous shallower one.
+type Outline struct {
+	MinDepth int // 1-6, minimum heading level to include (default: 1)
+	MaxDepth int // 1-6, maximum heading level to include (default: 3)
+}
+
+// Validate checks that outline settings are valid.
+// Returns nil if o is nil (nil means no outline).
+func (o *Outline) Validate() error {
+	if o == nil {
+		return nil
+	}
+	if o.MinDepth != 0 && (o.MinDepth < minTOCDepth || o.MinDepth > maxTOCDepth) {
+		return fmt.Errorf("%w: MinDepth %d (must be %d-%d)", ErrInvalidOutlineDepth, o.MinDepth, minTOCDepth, maxTOCDepth)
+	}
+	if o.MaxDepth != 0 && (o.MaxDepth < minTOCDepth || o.MaxDepth > maxTOCDepth) {
+		return fmt.Errorf("%w: MaxDepth %d (must be %d-%d)", ErrInvalidOutlineDepth, o.MaxDepth, minTOCDepth, maxTOCDepth)
+	}
+	if o.MinDepth != 0 && o.MaxDepth != 0 && o.MinDepth > o.MaxDepth {
+		return fmt.Errorf("%w: MinDepth %d > MaxDepth %d", ErrInvalidOutlineDepth, o.MinDepth, o.MaxDepth)
+	}
+	return nil
+}
+
 // isValidHexColor checks if color is a valid hex color (#RGB or #RRGGBB).
 func isValidHexColor(color string) bool {
 	if len(color) == 0 || color[0] != '#' {
@@ -293,6 +625,360 @@ func (f *Footer) Validate() error {
 	}
 }
 
+// Header placeholders, written as {name} inside a Header slot.
+const (
+	HeaderPlaceholderTitle      = "title"
+	HeaderPlaceholderVersion    = "version"
+	HeaderPlaceholderDocumentID = "documentID"
+	HeaderPlaceholderDate       = "date"
+	HeaderPlaceholderPageNumber = "pageNumber"
+	HeaderPlaceholderTotalPages = "totalPages"
+)
+
+// headerPlaceholderPattern matches {name} placeholders in header slots.
+var headerPlaceholderPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+// Header configures a running page header repeated at the top of every page.
+// Each slot holds free text with optional placeholders, e.g. "{title} - v{version}"
+// or "Page {pageNumber} of {totalPages}".
+type Header struct {
+	Left   string
+	Center string
+	Right  string
+
+	// Values substituted for placeholders. Empty values fall back to the
+	// matching Cover field, if any.
+	Title      string
+	Version    string
+	DocumentID string
+	Date       string
+}
+
+// Validate checks that header slots only use known placeholders.
+// Returns nil if h is nil (nil means no header).
+func (h *Header) Validate() error {
+	if h == nil {
+		return nil
+	}
+	for _, slot := range []string{h.Left, h.Center, h.Right} {
+		for _, m := range headerPlaceholderPattern.FindAllStringSubmatch(slot, -1) {
+			if !isHeaderPlaceholder(m[1]) {
+				return fmt.Errorf("%w: %q (must be title, version, documentID, date, pageNumber, or totalPages)", ErrInvalidHeaderPlaceholder, m[0])
+			}
+		}
+	}
+	return nil
+}
+
+// isHeaderPlaceholder reports whether name is a supported header placeholder.
+func isHeaderPlaceholder(name string) bool {
+	switch name {
+	case HeaderPlaceholderTitle, HeaderPlaceholderVersion, HeaderPlaceholderDocumentID,
+		HeaderPlaceholderDate, HeaderPlaceholderPageNumber, HeaderPlaceholderTotalPages:
+		return true
+	}
+	return false
+}
+
+// Page number formats.
+const (
+	PageNumberFormatSlash  = "slash"   // "3/12" (default)
+	PageNumberFormatPageOf = "page-of" // "Page 3 of 12"
+	PageNumberFormatPlain  = "plain"   // "3"
+)
+
+// Front matter numbering modes, for the cover and TOC pages.
+const (
+	FrontMatterContinue = "continue" // Number them like body pages (default)
+	FrontMatterSkip     = "skip"     // Print no number on them
+	FrontMatterRoman    = "roman"    // Number them i, ii, iii...
+)
+
+// PageNumbering controls the page numbers printed by Footer and Header and
+// the page labels PDF viewers display.
+//
+// The front matter is the cover and TOC. With FrontMatterSkip or
+// FrontMatterRoman, arabic numbering restarts at StartAt on the first body
+// page, and totals count body pages only.
+type PageNumbering struct {
+	Format      string // "slash", "page-of", "plain" (default: "slash")
+	StartAt     int    // Number of the first arabic-numbered page (default: 1)
+	FrontMatter string // "continue", "skip", "roman" (default: "continue")
+}
+
+// Validate checks that page numbering settings are valid.
+// Returns nil if n is nil (nil means Chrome's own page numbers).
+func (n *PageNumbering) Validate() error {
+	if n == nil {
+		return nil
+	}
+	switch strings.ToLower(n.Format) {
+	case "", PageNumberFormatSlash, PageNumberFormatPageOf, PageNumberFormatPlain:
+	default:
+		return fmt.Errorf("%w: format %q (must be slash, page-of, or plain)", ErrInvalidPageNumbering, n.Format)
+	}
+	switch strings.ToLower(n.FrontMatter) {
+	case "", FrontMatterContinue, FrontMatterSkip, FrontMatterRoman:
+	default:
+		return fmt.Errorf("%w: front matter %q (must be continue, skip, or roman)", ErrInvalidPageNumbering, n.FrontMatter)
+	}
+	if n.StartAt < 0 {
+		return fmt.Errorf("%w: start %d (must be positive)", ErrInvalidPageNumbering, n.StartAt)
+	}
+	return nil
+}
+
+// Mermaid theme constants.
+const (
+	DiagramThemeDefault = "default"
+	DiagramThemeNeutral = "neutral"
+	DiagramThemeDark    = "dark"
+	DiagramThemeForest  = "forest"
+	DiagramThemeBase    = "base"
+)
+
+// Diagrams renders ```mermaid code fences as inline SVG. Diagrams are drawn
+// offline in the headless Chrome session with an embedded Mermaid bundle.
+type Diagrams struct {
+	Theme string // "default", "neutral", "dark", "forest", "base" (default: "default")
+}
+
+// Validate checks that diagram settings are valid.
+// Returns nil if d is nil (nil means mermaid fences stay code blocks).
+func (d *Diagrams) Validate() error {
+	if d == nil {
+		return nil
+	}
+	switch strings.ToLower(d.Theme) {
+	case "", DiagramThemeDefault, DiagramThemeNeutral, DiagramThemeDark, DiagramThemeForest, DiagramThemeBase:
+		return nil
+	}
+	return fmt.Errorf("%w: %q (must be default, neutral, dark, forest, or base)", ErrInvalidDiagramTheme, d.Theme)
+}
+
+// htmlNamePattern matches an element or attribute name.
+var htmlNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9-]*$`)
+
+// RawHTML keeps HTML written in the Markdown, such as <br>, <kbd>, <sub> or
+// <details>. The body is then sanitized: elements outside the allowlist are
+// removed, keeping their text, as are attributes outside it. Scripts, on*
+// event handlers, javascript: URLs and data: URLs other than images in
+// <img src> are always removed. The default
+// allowlist covers the Markdown output and common formatting elements, with
+// attributes such as id, class, href, src and alt; data-* and aria-*
+// attributes are kept.
+type RawHTML struct {
+	AllowedTags       []string // Elements allowed besides the defaults
+	AllowedAttributes []string // Attributes allowed on any element besides the defaults
+}
+
+// Validate checks that the allowlist holds element and attribute names, and
+// none that is always removed.
+// Returns nil if r is nil (nil means raw HTML is omitted).
+func (r *RawHTML) Validate() error {
+	if r == nil {
+		return nil
+	}
+	for _, tag := range r.AllowedTags {
+		if !htmlNamePattern.MatchString(tag) {
+			return fmt.Errorf("%w: invalid tag name %q", ErrInvalidRawHTML, tag)
+		}
+		if strings.EqualFold(tag, "script") {
+			return fmt.Errorf("%w: %q cannot be allowed", ErrInvalidRawHTML, tag)
+		}
+	}
+	for _, attr := range r.AllowedAttributes {
+		if !htmlNamePattern.MatchString(attr) {
+			return fmt.Errorf("%w: invalid attribute name %q", ErrInvalidRawHTML, attr)
+		}
+		if len(attr) >= 2 && strings.EqualFold(attr[:2], "on") {
+			return fmt.Errorf("%w: event handler %q cannot be allowed", ErrInvalidRawHTML, attr)
+		}
+	}
+	return nil
+}
+
+// Math renders $...$ inline and $$...$$ display TeX with an embedded KaTeX
+// bundle in the headless Chrome session. Fonts are embedded in the HTML, so
+// no network access is needed.
+type Math struct {
+	// Numbering numbers display equations (1), (2), ... in document order.
+	// An equation with its own \tag keeps it; \notag or \nonumber skips one.
+	Numbering bool
+}
+
+// Variables fills {{ .Document.Version }}, {{ .Author.Name }} and
+// {{ .Vars.name }} references in the Markdown body. Code spans and fenced
+// code blocks are left as written, and a reference to an unknown variable is
+// an error.
+type Variables struct {
+	Document DocumentVariables
+	Author   AuthorVariables
+	Vars     map[string]string // Custom variables, referenced as {{ .Vars.name }}
+}
+
+// DocumentVariables are the document fields available as {{ .Document.Field }}.
+type DocumentVariables struct {
+	Title        string
+	Subtitle     string
+	Version      string
+	Date         string
+	ClientName   string
+	ProjectName  string
+	DocumentType string
+	DocumentID   string
+	Description  string
+}
+
+// AuthorVariables are the author fields available as {{ .Author.Field }}.
+type AuthorVariables struct {
+	Name         string
+	Title        string
+	Email        string
+	Organization string
+	Phone        string
+	Address      string
+	Department   string
+}
+
+// variableNamePattern matches names usable as {{ .Vars.name }}.
+var variableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Validate checks that custom variable names are template identifiers.
+// Returns nil if v is nil (nil means the body is left as written).
+func (v *Variables) Validate() error {
+	if v == nil {
+		return nil
+	}
+	for name := range v.Vars {
+		if !variableNamePattern.MatchString(name) {
+			return fmt.Errorf("%w: %q (use letters, digits and underscores, not starting with a digit)", ErrInvalidVariable, name)
+		}
+	}
+	return nil
+}
+
+// Citation style constants.
+const (
+	CitationStyleAuthorDate = "author-date"
+	CitationStyleNumeric    = "numeric"
+)
+
+// DefaultBibliographyTitle heads the reference list when Title is empty.
+const DefaultBibliographyTitle = "References"
+
+// Bibliography formats citations from a BibTeX (.bib) or CSL-JSON (.json)
+// file. [@key] and [see @key, p. 3; @other] are parenthetical citations,
+// [-@key] omits the author, and a bare @key is narrative. Cited entries are
+// listed where a [bibliography] line appears, or at the end of the document.
+type Bibliography struct {
+	Path  string // Bibliography file, relative to SourceDir (required)
+	Style string // "author-date" or "numeric" (default: "author-date")
+	Title string // Reference list heading (default: "References")
+}
+
+// Validate checks that bibliography settings are valid.
+// Returns nil if b is nil (nil means citations are left as written).
+func (b *Bibliography) Validate() error {
+	if b == nil {
+		return nil
+	}
+	if strings.TrimSpace(b.Path) == "" {
+		return fmt.Errorf("%w: path is required", ErrInvalidBibliography)
+	}
+	switch strings.ToLower(b.Style) {
+	case "", CitationStyleAuthorDate, CitationStyleNumeric:
+		return nil
+	}
+	return fmt.Errorf("%w: style %q (must be author-date or numeric)", ErrInvalidBibliography, b.Style)
+}
+
+// DefaultIndexTitle heads the index when Title is empty.
+const DefaultIndexTitle = "Index"
+
+// Index adds a back-of-book index. [[idx:term]] marks a place a term is
+// discussed and [[idx:term!subterm]] a sub-entry. Terms are listed by letter
+// where an [index] line appears, or at the end of the document. Each page
+// reference links to the term; pages are read from a first render, so the
+// PDF is rendered twice when the document has terms.
+type Index struct {
+	Title string // Index heading (default: "Index")
+}
+
+// Default headings of the lists of figures and tables.
+const (
+	DefaultListOfFiguresTitle = "List of Figures"
+	DefaultListOfTablesTitle  = "List of Tables"
+)
+
+// ListOf adds a list of figures or tables after the TOC, or after the cover
+// when there is no TOC. Entries carry the figure or table number and
+// caption and link to it; tables are listed only when captioned.
+type ListOf struct {
+	Title string // List heading (default: "List of Figures" or "List of Tables")
+
+	// PageNumbers prints each entry's page number after a dot leader, as
+	// TOC.PageNumbers does. The PDF is rendered twice.
+	PageNumbers bool
+}
+
+// Footnote placements.
+const (
+	FootnotePlacementEnd  = "end"
+	FootnotePlacementPage = "page"
+)
+
+// Footnote numbering schemes.
+const (
+	FootnoteNumberingDocument = "document"
+	FootnoteNumberingPage     = "page"
+)
+
+// Footnotes places [^label] footnotes. By default they are listed at the end
+// of the document (or of each chapter). At the foot of the page, each note is
+// printed under the theme's separator rule on the page of its first
+// reference, or continues on the next page when that page is full.
+//
+// Page placement reads the notes' heights from a first render, reserves a
+// band of the same height at the bottom of every page, and stamps each page's
+// notes into it, so the PDF is rendered two or three times. The HTML output
+// keeps the notes at the end.
+type Footnotes struct {
+	Placement string // "end" or "page" (default: "end")
+
+	// Numbering restarts at 1 on every page with "page", or runs through the
+	// document with "document" (default). "page" requires page placement.
+	Numbering string
+}
+
+// Validate checks that footnote settings are valid.
+// Returns nil if f is nil (nil means footnotes are listed at the end).
+func (f *Footnotes) Validate() error {
+	if f == nil {
+		return nil
+	}
+	switch strings.ToLower(f.Placement) {
+	case "", FootnotePlacementEnd, FootnotePlacementPage:
+	default:
+		return fmt.Errorf("%w: placement %q (must be end or page)", ErrInvalidFootnotes, f.Placement)
+	}
+	switch strings.ToLower(f.Numbering) {
+	case "", FootnoteNumberingDocument:
+	case FootnoteNumberingPage:
+		if !f.atPageFoot() {
+			return fmt.Errorf("%w: page numbering requires page placement", ErrInvalidFootnotes)
+		}
+	default:
+		return fmt.Errorf("%w: numbering %q (must be document or page)", ErrInvalidFootnotes, f.Numbering)
+	}
+	return nil
+}
+
+// atPageFoot reports whether footnotes are printed at the foot of the page.
+func (f *Footnotes) atPageFoot() bool {
+	return f != nil && strings.EqualFold(f.Placement, FootnotePlacementPage)
+}
+
 // Signature configures the signature block.
 type Signature struct {
 	Name         string
@@ -340,6 +1026,9 @@ type converterConfig struct {
 	assetPath     string // Path for WithAssetPath, resolved in New()
 	styleInput    string // Raw input for WithStyle (name, path, or CSS content)
 	resolvedStyle string // CSS content after resolution in New()
+	pdfa          bool   // Produce PDF/A-2b (WithPDFA)
+
+	goldmarkExtensions []goldmark.Extender // Added by WithGoldmarkExtensions
 }
 
 // defaultTimeout is used when no timeout is specified.
@@ -402,7 +1091,19 @@ func WithStyle(style string) Option {
 	}
 }
 
-// WithTemplateSet sets a custom template set for cover and signature.
+// WithPDFA produces PDF/A-2b output for long-term archiving.
+// Chrome's PDF is post-processed: an sRGB output intent and XMP
+// identification are added, and embedded fonts, transparency and encryption
+// are checked. Convert fails with ErrPDFAConformance when the document
+// cannot conform.
+func WithPDFA() Option {
+	return func(c *Converter) {
+		c.cfg.pdfa = true
+	}
+}
+
+// WithTemplateSet sets a custom template set for cover, signature and,
+// optionally, page footer and header.
 // Use this to override the default templates loaded from embedded assets.
 //
 // Example:
@@ -416,6 +1117,8 @@ func WithTemplateSet(ts *TemplateSet) Option {
 				Name:      ts.Name,
 				Cover:     ts.Cover,
 				Signature: ts.Signature,
+				Footer:    ts.Footer,
+				Header:    ts.Header,
 			}
 		}
 	}
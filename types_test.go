@@ -11,6 +11,7 @@ package picoloom
 
 import (
 	"errors"
+	"math"
 	"os"
 	"strings"
 	"testing"
@@ -100,7 +101,7 @@ func TestPageSettings_Validate(t *testing.T) {
 		{
 			name: "invalid page size",
 			ps: &PageSettings{
-				Size:        "tabloid",
+				Size:        "folio",
 				Orientation: OrientationPortrait,
 				Margin:      DefaultMargin,
 			},
@@ -169,6 +170,56 @@ func TestPageSettings_Validate(t *testing.T) {
 			},
 			wantErr: ErrInvalidMargin,
 		},
+		{
+			name:    "custom size in millimeters",
+			ps:      &PageSettings{Width: "170mm", Height: "240mm"},
+			wantErr: nil,
+		},
+		{
+			name:    "custom width without height",
+			ps:      &PageSettings{Width: "170mm"},
+			wantErr: ErrInvalidPageSize,
+		},
+		{
+			name:    "custom size with unknown unit",
+			ps:      &PageSettings{Width: "170px", Height: "240px"},
+			wantErr: ErrInvalidPageSize,
+		},
+		{
+			name:    "custom size below minimum",
+			ps:      &PageSettings{Width: "10mm", Height: "240mm"},
+			wantErr: ErrInvalidPageSize,
+		},
+		{
+			name:    "per-side margins",
+			ps:      &PageSettings{Size: PageSizeA5, Margins: &Margins{Top: "2cm", Bottom: "0.75in", Left: "54pt"}},
+			wantErr: nil,
+		},
+		{
+			name:    "mirrored margins",
+			ps:      &PageSettings{Margins: &Margins{Inner: "25mm", Outer: "15mm"}},
+			wantErr: nil,
+		},
+		{
+			name:    "inner margin with left margin",
+			ps:      &PageSettings{Margins: &Margins{Inner: "25mm", Left: "15mm"}},
+			wantErr: ErrInvalidMargin,
+		},
+		{
+			name:    "side margin above maximum",
+			ps:      &PageSettings{Margins: &Margins{Bottom: "10cm"}},
+			wantErr: ErrInvalidMargin,
+		},
+		{
+			name:    "side margin not a length",
+			ps:      &PageSettings{Margins: &Margins{Top: "wide"}},
+			wantErr: ErrInvalidMargin,
+		},
+		{
+			name:    "margins leave no content area",
+			ps:      &PageSettings{Width: "3in", Height: "3in", Margins: &Margins{Left: "1.5in", Right: "1in"}},
+			wantErr: ErrInvalidMargin,
+		},
 		{
 			name: "all empty values valid (all use defaults)",
 			ps: &PageSettings{
@@ -203,6 +254,46 @@ func TestPageSettings_Validate(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// TestParseLength - Length Units
+// ---------------------------------------------------------------------------
+
+func TestParseLength(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input   string
+		want    float64
+		wantErr bool
+	}{
+		{"1in", 1, false},
+		{"2.5", 2.5, false},
+		{"25.4mm", 1, false},
+		{"2.54cm", 1, false},
+		{"72pt", 1, false},
+		{" 36 PT ", 0.5, false},
+		{".5in", 0.5, false},
+		{"", 0, true},
+		{"-1in", 0, true},
+		{"1px", 0, true},
+		{"mm", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseLength(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLength(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("ParseLength(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
 // ---------------------------------------------------------------------------
 // TestDefaultPageSettings - Default PageSettings Values
 // ---------------------------------------------------------------------------
@@ -245,9 +336,14 @@ func TestIsValidPageSize(t *testing.T) {
 		{"LETTER", true},
 		{"A4", true},
 		{"Letter", true},
-		{"tabloid", false},
+		{"tabloid", true},
+		{"folio", false},
+		{"a3", true},
+		{"a5", true},
+		{"b5", true},
+		{"Executive", true},
 		{"", false},
-		{"a5", false},
+		{"b4", false},
 	}
 
 	for _, tt := range tests {
@@ -377,6 +473,264 @@ func TestFooter_Validate(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// TestHeader_Validate - Header Placeholder Validation
+// ---------------------------------------------------------------------------
+
+func TestHeader_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		header  *Header
+		wantErr error
+	}{
+		{
+			name:    "nil is valid",
+			header:  nil,
+			wantErr: nil,
+		},
+		{
+			name:    "free text is valid",
+			header:  &Header{Center: "Internal use only"},
+			wantErr: nil,
+		},
+		{
+			name: "all placeholders are valid",
+			header: &Header{
+				Left:   "{title} {version}",
+				Center: "{documentID} {date}",
+				Right:  "{pageNumber}/{totalPages}",
+			},
+			wantErr: nil,
+		},
+		{
+			name:    "unknown placeholder returns error",
+			header:  &Header{Right: "{author}"},
+			wantErr: ErrInvalidHeaderPlaceholder,
+		},
+		{
+			name:    "placeholders are case sensitive",
+			header:  &Header{Left: "{Title}"},
+			wantErr: ErrInvalidHeaderPlaceholder,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.header.Validate()
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Validate() unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestPageNumbering_Validate - Page numbering format and front matter
+// ---------------------------------------------------------------------------
+
+func TestPageNumbering_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		numbering *PageNumbering
+		wantErr   error
+	}{
+		{"nil is valid", nil, nil},
+		{"zero values use defaults", &PageNumbering{}, nil},
+		{"all settings", &PageNumbering{Format: PageNumberFormatPageOf, StartAt: 3, FrontMatter: FrontMatterRoman}, nil},
+		{"case insensitive", &PageNumbering{Format: "Plain", FrontMatter: "SKIP"}, nil},
+		{"unknown format", &PageNumbering{Format: "x-of-y"}, ErrInvalidPageNumbering},
+		{"unknown front matter", &PageNumbering{FrontMatter: "hide"}, ErrInvalidPageNumbering},
+		{"negative start", &PageNumbering{StartAt: -1}, ErrInvalidPageNumbering},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.numbering.Validate()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("PageNumbering.Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestDiagrams_Validate - Mermaid Theme Validation
+// ---------------------------------------------------------------------------
+
+func TestDiagrams_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		diagrams *Diagrams
+		wantErr  error
+	}{
+		{"nil is valid", nil, nil},
+		{"empty theme uses default", &Diagrams{}, nil},
+		{"neutral", &Diagrams{Theme: DiagramThemeNeutral}, nil},
+		{"case insensitive", &Diagrams{Theme: "Dark"}, nil},
+		{"unknown theme", &Diagrams{Theme: "neon"}, ErrInvalidDiagramTheme},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.diagrams.Validate()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Diagrams.Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestVariables_Validate - Custom Variable Names
+// ---------------------------------------------------------------------------
+
+func TestVariables_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		vars    *Variables
+		wantErr error
+	}{
+		{"nil is valid", nil, nil},
+		{"no custom variables", &Variables{}, nil},
+		{"identifiers", &Variables{Vars: map[string]string{"client": "x", "_v2": "y", "DocRef": "z"}}, nil},
+		{"dash", &Variables{Vars: map[string]string{"client-name": "x"}}, ErrInvalidVariable},
+		{"leading digit", &Variables{Vars: map[string]string{"2nd": "x"}}, ErrInvalidVariable},
+		{"empty name", &Variables{Vars: map[string]string{"": "x"}}, ErrInvalidVariable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.vars.Validate()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Variables.Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestBibliography_Validate - Citation Settings
+// ---------------------------------------------------------------------------
+
+func TestBibliography_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		bib     *Bibliography
+		wantErr error
+	}{
+		{"nil is valid", nil, nil},
+		{"path only", &Bibliography{Path: "refs.bib"}, nil},
+		{"numeric", &Bibliography{Path: "refs.json", Style: CitationStyleNumeric}, nil},
+		{"case insensitive", &Bibliography{Path: "refs.bib", Style: "Author-Date"}, nil},
+		{"missing path", &Bibliography{Style: CitationStyleNumeric}, ErrInvalidBibliography},
+		{"unknown style", &Bibliography{Path: "refs.bib", Style: "apa"}, ErrInvalidBibliography},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.bib.Validate()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Bibliography.Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestRawHTML_Validate - Raw HTML allowlist
+// ---------------------------------------------------------------------------
+
+func TestRawHTML_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		rawHTML *RawHTML
+		wantErr error
+	}{
+		{"nil is valid", nil, nil},
+		{"defaults", &RawHTML{}, nil},
+		{"allowlist", &RawHTML{AllowedTags: []string{"meter", "H7"}, AllowedAttributes: []string{"style", "data-x"}}, nil},
+		{"invalid tag name", &RawHTML{AllowedTags: []string{"<b>"}}, ErrInvalidRawHTML},
+		{"invalid attribute name", &RawHTML{AllowedAttributes: []string{"a b"}}, ErrInvalidRawHTML},
+		{"script tag", &RawHTML{AllowedTags: []string{"Script"}}, ErrInvalidRawHTML},
+		{"event handler", &RawHTML{AllowedAttributes: []string{"onClick"}}, ErrInvalidRawHTML},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.rawHTML.Validate()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("RawHTML.Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestFootnotes_Validate - Footnote placement and numbering
+// ---------------------------------------------------------------------------
+
+func TestFootnotes_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		footnotes *Footnotes
+		wantErr   error
+	}{
+		{"nil is valid", nil, nil},
+		{"defaults", &Footnotes{}, nil},
+		{"page placement", &Footnotes{Placement: FootnotePlacementPage}, nil},
+		{"per-page numbering", &Footnotes{Placement: "Page", Numbering: FootnoteNumberingPage}, nil},
+		{"document numbering at the end", &Footnotes{Placement: FootnotePlacementEnd, Numbering: FootnoteNumberingDocument}, nil},
+		{"per-page numbering at the end", &Footnotes{Numbering: FootnoteNumberingPage}, ErrInvalidFootnotes},
+		{"unknown placement", &Footnotes{Placement: "margin"}, ErrInvalidFootnotes},
+		{"unknown numbering", &Footnotes{Placement: FootnotePlacementPage, Numbering: "chapter"}, ErrInvalidFootnotes},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.footnotes.Validate()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Footnotes.Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 // ---------------------------------------------------------------------------
 // TestWithTimeout_panic - WithTimeout Panic Behavior
 // ---------------------------------------------------------------------------
@@ -897,6 +1251,104 @@ func TestTOC_Validate(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// TestOutline_Validate - Outline depth validation
+// ---------------------------------------------------------------------------
+
+func TestOutline_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		outline *Outline
+		wantErr error
+	}{
+		{"nil is valid", nil, nil},
+		{"zero depths use defaults", &Outline{}, nil},
+		{"full range", &Outline{MinDepth: 1, MaxDepth: 6}, nil},
+		{"negative minDepth invalid", &Outline{MinDepth: -1}, ErrInvalidOutlineDepth},
+		{"maxDepth 7 invalid", &Outline{MaxDepth: 7}, ErrInvalidOutlineDepth},
+		{"minDepth greater than maxDepth", &Outline{MinDepth: 4, MaxDepth: 2}, ErrInvalidOutlineDepth},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.outline.Validate()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Outline.Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestEncryption_Validate - Password validation
+// ---------------------------------------------------------------------------
+
+func TestEncryption_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		encryption *Encryption
+		wantErr    error
+	}{
+		{"nil is valid", nil, nil},
+		{"user password only", &Encryption{UserPassword: "secret"}, nil},
+		{"owner password only", &Encryption{OwnerPassword: "admin", AllowPrint: true}, nil},
+		{"max length password", &Encryption{UserPassword: strings.Repeat("a", MaxPasswordLength)}, nil},
+		{"no password", &Encryption{AllowCopy: true}, ErrInvalidEncryption},
+		{"user password too long", &Encryption{UserPassword: strings.Repeat("a", MaxPasswordLength+1)}, ErrInvalidEncryption},
+		{"owner password too long", &Encryption{UserPassword: "u", OwnerPassword: strings.Repeat("é", 64)}, ErrInvalidEncryption},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.encryption.Validate()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Encryption.Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// TestDigitalSignature_Validate - Certificate and timestamp URL validation
+// ---------------------------------------------------------------------------
+
+func TestDigitalSignature_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		signature *DigitalSignature
+		wantErr   error
+	}{
+		{"nil is valid", nil, nil},
+		{"certificate only", &DigitalSignature{CertFile: "signer.p12"}, nil},
+		{"https timestamp server", &DigitalSignature{CertFile: "signer.pem", TimestampURL: "https://tsa.example/tsr"}, nil},
+		{"local timestamp server", &DigitalSignature{CertFile: "signer.pem", TimestampURL: "http://localhost:3180"}, nil},
+		{"no certificate", &DigitalSignature{KeyFile: "signer.key"}, ErrInvalidDigitalSignature},
+		{"timestamp URL without scheme", &DigitalSignature{CertFile: "signer.pem", TimestampURL: "tsa.example"}, ErrInvalidDigitalSignature},
+		{"timestamp URL without host", &DigitalSignature{CertFile: "signer.pem", TimestampURL: "http://"}, ErrInvalidDigitalSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.signature.Validate()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("DigitalSignature.Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 // ---------------------------------------------------------------------------
 // TestSignature_Validate - Signature ImagePath Validation
 // ---------------------------------------------------------------------------